@@ -640,6 +640,47 @@ func (s *HTTPHandlers) ACLRoleCreate(resp http.ResponseWriter, req *http.Request
 	return s.ACLRoleWrite(resp, req, "")
 }
 
+func (s *HTTPHandlers) ACLRoleCreateFromTemplate(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if s.checkACLDisabled() {
+		return nil, aclDisabled
+	}
+
+	templateName := strings.TrimPrefix(req.URL.Path, "/v1/acl/role/template/")
+	if templateName == "" {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "Missing role template name"}
+	}
+
+	args := structs.ACLRoleSetRequest{
+		Datacenter: s.agent.config.Datacenter,
+		Template:   templateName,
+	}
+	s.parseToken(req, &args.Token)
+	if err := s.parseEntMeta(req, &args.Role.EnterpriseMeta); err != nil {
+		return nil, err
+	}
+
+	body := struct {
+		Role      *structs.ACLRole
+		Variables map[string]string
+	}{
+		Role: &args.Role,
+	}
+	if err := s.rewordUnknownEnterpriseFieldError(lib.DecodeJSON(req.Body, &body)); err != nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Role decoding failed: %v", err)}
+	}
+	if args.Role.ID != "" {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "Cannot specify an ID when creating a role from a template"}
+	}
+	args.TemplateVariables = body.Variables
+
+	var out structs.ACLRole
+	if err := s.agent.RPC(req.Context(), "ACL.RoleSet", args, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
 func (s *HTTPHandlers) ACLRoleWrite(resp http.ResponseWriter, req *http.Request, roleID string) (interface{}, error) {
 	args := structs.ACLRoleSetRequest{
 		Datacenter: s.agent.config.Datacenter,
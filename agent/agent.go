@@ -587,15 +587,16 @@ func (a *Agent) GetConfig() *config.RuntimeConfig {
 // LocalConfig takes a config.RuntimeConfig and maps the fields to a local.Config
 func LocalConfig(cfg *config.RuntimeConfig) local.Config {
 	lc := local.Config{
-		AdvertiseAddr:       cfg.AdvertiseAddrLAN.String(),
-		CheckUpdateInterval: cfg.CheckUpdateInterval,
-		Datacenter:          cfg.Datacenter,
-		DiscardCheckOutput:  cfg.DiscardCheckOutput,
-		NodeID:              cfg.NodeID,
-		NodeName:            cfg.NodeName,
-		NodeLocality:        cfg.StructLocality(),
-		Partition:           cfg.PartitionOrDefault(),
-		TaggedAddresses:     map[string]string{},
+		AdvertiseAddr:           cfg.AdvertiseAddrLAN.String(),
+		CheckUpdateInterval:     cfg.CheckUpdateInterval,
+		Datacenter:              cfg.Datacenter,
+		DiscardCheckOutput:      cfg.DiscardCheckOutput,
+		CheckOutputTruncateSize: cfg.CheckOutputTruncateSize,
+		NodeID:                  cfg.NodeID,
+		NodeName:                cfg.NodeName,
+		NodeLocality:            cfg.StructLocality(),
+		Partition:               cfg.PartitionOrDefault(),
+		TaggedAddresses:         map[string]string{},
 	}
 	for k, v := range cfg.TaggedAddresses {
 		lc.TaggedAddresses[k] = v
@@ -962,6 +963,13 @@ func (a *Agent) configureXDSServer(proxyWatcher xds.ProxyWatcher, server *consul
 		},
 		a,
 	)
+	a.xdsServer.CaseInsensitiveResourceNames = a.config.XDSCaseInsensitiveResourceNames
+	a.xdsServer.ResourceDiffLogServiceIDs = a.config.XDSResourceDiffLogServiceIDs
+	a.xdsServer.SecretsRequireMeshRead = a.config.XDSSecretsRequireMeshRead
+	a.xdsServer.MinUpdateInterval = a.config.XDSMinUpdateInterval
+	a.xdsServer.InitialConfigTimeout = a.config.XDSInitialConfigTimeout
+	a.xdsServer.TerminateOnInitialConfigTimeout = a.config.XDSTerminateOnInitialConfigTimeout
+	a.xdsServer.MaxStreamsPerPartition = a.config.XDSMaxStreamsPerPartition
 	a.xdsServer.Register(a.externalGRPCServer)
 }
 
@@ -1475,6 +1483,7 @@ func newConsulConfig(runtimeCfg *config.RuntimeConfig, logger hclog.Logger) (*co
 		cfg.ACLInitialManagementToken = runtimeCfg.ACLInitialManagementToken
 	}
 	cfg.ACLTokenReplication = runtimeCfg.ACLTokenReplication
+	cfg.ACLRoleTemplates = runtimeCfg.ACLRoleTemplates
 	cfg.ACLsEnabled = runtimeCfg.ACLsEnabled
 	if runtimeCfg.ACLEnableKeyListPolicy {
 		cfg.ACLEnableKeyListPolicy = runtimeCfg.ACLEnableKeyListPolicy
@@ -1482,6 +1491,9 @@ func newConsulConfig(runtimeCfg *config.RuntimeConfig, logger hclog.Logger) (*co
 	if runtimeCfg.SessionTTLMin != 0 {
 		cfg.SessionTTLMin = runtimeCfg.SessionTTLMin
 	}
+	cfg.SessionCheckExemptTypes = runtimeCfg.SessionCheckExemptTypes
+	cfg.SessionMaxTTL = runtimeCfg.SessionMaxTTL
+	cfg.VirtualIPCIDR = runtimeCfg.VirtualIPCIDR
 	if runtimeCfg.ReadReplica {
 		cfg.ReadReplica = runtimeCfg.ReadReplica
 	}
@@ -1553,6 +1565,7 @@ func newConsulConfig(runtimeCfg *config.RuntimeConfig, logger hclog.Logger) (*co
 	if runtimeCfg.ConnectEnabled {
 		cfg.ConnectEnabled = true
 		cfg.ConnectMeshGatewayWANFederationEnabled = runtimeCfg.ConnectMeshGatewayWANFederationEnabled
+		cfg.ConnectIntentionAuditLog = runtimeCfg.ConnectIntentionAuditLog
 
 		ca, err := runtimeCfg.ConnectCAConfiguration()
 		if err != nil {
@@ -1582,6 +1595,10 @@ func newConsulConfig(runtimeCfg *config.RuntimeConfig, logger hclog.Logger) (*co
 	}
 
 	cfg.ConfigEntryBootstrap = runtimeCfg.ConfigEntryBootstrap
+	cfg.ConfigEntryMaxPerPartition = runtimeCfg.ConfigEntryMaxPerPartition
+	cfg.ConfigEntryMaxPerKindPerPartition = runtimeCfg.ConfigEntryMaxPerKindPerPartition
+	cfg.ConfigEntryMaxIntentionPermissions = runtimeCfg.ConfigEntryMaxIntentionPermissions
+	cfg.ConfigEntryStrictFailoverDatacenters = runtimeCfg.ConfigEntryStrictFailoverDatacenters
 	cfg.LogStoreConfig = runtimeCfg.RaftLogStoreConfig
 
 	// Duplicate our own serf config once to make sure that the duplication
@@ -4331,6 +4348,7 @@ func (a *Agent) reloadConfigInternal(newCfg *config.RuntimeConfig) error {
 		newCfg.Telemetry.BlockedPrefixes)
 
 	a.State.SetDiscardCheckOutput(newCfg.DiscardCheckOutput)
+	a.State.SetCheckOutputTruncateSize(newCfg.CheckOutputTruncateSize)
 
 	for _, r := range a.configReloaders {
 		if err := r(newCfg); err != nil {
@@ -4436,7 +4454,10 @@ func (a *Agent) registerCache() {
 
 	a.cache.RegisterType(cachetype.ConnectCARootName, &cachetype.ConnectCARoot{RPC: a})
 
-	a.cache.RegisterType(cachetype.IntentionMatchName, &cachetype.IntentionMatch{RPC: a})
+	a.cache.RegisterType(cachetype.IntentionMatchName, &cachetype.IntentionMatch{
+		RPC: a,
+		TTL: a.config.IntentionMatchCacheTTL,
+	})
 
 	a.cache.RegisterType(cachetype.IntentionUpstreamsName, &cachetype.IntentionUpstreams{RPC: a})
 	a.cache.RegisterType(cachetype.IntentionUpstreamsDestinationName, &cachetype.IntentionUpstreamsDestination{RPC: a})
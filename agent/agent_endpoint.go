@@ -29,6 +29,7 @@ import (
 	"github.com/dhiaayachi/consul/agent/leafcert"
 	"github.com/dhiaayachi/consul/agent/structs"
 	token_store "github.com/dhiaayachi/consul/agent/token"
+	"github.com/dhiaayachi/consul/agent/xds"
 	"github.com/dhiaayachi/consul/api"
 	"github.com/dhiaayachi/consul/envoyextensions/xdscommon"
 	"github.com/dhiaayachi/consul/internal/gossip/librtt"
@@ -979,6 +980,79 @@ func (s *HTTPHandlers) agentCheckUpdate(resp http.ResponseWriter, req *http.Requ
 	return nil, nil
 }
 
+// checkOutput is the response of the check output endpoint.
+type checkOutput struct {
+	CheckID types.CheckID
+
+	// Output is the full output of the last run of the check.
+	Output string
+
+	// Truncated is true if the output stored in the catalog is a truncated
+	// copy of Output.
+	Truncated bool
+}
+
+// AgentCheckOutput returns the full output of the last run of a local check,
+// which the agent keeps even when the output it syncs to the servers is
+// truncated because of check_output_truncate_size.
+func (s *HTTPHandlers) AgentCheckOutput(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	id := strings.TrimPrefix(req.URL.Path, "/v1/agent/check/output/")
+	if id == "" {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "Missing check ID"}
+	}
+
+	entMeta := acl.NewEnterpriseMetaWithPartition(s.agent.config.PartitionOrDefault(), "")
+	cid := structs.NewCheckID(types.CheckID(id), &entMeta)
+
+	var token string
+	s.parseToken(req, &token)
+
+	if err := s.parseEntMetaNoWildcard(req, &cid.EnterpriseMeta); err != nil {
+		return nil, err
+	}
+
+	authz, err := s.agent.delegate.ResolveTokenAndDefaultMeta(token, &cid.EnterpriseMeta, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	cid.Normalize()
+
+	if !s.validateRequestPartition(resp, &cid.EnterpriseMeta) {
+		return nil, nil
+	}
+
+	c := s.agent.State.CheckState(cid)
+	if c == nil {
+		return nil, HTTPError{
+			StatusCode: http.StatusNotFound,
+			Reason:     fmt.Sprintf("Unknown check ID %q. Ensure that the check ID is passed, not the check name.", cid.String()),
+		}
+	}
+
+	var authzContext acl.AuthorizerContext
+	c.Check.FillAuthzContext(&authzContext)
+	if len(c.Check.ServiceName) > 0 {
+		if err := authz.ToAllowAuthorizer().ServiceReadAllowed(c.Check.ServiceName, &authzContext); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := authz.ToAllowAuthorizer().NodeReadAllowed(s.agent.config.NodeName, &authzContext); err != nil {
+			return nil, err
+		}
+	}
+
+	out := checkOutput{
+		CheckID: c.Check.CheckID,
+		Output:  c.Check.Output,
+	}
+	if c.FullOutput != "" {
+		out.Output = c.FullOutput
+		out.Truncated = true
+	}
+	return out, nil
+}
+
 // agentHealthService Returns Health for a given service ID
 func agentHealthService(serviceID structs.ServiceID, s *HTTPHandlers) (int, string, api.HealthChecks) {
 	checks := s.agent.State.ChecksForService(serviceID, true)
@@ -1785,6 +1859,20 @@ func (s *HTTPHandlers) AgentConnectAuthorize(resp http.ResponseWriter, req *http
 		authorized = authz.IntentionDefaultAllow(nil) == acl.Allow
 	}
 
+	if s.agent.config.ConnectIntentionAuditLog {
+		action := structs.IntentionActionDeny
+		if authorized {
+			action = structs.IntentionActionAllow
+		}
+		srcMeta := acl.NewEnterpriseMetaWithPartition(uriService.Partition, uriService.Namespace)
+		s.agent.logger.Named(logging.IntentionAudit).Info("intention decision",
+			"source", structs.NewServiceName(uriService.Service, &srcMeta).String(),
+			"destination", structs.NewServiceName(authReq.Target, &authReq.EnterpriseMeta).String(),
+			"action", action,
+			"rule", reason,
+		)
+	}
+
 	setCacheMeta(resp, &meta)
 
 	return &connectAuthorizeResp{
@@ -1800,6 +1888,25 @@ type connectAuthorizeResp struct {
 	Reason     string // Reason for the Authorized value (whether true or false)
 }
 
+// AgentConnectAuthorizeFlush drops the intention match results cached for the
+// /v1/agent/connect/authorize endpoint so that the next authorization is
+// evaluated against the servers. It requires a token with acl:write.
+func (s *HTTPHandlers) AgentConnectAuthorizeFlush(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var token string
+	s.parseToken(req, &token)
+	authz, err := s.agent.delegate.ResolveTokenAndDefaultMeta(token, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := authz.ToAllowAuthorizer().ACLWriteAllowed(nil); err != nil {
+		return nil, err
+	}
+
+	n := s.agent.cache.Evict(cachetype.IntentionMatchName)
+	s.agent.logger.Info("flushed cached intention matches", "entries", n)
+	return nil, nil
+}
+
 // AgentHost
 //
 // GET /v1/agent/host
@@ -1824,6 +1931,30 @@ func (s *HTTPHandlers) AgentHost(resp http.ResponseWriter, req *http.Request) (i
 	return debug.CollectHostInfo(), nil
 }
 
+// AgentXDSStreams
+//
+// GET /v1/agent/xds/streams
+//
+// Retrieves the status of the delta xDS streams served by this agent.
+func (s *HTTPHandlers) AgentXDSStreams(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	// Fetch the ACL token, if any, and enforce agent policy.
+	var token string
+	s.parseToken(req, &token)
+	authz, err := s.agent.delegate.ResolveTokenAndDefaultMeta(token, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authz.ToAllowAuthorizer().OperatorReadAllowed(nil); err != nil {
+		return nil, err
+	}
+
+	if s.agent.xdsServer == nil {
+		return []xds.StreamStatus{}, nil
+	}
+	return s.agent.xdsServer.StreamStatuses(), nil
+}
+
 // AgentVersion
 //
 // GET /v1/agent/version
@@ -41,6 +41,7 @@ import (
 	"github.com/dhiaayachi/consul/agent/structs"
 	"github.com/dhiaayachi/consul/agent/token"
 	tokenStore "github.com/dhiaayachi/consul/agent/token"
+	"github.com/dhiaayachi/consul/agent/xds"
 	"github.com/dhiaayachi/consul/api"
 	"github.com/dhiaayachi/consul/envoyextensions/xdscommon"
 	"github.com/dhiaayachi/consul/lib"
@@ -3499,6 +3500,66 @@ func TestAgent_UpdateCheck(t *testing.T) {
 	})
 }
 
+func TestAgent_CheckOutput(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "check_output_truncate_size = 64")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	chk := &structs.HealthCheck{Name: "test", CheckID: "test"}
+	chkType := &structs.CheckType{TTL: 15 * time.Second}
+	require.NoError(t, a.AddCheck(chk, chkType, false, "", ConfigSourceLocal))
+
+	getOutput := func(t *testing.T, id string) (int, checkOutput) {
+		req, _ := http.NewRequest("GET", "/v1/agent/check/output/"+id, nil)
+		resp := httptest.NewRecorder()
+		a.srv.h.ServeHTTP(resp, req)
+
+		var out checkOutput
+		if resp.Code == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+		}
+		return resp.Code, out
+	}
+
+	t.Run("oversized output", func(t *testing.T) {
+		output := strings.Repeat("0123456789", 50)
+		require.NoError(t, a.updateTTLCheck(structs.NewCheckID("test", nil), api.HealthPassing, output))
+
+		// The stored copy is truncated.
+		state := a.State.Check(structs.NewCheckID("test", nil))
+		require.Equal(t, output[:64]+" ... (stored 64 of 500 bytes)", state.Output)
+
+		// The full output is still available from the agent.
+		code, out := getOutput(t, "test")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, types.CheckID("test"), out.CheckID)
+		require.Equal(t, output, out.Output)
+		require.True(t, out.Truncated)
+	})
+
+	t.Run("short output", func(t *testing.T) {
+		require.NoError(t, a.updateTTLCheck(structs.NewCheckID("test", nil), api.HealthPassing, "ok"))
+
+		state := a.State.Check(structs.NewCheckID("test", nil))
+		require.Equal(t, "ok", state.Output)
+
+		code, out := getOutput(t, "test")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "ok", out.Output)
+		require.False(t, out.Truncated)
+	})
+
+	t.Run("unknown check", func(t *testing.T) {
+		code, _ := getOutput(t, "nope")
+		require.Equal(t, http.StatusNotFound, code)
+	})
+}
+
 func TestAgent_UpdateCheck_ACLDeny(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -7969,6 +8030,66 @@ func TestAgentConnectAuthorize_deny(t *testing.T) {
 	assert.Contains(t, obj.Reason, "Matched")
 }
 
+func TestAgentConnectAuthorize_intentionAuditLog(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	buf := &syncBuffer{b: new(bytes.Buffer)}
+	a := StartTestAgent(t, TestAgent{
+		HCL:       `connect { intention_audit_log = true }`,
+		LogOutput: buf,
+		LogLevel:  hclog.Info,
+	})
+	defer a.Shutdown()
+
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+	target := "db"
+
+	// Create an intention denying web
+	{
+		req := structs.IntentionRequest{
+			Datacenter: "dc1",
+			Op:         structs.IntentionOpCreate,
+			Intention:  structs.TestIntention(t),
+		}
+		req.Intention.SourceNS = structs.IntentionDefaultNamespace
+		req.Intention.SourceName = "web"
+		req.Intention.DestinationNS = structs.IntentionDefaultNamespace
+		req.Intention.DestinationName = target
+		req.Intention.Action = structs.IntentionActionDeny
+
+		var reply string
+		require.NoError(t, a.RPC(context.Background(), "Intention.Apply", &req, &reply))
+	}
+
+	authorize := func(source string) *connectAuthorizeResp {
+		args := &structs.ConnectAuthorizeRequest{
+			Target:        target,
+			ClientCertURI: connect.TestSpiffeIDService(t, source).URI().String(),
+		}
+		req, _ := http.NewRequest("POST", "/v1/agent/connect/authorize", jsonReader(args))
+		resp := httptest.NewRecorder()
+		a.srv.h.ServeHTTP(resp, req)
+		require.Equal(t, 200, resp.Code)
+
+		obj := &connectAuthorizeResp{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(obj))
+		return obj
+	}
+
+	denied := authorize("web")
+	require.False(t, denied.Authorized)
+	allowed := authorize("api")
+	require.True(t, allowed.Authorized)
+
+	logs := buf.String()
+	require.Contains(t, logs, `intention decision: source=web destination=db action=deny rule="Matched L4 intention: default/web => default/db`)
+	require.Contains(t, logs, `intention decision: source=api destination=db action=allow rule="Default behavior configured by ACLs"`)
+}
+
 // Test when there is an intention allowing service with a different trust
 // domain. We allow this because migration between trust domains shouldn't cause
 // an outage even if we have stale info about current trusted domains. It's safe
@@ -8134,6 +8255,82 @@ func TestAgentConnectAuthorize_serviceWrite(t *testing.T) {
 	assert.Equal(t, http.StatusForbidden, resp.Code)
 }
 
+func TestAgentConnectAuthorizeFlush(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := NewTestAgent(t, TestACLConfig()+`
+		cache {
+			intention_match_ttl = "1h"
+		}
+	`)
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+	require.Equal(t, time.Hour, a.config.IntentionMatchCacheTTL)
+
+	{
+		req := structs.IntentionRequest{
+			Datacenter:   "dc1",
+			Op:           structs.IntentionOpCreate,
+			Intention:    structs.TestIntention(t),
+			WriteRequest: structs.WriteRequest{Token: "root"},
+		}
+		req.Intention.SourceName = "web"
+		req.Intention.DestinationName = "test"
+		req.Intention.Action = structs.IntentionActionAllow
+
+		var reply string
+		require.NoError(t, a.RPC(context.Background(), "Intention.Apply", &req, &reply))
+	}
+
+	authorize := func(t *testing.T) string {
+		t.Helper()
+		args := &structs.ConnectAuthorizeRequest{
+			Target:        "test",
+			ClientCertURI: connect.TestSpiffeIDService(t, "web").URI().String(),
+		}
+		req, _ := http.NewRequest("POST", "/v1/agent/connect/authorize", jsonReader(args))
+		req.Header.Add("X-Consul-Token", "root")
+		resp := httptest.NewRecorder()
+		a.srv.h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		obj := &connectAuthorizeResp{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(obj))
+		require.True(t, obj.Authorized)
+		return resp.Header().Get("X-Cache")
+	}
+	flush := func(t *testing.T, token string) int {
+		t.Helper()
+		req, _ := http.NewRequest("PUT", "/v1/agent/connect/authorize/flush", nil)
+		req.Header.Add("X-Consul-Token", token)
+		resp := httptest.NewRecorder()
+		a.srv.h.ServeHTTP(resp, req)
+		return resp.Code
+	}
+
+	// Populate the cache.
+	require.Equal(t, "MISS", authorize(t))
+	require.Equal(t, "HIT", authorize(t))
+
+	t.Run("requires acl:write", func(t *testing.T) {
+		token := createACLTokenWithServicePolicy(t, a.srv, "write")
+		require.Equal(t, http.StatusForbidden, flush(t, token))
+		require.Equal(t, "HIT", authorize(t))
+	})
+
+	t.Run("flush", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, flush(t, "root"))
+
+		// The next authorization is evaluated again and cached.
+		require.Equal(t, "MISS", authorize(t))
+		require.Equal(t, "HIT", authorize(t))
+	})
+}
+
 func TestAgentConnectAuthorize_DefaultIntentionPolicy(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -8307,6 +8504,33 @@ func TestAgent_HostBadACL(t *testing.T) {
 	assert.Equal(t, http.StatusOK, resp.Code)
 }
 
+func TestAgent_XDSStreams(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := NewTestAgent(t, TestACLConfig())
+	defer a.Shutdown()
+
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	t.Run("no token", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/agent/xds/streams", nil)
+		_, err := a.srv.AgentXDSStreams(httptest.NewRecorder(), req)
+		require.True(t, acl.IsErrPermissionDenied(err))
+	})
+
+	t.Run("operator read", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/v1/agent/xds/streams", nil)
+		req.Header.Add("X-Consul-Token", "root")
+		obj, err := a.srv.AgentXDSStreams(httptest.NewRecorder(), req)
+		require.NoError(t, err)
+		require.Empty(t, obj.([]xds.StreamStatus))
+	})
+}
+
 func TestAgent_Version(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -6,6 +6,7 @@ package cachetype
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/dhiaayachi/consul/agent/cache"
 	"github.com/dhiaayachi/consul/agent/structs"
@@ -18,6 +19,16 @@ const IntentionMatchName = "intention-match"
 type IntentionMatch struct {
 	RegisterOptionsBlockingRefresh
 	RPC RPC
+
+	// TTL is how long a match result stays cached after it was last read.
+	// Zero uses the cache default.
+	TTL time.Duration
+}
+
+func (c *IntentionMatch) RegisterOptions() cache.RegisterOptions {
+	opts := c.RegisterOptionsBlockingRefresh.RegisterOptions()
+	opts.LastGetTTL = c.TTL
+	return opts
 }
 
 func (c *IntentionMatch) Fetch(opts cache.FetchOptions, req cache.Request) (cache.FetchResult, error) {
@@ -57,3 +57,12 @@ func TestIntentionMatch_badReqType(t *testing.T) {
 	require.Contains(t, err.Error(), "wrong type")
 
 }
+
+func TestIntentionMatch_RegisterOptions(t *testing.T) {
+	typ := &IntentionMatch{}
+	require.Zero(t, typ.RegisterOptions().LastGetTTL)
+	require.True(t, typ.RegisterOptions().Refresh)
+
+	typ = &IntentionMatch{TTL: 5 * time.Minute}
+	require.Equal(t, 5*time.Minute, typ.RegisterOptions().LastGetTTL)
+}
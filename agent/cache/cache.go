@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -920,6 +921,13 @@ func (c *Cache) runExpiryLoop() {
 			c.entriesLock.Lock()
 
 			entry := timer.Entry
+
+			// The entry may have been evicted while we waited for the lock.
+			if entry.Index() == ttlcache.NotIndexed {
+				c.entriesLock.Unlock()
+				continue
+			}
+
 			if closer, ok := c.entries[entry.Key()].State.(io.Closer); ok {
 				closer.Close()
 			}
@@ -979,3 +987,33 @@ func (c *Cache) Prepopulate(t string, res FetchResult, dc, peerName, token, k st
 	c.entriesLock.Unlock()
 	return nil
 }
+
+// Evict removes every entry of the given type from the cache and returns the
+// number of entries removed. The next Get for a removed entry fetches a fresh
+// value, and background refreshes of the removed entries stop once their
+// in-flight fetch returns.
+func (c *Cache) Evict(t string) int {
+	prefix := t + "/"
+
+	c.entriesLock.Lock()
+	defer c.entriesLock.Unlock()
+
+	var n int
+	for key, entry := range c.entries {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if closer, ok := entry.State.(io.Closer); ok {
+			closer.Close()
+		}
+		if entry.Expiry != nil && entry.Expiry.Index() != ttlcache.NotIndexed {
+			c.entriesExpiryHeap.Remove(entry.Expiry.Index())
+		}
+		delete(c.entries, key)
+		n++
+	}
+
+	metrics.SetGauge([]string{"consul", "cache", "entries_count"}, float32(len(c.entries)))
+	metrics.SetGauge([]string{"cache", "entries_count"}, float32(len(c.entries)))
+	return n
+}
@@ -1707,6 +1707,43 @@ func TestCache_Prepopulate(t *testing.T) {
 	require.Equal(t, 17, result)
 }
 
+func TestCache_Evict(t *testing.T) {
+	t.Parallel()
+
+	typ := &MockType{}
+	typ.On("RegisterOptions").Return(RegisterOptions{})
+	defer typ.AssertExpectations(t)
+	other := &MockType{}
+	other.On("RegisterOptions").Return(RegisterOptions{})
+	defer other.AssertExpectations(t)
+
+	c := New(Options{})
+	c.RegisterType("t", typ)
+	c.RegisterType("other", other)
+
+	// The evicted type is fetched again, the other one is not.
+	typ.Static(FetchResult{Value: 42}, nil).Times(2)
+	other.Static(FetchResult{Value: 17}, nil).Once()
+
+	ctx := context.Background()
+	get := func(typ string, value int, hit bool) {
+		t.Helper()
+		result, meta, err := c.Get(ctx, typ, TestRequest(t, RequestInfo{Key: "hello"}))
+		require.NoError(t, err)
+		require.Equal(t, value, result)
+		require.Equal(t, hit, meta.Hit)
+	}
+	get("t", 42, false)
+	get("other", 17, false)
+	get("t", 42, true)
+
+	require.Equal(t, 1, c.Evict("t"))
+	require.Equal(t, 0, c.Evict("unknown"))
+
+	get("t", 42, false)
+	get("other", 17, true)
+}
+
 func TestCache_RefreshLifeCycle(t *testing.T) {
 	typ := &MockType{}
 	t.Cleanup(func() { typ.AssertExpectations(t) })
@@ -786,6 +786,23 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		}
 	}
 
+	configEntryMaxPerPartition := intVal(c.ConfigEntries.MaxPerPartition)
+	if configEntryMaxPerPartition < 0 {
+		return RuntimeConfig{}, fmt.Errorf("config_entries.max_per_partition cannot be negative")
+	}
+	for kind, limit := range c.ConfigEntries.MaxPerKindPerPartition {
+		if _, err := structs.MakeConfigEntry(kind, ""); err != nil {
+			return RuntimeConfig{}, fmt.Errorf("config_entries.max_per_kind_per_partition[%q]: %s", kind, err)
+		}
+		if limit < 0 {
+			return RuntimeConfig{}, fmt.Errorf("config_entries.max_per_kind_per_partition[%q] cannot be negative", kind)
+		}
+	}
+	configEntryMaxIntentionPermissions := intVal(c.ConfigEntries.MaxIntentionPermissions)
+	if configEntryMaxIntentionPermissions < 0 {
+		return RuntimeConfig{}, fmt.Errorf("config_entries.max_intention_permissions cannot be negative")
+	}
+
 	serfAllowedCIDRSLAN, err := memberlist.ParseCIDRs(c.SerfAllowedCIDRsLAN)
 	if err != nil {
 		return RuntimeConfig{}, fmt.Errorf("serf_lan_allowed_cidrs: %s", err)
@@ -878,6 +895,7 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		ACLInitialManagementToken: stringVal(c.ACL.Tokens.InitialManagement),
 
 		ACLTokenReplication: boolVal(c.ACL.TokenReplication),
+		ACLRoleTemplates:    b.aclRoleTemplatesVal(c.ACL.RoleTemplates),
 
 		ACLTokens: token.Config{
 			DataDir:                        dataDir,
@@ -980,12 +998,18 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 				c.Cache.EntryFetchMaxBurst, cache.DefaultEntryFetchMaxBurst,
 			),
 		},
+		IntentionMatchCacheTTL:                 b.durationValWithDefaultMin("cache.intention_match_ttl", c.Cache.IntentionMatchTTL, 0, 0),
 		AutoReloadConfig:                       boolVal(c.AutoReloadConfig),
 		CheckUpdateInterval:                    b.durationVal("check_update_interval", c.CheckUpdateInterval),
 		CheckOutputMaxSize:                     intValWithDefault(c.CheckOutputMaxSize, 4096),
+		CheckOutputTruncateSize:                intVal(c.CheckOutputTruncateSize),
 		Checks:                                 checks,
 		ClientAddrs:                            clientAddrs,
 		ConfigEntryBootstrap:                   configEntries,
+		ConfigEntryMaxPerPartition:             configEntryMaxPerPartition,
+		ConfigEntryMaxPerKindPerPartition:      c.ConfigEntries.MaxPerKindPerPartition,
+		ConfigEntryMaxIntentionPermissions:     configEntryMaxIntentionPermissions,
+		ConfigEntryStrictFailoverDatacenters:   boolVal(c.ConfigEntries.StrictFailoverDatacenters),
 		AutoEncryptTLS:                         boolVal(c.AutoEncrypt.TLS),
 		AutoEncryptDNSSAN:                      autoEncryptDNSSAN,
 		AutoEncryptIPSAN:                       autoEncryptIPSAN,
@@ -996,6 +1020,7 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 		ConnectCAProvider:                      connectCAProvider,
 		ConnectCAConfig:                        connectCAConfig,
 		ConnectMeshGatewayWANFederationEnabled: connectMeshGatewayWANFederationEnabled,
+		ConnectIntentionAuditLog:               boolVal(c.Connect.IntentionAuditLog),
 		ConnectSidecarMinPort:                  sidecarMinPort,
 		ConnectSidecarMaxPort:                  sidecarMaxPort,
 		ConnectTestCALeafRootChangeSpread:      b.durationVal("connect.test_ca_leaf_root_change_spread", c.Connect.TestCALeafRootChangeSpread),
@@ -1049,74 +1074,84 @@ func (b *builder) build() (rt RuntimeConfig, err error) {
 			LogRotateBytes:    intVal(c.LogRotateBytes),
 			LogRotateMaxFiles: intVal(c.LogRotateMaxFiles),
 		},
-		MaxQueryTime:                      b.durationVal("max_query_time", c.MaxQueryTime),
-		NodeID:                            types.NodeID(stringVal(c.NodeID)),
-		NodeMeta:                          c.NodeMeta,
-		NodeName:                          b.nodeName(c.NodeName),
-		ReadReplica:                       boolVal(c.ReadReplica),
-		PeeringEnabled:                    boolVal(c.Peering.Enabled),
-		PeeringTestAllowPeerRegistrations: boolValWithDefault(c.Peering.TestAllowPeerRegistrations, false),
-		PidFile:                           stringVal(c.PidFile),
-		PrimaryDatacenter:                 primaryDatacenter,
-		PrimaryGateways:                   b.expandAllOptionalAddrs("primary_gateways", c.PrimaryGateways),
-		PrimaryGatewaysInterval:           b.durationVal("primary_gateways_interval", c.PrimaryGatewaysInterval),
-		RPCAdvertiseAddr:                  rpcAdvertiseAddr,
-		RPCBindAddr:                       rpcBindAddr,
-		RPCHandshakeTimeout:               b.durationVal("limits.rpc_handshake_timeout", c.Limits.RPCHandshakeTimeout),
-		RPCHoldTimeout:                    b.durationVal("performance.rpc_hold_timeout", c.Performance.RPCHoldTimeout),
-		RPCClientTimeout:                  b.durationVal("limits.rpc_client_timeout", c.Limits.RPCClientTimeout),
-		RPCMaxBurst:                       intVal(c.Limits.RPCMaxBurst),
-		RPCMaxConnsPerClient:              intVal(c.Limits.RPCMaxConnsPerClient),
-		RPCProtocol:                       intVal(c.RPCProtocol),
-		RPCRateLimit:                      limitVal(c.Limits.RPCRate),
-		RPCConfig:                         consul.RPCConfig{EnableStreaming: boolValWithDefault(c.RPC.EnableStreaming, serverMode)},
-		RaftProtocol:                      intVal(c.RaftProtocol),
-		RaftSnapshotThreshold:             intVal(c.RaftSnapshotThreshold),
-		RaftSnapshotInterval:              b.durationVal("raft_snapshot_interval", c.RaftSnapshotInterval),
-		RaftTrailingLogs:                  intVal(c.RaftTrailingLogs),
-		RaftPreVoteDisabled:               boolVal(c.RaftPreVoteDisabled),
-		RaftLogStoreConfig:                b.raftLogStoreConfigVal(&c.RaftLogStore),
-		ReconnectTimeoutLAN:               b.durationVal("reconnect_timeout", c.ReconnectTimeoutLAN),
-		ReconnectTimeoutWAN:               b.durationVal("reconnect_timeout_wan", c.ReconnectTimeoutWAN),
-		RejoinAfterLeave:                  boolVal(c.RejoinAfterLeave),
-		RequestLimitsMode:                 b.requestsLimitsModeVal(stringVal(c.Limits.RequestLimits.Mode)),
-		RequestLimitsReadRate:             limitVal(c.Limits.RequestLimits.ReadRate),
-		RequestLimitsWriteRate:            limitVal(c.Limits.RequestLimits.WriteRate),
-		RetryJoinIntervalLAN:              b.durationVal("retry_interval", c.RetryJoinIntervalLAN),
-		RetryJoinIntervalWAN:              b.durationVal("retry_interval_wan", c.RetryJoinIntervalWAN),
-		RetryJoinLAN:                      b.expandAllOptionalAddrs("retry_join", c.RetryJoinLAN),
-		RetryJoinMaxAttemptsLAN:           intVal(c.RetryJoinMaxAttemptsLAN),
-		RetryJoinMaxAttemptsWAN:           intVal(c.RetryJoinMaxAttemptsWAN),
-		RetryJoinWAN:                      b.expandAllOptionalAddrs("retry_join_wan", c.RetryJoinWAN),
-		SegmentName:                       stringVal(c.SegmentName),
-		Segments:                          segments,
-		SegmentLimit:                      intVal(c.SegmentLimit),
-		SerfAdvertiseAddrLAN:              serfAdvertiseAddrLAN,
-		SerfAdvertiseAddrWAN:              serfAdvertiseAddrWAN,
-		SerfAllowedCIDRsLAN:               serfAllowedCIDRSLAN,
-		SerfAllowedCIDRsWAN:               serfAllowedCIDRSWAN,
-		SerfBindAddrLAN:                   serfBindAddrLAN,
-		SerfBindAddrWAN:                   serfBindAddrWAN,
-		SerfPortLAN:                       serfPortLAN,
-		SerfPortWAN:                       serfPortWAN,
-		ServerMode:                        serverMode,
-		ServerName:                        stringVal(c.ServerName),
-		ServerPort:                        serverPort,
-		ServerRejoinAgeMax:                b.durationValWithDefaultMin("server_rejoin_age_max", c.ServerRejoinAgeMax, 24*7*time.Hour, 6*time.Hour),
-		Services:                          services,
-		SessionTTLMin:                     b.durationVal("session_ttl_min", c.SessionTTLMin),
-		SkipLeaveOnInt:                    skipLeaveOnInt,
-		TaggedAddresses:                   c.TaggedAddresses,
-		TranslateWANAddrs:                 boolVal(c.TranslateWANAddrs),
-		TxnMaxReqLen:                      uint64Val(c.Limits.TxnMaxReqLen),
-		UIConfig:                          b.uiConfigVal(c.UIConfig),
-		UnixSocketGroup:                   stringVal(c.UnixSocket.Group),
-		UnixSocketMode:                    stringVal(c.UnixSocket.Mode),
-		UnixSocketUser:                    stringVal(c.UnixSocket.User),
-		Watches:                           c.Watches,
-		XDSUpdateRateLimit:                limitVal(c.XDS.UpdateMaxPerSecond),
-		AutoReloadConfigCoalesceInterval:  1 * time.Second,
-		LocalProxyConfigResyncInterval:    30 * time.Second,
+		MaxQueryTime:                       b.durationVal("max_query_time", c.MaxQueryTime),
+		NodeID:                             types.NodeID(stringVal(c.NodeID)),
+		NodeMeta:                           c.NodeMeta,
+		NodeName:                           b.nodeName(c.NodeName),
+		ReadReplica:                        boolVal(c.ReadReplica),
+		PeeringEnabled:                     boolVal(c.Peering.Enabled),
+		PeeringTestAllowPeerRegistrations:  boolValWithDefault(c.Peering.TestAllowPeerRegistrations, false),
+		PidFile:                            stringVal(c.PidFile),
+		PrimaryDatacenter:                  primaryDatacenter,
+		PrimaryGateways:                    b.expandAllOptionalAddrs("primary_gateways", c.PrimaryGateways),
+		PrimaryGatewaysInterval:            b.durationVal("primary_gateways_interval", c.PrimaryGatewaysInterval),
+		RPCAdvertiseAddr:                   rpcAdvertiseAddr,
+		RPCBindAddr:                        rpcBindAddr,
+		RPCHandshakeTimeout:                b.durationVal("limits.rpc_handshake_timeout", c.Limits.RPCHandshakeTimeout),
+		RPCHoldTimeout:                     b.durationVal("performance.rpc_hold_timeout", c.Performance.RPCHoldTimeout),
+		RPCClientTimeout:                   b.durationVal("limits.rpc_client_timeout", c.Limits.RPCClientTimeout),
+		RPCMaxBurst:                        intVal(c.Limits.RPCMaxBurst),
+		RPCMaxConnsPerClient:               intVal(c.Limits.RPCMaxConnsPerClient),
+		RPCProtocol:                        intVal(c.RPCProtocol),
+		RPCRateLimit:                       limitVal(c.Limits.RPCRate),
+		RPCConfig:                          consul.RPCConfig{EnableStreaming: boolValWithDefault(c.RPC.EnableStreaming, serverMode)},
+		RaftProtocol:                       intVal(c.RaftProtocol),
+		RaftSnapshotThreshold:              intVal(c.RaftSnapshotThreshold),
+		RaftSnapshotInterval:               b.durationVal("raft_snapshot_interval", c.RaftSnapshotInterval),
+		RaftTrailingLogs:                   intVal(c.RaftTrailingLogs),
+		RaftPreVoteDisabled:                boolVal(c.RaftPreVoteDisabled),
+		RaftLogStoreConfig:                 b.raftLogStoreConfigVal(&c.RaftLogStore),
+		ReconnectTimeoutLAN:                b.durationVal("reconnect_timeout", c.ReconnectTimeoutLAN),
+		ReconnectTimeoutWAN:                b.durationVal("reconnect_timeout_wan", c.ReconnectTimeoutWAN),
+		RejoinAfterLeave:                   boolVal(c.RejoinAfterLeave),
+		RequestLimitsMode:                  b.requestsLimitsModeVal(stringVal(c.Limits.RequestLimits.Mode)),
+		RequestLimitsReadRate:              limitVal(c.Limits.RequestLimits.ReadRate),
+		RequestLimitsWriteRate:             limitVal(c.Limits.RequestLimits.WriteRate),
+		RetryJoinIntervalLAN:               b.durationVal("retry_interval", c.RetryJoinIntervalLAN),
+		RetryJoinIntervalWAN:               b.durationVal("retry_interval_wan", c.RetryJoinIntervalWAN),
+		RetryJoinLAN:                       b.expandAllOptionalAddrs("retry_join", c.RetryJoinLAN),
+		RetryJoinMaxAttemptsLAN:            intVal(c.RetryJoinMaxAttemptsLAN),
+		RetryJoinMaxAttemptsWAN:            intVal(c.RetryJoinMaxAttemptsWAN),
+		RetryJoinWAN:                       b.expandAllOptionalAddrs("retry_join_wan", c.RetryJoinWAN),
+		SegmentName:                        stringVal(c.SegmentName),
+		Segments:                           segments,
+		SegmentLimit:                       intVal(c.SegmentLimit),
+		SerfAdvertiseAddrLAN:               serfAdvertiseAddrLAN,
+		SerfAdvertiseAddrWAN:               serfAdvertiseAddrWAN,
+		SerfAllowedCIDRsLAN:                serfAllowedCIDRSLAN,
+		SerfAllowedCIDRsWAN:                serfAllowedCIDRSWAN,
+		SerfBindAddrLAN:                    serfBindAddrLAN,
+		SerfBindAddrWAN:                    serfBindAddrWAN,
+		SerfPortLAN:                        serfPortLAN,
+		SerfPortWAN:                        serfPortWAN,
+		ServerMode:                         serverMode,
+		ServerName:                         stringVal(c.ServerName),
+		ServerPort:                         serverPort,
+		ServerRejoinAgeMax:                 b.durationValWithDefaultMin("server_rejoin_age_max", c.ServerRejoinAgeMax, 24*7*time.Hour, 6*time.Hour),
+		Services:                           services,
+		SessionCheckExemptTypes:            c.SessionCheckExemptTypes,
+		SessionMaxTTL:                      b.durationVal("session_max_ttl", c.SessionMaxTTL),
+		SessionTTLMin:                      b.durationVal("session_ttl_min", c.SessionTTLMin),
+		SkipLeaveOnInt:                     skipLeaveOnInt,
+		TaggedAddresses:                    c.TaggedAddresses,
+		TranslateWANAddrs:                  boolVal(c.TranslateWANAddrs),
+		TxnMaxReqLen:                       uint64Val(c.Limits.TxnMaxReqLen),
+		UIConfig:                           b.uiConfigVal(c.UIConfig),
+		UnixSocketGroup:                    stringVal(c.UnixSocket.Group),
+		UnixSocketMode:                     stringVal(c.UnixSocket.Mode),
+		UnixSocketUser:                     stringVal(c.UnixSocket.User),
+		VirtualIPCIDR:                      b.cidrVal("virtual_ip_cidr", c.VirtualIPCIDR),
+		Watches:                            c.Watches,
+		XDSUpdateRateLimit:                 limitVal(c.XDS.UpdateMaxPerSecond),
+		XDSCaseInsensitiveResourceNames:    boolVal(c.XDS.CaseInsensitiveResourceNames),
+		XDSResourceDiffLogServiceIDs:       c.XDS.ResourceDiffLogServiceIDs,
+		XDSSecretsRequireMeshRead:          boolVal(c.XDS.SecretsRequireMeshRead),
+		XDSMinUpdateInterval:               b.durationVal("xds.min_update_interval", c.XDS.MinUpdateInterval),
+		XDSInitialConfigTimeout:            b.durationVal("xds.initial_config_timeout", c.XDS.InitialConfigTimeout),
+		XDSTerminateOnInitialConfigTimeout: boolVal(c.XDS.TerminateOnInitialConfigTimeout),
+		XDSMaxStreamsPerPartition:          intVal(c.XDS.MaxStreamsPerPartition),
+		AutoReloadConfigCoalesceInterval:   1 * time.Second,
+		LocalProxyConfigResyncInterval:     30 * time.Second,
 	}
 
 	// host metrics are enabled if consul is configured with HashiCorp Cloud Platform integration
@@ -1346,6 +1381,15 @@ func (b *builder) validate(rt RuntimeConfig) error {
 	if rt.CheckOutputMaxSize < 1 {
 		return fmt.Errorf("check_output_max_size must be positive, to discard check output use the discard_check_output flag")
 	}
+	if rt.CheckOutputTruncateSize < 0 {
+		return fmt.Errorf("check_output_truncate_size cannot be negative")
+	}
+	if rt.XDSMaxStreamsPerPartition < 0 {
+		return fmt.Errorf("xds.max_streams_per_partition cannot be negative")
+	}
+	if rt.SessionMaxTTL < 0 {
+		return fmt.Errorf("session_max_ttl cannot be negative")
+	}
 	if rt.AEInterval <= 0 {
 		return fmt.Errorf("ae_interval cannot be %s. Must be positive", rt.AEInterval)
 	}
@@ -1396,6 +1440,14 @@ func (b *builder) validate(rt RuntimeConfig) error {
 			return fmt.Errorf("'primary_gateways' should only be configured in a secondary datacenter")
 		}
 	}
+	if rt.VirtualIPCIDR != nil {
+		if !rt.ServerMode {
+			return fmt.Errorf("'virtual_ip_cidr' requires 'server = true'")
+		}
+		if err := validateVirtualIPCIDR(rt); err != nil {
+			return err
+		}
+	}
 
 	// Check the data dir for signs of an un-migrated Consul 0.5.x or older
 	// server. Consul refuses to start if this is present to protect a server
@@ -1469,6 +1521,17 @@ func (b *builder) validate(rt RuntimeConfig) error {
 		}
 	}
 
+	roleTemplates := make(map[string]bool)
+	for _, tmpl := range rt.ACLRoleTemplates {
+		if err := tmpl.Validate(); err != nil {
+			return fmt.Errorf("acl.role_templates: %w", err)
+		}
+		if roleTemplates[tmpl.Name] {
+			return fmt.Errorf("acl.role_templates: duplicate role template %q", tmpl.Name)
+		}
+		roleTemplates[tmpl.Name] = true
+	}
+
 	// Validate the given Connect CA provider config
 	validCAProviders := map[string]bool{
 		"":                       true,
@@ -1675,6 +1738,31 @@ func (b *builder) svcTaggedAddresses(v map[string]ServiceAddress) map[string]str
 	return svcAddrs
 }
 
+func (b *builder) aclRoleTemplatesVal(v []ACLRoleTemplate) []*structs.ACLRoleTemplate {
+	var out []*structs.ACLRoleTemplate
+	for _, raw := range v {
+		tmpl := &structs.ACLRoleTemplate{
+			Name:        stringVal(raw.Name),
+			Description: stringVal(raw.Description),
+			Policies:    raw.Policies,
+		}
+		for _, svcid := range raw.ServiceIdentities {
+			tmpl.ServiceIdentities = append(tmpl.ServiceIdentities, &structs.ACLServiceIdentity{
+				ServiceName: stringVal(svcid.ServiceName),
+				Datacenters: svcid.Datacenters,
+			})
+		}
+		for _, nodeid := range raw.NodeIdentities {
+			tmpl.NodeIdentities = append(tmpl.NodeIdentities, &structs.ACLNodeIdentity{
+				NodeName:   stringVal(nodeid.NodeName),
+				Datacenter: stringVal(nodeid.Datacenter),
+			})
+		}
+		out = append(out, tmpl)
+	}
+	return out
+}
+
 func (b *builder) serviceVal(v *ServiceDefinition) *structs.ServiceDefinition {
 	if v == nil {
 		return nil
@@ -2110,6 +2198,19 @@ func (b *builder) cidrsVal(name string, v []string) (nets []*net.IPNet) {
 	return
 }
 
+func (b *builder) cidrVal(name string, v *string) *net.IPNet {
+	if v == nil {
+		return nil
+	}
+
+	_, n, err := net.ParseCIDR(strings.TrimSpace(*v))
+	if err != nil {
+		b.err = multierror.Append(b.err, fmt.Errorf("%s: invalid cidr: %s", name, *v))
+		return nil
+	}
+	return n
+}
+
 func (b *builder) tlsVersion(name string, v *string) types.TLSVersion {
 	// Handles unspecified config and empty string case.
 	//
@@ -2472,6 +2573,38 @@ func (b *builder) autoConfigAuthorizerVal(raw AutoConfigAuthorizationRaw, agentP
 	return val
 }
 
+// minVirtualIPCIDRPrefix is the longest prefix allowed for virtual_ip_cidr,
+// which keeps enough addresses available for every service in the cluster.
+const minVirtualIPCIDRPrefix = 16
+
+// validateVirtualIPCIDR checks that the virtual IP range is large enough and
+// doesn't overlap the addresses this agent binds to or advertises, since
+// traffic to those would otherwise be captured by transparent proxies.
+func validateVirtualIPCIDR(rt RuntimeConfig) error {
+	cidr := rt.VirtualIPCIDR
+	if cidr.IP.To4() == nil {
+		return fmt.Errorf("virtual_ip_cidr %q must be an IPv4 range", cidr)
+	}
+	if ones, _ := cidr.Mask.Size(); ones > minVirtualIPCIDRPrefix {
+		return fmt.Errorf("virtual_ip_cidr %q is too small, it must be a /%d or larger", cidr, minVirtualIPCIDRPrefix)
+	}
+
+	addrs := []struct {
+		name string
+		addr *net.IPAddr
+	}{
+		{"bind_addr", rt.BindAddr},
+		{"advertise_addr", rt.AdvertiseAddrLAN},
+		{"advertise_addr_wan", rt.AdvertiseAddrWAN},
+	}
+	for _, a := range addrs {
+		if a.addr != nil && cidr.Contains(a.addr.IP) {
+			return fmt.Errorf("virtual_ip_cidr %q overlaps %s %s", cidr, a.name, a.addr.IP)
+		}
+	}
+	return nil
+}
+
 func (b *builder) validateAutoConfig(rt RuntimeConfig) error {
 	autoconf := rt.AutoConfig
 
@@ -733,6 +733,38 @@ func (o *RuntimeConfig) DeepCopy() *RuntimeConfig {
 			copy(cp.Cloud.TLSConfig.EncryptedClientHelloConfigList, o.Cloud.TLSConfig.EncryptedClientHelloConfigList)
 		}
 	}
+	if o.ACLRoleTemplates != nil {
+		cp.ACLRoleTemplates = make([]*structs.ACLRoleTemplate, len(o.ACLRoleTemplates))
+		copy(cp.ACLRoleTemplates, o.ACLRoleTemplates)
+		for i2 := range o.ACLRoleTemplates {
+			if o.ACLRoleTemplates[i2] != nil {
+				cp.ACLRoleTemplates[i2] = new(structs.ACLRoleTemplate)
+				*cp.ACLRoleTemplates[i2] = *o.ACLRoleTemplates[i2]
+				if o.ACLRoleTemplates[i2].Policies != nil {
+					cp.ACLRoleTemplates[i2].Policies = make([]string, len(o.ACLRoleTemplates[i2].Policies))
+					copy(cp.ACLRoleTemplates[i2].Policies, o.ACLRoleTemplates[i2].Policies)
+				}
+				if o.ACLRoleTemplates[i2].ServiceIdentities != nil {
+					cp.ACLRoleTemplates[i2].ServiceIdentities = make([]*structs.ACLServiceIdentity, len(o.ACLRoleTemplates[i2].ServiceIdentities))
+					copy(cp.ACLRoleTemplates[i2].ServiceIdentities, o.ACLRoleTemplates[i2].ServiceIdentities)
+					for i5 := range o.ACLRoleTemplates[i2].ServiceIdentities {
+						if o.ACLRoleTemplates[i2].ServiceIdentities[i5] != nil {
+							cp.ACLRoleTemplates[i2].ServiceIdentities[i5] = o.ACLRoleTemplates[i2].ServiceIdentities[i5].Clone()
+						}
+					}
+				}
+				if o.ACLRoleTemplates[i2].NodeIdentities != nil {
+					cp.ACLRoleTemplates[i2].NodeIdentities = make([]*structs.ACLNodeIdentity, len(o.ACLRoleTemplates[i2].NodeIdentities))
+					copy(cp.ACLRoleTemplates[i2].NodeIdentities, o.ACLRoleTemplates[i2].NodeIdentities)
+					for i5 := range o.ACLRoleTemplates[i2].NodeIdentities {
+						if o.ACLRoleTemplates[i2].NodeIdentities[i5] != nil {
+							cp.ACLRoleTemplates[i2].NodeIdentities[i5] = o.ACLRoleTemplates[i2].NodeIdentities[i5].Clone()
+						}
+					}
+				}
+			}
+		}
+	}
 	if o.DNSServiceTTL != nil {
 		cp.DNSServiceTTL = make(map[string]time.Duration, len(o.DNSServiceTTL))
 		for k2, v2 := range o.DNSServiceTTL {
@@ -892,6 +924,12 @@ func (o *RuntimeConfig) DeepCopy() *RuntimeConfig {
 		cp.ConfigEntryBootstrap = make([]structs.ConfigEntry, len(o.ConfigEntryBootstrap))
 		copy(cp.ConfigEntryBootstrap, o.ConfigEntryBootstrap)
 	}
+	if o.ConfigEntryMaxPerKindPerPartition != nil {
+		cp.ConfigEntryMaxPerKindPerPartition = make(map[string]int, len(o.ConfigEntryMaxPerKindPerPartition))
+		for k2, v2 := range o.ConfigEntryMaxPerKindPerPartition {
+			cp.ConfigEntryMaxPerKindPerPartition[k2] = v2
+		}
+	}
 	if o.AutoEncryptDNSSAN != nil {
 		cp.AutoEncryptDNSSAN = make([]string, len(o.AutoEncryptDNSSAN))
 		copy(cp.AutoEncryptDNSSAN, o.AutoEncryptDNSSAN)
@@ -1097,6 +1135,10 @@ func (o *RuntimeConfig) DeepCopy() *RuntimeConfig {
 			}
 		}
 	}
+	if o.SessionCheckExemptTypes != nil {
+		cp.SessionCheckExemptTypes = make([]string, len(o.SessionCheckExemptTypes))
+		copy(cp.SessionCheckExemptTypes, o.SessionCheckExemptTypes)
+	}
 	if o.TLS.InternalRPC.CipherSuites != nil {
 		cp.TLS.InternalRPC.CipherSuites = make([]types.TLSCipherSuite, len(o.TLS.InternalRPC.CipherSuites))
 		copy(cp.TLS.InternalRPC.CipherSuites, o.TLS.InternalRPC.CipherSuites)
@@ -1133,6 +1175,18 @@ func (o *RuntimeConfig) DeepCopy() *RuntimeConfig {
 			cp.UIConfig.DashboardURLTemplates[k3] = v3
 		}
 	}
+	if o.VirtualIPCIDR != nil {
+		cp.VirtualIPCIDR = new(net.IPNet)
+		*cp.VirtualIPCIDR = *o.VirtualIPCIDR
+		if o.VirtualIPCIDR.IP != nil {
+			cp.VirtualIPCIDR.IP = make([]byte, len(o.VirtualIPCIDR.IP))
+			copy(cp.VirtualIPCIDR.IP, o.VirtualIPCIDR.IP)
+		}
+		if o.VirtualIPCIDR.Mask != nil {
+			cp.VirtualIPCIDR.Mask = make([]byte, len(o.VirtualIPCIDR.Mask))
+			copy(cp.VirtualIPCIDR.Mask, o.VirtualIPCIDR.Mask)
+		}
+	}
 	if o.Watches != nil {
 		cp.Watches = make([]map[string]interface{}, len(o.Watches))
 		copy(cp.Watches, o.Watches)
@@ -123,6 +123,9 @@ type Cache struct {
 	EntryFetchMaxBurst *int `mapstructure:"entry_fetch_max_burst"`
 	// EntryFetchRate represents the max calls/sec for a single cache entry
 	EntryFetchRate *float64 `mapstructure:"entry_fetch_rate"`
+	// IntentionMatchTTL is how long an intention match result stays cached
+	// after it was last read
+	IntentionMatchTTL *string `mapstructure:"intention_match_ttl"`
 }
 
 // Config defines the format of a configuration file in either JSON or
@@ -152,6 +155,7 @@ type Config struct {
 	Cache                            Cache               `mapstructure:"cache" json:"-"`
 	Check                            *CheckDefinition    `mapstructure:"check" json:"-"` // needs to be a pointer to avoid partial merges
 	CheckOutputMaxSize               *int                `mapstructure:"check_output_max_size" json:"check_output_max_size,omitempty"`
+	CheckOutputTruncateSize          *int                `mapstructure:"check_output_truncate_size" json:"check_output_truncate_size,omitempty"`
 	CheckUpdateInterval              *string             `mapstructure:"check_update_interval" json:"check_update_interval,omitempty"`
 	Checks                           []CheckDefinition   `mapstructure:"checks" json:"-"`
 	ClientAddr                       *string             `mapstructure:"client_addr" json:"client_addr,omitempty"`
@@ -235,6 +239,8 @@ type Config struct {
 	ServerRejoinAgeMax               *string             `mapstructure:"server_rejoin_age_max" json:"server_rejoin_age_max,omitempty"`
 	Service                          *ServiceDefinition  `mapstructure:"service" json:"-"`
 	Services                         []ServiceDefinition `mapstructure:"services" json:"-"`
+	SessionCheckExemptTypes          []string            `mapstructure:"session_check_exempt_types" json:"session_check_exempt_types,omitempty"`
+	SessionMaxTTL                    *string             `mapstructure:"session_max_ttl" json:"session_max_ttl,omitempty"`
 	SessionTTLMin                    *string             `mapstructure:"session_ttl_min" json:"session_ttl_min,omitempty"`
 	SkipLeaveOnInt                   *bool               `mapstructure:"skip_leave_on_interrupt" json:"skip_leave_on_interrupt,omitempty"`
 	SyslogFacility                   *string             `mapstructure:"syslog_facility" json:"syslog_facility,omitempty"`
@@ -242,6 +248,7 @@ type Config struct {
 	TaggedAddresses                  map[string]string   `mapstructure:"tagged_addresses" json:"tagged_addresses,omitempty"`
 	Telemetry                        Telemetry           `mapstructure:"telemetry" json:"telemetry,omitempty"`
 	TranslateWANAddrs                *bool               `mapstructure:"translate_wan_addrs" json:"translate_wan_addrs,omitempty"`
+	VirtualIPCIDR                    *string             `mapstructure:"virtual_ip_cidr" json:"virtual_ip_cidr,omitempty"`
 	XDS                              XDS                 `mapstructure:"xds" json:"-"`
 
 	// DEPRECATED (ui-config) - moved to the ui_config stanza
@@ -632,6 +639,7 @@ type Connect struct {
 	CAProvider                      *string                `mapstructure:"ca_provider" json:"ca_provider,omitempty"`
 	CAConfig                        map[string]interface{} `mapstructure:"ca_config" json:"ca_config,omitempty"`
 	MeshGatewayWANFederationEnabled *bool                  `mapstructure:"enable_mesh_gateway_wan_federation" json:"enable_mesh_gateway_wan_federation,omitempty"`
+	IntentionAuditLog               *bool                  `mapstructure:"intention_audit_log" json:"intention_audit_log,omitempty"`
 
 	// TestCALeafRootChangeSpread controls how long after a CA roots change before new leaf certs will be generated.
 	// This is only tuned in tests, generally set to 1ns to make tests deterministic with when to expect updated leaf
@@ -775,10 +783,30 @@ type ACL struct {
 	Tokens                 Tokens  `mapstructure:"tokens"`
 	EnableTokenPersistence *bool   `mapstructure:"enable_token_persistence"`
 
+	RoleTemplates []ACLRoleTemplate `mapstructure:"role_templates"`
+
 	// Enterprise Only
 	MSPDisableBootstrap *bool `mapstructure:"msp_disable_bootstrap"`
 }
 
+type ACLRoleTemplate struct {
+	Name              *string                          `mapstructure:"name"`
+	Description       *string                          `mapstructure:"description"`
+	Policies          []string                         `mapstructure:"policies"`
+	ServiceIdentities []ACLRoleTemplateServiceIdentity `mapstructure:"service_identities"`
+	NodeIdentities    []ACLRoleTemplateNodeIdentity    `mapstructure:"node_identities"`
+}
+
+type ACLRoleTemplateServiceIdentity struct {
+	ServiceName *string  `mapstructure:"service_name"`
+	Datacenters []string `mapstructure:"datacenters"`
+}
+
+type ACLRoleTemplateNodeIdentity struct {
+	NodeName   *string `mapstructure:"node_name"`
+	Datacenter *string `mapstructure:"datacenter"`
+}
+
 type Tokens struct {
 	InitialManagement      *string `mapstructure:"initial_management"`
 	Replication            *string `mapstructure:"replication"`
@@ -814,6 +842,23 @@ type ConfigEntries struct {
 	// need to figure out the right concrete type before we can decode it
 	// unabiguously.
 	Bootstrap []map[string]interface{} `mapstructure:"bootstrap"`
+
+	// MaxPerPartition limits the number of config entries that may exist in
+	// a single admin partition. Zero means unlimited.
+	MaxPerPartition *int `mapstructure:"max_per_partition"`
+
+	// MaxPerKindPerPartition limits the number of config entries of a given
+	// kind that may exist in a single admin partition.
+	MaxPerKindPerPartition map[string]int `mapstructure:"max_per_kind_per_partition"`
+
+	// MaxIntentionPermissions limits the number of L7 permissions a single
+	// source may have in a service-intentions config entry. Zero means
+	// unlimited.
+	MaxIntentionPermissions *int `mapstructure:"max_intention_permissions"`
+
+	// StrictFailoverDatacenters rejects service-resolver writes whose failover
+	// references a datacenter that is not known to the servers.
+	StrictFailoverDatacenters *bool `mapstructure:"strict_failover_datacenters"`
 }
 
 // Audit allows us to enable and define destinations for auditing
@@ -947,7 +992,14 @@ type Peering struct {
 }
 
 type XDS struct {
-	UpdateMaxPerSecond *float64 `mapstructure:"update_max_per_second"`
+	UpdateMaxPerSecond              *float64 `mapstructure:"update_max_per_second"`
+	CaseInsensitiveResourceNames    *bool    `mapstructure:"case_insensitive_resource_names"`
+	ResourceDiffLogServiceIDs       []string `mapstructure:"resource_diff_log_service_ids"`
+	SecretsRequireMeshRead          *bool    `mapstructure:"secrets_require_mesh_read"`
+	MinUpdateInterval               *string  `mapstructure:"min_update_interval"`
+	InitialConfigTimeout            *string  `mapstructure:"initial_config_timeout"`
+	TerminateOnInitialConfigTimeout *bool    `mapstructure:"terminate_on_initial_config_timeout"`
+	MaxStreamsPerPartition          *int     `mapstructure:"max_streams_per_partition"`
 }
 
 type RaftLogStoreRaw struct {
@@ -155,6 +155,7 @@ func DefaultSource() Source {
 		}
 		xds {
 			update_max_per_second = 250
+			initial_config_timeout = "1m"
 		}
 
 		connect = {
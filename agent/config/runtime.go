@@ -109,6 +109,13 @@ type RuntimeConfig struct {
 	// hcl: acl.token_replication = boolean
 	ACLTokenReplication bool
 
+	// ACLRoleTemplates are role definitions that new roles can be created
+	// from. Their fields may reference variables given at creation time as
+	// {{.name}}. Only used by servers in the primary datacenter.
+	//
+	// hcl: acl { role_templates = [{ name = string, description = string, policies = []string, service_identities = [{ service_name = string, datacenters = []string }], node_identities = [{ node_name = string, datacenter = string }] }] }
+	ACLRoleTemplates []*structs.ACLRoleTemplate
+
 	// AutopilotCleanupDeadServers enables the automatic cleanup of dead servers when new ones
 	// are added to the peer list. Defaults to true.
 	//
@@ -405,6 +412,13 @@ type RuntimeConfig struct {
 	// Cache represent cache configuration of agent
 	Cache cache.Options
 
+	// IntentionMatchCacheTTL is how long the intention match results used by
+	// the /v1/agent/connect/authorize endpoint stay cached after they were
+	// last read. Zero uses the default TTL of the agent cache.
+	//
+	// hcl: cache { intention_match_ttl = "duration" }
+	IntentionMatchCacheTTL time.Duration
+
 	// CheckUpdateInterval controls the interval on which the output of a health check
 	// is updated if there is no change to the state. For example, a check in a steady
 	// state may run every 5 second generating a unique output (timestamp, etc), forcing
@@ -421,6 +435,15 @@ type RuntimeConfig struct {
 	// flag: -check_output_max_size int
 	CheckOutputMaxSize int
 
+	// CheckOutputTruncateSize is the size above which the output of a health
+	// check is truncated before it is stored and synced to the servers. The
+	// agent keeps the full output of the last run, which can be read from
+	// the agent's check output endpoint. Zero disables the truncation.
+	// (reloadable)
+	//
+	// hcl: check_output_truncate_size = int
+	CheckOutputTruncateSize int
+
 	// Checks contains the provided check definitions.
 	//
 	// hcl: checks = [
@@ -474,6 +497,33 @@ type RuntimeConfig struct {
 	// If entries of the same Kind/Name exist already these will not update them.
 	ConfigEntryBootstrap []structs.ConfigEntry
 
+	// ConfigEntryMaxPerPartition is the maximum number of config entries that
+	// may exist in a single admin partition. Zero means unlimited.
+	//
+	// hcl: config_entries { max_per_partition = int }
+	ConfigEntryMaxPerPartition int
+
+	// ConfigEntryMaxPerKindPerPartition limits the number of config entries of
+	// a given kind that may exist in a single admin partition. Kinds that are
+	// absent or set to zero are unlimited.
+	//
+	// hcl: config_entries { max_per_kind_per_partition = map[string]int }
+	ConfigEntryMaxPerKindPerPartition map[string]int
+
+	// ConfigEntryMaxIntentionPermissions is the maximum number of L7
+	// permissions a single source may have in a service-intentions config
+	// entry. Zero means unlimited.
+	//
+	// hcl: config_entries { max_intention_permissions = int }
+	ConfigEntryMaxIntentionPermissions int
+
+	// ConfigEntryStrictFailoverDatacenters rejects service-resolver writes
+	// whose failover references a datacenter that is not known through the
+	// WAN pool or federation states. When false these are only logged.
+	//
+	// hcl: config_entries { strict_failover_datacenters = (true|false) }
+	ConfigEntryStrictFailoverDatacenters bool
+
 	// AutoEncryptTLS requires the client to acquire TLS certificates from
 	// servers.
 	AutoEncryptTLS bool
@@ -526,6 +576,12 @@ type RuntimeConfig struct {
 	// datacenters should exclusively traverse mesh gateways.
 	ConnectMeshGatewayWANFederationEnabled bool
 
+	// ConnectIntentionAuditLog enables logging of every intention decision
+	// made by the agent, including the intention or default that decided it.
+	//
+	// hcl: connect { intention_audit_log = (true|false) }
+	ConnectIntentionAuditLog bool
+
 	// ConnectTestCALeafRootChangeSpread is used to control how long the CA leaf
 	// cache with spread CSRs over when a root change occurs. For now we don't
 	// expose this in public config intentionally but could later with a rename.
@@ -1419,6 +1475,18 @@ type RuntimeConfig struct {
 	// ]
 	Services []*structs.ServiceDefinition
 
+	// SessionCheckExemptTypes lists the health check types, in addition to
+	// "session", that are allowed to be critical when a session is created.
+	//
+	// hcl: session_check_exempt_types = []string
+	SessionCheckExemptTypes []string
+
+	// SessionMaxTTL is the longest TTL that a session can be created with.
+	// Zero means there is no limit beyond the built-in maximum of 24h.
+	//
+	// hcl: session_max_ttl = "duration"
+	SessionMaxTTL time.Duration
+
 	// Minimum Session TTL.
 	//
 	// hcl: session_ttl_min = "duration"
@@ -1496,6 +1564,12 @@ type RuntimeConfig struct {
 	// hcl: unix_sockets { user = string }
 	UnixSocketUser string
 
+	// VirtualIPCIDR is the IPv4 range that servers allocate service virtual
+	// IPs from. When nil the default range of 240.0.0.0/4 is used.
+	//
+	// hcl: virtual_ip_cidr = string
+	VirtualIPCIDR *net.IPNet
+
 	StaticRuntimeConfig StaticRuntimeConfig
 
 	// Watches are used to monitor various endpoints and to invoke a
@@ -1521,6 +1595,50 @@ type RuntimeConfig struct {
 	// hcl: xds { update_max_per_second = (float64|MaxFloat64) }
 	XDSUpdateRateLimit rate.Limit
 
+	// XDSCaseInsensitiveResourceNames makes the resource names proxies
+	// subscribe to over xDS match resources regardless of case.
+	//
+	// hcl: xds { case_insensitive_resource_names = (true|false) }
+	XDSCaseInsensitiveResourceNames bool
+
+	// XDSResourceDiffLogServiceIDs lists the service IDs of proxies whose xDS
+	// resource changes are logged when their configuration updates.
+	//
+	// hcl: xds { resource_diff_log_service_ids = []string }
+	XDSResourceDiffLogServiceIDs []string
+
+	// XDSSecretsRequireMeshRead withholds SDS secrets from proxies whose
+	// token does not also have mesh:read.
+	//
+	// hcl: xds { secrets_require_mesh_read = (true|false) }
+	XDSSecretsRequireMeshRead bool
+
+	// XDSMinUpdateInterval is the minimum time between two configuration
+	// update sweeps on a single xDS stream. Snapshots arriving faster than
+	// this are coalesced and only the latest is delivered. Zero disables it.
+	//
+	// hcl: xds { min_update_interval = "duration" }
+	XDSMinUpdateInterval time.Duration
+
+	// XDSInitialConfigTimeout is how long an xDS stream may wait for the
+	// proxy's first config snapshot before it is logged and counted. Zero
+	// disables the check.
+	//
+	// hcl: xds { initial_config_timeout = "duration" }
+	XDSInitialConfigTimeout time.Duration
+
+	// XDSTerminateOnInitialConfigTimeout closes xDS streams that exceed
+	// XDSInitialConfigTimeout with an error.
+	//
+	// hcl: xds { terminate_on_initial_config_timeout = (true|false) }
+	XDSTerminateOnInitialConfigTimeout bool
+
+	// XDSMaxStreamsPerPartition limits the number of concurrent xDS streams
+	// from the proxies of a single admin partition. Zero means no limit.
+	//
+	// hcl: xds { max_streams_per_partition = int }
+	XDSMaxStreamsPerPartition int
+
 	// AutoReloadConfigCoalesceInterval Coalesce Interval for auto reload config
 	AutoReloadConfigCoalesceInterval time.Duration
 
@@ -1729,6 +1729,20 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 			rt.DataDir = dataDir
 		},
 	})
+	run(t, testCase{
+		desc:        "acl.role_templates invalid template",
+		args:        []string{`-data-dir=` + dataDir},
+		json:        []string{`{ "acl": { "role_templates": [{ "name": "svc", "policies": ["{{.name"] }] } }`},
+		hcl:         []string{`acl { role_templates = [{ name = "svc", policies = ["{{.name"] }] }`},
+		expectedErr: `acl.role_templates: role template "svc" is not valid`,
+	})
+	run(t, testCase{
+		desc:        "acl.role_templates duplicate name",
+		args:        []string{`-data-dir=` + dataDir},
+		json:        []string{`{ "acl": { "role_templates": [{ "name": "svc" }, { "name": "svc" }] } }`},
+		hcl:         []string{`acl { role_templates = [{ name = "svc" }, { name = "svc" }] }`},
+		expectedErr: `acl.role_templates: duplicate role template "svc"`,
+	})
 	run(t, testCase{
 		desc: "acl_enforce_version_8 is deprecated",
 		args: []string{`-data-dir=` + dataDir},
@@ -3862,6 +3876,108 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 			`},
 		expectedErr: "'primary_gateways' should only be configured in a secondary datacenter",
 	})
+	run(t, testCase{
+		desc: "virtual_ip_cidr requires server mode",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`{
+			  "server": false,
+			  "virtual_ip_cidr": "100.64.0.0/10"
+			}`},
+		hcl: []string{`
+			  server = false
+			  virtual_ip_cidr = "100.64.0.0/10"
+			`},
+		expectedErr: "'virtual_ip_cidr' requires 'server = true'",
+	})
+	run(t, testCase{
+		desc: "virtual_ip_cidr invalid",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`{
+			  "server": true,
+			  "virtual_ip_cidr": "100.64.0.0"
+			}`},
+		hcl: []string{`
+			  server = true
+			  virtual_ip_cidr = "100.64.0.0"
+			`},
+		expectedErr: "virtual_ip_cidr: invalid cidr: 100.64.0.0",
+	})
+	run(t, testCase{
+		desc: "virtual_ip_cidr must be ipv4",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`{
+			  "server": true,
+			  "virtual_ip_cidr": "fd00::/8"
+			}`},
+		hcl: []string{`
+			  server = true
+			  virtual_ip_cidr = "fd00::/8"
+			`},
+		expectedErr: `virtual_ip_cidr "fd00::/8" must be an IPv4 range`,
+	})
+	run(t, testCase{
+		desc: "virtual_ip_cidr too small",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`{
+			  "server": true,
+			  "virtual_ip_cidr": "100.64.0.0/24"
+			}`},
+		hcl: []string{`
+			  server = true
+			  virtual_ip_cidr = "100.64.0.0/24"
+			`},
+		expectedErr: `virtual_ip_cidr "100.64.0.0/24" is too small, it must be a /16 or larger`,
+	})
+	run(t, testCase{
+		desc: "virtual_ip_cidr overlaps advertise address",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`{
+			  "server": true,
+			  "advertise_addr": "100.64.1.1",
+			  "virtual_ip_cidr": "100.64.0.0/10"
+			}`},
+		hcl: []string{`
+			  server = true
+			  advertise_addr = "100.64.1.1"
+			  virtual_ip_cidr = "100.64.0.0/10"
+			`},
+		expectedErr: `virtual_ip_cidr "100.64.0.0/10" overlaps advertise_addr 100.64.1.1`,
+	})
+	run(t, testCase{
+		desc: "virtual_ip_cidr",
+		args: []string{
+			`-data-dir=` + dataDir,
+		},
+		json: []string{`{
+			  "server": true,
+			  "virtual_ip_cidr": "100.64.0.0/10"
+			}`},
+		hcl: []string{`
+			  server = true
+			  virtual_ip_cidr = "100.64.0.0/10"
+			`},
+		expected: func(rt *RuntimeConfig) {
+			rt.ServerMode = true
+			rt.TLS.ServerMode = true
+			rt.LeaveOnTerm = false
+			rt.SkipLeaveOnInt = true
+			rt.DataDir = dataDir
+			rt.RPCConfig.EnableStreaming = true
+			rt.GRPCTLSPort = 8503
+			rt.GRPCTLSAddrs = []net.Addr{defaultGrpcTlsAddr}
+			rt.VirtualIPCIDR = &net.IPNet{IP: net.IP{100, 64, 0, 0}, Mask: net.CIDRMask(10, 32)}
+		},
+	})
 	run(t, testCase{
 		desc: "connect.enable_mesh_gateway_wan_federation in secondary with primary_gateways configured",
 		args: []string{
@@ -3908,6 +4024,96 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 	// ------------------------------------------------------------
 	// ConfigEntry Handling
 	//
+	run(t, testCase{
+		desc: "ConfigEntry max_per_partition negative",
+		args: []string{`-data-dir=` + dataDir},
+		json: []string{`{
+				"config_entries": {
+					"max_per_partition": -1
+				}
+			}`},
+		hcl: []string{`
+			config_entries {
+				max_per_partition = -1
+			}`},
+		expectedErr: "config_entries.max_per_partition cannot be negative",
+	})
+	run(t, testCase{
+		desc: "ConfigEntry max_per_kind_per_partition unknown kind",
+		args: []string{`-data-dir=` + dataDir},
+		json: []string{`{
+				"config_entries": {
+					"max_per_kind_per_partition": {
+						"foo": 1
+					}
+				}
+			}`},
+		hcl: []string{`
+			config_entries {
+				max_per_kind_per_partition = {
+					foo = 1
+				}
+			}`},
+		expectedErr: `config_entries.max_per_kind_per_partition["foo"]: invalid config entry kind: foo`,
+	})
+	run(t, testCase{
+		desc: "ConfigEntry max_per_kind_per_partition negative",
+		args: []string{`-data-dir=` + dataDir},
+		json: []string{`{
+				"config_entries": {
+					"max_per_kind_per_partition": {
+						"service-defaults": -1
+					}
+				}
+			}`},
+		hcl: []string{`
+			config_entries {
+				max_per_kind_per_partition = {
+					"service-defaults" = -1
+				}
+			}`},
+		expectedErr: `config_entries.max_per_kind_per_partition["service-defaults"] cannot be negative`,
+	})
+	run(t, testCase{
+		desc: "ConfigEntry max_intention_permissions negative",
+		args: []string{`-data-dir=` + dataDir},
+		json: []string{`{
+				"config_entries": {
+					"max_intention_permissions": -1
+				}
+			}`},
+		hcl: []string{`
+			config_entries {
+				max_intention_permissions = -1
+			}`},
+		expectedErr: "config_entries.max_intention_permissions cannot be negative",
+	})
+	run(t, testCase{
+		desc: "ConfigEntry max per partition limits",
+		args: []string{`-data-dir=` + dataDir},
+		json: []string{`{
+				"config_entries": {
+					"max_per_partition": 100,
+					"max_per_kind_per_partition": {
+						"service-defaults": 10
+					}
+				}
+			}`},
+		hcl: []string{`
+			config_entries {
+				max_per_partition = 100
+				max_per_kind_per_partition = {
+					"service-defaults" = 10
+				}
+			}`},
+		expected: func(rt *RuntimeConfig) {
+			rt.DataDir = dataDir
+			rt.ConfigEntryMaxPerPartition = 100
+			rt.ConfigEntryMaxPerKindPerPartition = map[string]int{
+				structs.ServiceDefaults: 10,
+			}
+		},
+	})
 	run(t, testCase{
 		desc: "ConfigEntry bootstrap doesn't parse",
 		args: []string{`-data-dir=` + dataDir},
@@ -4834,6 +5040,7 @@ func TestLoad_IntegrationWithFlags(t *testing.T) {
 			rt.RequestLimitsWriteRate = rate.Inf
 			rt.SegmentLimit = 64
 			rt.XDSUpdateRateLimit = 250
+			rt.XDSInitialConfigTimeout = time.Minute
 			rt.RPCRateLimit = rate.Inf
 			rt.RPCMaxBurst = 1000
 		},
@@ -6316,9 +6523,22 @@ func TestLoad_FullConfig(t *testing.T) {
 			ACLPolicyTTL:     1123 * time.Second,
 			ACLRoleTTL:       9876 * time.Second,
 		},
-		ACLEnableKeyListPolicy:           true,
-		ACLInitialManagementToken:        "3820e09a",
-		ACLTokenReplication:              true,
+		ACLEnableKeyListPolicy:    true,
+		ACLInitialManagementToken: "3820e09a",
+		ACLTokenReplication:       true,
+		ACLRoleTemplates: []*structs.ACLRoleTemplate{
+			{
+				Name:        "service-role",
+				Description: "Role for {{.name}}",
+				Policies:    []string{"base-policy"},
+				ServiceIdentities: []*structs.ACLServiceIdentity{
+					{ServiceName: "{{.name}}", Datacenters: []string{"dc1"}},
+				},
+				NodeIdentities: []*structs.ACLNodeIdentity{
+					{NodeName: "{{.node}}", Datacenter: "dc1"},
+				},
+			},
+		},
 		AdvertiseAddrLAN:                 ipAddr("17.99.29.16"),
 		AdvertiseAddrWAN:                 ipAddr("78.63.37.19"),
 		AdvertiseReconnectTimeout:        0 * time.Second,
@@ -6336,7 +6556,9 @@ func TestLoad_FullConfig(t *testing.T) {
 			EntryFetchMaxBurst: 42,
 			EntryFetchRate:     0.334,
 		},
-		CheckOutputMaxSize: checks.DefaultBufSize,
+		IntentionMatchCacheTTL:  17 * time.Minute,
+		CheckOutputMaxSize:      checks.DefaultBufSize,
+		CheckOutputTruncateSize: 1024,
 		Checks: []*structs.CheckDefinition{
 			{
 				ID:         "uAjE6m9Z",
@@ -6443,10 +6665,16 @@ func TestLoad_FullConfig(t *testing.T) {
 				},
 			},
 		},
-		AutoEncryptTLS:      false,
-		AutoEncryptDNSSAN:   []string{"a.com", "b.com"},
-		AutoEncryptIPSAN:    []net.IP{net.ParseIP("192.168.4.139"), net.ParseIP("192.168.4.140")},
-		AutoEncryptAllowTLS: true,
+		ConfigEntryMaxPerPartition: 4392,
+		ConfigEntryMaxPerKindPerPartition: map[string]int{
+			structs.ServiceDefaults: 2719,
+		},
+		ConfigEntryMaxIntentionPermissions:   38,
+		ConfigEntryStrictFailoverDatacenters: true,
+		AutoEncryptTLS:                       false,
+		AutoEncryptDNSSAN:                    []string{"a.com", "b.com"},
+		AutoEncryptIPSAN:                     []net.IP{net.ParseIP("192.168.4.139"), net.ParseIP("192.168.4.140")},
+		AutoEncryptAllowTLS:                  true,
 		AutoConfig: AutoConfig{
 			Enabled:         false,
 			IntroToken:      "OpBPGRwt",
@@ -6498,6 +6726,7 @@ func TestLoad_FullConfig(t *testing.T) {
 			"CSRMaxConcurrent":    float64(2),
 		},
 		ConnectMeshGatewayWANFederationEnabled: false,
+		ConnectIntentionAuditLog:               true,
 		Cloud: hcpconfig.CloudConfig{
 			ResourceID:   "N43DsscE",
 			ClientID:     "6WvsDZCP",
@@ -6930,15 +7159,17 @@ func TestLoad_FullConfig(t *testing.T) {
 				},
 			},
 		},
-		UseStreamingBackend:  true,
-		SerfAdvertiseAddrLAN: tcpAddr("17.99.29.16:8301"),
-		SerfAdvertiseAddrWAN: tcpAddr("78.63.37.19:8302"),
-		SerfBindAddrLAN:      tcpAddr("99.43.63.15:8301"),
-		SerfBindAddrWAN:      tcpAddr("67.88.33.19:8302"),
-		SerfAllowedCIDRsLAN:  []net.IPNet{},
-		SerfAllowedCIDRsWAN:  []net.IPNet{},
-		SessionTTLMin:        26627 * time.Second,
-		SkipLeaveOnInt:       true,
+		UseStreamingBackend:     true,
+		SerfAdvertiseAddrLAN:    tcpAddr("17.99.29.16:8301"),
+		SerfAdvertiseAddrWAN:    tcpAddr("78.63.37.19:8302"),
+		SerfBindAddrLAN:         tcpAddr("99.43.63.15:8301"),
+		SerfBindAddrWAN:         tcpAddr("67.88.33.19:8302"),
+		SerfAllowedCIDRsLAN:     []net.IPNet{},
+		SerfAllowedCIDRsWAN:     []net.IPNet{},
+		SessionCheckExemptTypes: []string{"Zg6cQYAn", "informational"},
+		SessionMaxTTL:           12 * time.Hour,
+		SessionTTLMin:           26627 * time.Second,
+		SkipLeaveOnInt:          true,
 		Telemetry: lib.TelemetryConfig{
 			CirconusAPIApp:                     "p4QOTe9j",
 			CirconusAPIToken:                   "E3j35V23",
@@ -7039,6 +7270,10 @@ func TestLoad_FullConfig(t *testing.T) {
 		UnixSocketUser:  "E0nB1DwA",
 		UnixSocketGroup: "8pFodrV8",
 		UnixSocketMode:  "E8sAwOv4",
+		VirtualIPCIDR: &net.IPNet{
+			IP:   net.IP{100, 96, 0, 0},
+			Mask: net.CIDRMask(12, 32),
+		},
 		Watches: []map[string]interface{}{
 			{
 				"type":       "key",
@@ -7053,7 +7288,14 @@ func TestLoad_FullConfig(t *testing.T) {
 				"args":       []interface{}{"dltjDJ2a", "flEa7C2d"},
 			},
 		},
-		XDSUpdateRateLimit: 9526.2,
+		XDSUpdateRateLimit:                 9526.2,
+		XDSCaseInsensitiveResourceNames:    true,
+		XDSResourceDiffLogServiceIDs:       []string{"web-sidecar-proxy"},
+		XDSSecretsRequireMeshRead:          true,
+		XDSMinUpdateInterval:               250 * time.Millisecond,
+		XDSInitialConfigTimeout:            45 * time.Second,
+		XDSTerminateOnInitialConfigTimeout: true,
+		XDSMaxStreamsPerPartition:          2000,
 		RaftLogStoreConfig: consul.RaftLogStoreConfig{
 			Backend:         consul.LogStoreBackendWAL,
 			DisableLogCache: true,
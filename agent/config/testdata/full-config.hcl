@@ -22,6 +22,25 @@ acl = {
     token_ttl = "3321s"
     enable_token_replication = true
     msp_disable_bootstrap = true
+    role_templates = [
+        {
+            name = "service-role"
+            description = "Role for {{.name}}"
+            policies = ["base-policy"]
+            service_identities = [
+                {
+                    service_name = "{{.name}}"
+                    datacenters = ["dc1"]
+                }
+            ]
+            node_identities = [
+                {
+                    node_name = "{{.node}}"
+                    datacenter = "dc1"
+                }
+            ]
+        }
+    ]
     tokens = {
         master = "8a19ac27",
         initial_management = "3820e09a",
@@ -94,6 +113,7 @@ bootstrap_expect = 53
 cache = {
     entry_fetch_max_burst = 42
     entry_fetch_rate = 0.334
+    intention_match_ttl = "17m"
 },
 use_streaming_backend = true
 ca_file = "erA7T0PM"
@@ -188,6 +208,7 @@ checks = [
         deregister_critical_service_after = "2366s"
     }
 ]
+check_output_truncate_size = 1024
 check_update_interval = "16507s"
 client_addr = "93.83.18.19"
 config_entries {
@@ -200,6 +221,12 @@ config_entries {
             bar = 1.0
         }
     }
+    max_per_partition = 4392
+    max_per_kind_per_partition = {
+        "service-defaults" = 2719
+    }
+    max_intention_permissions = 38
+    strict_failover_datacenters = true
 }
 auto_encrypt = {
     tls = false
@@ -228,6 +255,7 @@ connect {
     }
     enable_mesh_gateway_wan_federation = false
     enabled = true
+    intention_audit_log = true
 }
 gossip_lan {
     gossip_nodes    = 6
@@ -685,6 +713,8 @@ services = [
         }
     }
 ]
+session_check_exempt_types = [ "Zg6cQYAn", "informational" ]
+session_max_ttl = "43200s"
 session_ttl_min = "26627s"
 skip_leave_on_interrupt = true
 start_join = [ "LR3hGDoG", "MwVpZ4Up" ]
@@ -792,6 +822,7 @@ unix_sockets = {
     mode = "E8sAwOv4"
     user = "E0nB1DwA"
 }
+virtual_ip_cidr = "100.96.0.0/12"
 verify_incoming = true
 verify_incoming_https = true
 verify_incoming_rpc = true
@@ -810,4 +841,11 @@ watches = [{
 }]
 xds {
   update_max_per_second = 9526.2
+  case_insensitive_resource_names = true
+  resource_diff_log_service_ids = ["web-sidecar-proxy"]
+  secrets_require_mesh_read = true
+  min_update_interval = "250ms"
+  initial_config_timeout = "45s"
+  terminate_on_initial_config_timeout = true
+  max_streams_per_partition = 2000
 }
@@ -4,7 +4,9 @@
 package agent
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
@@ -29,8 +31,11 @@ func (s *HTTPHandlers) Config(resp http.ResponseWriter, req *http.Request) (inte
 	case "DELETE":
 		return s.configDelete(resp, req)
 
+	case "PATCH":
+		return s.configPatch(resp, req)
+
 	default:
-		return nil, MethodNotAllowedError{req.Method, []string{"GET", "DELETE"}}
+		return nil, MethodNotAllowedError{req.Method, []string{"GET", "DELETE", "PATCH"}}
 	}
 }
 
@@ -59,6 +64,9 @@ func (s *HTTPHandlers) configGet(resp http.ResponseWriter, req *http.Request) (i
 			return nil, err
 		}
 		setMeta(resp, &reply.QueryMeta)
+		if reply.PrimaryDatacenter != "" {
+			resp.Header().Set("X-Consul-Primary-Datacenter", reply.PrimaryDatacenter)
+		}
 
 		if reply.Entry == nil {
 			return nil, HTTPError{StatusCode: http.StatusNotFound, Reason: fmt.Sprintf("%s for %q / %q", ConfigEntryNotFoundErr, pathArgs[0], pathArgs[1])}
@@ -123,6 +131,15 @@ func (s *HTTPHandlers) configDelete(resp http.ResponseWriter, req *http.Request)
 		return nil, err
 	}
 
+	// With the return parameter, respond with the deleted entry, or null if
+	// nothing was deleted.
+	if _, ok := req.URL.Query()["return"]; ok {
+		if reply.Entry == nil {
+			return nil, nil
+		}
+		return reply.Entry.Entry, nil
+	}
+
 	// Return the `deleted` boolean for CAS operations, but not normal deletions
 	// to maintain backwards-compatibility with existing callers.
 	if args.Op == structs.ConfigEntryDeleteCAS {
@@ -131,6 +148,54 @@ func (s *HTTPHandlers) configDelete(resp http.ResponseWriter, req *http.Request)
 	return struct{}{}, nil
 }
 
+// configPatch applies a JSON merge patch to the given config entry.
+func (s *HTTPHandlers) configPatch(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args structs.ConfigEntryPatchRequest
+	s.parseDC(req, &args.Datacenter)
+	s.parseToken(req, &args.Token)
+
+	kindAndName := strings.TrimPrefix(req.URL.Path, "/v1/config/")
+	pathArgs := strings.SplitN(kindAndName, "/", 2)
+	if len(pathArgs) != 2 {
+		return nil, HTTPError{StatusCode: http.StatusNotFound, Reason: "Must provide both a kind and name to patch"}
+	}
+	args.Kind = pathArgs[0]
+	args.Name = pathArgs[1]
+
+	if err := s.parseEntMetaForConfigEntryKind(args.Kind, req, &args.EnterpriseMeta); err != nil {
+		return nil, err
+	}
+
+	patch, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Request decoding failed: %v", err)}
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(patch, &obj); err != nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "Request body must be a JSON merge patch object"}
+	}
+	args.Patch = patch
+
+	// Check for cas value
+	if casStr := req.URL.Query().Get("cas"); casStr != "" {
+		casVal, err := strconv.ParseUint(casStr, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		args.CASIndex = casVal
+	}
+
+	var reply bool
+	if err := s.agent.RPC(req.Context(), "ConfigEntry.Patch", &args, &reply); err != nil {
+		if structs.IsErrConfigEntryNotFound(err) {
+			return nil, HTTPError{StatusCode: http.StatusNotFound, Reason: fmt.Sprintf("%s for %q / %q", ConfigEntryNotFoundErr, args.Kind, args.Name)}
+		}
+		return nil, err
+	}
+
+	return reply, nil
+}
+
 // ConfigApply applies the given config entry update.
 func (s *HTTPHandlers) ConfigApply(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	args := structs.ConfigEntryRequest{
@@ -149,6 +214,9 @@ func (s *HTTPHandlers) ConfigApply(resp http.ResponseWriter, req *http.Request)
 	} else {
 		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Request decoding failed: %v", err)}
 	}
+	// Record which fields were given so that an explicit zero value is not
+	// replaced by config-entry-defaults.
+	args.SetFields = structs.ConfigEntryFields(args.Entry, raw)
 
 	// Parse enterprise meta.
 	var meta acl.EnterpriseMeta
@@ -167,14 +235,43 @@ func (s *HTTPHandlers) ConfigApply(resp http.ResponseWriter, req *http.Request)
 		args.Entry.GetRaftIndex().ModifyIndex = casVal
 	}
 
+	if _, ok := req.URL.Query()["dry-run"]; ok {
+		if args.Entry.GetKind() != structs.ServiceIntentions {
+			return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("dry-run is only supported for %s config entries", structs.ServiceIntentions)}
+		}
+		var diff structs.IntentionSourcesDiff
+		if err := s.agent.RPC(req.Context(), "ConfigEntry.DryRunIntentions", &args, &diff); err != nil {
+			return nil, err
+		}
+		return diff, nil
+	}
+
 	var reply bool
 	if err := s.agent.RPC(req.Context(), "ConfigEntry.Apply", &args, &reply); err != nil {
+		if structs.IsErrConfigEntryQuotaExceeded(err) {
+			return nil, HTTPError{StatusCode: http.StatusTooManyRequests, Reason: err.Error()}
+		}
 		return nil, err
 	}
 
 	return reply, nil
 }
 
+// ConfigEntryReplicationStatus returns the status of the replication of config
+// entries from the primary datacenter.
+func (s *HTTPHandlers) ConfigEntryReplicationStatus(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	args := structs.DCSpecificRequest{}
+	if done := s.parse(resp, req, &args.Datacenter, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	var out structs.ConfigEntryReplicationStatus
+	if err := s.agent.RPC(req.Context(), "ConfigEntry.ReplicationStatus", &args, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (s *HTTPHandlers) parseEntMetaForConfigEntryKind(kind string, req *http.Request, entMeta *acl.EnterpriseMeta) error {
 	if kind == structs.ServiceIntentions {
 		return s.parseEntMeta(req, entMeta)
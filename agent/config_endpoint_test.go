@@ -6,6 +6,7 @@ package agent
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -83,6 +84,7 @@ func TestConfig_Get(t *testing.T) {
 		require.Equal(t, structs.ServiceDefaults, value.GetKind())
 		entry := value.(*structs.ServiceConfigEntry)
 		require.Equal(t, entry.Name, "foo")
+		require.Equal(t, "dc1", resp.Header().Get("X-Consul-Primary-Datacenter"))
 	})
 	t.Run("list both service entries", func(t *testing.T) {
 		req, _ := http.NewRequest("GET", "/v1/config/service-defaults", nil)
@@ -282,6 +284,168 @@ func TestConfig_Delete_CAS(t *testing.T) {
 	})
 }
 
+func TestConfig_Delete_Return(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+	t.Parallel()
+
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	for _, name := range []string{"foo", "bar"} {
+		var created bool
+		require.NoError(t, a.RPC(context.Background(), "ConfigEntry.Apply", &structs.ConfigEntryRequest{
+			Datacenter: "dc1",
+			Entry: &structs.ServiceConfigEntry{
+				Kind:     structs.ServiceDefaults,
+				Name:     name,
+				Protocol: "http",
+			},
+		}, &created))
+		require.True(t, created)
+	}
+
+	// Read one back to get its ModifyIndex.
+	var out structs.ConfigEntryResponse
+	require.NoError(t, a.RPC(context.Background(), "ConfigEntry.Get", &structs.ConfigEntryQuery{
+		Datacenter: "dc1",
+		Kind:       structs.ServiceDefaults,
+		Name:       "bar",
+	}, &out))
+	require.NotNil(t, out.Entry)
+	modifyIndex := out.Entry.GetRaftIndex().ModifyIndex
+
+	t.Run("delete", func(t *testing.T) {
+		req := httptest.NewRequest("DELETE", "/v1/config/service-defaults/foo?return", nil)
+		rawRsp, err := a.srv.Config(httptest.NewRecorder(), req)
+		require.NoError(t, err)
+
+		entry, ok := rawRsp.(*structs.ServiceConfigEntry)
+		require.True(t, ok, "response should be the deleted entry")
+		require.Equal(t, "foo", entry.Name)
+		require.Equal(t, "http", entry.Protocol)
+	})
+
+	t.Run("delete missing entry", func(t *testing.T) {
+		req := httptest.NewRequest("DELETE", "/v1/config/service-defaults/foo?return", nil)
+		rawRsp, err := a.srv.Config(httptest.NewRecorder(), req)
+		require.NoError(t, err)
+		require.Nil(t, rawRsp)
+	})
+
+	t.Run("cas with an invalid index", func(t *testing.T) {
+		req := httptest.NewRequest(
+			"DELETE",
+			fmt.Sprintf("/v1/config/service-defaults/bar?cas=%d&return", modifyIndex-1),
+			nil,
+		)
+		rawRsp, err := a.srv.Config(httptest.NewRecorder(), req)
+		require.NoError(t, err)
+		require.Nil(t, rawRsp)
+	})
+
+	t.Run("cas with a valid index", func(t *testing.T) {
+		req := httptest.NewRequest(
+			"DELETE",
+			fmt.Sprintf("/v1/config/service-defaults/bar?cas=%d&return", modifyIndex),
+			nil,
+		)
+		rawRsp, err := a.srv.Config(httptest.NewRecorder(), req)
+		require.NoError(t, err)
+
+		entry, ok := rawRsp.(*structs.ServiceConfigEntry)
+		require.True(t, ok, "response should be the deleted entry")
+		require.Equal(t, "bar", entry.Name)
+		require.Equal(t, modifyIndex, entry.ModifyIndex)
+	})
+}
+
+func TestConfig_Patch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+	t.Parallel()
+
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	var created bool
+	require.NoError(t, a.RPC(context.Background(), "ConfigEntry.Apply", &structs.ConfigEntryRequest{
+		Datacenter: "dc1",
+		Entry: &structs.ServiceConfigEntry{
+			Kind:        structs.ServiceDefaults,
+			Name:        "foo",
+			Protocol:    "tcp",
+			ExternalSNI: "foo.example.com",
+			Meta:        map[string]string{"team": "web"},
+		},
+	}, &created))
+	require.True(t, created)
+
+	var out structs.ConfigEntryResponse
+	require.NoError(t, a.RPC(context.Background(), "ConfigEntry.Get", &structs.ConfigEntryQuery{
+		Datacenter: "dc1",
+		Kind:       structs.ServiceDefaults,
+		Name:       "foo",
+	}, &out))
+	require.NotNil(t, out.Entry)
+	modifyIndex := out.Entry.GetRaftIndex().ModifyIndex
+
+	t.Run("patch the protocol", func(t *testing.T) {
+		body := bytes.NewBufferString(`{"Protocol": "http"}`)
+		req := httptest.NewRequest("PATCH", fmt.Sprintf("/v1/config/service-defaults/foo?cas=%d", modifyIndex), body)
+		rawRsp, err := a.srv.Config(httptest.NewRecorder(), req)
+		require.NoError(t, err)
+		require.Equal(t, true, rawRsp)
+
+		var out structs.ConfigEntryResponse
+		require.NoError(t, a.RPC(context.Background(), "ConfigEntry.Get", &structs.ConfigEntryQuery{
+			Datacenter: "dc1",
+			Kind:       structs.ServiceDefaults,
+			Name:       "foo",
+		}, &out))
+		entry := out.Entry.(*structs.ServiceConfigEntry)
+		require.Equal(t, "http", entry.Protocol)
+		require.Equal(t, "foo.example.com", entry.ExternalSNI)
+		require.Equal(t, map[string]string{"team": "web"}, entry.Meta)
+	})
+
+	t.Run("stale cas", func(t *testing.T) {
+		body := bytes.NewBufferString(`{"Protocol": "grpc"}`)
+		req := httptest.NewRequest("PATCH", fmt.Sprintf("/v1/config/service-defaults/foo?cas=%d", modifyIndex), body)
+		rawRsp, err := a.srv.Config(httptest.NewRecorder(), req)
+		require.NoError(t, err)
+		require.Equal(t, false, rawRsp)
+
+		var out structs.ConfigEntryResponse
+		require.NoError(t, a.RPC(context.Background(), "ConfigEntry.Get", &structs.ConfigEntryQuery{
+			Datacenter: "dc1",
+			Kind:       structs.ServiceDefaults,
+			Name:       "foo",
+		}, &out))
+		require.Equal(t, "http", out.Entry.(*structs.ServiceConfigEntry).Protocol)
+	})
+
+	t.Run("not an object", func(t *testing.T) {
+		body := bytes.NewBufferString(`["Protocol"]`)
+		req := httptest.NewRequest("PATCH", "/v1/config/service-defaults/foo", body)
+		_, err := a.srv.Config(httptest.NewRecorder(), req)
+		require.Error(t, err)
+		require.Equal(t, http.StatusBadRequest, err.(HTTPError).StatusCode)
+	})
+
+	t.Run("missing entry", func(t *testing.T) {
+		body := bytes.NewBufferString(`{"Protocol": "http"}`)
+		req := httptest.NewRequest("PATCH", "/v1/config/service-defaults/bar", body)
+		_, err := a.srv.Config(httptest.NewRecorder(), req)
+		require.Error(t, err)
+		require.Equal(t, http.StatusNotFound, err.(HTTPError).StatusCode)
+	})
+}
+
 func TestConfig_Apply(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -324,6 +488,99 @@ func TestConfig_Apply(t *testing.T) {
 	}
 }
 
+func TestConfig_Apply_DryRunIntentions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	apply := func(url, sources string) *httptest.ResponseRecorder {
+		body := bytes.NewBuffer([]byte(fmt.Sprintf(`
+		{
+			"Kind": "service-intentions",
+			"Name": "db",
+			"Sources": %s
+		}`, sources)))
+		req, _ := http.NewRequest("PUT", url, body)
+		resp := httptest.NewRecorder()
+		a.srv.h.ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := apply("/v1/config", `[{"Name": "web", "Action": "allow"}, {"Name": "api", "Action": "allow"}]`)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	resp = apply("/v1/config?dry-run", `[{"Name": "web", "Action": "deny"}, {"Name": "billing", "Action": "allow"}]`)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var diff structs.IntentionSourcesDiff
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&diff))
+	require.Len(t, diff.Added, 1)
+	require.Equal(t, "billing", diff.Added[0].Name)
+	require.Len(t, diff.Removed, 1)
+	require.Equal(t, "api", diff.Removed[0].Name)
+	require.Len(t, diff.Changed, 1)
+	require.Equal(t, "web", diff.Changed[0].Name)
+
+	// The stored entry is untouched.
+	args := structs.ConfigEntryQuery{
+		Kind:       structs.ServiceIntentions,
+		Name:       "db",
+		Datacenter: "dc1",
+	}
+	var out structs.ConfigEntryResponse
+	require.NoError(t, a.RPC(context.Background(), "ConfigEntry.Get", &args, &out))
+	entry := out.Entry.(*structs.ServiceIntentionsConfigEntry)
+	require.Len(t, entry.Sources, 2)
+	require.Equal(t, structs.IntentionActionAllow, entry.Sources[0].Action)
+}
+
+func TestConfig_Apply_QuotaExceeded(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := NewTestAgent(t, `
+		config_entries {
+			max_per_kind_per_partition = {
+				"service-defaults" = 1
+			}
+		}
+	`)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	apply := func(name string) (*httptest.ResponseRecorder, error) {
+		body := bytes.NewBuffer([]byte(fmt.Sprintf(`
+		{
+			"Kind": "service-defaults",
+			"Name": %q
+		}`, name)))
+		req, _ := http.NewRequest("PUT", "/v1/config", body)
+		resp := httptest.NewRecorder()
+		_, err := a.srv.ConfigApply(resp, req)
+		return resp, err
+	}
+
+	resp, err := apply("foo")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	_, err = apply("bar")
+	require.Error(t, err)
+	httpErr, ok := err.(HTTPError)
+	require.True(t, ok, "expected HTTPError, got %T", err)
+	require.Equal(t, http.StatusTooManyRequests, httpErr.StatusCode)
+	require.Contains(t, httpErr.Reason, "Config entry quota exceeded")
+}
+
 func TestConfig_Apply_TerminatingGateway(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
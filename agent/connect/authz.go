@@ -4,6 +4,8 @@
 package connect
 
 import (
+	"time"
+
 	"github.com/dhiaayachi/consul/acl"
 	"github.com/dhiaayachi/consul/agent/structs"
 )
@@ -14,6 +16,7 @@ import (
 //
 // The return value of `auth` is only valid if the second value `match` is true.
 // If `match` is false, then the intention doesn't match this target and any result should be ignored.
+// An intention outside of its NotBefore/NotAfter window never matches.
 func AuthorizeIntentionTarget(
 	target, targetNS, targetAP, targetPeer string,
 	ixn *structs.Intention,
@@ -22,7 +25,8 @@ func AuthorizeIntentionTarget(
 
 	match := IntentionMatch(target, targetNS, targetAP, targetPeer, ixn, matchType)
 
-	if match {
+	// Intentions outside of their scheduled window are treated as absent.
+	if match && ixn.ActiveAt(time.Now()) {
 		return ixn.Action == structs.IntentionActionAllow, true
 	} else {
 		return false, false
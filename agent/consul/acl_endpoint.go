@@ -510,6 +510,7 @@ func (a *ACL) TokenClone(args *structs.ACLTokenSetRequest, reply *structs.ACLTok
 		Local:             token.Local,
 		Description:       token.Description,
 		ExpirationTime:    token.ExpirationTime,
+		WriteRateLimit:    token.WriteRateLimit,
 		EnterpriseMeta:    args.ACLToken.EnterpriseMeta,
 	}
 
@@ -655,6 +656,50 @@ func (a *ACL) TokenDelete(args *structs.ACLTokenDeleteRequest, reply *string) er
 	return nil
 }
 
+// PurgeExpiredTokens deletes all expired tokens right away instead of
+// waiting for the leader to reap them, and reports how many were deleted.
+// Local tokens are purged in the datacenter of the request and global tokens
+// only in the primary datacenter. It requires both acl:write and
+// operator:write, as granted by the global-management policy.
+func (a *ACL) PurgeExpiredTokens(args *structs.ACLTokenPurgeExpiredRequest, reply *structs.ACLTokenPurgeExpiredResponse) error {
+	if err := a.aclPreCheck(); err != nil {
+		return err
+	}
+
+	if done, err := a.srv.ForwardRPC("ACL.PurgeExpiredTokens", args, reply); done {
+		return err
+	}
+
+	defer metrics.MeasureSince([]string{"acl", "token", "purge_expired"}, time.Now())
+
+	authz, err := a.srv.ResolveToken(args.Token)
+	if err != nil {
+		return err
+	}
+	if err := authz.ToAllowAuthorizer().ACLWriteAllowed(nil); err != nil {
+		return err
+	}
+	if err := authz.ToAllowAuthorizer().OperatorWriteAllowed(nil); err != nil {
+		return err
+	}
+
+	if a.srv.LocalTokensEnabled() {
+		n, err := a.srv.purgeExpiredACLTokens(true)
+		reply.Local = n
+		if err != nil {
+			return err
+		}
+	}
+	if a.srv.InPrimaryDatacenter() {
+		n, err := a.srv.purgeExpiredACLTokens(false)
+		reply.Global = n
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (a *ACL) TokenList(args *structs.ACLTokenListRequest, reply *structs.ACLTokenListResponse) error {
 	if err := a.aclPreCheck(); err != nil {
 		return err
@@ -1280,6 +1325,19 @@ func (a *ACL) RoleSet(args *structs.ACLRoleSetRequest, reply *structs.ACLRole) e
 	role := &args.Role
 	state := a.srv.fsm.State()
 
+	if args.Template != "" {
+		if role.ID != "" {
+			return fmt.Errorf("Invalid Role: role templates can only be used to create a role")
+		}
+		tmpl := a.srv.config.ACLRoleTemplate(args.Template)
+		if tmpl == nil {
+			return fmt.Errorf("Invalid Role: role template %q does not exist", args.Template)
+		}
+		if err := tmpl.Instantiate(role, args.TemplateVariables); err != nil {
+			return fmt.Errorf("Invalid Role: %v", err)
+		}
+	}
+
 	// Almost all of the checks here are also done in the state store. However,
 	// we want to prevent the raft operations when we know they are going to fail
 	// so we still do them here.
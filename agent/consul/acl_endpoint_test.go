@@ -1857,6 +1857,79 @@ func TestACLEndpoint_TokenDelete_anon(t *testing.T) {
 	require.NotNil(t, tokenResp.Token)
 }
 
+func TestACLEndpoint_PurgeExpiredTokens(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, s1, codec := testACLServerWithConfig(t, func(c *Config) {
+		c.ACLTokenMinExpirationTTL = 10 * time.Millisecond
+		c.ACLTokenMaxExpirationTTL = 5 * time.Second
+	}, false)
+	waitForLeaderEstablishment(t, s1)
+
+	// Stop the background reaper so that only the purge deletes tokens.
+	s1.stopACLTokenReaping()
+
+	var expiring []*structs.ACLToken
+	for _, local := range []bool{false, false, true} {
+		token, err := upsertTestToken(codec, TestDefaultInitialManagementToken, "dc1", func(token *structs.ACLToken) {
+			token.ExpirationTTL = 1 * time.Second
+			token.Local = local
+		})
+		require.NoError(t, err)
+		expiring = append(expiring, token)
+	}
+	unexpired, err := upsertTestToken(codec, TestDefaultInitialManagementToken, "dc1", nil)
+	require.NoError(t, err)
+
+	// The expiration index has a granularity of one second.
+	time.Sleep(time.Until(*expiring[2].ExpirationTime) + time.Second)
+
+	count, err := s1.fsm.State().ACLTokenCountExpired(false, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	t.Run("requires global management", func(t *testing.T) {
+		token, err := upsertTestTokenWithPolicyRules(codec, TestDefaultInitialManagementToken, "dc1", `acl = "write"`)
+		require.NoError(t, err)
+
+		req := structs.ACLTokenPurgeExpiredRequest{
+			Datacenter:   "dc1",
+			WriteRequest: structs.WriteRequest{Token: token.SecretID},
+		}
+		var resp structs.ACLTokenPurgeExpiredResponse
+		err = msgpackrpc.CallWithCodec(codec, "ACL.PurgeExpiredTokens", &req, &resp)
+		require.True(t, acl.IsErrPermissionDenied(err), "unexpected error: %v", err)
+	})
+
+	t.Run("purges expired tokens", func(t *testing.T) {
+		req := structs.ACLTokenPurgeExpiredRequest{
+			Datacenter:   "dc1",
+			WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+		}
+		var resp structs.ACLTokenPurgeExpiredResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.PurgeExpiredTokens", &req, &resp))
+		require.Equal(t, structs.ACLTokenPurgeExpiredResponse{Local: 1, Global: 2}, resp)
+
+		for _, token := range expiring {
+			_, got, err := s1.fsm.State().ACLTokenGetByAccessor(nil, token.AccessorID, nil)
+			require.NoError(t, err)
+			require.Nil(t, got)
+		}
+		_, got, err := s1.fsm.State().ACLTokenGetByAccessor(nil, unexpired.AccessorID, nil)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+
+		// Nothing is left to purge.
+		resp = structs.ACLTokenPurgeExpiredResponse{}
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.PurgeExpiredTokens", &req, &resp))
+		require.Equal(t, structs.ACLTokenPurgeExpiredResponse{}, resp)
+	})
+}
+
 func TestACLEndpoint_TokenList(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -8,11 +8,20 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/armon/go-metrics"
+	"github.com/armon/go-metrics/prometheus"
 	"golang.org/x/time/rate"
 
 	"github.com/dhiaayachi/consul/agent/structs"
 )
 
+var ACLTokenReapingGauges = []prometheus.GaugeDefinition{
+	{
+		Name: []string{"acl", "token", "expired_pending"},
+		Help: "Tracks the number of expired ACL tokens that have not been reaped yet, labeled by locality.",
+	},
+}
+
 func (s *Server) reapExpiredTokens(ctx context.Context) error {
 	limiter := rate.NewLimiter(aclTokenReapingRateLimit, aclTokenReapingBurst)
 	for {
@@ -24,11 +33,54 @@ func (s *Server) reapExpiredTokens(ctx context.Context) error {
 			if _, err := s.reapExpiredLocalACLTokens(); err != nil {
 				s.logger.Error("error reaping expired local ACL tokens", "error", err)
 			}
+			s.emitExpiredACLTokensGauge(true)
 		}
 		if s.InPrimaryDatacenter() {
 			if _, err := s.reapExpiredGlobalACLTokens(); err != nil {
 				s.logger.Error("error reaping expired global ACL tokens", "error", err)
 			}
+			s.emitExpiredACLTokensGauge(false)
+		}
+	}
+}
+
+// emitExpiredACLTokensGauge reports how many expired tokens of the given
+// locality are still waiting to be reaped. Tokens are reaped in batches, so
+// this is only non-zero when there is a backlog.
+func (s *Server) emitExpiredACLTokensGauge(local bool) {
+	if !s.config.ACLsEnabled {
+		return
+	}
+	count, err := s.fsm.State().ACLTokenCountExpired(local, time.Now())
+	if err != nil {
+		s.logger.Error("error counting expired ACL tokens", "error", err)
+		return
+	}
+	metrics.SetGaugeWithLabels([]string{"acl", "token", "expired_pending"}, float32(count),
+		[]metrics.Label{{Name: "locality", Value: localityName(local)}})
+}
+
+// purgeExpiredACLTokens reaps expired tokens of the given locality until none
+// are left and returns how many were deleted.
+func (s *Server) purgeExpiredACLTokens(local bool) (int, error) {
+	var total int
+	for {
+		var (
+			n   int
+			err error
+		)
+		if local {
+			n, err = s.reapExpiredLocalACLTokens()
+		} else {
+			n, err = s.reapExpiredGlobalACLTokens()
+		}
+		total += n
+		if err != nil {
+			return total, err
+		}
+		if n < aclBatchDeleteSize {
+			s.emitExpiredACLTokensGauge(local)
+			return total, nil
 		}
 	}
 }
@@ -49,6 +101,13 @@ func (s *Server) startACLTokenReaping(ctx context.Context) {
 
 func (s *Server) stopACLTokenReaping() {
 	s.leaderRoutineManager.Stop(aclTokenReapingRoutineName)
+
+	// Only the leader reaps tokens, so clear the gauges rather than leaving a
+	// stale backlog reported by a server that is no longer reaping.
+	for _, local := range []bool{true, false} {
+		metrics.SetGaugeWithLabels([]string{"acl", "token", "expired_pending"}, 0,
+			[]metrics.Label{{Name: "locality", Value: localityName(local)}})
+	}
 }
 
 func (s *Server) reapExpiredGlobalACLTokens() (int, error) {
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/armon/go-metrics"
 	"github.com/stretchr/testify/require"
 
 	"github.com/dhiaayachi/consul/acl"
@@ -226,3 +227,41 @@ func testACLTokenReap_Primary(t *testing.T, local, global bool) {
 		})
 	})
 }
+
+func TestACLTokenReap_StopResetsExpiredGauge(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	// This test cannot be run in parallel as it replaces the global metrics sink.
+	sink := metrics.NewInmemSink(1*time.Minute, 1*time.Minute)
+	cfg := metrics.DefaultConfig("consul")
+	cfg.EnableHostname = false
+	metrics.NewGlobal(cfg, sink)
+	t.Cleanup(func() {
+		metrics.NewGlobal(cfg, &metrics.BlackholeSink{})
+	})
+
+	_, s1 := testServerWithConfig(t, func(c *Config) {
+		c.PrimaryDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLInitialManagementToken = "root"
+	})
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	for _, locality := range []string{"local", "global"} {
+		metrics.SetGaugeWithLabels([]string{"acl", "token", "expired_pending"}, 5,
+			[]metrics.Label{{Name: "locality", Value: locality}})
+	}
+
+	s1.stopACLTokenReaping()
+
+	intervals := sink.Data()
+	require.NotEmpty(t, intervals)
+	gauges := intervals[len(intervals)-1].Gauges
+	for _, locality := range []string{"local", "global"} {
+		gauge, ok := gauges["consul.acl.token.expired_pending;locality="+locality]
+		require.True(t, ok, locality)
+		require.Equal(t, float32(0), gauge.Value, locality)
+	}
+}
@@ -95,39 +95,27 @@ func (b *Binder) Bind(authMethod *structs.ACLAuthMethod, verifiedIdentity *authm
 	// Compute role, service identity, node identity or templated policy names by interpolating
 	// the identity's projected variables into the rule BindName templates.
 	for _, rule := range matchingRules {
+		bindName, templatedPolicy, err := computeRuleBinding(rule, verifiedIdentity.ProjectedVars)
+		if err != nil {
+			return nil, err
+		}
+
 		switch rule.BindType {
 		case structs.BindingRuleBindTypeService:
-			bindName, err := computeBindName(rule.BindName, verifiedIdentity.ProjectedVars, acl.IsValidServiceIdentityName)
-			if err != nil {
-				return nil, err
-			}
 			bindings.ServiceIdentities = append(bindings.ServiceIdentities, &structs.ACLServiceIdentity{
 				ServiceName: bindName,
 			})
 
 		case structs.BindingRuleBindTypeNode:
-			bindName, err := computeBindName(rule.BindName, verifiedIdentity.ProjectedVars, acl.IsValidNodeIdentityName)
-			if err != nil {
-				return nil, err
-			}
 			bindings.NodeIdentities = append(bindings.NodeIdentities, &structs.ACLNodeIdentity{
 				NodeName:   bindName,
 				Datacenter: b.datacenter,
 			})
 
 		case structs.BindingRuleBindTypeTemplatedPolicy:
-			templatedPolicy, err := generateTemplatedPolicies(rule.BindName, rule.BindVars, verifiedIdentity.ProjectedVars)
-			if err != nil {
-				return nil, err
-			}
 			bindings.TemplatedPolicies = append(bindings.TemplatedPolicies, templatedPolicy)
 
 		case structs.BindingRuleBindTypePolicy:
-			bindName, err := computeBindName(rule.BindName, verifiedIdentity.ProjectedVars, acl.IsValidRoleName)
-			if err != nil {
-				return nil, err
-			}
-
 			_, policy, err := b.store.ACLPolicyGetByName(nil, bindName, &bindings.EnterpriseMeta)
 			if err != nil {
 				return nil, err
@@ -141,11 +129,6 @@ func (b *Binder) Bind(authMethod *structs.ACLAuthMethod, verifiedIdentity *authm
 			}
 
 		case structs.BindingRuleBindTypeRole:
-			bindName, err := computeBindName(rule.BindName, verifiedIdentity.ProjectedVars, acl.IsValidRoleName)
-			if err != nil {
-				return nil, err
-			}
-
 			_, role, err := b.store.ACLRoleGetByName(nil, bindName, &bindings.EnterpriseMeta)
 			if err != nil {
 				return nil, err
@@ -162,6 +145,69 @@ func (b *Binder) Bind(authMethod *structs.ACLAuthMethod, verifiedIdentity *authm
 	return &bindings, nil
 }
 
+// computeRuleBinding interpolates the given projected variables into the rule's
+// BindName, and BindVars for templated policies, and validates the result. It
+// returns the computed bind name, and the templated policy for rules of type
+// templated-policy. Rules of an unknown type produce nothing.
+func computeRuleBinding(rule *structs.ACLBindingRule, projectedVars map[string]string) (string, *structs.ACLTemplatedPolicy, error) {
+	switch rule.BindType {
+	case structs.BindingRuleBindTypeService:
+		bindName, err := computeBindName(rule.BindName, projectedVars, acl.IsValidServiceIdentityName)
+		return bindName, nil, err
+
+	case structs.BindingRuleBindTypeNode:
+		bindName, err := computeBindName(rule.BindName, projectedVars, acl.IsValidNodeIdentityName)
+		return bindName, nil, err
+
+	case structs.BindingRuleBindTypeTemplatedPolicy:
+		templatedPolicy, err := generateTemplatedPolicies(rule.BindName, rule.BindVars, projectedVars)
+		if err != nil {
+			return "", nil, err
+		}
+		return rule.BindName, templatedPolicy, nil
+
+	case structs.BindingRuleBindTypePolicy, structs.BindingRuleBindTypeRole:
+		bindName, err := computeBindName(rule.BindName, projectedVars, acl.IsValidRoleName)
+		return bindName, nil, err
+	}
+	return "", nil, nil
+}
+
+// BindingRulePreview describes what a binding rule produces for an identity.
+type BindingRulePreview struct {
+	// Matched is whether the rule's selector matches the identity. The other
+	// fields are only set if it does.
+	Matched bool
+
+	// BindName is the bind name computed for the identity. For policy and role
+	// rules, the token is only linked to the policy or role with this name if
+	// it exists when logging in.
+	BindName string
+
+	// TemplatedPolicy is the templated policy computed for the identity if the
+	// rule is of type templated-policy.
+	TemplatedPolicy *structs.ACLTemplatedPolicy
+}
+
+// PreviewBindingRule evaluates a binding rule against an identity the same
+// way Bind does, without looking up the policies or roles that it names. This
+// lets a rule be checked against a sample identity before it is saved.
+func PreviewBindingRule(rule *structs.ACLBindingRule, selectableFields interface{}, projectedVars map[string]string) (*BindingRulePreview, error) {
+	if !doesSelectorMatch(rule.Selector, selectableFields) {
+		return &BindingRulePreview{}, nil
+	}
+
+	bindName, templatedPolicy, err := computeRuleBinding(rule, projectedVars)
+	if err != nil {
+		return nil, err
+	}
+	return &BindingRulePreview{
+		Matched:         true,
+		BindName:        bindName,
+		TemplatedPolicy: templatedPolicy,
+	}, nil
+}
+
 // IsValidBindingRule returns whether the given BindName and/or BindVars template produces valid
 // results when interpolating the auth method's available variables.
 func IsValidBindingRule(bindType, bindName string, bindVars *structs.ACLTemplatedPolicyVariables, availableVariables []string) error {
@@ -353,6 +353,56 @@ func TestBinder_NodeIdentities_NameValidation(t *testing.T) {
 	require.Contains(t, err.Error(), "invalid bind name")
 }
 
+func TestPreviewBindingRule(t *testing.T) {
+	selectableFields := map[string]map[string]string{
+		"serviceaccount": {"namespace": "default", "name": "web"},
+	}
+	projectedVars := map[string]string{
+		"serviceaccount.namespace": "default",
+		"serviceaccount.name":      "web",
+	}
+
+	t.Run("no match", func(t *testing.T) {
+		preview, err := PreviewBindingRule(&structs.ACLBindingRule{
+			Selector: "serviceaccount.namespace==prod",
+			BindType: structs.BindingRuleBindTypeService,
+			BindName: "${serviceaccount.name}",
+		}, selectableFields, projectedVars)
+		require.NoError(t, err)
+		require.Equal(t, &BindingRulePreview{}, preview)
+	})
+
+	t.Run("service", func(t *testing.T) {
+		preview, err := PreviewBindingRule(&structs.ACLBindingRule{
+			Selector: "serviceaccount.namespace==default",
+			BindType: structs.BindingRuleBindTypeService,
+			BindName: "k8s-${serviceaccount.name}",
+		}, selectableFields, projectedVars)
+		require.NoError(t, err)
+		require.Equal(t, &BindingRulePreview{Matched: true, BindName: "k8s-web"}, preview)
+	})
+
+	t.Run("templated policy", func(t *testing.T) {
+		preview, err := PreviewBindingRule(&structs.ACLBindingRule{
+			BindType: structs.BindingRuleBindTypeTemplatedPolicy,
+			BindName: api.ACLTemplatedPolicyServiceName,
+			BindVars: &structs.ACLTemplatedPolicyVariables{Name: "${serviceaccount.name}"},
+		}, selectableFields, projectedVars)
+		require.NoError(t, err)
+		require.True(t, preview.Matched)
+		require.Equal(t, api.ACLTemplatedPolicyServiceName, preview.TemplatedPolicy.TemplateName)
+		require.Equal(t, "web", preview.TemplatedPolicy.TemplateVariables.Name)
+	})
+
+	t.Run("invalid bind name", func(t *testing.T) {
+		_, err := PreviewBindingRule(&structs.ACLBindingRule{
+			BindType: structs.BindingRuleBindTypeService,
+			BindName: "${serviceaccount.name}!",
+		}, selectableFields, projectedVars)
+		require.ErrorContains(t, err, `invalid bind name: "web!"`)
+	})
+}
+
 func Test_IsValidBindingRule(t *testing.T) {
 	type testcase struct {
 		name     string
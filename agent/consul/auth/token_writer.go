@@ -315,6 +315,10 @@ func (w *TokenWriter) write(token, existing *structs.ACLToken, fromLogin bool) (
 	}
 	token.TemplatedPolicies = templatedPolicies
 
+	if token.WriteRateLimit < 0 {
+		return nil, errors.New("WriteRateLimit must not be negative")
+	}
+
 	if err := w.enterpriseValidation(token, existing); err != nil {
 		return nil, err
 	}
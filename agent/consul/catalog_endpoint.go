@@ -1149,3 +1149,47 @@ func (c *Catalog) VirtualIPForService(args *structs.ServiceSpecificRequest, repl
 	*reply, err = state.VirtualIPForService(psn)
 	return err
 }
+
+// ServiceByVirtualIP returns the service that owns the given virtual IP. This
+// is the reverse of VirtualIPForService and is meant for debugging mesh traffic
+// addressed to a virtual IP.
+func (c *Catalog) ServiceByVirtualIP(args *structs.VirtualIPRequest, reply *structs.ServiceByVirtualIPResponse) error {
+	if done, err := c.srv.ForwardRPC("Catalog.ServiceByVirtualIP", args, reply); done {
+		return err
+	}
+
+	if args.VirtualIP == "" {
+		return fmt.Errorf("Must provide a virtual IP")
+	}
+
+	authz, err := c.srv.ResolveTokenAndDefaultMeta(args.Token, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	return c.srv.blockingQuery(
+		&args.QueryOptions,
+		&reply.QueryMeta,
+		func(ws memdb.WatchSet, state *state.Store) error {
+			index, psn, err := state.ServiceByVirtualIP(ws, args.VirtualIP)
+			if err != nil {
+				return err
+			}
+			reply.Index, reply.Service = index, nil
+
+			if psn == nil {
+				return nil
+			}
+
+			authzContext := acl.AuthorizerContext{
+				Peer: psn.Peer,
+			}
+			psn.ServiceName.EnterpriseMeta.FillAuthzContext(&authzContext)
+			if err := authz.ToAllowAuthorizer().ServiceReadAllowed(psn.ServiceName.Name, &authzContext); err != nil {
+				return err
+			}
+
+			reply.Service = psn
+			return nil
+		})
+}
@@ -4529,6 +4529,71 @@ func TestCatalog_VirtualIPForService(t *testing.T) {
 	require.Equal(t, "240.0.0.1", out)
 }
 
+func TestCatalog_ServiceByVirtualIP(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.PrimaryDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLInitialManagementToken = "root"
+		c.ACLResolverSettings.ACLDefaultPolicy = "deny"
+		c.Build = "1.11.0"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	err := s1.fsm.State().EnsureRegistration(1, &structs.RegisterRequest{
+		Node:    "foo",
+		Address: "127.0.0.1",
+		Service: &structs.NodeService{
+			Service: "api",
+			Connect: structs.ServiceConnect{
+				Native: true,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	// Call the endpoint with no token and expect permission denied.
+	args := structs.VirtualIPRequest{
+		Datacenter: "dc1",
+		VirtualIP:  "240.0.0.1",
+	}
+	var out structs.ServiceByVirtualIPResponse
+	err = msgpackrpc.CallWithCodec(codec, "Catalog.ServiceByVirtualIP", &args, &out)
+	require.Contains(t, err.Error(), acl.ErrPermissionDenied.Error())
+	require.Nil(t, out.Service)
+
+	id := createToken(t, codec, `
+	service "api" {
+		policy = "read"
+	}`)
+
+	// Now try with the token and it will go through.
+	args.Token = id
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Catalog.ServiceByVirtualIP", &args, &out))
+	require.NotNil(t, out.Service)
+	require.Equal(t, "api", out.Service.ServiceName.Name)
+	require.Equal(t, "", out.Service.Peer)
+
+	// An address no service owns is not found.
+	args.VirtualIP = "240.0.0.2"
+	var out2 structs.ServiceByVirtualIPResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Catalog.ServiceByVirtualIP", &args, &out2))
+	require.Nil(t, out2.Service)
+
+	args.VirtualIP = "nope"
+	err = msgpackrpc.CallWithCodec(codec, "Catalog.ServiceByVirtualIP", &args, &out2)
+	require.ErrorContains(t, err, `invalid virtual IP "nope"`)
+}
+
 func TestCatalog_VirtualIPForService_ACLDeny(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
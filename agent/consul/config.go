@@ -229,6 +229,10 @@ type Config struct {
 	// by default in Consul 1.0 and later.
 	ACLEnableKeyListPolicy bool
 
+	// ACLRoleTemplates are the role templates that new roles can be created
+	// from. They are only used by servers in the primary datacenter.
+	ACLRoleTemplates []*structs.ACLRoleTemplate
+
 	AutoConfigEnabled              bool
 	AutoConfigIntroToken           string
 	AutoConfigIntroTokenFile       string
@@ -266,6 +270,20 @@ type Config struct {
 	// Minimum Session TTL
 	SessionTTLMin time.Duration
 
+	// SessionCheckExemptTypes lists the health check types, in addition to
+	// "session", that don't prevent a session from being created while they
+	// are critical.
+	SessionCheckExemptTypes []string
+
+	// SessionMaxTTL is the longest TTL that a session can be created with.
+	// Zero means there is no limit beyond structs.SessionTTLMax.
+	SessionMaxTTL time.Duration
+
+	// VirtualIPCIDR is the range service virtual IPs are allocated from. The
+	// leader replicates it to all servers, falling back to 240.0.0.0/4 when
+	// unset.
+	VirtualIPCIDR *net.IPNet
+
 	// maxTokenExpirationDuration is the maximum difference allowed between
 	// ACLToken CreateTime and ExpirationTime values if ExpirationTime is set
 	// on a token.
@@ -414,6 +432,10 @@ type Config struct {
 	// datacenters should exclusively traverse mesh gateways.
 	ConnectMeshGatewayWANFederationEnabled bool
 
+	// ConnectIntentionAuditLog enables logging of every intention decision
+	// made by the Intention.Check endpoint.
+	ConnectIntentionAuditLog bool
+
 	// DefaultIntentionPolicy is used to define a default intention action for all
 	// sources and destinations. Possible values are "allow", "deny", or "" (blank).
 	// For compatibility, falls back to ACLResolverSettings.ACLDefaultPolicy (which
@@ -438,6 +460,26 @@ type Config struct {
 	// If entries of the same Kind/Name exist already these will not update them.
 	ConfigEntryBootstrap []structs.ConfigEntry
 
+	// ConfigEntryMaxPerPartition is the maximum number of config entries
+	// that may exist in a single admin partition. Writes that would create
+	// a new entry beyond this limit are rejected. Zero means unlimited.
+	ConfigEntryMaxPerPartition int
+
+	// ConfigEntryMaxPerKindPerPartition optionally limits the number of
+	// config entries of a given kind that may exist in a single admin
+	// partition. Kinds that are absent or set to zero are unlimited.
+	ConfigEntryMaxPerKindPerPartition map[string]int
+
+	// ConfigEntryMaxIntentionPermissions is the maximum number of L7
+	// permissions a single source may have in a service-intentions config
+	// entry. Writes exceeding it are rejected. Zero means unlimited.
+	ConfigEntryMaxIntentionPermissions int
+
+	// ConfigEntryStrictFailoverDatacenters rejects service-resolver writes
+	// whose failover references a datacenter that is not known to this
+	// server. When false, such references are only logged as warnings.
+	ConfigEntryStrictFailoverDatacenters bool
+
 	// AutoEncryptAllowTLS is whether to enable the server responding to
 	// AutoEncrypt.Sign requests.
 	AutoEncryptAllowTLS bool
@@ -476,6 +518,17 @@ func (c *Config) InPrimaryDatacenter() bool {
 	return c.PrimaryDatacenter == "" || c.Datacenter == c.PrimaryDatacenter
 }
 
+// ACLRoleTemplate returns the role template with the given name, or nil if
+// there isn't one.
+func (c *Config) ACLRoleTemplate(name string) *structs.ACLRoleTemplate {
+	for _, tmpl := range c.ACLRoleTemplates {
+		if tmpl.Name == name {
+			return tmpl
+		}
+	}
+	return nil
+}
+
 // CheckProtocolVersion validates the protocol version.
 func (c *Config) CheckProtocolVersion() error {
 	if c.ProtocolVersion < ProtocolVersionMin {
@@ -6,6 +6,8 @@ package consul
 import (
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 	"time"
 
 	metrics "github.com/armon/go-metrics"
@@ -17,10 +19,15 @@ import (
 
 	"github.com/dhiaayachi/consul/acl"
 	"github.com/dhiaayachi/consul/agent/configentry"
+	"github.com/dhiaayachi/consul/agent/consul/discoverychain"
 	"github.com/dhiaayachi/consul/agent/consul/state"
 	"github.com/dhiaayachi/consul/agent/structs"
 )
 
+// configEntryPatchMaxAttempts is how many times a patch without a CAS index
+// is retried when another write changes the entry before it is applied.
+const configEntryPatchMaxAttempts = 5
+
 // The ConfigEntry endpoint is used to query centralized config information
 type ConfigEntry struct {
 	srv    *Server
@@ -57,6 +64,11 @@ func (c *ConfigEntry) Apply(args *structs.ConfigEntryRequest, reply *bool) error
 		return err
 	}
 
+	// Newly created entries inherit the defaults configured for their kind.
+	if err := c.applyDefaults(args.Entry, args.SetFields); err != nil {
+		return err
+	}
+
 	// Normalize and validate the incoming config entry as if it came from a user.
 	// Ensure Normalize is called before Validate for accurate validation
 	if err := args.Entry.Normalize(); err != nil {
@@ -78,17 +90,50 @@ func (c *ConfigEntry) Apply(args *structs.ConfigEntryRequest, reply *bool) error
 		return err
 	}
 
+	if resolver, ok := args.Entry.(*structs.ServiceResolverConfigEntry); ok {
+		if err := c.checkFailoverDatacenters(resolver); err != nil {
+			return err
+		}
+	}
+
+	if intentions, ok := args.Entry.(*structs.ServiceIntentionsConfigEntry); ok {
+		if err := c.checkIntentionPermissions(intentions); err != nil {
+			return err
+		}
+	}
+
 	if args.Op != structs.ConfigEntryUpsert && args.Op != structs.ConfigEntryUpsertCAS {
 		args.Op = structs.ConfigEntryUpsert
 	}
 
-	if skip, err := c.shouldSkipOperation(args); err != nil {
+	if c.quotaEnabled(args.Entry.GetKind()) {
+		// The quota is checked against the current state before the write is
+		// committed through Raft, so new entries are created one at a time to
+		// keep concurrent writes from all passing the check. Entries written
+		// by replication or bootstrapping do not go through this path and
+		// are not subject to the quota.
+		c.srv.configEntryQuotaLock.Lock()
+		defer c.srv.configEntryQuotaLock.Unlock()
+	}
+
+	currentEntry, err := c.currentEntry(args.Entry)
+	if err != nil {
+		return err
+	}
+
+	if skip, err := c.shouldSkipOperation(args, currentEntry); err != nil {
 		return err
 	} else if skip {
 		*reply = true
 		return nil
 	}
 
+	if currentEntry == nil {
+		if err := c.checkQuota(args.Entry); err != nil {
+			return err
+		}
+	}
+
 	resp, err := c.srv.raftApply(structs.ConfigEntryRequestType, args)
 	if err != nil {
 		return err
@@ -100,19 +145,217 @@ func (c *ConfigEntry) Apply(args *structs.ConfigEntryRequest, reply *bool) error
 	return nil
 }
 
+// Patch applies a JSON merge patch to an existing config entry. The patched
+// entry is written with a check-and-set against the version the patch was
+// applied to, so a concurrent edit is never overwritten; the patch fails
+// instead.
+func (c *ConfigEntry) Patch(args *structs.ConfigEntryPatchRequest, reply *bool) error {
+	if err := c.srv.validateEnterpriseRequest(&args.EnterpriseMeta, true); err != nil {
+		return err
+	}
+
+	err := gateWriteToSecondary(args.Datacenter, c.srv.config.Datacenter, c.srv.config.PrimaryDatacenter, args.Kind)
+	if err != nil {
+		return err
+	}
+
+	// Like Apply, all config entry writes go to the primary datacenter.
+	args.Datacenter = c.srv.config.PrimaryDatacenter
+
+	if done, err := c.srv.ForwardRPC("ConfigEntry.Patch", args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"config_entry", "patch"}, time.Now())
+
+	authz, err := c.srv.ResolveTokenAndDefaultMeta(args.Token, &args.EnterpriseMeta, nil)
+	if err != nil {
+		return err
+	}
+
+	// Check write access before revealing whether the entry exists.
+	stub, err := structs.MakeConfigEntry(args.Kind, args.Name)
+	if err != nil {
+		return err
+	}
+	stub.GetEnterpriseMeta().Merge(&args.EnterpriseMeta)
+	if err := stub.CanWrite(authz); err != nil {
+		return err
+	}
+
+	// Without a CAS index the caller only wants the patch applied, so a write
+	// that raced with ours is handled by patching the new entry again.
+	for attempt := 1; ; attempt++ {
+		currentEntry, err := c.currentEntry(stub)
+		if err != nil {
+			return err
+		}
+		if currentEntry == nil {
+			return fmt.Errorf("%w: %s %q", structs.ErrConfigEntryNotFound, args.Kind, args.Name)
+		}
+
+		modifyIndex := currentEntry.GetRaftIndex().ModifyIndex
+		if args.CASIndex != 0 && args.CASIndex != modifyIndex {
+			*reply = false
+			return nil
+		}
+
+		patched, err := structs.MergePatchConfigEntry(currentEntry, args.Patch)
+		if err != nil {
+			return err
+		}
+		patched.GetRaftIndex().ModifyIndex = modifyIndex
+
+		// The patched entry goes through the same checks as any other write.
+		err = c.Apply(&structs.ConfigEntryRequest{
+			Op:           structs.ConfigEntryUpsertCAS,
+			Datacenter:   args.Datacenter,
+			Entry:        patched,
+			WriteRequest: args.WriteRequest,
+		}, reply)
+		if err != nil || *reply || args.CASIndex != 0 || attempt == configEntryPatchMaxAttempts {
+			return err
+		}
+	}
+}
+
+// checkFailoverDatacenters flags failover in the resolver that references a
+// datacenter this server does not know about. Unknown datacenters are logged
+// as warnings, or reject the write when strict validation is enabled.
+func (c *ConfigEntry) checkFailoverDatacenters(resolver *structs.ServiceResolverConfigEntry) error {
+	known, err := c.knownDatacenters()
+	if err != nil {
+		return err
+	}
+
+	unknown := unknownFailoverDatacenters(resolver, known)
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	if c.srv.config.ConfigEntryStrictFailoverDatacenters {
+		return fmt.Errorf("service-resolver %q has failover to unknown datacenters: %s",
+			resolver.Name, strings.Join(unknown, ", "))
+	}
+	c.logger.Warn("service-resolver has failover to unknown datacenters",
+		"name", resolver.Name,
+		"datacenters", unknown,
+	)
+	return nil
+}
+
+// checkIntentionPermissions returns an error if any source of the given
+// service-intentions entry has more permissions than the configured limit.
+func (c *ConfigEntry) checkIntentionPermissions(entry *structs.ServiceIntentionsConfigEntry) error {
+	max := c.srv.config.ConfigEntryMaxIntentionPermissions
+	if max <= 0 {
+		return nil
+	}
+	for i, src := range entry.Sources {
+		if len(src.Permissions) > max {
+			return fmt.Errorf("Sources[%d].Permissions has %d permissions, which exceeds the limit of %d per source",
+				i, len(src.Permissions), max)
+		}
+	}
+	return nil
+}
+
+// knownDatacenters returns the datacenters known to this server, either
+// through the WAN pool or through federation states.
+func (c *ConfigEntry) knownDatacenters() (map[string]struct{}, error) {
+	known := map[string]struct{}{
+		c.srv.config.Datacenter: {},
+	}
+	for _, dc := range c.srv.router.GetDatacenters() {
+		known[dc] = struct{}{}
+	}
+
+	_, fedStates, err := c.srv.fsm.State().FederationStateList(nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, fedState := range fedStates {
+		known[fedState.Datacenter] = struct{}{}
+	}
+	return known, nil
+}
+
+// unknownFailoverDatacenters returns the sorted datacenters referenced by the
+// resolver's failover that are not in the known set.
+func unknownFailoverDatacenters(resolver *structs.ServiceResolverConfigEntry, known map[string]struct{}) []string {
+	unknown := make(map[string]struct{})
+	check := func(dc string) {
+		if dc == "" {
+			return
+		}
+		if _, ok := known[dc]; !ok {
+			unknown[dc] = struct{}{}
+		}
+	}
+
+	for _, failover := range resolver.Failover {
+		for _, dc := range failover.Datacenters {
+			check(dc)
+		}
+		for _, target := range failover.Targets {
+			check(target.Datacenter)
+		}
+	}
+
+	if len(unknown) == 0 {
+		return nil
+	}
+	result := make([]string, 0, len(unknown))
+	for dc := range unknown {
+		result = append(result, dc)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// applyDefaults fills in the unset fields of an entry that does not exist yet
+// from the config-entry-defaults entry for its kind, if there is one. Fields
+// named in setFields were given explicitly and are kept.
+func (c *ConfigEntry) applyDefaults(entry structs.ConfigEntry, setFields []string) error {
+	if entry.GetKind() == structs.ConfigEntryDefaults {
+		return nil
+	}
+
+	currentEntry, err := c.currentEntry(entry)
+	if err != nil {
+		return err
+	}
+	if currentEntry != nil {
+		return nil
+	}
+
+	_, raw, err := c.srv.fsm.State().ConfigEntry(nil, structs.ConfigEntryDefaults, entry.GetKind(), entry.GetEnterpriseMeta())
+	if err != nil {
+		return fmt.Errorf("error reading config entry defaults: %w", err)
+	}
+	defaults, ok := raw.(*structs.ConfigEntryDefaultsConfigEntry)
+	if !ok {
+		return nil
+	}
+	return defaults.ApplyTo(entry, setFields)
+}
+
+// currentEntry returns the stored config entry with the same kind, name and
+// enterprise meta as the given entry, or nil if there is none.
+func (c *ConfigEntry) currentEntry(entry structs.ConfigEntry) (structs.ConfigEntry, error) {
+	_, currentEntry, err := c.srv.fsm.State().ConfigEntry(nil, entry.GetKind(), entry.GetName(), entry.GetEnterpriseMeta())
+	if err != nil {
+		return nil, fmt.Errorf("error reading current config entry value: %w", err)
+	}
+	return currentEntry, nil
+}
+
 // shouldSkipOperation returns true if the result of the operation has
 // already happened and is safe to skip.
 //
 // It is ok if this incorrectly detects something as changed when it
 // in fact has not, the important thing is that it doesn't do
 // the reverse and incorrectly detect a change as a no-op.
-func (c *ConfigEntry) shouldSkipOperation(args *structs.ConfigEntryRequest) (bool, error) {
-	state := c.srv.fsm.State()
-	_, currentEntry, err := state.ConfigEntry(nil, args.Entry.GetKind(), args.Entry.GetName(), args.Entry.GetEnterpriseMeta())
-	if err != nil {
-		return false, fmt.Errorf("error reading current config entry value: %w", err)
-	}
-
+func (c *ConfigEntry) shouldSkipOperation(args *structs.ConfigEntryRequest, currentEntry structs.ConfigEntry) (bool, error) {
 	switch args.Op {
 	case structs.ConfigEntryUpsert, structs.ConfigEntryUpsertCAS:
 		return c.shouldSkipUpsertOperation(currentEntry, args.Entry)
@@ -123,6 +366,48 @@ func (c *ConfigEntry) shouldSkipOperation(args *structs.ConfigEntryRequest) (boo
 	}
 }
 
+// quotaEnabled returns true if any per-partition limit applies to config
+// entries of the given kind.
+func (c *ConfigEntry) quotaEnabled(kind string) bool {
+	return c.srv.config.ConfigEntryMaxPerPartition > 0 ||
+		c.srv.config.ConfigEntryMaxPerKindPerPartition[kind] > 0
+}
+
+// checkQuota returns an error if creating the given entry would exceed the
+// configured per-partition limits. It must only be called for entries that
+// do not exist yet.
+func (c *ConfigEntry) checkQuota(entry structs.ConfigEntry) error {
+	var (
+		state     = c.srv.fsm.State()
+		entMeta   = entry.GetEnterpriseMeta().WithWildcardNamespace()
+		partition = entry.GetEnterpriseMeta().PartitionOrDefault()
+	)
+
+	if max := c.srv.config.ConfigEntryMaxPerPartition; max > 0 {
+		count, err := state.ConfigEntryCount("", entMeta)
+		if err != nil {
+			return fmt.Errorf("error counting config entries: %w", err)
+		}
+		if count >= max {
+			return fmt.Errorf("%w: partition %q already has %d config entries (limit %d)",
+				structs.ErrConfigEntryQuotaExceeded, partition, count, max)
+		}
+	}
+
+	if max := c.srv.config.ConfigEntryMaxPerKindPerPartition[entry.GetKind()]; max > 0 {
+		count, err := state.ConfigEntryCount(entry.GetKind(), entMeta)
+		if err != nil {
+			return fmt.Errorf("error counting config entries: %w", err)
+		}
+		if count >= max {
+			return fmt.Errorf("%w: partition %q already has %d %s config entries (limit %d)",
+				structs.ErrConfigEntryQuotaExceeded, partition, count, entry.GetKind(), max)
+		}
+	}
+
+	return nil
+}
+
 func (c *ConfigEntry) shouldSkipUpsertOperation(currentEntry, updatedEntry structs.ConfigEntry) (bool, error) {
 	if currentEntry == nil {
 		return false, nil
@@ -156,7 +441,154 @@ func (c *ConfigEntry) shouldSkipUpsertOperation(currentEntry, updatedEntry struc
 	return same, nil
 }
 
+// ShadowApply compiles the discovery chain that would result from applying
+// the given config entry, without writing anything. Compile and graph
+// validation errors are returned exactly as ConfigEntry.Apply would return
+// them.
+func (c *ConfigEntry) ShadowApply(args *structs.ConfigEntryRequest, reply *structs.DiscoveryChainResponse) error {
+	if err := c.srv.validateEnterpriseRequest(args.Entry.GetEnterpriseMeta(), false); err != nil {
+		return err
+	}
+
+	// Exit early if Connect hasn't been enabled.
+	if !c.srv.config.ConnectEnabled {
+		return ErrConnectNotEnabled
+	}
+
+	if done, err := c.srv.ForwardRPC("ConfigEntry.ShadowApply", args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"config_entry", "shadow_apply"}, time.Now())
+
+	switch args.Entry.GetKind() {
+	case structs.ServiceDefaults, structs.ServiceRouter, structs.ServiceSplitter, structs.ServiceResolver:
+	default:
+		return fmt.Errorf("config entry kind %q does not affect a discovery chain", args.Entry.GetKind())
+	}
+
+	entMeta := args.Entry.GetEnterpriseMeta()
+	var authzContext acl.AuthorizerContext
+	authz, err := c.srv.ResolveTokenAndDefaultMeta(args.Token, entMeta, &authzContext)
+	if err != nil {
+		return err
+	}
+
+	if err := c.applyDefaults(args.Entry, args.SetFields); err != nil {
+		return err
+	}
+	if err := args.Entry.Normalize(); err != nil {
+		return err
+	}
+	if err := args.Entry.Validate(); err != nil {
+		return err
+	}
+
+	if err := args.Entry.CanWrite(authz); err != nil {
+		return err
+	}
+	if err := authz.ToAllowAuthorizer().ServiceReadAllowed(args.Entry.GetName(), &authzContext); err != nil {
+		return err
+	}
+
+	req := discoverychain.CompileRequest{
+		ServiceName:          args.Entry.GetName(),
+		EvaluateInNamespace:  entMeta.NamespaceOrDefault(),
+		EvaluateInPartition:  entMeta.PartitionOrDefault(),
+		EvaluateInDatacenter: c.srv.config.Datacenter,
+	}
+	index, chain, err := c.srv.fsm.State().ShadowServiceDiscoveryChain(args.Entry.GetName(), entMeta, req, args.Entry)
+	if err != nil {
+		return err
+	}
+
+	reply.Index = index
+	reply.Chain = chain
+	return nil
+}
+
+// DryRunIntentions validates a service-intentions config entry the same way
+// ConfigEntry.Apply does and reports how its sources differ from the existing
+// entry, without writing anything.
+func (c *ConfigEntry) DryRunIntentions(args *structs.ConfigEntryRequest, reply *structs.IntentionSourcesDiff) error {
+	if err := c.srv.validateEnterpriseRequest(args.Entry.GetEnterpriseMeta(), false); err != nil {
+		return err
+	}
+
+	if done, err := c.srv.ForwardRPC("ConfigEntry.DryRunIntentions", args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"config_entry", "dry_run_intentions"}, time.Now())
+
+	entry, ok := args.Entry.(*structs.ServiceIntentionsConfigEntry)
+	if !ok {
+		return fmt.Errorf("dry-run is only supported for %s config entries, got %q", structs.ServiceIntentions, args.Entry.GetKind())
+	}
+
+	authz, err := c.srv.ResolveTokenAndDefaultMeta(args.Token, entry.GetEnterpriseMeta(), nil)
+	if err != nil {
+		return err
+	}
+
+	if err := c.applyDefaults(entry, args.SetFields); err != nil {
+		return err
+	}
+	if err := entry.Normalize(); err != nil {
+		return err
+	}
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+	if err := entry.CanWrite(authz); err != nil {
+		return err
+	}
+	if err := c.checkIntentionPermissions(entry); err != nil {
+		return err
+	}
+
+	currentEntry, err := c.currentEntry(entry)
+	if err != nil {
+		return err
+	}
+	var prev *structs.ServiceIntentionsConfigEntry
+	if currentEntry != nil {
+		prev, ok = currentEntry.(*structs.ServiceIntentionsConfigEntry)
+		if !ok {
+			return fmt.Errorf("existing config entry is not of type %T: %T", entry, currentEntry)
+		}
+	}
+
+	*reply = *entry.DiffSources(prev)
+	return nil
+}
+
 // Get returns a single config entry by Kind/Name.
+// ReplicationStatus returns the status of the replication of config entries
+// from the primary datacenter.
+func (c *ConfigEntry) ReplicationStatus(args *structs.DCSpecificRequest, reply *structs.ConfigEntryReplicationStatus) error {
+	// This must be sent to the leader, so we fix the args since we are
+	// re-using a structure where we don't support all the options.
+	args.RequireConsistent = true
+	args.AllowStale = false
+	if done, err := c.srv.ForwardRPC("ConfigEntry.ReplicationStatus", args, reply); done {
+		return err
+	}
+
+	// There's no ACL token required here since only the indexes of the
+	// replication are returned, like the ACL replication status.
+	primary := c.srv.config.PrimaryDatacenter
+	if primary == "" || primary == c.srv.config.Datacenter {
+		*reply = structs.ConfigEntryReplicationStatus{}
+		return nil
+	}
+	*reply = structs.ConfigEntryReplicationStatus{
+		Enabled:          true,
+		Running:          c.srv.leaderRoutineManager.IsRunning(configReplicationRoutineName),
+		SourceDatacenter: primary,
+		ReplicatedIndex:  c.srv.configReplicator.Index(),
+	}
+	return nil
+}
+
 func (c *ConfigEntry) Get(args *structs.ConfigEntryQuery, reply *structs.ConfigEntryResponse) error {
 	if err := c.srv.validateEnterpriseRequest(&args.EnterpriseMeta, false); err != nil {
 		return err
@@ -193,6 +625,7 @@ func (c *ConfigEntry) Get(args *structs.ConfigEntryQuery, reply *structs.ConfigE
 			}
 
 			reply.Index, reply.Entry = index, entry
+			reply.PrimaryDatacenter = c.srv.config.PrimaryDatacenter
 			if entry == nil {
 				return errNotFound
 			}
@@ -416,7 +849,12 @@ func (c *ConfigEntry) Delete(args *structs.ConfigEntryRequest, reply *structs.Co
 		args.Op = structs.ConfigEntryDelete
 	}
 
-	if skip, err := c.shouldSkipOperation(args); err != nil {
+	currentEntry, err := c.currentEntry(args.Entry)
+	if err != nil {
+		return err
+	}
+
+	if skip, err := c.shouldSkipOperation(args, currentEntry); err != nil {
 		return err
 	} else if skip {
 		reply.Deleted = true
@@ -433,9 +871,21 @@ func (c *ConfigEntry) Delete(args *structs.ConfigEntryRequest, reply *structs.Co
 		// operation was successful.
 		deleted, _ := rsp.(bool)
 		reply.Deleted = deleted
+
+		// A CAS deletion only succeeds if the entry still had the given
+		// index, so when that matches the index read above it is the exact
+		// version that was deleted.
+		if deleted && currentEntry != nil &&
+			currentEntry.GetRaftIndex().ModifyIndex == args.Entry.GetRaftIndex().ModifyIndex {
+			reply.Entry = &structs.ConfigEntryResponse{Entry: currentEntry}
+		}
 	} else {
-		// For non-CAS deletions any non-error result indicates a successful deletion.
+		// For non-CAS deletions any non-error result indicates a successful
+		// deletion, and the FSM returns the entry it deleted if there was one.
 		reply.Deleted = true
+		if deleted, ok := rsp.(structs.ConfigEntry); ok {
+			reply.Entry = &structs.ConfigEntryResponse{Entry: deleted}
+		}
 	}
 
 	return nil
@@ -4,9 +4,11 @@
 package consul
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"sort"
+	"sync"
 	"testing"
 	"time"
 
@@ -163,6 +165,311 @@ func TestConfigEntry_Apply(t *testing.T) {
 	})
 }
 
+func TestConfigEntry_Apply_Quota(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, s1 := testServerWithConfig(t, func(c *Config) {
+		c.ConfigEntryMaxPerPartition = 3
+		c.ConfigEntryMaxPerKindPerPartition = map[string]int{
+			structs.ServiceDefaults: 2,
+		}
+	})
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	apply := func(t *testing.T, entry structs.ConfigEntry) error {
+		args := structs.ConfigEntryRequest{
+			Datacenter: "dc1",
+			Entry:      entry,
+		}
+		var out bool
+		return msgpackrpc.CallWithCodec(codec, "ConfigEntry.Apply", &args, &out)
+	}
+
+	require.NoError(t, apply(t, &structs.ServiceConfigEntry{Name: "foo"}))
+	require.NoError(t, apply(t, &structs.ServiceConfigEntry{Name: "bar"}))
+
+	testutil.RunStep(t, "per-kind limit rejects new entries of that kind", func(t *testing.T) {
+		err := apply(t, &structs.ServiceConfigEntry{Name: "baz"})
+		require.Error(t, err)
+		require.True(t, structs.IsErrConfigEntryQuotaExceeded(err), err.Error())
+	})
+
+	testutil.RunStep(t, "updates to existing entries are allowed", func(t *testing.T) {
+		require.NoError(t, apply(t, &structs.ServiceConfigEntry{Name: "foo", Protocol: "http"}))
+	})
+
+	testutil.RunStep(t, "other kinds count towards the partition limit", func(t *testing.T) {
+		require.NoError(t, apply(t, &structs.ProxyConfigEntry{
+			Kind: structs.ProxyDefaults,
+			Name: structs.ProxyConfigGlobal,
+		}))
+
+		err := apply(t, &structs.ServiceResolverConfigEntry{
+			Kind: structs.ServiceResolver,
+			Name: "foo",
+		})
+		require.Error(t, err)
+		require.True(t, structs.IsErrConfigEntryQuotaExceeded(err), err.Error())
+	})
+
+	testutil.RunStep(t, "deleting an entry frees quota", func(t *testing.T) {
+		args := structs.ConfigEntryRequest{
+			Datacenter: "dc1",
+			Op:         structs.ConfigEntryDelete,
+			Entry:      &structs.ServiceConfigEntry{Name: "bar"},
+		}
+		var out structs.ConfigEntryDeleteResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConfigEntry.Delete", &args, &out))
+
+		require.NoError(t, apply(t, &structs.ServiceConfigEntry{Name: "baz"}))
+	})
+
+	_, entries, err := s1.fsm.State().ConfigEntries(nil, nil)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+}
+
+func TestConfigEntry_Apply_ConfigEntryDefaults(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, s1 := testServer(t)
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	applySet := func(t *testing.T, entry structs.ConfigEntry, setFields ...string) error {
+		args := structs.ConfigEntryRequest{
+			Datacenter: "dc1",
+			Entry:      entry,
+			SetFields:  setFields,
+		}
+		var out bool
+		return msgpackrpc.CallWithCodec(codec, "ConfigEntry.Apply", &args, &out)
+	}
+	apply := func(t *testing.T, entry structs.ConfigEntry) error {
+		return applySet(t, entry)
+	}
+	get := func(t *testing.T, name string) *structs.ServiceConfigEntry {
+		_, entry, err := s1.fsm.State().ConfigEntry(nil, structs.ServiceDefaults, name, nil)
+		require.NoError(t, err)
+		require.NotNil(t, entry)
+		return entry.(*structs.ServiceConfigEntry)
+	}
+
+	require.NoError(t, apply(t, &structs.ConfigEntryDefaultsConfigEntry{
+		Name: structs.ServiceDefaults,
+		Defaults: map[string]interface{}{
+			"Protocol": "http",
+			"MeshGateway": map[string]interface{}{
+				"Mode": "local",
+			},
+			"MaxInboundConnections": 100,
+		},
+	}))
+
+	testutil.RunStep(t, "new entries inherit unset fields", func(t *testing.T) {
+		require.NoError(t, apply(t, &structs.ServiceConfigEntry{Name: "web"}))
+
+		entry := get(t, "web")
+		require.Equal(t, "http", entry.Protocol)
+		require.Equal(t, structs.MeshGatewayModeLocal, entry.MeshGateway.Mode)
+		require.Equal(t, 100, entry.MaxInboundConnections)
+	})
+
+	testutil.RunStep(t, "explicit zero values win", func(t *testing.T) {
+		require.NoError(t, applySet(t, &structs.ServiceConfigEntry{Name: "db"}, "MaxInboundConnections"))
+
+		entry := get(t, "db")
+		require.Equal(t, "http", entry.Protocol)
+		require.Zero(t, entry.MaxInboundConnections)
+	})
+
+	testutil.RunStep(t, "explicit fields win", func(t *testing.T) {
+		require.NoError(t, apply(t, &structs.ServiceConfigEntry{Name: "api", Protocol: "grpc"}))
+
+		entry := get(t, "api")
+		require.Equal(t, "grpc", entry.Protocol)
+		require.Equal(t, structs.MeshGatewayModeLocal, entry.MeshGateway.Mode)
+	})
+
+	testutil.RunStep(t, "updates to existing entries do not inherit", func(t *testing.T) {
+		require.NoError(t, apply(t, &structs.ServiceConfigEntry{Name: "web", Protocol: "tcp"}))
+		require.NoError(t, apply(t, &structs.ServiceConfigEntry{Name: "web"}))
+
+		entry := get(t, "web")
+		require.Empty(t, entry.Protocol)
+		require.Equal(t, structs.MeshGatewayModeDefault, entry.MeshGateway.Mode)
+	})
+
+	testutil.RunStep(t, "defaults must be valid for the kind", func(t *testing.T) {
+		err := apply(t, &structs.ConfigEntryDefaultsConfigEntry{
+			Name:     structs.ServiceResolver,
+			Defaults: map[string]interface{}{"Protocol": "http"},
+		})
+		testutil.RequireErrorContains(t, err, "Defaults:")
+	})
+}
+
+func TestConfigEntry_Apply_FailoverDatacenters(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, s1 := testServerWithConfig(t, func(c *Config) {
+		c.ConfigEntryStrictFailoverDatacenters = true
+	})
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	// dc2 is only known through its federation state.
+	require.NoError(t, s1.fsm.State().FederationStateSet(100, &structs.FederationState{
+		Datacenter: "dc2",
+	}))
+
+	apply := func(t *testing.T, failover structs.ServiceResolverFailover) error {
+		args := structs.ConfigEntryRequest{
+			Datacenter: "dc1",
+			Entry: &structs.ServiceResolverConfigEntry{
+				Kind: structs.ServiceResolver,
+				Name: "web",
+				Failover: map[string]structs.ServiceResolverFailover{
+					"*": failover,
+				},
+			},
+		}
+		var out bool
+		return msgpackrpc.CallWithCodec(codec, "ConfigEntry.Apply", &args, &out)
+	}
+
+	testutil.RunStep(t, "known datacenter is accepted", func(t *testing.T) {
+		require.NoError(t, apply(t, structs.ServiceResolverFailover{
+			Targets: []structs.ServiceResolverFailoverTarget{{Datacenter: "dc2"}},
+		}))
+	})
+
+	testutil.RunStep(t, "unknown datacenter is rejected", func(t *testing.T) {
+		err := apply(t, structs.ServiceResolverFailover{
+			Targets: []structs.ServiceResolverFailoverTarget{
+				{Datacenter: "dc2"},
+				{Datacenter: "dc9"},
+			},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unknown datacenters: dc9")
+		require.NotContains(t, err.Error(), "dc2")
+	})
+}
+
+func TestConfigEntry_Apply_MaxIntentionPermissions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, s1 := testServerWithConfig(t, func(c *Config) {
+		c.ConfigEntryMaxIntentionPermissions = 2
+	})
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	// L7 permissions require an http-based protocol.
+	defaults := structs.ConfigEntryRequest{
+		Datacenter: "dc1",
+		Entry: &structs.ProxyConfigEntry{
+			Kind: structs.ProxyDefaults,
+			Name: structs.ProxyConfigGlobal,
+			Config: map[string]interface{}{
+				"protocol": "http",
+			},
+		},
+	}
+	var out bool
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConfigEntry.Apply", &defaults, &out))
+
+	apply := func(t *testing.T, n int) error {
+		var perms []*structs.IntentionPermission
+		for i := 0; i < n; i++ {
+			perms = append(perms, &structs.IntentionPermission{
+				Action: structs.IntentionActionAllow,
+				HTTP: &structs.IntentionHTTPPermission{
+					PathExact: fmt.Sprintf("/path/%d", i),
+				},
+			})
+		}
+		args := structs.ConfigEntryRequest{
+			Datacenter: "dc1",
+			Entry: &structs.ServiceIntentionsConfigEntry{
+				Kind: structs.ServiceIntentions,
+				Name: "api",
+				Sources: []*structs.SourceIntention{
+					{Name: "web", Permissions: perms},
+				},
+			},
+		}
+		var out bool
+		return msgpackrpc.CallWithCodec(codec, "ConfigEntry.Apply", &args, &out)
+	}
+
+	testutil.RunStep(t, "within limit is accepted", func(t *testing.T) {
+		require.NoError(t, apply(t, 2))
+	})
+
+	testutil.RunStep(t, "over limit is rejected", func(t *testing.T) {
+		err := apply(t, 3)
+		testutil.RequireErrorContains(t, err, "Sources[0].Permissions has 3 permissions, which exceeds the limit of 2 per source")
+
+		_, entry, err := s1.fsm.State().ConfigEntry(nil, structs.ServiceIntentions, "api", nil)
+		require.NoError(t, err)
+		require.Len(t, entry.(*structs.ServiceIntentionsConfigEntry).Sources[0].Permissions, 2)
+	})
+}
+
+func TestUnknownFailoverDatacenters(t *testing.T) {
+	known := map[string]struct{}{"dc1": {}, "dc2": {}}
+
+	resolver := &structs.ServiceResolverConfigEntry{
+		Kind: structs.ServiceResolver,
+		Name: "web",
+		Failover: map[string]structs.ServiceResolverFailover{
+			"*": {
+				Datacenters: []string{"dc2", "dc3"},
+			},
+			"v1": {
+				Targets: []structs.ServiceResolverFailoverTarget{
+					{Datacenter: "dc1"},
+					{Datacenter: "dc4"},
+					{Service: "api"},
+				},
+			},
+		},
+	}
+	require.Equal(t, []string{"dc3", "dc4"}, unknownFailoverDatacenters(resolver, known))
+
+	resolver.Failover = map[string]structs.ServiceResolverFailover{
+		"*": {Datacenters: []string{"dc1", "dc2"}},
+	}
+	require.Nil(t, unknownFailoverDatacenters(resolver, known))
+}
+
 func TestConfigEntry_ProxyDefaultsMeshGateway(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -274,6 +581,223 @@ operator = "write"
 	require.NoError(t, err)
 }
 
+func TestConfigEntry_ShadowApply(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	waitForLeaderEstablishment(t, s1)
+
+	state := s1.fsm.State()
+	require.NoError(t, state.EnsureConfigEntry(1, &structs.ServiceConfigEntry{
+		Kind:     structs.ServiceDefaults,
+		Name:     "web",
+		Protocol: "http",
+	}))
+	require.NoError(t, state.EnsureConfigEntry(2, &structs.ServiceConfigEntry{
+		Kind:     structs.ServiceDefaults,
+		Name:     "api",
+		Protocol: "http",
+	}))
+
+	testutil.RunStep(t, "router compiles into the returned chain", func(t *testing.T) {
+		args := structs.ConfigEntryRequest{
+			Datacenter: "dc1",
+			Entry: &structs.ServiceRouterConfigEntry{
+				Kind: structs.ServiceRouter,
+				Name: "web",
+				Routes: []structs.ServiceRoute{{
+					Match: &structs.ServiceRouteMatch{
+						HTTP: &structs.ServiceRouteHTTPMatch{PathPrefix: "/api"},
+					},
+					Destination: &structs.ServiceRouteDestination{Service: "api"},
+				}},
+			},
+		}
+		var out structs.DiscoveryChainResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConfigEntry.ShadowApply", &args, &out))
+
+		chain := out.Chain
+		require.NotNil(t, chain)
+		require.Equal(t, "web", chain.ServiceName)
+		require.Equal(t, "http", chain.Protocol)
+		require.Equal(t, "router:web.default.default", chain.StartNode)
+
+		router := chain.Nodes[chain.StartNode]
+		require.NotNil(t, router)
+		require.Len(t, router.Routes, 2)
+		require.Contains(t, chain.Targets, "api.default.default.dc1")
+
+		// Nothing should have been written.
+		_, entry, err := state.ConfigEntry(nil, structs.ServiceRouter, "web", nil)
+		require.NoError(t, err)
+		require.Nil(t, entry)
+	})
+
+	testutil.RunStep(t, "compile errors are returned", func(t *testing.T) {
+		args := structs.ConfigEntryRequest{
+			Datacenter: "dc1",
+			Entry: &structs.ServiceRouterConfigEntry{
+				Kind: structs.ServiceRouter,
+				Name: "db",
+				Routes: []structs.ServiceRoute{{
+					Match: &structs.ServiceRouteMatch{
+						HTTP: &structs.ServiceRouteHTTPMatch{PathPrefix: "/other"},
+					},
+					Destination: &structs.ServiceRouteDestination{Service: "api"},
+				}},
+			},
+		}
+		var out structs.DiscoveryChainResponse
+		err := msgpackrpc.CallWithCodec(codec, "ConfigEntry.ShadowApply", &args, &out)
+		testutil.RequireErrorContains(t, err, `discovery chain "db" uses inconsistent protocols`)
+
+		_, entry, err := state.ConfigEntry(nil, structs.ServiceRouter, "db", nil)
+		require.NoError(t, err)
+		require.Nil(t, entry)
+	})
+
+	testutil.RunStep(t, "kinds outside the discovery chain are rejected", func(t *testing.T) {
+		args := structs.ConfigEntryRequest{
+			Datacenter: "dc1",
+			Entry: &structs.ProxyConfigEntry{
+				Kind: structs.ProxyDefaults,
+				Name: structs.ProxyConfigGlobal,
+			},
+		}
+		var out structs.DiscoveryChainResponse
+		err := msgpackrpc.CallWithCodec(codec, "ConfigEntry.ShadowApply", &args, &out)
+		testutil.RequireErrorContains(t, err, `config entry kind "proxy-defaults" does not affect a discovery chain`)
+	})
+}
+
+func TestConfigEntry_DryRunIntentions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	waitForLeaderEstablishment(t, s1)
+
+	existing := &structs.ServiceIntentionsConfigEntry{
+		Kind: structs.ServiceIntentions,
+		Name: "db",
+		Sources: []*structs.SourceIntention{
+			{Name: "web", Action: structs.IntentionActionAllow},
+			{Name: "api", Action: structs.IntentionActionAllow},
+			{Name: "batch", Action: structs.IntentionActionDeny},
+		},
+	}
+	var applied bool
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConfigEntry.Apply", &structs.ConfigEntryRequest{
+		Datacenter: "dc1",
+		Entry:      existing,
+	}, &applied))
+	require.True(t, applied)
+
+	state := s1.fsm.State()
+	idx, before, err := state.ConfigEntry(nil, structs.ServiceIntentions, "db", nil)
+	require.NoError(t, err)
+
+	testutil.RunStep(t, "diff against the existing entry", func(t *testing.T) {
+		args := structs.ConfigEntryRequest{
+			Datacenter: "dc1",
+			Entry: &structs.ServiceIntentionsConfigEntry{
+				Kind: structs.ServiceIntentions,
+				Name: "db",
+				Sources: []*structs.SourceIntention{
+					{Name: "web", Action: structs.IntentionActionAllow},
+					{Name: "api", Action: structs.IntentionActionDeny},
+					{Name: "billing", Action: structs.IntentionActionAllow},
+				},
+			},
+		}
+		var out structs.IntentionSourcesDiff
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConfigEntry.DryRunIntentions", &args, &out))
+
+		names := func(srcs []*structs.SourceIntention) []string {
+			var out []string
+			for _, src := range srcs {
+				out = append(out, src.Name)
+			}
+			return out
+		}
+		require.Equal(t, []string{"billing"}, names(out.Added))
+		require.Equal(t, []string{"batch"}, names(out.Removed))
+		require.Equal(t, []string{"api"}, names(out.Changed))
+		require.Equal(t, structs.IntentionActionDeny, out.Changed[0].Action)
+
+		// Nothing should have been written.
+		gotIdx, after, err := state.ConfigEntry(nil, structs.ServiceIntentions, "db", nil)
+		require.NoError(t, err)
+		require.Equal(t, idx, gotIdx)
+		require.Equal(t, before, after)
+	})
+
+	testutil.RunStep(t, "new entries only add sources", func(t *testing.T) {
+		args := structs.ConfigEntryRequest{
+			Datacenter: "dc1",
+			Entry: &structs.ServiceIntentionsConfigEntry{
+				Kind: structs.ServiceIntentions,
+				Name: "cache",
+				Sources: []*structs.SourceIntention{
+					{Name: "web", Action: structs.IntentionActionAllow},
+				},
+			},
+		}
+		var out structs.IntentionSourcesDiff
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConfigEntry.DryRunIntentions", &args, &out))
+		require.Len(t, out.Added, 1)
+		require.Empty(t, out.Removed)
+		require.Empty(t, out.Changed)
+
+		_, entry, err := state.ConfigEntry(nil, structs.ServiceIntentions, "cache", nil)
+		require.NoError(t, err)
+		require.Nil(t, entry)
+	})
+
+	testutil.RunStep(t, "validation errors are returned", func(t *testing.T) {
+		args := structs.ConfigEntryRequest{
+			Datacenter: "dc1",
+			Entry: &structs.ServiceIntentionsConfigEntry{
+				Kind: structs.ServiceIntentions,
+				Name: "db",
+			},
+		}
+		var out structs.IntentionSourcesDiff
+		err := msgpackrpc.CallWithCodec(codec, "ConfigEntry.DryRunIntentions", &args, &out)
+		testutil.RequireErrorContains(t, err, "At least one source is required")
+	})
+
+	testutil.RunStep(t, "other kinds are rejected", func(t *testing.T) {
+		args := structs.ConfigEntryRequest{
+			Datacenter: "dc1",
+			Entry: &structs.ServiceConfigEntry{
+				Kind: structs.ServiceDefaults,
+				Name: "db",
+			},
+		}
+		var out structs.IntentionSourcesDiff
+		err := msgpackrpc.CallWithCodec(codec, "ConfigEntry.DryRunIntentions", &args, &out)
+		testutil.RequireErrorContains(t, err, `dry-run is only supported for service-intentions config entries, got "service-defaults"`)
+	})
+}
+
 func TestConfigEntry_Get(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -1019,6 +1543,8 @@ func TestConfigEntry_Delete(t *testing.T) {
 		var out structs.ConfigEntryDeleteResponse
 		require.NoError(t, msgpackrpc.CallWithCodec(codec2, "ConfigEntry.Delete", &args, &out))
 		require.True(t, out.Deleted)
+		require.NotNil(t, out.Entry)
+		require.Equal(t, "foo", out.Entry.Entry.GetName())
 	})
 
 	testutil.RunStep(t, "verify the entry was deleted in the primary and secondary", func(t *testing.T) {
@@ -1046,6 +1572,7 @@ func TestConfigEntry_Delete(t *testing.T) {
 		var out structs.ConfigEntryDeleteResponse
 		require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConfigEntry.Delete", &args, &out))
 		require.True(t, out.Deleted)
+		require.Nil(t, out.Entry)
 	})
 }
 
@@ -1087,6 +1614,7 @@ func TestConfigEntry_DeleteCAS(t *testing.T) {
 	var rsp structs.ConfigEntryDeleteResponse
 	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConfigEntry.Delete", &args, &rsp))
 	require.False(t, rsp.Deleted)
+	require.Nil(t, rsp.Entry)
 
 	// Verify the entry was not deleted.
 	_, existing, err = s.fsm.State().ConfigEntry(nil, structs.ServiceDefaults, "foo", nil)
@@ -1099,12 +1627,125 @@ func TestConfigEntry_DeleteCAS(t *testing.T) {
 	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConfigEntry.Delete", &args, &rsp))
 	require.True(t, rsp.Deleted)
 
+	// The deleted version should be returned.
+	require.NotNil(t, rsp.Entry)
+	require.Equal(t, existing, rsp.Entry.Entry)
+
 	// Verify the entry was deleted.
 	_, existing, err = s.fsm.State().ConfigEntry(nil, structs.ServiceDefaults, "foo", nil)
 	require.NoError(t, err)
 	require.Nil(t, existing)
 }
 
+func TestConfigEntry_Patch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+	t.Parallel()
+
+	dir, s := testServer(t)
+	defer os.RemoveAll(dir)
+	defer s.Shutdown()
+
+	codec := rpcClient(t, s)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s.RPC, "dc1")
+
+	entry := &structs.ServiceConfigEntry{
+		Kind:                  structs.ServiceDefaults,
+		Name:                  "foo",
+		Protocol:              "tcp",
+		ExternalSNI:           "foo.example.com",
+		MaxInboundConnections: 10,
+	}
+	var applied bool
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConfigEntry.Apply", &structs.ConfigEntryRequest{
+		Datacenter: "dc1",
+		Entry:      entry,
+	}, &applied))
+	require.True(t, applied)
+
+	state := s.fsm.State()
+	_, existing, err := state.ConfigEntry(nil, structs.ServiceDefaults, "foo", nil)
+	require.NoError(t, err)
+	staleIndex := existing.GetRaftIndex().ModifyIndex
+
+	args := structs.ConfigEntryPatchRequest{
+		Datacenter: "dc1",
+		Kind:       structs.ServiceDefaults,
+		Name:       "foo",
+		Patch:      []byte(`{"Protocol": "http", "ExternalSNI": null}`),
+		CASIndex:   staleIndex,
+	}
+	var out bool
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConfigEntry.Patch", &args, &out))
+	require.True(t, out)
+
+	_, existing, err = state.ConfigEntry(nil, structs.ServiceDefaults, "foo", nil)
+	require.NoError(t, err)
+	patched := existing.(*structs.ServiceConfigEntry)
+	require.Equal(t, "http", patched.Protocol)
+	require.Empty(t, patched.ExternalSNI)
+	require.Equal(t, 10, patched.MaxInboundConnections)
+
+	// The entry was modified since staleIndex, so the patch is rejected.
+	args.Patch = []byte(`{"Protocol": "grpc"}`)
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConfigEntry.Patch", &args, &out))
+	require.False(t, out)
+
+	_, existing, err = state.ConfigEntry(nil, structs.ServiceDefaults, "foo", nil)
+	require.NoError(t, err)
+	require.Equal(t, "http", existing.(*structs.ServiceConfigEntry).Protocol)
+
+	// The name cannot be changed.
+	args.CASIndex = 0
+	args.Patch = []byte(`{"Name": "bar"}`)
+	err = msgpackrpc.CallWithCodec(codec, "ConfigEntry.Patch", &args, &out)
+	testutil.RequireErrorContains(t, err, "cannot be changed")
+
+	// Missing entries cannot be patched.
+	args.Name = "bar"
+	args.Patch = []byte(`{"Protocol": "http"}`)
+	err = msgpackrpc.CallWithCodec(codec, "ConfigEntry.Patch", &args, &out)
+	require.True(t, structs.IsErrConfigEntryNotFound(err), "unexpected error: %v", err)
+
+	// Concurrent patches without a CAS index are retried when they race, so
+	// they are all applied. Each one can only lose to the others, which is
+	// fewer times than it is attempted.
+	t.Run("concurrent", func(t *testing.T) {
+		var wg sync.WaitGroup
+		errCh := make(chan error, configEntryPatchMaxAttempts)
+		for i := 0; i < configEntryPatchMaxAttempts; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				args := structs.ConfigEntryPatchRequest{
+					Datacenter: "dc1",
+					Kind:       structs.ServiceDefaults,
+					Name:       "foo",
+					Patch:      []byte(fmt.Sprintf(`{"Meta": {"key-%d": "value"}}`, i)),
+				}
+				var out bool
+				if err := s.RPC(context.Background(), "ConfigEntry.Patch", &args, &out); err != nil {
+					errCh <- err
+				} else if !out {
+					errCh <- fmt.Errorf("patch %d was not applied", i)
+				}
+			}(i)
+		}
+		wg.Wait()
+		close(errCh)
+		for err := range errCh {
+			require.NoError(t, err)
+		}
+
+		_, existing, err := state.ConfigEntry(nil, structs.ServiceDefaults, "foo", nil)
+		require.NoError(t, err)
+		require.Len(t, existing.GetMeta(), configEntryPatchMaxAttempts)
+	})
+}
+
 func TestConfigEntry_Delete_ACLDeny(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -287,6 +287,61 @@ func mutateIDs(e []structs.ConfigEntry, indexStart int) []structs.ConfigEntry {
 	return entries
 }
 
+func TestConfigEntry_ReplicationStatus(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	_, s1 := testServerWithConfig(t, func(c *Config) {
+		c.PrimaryDatacenter = "dc1"
+	})
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	_, s2 := testServerWithConfig(t, func(c *Config) {
+		c.Datacenter = "dc2"
+		c.PrimaryDatacenter = "dc1"
+		c.ConfigReplicationRate = 100
+		c.ConfigReplicationBurst = 100
+		c.ConfigReplicationApplyLimit = 1000000
+	})
+	testrpc.WaitForLeader(t, s2.RPC, "dc2")
+
+	joinWAN(t, s2, s1)
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+	testrpc.WaitForLeader(t, s1.RPC, "dc2")
+
+	arg := structs.ConfigEntryRequest{
+		Datacenter: "dc1",
+		Op:         structs.ConfigEntryUpsert,
+		Entry: &structs.ServiceConfigEntry{
+			Kind:     structs.ServiceDefaults,
+			Name:     "web",
+			Protocol: "http",
+		},
+	}
+	out := false
+	require.NoError(t, s1.RPC(context.Background(), "ConfigEntry.Apply", &arg, &out))
+	index, _, err := s1.fsm.State().ConfigEntry(nil, structs.ServiceDefaults, "web", nil)
+	require.NoError(t, err)
+
+	// The primary datacenter does not replicate.
+	var status structs.ConfigEntryReplicationStatus
+	require.NoError(t, s1.RPC(context.Background(), "ConfigEntry.ReplicationStatus",
+		&structs.DCSpecificRequest{Datacenter: "dc1"}, &status))
+	require.Equal(t, structs.ConfigEntryReplicationStatus{}, status)
+
+	retry.Run(t, func(r *retry.R) {
+		var status structs.ConfigEntryReplicationStatus
+		require.NoError(r, s1.RPC(context.Background(), "ConfigEntry.ReplicationStatus",
+			&structs.DCSpecificRequest{Datacenter: "dc2"}, &status))
+		require.True(r, status.Enabled)
+		require.True(r, status.Running)
+		require.Equal(r, "dc1", status.SourceDatacenter)
+		require.GreaterOrEqual(r, status.ReplicatedIndex, index)
+	})
+}
+
 func Test_diffConfigEntries(t *testing.T) {
 	type args struct {
 		local           []structs.ConfigEntry
@@ -34,6 +34,10 @@ var CommandsSummaries = []prometheus.SummaryDefinition{
 		Name: []string{"fsm", "session"},
 		Help: "Measures the time it takes to apply the given session operation to the FSM.",
 	},
+	{
+		Name: []string{"fsm", "session_renew_all"},
+		Help: "Measures the time it takes to apply a batch session renewal to the FSM.",
+	},
 	{
 		Name: []string{"fsm", "acl"},
 		Help: "Measures the time it takes to apply the given ACL operation to the FSM.",
@@ -148,6 +152,9 @@ func init() {
 	registerCommand(structs.PeeringSecretsWriteType, (*FSM).applyPeeringSecretsWrite)
 	registerCommand(structs.ResourceOperationType, (*FSM).applyResourceOperation)
 	registerCommand(structs.UpdateVirtualIPRequestType, (*FSM).applyManualVirtualIPs)
+	registerCommand(structs.ReclaimVirtualIPsRequestType, (*FSM).applyReclaimVirtualIPs)
+	registerCommand(structs.SessionRenewAllRequestType, (*FSM).applySessionRenewAll)
+	registerCommand(structs.SessionTransferRequestType, (*FSM).applySessionTransfer)
 }
 
 func (c *FSM) applyRegister(buf []byte, index uint64) interface{} {
@@ -225,7 +232,11 @@ func (c *FSM) applyKVSOperation(buf []byte, index uint64) interface{} {
 		}
 		return act
 	case api.KVDeleteTree:
-		return c.state.KVSDeleteTree(index, req.DirEnt.Key, &req.DirEnt.EnterpriseMeta)
+		deleted, err := c.state.KVSDeleteTree(index, req.DirEnt.Key, &req.DirEnt.EnterpriseMeta)
+		if err != nil {
+			return err
+		}
+		return deleted
 	case api.KVCAS:
 		act, err := c.state.KVSSetCAS(index, &req.DirEnt)
 		if err != nil {
@@ -244,6 +255,12 @@ func (c *FSM) applyKVSOperation(buf []byte, index uint64) interface{} {
 			return err
 		}
 		return act
+	case api.KVLockTransfer:
+		act, err := c.state.KVSLockTransfer(index, req.LockHolder, &req.DirEnt)
+		if err != nil {
+			return err
+		}
+		return act
 	default:
 		err := fmt.Errorf("Invalid KVS operation '%s'", req.Op)
 		c.logger.Warn("Invalid KVS operation", "operation", req.Op)
@@ -264,7 +281,7 @@ func (c *FSM) applySessionOperation(buf []byte, index uint64) interface{} {
 		[]metrics.Label{{Name: "op", Value: string(req.Op)}})
 	switch req.Op {
 	case structs.SessionCreate:
-		if err := c.state.SessionCreate(index, &req.Session); err != nil {
+		if err := c.state.SessionCreateWithCheckExemptions(index, &req.Session, req.ExemptCheckTypes); err != nil {
 			return err
 		}
 		return req.Session.ID
@@ -609,7 +626,14 @@ func (c *FSM) applyConfigEntryOperation(buf []byte, index uint64) interface{} {
 	case structs.ConfigEntryDelete:
 		defer metrics.MeasureSinceWithLabels([]string{"fsm", "config_entry", req.Entry.GetKind()}, time.Now(),
 			[]metrics.Label{{Name: "op", Value: "delete"}})
-		return c.state.DeleteConfigEntry(index, req.Entry.GetKind(), req.Entry.GetName(), req.Entry.GetEnterpriseMeta())
+		deleted, err := c.state.DeleteConfigEntryReturning(index, req.Entry.GetKind(), req.Entry.GetName(), req.Entry.GetEnterpriseMeta())
+		if err != nil {
+			return err
+		}
+		if deleted == nil {
+			return nil
+		}
+		return deleted
 	default:
 		return fmt.Errorf("invalid config entry operation type: %v", req.Op)
 	}
@@ -851,3 +875,38 @@ func (c *FSM) applyManualVirtualIPs(buf []byte, index uint64) interface{} {
 		UnassignedFrom: unassignedFrom,
 	}
 }
+
+func (c *FSM) applyReclaimVirtualIPs(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"fsm", "reclaim_virtual_ips"}, time.Now())
+	var req structs.ReclaimVirtualIPsRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	return c.state.ReclaimServiceVirtualIPs(index, req.Services)
+}
+
+func (c *FSM) applySessionRenewAll(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"fsm", "session_renew_all"}, time.Now())
+	var req structs.SessionRenewAllRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	renewed, err := c.state.SessionRenewAll(index, req.SessionIDs, &req.EnterpriseMeta)
+	if err != nil {
+		return err
+	}
+	return renewed
+}
+
+func (c *FSM) applySessionTransfer(buf []byte, index uint64) interface{} {
+	var req structs.SessionTransferRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+	defer metrics.MeasureSinceWithLabels([]string{"fsm", "session"}, time.Now(),
+		[]metrics.Label{{Name: "op", Value: "transfer"}})
+
+	return c.state.SessionTransfer(index, &req)
+}
@@ -451,7 +451,7 @@ func TestFSM_KVSDeleteTree(t *testing.T) {
 		t.Fatalf("err: %v", err)
 	}
 	resp = fsm.Apply(makeLog(buf))
-	if resp != nil {
+	if resp != 1 {
 		t.Fatalf("resp: %v", resp)
 	}
 
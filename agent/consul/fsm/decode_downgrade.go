@@ -611,6 +611,10 @@ func MakeShadowConfigEntry(kind, name string) (structs.ConfigEntry, error) {
 		return &ShadowServiceIntentionsConfigEntry{ServiceIntentionsConfigEntry: &structs.ServiceIntentionsConfigEntry{Name: name}}, nil
 	case structs.MeshConfig:
 		return &ShadowMeshConfigEntry{MeshConfigEntry: &structs.MeshConfigEntry{}}, nil
+	case structs.SessionDefaults:
+		return &ShadowSessionDefaultsConfigEntry{SessionDefaultsConfigEntry: &structs.SessionDefaultsConfigEntry{}}, nil
+	case structs.ConfigEntryDefaults:
+		return &ShadowConfigEntryDefaultsConfigEntry{ConfigEntryDefaultsConfigEntry: &structs.ConfigEntryDefaultsConfigEntry{Name: name}}, nil
 	case structs.ExportedServices:
 		return &ShadowExportedServicesConfigEntry{ExportedServicesConfigEntry: &structs.ExportedServicesConfigEntry{Name: name}}, nil
 	case structs.SamenessGroup:
@@ -862,6 +866,24 @@ func (s ShadowMeshConfigEntry) GetRealConfigEntry() structs.ConfigEntry {
 	return s.MeshConfigEntry
 }
 
+type ShadowSessionDefaultsConfigEntry struct {
+	ShadowBase
+	*structs.SessionDefaultsConfigEntry
+}
+
+func (s ShadowSessionDefaultsConfigEntry) GetRealConfigEntry() structs.ConfigEntry {
+	return s.SessionDefaultsConfigEntry
+}
+
+type ShadowConfigEntryDefaultsConfigEntry struct {
+	ShadowBase
+	*structs.ConfigEntryDefaultsConfigEntry
+}
+
+func (s ShadowConfigEntryDefaultsConfigEntry) GetRealConfigEntry() structs.ConfigEntry {
+	return s.ConfigEntryDefaultsConfigEntry
+}
+
 type ShadowExportedServicesConfigEntry struct {
 	ShadowBase
 	*structs.ExportedServicesConfigEntry
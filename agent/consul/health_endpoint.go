@@ -12,8 +12,10 @@ import (
 
 	"github.com/dhiaayachi/consul/acl"
 	"github.com/dhiaayachi/consul/agent/configentry"
+	"github.com/dhiaayachi/consul/agent/consul/discoverychain"
 	"github.com/dhiaayachi/consul/agent/consul/state"
 	"github.com/dhiaayachi/consul/agent/structs"
+	"github.com/dhiaayachi/consul/api"
 	"github.com/hashicorp/go-bexpr"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-memdb"
@@ -389,6 +391,149 @@ func (h *Health) ServiceNodes(args *structs.ServiceSpecificRequest, reply *struc
 	return err
 }
 
+// ServiceFailoverSummary returns the number of healthy and total instances of
+// a service along with the same counts for each target its discovery chain
+// fails over to, in failover order. Errors fetching a failover target, such as
+// an unreachable datacenter, are reported on that target rather than failing
+// the request.
+func (h *Health) ServiceFailoverSummary(args *structs.ServiceFailoverSummaryRequest, reply *structs.ServiceFailoverSummaryResponse) error {
+	if done, err := h.srv.ForwardRPC("Health.ServiceFailoverSummary", args, reply); done {
+		return err
+	}
+
+	if args.ServiceName == "" {
+		return fmt.Errorf("Must provide service name")
+	}
+
+	var authzContext acl.AuthorizerContext
+	authz, err := h.srv.ResolveTokenAndDefaultMeta(args.Token, &args.EnterpriseMeta, &authzContext)
+	if err != nil {
+		return err
+	}
+
+	if err := h.srv.validateEnterpriseRequest(&args.EnterpriseMeta, false); err != nil {
+		return err
+	}
+
+	if err := authz.ToAllowAuthorizer().ServiceReadAllowed(args.ServiceName, &authzContext); err != nil {
+		return err
+	}
+
+	index, chain, _, err := h.srv.fsm.State().ServiceDiscoveryChain(nil, args.ServiceName, &args.EnterpriseMeta, discoverychain.CompileRequest{
+		ServiceName:          args.ServiceName,
+		EvaluateInNamespace:  args.EnterpriseMeta.NamespaceOrDefault(),
+		EvaluateInPartition:  args.EnterpriseMeta.PartitionOrDefault(),
+		EvaluateInDatacenter: h.srv.config.Datacenter,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Routers and splitters can send traffic to several resolvers, so only the
+	// resolver for the service itself is summarized for those chains.
+	node := chain.Nodes[chain.StartNode]
+	if node == nil || !node.IsResolver() {
+		node = chain.Nodes[structs.DiscoveryGraphNodeTypeResolver+":"+chain.ID()]
+	}
+	if node == nil {
+		return fmt.Errorf("discovery chain for service %q has no resolver", args.ServiceName)
+	}
+
+	reply.Index = index
+	reply.Primary, err = h.discoveryTargetHealth(args, chain.Targets[node.Resolver.Target], reply)
+	if err != nil {
+		return err
+	}
+
+	if failover := node.Resolver.Failover; failover != nil {
+		for _, id := range failover.Targets {
+			th, err := h.discoveryTargetHealth(args, chain.Targets[id], reply)
+			if err != nil {
+				th.Error = err.Error()
+			}
+			reply.Failover = append(reply.Failover, th)
+		}
+	}
+	return nil
+}
+
+// discoveryTargetHealth counts the instances behind a discovery chain target,
+// merging the index and ACL filtering of the underlying query into reply.
+func (h *Health) discoveryTargetHealth(
+	args *structs.ServiceFailoverSummaryRequest,
+	target *structs.DiscoveryTarget,
+	reply *structs.ServiceFailoverSummaryResponse,
+) (*structs.DiscoveryTargetHealth, error) {
+	th := &structs.DiscoveryTargetHealth{
+		TargetID:      target.ID,
+		Service:       target.Service,
+		ServiceSubset: target.ServiceSubset,
+		Namespace:     target.Namespace,
+		Partition:     target.Partition,
+		Datacenter:    target.Datacenter,
+		Peer:          target.Peer,
+	}
+
+	req := structs.ServiceSpecificRequest{
+		Datacenter:     target.Datacenter,
+		PeerName:       target.Peer,
+		ServiceName:    target.Service,
+		EnterpriseMeta: acl.NewEnterpriseMetaWithPartition(target.Partition, target.Namespace),
+		QueryOptions: structs.QueryOptions{
+			Token:             args.Token,
+			Filter:            target.Subset.Filter,
+			AllowStale:        args.AllowStale,
+			RequireConsistent: args.RequireConsistent,
+		},
+	}
+	if target.Peer != "" {
+		// Services imported from a peer are stored in the local datacenter.
+		req.Datacenter = h.srv.config.Datacenter
+	}
+
+	var out structs.IndexedCheckServiceNodes
+	if err := h.ServiceNodes(&req, &out); err != nil {
+		return th, err
+	}
+
+	if out.Index > reply.Index {
+		reply.Index = out.Index
+	}
+	if out.ResultsFilteredByACLs {
+		reply.ResultsFilteredByACLs = true
+	}
+
+	th.Total = len(out.Nodes)
+	for _, csn := range out.Nodes {
+		if isEndpointHealthy(csn, target.Subset.OnlyPassing) {
+			th.Healthy++
+		}
+	}
+	return th, nil
+}
+
+// isEndpointHealthy reports whether a proxy would send traffic to the given
+// instance. This follows the rules used to compute Envoy endpoint health.
+func isEndpointHealthy(csn structs.CheckServiceNode, onlyPassing bool) bool {
+	weight := 1
+	if csn.Service.Weights != nil {
+		weight = csn.Service.Weights.Passing
+	}
+
+	for _, chk := range csn.Checks {
+		if chk.Status == api.HealthCritical {
+			return false
+		}
+		if onlyPassing && chk.Status != api.HealthPassing {
+			return false
+		}
+		if chk.Status == api.HealthWarning && csn.Service.Weights != nil {
+			weight = csn.Service.Weights.Warning
+		}
+	}
+	return weight >= 1
+}
+
 // The serviceNodes* functions below are the various lookup methods that
 // can be used by the ServiceNodes endpoint.
 
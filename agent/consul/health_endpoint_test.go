@@ -1494,6 +1494,126 @@ func TestHealth_ServiceNodes_Ingress_ACL(t *testing.T) {
 	require.Equal(t, nodes[1].Checks[0].Status, api.HealthPassing)
 }
 
+func TestHealth_ServiceFailoverSummary(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	_, s1 := testServerWithConfig(t, func(config *Config) {
+		config.PeeringTestAllowPeerRegistrations = true
+	})
+	codec := rpcClient(t, s1)
+
+	waitForLeaderEstablishment(t, s1)
+
+	register := func(t *testing.T, peerName, node, status string) {
+		arg := structs.RegisterRequest{
+			Datacenter: "dc1",
+			Node:       node,
+			Address:    "127.0.0.1",
+			PeerName:   peerName,
+			Service: &structs.NodeService{
+				ID:       "db",
+				Service:  "db",
+				PeerName: peerName,
+			},
+			Check: &structs.HealthCheck{
+				Name:      "db check",
+				Status:    status,
+				ServiceID: "db",
+				PeerName:  peerName,
+			},
+		}
+		var out struct{}
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Catalog.Register", &arg, &out))
+	}
+
+	// Local instances: one of each status. Only critical instances are
+	// unhealthy without OnlyPassing.
+	register(t, "", "foo", api.HealthPassing)
+	register(t, "", "bar", api.HealthWarning)
+	register(t, "", "baz", api.HealthCritical)
+
+	// Instances imported from the peer the service fails over to.
+	register(t, "cluster-02", "peer-foo", api.HealthPassing)
+	register(t, "cluster-02", "peer-bar", api.HealthCritical)
+
+	// Sameness groups resolve to the same peer failover targets, but are only
+	// available in Consul Enterprise, so the peer is targeted directly here.
+	resolver := structs.ConfigEntryRequest{
+		Datacenter: "dc1",
+		Entry: &structs.ServiceResolverConfigEntry{
+			Kind: structs.ServiceResolver,
+			Name: "db",
+			Failover: map[string]structs.ServiceResolverFailover{
+				"*": {
+					Targets: []structs.ServiceResolverFailoverTarget{
+						{Peer: "cluster-02"},
+						{Datacenter: "dc9"},
+					},
+				},
+			},
+		},
+	}
+	var applied bool
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConfigEntry.Apply", &resolver, &applied))
+	require.True(t, applied)
+
+	t.Run("with failover", func(t *testing.T) {
+		req := structs.ServiceFailoverSummaryRequest{
+			Datacenter:  "dc1",
+			ServiceName: "db",
+		}
+		var out structs.ServiceFailoverSummaryResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Health.ServiceFailoverSummary", &req, &out))
+		require.NotZero(t, out.Index)
+
+		require.NotNil(t, out.Primary)
+		require.Equal(t, "db.default.default.dc1", out.Primary.TargetID)
+		require.Equal(t, "dc1", out.Primary.Datacenter)
+		require.Equal(t, 2, out.Primary.Healthy)
+		require.Equal(t, 3, out.Primary.Total)
+		require.Empty(t, out.Primary.Error)
+
+		require.Len(t, out.Failover, 2)
+
+		peer := out.Failover[0]
+		require.Equal(t, "db.default.default.external.cluster-02", peer.TargetID)
+		require.Equal(t, "cluster-02", peer.Peer)
+		require.Equal(t, 1, peer.Healthy)
+		require.Equal(t, 2, peer.Total)
+		require.Empty(t, peer.Error)
+
+		remote := out.Failover[1]
+		require.Equal(t, "db.default.default.dc9", remote.TargetID)
+		require.Equal(t, "dc9", remote.Datacenter)
+		require.Zero(t, remote.Total)
+		require.Contains(t, remote.Error, structs.ErrNoDCPath.Error())
+	})
+
+	t.Run("without failover", func(t *testing.T) {
+		req := structs.ServiceFailoverSummaryRequest{
+			Datacenter:  "dc1",
+			ServiceName: "web",
+		}
+		var out structs.ServiceFailoverSummaryResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Health.ServiceFailoverSummary", &req, &out))
+
+		require.NotNil(t, out.Primary)
+		require.Equal(t, "web.default.default.dc1", out.Primary.TargetID)
+		require.Zero(t, out.Primary.Total)
+		require.Empty(t, out.Failover)
+	})
+
+	t.Run("missing service name", func(t *testing.T) {
+		req := structs.ServiceFailoverSummaryRequest{Datacenter: "dc1"}
+		var out structs.ServiceFailoverSummaryResponse
+		err := msgpackrpc.CallWithCodec(codec, "Health.ServiceFailoverSummary", &req, &out)
+		require.ErrorContains(t, err, "Must provide service name")
+	})
+}
+
 func TestHealth_NodeChecks_FilterACL(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
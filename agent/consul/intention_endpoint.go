@@ -6,6 +6,7 @@ package consul
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/armon/go-metrics"
@@ -13,12 +14,16 @@ import (
 	"github.com/hashicorp/go-bexpr"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/go-uuid"
 	hashstructure_v2 "github.com/mitchellh/hashstructure/v2"
 
 	"github.com/dhiaayachi/consul/acl"
+	"github.com/dhiaayachi/consul/acl/resolver"
+	"github.com/dhiaayachi/consul/agent/consul/discoverychain"
 	"github.com/dhiaayachi/consul/agent/consul/state"
 	"github.com/dhiaayachi/consul/agent/structs"
 	"github.com/dhiaayachi/consul/lib"
+	"github.com/dhiaayachi/consul/logging"
 )
 
 var IntentionSummaries = []prometheus.SummaryDefinition{
@@ -121,14 +126,25 @@ func (s *Intention) Apply(args *structs.IntentionRequest, reply *string) error {
 	)
 	switch args.Op {
 	case structs.IntentionOpCreate:
-		legacyWrite = true
-		mut, err = s.computeApplyChangesLegacyCreate(accessorID, authz, &entMeta, args)
+		if args.AllowL7 && len(args.Intention.Permissions) > 0 {
+			legacyWrite = false
+			mut, err = s.computeApplyChangesL7Create(accessorID, authz, &entMeta, args)
+		} else {
+			legacyWrite = true
+			mut, err = s.computeApplyChangesLegacyCreate(accessorID, authz, &entMeta, args)
+		}
 	case structs.IntentionOpUpdate:
+		if args.AllowL7 && len(args.Intention.Permissions) > 0 {
+			return fmt.Errorf("Permissions cannot be added to an existing legacy intention, delete it and create it again with AllowL7")
+		}
 		legacyWrite = true
 		mut, err = s.computeApplyChangesLegacyUpdate(accessorID, authz, &entMeta, args)
 	case structs.IntentionOpUpsert:
 		legacyWrite = false
 		mut, err = s.computeApplyChangesUpsert(accessorID, authz, &entMeta, args)
+	case structs.IntentionOpImport:
+		legacyWrite = args.Intention.ID != ""
+		mut, err = s.computeApplyChangesImport(accessorID, authz, &entMeta, args)
 	case structs.IntentionOpDelete:
 		if args.Intention.ID == "" {
 			legacyWrite = false
@@ -296,6 +312,67 @@ func (s *Intention) computeApplyChangesLegacyUpdate(
 	return mut, nil
 }
 
+func (s *Intention) computeApplyChangesL7Create(
+	accessorID string,
+	authz acl.Authorizer,
+	entMeta *acl.EnterpriseMeta,
+	args *structs.IntentionRequest,
+) (*structs.IntentionMutation, error) {
+	// Legacy intentions cannot carry Permissions, so an opted-in create is
+	// written as a config-entry source instead, exactly like an upsert.
+
+	if args.Intention.ID != "" {
+		return nil, fmt.Errorf("ID must be empty when creating a new intention")
+	}
+
+	args.Intention.FillPartitionAndNamespace(entMeta, true)
+
+	sn := args.Intention.SourceServiceName()
+	dn := args.Intention.DestinationServiceName()
+	if !args.Intention.CanWrite(authz) {
+		s.logger.Debug("Intention creation denied due to ACLs",
+			"source", sn.String(),
+			"destination", dn.String(),
+			"accessorID", acl.AliasIfAnonymousToken(accessorID))
+		return nil, acl.ErrPermissionDenied
+	}
+
+	// Default source type
+	if args.Intention.SourceType == "" {
+		args.Intention.SourceType = structs.IntentionSourceConsul
+	}
+
+	if err := s.validateEnterpriseIntention(args.Intention); err != nil {
+		return nil, err
+	}
+
+	//nolint:staticcheck
+	if err := args.Intention.ValidateAllowL7(); err != nil {
+		return nil, err
+	}
+
+	state := s.srv.fsm.State()
+	_, prevEntry, err := state.ConfigEntry(nil, structs.ServiceIntentions, dn.Name, &dn.EnterpriseMeta)
+	if err != nil {
+		return nil, fmt.Errorf("Intention lookup failed: %v", err)
+	}
+	if prevEntry != nil && !prevEntry.(*structs.ServiceIntentionsConfigEntry).LegacyIDFieldsAreAllEmpty() {
+		return nil, fmt.Errorf("cannot create intentions with Permissions for a destination of %q that has intentions created via the legacy intention API", dn.String())
+	}
+
+	// Unlike an upsert a create must not replace an existing intention.
+	_, _, ixn, err := state.IntentionGetExact(nil, args.Intention.ToExact())
+	if err != nil {
+		return nil, fmt.Errorf("Intention lookup failed: %v", err)
+	}
+	if ixn != nil {
+		return nil, fmt.Errorf("Intention from %q to %q already exists", sn.String(), dn.String())
+	}
+
+	args.Op = structs.IntentionOpUpsert
+	return s.computeApplyChangesUpsert(accessorID, authz, entMeta, args)
+}
+
 func (s *Intention) computeApplyChangesUpsert(
 	accessorID string,
 	authz acl.Authorizer,
@@ -311,6 +388,11 @@ func (s *Intention) computeApplyChangesUpsert(
 
 	args.Intention.FillPartitionAndNamespace(entMeta, true)
 
+	if max := s.srv.config.ConfigEntryMaxIntentionPermissions; max > 0 && len(args.Intention.Permissions) > max {
+		return nil, fmt.Errorf("Permissions has %d permissions, which exceeds the limit of %d per source",
+			len(args.Intention.Permissions), max)
+	}
+
 	if !args.Intention.CanWrite(authz) {
 		sn := args.Intention.SourceServiceName()
 		dn := args.Intention.DestinationServiceName()
@@ -358,6 +440,76 @@ func (s *Intention) computeApplyChangesUpsert(
 	}, nil
 }
 
+func (s *Intention) computeApplyChangesImport(
+	accessorID string,
+	authz acl.Authorizer,
+	entMeta *acl.EnterpriseMeta,
+	args *structs.IntentionRequest,
+) (*structs.IntentionMutation, error) {
+	// Intentions without an ID were never legacy intentions so they are
+	// written exactly like an upsert.
+	if args.Intention.ID == "" {
+		args.Op = structs.IntentionOpUpsert
+		return s.computeApplyChangesUpsert(accessorID, authz, entMeta, args)
+	}
+
+	// Otherwise this is an exported legacy intention. Unlike a legacy create
+	// the ID and timestamps are kept so that anything keyed on them still
+	// works after the round trip.
+	args.Intention.FillPartitionAndNamespace(entMeta, true)
+
+	if !args.Intention.CanWrite(authz) {
+		sn := args.Intention.SourceServiceName()
+		dn := args.Intention.DestinationServiceName()
+		s.logger.Debug("Intention import denied due to ACLs",
+			"source", sn.String(),
+			"destination", dn.String(),
+			"accessorID", acl.AliasIfAnonymousToken(accessorID))
+		return nil, acl.ErrPermissionDenied
+	}
+
+	if _, err := uuid.ParseUUID(args.Intention.ID); err != nil {
+		return nil, fmt.Errorf("ID must be a valid UUID: %v", err)
+	}
+	if ok, err := s.checkIntentionID(args.Intention.ID); err != nil {
+		return nil, fmt.Errorf("Intention lookup failed: %v", err)
+	} else if !ok {
+		return nil, fmt.Errorf("Intention with ID %q already exists", args.Intention.ID)
+	}
+
+	// Default source type
+	if args.Intention.SourceType == "" {
+		args.Intention.SourceType = structs.IntentionSourceConsul
+	}
+
+	if err := s.validateEnterpriseIntention(args.Intention); err != nil {
+		return nil, err
+	}
+
+	//nolint:staticcheck
+	if err := args.Intention.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Intentions exported before timestamps were tracked get the current
+	// time, the same as a legacy create would.
+	now := time.Now().UTC()
+	if args.Intention.CreatedAt.IsZero() {
+		args.Intention.CreatedAt = now
+	}
+	if args.Intention.UpdatedAt.IsZero() {
+		args.Intention.UpdatedAt = args.Intention.CreatedAt
+	}
+
+	// The FSM only knows how to store a legacy intention as a create.
+	args.Op = structs.IntentionOpCreate
+
+	return &structs.IntentionMutation{
+		Destination: args.Intention.DestinationServiceName(),
+		Value:       args.Intention.ToSourceIntention(true),
+	}, nil
+}
+
 func (s *Intention) computeApplyChangesLegacyDelete(
 	accessorID string,
 	authz acl.Authorizer,
@@ -569,6 +721,22 @@ func (s *Intention) List(args *structs.IntentionListRequest, reply *structs.Inde
 			}
 			reply.Intentions = raw.(structs.Intentions)
 
+			if args.Unused {
+				unused := make(structs.Intentions, 0, len(reply.Intentions))
+				for _, ixn := range reply.Intentions {
+					if s.srv.intentionMatchStats.matches(ixn) == 0 {
+						unused = append(unused, ixn)
+					}
+				}
+				reply.Intentions = unused
+			}
+
+			// Sorting only reorders the reply, so the index above is
+			// unaffected and blocking queries behave the same either way.
+			if args.Sorted {
+				sort.Sort(structs.IntentionPrecedenceSorter(reply.Intentions))
+			}
+
 			return nil
 		},
 	)
@@ -683,6 +851,222 @@ func (s *Intention) Match(args *structs.IntentionQueryRequest, reply *structs.In
 	)
 }
 
+// Precedence returns the intentions matching a destination in the order they
+// are evaluated, followed by an entry for the default intention policy which
+// applies when none of them match.
+func (s *Intention) Precedence(args *structs.IntentionQueryRequest, reply *structs.IndexedIntentionPrecedence) error {
+	// Exit early if Connect hasn't been enabled.
+	if !s.srv.config.ConnectEnabled {
+		return ErrConnectNotEnabled
+	}
+
+	// Forward if necessary
+	if done, err := s.srv.ForwardRPC("Intention.Precedence", args, reply); done {
+		return err
+	}
+
+	entry := args.Precedence
+	if entry == nil {
+		return errors.New("Precedence must be specified on args")
+	}
+	if entry.Name == "" {
+		return errors.New("Precedence requires a destination name")
+	}
+
+	// Get the ACL token for the request for the checks below.
+	var entMeta acl.EnterpriseMeta
+	authz, err := s.srv.ResolveTokenAndDefaultMeta(args.Token, &entMeta, nil)
+	if err != nil {
+		return err
+	}
+
+	// Finish defaulting the namespace and partition fields.
+	if entry.Namespace == "" {
+		entry.Namespace = entMeta.NamespaceOrDefault()
+	}
+	if err := s.srv.validateEnterpriseIntentionNamespace(entry.Namespace, true); err != nil {
+		return fmt.Errorf("Invalid destination namespace %q: %v", entry.Namespace, err)
+	}
+	if entry.Partition == "" {
+		entry.Partition = entMeta.PartitionOrDefault()
+	}
+	if err := s.srv.validateEnterpriseIntentionPartition(entry.Partition); err != nil {
+		return fmt.Errorf("Invalid destination partition %q: %v", entry.Partition, err)
+	}
+
+	var authzContext acl.AuthorizerContext
+	entry.FillAuthzContext(&authzContext)
+	if err := authz.ToAllowAuthorizer().IntentionReadAllowed(entry.Name, &authzContext); err != nil {
+		accessorID := authz.AccessorID()
+		s.logger.Debug("Operation on intention prefix denied due to ACLs",
+			"prefix", entry.Name,
+			"accessorID", acl.AliasIfAnonymousToken(accessorID))
+		return err
+	}
+
+	defaultAction := structs.IntentionActionDeny
+	if DefaultIntentionAllow(authz, s.srv.config.DefaultIntentionPolicy) {
+		defaultAction = structs.IntentionActionAllow
+	}
+
+	return s.srv.blockingQuery(
+		&args.QueryOptions,
+		&reply.QueryMeta,
+		func(ws memdb.WatchSet, state *state.Store) error {
+			index, intentions, err := state.IntentionMatchOne(ws, *entry, structs.IntentionMatchDestination, structs.IntentionTargetService)
+			if err != nil {
+				return err
+			}
+
+			sorted := make(structs.Intentions, len(intentions))
+			copy(sorted, intentions)
+			sort.Sort(structs.IntentionPrecedenceSorter(sorted))
+
+			rules := make([]*structs.IntentionPrecedenceRule, 0, len(sorted)+1)
+			for _, ixn := range sorted {
+				rules = append(rules, &structs.IntentionPrecedenceRule{
+					Intention:  ixn,
+					Precedence: ixn.Precedence,
+					Action:     ixn.Action,
+				})
+			}
+			rules = append(rules, &structs.IntentionPrecedenceRule{
+				Action:  defaultAction,
+				Default: true,
+			})
+
+			reply.Index = index
+			reply.Rules = rules
+			return nil
+		},
+	)
+}
+
+// Topology returns the upstreams that intentions allow a service to connect to,
+// each with the targets its discovery chain routes, splits or fails over to.
+// Targets are checked against the intentions of the source as well, since the
+// service behind a target enforces its own intentions.
+func (s *Intention) Topology(args *structs.ServiceSpecificRequest, reply *structs.IndexedIntentionTopology) error {
+	// Exit early if Connect hasn't been enabled.
+	if !s.srv.config.ConnectEnabled {
+		return ErrConnectNotEnabled
+	}
+	if args.ServiceName == "" {
+		return fmt.Errorf("Must provide a service name")
+	}
+
+	// Forward if necessary
+	if done, err := s.srv.ForwardRPC("Intention.Topology", args, reply); done {
+		return err
+	}
+
+	var authzContext acl.AuthorizerContext
+	authz, err := s.srv.ResolveTokenAndDefaultMeta(args.Token, &args.EnterpriseMeta, &authzContext)
+	if err != nil {
+		return err
+	}
+	if err := s.srv.validateEnterpriseRequest(&args.EnterpriseMeta, false); err != nil {
+		return err
+	}
+	if err := authz.ToAllowAuthorizer().ServiceReadAllowed(args.ServiceName, &authzContext); err != nil {
+		return err
+	}
+
+	defaultAllow := DefaultIntentionAllow(authz, s.srv.config.DefaultIntentionPolicy)
+
+	return s.srv.blockingQuery(
+		&args.QueryOptions,
+		&reply.QueryMeta,
+		func(ws memdb.WatchSet, store *state.Store) error {
+			source := structs.NewServiceName(args.ServiceName, &args.EnterpriseMeta)
+			index, upstreams, err := store.IntentionTopology(ws, source, false, defaultAllow, structs.IntentionTargetService)
+			if err != nil {
+				return err
+			}
+
+			// Only include upstreams the token can discover.
+			list := structs.IndexedServiceList{Services: upstreams}
+			s.srv.filterACLWithAuthorizer(authz, &list)
+
+			entry := structs.IntentionMatchEntry{
+				Namespace: args.EnterpriseMeta.NamespaceOrDefault(),
+				Partition: args.EnterpriseMeta.PartitionOrDefault(),
+				Name:      args.ServiceName,
+			}
+			ixnIndex, intentions, err := store.IntentionMatchOne(ws, entry, structs.IntentionMatchSource, structs.IntentionTargetService)
+			if err != nil {
+				return fmt.Errorf("failed to query intentions for %s: %v", source.String(), err)
+			}
+			index = lib.MaxUint64(index, ixnIndex)
+
+			result := make([]*structs.IntentionTopologyUpstream, 0, len(list.Services))
+			for _, upstream := range list.Services {
+				req := discoverychain.CompileRequest{
+					ServiceName:          upstream.Name,
+					EvaluateInNamespace:  upstream.NamespaceOrDefault(),
+					EvaluateInPartition:  upstream.PartitionOrDefault(),
+					EvaluateInDatacenter: s.srv.config.Datacenter,
+				}
+				chainIndex, chain, _, err := store.ServiceDiscoveryChain(ws, upstream.Name, &upstream.EnterpriseMeta, req)
+				if err != nil {
+					return fmt.Errorf("failed to compile discovery chain for %s: %v", upstream.String(), err)
+				}
+				index = lib.MaxUint64(index, chainIndex)
+
+				out := &structs.IntentionTopologyUpstream{
+					Name:      upstream.Name,
+					Namespace: upstream.NamespaceOrEmpty(),
+					Partition: upstream.PartitionOrEmpty(),
+					Protocol:  chain.Protocol,
+				}
+				for _, target := range chain.Targets {
+					decision, err := store.IntentionDecision(state.IntentionDecisionOpts{
+						Target:           target.Service,
+						Namespace:        target.Namespace,
+						Partition:        target.Partition,
+						Intentions:       intentions,
+						MatchType:        structs.IntentionMatchDestination,
+						DefaultAllow:     defaultAllow,
+						AllowPermissions: true,
+					})
+					if err != nil {
+						return fmt.Errorf("failed to get intention decision from %s to %s: %v", source.String(), target.Service, err)
+					}
+					out.Targets = append(out.Targets, &structs.IntentionTopologyTarget{
+						ID:            target.ID,
+						Service:       target.Service,
+						ServiceSubset: target.ServiceSubset,
+						Namespace:     target.Namespace,
+						Partition:     target.Partition,
+						Datacenter:    target.Datacenter,
+						Peer:          target.Peer,
+						Allowed:       decision.Allowed,
+					})
+				}
+				sort.Slice(out.Targets, func(i, j int) bool {
+					return out.Targets[i].ID < out.Targets[j].ID
+				})
+				result = append(result, out)
+			}
+
+			sort.Slice(result, func(i, j int) bool {
+				a, b := result[i], result[j]
+				if a.Partition != b.Partition {
+					return a.Partition < b.Partition
+				}
+				if a.Namespace != b.Namespace {
+					return a.Namespace < b.Namespace
+				}
+				return a.Name < b.Name
+			})
+
+			reply.Index = index
+			reply.Upstreams = result
+			return nil
+		},
+	)
+}
+
 // Check tests a source/destination and returns whether it would be allowed
 // or denied based on the current ACL configuration.
 //
@@ -715,6 +1099,203 @@ func (s *Intention) Check(args *structs.IntentionQueryRequest, reply *structs.In
 		return err
 	}
 
+	allowed, err := s.check(authz, &entMeta, query)
+	if err != nil {
+		return err
+	}
+	reply.Allowed = allowed
+	return nil
+}
+
+// CheckBatch tests many source/destination pairs at once and returns
+// whether each would be allowed or denied. The results are identical to
+// calling Check for each pair, except that a pair whose destination the
+// token cannot read is reported with an error instead of failing the
+// whole request.
+func (s *Intention) CheckBatch(args *structs.IntentionQueryCheckBatchRequest, reply *structs.IntentionQueryCheckBatchResponse) error {
+	// Exit early if Connect hasn't been enabled.
+	if !s.srv.config.ConnectEnabled {
+		return ErrConnectNotEnabled
+	}
+
+	// Forward maybe
+	if done, err := s.srv.ForwardRPC("Intention.CheckBatch", args, reply); done {
+		return err
+	}
+
+	for i, query := range args.Checks {
+		if query == nil {
+			return fmt.Errorf("Check %d must not be empty", i)
+		}
+	}
+
+	// Get the ACL token for the request for the checks below.
+	var entMeta acl.EnterpriseMeta
+	authz, err := s.srv.ResolveTokenAndDefaultMeta(args.Token, &entMeta, nil)
+	if err != nil {
+		return err
+	}
+
+	results := make([]*structs.IntentionQueryCheckResponse, 0, len(args.Checks))
+	for _, query := range args.Checks {
+		allowed, err := s.check(authz, &entMeta, query)
+		switch {
+		case acl.IsErrPermissionDenied(err):
+			results = append(results, &structs.IntentionQueryCheckResponse{Error: err.Error()})
+		case err != nil:
+			return err
+		default:
+			results = append(results, &structs.IntentionQueryCheckResponse{Allowed: allowed})
+		}
+	}
+	reply.Results = results
+	return nil
+}
+
+// Simulate reports the pairs of catalog services whose intention decision
+// would flip if the proposed service-intentions config entry replaced the
+// existing entry for its destination. Decisions are made the same way as
+// for CheckBatch, so L7 intentions count as deny, but they are not recorded
+// in the match stats or the audit log. Nothing is written.
+func (s *Intention) Simulate(args *structs.IntentionSimulateRequest, reply *structs.IntentionSimulateResponse) error {
+	// Exit early if Connect hasn't been enabled.
+	if !s.srv.config.ConnectEnabled {
+		return ErrConnectNotEnabled
+	}
+
+	if args.Entry == nil {
+		return errors.New("Entry must be specified on args")
+	}
+	if err := s.srv.validateEnterpriseRequest(args.Entry.GetEnterpriseMeta(), false); err != nil {
+		return err
+	}
+
+	// Forward maybe
+	if done, err := s.srv.ForwardRPC("Intention.Simulate", args, reply); done {
+		return err
+	}
+
+	entry := args.Entry
+	authz, err := s.srv.ResolveTokenAndDefaultMeta(args.Token, entry.GetEnterpriseMeta(), nil)
+	if err != nil {
+		return err
+	}
+	if err := entry.Normalize(); err != nil {
+		return err
+	}
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+	if err := entry.CanRead(authz); err != nil {
+		return err
+	}
+
+	defaultAllow := DefaultIntentionAllow(authz, s.srv.config.DefaultIntentionPolicy)
+	store := s.srv.fsm.State()
+
+	_, kindNames, err := store.ServiceNamesOfKind(nil, structs.ServiceKindTypical)
+	if err != nil {
+		return fmt.Errorf("failed to list service names: %v", err)
+	}
+
+	// Only simulate services the token can discover.
+	services := make([]structs.ServiceName, 0, len(kindNames))
+	for _, kn := range kindNames {
+		if kn.Service.Name == structs.ConsulServiceName {
+			continue
+		}
+		var authzContext acl.AuthorizerContext
+		kn.Service.FillAuthzContext(&authzContext)
+		if authz.ServiceRead(kn.Service.Name, &authzContext) != acl.Allow {
+			continue
+		}
+		services = append(services, kn.Service)
+	}
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].String() < services[j].String()
+	})
+
+	decide := func(intentions structs.SimplifiedIntentions, source structs.ServiceName) (bool, error) {
+		decision, err := store.IntentionDecision(state.IntentionDecisionOpts{
+			Target:           source.Name,
+			Namespace:        source.NamespaceOrDefault(),
+			Partition:        source.PartitionOrDefault(),
+			Intentions:       intentions,
+			MatchType:        structs.IntentionMatchSource,
+			DefaultAllow:     defaultAllow,
+			AllowPermissions: false,
+		})
+		return decision.Allowed, err
+	}
+
+	target := entry.DestinationServiceName()
+	proposed := entry.ToIntentions()
+	for _, dest := range services {
+		if !simulatedDestination(target, dest) {
+			continue
+		}
+
+		matchEntry := structs.IntentionMatchEntry{
+			Namespace: dest.NamespaceOrDefault(),
+			Partition: dest.PartitionOrDefault(),
+			Name:      dest.Name,
+		}
+		_, current, err := store.IntentionMatchOne(nil, matchEntry, structs.IntentionMatchDestination, structs.IntentionTargetService)
+		if err != nil {
+			return fmt.Errorf("failed to query intentions for %s: %v", dest.String(), err)
+		}
+
+		// Replace the intentions of the existing entry with the proposed ones.
+		next := make(structs.Intentions, 0, len(current)+len(proposed))
+		for _, ixn := range current {
+			if ixn.DestinationServiceName() != target {
+				next = append(next, ixn)
+			}
+		}
+		next = append(next, proposed...)
+		sort.Sort(structs.IntentionPrecedenceSorter(next))
+
+		for _, source := range services {
+			if source == dest {
+				continue
+			}
+			before, err := decide(current, source)
+			if err != nil {
+				return fmt.Errorf("failed to get intention decision from %s to %s: %v", source.String(), dest.String(), err)
+			}
+			after, err := decide(structs.SimplifiedIntentions(next), source)
+			if err != nil {
+				return fmt.Errorf("failed to get intention decision from %s to %s: %v", source.String(), dest.String(), err)
+			}
+
+			pair := structs.IntentionSimulatePair{Source: source, Destination: dest}
+			switch {
+			case !before && after:
+				reply.NewlyAllowed = append(reply.NewlyAllowed, pair)
+			case before && !after:
+				reply.NewlyDenied = append(reply.NewlyDenied, pair)
+			}
+		}
+	}
+	return nil
+}
+
+// simulatedDestination returns whether the intentions of a config entry for
+// target apply to the dest service.
+func simulatedDestination(target, dest structs.ServiceName) bool {
+	if target.PartitionOrDefault() != dest.PartitionOrDefault() {
+		return false
+	}
+	if target.NamespaceOrDefault() != structs.WildcardSpecifier && target.NamespaceOrDefault() != dest.NamespaceOrDefault() {
+		return false
+	}
+	return target.Name == structs.WildcardSpecifier || target.Name == dest.Name
+}
+
+// check evaluates a single source/destination pair on behalf of Check and
+// CheckBatch. Namespace and partition fields left empty on the query are
+// defaulted from entMeta.
+func (s *Intention) check(authz resolver.Result, entMeta *acl.EnterpriseMeta, query *structs.IntentionQueryCheck) (bool, error) {
 	// Finish defaulting the namespace fields.
 	if query.SourceNS == "" {
 		query.SourceNS = entMeta.NamespaceOrDefault()
@@ -730,14 +1311,14 @@ func (s *Intention) Check(args *structs.IntentionQueryRequest, reply *structs.In
 	}
 
 	if err := s.srv.validateEnterpriseIntentionNamespace(query.SourceNS, false); err != nil {
-		return fmt.Errorf("Invalid source namespace %q: %v", query.SourceNS, err)
+		return false, fmt.Errorf("Invalid source namespace %q: %v", query.SourceNS, err)
 	}
 	if err := s.srv.validateEnterpriseIntentionNamespace(query.DestinationNS, false); err != nil {
-		return fmt.Errorf("Invalid destination namespace %q: %v", query.DestinationNS, err)
+		return false, fmt.Errorf("Invalid destination namespace %q: %v", query.DestinationNS, err)
 	}
 
 	if query.SourceType != structs.IntentionSourceConsul {
-		return fmt.Errorf("unsupported SourceType: %q", query.SourceType)
+		return false, fmt.Errorf("unsupported SourceType: %q", query.SourceType)
 	}
 
 	// Perform the ACL check. For Check we only require ServiceRead and
@@ -754,7 +1335,7 @@ func (s *Intention) Check(args *structs.IntentionQueryRequest, reply *structs.In
 			s.logger.Debug("test on intention denied due to ACLs",
 				"prefix", prefix,
 				"accessorID", acl.AliasIfAnonymousToken(accessorID))
-			return err
+			return false, err
 		}
 	}
 
@@ -762,33 +1343,86 @@ func (s *Intention) Check(args *structs.IntentionQueryRequest, reply *structs.In
 
 	store := s.srv.fsm.State()
 
-	entry := structs.IntentionMatchEntry{
-		Namespace: query.SourceNS,
-		Partition: query.SourcePartition,
-		Name:      query.SourceName,
-	}
-	_, intentions, err := store.IntentionMatchOne(nil, entry, structs.IntentionMatchSource, structs.IntentionTargetService)
-	if err != nil {
-		return fmt.Errorf("failed to query intentions for %s/%s", query.SourceNS, query.SourceName)
-	}
+	var opts state.IntentionDecisionOpts
+	if query.SourcePeer != "" {
+		// Intentions are only indexed by local sources, so peered sources
+		// are matched against the intentions for the destination instead.
+		entry := structs.IntentionMatchEntry{
+			Namespace: query.DestinationNS,
+			Partition: query.DestinationPartition,
+			Name:      query.DestinationName,
+		}
+		_, intentions, err := store.IntentionMatchOne(nil, entry, structs.IntentionMatchDestination, structs.IntentionTargetService)
+		if err != nil {
+			return false, fmt.Errorf("failed to query intentions for %s/%s", query.DestinationNS, query.DestinationName)
+		}
+
+		opts = state.IntentionDecisionOpts{
+			Target:           query.SourceName,
+			Namespace:        query.SourceNS,
+			Partition:        query.SourcePartition,
+			Peer:             query.SourcePeer,
+			Intentions:       intentions,
+			MatchType:        structs.IntentionMatchSource,
+			DefaultAllow:     defaultAllow,
+			AllowPermissions: false,
+		}
+	} else {
+		entry := structs.IntentionMatchEntry{
+			Namespace: query.SourceNS,
+			Partition: query.SourcePartition,
+			Name:      query.SourceName,
+		}
+		_, intentions, err := store.IntentionMatchOne(nil, entry, structs.IntentionMatchSource, structs.IntentionTargetService)
+		if err != nil {
+			return false, fmt.Errorf("failed to query intentions for %s/%s", query.SourceNS, query.SourceName)
+		}
 
-	opts := state.IntentionDecisionOpts{
-		Target:           query.DestinationName,
-		Namespace:        query.DestinationNS,
-		Partition:        query.DestinationPartition,
-		Intentions:       intentions,
-		MatchType:        structs.IntentionMatchDestination,
-		DefaultAllow:     defaultAllow,
-		AllowPermissions: false,
+		opts = state.IntentionDecisionOpts{
+			Target:           query.DestinationName,
+			Namespace:        query.DestinationNS,
+			Partition:        query.DestinationPartition,
+			Intentions:       intentions,
+			MatchType:        structs.IntentionMatchDestination,
+			DefaultAllow:     defaultAllow,
+			AllowPermissions: false,
+		}
 	}
 	decision, err := store.IntentionDecision(opts)
 	if err != nil {
-		return fmt.Errorf("failed to get intention decision from (%s/%s) to (%s/%s): %v",
+		return false, fmt.Errorf("failed to get intention decision from (%s/%s) to (%s/%s): %v",
 			query.SourceNS, query.SourceName, query.DestinationNS, query.DestinationName, err)
 	}
-	reply.Allowed = decision.Allowed
 
-	return nil
+	ixn := state.MatchingIntention(opts)
+	if ixn != nil {
+		s.srv.intentionMatchStats.record(ixn)
+	}
+
+	if s.srv.config.ConnectIntentionAuditLog {
+		rule := "default"
+		if ixn != nil {
+			rule = ixn.String()
+		}
+		action := structs.IntentionActionDeny
+		if decision.Allowed {
+			action = structs.IntentionActionAllow
+		}
+		srcMeta := acl.NewEnterpriseMetaWithPartition(query.SourcePartition, query.SourceNS)
+		dstMeta := acl.NewEnterpriseMetaWithPartition(query.DestinationPartition, query.DestinationNS)
+		source := structs.NewServiceName(query.SourceName, &srcMeta).String()
+		if query.SourcePeer != "" {
+			source = structs.PeeredServiceName{Peer: query.SourcePeer, ServiceName: structs.NewServiceName(query.SourceName, &srcMeta)}.String()
+		}
+		s.logger.Named(logging.IntentionAudit).Info("intention decision",
+			"source", source,
+			"destination", structs.NewServiceName(query.DestinationName, &dstMeta).String(),
+			"action", action,
+			"rule", rule,
+		)
+	}
+
+	return decision.Allowed, nil
 }
 
 func (s *Intention) validateEnterpriseIntention(ixn *structs.Intention) error {
@@ -191,6 +191,257 @@ func TestIntentionApply_createWithID(t *testing.T) {
 }
 
 // Test basic updating
+func TestIntentionApply_importLegacy(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	waitForLeaderEstablishment(t, s1)
+
+	// Create a legacy intention so it has an ID and legacy timestamps.
+	create := structs.IntentionRequest{
+		Datacenter: "dc1",
+		Op:         structs.IntentionOpCreate,
+		Intention: &structs.Intention{
+			SourceName:      "web",
+			DestinationName: "db",
+			Action:          structs.IntentionActionAllow,
+			Meta:            map[string]string{"team": "a"},
+		},
+	}
+	var id string
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Intention.Apply", &create, &id))
+	require.NotEmpty(t, id)
+
+	getByID := func(t *testing.T, id string) *structs.Intention {
+		req := &structs.IntentionQueryRequest{
+			Datacenter:  "dc1",
+			IntentionID: id,
+		}
+		var resp structs.IndexedIntentions
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Intention.Get", req, &resp))
+		require.Len(t, resp.Intentions, 1)
+		return resp.Intentions[0]
+	}
+
+	// Export it.
+	exported := getByID(t, id)
+	require.Equal(t, id, exported.ID)
+	require.False(t, exported.CreatedAt.IsZero())
+
+	// Delete it so the import has to recreate it.
+	del := structs.IntentionRequest{
+		Datacenter: "dc1",
+		Op:         structs.IntentionOpDelete,
+		Intention:  &structs.Intention{ID: id},
+	}
+	var ignored string
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Intention.Apply", &del, &ignored))
+
+	importReq := func(ixn *structs.Intention) structs.IntentionRequest {
+		ixn = ixn.Clone()
+		ixn.CreateIndex, ixn.ModifyIndex = 0, 0
+		return structs.IntentionRequest{
+			Datacenter: "dc1",
+			Op:         structs.IntentionOpImport,
+			Intention:  ixn,
+		}
+	}
+
+	t.Run("legacy fields are preserved", func(t *testing.T) {
+		req := importReq(exported)
+		var reply string
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Intention.Apply", &req, &reply))
+		require.Equal(t, id, reply)
+
+		imported := getByID(t, id)
+		require.Equal(t, id, imported.ID)
+		require.True(t, exported.CreatedAt.Equal(imported.CreatedAt), "%v != %v", exported.CreatedAt, imported.CreatedAt)
+		require.True(t, exported.UpdatedAt.Equal(imported.UpdatedAt), "%v != %v", exported.UpdatedAt, imported.UpdatedAt)
+		require.Equal(t, exported.Meta, imported.Meta)
+		require.Equal(t, exported.Action, imported.Action)
+
+		// The stored source must still be a legacy one.
+		_, entry, err := s1.fsm.State().ConfigEntry(nil, structs.ServiceIntentions, "db", nil)
+		require.NoError(t, err)
+		ixnEntry := entry.(*structs.ServiceIntentionsConfigEntry)
+		require.Len(t, ixnEntry.Sources, 1)
+		require.Equal(t, id, ixnEntry.Sources[0].LegacyID)
+		require.NotNil(t, ixnEntry.Sources[0].LegacyCreateTime)
+		require.True(t, exported.CreatedAt.Equal(*ixnEntry.Sources[0].LegacyCreateTime))
+	})
+
+	t.Run("duplicate IDs are rejected", func(t *testing.T) {
+		req := importReq(exported)
+		var reply string
+		err := msgpackrpc.CallWithCodec(codec, "Intention.Apply", &req, &reply)
+		testutil.RequireErrorContains(t, err, "already exists")
+	})
+
+	t.Run("intentions without an ID are upserted", func(t *testing.T) {
+		req := importReq(&structs.Intention{
+			SourceName:      "web",
+			DestinationName: "api",
+			Action:          structs.IntentionActionDeny,
+		})
+		var reply string
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Intention.Apply", &req, &reply))
+		require.Empty(t, reply)
+
+		_, entry, err := s1.fsm.State().ConfigEntry(nil, structs.ServiceIntentions, "api", nil)
+		require.NoError(t, err)
+		ixnEntry := entry.(*structs.ServiceIntentionsConfigEntry)
+		require.Len(t, ixnEntry.Sources, 1)
+		require.Empty(t, ixnEntry.Sources[0].LegacyID)
+	})
+}
+
+func TestIntentionApply_allowL7(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	waitForLeaderEstablishment(t, s1)
+
+	// Force the destinations to be L7-capable.
+	for _, name := range []string{"api", "db"} {
+		args := structs.ConfigEntryRequest{
+			Datacenter: "dc1",
+			Entry: &structs.ServiceConfigEntry{
+				Kind:     structs.ServiceDefaults,
+				Name:     name,
+				Protocol: "http",
+			},
+		}
+
+		var out bool
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConfigEntry.Apply", &args, &out))
+		require.True(t, out)
+	}
+
+	perms := []*structs.IntentionPermission{
+		{
+			Action: structs.IntentionActionAllow,
+			HTTP: &structs.IntentionHTTPPermission{
+				PathPrefix: "/api",
+				Methods:    []string{"GET", "PUT"},
+			},
+		},
+		{
+			Action: structs.IntentionActionDeny,
+			HTTP: &structs.IntentionHTTPPermission{
+				PathExact: "/admin",
+			},
+		},
+	}
+
+	createReq := func(dest string, allowL7 bool) *structs.IntentionRequest {
+		return &structs.IntentionRequest{
+			Datacenter: "dc1",
+			Op:         structs.IntentionOpCreate,
+			AllowL7:    allowL7,
+			Intention: &structs.Intention{
+				SourceName:      "web",
+				DestinationName: dest,
+				Permissions:     perms,
+			},
+		}
+	}
+
+	t.Run("rejected without opt-in", func(t *testing.T) {
+		var reply string
+		err := msgpackrpc.CallWithCodec(codec, "Intention.Apply", createReq("api", false), &reply)
+		testutil.RequireErrorContains(t, err, "Permissions must not be set")
+	})
+
+	t.Run("written as a config entry source", func(t *testing.T) {
+		var reply string
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Intention.Apply", createReq("api", true), &reply))
+		require.Empty(t, reply)
+
+		_, entry, err := s1.fsm.State().ConfigEntry(nil, structs.ServiceIntentions, "api", nil)
+		require.NoError(t, err)
+		ixnEntry := entry.(*structs.ServiceIntentionsConfigEntry)
+		require.Len(t, ixnEntry.Sources, 1)
+		src := ixnEntry.Sources[0]
+		require.Equal(t, "web", src.Name)
+		require.Empty(t, src.LegacyID)
+		require.Empty(t, src.Action)
+		require.Equal(t, perms, src.Permissions)
+		require.Equal(t, 9, src.Precedence)
+
+		// The permissions survive the round trip through the intention APIs.
+		req := &structs.IntentionQueryRequest{
+			Datacenter: "dc1",
+			Exact: &structs.IntentionQueryExact{
+				SourceNS:        structs.IntentionDefaultNamespace,
+				SourceName:      "web",
+				DestinationNS:   structs.IntentionDefaultNamespace,
+				DestinationName: "api",
+			},
+		}
+		var resp structs.IndexedIntentions
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Intention.Get", req, &resp))
+		require.Len(t, resp.Intentions, 1)
+		require.Equal(t, perms, resp.Intentions[0].Permissions)
+		require.Equal(t, 9, resp.Intentions[0].Precedence)
+	})
+
+	t.Run("existing intentions are not replaced", func(t *testing.T) {
+		var reply string
+		err := msgpackrpc.CallWithCodec(codec, "Intention.Apply", createReq("api", true), &reply)
+		testutil.RequireErrorContains(t, err, "already exists")
+	})
+
+	t.Run("destinations with legacy intentions are rejected", func(t *testing.T) {
+		legacy := &structs.IntentionRequest{
+			Datacenter: "dc1",
+			Op:         structs.IntentionOpCreate,
+			Intention: &structs.Intention{
+				SourceName:      "billing",
+				DestinationName: "db",
+				Action:          structs.IntentionActionAllow,
+			},
+		}
+		var id string
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Intention.Apply", legacy, &id))
+
+		var reply string
+		err := msgpackrpc.CallWithCodec(codec, "Intention.Apply", createReq("db", true), &reply)
+		testutil.RequireErrorContains(t, err, "legacy intention API")
+
+		update := &structs.IntentionRequest{
+			Datacenter: "dc1",
+			Op:         structs.IntentionOpUpdate,
+			AllowL7:    true,
+			Intention: &structs.Intention{
+				ID:              id,
+				SourceName:      "billing",
+				DestinationName: "db",
+				Permissions:     perms,
+			},
+		}
+		err = msgpackrpc.CallWithCodec(codec, "Intention.Apply", update, &reply)
+		testutil.RequireErrorContains(t, err, "cannot be added to an existing legacy intention")
+	})
+}
+
 func TestIntentionApply_updateGood(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -1620,6 +1871,63 @@ func TestIntentionList(t *testing.T) {
 	}
 }
 
+func TestIntentionList_sorted(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+	waitForLeaderEstablishment(t, s1)
+
+	// Insert in an order that differs from precedence order.
+	for _, v := range [][]string{
+		{"*", "*"},
+		{"*", "web"},
+		{"api", "web"},
+		{"api", "db"},
+	} {
+		ixn := structs.IntentionRequest{
+			Datacenter: "dc1",
+			Op:         structs.IntentionOpCreate,
+			Intention:  structs.TestIntention(t),
+		}
+		ixn.Intention.SourceName = v[0]
+		ixn.Intention.DestinationName = v[1]
+
+		var reply string
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Intention.Apply", &ixn, &reply))
+	}
+
+	var unsorted structs.IndexedIntentions
+	req := &structs.IntentionListRequest{Datacenter: "dc1"}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Intention.List", req, &unsorted))
+	require.Len(t, unsorted.Intentions, 4)
+
+	var sorted structs.IndexedIntentions
+	req.Sorted = true
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Intention.List", req, &sorted))
+	require.Equal(t, unsorted.Index, sorted.Index)
+
+	var actual [][]string
+	for _, ixn := range sorted.Intentions {
+		actual = append(actual, []string{ixn.SourceName, ixn.DestinationName})
+	}
+	expected := [][]string{
+		{"api", "db"},
+		{"api", "web"},
+		{"*", "web"},
+		{"*", "*"},
+	}
+	require.Equal(t, expected, actual)
+}
+
 // Test listing with ACLs
 func TestIntentionList_acl(t *testing.T) {
 	if testing.Short() {
@@ -1851,6 +2159,208 @@ func TestIntentionMatch_good(t *testing.T) {
 	require.Equal(t, expected, actual)
 }
 
+func TestIntentionPrecedence(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, s1 := testServerWithConfig(t, func(c *Config) {
+		c.DefaultIntentionPolicy = structs.IntentionDefaultPolicyDeny
+	})
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	waitForLeaderEstablishment(t, s1)
+
+	// Create some records
+	insert := []struct {
+		src, dst string
+		action   structs.IntentionAction
+	}{
+		{"*", "*", structs.IntentionActionAllow},
+		{"*", "bar", structs.IntentionActionDeny},
+		{"foo", "bar", structs.IntentionActionAllow},
+		{"foo", "baz", structs.IntentionActionAllow}, // shouldn't match
+	}
+	for _, v := range insert {
+		ixn := structs.IntentionRequest{
+			Datacenter: "dc1",
+			Op:         structs.IntentionOpCreate,
+			Intention: &structs.Intention{
+				SourceNS:        "default",
+				SourceName:      v.src,
+				DestinationNS:   "default",
+				DestinationName: v.dst,
+				Action:          v.action,
+			},
+		}
+		var reply string
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Intention.Apply", &ixn, &reply))
+	}
+
+	req := &structs.IntentionQueryRequest{
+		Datacenter: "dc1",
+		Precedence: &structs.IntentionMatchEntry{Name: "bar"},
+	}
+	var resp structs.IndexedIntentionPrecedence
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Intention.Precedence", req, &resp))
+
+	type rule struct {
+		src, dst string
+		action   structs.IntentionAction
+		dflt     bool
+	}
+	var actual []rule
+	for _, r := range resp.Rules {
+		if r.Intention == nil {
+			actual = append(actual, rule{action: r.Action, dflt: r.Default})
+			continue
+		}
+		require.Equal(t, r.Intention.Precedence, r.Precedence)
+		actual = append(actual, rule{src: r.Intention.SourceName, dst: r.Intention.DestinationName, action: r.Action})
+	}
+
+	// The evaluation order ends with the default policy.
+	expected := []rule{
+		{src: "foo", dst: "bar", action: structs.IntentionActionAllow},
+		{src: "*", dst: "bar", action: structs.IntentionActionDeny},
+		{src: "*", dst: "*", action: structs.IntentionActionAllow},
+		{action: structs.IntentionActionDeny, dflt: true},
+	}
+	require.Equal(t, expected, actual)
+
+	// A destination only matched by the wildcard intention falls through to
+	// the default policy after it.
+	req.Precedence = &structs.IntentionMatchEntry{Name: "qux"}
+	var wildcardResp structs.IndexedIntentionPrecedence
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Intention.Precedence", req, &wildcardResp))
+	require.Len(t, wildcardResp.Rules, 2)
+	require.Equal(t, "*", wildcardResp.Rules[0].Intention.DestinationName)
+	require.True(t, wildcardResp.Rules[1].Default)
+}
+
+func TestIntentionTopology(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, s1 := testServerWithConfig(t, func(c *Config) {
+		c.DefaultIntentionPolicy = structs.IntentionDefaultPolicyDeny
+	})
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	waitForLeaderEstablishment(t, s1)
+
+	for _, svc := range []string{"web", "db", "db-v2", "db-v3"} {
+		reg := structs.RegisterRequest{
+			Datacenter: "dc1",
+			Node:       "node1",
+			Address:    "127.0.0.1",
+			Service: &structs.NodeService{
+				Service: svc,
+				Port:    8080,
+			},
+		}
+		var out struct{}
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Catalog.Register", &reg, &out))
+	}
+
+	entries := []structs.ConfigEntryRequest{
+		{
+			Datacenter: "dc1",
+			Entry: &structs.ProxyConfigEntry{
+				Kind: structs.ProxyDefaults,
+				Name: structs.ProxyConfigGlobal,
+				Config: map[string]interface{}{
+					"protocol": "http",
+				},
+			},
+		},
+		{
+			Datacenter: "dc1",
+			Entry: &structs.ServiceRouterConfigEntry{
+				Kind: structs.ServiceRouter,
+				Name: "db",
+				Routes: []structs.ServiceRoute{
+					{
+						Match: &structs.ServiceRouteMatch{
+							HTTP: &structs.ServiceRouteHTTPMatch{PathPrefix: "/v2"},
+						},
+						Destination: &structs.ServiceRouteDestination{Service: "db-v2"},
+					},
+					{
+						Match: &structs.ServiceRouteMatch{
+							HTTP: &structs.ServiceRouteHTTPMatch{PathPrefix: "/v3"},
+						},
+						Destination: &structs.ServiceRouteDestination{Service: "db-v3"},
+					},
+				},
+			},
+		},
+	}
+	for _, req := range entries {
+		var out bool
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConfigEntry.Apply", &req, &out))
+	}
+
+	// web may connect to db and db-v2 but not to db-v3.
+	for _, dst := range []string{"db", "db-v2"} {
+		ixn := structs.IntentionRequest{
+			Datacenter: "dc1",
+			Op:         structs.IntentionOpCreate,
+			Intention: &structs.Intention{
+				SourceNS:        "default",
+				SourceName:      "web",
+				DestinationNS:   "default",
+				DestinationName: dst,
+				Action:          structs.IntentionActionAllow,
+			},
+		}
+		var reply string
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Intention.Apply", &ixn, &reply))
+	}
+
+	req := structs.ServiceSpecificRequest{
+		Datacenter:  "dc1",
+		ServiceName: "web",
+	}
+	var resp structs.IndexedIntentionTopology
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Intention.Topology", &req, &resp))
+	require.NotZero(t, resp.Index)
+
+	type target struct {
+		service string
+		allowed bool
+	}
+	actual := make(map[string][]target)
+	for _, upstream := range resp.Upstreams {
+		require.Equal(t, "http", upstream.Protocol)
+		for _, tgt := range upstream.Targets {
+			require.Equal(t, "dc1", tgt.Datacenter)
+			actual[upstream.Name] = append(actual[upstream.Name], target{tgt.Service, tgt.Allowed})
+		}
+	}
+
+	// The routed targets of db are part of the graph, with db-v3 reported
+	// as unreachable. db-v3 isn't an upstream itself.
+	expected := map[string][]target{
+		"db": {
+			{"db-v2", true},
+			{"db-v3", false},
+			{"db", true},
+		},
+		"db-v2": {
+			{"db-v2", true},
+		},
+	}
+	require.Equal(t, expected, actual)
+}
+
 func TestIntentionMatch_BlockOnNoChange(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -2233,6 +2743,266 @@ func TestIntentionCheck_match(t *testing.T) {
 	}
 }
 
+func TestIntentionCheck_sourcePeer(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, srv, codec := testACLServerWithConfig(t, nil, false)
+	waitForLeaderEstablishment(t, srv)
+
+	token, err := upsertTestTokenWithPolicyRules(codec, TestDefaultInitialManagementToken, "dc1", `service "api" { policy = "read" }`)
+	require.NoError(t, err)
+
+	// Allow web, but only when it is imported from peer1.
+	args := structs.ConfigEntryRequest{
+		Datacenter: "dc1",
+		Entry: &structs.ServiceIntentionsConfigEntry{
+			Kind: structs.ServiceIntentions,
+			Name: "api",
+			Sources: []*structs.SourceIntention{
+				{Name: "web", Peer: "peer1", Action: structs.IntentionActionAllow},
+			},
+		},
+		WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+	}
+	var applied bool
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConfigEntry.Apply", &args, &applied))
+	require.True(t, applied)
+
+	check := func(peer string) bool {
+		t.Helper()
+		req := &structs.IntentionQueryRequest{
+			Datacenter: "dc1",
+			Check: &structs.IntentionQueryCheck{
+				SourceName:      "web",
+				SourcePeer:      peer,
+				DestinationName: "api",
+				SourceType:      structs.IntentionSourceConsul,
+			},
+			QueryOptions: structs.QueryOptions{Token: token.SecretID},
+		}
+		var resp structs.IntentionQueryCheckResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Intention.Check", req, &resp))
+		return resp.Allowed
+	}
+
+	require.True(t, check("peer1"))
+	require.False(t, check(""))
+	require.False(t, check("peer2"))
+}
+
+func TestIntentionCheckBatch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, srv, codec := testACLServerWithConfig(t, nil, false)
+	waitForLeaderEstablishment(t, srv)
+
+	token, err := upsertTestTokenWithPolicyRules(codec, TestDefaultInitialManagementToken, "dc1", `
+service "api" { policy = "read" }
+service "db" { policy = "read" }`)
+	require.NoError(t, err)
+
+	// Create some intentions
+	{
+		insert := [][]string{
+			{"web", "api"},
+			{"*", "db"},
+		}
+
+		for _, v := range insert {
+			ixn := structs.IntentionRequest{
+				Datacenter: "dc1",
+				Op:         structs.IntentionOpCreate,
+				Intention: &structs.Intention{
+					SourceNS:        "default",
+					SourceName:      v[0],
+					DestinationNS:   "default",
+					DestinationName: v[1],
+					Action:          structs.IntentionActionAllow,
+				},
+				WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+			}
+			// Create
+			var reply string
+			require.NoError(t, msgpackrpc.CallWithCodec(codec, "Intention.Apply", &ixn, &reply))
+		}
+	}
+
+	check := func(src, dst string) *structs.IntentionQueryCheck {
+		return &structs.IntentionQueryCheck{
+			SourceName:      src,
+			DestinationName: dst,
+			SourceType:      structs.IntentionSourceConsul,
+		}
+	}
+
+	req := &structs.IntentionQueryCheckBatchRequest{
+		Datacenter: "dc1",
+		Checks: []*structs.IntentionQueryCheck{
+			check("web", "api"),
+			check("db", "api"),
+			check("cache", "db"),
+			check("web", "secret"),
+		},
+		QueryOptions: structs.QueryOptions{Token: token.SecretID},
+	}
+	var resp structs.IntentionQueryCheckBatchResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Intention.CheckBatch", req, &resp))
+	require.Len(t, resp.Results, 4)
+
+	// Exact match, default deny and wildcard match.
+	require.Equal(t, &structs.IntentionQueryCheckResponse{Allowed: true}, resp.Results[0])
+	require.Equal(t, &structs.IntentionQueryCheckResponse{Allowed: false}, resp.Results[1])
+	require.Equal(t, &structs.IntentionQueryCheckResponse{Allowed: true}, resp.Results[2])
+
+	// The token cannot read the destination, so no decision is returned.
+	require.False(t, resp.Results[3].Allowed)
+	require.Contains(t, resp.Results[3].Error, acl.ErrPermissionDenied.Error())
+
+	// The results match individual checks.
+	for i, c := range req.Checks[:3] {
+		single := &structs.IntentionQueryRequest{
+			Datacenter:   "dc1",
+			Check:        check(c.SourceName, c.DestinationName),
+			QueryOptions: structs.QueryOptions{Token: token.SecretID},
+		}
+		var singleResp structs.IntentionQueryCheckResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Intention.Check", single, &singleResp))
+		require.Equal(t, singleResp.Allowed, resp.Results[i].Allowed)
+	}
+
+	// A nil check fails the whole request.
+	req.Checks = append(req.Checks, nil)
+	err = msgpackrpc.CallWithCodec(codec, "Intention.CheckBatch", req, &resp)
+	require.ErrorContains(t, err, "Check 4 must not be empty")
+}
+
+func TestIntentionSimulate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	_, srv, codec := testACLServerWithConfig(t, nil, false)
+	waitForLeaderEstablishment(t, srv)
+
+	for _, svc := range []string{"web", "api", "db"} {
+		arg := structs.RegisterRequest{
+			Datacenter: "dc1",
+			Node:       "node1",
+			Address:    "127.0.0.1",
+			Service: &structs.NodeService{
+				Service: svc,
+				Port:    8080,
+			},
+			WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+		}
+		var out struct{}
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Catalog.Register", &arg, &out))
+	}
+
+	// Only web => api is allowed today.
+	ixn := structs.IntentionRequest{
+		Datacenter: "dc1",
+		Op:         structs.IntentionOpCreate,
+		Intention: &structs.Intention{
+			SourceNS:        "default",
+			SourceName:      "web",
+			DestinationNS:   "default",
+			DestinationName: "api",
+			Action:          structs.IntentionActionAllow,
+		},
+		WriteRequest: structs.WriteRequest{Token: TestDefaultInitialManagementToken},
+	}
+	var id string
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Intention.Apply", &ixn, &id))
+
+	pair := func(src, dst string) structs.IntentionSimulatePair {
+		return structs.IntentionSimulatePair{
+			Source:      structs.NewServiceName(src, nil),
+			Destination: structs.NewServiceName(dst, nil),
+		}
+	}
+	simulate := func(t *testing.T, token string, entry *structs.ServiceIntentionsConfigEntry) (*structs.IntentionSimulateResponse, error) {
+		req := &structs.IntentionSimulateRequest{
+			Datacenter:   "dc1",
+			Entry:        entry,
+			QueryOptions: structs.QueryOptions{Token: token},
+		}
+		var resp structs.IntentionSimulateResponse
+		err := msgpackrpc.CallWithCodec(codec, "Intention.Simulate", req, &resp)
+		return &resp, err
+	}
+
+	t.Run("new allow", func(t *testing.T) {
+		resp, err := simulate(t, TestDefaultInitialManagementToken, &structs.ServiceIntentionsConfigEntry{
+			Kind: structs.ServiceIntentions,
+			Name: "db",
+			Sources: []*structs.SourceIntention{
+				{Name: "web", Action: structs.IntentionActionAllow},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []structs.IntentionSimulatePair{pair("web", "db")}, resp.NewlyAllowed)
+		require.Empty(t, resp.NewlyDenied)
+	})
+
+	t.Run("replaced entry", func(t *testing.T) {
+		resp, err := simulate(t, TestDefaultInitialManagementToken, &structs.ServiceIntentionsConfigEntry{
+			Kind: structs.ServiceIntentions,
+			Name: "api",
+			Sources: []*structs.SourceIntention{
+				{Name: "db", Action: structs.IntentionActionAllow},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []structs.IntentionSimulatePair{pair("db", "api")}, resp.NewlyAllowed)
+		require.Equal(t, []structs.IntentionSimulatePair{pair("web", "api")}, resp.NewlyDenied)
+	})
+
+	t.Run("wildcard destination", func(t *testing.T) {
+		resp, err := simulate(t, TestDefaultInitialManagementToken, &structs.ServiceIntentionsConfigEntry{
+			Kind: structs.ServiceIntentions,
+			Name: "*",
+			Sources: []*structs.SourceIntention{
+				{Name: "db", Action: structs.IntentionActionAllow},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []structs.IntentionSimulatePair{pair("db", "api"), pair("db", "web")}, resp.NewlyAllowed)
+		require.Empty(t, resp.NewlyDenied)
+	})
+
+	t.Run("nothing is written", func(t *testing.T) {
+		_, entry, err := srv.fsm.State().ConfigEntry(nil, structs.ServiceIntentions, "db", nil)
+		require.NoError(t, err)
+		require.Nil(t, entry)
+	})
+
+	t.Run("requires intention read", func(t *testing.T) {
+		token, err := upsertTestTokenWithPolicyRules(codec, TestDefaultInitialManagementToken, "dc1", `
+service "db" { policy = "read" }`)
+		require.NoError(t, err)
+
+		_, err = simulate(t, token.SecretID, &structs.ServiceIntentionsConfigEntry{
+			Kind: structs.ServiceIntentions,
+			Name: "api",
+			Sources: []*structs.SourceIntention{
+				{Name: "web", Action: structs.IntentionActionAllow},
+			},
+		})
+		require.True(t, acl.IsErrPermissionDenied(err), "expected permission denied, got %v", err)
+	})
+}
+
 func TestEqualStringMaps(t *testing.T) {
 	m1 := map[string]string{
 		"foo": "a",
@@ -0,0 +1,66 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package consul
+
+import (
+	"sync"
+
+	"github.com/dhiaayachi/consul/agent/structs"
+)
+
+// intentionMatchKey identifies an intention by its source and destination,
+// which stays stable across updates to its action or permissions. Intentions
+// stored as config entries do not have a stable ID.
+type intentionMatchKey struct {
+	SourcePeer           string
+	SourceSamenessGroup  string
+	SourcePartition      string
+	SourceNS             string
+	SourceName           string
+	DestinationPartition string
+	DestinationNS        string
+	DestinationName      string
+}
+
+func newIntentionMatchKey(ixn *structs.Intention) intentionMatchKey {
+	src, dst := ixn.SourceEnterpriseMeta(), ixn.DestinationEnterpriseMeta()
+	return intentionMatchKey{
+		SourcePeer:           ixn.SourcePeer,
+		SourceSamenessGroup:  ixn.SourceSamenessGroup,
+		SourcePartition:      src.PartitionOrDefault(),
+		SourceNS:             src.NamespaceOrDefault(),
+		SourceName:           ixn.SourceName,
+		DestinationPartition: dst.PartitionOrDefault(),
+		DestinationNS:        dst.NamespaceOrDefault(),
+		DestinationName:      ixn.DestinationName,
+	}
+}
+
+// intentionMatchStats keeps in-memory counts of how many intention checks
+// were decided by each intention. The counts are local to a server and are
+// reset when it restarts. The zero value is ready to use.
+type intentionMatchStats struct {
+	lock   sync.Mutex
+	counts map[intentionMatchKey]uint64
+}
+
+// record counts a decision that was made by the given intention.
+func (s *intentionMatchStats) record(ixn *structs.Intention) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.counts == nil {
+		s.counts = make(map[intentionMatchKey]uint64)
+	}
+	s.counts[newIntentionMatchKey(ixn)]++
+}
+
+// matches returns the number of decisions that were made by the given
+// intention.
+func (s *intentionMatchStats) matches(ixn *structs.Intention) uint64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.counts[newIntentionMatchKey(ixn)]
+}
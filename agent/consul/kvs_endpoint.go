@@ -12,6 +12,7 @@ import (
 	"github.com/armon/go-metrics/prometheus"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/go-version"
 
 	"github.com/dhiaayachi/consul/acl"
 	"github.com/dhiaayachi/consul/acl/resolver"
@@ -20,6 +21,18 @@ import (
 	"github.com/dhiaayachi/consul/api"
 )
 
+var (
+	// minKVLockTransferVersion is the lowest server version whose FSM can
+	// apply the lock-transfer operation. Older servers would reject it as an
+	// invalid operation while newer ones apply it.
+	minKVLockTransferVersion = version.Must(version.NewVersion("1.22.0"))
+
+	// minKVTTLVersion is the lowest server version whose FSM stores the
+	// expiration time of a key. Older servers would drop it and never expire
+	// the key while newer ones delete it.
+	minKVTTLVersion = version.Must(version.NewVersion("1.22.0"))
+)
+
 var KVSummaries = []prometheus.SummaryDefinition{
 	{
 		Name: []string{"kvs", "apply"},
@@ -67,7 +80,7 @@ func kvsPreApply(logger hclog.Logger, srv *Server, authz resolver.Result, op api
 			return false, err
 		}
 
-	case api.KVCheckNotExists, api.KVUnlock, api.KVLock, api.KVCAS, api.KVDeleteCAS, api.KVDelete, api.KVSet:
+	case api.KVCheckNotExists, api.KVUnlock, api.KVLock, api.KVLockTransfer, api.KVCAS, api.KVDeleteCAS, api.KVDelete, api.KVSet:
 		var authzContext acl.AuthorizerContext
 		dirEnt.FillAuthzContext(&authzContext)
 
@@ -78,6 +91,21 @@ func kvsPreApply(logger hclog.Logger, srv *Server, authz resolver.Result, op api
 		return false, fmt.Errorf("unknown KV operation: %s", op)
 	}
 
+	// Like lock-delay, expiration is based on wall-time, so the expiration
+	// time is computed here on the leader and committed with the entry.
+	dirEnt.ExpirationTime = nil
+	if dirEnt.ExpirationTTL < 0 {
+		return false, fmt.Errorf("Invalid key TTL %q: must not be negative", dirEnt.ExpirationTTL)
+	}
+	if dirEnt.ExpirationTTL > 0 {
+		if ok, _ := ServersInDCMeetMinimumVersion(srv, srv.config.Datacenter, minKVTTLVersion); !ok {
+			return false, fmt.Errorf("can't set key TTLs until all servers >= %s",
+				minKVTTLVersion.String())
+		}
+		expires := time.Now().Add(dirEnt.ExpirationTTL)
+		dirEnt.ExpirationTime = &expires
+	}
+
 	// If this is a lock, we must check for a lock-delay. Since lock-delay
 	// is based on wall-time, each peer would expire the lock-delay at a slightly
 	// different time. This means the enforcement of lock-delay cannot be done
@@ -106,6 +134,33 @@ func (k *KVS) Apply(args *structs.KVSRequest, reply *bool) error {
 	}
 	defer metrics.MeasureSince([]string{"kvs", "apply"}, time.Now())
 
+	var out structs.KVSApplyResponse
+	if err := k.apply(args, &out); err != nil {
+		return err
+	}
+	*reply = out.Success
+	return nil
+}
+
+// ApplyWithResult is like Apply but also returns the number of keys removed
+// by a delete-tree operation.
+func (k *KVS) ApplyWithResult(args *structs.KVSRequest, reply *structs.KVSApplyResponse) error {
+	if done, err := k.srv.ForwardRPC("KVS.ApplyWithResult", args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"kvs", "apply"}, time.Now())
+
+	return k.apply(args, reply)
+}
+
+func (k *KVS) apply(args *structs.KVSRequest, reply *structs.KVSApplyResponse) error {
+	if args.Op == api.KVLockTransfer {
+		if ok, _ := ServersInDCMeetMinimumVersion(k.srv, k.srv.config.Datacenter, minKVLockTransferVersion); !ok {
+			return fmt.Errorf("can't transfer locks until all servers >= %s",
+				minKVLockTransferVersion.String())
+		}
+	}
+
 	// Perform the pre-apply checks.
 	authz, err := k.srv.ResolveTokenAndDefaultMeta(args.Token, &args.DirEnt.EnterpriseMeta, nil)
 	if err != nil {
@@ -121,7 +176,7 @@ func (k *KVS) Apply(args *structs.KVSRequest, reply *bool) error {
 		return err
 	}
 	if !ok {
-		*reply = false
+		reply.Success = false
 		return nil
 	}
 
@@ -131,9 +186,15 @@ func (k *KVS) Apply(args *structs.KVSRequest, reply *bool) error {
 		return fmt.Errorf("raft apply failed: %w", err)
 	}
 
-	// Check if the return type is a bool.
-	if respBool, ok := resp.(bool); ok {
-		*reply = respBool
+	// Check the return type, which is a bool for most operations and the
+	// number of deleted keys for delete-tree, which always succeeds once
+	// applied.
+	switch v := resp.(type) {
+	case bool:
+		reply.Success = v
+	case int:
+		reply.Success = true
+		reply.Deleted = v
 	}
 	return nil
 }
@@ -204,11 +265,22 @@ func (k *KVS) List(args *structs.KeyRequest, reply *structs.IndexedDirEntries) e
 		&args.QueryOptions,
 		&reply.QueryMeta,
 		func(ws memdb.WatchSet, state *state.Store) error {
-			index, ent, err := state.KVSList(ws, args.Key, &args.EnterpriseMeta)
+			var index uint64
+			var ent structs.DirEntries
+			var err error
+			if args.Session != "" {
+				index, ent, err = state.KVSListBySession(ws, args.Session, args.Key, &args.EnterpriseMeta)
+			} else {
+				index, ent, err = state.KVSList(ws, args.Key, &args.EnterpriseMeta)
+			}
 			if err != nil {
 				return err
 			}
 
+			if args.Flags != nil {
+				ent = filterDirEntFlags(ent, *args.Flags)
+			}
+
 			total := len(ent)
 			ent = FilterDirEnt(authz, ent)
 			reply.QueryMeta.ResultsFilteredByACLs = total != len(ent)
@@ -230,6 +302,17 @@ func (k *KVS) List(args *structs.KeyRequest, reply *structs.IndexedDirEntries) e
 		})
 }
 
+// filterDirEntFlags returns the entries whose flags equal flags.
+func filterDirEntFlags(ent structs.DirEntries, flags uint64) structs.DirEntries {
+	filtered := ent[:0]
+	for _, e := range ent {
+		if e.Flags == flags {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
 // ListKeys is used to list all keys with a given prefix to a separator.
 // An optional separator may be specified, which can be used to slice off a part
 // of the response so that only a subset of the prefix is returned. In this
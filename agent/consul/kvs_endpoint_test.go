@@ -132,6 +132,58 @@ func TestKVS_Apply_ACLDeny(t *testing.T) {
 	}
 }
 
+func TestKVS_ApplyWithResult(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	for _, key := range []string{"test/a", "test/b", "test/c/d", "other"} {
+		arg := structs.KVSRequest{
+			Datacenter: "dc1",
+			Op:         api.KVSet,
+			DirEnt: structs.DirEntry{
+				Key:   key,
+				Value: []byte("test"),
+			},
+		}
+		var out structs.KVSApplyResponse
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.ApplyWithResult", &arg, &out))
+		require.Zero(t, out.Deleted)
+	}
+
+	arg := structs.KVSRequest{
+		Datacenter: "dc1",
+		Op:         api.KVDeleteTree,
+		DirEnt: structs.DirEntry{
+			Key: "test",
+		},
+	}
+	var out structs.KVSApplyResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.ApplyWithResult", &arg, &out))
+	require.True(t, out.Success)
+	require.Equal(t, 3, out.Deleted)
+
+	// Nothing is left under the prefix.
+	out = structs.KVSApplyResponse{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.ApplyWithResult", &arg, &out))
+	require.True(t, out.Success)
+	require.Zero(t, out.Deleted)
+
+	// KVS.Apply still returns a plain bool.
+	var ok bool
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.Apply", &arg, &ok))
+	require.True(t, ok)
+}
+
 func TestKVS_Get(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -308,6 +360,108 @@ func TestKVSEndpoint_List(t *testing.T) {
 	}
 }
 
+func TestKVSEndpoint_List_Flags(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForTestAgent(t, s1.RPC, "dc1")
+
+	keyFlags := map[string]uint64{
+		"test/key1":     0,
+		"test/key2":     42,
+		"test/sub/key3": 42,
+		"test/sub/key4": 7,
+	}
+	for key, flags := range keyFlags {
+		arg := structs.KVSRequest{
+			Datacenter: "dc1",
+			Op:         api.KVSet,
+			DirEnt: structs.DirEntry{
+				Key:   key,
+				Flags: flags,
+			},
+		}
+		var out bool
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.Apply", &arg, &out))
+	}
+
+	list := func(t *testing.T, flags uint64) []string {
+		getR := structs.KeyRequest{
+			Datacenter: "dc1",
+			Key:        "test",
+			Flags:      &flags,
+		}
+		var dirent structs.IndexedDirEntries
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.List", &getR, &dirent))
+		require.NotZero(t, dirent.Index)
+
+		var keys []string
+		for _, d := range dirent.Entries {
+			require.Equal(t, flags, d.Flags)
+			keys = append(keys, d.Key)
+		}
+		return keys
+	}
+
+	require.Equal(t, []string{"test/key2", "test/sub/key3"}, list(t, 42))
+	require.Equal(t, []string{"test/key1"}, list(t, 0))
+	require.Empty(t, list(t, 1))
+}
+
+func TestKVSEndpoint_List_Session(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForTestAgent(t, s1.RPC, "dc1")
+
+	state := s1.fsm.State()
+	require.NoError(t, state.EnsureNode(1, &structs.Node{Node: "foo", Address: "127.0.0.1"}))
+	session := generateUUID()
+	require.NoError(t, state.SessionCreate(2, &structs.Session{ID: session, Node: "foo"}))
+
+	for _, key := range []string{"test/key1", "test/key2", "other"} {
+		arg := structs.KVSRequest{
+			Datacenter: "dc1",
+			Op:         api.KVSet,
+			DirEnt:     structs.DirEntry{Key: key},
+		}
+		if key != "test/key2" {
+			arg.Op = api.KVLock
+			arg.DirEnt.Session = session
+		}
+		var out bool
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.Apply", &arg, &out))
+	}
+
+	getR := structs.KeyRequest{
+		Datacenter: "dc1",
+		Key:        "test",
+		Session:    session,
+	}
+	var dirent structs.IndexedDirEntries
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.List", &getR, &dirent))
+	require.NotZero(t, dirent.Index)
+	require.Len(t, dirent.Entries, 1)
+	require.Equal(t, "test/key1", dirent.Entries[0].Key)
+	require.Equal(t, session, dirent.Entries[0].Session)
+}
+
 func TestKVSEndpoint_List_Blocking(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -828,6 +982,115 @@ func TestKVS_Apply_LockDelay(t *testing.T) {
 	}
 }
 
+func TestKVS_Apply_LockTransfer(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForTestAgent(t, s1.RPC, "dc1")
+
+	state := s1.fsm.State()
+	require.NoError(t, state.EnsureNode(1, &structs.Node{Node: "foo", Address: "127.0.0.1"}))
+	outgoing, incoming := generateUUID(), generateUUID()
+	require.NoError(t, state.SessionCreate(2, &structs.Session{ID: outgoing, Node: "foo"}))
+	require.NoError(t, state.SessionCreate(3, &structs.Session{ID: incoming, Node: "foo"}))
+	ok, err := state.KVSLock(4, &structs.DirEntry{Key: "leader", Session: outgoing})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	arg := structs.KVSRequest{
+		Datacenter: "dc1",
+		Op:         api.KVLockTransfer,
+		DirEnt: structs.DirEntry{
+			Key:     "leader",
+			Value:   []byte("incoming"),
+			Session: incoming,
+		},
+	}
+
+	// The incoming session doesn't hold the lock, so it can't hand it over.
+	arg.LockHolder = incoming
+	var out bool
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.Apply", &arg, &out))
+	require.False(t, out)
+
+	// The outgoing session can.
+	arg.LockHolder = outgoing
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.Apply", &arg, &out))
+	require.True(t, out)
+
+	_, d, err := state.KVSGet(nil, "leader", nil)
+	require.NoError(t, err)
+	require.Equal(t, incoming, d.Session)
+	require.Equal(t, uint64(2), d.LockIndex)
+	require.Equal(t, "incoming", string(d.Value))
+}
+
+func TestKVS_Apply_LockTransfer_MinimumVersion(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	_, s1 := testServerWithConfig(t, func(c *Config) {
+		c.Build = "1.21.0"
+	})
+	codec := rpcClient(t, s1)
+
+	testrpc.WaitForTestAgent(t, s1.RPC, "dc1")
+
+	arg := structs.KVSRequest{
+		Datacenter: "dc1",
+		Op:         api.KVLockTransfer,
+		DirEnt: structs.DirEntry{
+			Key:     "leader",
+			Session: generateUUID(),
+		},
+		LockHolder: generateUUID(),
+	}
+	var out bool
+	err := msgpackrpc.CallWithCodec(codec, "KVS.Apply", &arg, &out)
+	require.ErrorContains(t, err, "can't transfer locks until all servers >= 1.22.0")
+}
+
+func TestKVS_Apply_TTL_MinimumVersion(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	_, s1 := testServerWithConfig(t, func(c *Config) {
+		c.Build = "1.21.0"
+	})
+	codec := rpcClient(t, s1)
+
+	testrpc.WaitForTestAgent(t, s1.RPC, "dc1")
+
+	arg := structs.KVSRequest{
+		Datacenter: "dc1",
+		Op:         api.KVSet,
+		DirEnt: structs.DirEntry{
+			Key:           "test",
+			Value:         []byte("test"),
+			ExpirationTTL: time.Hour,
+		},
+	}
+	var out bool
+	err := msgpackrpc.CallWithCodec(codec, "KVS.Apply", &arg, &out)
+	require.ErrorContains(t, err, "can't set key TTLs until all servers >= 1.22.0")
+
+	// Keys without a TTL can still be written.
+	arg.DirEnt.ExpirationTTL = 0
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.Apply", &arg, &out))
+}
+
 func TestKVS_Issue_1626(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -0,0 +1,86 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package consul
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/dhiaayachi/consul/agent/structs"
+	"github.com/dhiaayachi/consul/api"
+)
+
+const (
+	// kvsReapingRateLimit is the number of expired key reaping passes per
+	// second allowed.
+	kvsReapingRateLimit rate.Limit = 1.0
+
+	// kvsReapingBurst is the number of expired key reaping passes per second
+	// that can be handled in a burst.
+	kvsReapingBurst = 5
+
+	// kvsReapingBatchSize is the maximum number of expired keys deleted in a
+	// single reaping pass.
+	kvsReapingBatchSize = 256
+)
+
+func (s *Server) reapExpiredKeys(ctx context.Context) error {
+	limiter := rate.NewLimiter(kvsReapingRateLimit, kvsReapingBurst)
+	for {
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+
+		if _, err := s.reapExpiredKVs(time.Now()); err != nil {
+			s.logger.Error("error reaping expired keys", "error", err)
+		}
+	}
+}
+
+func (s *Server) startKVSReaping(ctx context.Context) {
+	s.leaderRoutineManager.Start(ctx, kvsReapingRoutineName, s.reapExpiredKeys)
+}
+
+func (s *Server) stopKVSReaping() {
+	s.leaderRoutineManager.Stop(kvsReapingRoutineName)
+}
+
+// reapExpiredKVs deletes the keys that expired before now and returns how
+// many were deleted. Each key is deleted with a check-and-set on the index it
+// was read at, so a key that is written again in the meantime is kept with
+// its new expiration time.
+func (s *Server) reapExpiredKVs(now time.Time) (int, error) {
+	entries, err := s.fsm.State().KVSListExpired(now, kvsReapingBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var deleted int
+	for _, entry := range entries {
+		req := structs.KVSRequest{
+			Datacenter: s.config.Datacenter,
+			Op:         api.KVDeleteCAS,
+			DirEnt: structs.DirEntry{
+				Key:            entry.Key,
+				RaftIndex:      structs.RaftIndex{ModifyIndex: entry.ModifyIndex},
+				EnterpriseMeta: entry.EnterpriseMeta,
+			},
+		}
+		resp, err := s.leaderRaftApply("KVS.Apply", structs.KVSRequestType, &req)
+		if err != nil {
+			return deleted, fmt.Errorf("failed to delete expired key %q: %v", entry.Key, err)
+		}
+		if ok, _ := resp.(bool); ok {
+			deleted++
+		}
+	}
+
+	if deleted > 0 {
+		s.logger.Info("deleted expired keys", "amount", deleted)
+	}
+	return deleted, nil
+}
@@ -45,6 +45,17 @@ var LeaderSummaries = []prometheus.SummaryDefinition{
 		Name: []string{"leader", "reapTombstones"},
 		Help: "Measures the time spent clearing tombstones.",
 	},
+	{
+		Name: []string{"leader", "config_bootstrap", "duration"},
+		Help: "Measures the time spent applying the bootstrap config entries upon gaining leadership.",
+	},
+}
+
+var LeaderCounters = []prometheus.CounterDefinition{
+	{
+		Name: []string{"leader", "config_bootstrap", "failures"},
+		Help: "Increments whenever the bootstrap config entries fail to apply upon gaining leadership.",
+	},
 }
 
 const (
@@ -323,6 +334,8 @@ func (s *Server) establishLeadership(ctx context.Context) error {
 
 	s.startDeferredDeletion(ctx)
 
+	s.startKVSReaping(ctx)
+
 	if err := s.startConnectLeader(ctx); err != nil {
 		return err
 	}
@@ -379,6 +392,8 @@ func (s *Server) revokeLeadership() {
 
 	s.stopACLTokenReaping()
 
+	s.stopKVSReaping()
+
 	s.resetConsistentReadReady()
 
 	s.autopilot.DisableReconciliation()
@@ -876,7 +891,13 @@ func (s *Server) getOrCreateAutopilotConfig() *structs.AutopilotConfig {
 	return config
 }
 
-func (s *Server) bootstrapConfigEntries(entries []structs.ConfigEntry) error {
+func (s *Server) bootstrapConfigEntries(entries []structs.ConfigEntry) (err error) {
+	defer func() {
+		if err != nil {
+			metrics.IncrCounter([]string{"leader", "config_bootstrap", "failures"}, 1)
+		}
+	}()
+
 	if s.config.PrimaryDatacenter != "" && s.config.PrimaryDatacenter != s.config.Datacenter {
 		// only bootstrap in the primary datacenter
 		return nil
@@ -926,6 +947,7 @@ func (s *Server) bootstrapConfigEntries(entries []structs.ConfigEntry) error {
 		}
 	}
 
+	defer metrics.MeasureSince([]string{"leader", "config_bootstrap", "duration"}, time.Now())
 	for _, entry := range entries {
 		// avoid a round trip through Raft if we know the CAS is going to fail
 		_, existing, err := state.ConfigEntry(nil, entry.GetKind(), entry.GetName(), entry.GetEnterpriseMeta())
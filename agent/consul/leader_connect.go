@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/go-version"
 
 	"github.com/dhiaayachi/consul/agent/consul/gateways"
+	"github.com/dhiaayachi/consul/agent/consul/state"
 	"github.com/dhiaayachi/consul/agent/structs"
 	"github.com/dhiaayachi/consul/logging"
 )
@@ -43,6 +44,16 @@ var (
 	// virtualIPVersionCheckInterval is the frequency we check whether all servers meet
 	// the minimum version to enable virtual IP assignment for services.
 	virtualIPVersionCheckInterval = time.Minute
+
+	// virtualIPReclaimInterval is the frequency we check for virtual IPs that
+	// are still assigned to services with no remaining registrations or
+	// references, so they can be returned to the pool.
+	virtualIPReclaimInterval = 10 * time.Minute
+
+	// minVirtualIPReclaimVersion is the minimum version for all Consul servers
+	// before the leader reclaims orphaned virtual IPs, as older servers can't
+	// apply the reclaim request.
+	minVirtualIPReclaimVersion = version.Must(version.NewVersion("1.22.0"))
 )
 
 // startConnectLeader starts multi-dc connect leader routines.
@@ -56,6 +67,7 @@ func (s *Server) startConnectLeader(ctx context.Context) error {
 	s.leaderRoutineManager.Start(ctx, caRootMetricRoutineName, rootCAExpiryMonitor(s).Monitor)
 	s.leaderRoutineManager.Start(ctx, caSigningMetricRoutineName, signingCAExpiryMonitor(s).Monitor)
 	s.leaderRoutineManager.Start(ctx, virtualIPCheckRoutineName, s.runVirtualIPVersionCheck)
+	s.leaderRoutineManager.Start(ctx, virtualIPReclaimRoutineName, s.runVirtualIPReclaim)
 	s.leaderRoutineManager.Start(ctx, configEntryControllersRoutineName, s.runConfigEntryControllers)
 
 	return s.startIntentionConfigEntryMigration(ctx)
@@ -69,6 +81,7 @@ func (s *Server) stopConnectLeader() {
 	s.leaderRoutineManager.Stop(caRootMetricRoutineName)
 	s.leaderRoutineManager.Stop(caSigningMetricRoutineName)
 	s.leaderRoutineManager.Stop(virtualIPCheckRoutineName)
+	s.leaderRoutineManager.Stop(virtualIPReclaimRoutineName)
 	s.leaderRoutineManager.Stop(configEntryControllersRoutineName)
 }
 
@@ -193,7 +206,53 @@ func (s *Server) runVirtualIPVersionCheck(ctx context.Context) error {
 	}
 }
 
+// runVirtualIPReclaim periodically frees the virtual IPs of services that no
+// longer have any registrations, config entries or gateways referencing them.
+// These are normally freed when the last reference goes away, but this pass
+// makes sure addresses leaked along the way are eventually returned to the pool.
+func (s *Server) runVirtualIPReclaim(ctx context.Context) error {
+	ticker := time.NewTicker(virtualIPReclaimInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := s.reclaimVirtualIPs(); err != nil {
+				s.loggers.Named(logging.Connect).Warn("error reclaiming virtual IPs", "error", err)
+			}
+		}
+	}
+}
+
+func (s *Server) reclaimVirtualIPs() error {
+	if ok, _ := ServersInDCMeetMinimumVersion(s, s.config.Datacenter, minVirtualIPReclaimVersion); !ok {
+		return nil
+	}
+
+	orphaned, err := s.fsm.State().OrphanedServiceVirtualIPs()
+	if err != nil {
+		return err
+	}
+	if len(orphaned) == 0 {
+		return nil
+	}
+
+	s.loggers.Named(logging.Connect).Debug("reclaiming virtual IPs", "services", len(orphaned))
+	_, err = s.raftApply(structs.ReclaimVirtualIPsRequestType, &structs.ReclaimVirtualIPsRequest{
+		Services: orphaned,
+	})
+	return err
+}
+
 func (s *Server) setVirtualIPFlags() (bool, error) {
+	// The range has to be in place before virtual IPs are enabled so that
+	// none get assigned from the default range by mistake.
+	if err := s.setVirtualIPCIDR(); err != nil {
+		return false, err
+	}
+
 	virtualIPFlag, err := s.setVirtualIPVersionFlag()
 	if err != nil {
 		return false, err
@@ -227,6 +286,47 @@ func (s *Server) setVirtualIPVersionFlag() (bool, error) {
 	return true, nil
 }
 
+// setVirtualIPCIDR stores the configured virtual IP range in the system
+// metadata so that every server's state store allocates from the same range.
+// Virtual IPs are stored as offsets into the range, so once any have been
+// allocated the stored range is kept as-is. A leader without an explicit range
+// never changes the stored one.
+func (s *Server) setVirtualIPCIDR() error {
+	if s.config.VirtualIPCIDR == nil {
+		return nil
+	}
+
+	val, err := s.GetSystemMetadata(structs.SystemMetadataVirtualIPCIDR)
+	if err != nil {
+		return err
+	}
+
+	cidr := s.config.VirtualIPCIDR.String()
+	if cidr == val {
+		return nil
+	}
+
+	current := val
+	if current == "" {
+		current = state.DefaultVirtualIPCIDR.String()
+	}
+	if cidr != current {
+		allocated, err := s.fsm.State().VirtualIPsAllocated()
+		if err != nil {
+			return err
+		}
+		if allocated {
+			s.logger.Warn("Ignoring virtual_ip_cidr because virtual IPs have already been allocated from another range",
+				"virtual_ip_cidr", cidr,
+				"current", current,
+			)
+			return nil
+		}
+	}
+
+	return s.SetSystemMetadataKey(structs.SystemMetadataVirtualIPCIDR, cidr)
+}
+
 func (s *Server) setVirtualIPTerminatingGatewayVersionFlag() (bool, error) {
 	val, err := s.GetSystemMetadata(structs.SystemMetadataTermGatewayVirtualIPsEnabled)
 	if err != nil {
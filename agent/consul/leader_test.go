@@ -9,11 +9,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/armon/go-metrics"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 
@@ -23,6 +25,7 @@ import (
 	"github.com/hashicorp/serf/serf"
 
 	"github.com/dhiaayachi/consul/acl"
+	"github.com/dhiaayachi/consul/agent/consul/state"
 	"github.com/dhiaayachi/consul/agent/structs"
 	tokenStore "github.com/dhiaayachi/consul/agent/token"
 	"github.com/dhiaayachi/consul/api"
@@ -97,6 +100,62 @@ func TestLeader_TombstoneGC_Reset(t *testing.T) {
 	})
 }
 
+func TestLeader_ReapExpiredKeys(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	for key, ttl := range map[string]time.Duration{"expiring": 500 * time.Millisecond, "forever": 0} {
+		arg := structs.KVSRequest{
+			Datacenter: "dc1",
+			Op:         api.KVSet,
+			DirEnt: structs.DirEntry{
+				Key:           key,
+				Value:         []byte("test"),
+				ExpirationTTL: ttl,
+			},
+		}
+		var out bool
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.Apply", &arg, &out))
+	}
+
+	// The leader sets the expiration time from the TTL.
+	store := s1.fsm.State()
+	_, entry, err := store.KVSGet(nil, "expiring", nil)
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	require.True(t, entry.HasExpirationTime())
+
+	// The expiring key is deleted like a normal delete, leaving a tombstone.
+	retry.Run(t, func(r *retry.R) {
+		_, entry, err := store.KVSGet(nil, "expiring", nil)
+		require.NoError(r, err)
+		require.Nil(r, entry)
+	})
+
+	snap := store.Snapshot()
+	defer snap.Close()
+	stones, err := snap.Tombstones()
+	require.NoError(t, err)
+	stone := stones.Next()
+	require.NotNil(t, stone)
+	require.Equal(t, "expiring", stone.(*state.Tombstone).Key)
+	require.Nil(t, stones.Next())
+
+	_, entry, err = store.KVSGet(nil, "forever", nil)
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	require.False(t, entry.HasExpirationTime())
+}
+
 func TestLeader_ReapTombstones(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -650,6 +709,93 @@ func TestLeader_ConfigEntryBootstrap(t *testing.T) {
 	})
 }
 
+func TestLeader_ConfigEntryBootstrap_Metrics(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	// This test cannot be run in parallel as it replaces the global metrics sink.
+	sink := metrics.NewInmemSink(1*time.Minute, 1*time.Minute)
+	cfg := metrics.DefaultConfig("consul")
+	cfg.EnableHostname = false
+	metrics.NewGlobal(cfg, sink)
+	t.Cleanup(func() {
+		metrics.NewGlobal(cfg, &metrics.BlackholeSink{})
+	})
+
+	_, s1 := testServerWithConfig(t, func(c *Config) {
+		c.Build = "1.5.0"
+		c.ConnectEnabled = false
+		c.ConfigEntryBootstrap = []structs.ConfigEntry{
+			&structs.ProxyConfigEntry{
+				Kind: structs.ProxyDefaults,
+				Name: structs.ProxyConfigGlobal,
+				Config: map[string]interface{}{
+					"foo": "bar",
+				},
+			},
+			&structs.ServiceResolverConfigEntry{
+				Kind:           structs.ServiceResolver,
+				Name:           "web",
+				ConnectTimeout: 5 * time.Second,
+			},
+		}
+	})
+	testrpc.WaitForTestAgent(t, s1.RPC, "dc1")
+
+	const (
+		durationKey = "consul.leader.config_bootstrap.duration"
+		failuresKey = "consul.leader.config_bootstrap.failures"
+	)
+
+	retry.Run(t, func(r *retry.R) {
+		intervals := sink.Data()
+		require.NotEmpty(r, intervals)
+		sample, ok := intervals[len(intervals)-1].Samples[durationKey]
+		require.True(r, ok, "did not find the key %q", durationKey)
+		require.GreaterOrEqual(r, sample.Count, 1)
+	})
+	for _, intv := range sink.Data() {
+		require.NotContains(t, intv.Counters, failuresKey)
+	}
+
+	// A splitter for a tcp service can't be applied, which is counted as a
+	// failure.
+	err := s1.bootstrapConfigEntries([]structs.ConfigEntry{
+		&structs.ServiceSplitterConfigEntry{
+			Kind: structs.ServiceSplitter,
+			Name: "db",
+			Splits: []structs.ServiceSplit{
+				{Weight: 100, Service: "db"},
+			},
+		},
+	})
+	require.Error(t, err)
+
+	intervals := sink.Data()
+	require.NotEmpty(t, intervals)
+	counter, ok := intervals[len(intervals)-1].Counters[failuresKey]
+	require.True(t, ok, "did not find the key %q", failuresKey)
+	require.Equal(t, 1, counter.Count)
+
+	// Entries refused before being applied are counted as well.
+	err = s1.bootstrapConfigEntries([]structs.ConfigEntry{
+		&structs.ServiceIntentionsConfigEntry{
+			Kind: structs.ServiceIntentions,
+			Name: "web",
+			Sources: []*structs.SourceIntention{
+				{Name: "api", Action: structs.IntentionActionAllow},
+			},
+		},
+	})
+	require.ErrorContains(t, err, "Connect must be enabled")
+
+	intervals = sink.Data()
+	counter, ok = intervals[len(intervals)-1].Counters[failuresKey]
+	require.True(t, ok, "did not find the key %q", failuresKey)
+	require.Equal(t, 2, counter.Count)
+}
+
 func TestLeader_ConfigEntryBootstrap_Fail(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -1598,6 +1744,226 @@ func TestLeader_EnableVirtualIPs(t *testing.T) {
 	require.Equal(t, "240.0.0.2", vip)
 }
 
+func TestLeader_VirtualIPCIDR(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	_, cidr, err := net.ParseCIDR("100.64.0.0/16")
+	require.NoError(t, err)
+
+	_, s1 := testServerWithConfig(t, func(c *Config) {
+		c.VirtualIPCIDR = cidr
+	})
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	state := s1.fsm.State()
+	retry.Run(t, func(r *retry.R) {
+		_, entry, err := state.SystemMetadataGet(nil, structs.SystemMetadataVirtualIPCIDR)
+		require.NoError(r, err)
+		require.NotNil(r, entry)
+		require.Equal(r, "100.64.0.0/16", entry.Value)
+		_, entry, err = state.SystemMetadataGet(nil, structs.SystemMetadataVirtualIPsEnabled)
+		require.NoError(r, err)
+		require.NotNil(r, entry)
+	})
+
+	for i, name := range []string{"api", "web"} {
+		err := state.EnsureRegistration(uint64(100+i), &structs.RegisterRequest{
+			Node:    "foo",
+			Address: "127.0.0.1",
+			Service: &structs.NodeService{
+				Service: name,
+				Connect: structs.ServiceConnect{
+					Native: true,
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		psn := structs.PeeredServiceName{ServiceName: structs.NewServiceName(name, nil)}
+		vip, err := state.VirtualIPForService(psn)
+		require.NoError(t, err)
+		require.True(t, cidr.Contains(net.ParseIP(vip)), "virtual IP %q not in %s", vip, cidr)
+	}
+
+	// Once virtual IPs were allocated the range can't be changed anymore, and
+	// a leader without an explicit range leaves the stored one alone.
+	_, other, err := net.ParseCIDR("10.128.0.0/16")
+	require.NoError(t, err)
+	for _, c := range []*net.IPNet{other, nil} {
+		s1.config.VirtualIPCIDR = c
+		require.NoError(t, s1.setVirtualIPCIDR())
+
+		_, entry, err := state.SystemMetadataGet(nil, structs.SystemMetadataVirtualIPCIDR)
+		require.NoError(t, err)
+		require.Equal(t, "100.64.0.0/16", entry.Value)
+	}
+}
+
+func TestLeader_VirtualIPCIDR_Default(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	_, s1 := testServer(t)
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	state := s1.fsm.State()
+	retry.Run(t, func(r *retry.R) {
+		_, entry, err := state.SystemMetadataGet(nil, structs.SystemMetadataVirtualIPsEnabled)
+		require.NoError(r, err)
+		require.NotNil(r, entry)
+	})
+
+	// Without an explicit range nothing is stored.
+	_, entry, err := state.SystemMetadataGet(nil, structs.SystemMetadataVirtualIPCIDR)
+	require.NoError(t, err)
+	require.Nil(t, entry)
+
+	// The range can still be set as long as no virtual IP has been allocated.
+	_, cidr, err := net.ParseCIDR("100.64.0.0/16")
+	require.NoError(t, err)
+	s1.config.VirtualIPCIDR = cidr
+	require.NoError(t, s1.setVirtualIPCIDR())
+
+	_, entry, err = state.SystemMetadataGet(nil, structs.SystemMetadataVirtualIPCIDR)
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	require.Equal(t, "100.64.0.0/16", entry.Value)
+}
+
+func TestLeader_ReclaimVirtualIPs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	origCheck := virtualIPVersionCheckInterval
+	virtualIPVersionCheckInterval = 50 * time.Millisecond
+	origReclaim := virtualIPReclaimInterval
+	virtualIPReclaimInterval = 50 * time.Millisecond
+	t.Cleanup(func() {
+		virtualIPVersionCheckInterval = origCheck
+		virtualIPReclaimInterval = origReclaim
+	})
+
+	_, s1 := testServerWithConfig(t, func(c *Config) {
+		c.Build = "1.22.0"
+	})
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	store := s1.fsm.State()
+	retry.Run(t, func(r *retry.R) {
+		_, entry, err := store.SystemMetadataGet(nil, structs.SystemMetadataVirtualIPsEnabled)
+		require.NoError(r, err)
+		require.NotNil(r, entry)
+	})
+
+	// Register a connect-native service and make sure it gets a virtual IP.
+	register := structs.RegisterRequest{
+		Datacenter: "dc1",
+		Node:       "foo",
+		Address:    "127.0.0.1",
+		Service: &structs.NodeService{
+			ID:      "api",
+			Service: "api",
+			Port:    8080,
+			Connect: structs.ServiceConnect{
+				Native: true,
+			},
+		},
+	}
+	var out struct{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Catalog.Register", &register, &out))
+
+	psn := structs.PeeredServiceName{ServiceName: structs.NewServiceName("api", nil)}
+	vip, err := store.VirtualIPForService(psn)
+	require.NoError(t, err)
+	require.Equal(t, "240.0.0.1", vip)
+
+	// Fully deregister the service and make sure its virtual IP is reclaimed.
+	deregister := structs.DeregisterRequest{
+		Datacenter: "dc1",
+		Node:       "foo",
+		ServiceID:  "api",
+	}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Catalog.Deregister", &deregister, &out))
+
+	retry.Run(t, func(r *retry.R) {
+		vip, err := store.VirtualIPForService(psn)
+		require.NoError(r, err)
+		require.Empty(r, vip)
+
+		orphaned, err := store.OrphanedServiceVirtualIPs()
+		require.NoError(r, err)
+		require.Empty(r, orphaned)
+	})
+
+	// Leak a virtual IP by writing one for a service that was never
+	// registered, so deregistration never gets a chance to free it. Only the
+	// periodic reclaim can return it to the pool.
+	leaked := structs.PeeredServiceName{ServiceName: structs.NewServiceName("leaked", nil)}
+	restore := store.Restore()
+	require.NoError(t, restore.ServiceVirtualIP(state.ServiceVirtualIP{
+		Service: leaked,
+		IP:      net.ParseIP("240.0.0.2"),
+	}))
+	require.NoError(t, restore.Commit())
+
+	retry.Run(t, func(r *retry.R) {
+		vip, err := store.VirtualIPForService(leaked)
+		require.NoError(r, err)
+		require.Empty(r, vip)
+
+		orphaned, err := store.OrphanedServiceVirtualIPs()
+		require.NoError(r, err)
+		require.Empty(r, orphaned)
+	})
+}
+
+func TestLeader_ReclaimVirtualIPs_VersionGate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	origCheck := virtualIPVersionCheckInterval
+	virtualIPVersionCheckInterval = 50 * time.Millisecond
+	origReclaim := virtualIPReclaimInterval
+	virtualIPReclaimInterval = 50 * time.Millisecond
+	t.Cleanup(func() {
+		virtualIPVersionCheckInterval = origCheck
+		virtualIPReclaimInterval = origReclaim
+	})
+
+	// Virtual IPs are supported, but not every server can apply a reclaim.
+	_, s1 := testServerWithConfig(t, func(c *Config) {
+		c.Build = "1.21.0"
+	})
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	store := s1.fsm.State()
+	retry.Run(t, func(r *retry.R) {
+		_, entry, err := store.SystemMetadataGet(nil, structs.SystemMetadataVirtualIPsEnabled)
+		require.NoError(r, err)
+		require.NotNil(r, entry)
+	})
+
+	leaked := structs.PeeredServiceName{ServiceName: structs.NewServiceName("leaked", nil)}
+	restore := store.Restore()
+	require.NoError(t, restore.ServiceVirtualIP(state.ServiceVirtualIP{
+		Service: leaked,
+		IP:      net.ParseIP("240.0.0.2"),
+	}))
+	require.NoError(t, restore.Commit())
+
+	require.NoError(t, s1.reclaimVirtualIPs())
+	orphaned, err := store.OrphanedServiceVirtualIPs()
+	require.NoError(t, err)
+	require.Equal(t, []structs.PeeredServiceName{leaked}, orphaned)
+}
+
 func TestLeader_ACL_Initialization_AnonymousToken(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -54,6 +54,8 @@ func (op *Operator) RaftGetConfiguration(args *structs.DCSpecificRequest, reply
 		serverIDLastIndexMap[serverState.Server.ID] = serverState.Stats.LastIndex
 	}
 
+	serverIDStatsMap := op.srv.statsFetcher.LastStats()
+
 	// Fill out the reply.
 	leader := op.srv.raft.Leader()
 	reply.Index = future.Index()
@@ -73,6 +75,8 @@ func (op *Operator) RaftGetConfiguration(args *structs.DCSpecificRequest, reply
 			Voter:           server.Suffrage == raft.Voter,
 			ProtocolVersion: raftProtocolVersion,
 			LastIndex:       serverIDLastIndexMap[server.ID],
+			CommitIndex:     serverIDStatsMap[server.ID].CommitIndex,
+			AppliedIndex:    serverIDStatsMap[server.ID].AppliedIndex,
 		}
 		reply.Servers = append(reply.Servers, entry)
 	}
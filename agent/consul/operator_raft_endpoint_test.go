@@ -56,6 +56,7 @@ func TestOperator_RaftGetConfiguration(t *testing.T) {
 	for _, serverState := range s1.autopilot.GetState().Servers {
 		serverIDLastIndexMap[serverState.Server.ID] = serverState.Stats.LastIndex
 	}
+	serverIDStatsMap := s1.statsFetcher.LastStats()
 
 	me := future.Configuration().Servers[0]
 	expected := structs.RaftConfigurationResponse{
@@ -68,6 +69,8 @@ func TestOperator_RaftGetConfiguration(t *testing.T) {
 				Voter:           true,
 				ProtocolVersion: "3",
 				LastIndex:       serverIDLastIndexMap[me.ID],
+				CommitIndex:     serverIDStatsMap[me.ID].CommitIndex,
+				AppliedIndex:    serverIDStatsMap[me.ID].AppliedIndex,
 			},
 		},
 		Index: future.Index(),
@@ -125,6 +128,7 @@ func TestOperator_RaftGetConfiguration_ACLDeny(t *testing.T) {
 	for _, serverState := range s1.autopilot.GetState().Servers {
 		serverIDLastIndexMap[serverState.Server.ID] = serverState.Stats.LastIndex
 	}
+	serverIDStatsMap := s1.statsFetcher.LastStats()
 	me := future.Configuration().Servers[0]
 	expected := structs.RaftConfigurationResponse{
 		Servers: []*structs.RaftServer{
@@ -136,6 +140,8 @@ func TestOperator_RaftGetConfiguration_ACLDeny(t *testing.T) {
 				Voter:           true,
 				ProtocolVersion: "3",
 				LastIndex:       serverIDLastIndexMap[me.ID],
+				CommitIndex:     serverIDStatsMap[me.ID].CommitIndex,
+				AppliedIndex:    serverIDStatsMap[me.ID].AppliedIndex,
 			},
 		},
 		Index: future.Index(),
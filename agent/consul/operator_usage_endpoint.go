@@ -7,13 +7,24 @@ import (
 	"github.com/dhiaayachi/consul/acl"
 	"github.com/dhiaayachi/consul/agent/consul/state"
 	"github.com/dhiaayachi/consul/agent/structs"
+	"github.com/hashicorp/go-bexpr"
 	"github.com/hashicorp/go-memdb"
 )
 
-// Usage returns counts for service usage within catalog.
+// Usage returns counts for service usage within catalog. When a filter is
+// given, the counts only include the service instances it matches.
 func (op *Operator) Usage(args *structs.OperatorUsageRequest, reply *structs.Usage) error {
 	reply.Usage = make(map[string]structs.ServiceUsage)
 
+	var filter *bexpr.Evaluator
+	if args.Filter != "" {
+		var err error
+		filter, err = bexpr.CreateEvaluatorForType(args.Filter, nil, &structs.ServiceNode{})
+		if err != nil {
+			return err
+		}
+	}
+
 	if args.Global {
 		remoteDCs := op.srv.router.GetDatacenters()
 		for _, dc := range remoteDCs {
@@ -21,7 +32,8 @@ func (op *Operator) Usage(args *structs.OperatorUsageRequest, reply *structs.Usa
 				DCSpecificRequest: structs.DCSpecificRequest{
 					Datacenter: dc,
 					QueryOptions: structs.QueryOptions{
-						Token: args.Token,
+						Token:  args.Token,
+						Filter: args.Filter,
 					},
 				},
 			}
@@ -54,7 +66,18 @@ func (op *Operator) Usage(args *structs.OperatorUsageRequest, reply *structs.Usa
 		&reply.QueryMeta,
 		func(ws memdb.WatchSet, state *state.Store) error {
 			// Get service usage.
-			index, serviceUsage, err := state.ServiceUsage(ws, true)
+			var (
+				index        uint64
+				serviceUsage structs.ServiceUsage
+				err          error
+			)
+			if filter != nil {
+				index, serviceUsage, err = state.ServiceUsageFiltered(ws, func(svc *structs.ServiceNode) (bool, error) {
+					return filter.Evaluate(svc)
+				})
+			} else {
+				index, serviceUsage, err = state.ServiceUsage(ws, true)
+			}
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,93 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package rate
+
+import (
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"golang.org/x/time/rate"
+)
+
+// TokenLimiter enforces the write rate limits configured on individual ACL
+// tokens. Unlike the Handler's limits, token limits are always enforced and
+// are keyed on the token's AccessorID, so they are not affected by the
+// global request_limits mode.
+//
+// Limiters are swept out once their bucket has refilled, at which point a
+// fresh limiter would behave the same, so the set only holds tokens that have
+// written recently.
+type TokenLimiter struct {
+	mu        sync.Mutex
+	limiters  map[string]*rate.Limiter
+	lastSweep time.Time
+}
+
+// tokenLimiterSweepInterval is how often AllowWrite looks for limiters that
+// can be dropped.
+const tokenLimiterSweepInterval = time.Minute
+
+// NewTokenLimiter creates an empty TokenLimiter.
+func NewTokenLimiter() *TokenLimiter {
+	return &TokenLimiter{limiters: make(map[string]*rate.Limiter)}
+}
+
+// AllowWrite returns ErrRetryLater if the token identified by accessorID has
+// exhausted its write rate limit. A limit of zero or less disables the check
+// and forgets any limiter previously kept for the token.
+func (t *TokenLimiter) AllowWrite(accessorID string, limit float64) error {
+	return t.allowWriteAt(accessorID, limit, time.Now())
+}
+
+func (t *TokenLimiter) allowWriteAt(accessorID string, limit float64, now time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if now.Sub(t.lastSweep) >= tokenLimiterSweepInterval {
+		t.sweep(now)
+	}
+
+	if limit <= 0 {
+		delete(t.limiters, accessorID)
+		return nil
+	}
+
+	l, ok := t.limiters[accessorID]
+	if !ok || l.Limit() != rate.Limit(limit) {
+		burst := int(limit)
+		if burst < 1 {
+			burst = 1
+		}
+		l = rate.NewLimiter(rate.Limit(limit), burst)
+		t.limiters[accessorID] = l
+	}
+
+	if l.AllowN(now, 1) {
+		return nil
+	}
+
+	metrics.IncrCounterWithLabels([]string{"rpc", "rate_limit", "exceeded"}, 1, []metrics.Label{
+		{
+			Name:  "limit_type",
+			Value: "token/write",
+		},
+		{
+			Name:  "mode",
+			Value: ModeEnforcing.String(),
+		},
+	})
+	return ErrRetryLater
+}
+
+// sweep drops the limiters whose bucket is full again, including those of
+// tokens that have since been deleted.
+func (t *TokenLimiter) sweep(now time.Time) {
+	for accessorID, l := range t.limiters {
+		if l.TokensAt(now) >= float64(l.Burst()) {
+			delete(t.limiters, accessorID)
+		}
+	}
+	t.lastSweep = now
+}
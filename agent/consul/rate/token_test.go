@@ -0,0 +1,44 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package rate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenLimiter_AllowWrite(t *testing.T) {
+	l := NewTokenLimiter()
+	now := time.Now()
+
+	require.NoError(t, l.allowWriteAt("a", 1, now))
+	require.ErrorIs(t, l.allowWriteAt("a", 1, now), ErrRetryLater)
+	require.NoError(t, l.allowWriteAt("a", 1, now.Add(time.Second)))
+
+	// Other tokens and unlimited tokens are not affected.
+	require.NoError(t, l.allowWriteAt("b", 1, now))
+	require.NoError(t, l.allowWriteAt("c", 0, now))
+	require.NoError(t, l.allowWriteAt("c", 0, now))
+	require.Len(t, l.limiters, 2)
+}
+
+func TestTokenLimiter_Sweep(t *testing.T) {
+	l := NewTokenLimiter()
+	now := time.Now()
+
+	// The first call sweeps, so the next sweep happens a full interval later.
+	require.NoError(t, l.allowWriteAt("fast", 10, now))
+	require.NoError(t, l.allowWriteAt("slow", 0.001, now))
+	require.Len(t, l.limiters, 2)
+
+	// By the next sweep the fast token's bucket has refilled and its limiter
+	// is dropped, while the slow token is still being held back.
+	now = now.Add(tokenLimiterSweepInterval)
+	require.NoError(t, l.allowWriteAt("other", 1, now))
+	require.Contains(t, l.limiters, "slow")
+	require.NotContains(t, l.limiters, "fast")
+	require.ErrorIs(t, l.allowWriteAt("slow", 0.001, now), ErrRetryLater)
+}
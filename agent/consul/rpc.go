@@ -698,7 +698,33 @@ func (s *Server) forwardRPC(
 		return false, nil
 	}
 
-	return s.forwardRequestToLeader(info, forwardToLeader)
+	handled, err = s.forwardRequestToLeader(info, forwardToLeader)
+	if handled || err != nil {
+		return handled, err
+	}
+
+	// Writes are only handled by the leader, so this is the one place where
+	// per-token write limits can be enforced consistently.
+	if !info.IsRead() {
+		if err := s.allowTokenWrite(info.TokenSecret()); err != nil {
+			return true, err
+		}
+	}
+	return false, nil
+}
+
+// allowTokenWrite returns an error if the token with the given secret has a
+// write rate limit configured and has exhausted it.
+func (s *Server) allowTokenWrite(secret string) error {
+	if !s.config.ACLsEnabled || secret == "" {
+		return nil
+	}
+	_, token, err := s.fsm.State().ACLTokenGetBySecret(nil, secret, nil)
+	if err != nil || token == nil {
+		// Unknown tokens are rejected later on by the ACL checks.
+		return nil
+	}
+	return s.tokenRateLimiter.AllowWrite(token.AccessorID, token.WriteRateLimit)
 }
 
 // forwardRequestToOtherDatacenter is an implementation detail of forwardRPC.
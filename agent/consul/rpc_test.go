@@ -1656,3 +1656,59 @@ func rpcBlockingQueryTestHarness(
 
 	require.Equal(t, 1, count, "if this fails, then the timer likely needs to be increased above")
 }
+
+func TestRPC_TokenWriteRateLimit(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	_, srv, codec := testACLServerWithConfig(t, nil, false)
+	waitForLeaderEstablishment(t, srv)
+
+	policy, err := upsertTestPolicyWithRules(codec, TestDefaultInitialManagementToken, "dc1", `key_prefix "" { policy = "write" }`)
+	require.NoError(t, err)
+
+	newToken := func(writeRateLimit float64) *structs.ACLToken {
+		token, err := upsertTestToken(codec, TestDefaultInitialManagementToken, "dc1", func(token *structs.ACLToken) {
+			token.Policies = []structs.ACLTokenPolicyLink{{ID: policy.ID}}
+			token.WriteRateLimit = writeRateLimit
+		})
+		require.NoError(t, err)
+		return token
+	}
+	limited := newToken(0.01)
+	unlimited := newToken(0)
+	require.Equal(t, 0.01, limited.WriteRateLimit)
+
+	write := func(token *structs.ACLToken) error {
+		args := structs.KVSRequest{
+			Datacenter: "dc1",
+			Op:         api.KVSet,
+			DirEnt: structs.DirEntry{
+				Key:   "foo",
+				Value: []byte("bar"),
+			},
+			WriteRequest: structs.WriteRequest{Token: token.SecretID},
+		}
+		var out bool
+		return msgpackrpc.CallWithCodec(codec, "KVS.Apply", &args, &out)
+	}
+
+	// The limited token is allowed a burst of a single write.
+	require.NoError(t, write(limited))
+	err = write(limited)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), rate.ErrRetryLater.Error())
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, write(unlimited))
+	}
+
+	t.Run("negative limit is rejected", func(t *testing.T) {
+		_, err := upsertTestToken(codec, TestDefaultInitialManagementToken, "dc1", func(token *structs.ACLToken) {
+			token.WriteRateLimit = -1
+		})
+		require.ErrorContains(t, err, "WriteRateLimit must not be negative")
+	})
+}
@@ -139,10 +139,12 @@ const (
 	federationStateAntiEntropyRoutineName = "federation state anti-entropy"
 	federationStatePruningRoutineName     = "federation state pruning"
 	intentionMigrationRoutineName         = "intention config entry migration"
+	kvsReapingRoutineName                 = "kvs reaping"
 	secondaryCARootWatchRoutineName       = "secondary CA roots watch"
 	intermediateCertRenewWatchRoutineName = "intermediate cert renew watch"
 	backgroundCAInitializationRoutineName = "CA initialization"
 	virtualIPCheckRoutineName             = "virtual IP version check"
+	virtualIPReclaimRoutineName           = "virtual IP reclaim"
 	peeringStreamsRoutineName             = "streaming peering resources"
 	peeringDeletionRoutineName            = "peering deferred deletion"
 	peeringStreamsMetricsRoutineName      = "metrics for streaming peering resources"
@@ -327,6 +329,9 @@ type Server struct {
 	// incomingRPCLimiter rate-limits incoming net/rpc and gRPC calls.
 	incomingRPCLimiter rpcRate.RequestLimitsHandler
 
+	// tokenRateLimiter enforces the write rate limits configured on ACL tokens.
+	tokenRateLimiter *rpcRate.TokenLimiter
+
 	// insecureRPCServer is a RPC server that is configure with
 	// IncomingInsecureRPCConfig to allow clients to call AutoEncrypt.Sign
 	// to request client certificates. At this point a client doesn't have
@@ -397,6 +402,15 @@ type Server struct {
 	aclReplicationStatus     structs.ACLReplicationStatus
 	aclReplicationStatusLock sync.RWMutex
 
+	// configEntryQuotaLock serializes config entry writes that are subject
+	// to a per-partition quota, so that the quota check and the Raft apply
+	// happen atomically with respect to other quota-checked writes.
+	configEntryQuotaLock sync.Mutex
+
+	// intentionMatchStats counts how many intention checks served by this
+	// server were decided by each intention since the server started.
+	intentionMatchStats intentionMatchStats
+
 	// shutdown and the associated members here are used in orchestrating
 	// a clean shutdown. The shutdownCh is never written to, only closed to
 	// indicate a shutdown has been initiated.
@@ -524,6 +538,7 @@ func NewServer(config *Config, flat Deps, externalGRPCServer *grpc.Server,
 		aclAuthMethodValidators: authmethod.NewCache(),
 		publisher:               flat.EventPublisher,
 		incomingRPCLimiter:      incomingRPCLimiter,
+		tokenRateLimiter:        rpcRate.NewTokenLimiter(),
 		routineManager:          routine.NewManager(logger.Named(logging.ConsulServer)),
 		registry:                flat.Registry,
 	}
@@ -540,7 +555,8 @@ func NewServer(config *Config, flat Deps, externalGRPCServer *grpc.Server,
 	s.fsm = fsm.NewFromDeps(fsm.Deps{
 		Logger: flat.Logger,
 		NewStateStore: func() *state.Store {
-			return state.NewStateStoreWithEventPublisher(gc, flat.EventPublisher)
+			store := state.NewStateStoreWithEventPublisher(gc, flat.EventPublisher)
+			return store
 		},
 		Publisher:      flat.EventPublisher,
 		StorageBackend: s.raftStorageBackend,
@@ -1137,7 +1153,8 @@ func (s *Server) setupRaft() error {
 			tmpFsm := fsm.NewFromDeps(fsm.Deps{
 				Logger: s.logger,
 				NewStateStore: func() *state.Store {
-					return state.NewStateStore(s.tombstoneGC)
+					store := state.NewStateStore(s.tombstoneGC)
+					return store
 				},
 				StorageBackend: backend,
 			})
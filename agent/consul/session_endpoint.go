@@ -12,12 +12,27 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/go-version"
 
 	"github.com/dhiaayachi/consul/acl"
 	"github.com/dhiaayachi/consul/agent/consul/state"
 	"github.com/dhiaayachi/consul/agent/structs"
 )
 
+var (
+	// minSessionCheckExemptTypesVersion is the lowest server version whose FSM
+	// honors SessionRequest.ExemptCheckTypes.
+	minSessionCheckExemptTypesVersion = version.Must(version.NewVersion("1.22.0"))
+
+	// minSessionRenewAllVersion is the lowest server version that can apply
+	// SessionRenewAllRequestType raft entries.
+	minSessionRenewAllVersion = version.Must(version.NewVersion("1.22.0"))
+
+	// minSessionTransferVersion is the lowest server version that can apply
+	// SessionTransferRequestType raft entries.
+	minSessionTransferVersion = version.Must(version.NewVersion("1.22.0"))
+)
+
 var SessionEndpointSummaries = []prometheus.SummaryDefinition{
 	{
 		Name: []string{"session", "apply"},
@@ -27,6 +42,14 @@ var SessionEndpointSummaries = []prometheus.SummaryDefinition{
 		Name: []string{"session", "renew"},
 		Help: "Measures the time spent renewing a session.",
 	},
+	{
+		Name: []string{"session", "renew_all"},
+		Help: "Measures the time spent renewing a batch of sessions.",
+	},
+	{
+		Name: []string{"session", "transfer"},
+		Help: "Measures the time spent transferring a session to another node.",
+	},
 }
 
 // Session endpoint is used to manipulate sessions for KV
@@ -101,8 +124,18 @@ func (s *Session) Apply(args *structs.SessionRequest, reply *string) error {
 	// Ensure that the specified behavior is allowed
 	switch args.Session.Behavior {
 	case "":
-		// Default behavior to Release for backwards compatibility
+		// Default behavior to the namespace's session-defaults config entry,
+		// or to Release for backwards compatibility
 		args.Session.Behavior = structs.SessionKeysRelease
+		if args.Op == structs.SessionCreate {
+			_, entry, err := s.srv.fsm.State().ConfigEntry(nil, structs.SessionDefaults, structs.SessionDefaultsName, &args.Session.EnterpriseMeta)
+			if err != nil {
+				return fmt.Errorf("Session defaults lookup failed: %v", err)
+			}
+			if defaults, ok := entry.(*structs.SessionDefaultsConfigEntry); ok && defaults.Behavior != "" {
+				args.Session.Behavior = defaults.Behavior
+			}
+		}
 	case structs.SessionKeysRelease:
 	case structs.SessionKeysDelete:
 	default:
@@ -120,6 +153,10 @@ func (s *Session) Apply(args *structs.SessionRequest, reply *string) error {
 			return fmt.Errorf("Invalid Session TTL '%d', must be between [%v=%v]",
 				ttl, s.srv.config.SessionTTLMin, structs.SessionTTLMax)
 		}
+
+		if maxTTL := s.srv.config.SessionMaxTTL; maxTTL > 0 && ttl > maxTTL {
+			return fmt.Errorf("Invalid Session TTL %q: exceeds the maximum of %v", args.Session.TTL, maxTTL)
+		}
 	}
 
 	// If this is a create, we must generate the Session ID. This must
@@ -144,6 +181,18 @@ func (s *Session) Apply(args *structs.SessionRequest, reply *string) error {
 				break
 			}
 		}
+
+		// Check types exempted by the operator are recorded in the request
+		// rather than read from each server's configuration when the entry is
+		// applied, so that every server applies it the same way. Older servers
+		// would ignore them, so they are only sent once all servers can honor
+		// them.
+		args.ExemptCheckTypes = nil
+		if len(s.srv.config.SessionCheckExemptTypes) > 0 {
+			if ok, _ := ServersInDCMeetMinimumVersion(s.srv, s.srv.config.Datacenter, minSessionCheckExemptTypesVersion); ok {
+				args.ExemptCheckTypes = s.srv.config.SessionCheckExemptTypes
+			}
+		}
 	}
 
 	// Apply the update
@@ -215,6 +264,10 @@ func (s *Session) List(args *structs.SessionSpecificRequest,
 		return err
 	}
 
+	if err := args.ListFilter.Validate(); err != nil {
+		return err
+	}
+
 	var authzContext acl.AuthorizerContext
 	authz, err := s.srv.ResolveTokenAndDefaultMeta(args.Token, &args.EnterpriseMeta, &authzContext)
 	if err != nil {
@@ -229,7 +282,7 @@ func (s *Session) List(args *structs.SessionSpecificRequest,
 		&args.QueryOptions,
 		&reply.QueryMeta,
 		func(ws memdb.WatchSet, state *state.Store) error {
-			index, sessions, err := state.SessionList(ws, &args.EnterpriseMeta)
+			index, sessions, err := state.SessionList(ws, &args.ListFilter, &args.EnterpriseMeta)
 			if err != nil {
 				return err
 			}
@@ -319,3 +372,180 @@ func (s *Session) Renew(args *structs.SessionSpecificRequest,
 
 	return nil
 }
+
+// RenewAll is used to renew the TTLs of many sessions with a single Raft
+// apply. Sessions that don't exist, or that the token may not write, are
+// reported in the results rather than failing the whole batch.
+func (s *Session) RenewAll(args *structs.SessionRenewAllRequest,
+	reply *structs.SessionRenewAllResponse) error {
+	if done, err := s.srv.ForwardRPC("Session.RenewAll", args, reply); done {
+		return err
+	}
+
+	defer metrics.MeasureSince([]string{"session", "renew_all"}, time.Now())
+
+	if ok, _ := ServersInDCMeetMinimumVersion(s.srv, s.srv.config.Datacenter, minSessionRenewAllVersion); !ok {
+		return fmt.Errorf("can't renew sessions in a batch until all servers >= %s",
+			minSessionRenewAllVersion.String())
+	}
+
+	// Fetch the ACL token, if any, and apply the policy.
+	var authzContext acl.AuthorizerContext
+	authz, err := s.srv.ResolveTokenAndDefaultMeta(args.Token, &args.EnterpriseMeta, &authzContext)
+	if err != nil {
+		return err
+	}
+
+	if err := s.srv.validateEnterpriseRequest(&args.EnterpriseMeta, true); err != nil {
+		return err
+	}
+
+	state := s.srv.fsm.State()
+	reply.Results = make([]structs.SessionRenewResult, len(args.SessionIDs))
+	var allowed []string
+	for i, id := range args.SessionIDs {
+		reply.Results[i].SessionID = id
+
+		index, session, err := state.SessionGet(nil, id, &args.EnterpriseMeta)
+		if err != nil {
+			return err
+		}
+		reply.Index = index
+		if session == nil {
+			reply.Results[i].Error = "session not found"
+			continue
+		}
+		if err := authz.ToAllowAuthorizer().SessionWriteAllowed(session.Node, &authzContext); err != nil {
+			reply.Results[i].Error = err.Error()
+			continue
+		}
+		allowed = append(allowed, id)
+	}
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	req := structs.SessionRenewAllRequest{
+		Datacenter:     args.Datacenter,
+		SessionIDs:     allowed,
+		EnterpriseMeta: args.EnterpriseMeta,
+	}
+	resp, err := s.srv.raftApply(structs.SessionRenewAllRequestType, &req)
+	if err != nil {
+		return fmt.Errorf("apply failed: %w", err)
+	}
+	renewed := make(map[string]bool)
+	if ids, ok := resp.([]string); ok {
+		for _, id := range ids {
+			renewed[id] = true
+		}
+	}
+
+	now := time.Now()
+	for i := range reply.Results {
+		result := &reply.Results[i]
+		if result.Error != "" {
+			continue
+		}
+		if !renewed[result.SessionID] {
+			// The session was destroyed, or its node deregistered, before
+			// the renewal was applied.
+			result.Error = "session not found"
+			continue
+		}
+
+		index, session, err := state.SessionGet(nil, result.SessionID, &args.EnterpriseMeta)
+		if err != nil {
+			return err
+		}
+		reply.Index = index
+		if session == nil {
+			result.Error = "session not found"
+			continue
+		}
+
+		// Reset the session TTL timer.
+		if err := s.srv.resetSessionTimer(session); err != nil {
+			s.logger.Error("Session renew failed", "error", err)
+			result.Error = err.Error()
+			continue
+		}
+		result.Renewed = true
+		if ttl, err := time.ParseDuration(session.TTL); err == nil && ttl > 0 {
+			result.Deadline = now.Add(ttl)
+		}
+	}
+
+	return nil
+}
+
+// Transfer is used to move a session to another node, e.g. when the node it
+// is tied to is being replaced. Any locks held by the session are kept.
+func (s *Session) Transfer(args *structs.SessionTransferRequest,
+	reply *structs.IndexedSessions) error {
+	if done, err := s.srv.ForwardRPC("Session.Transfer", args, reply); done {
+		return err
+	}
+
+	defer metrics.MeasureSince([]string{"session", "transfer"}, time.Now())
+
+	if ok, _ := ServersInDCMeetMinimumVersion(s.srv, s.srv.config.Datacenter, minSessionTransferVersion); !ok {
+		return fmt.Errorf("can't transfer sessions until all servers >= %s",
+			minSessionTransferVersion.String())
+	}
+
+	// Verify the args
+	if args.SessionID == "" {
+		return fmt.Errorf("Must provide ID")
+	}
+	if args.Node == "" {
+		return fmt.Errorf("Must provide Node")
+	}
+
+	// Fetch the ACL token, if any, and apply the policy.
+	var authzContext acl.AuthorizerContext
+	authz, err := s.srv.ResolveTokenAndDefaultMeta(args.Token, &args.EnterpriseMeta, &authzContext)
+	if err != nil {
+		return err
+	}
+
+	if err := s.srv.validateEnterpriseRequest(&args.EnterpriseMeta, true); err != nil {
+		return err
+	}
+
+	state := s.srv.fsm.State()
+	_, existing, err := state.SessionGet(nil, args.SessionID, &args.EnterpriseMeta)
+	if err != nil {
+		return fmt.Errorf("Session lookup failed: %v", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("Session %q not found", args.SessionID)
+	}
+
+	// The token must be able to write sessions on both the old and new node.
+	if err := authz.ToAllowAuthorizer().SessionWriteAllowed(existing.Node, &authzContext); err != nil {
+		return err
+	}
+	if err := authz.ToAllowAuthorizer().SessionWriteAllowed(args.Node, &authzContext); err != nil {
+		return err
+	}
+
+	// As with session creation, the exempted check types are recorded in the
+	// request so that every server validates the new node's checks the same
+	// way.
+	args.ExemptCheckTypes = s.srv.config.SessionCheckExemptTypes
+
+	if _, err := s.srv.raftApply(structs.SessionTransferRequestType, args); err != nil {
+		return fmt.Errorf("apply failed: %w", err)
+	}
+
+	index, session, err := state.SessionGet(nil, args.SessionID, &args.EnterpriseMeta)
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	if session != nil {
+		reply.Sessions = structs.Sessions{session}
+	}
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/dhiaayachi/consul/acl"
 	"github.com/dhiaayachi/consul/agent/structs"
+	"github.com/dhiaayachi/consul/api"
 	"github.com/dhiaayachi/consul/lib/stringslice"
 	"github.com/dhiaayachi/consul/testrpc"
 )
@@ -83,6 +84,143 @@ func TestSession_Apply(t *testing.T) {
 	}
 }
 
+func TestSession_Apply_ExemptCheckTypes(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	_, s1 := testServerWithConfig(t, func(c *Config) {
+		c.SessionCheckExemptTypes = []string{"informational"}
+	})
+	codec := rpcClient(t, s1)
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	state := s1.fsm.State()
+	require.NoError(t, state.EnsureNode(1, &structs.Node{Node: "foo", Address: "127.0.0.1"}))
+	require.NoError(t, state.EnsureCheck(2, &structs.HealthCheck{
+		Node:    "foo",
+		CheckID: "info",
+		Type:    "informational",
+		Status:  api.HealthCritical,
+	}))
+	require.NoError(t, state.EnsureCheck(3, &structs.HealthCheck{
+		Node:    "foo",
+		CheckID: "ttl",
+		Type:    "ttl",
+		Status:  api.HealthCritical,
+	}))
+
+	arg := structs.SessionRequest{
+		Datacenter: "dc1",
+		Op:         structs.SessionCreate,
+		Session: structs.Session{
+			Node:       "foo",
+			NodeChecks: []string{"info"},
+		},
+		// Exemptions supplied by the caller are replaced by the server's.
+		ExemptCheckTypes: []string{"ttl"},
+	}
+	var out string
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Session.Apply", &arg, &out))
+
+	_, sess, err := state.SessionGet(nil, out, nil)
+	require.NoError(t, err)
+	require.NotNil(t, sess)
+
+	arg.Session.NodeChecks = []string{"ttl"}
+	err = msgpackrpc.CallWithCodec(codec, "Session.Apply", &arg, &out)
+	require.ErrorContains(t, err, "Check 'ttl' is in critical state")
+}
+
+func TestSession_Apply_MaxTTL(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	_, s1 := testServerWithConfig(t, func(c *Config) {
+		c.SessionMaxTTL = time.Hour
+	})
+	codec := rpcClient(t, s1)
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	require.NoError(t, s1.fsm.State().EnsureNode(1, &structs.Node{Node: "foo", Address: "127.0.0.1"}))
+
+	apply := func(ttl string) error {
+		arg := structs.SessionRequest{
+			Datacenter: "dc1",
+			Op:         structs.SessionCreate,
+			Session: structs.Session{
+				Node: "foo",
+				TTL:  ttl,
+			},
+		}
+		var out string
+		return msgpackrpc.CallWithCodec(codec, "Session.Apply", &arg, &out)
+	}
+
+	require.NoError(t, apply("30m"))
+	require.NoError(t, apply("1h"))
+	require.ErrorContains(t, apply("61m"), `Invalid Session TTL "61m": exceeds the maximum of 1h0m0s`)
+}
+
+func TestSession_Apply_SessionDefaults(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	// Just add a node
+	s1.fsm.State().EnsureNode(1, &structs.Node{Node: "foo", Address: "127.0.0.1"})
+
+	// Configure the namespace to default to delete behavior
+	var configOut bool
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ConfigEntry.Apply", &structs.ConfigEntryRequest{
+		Datacenter: "dc1",
+		Entry: &structs.SessionDefaultsConfigEntry{
+			Behavior:       structs.SessionKeysDelete,
+			EnterpriseMeta: *structs.DefaultEnterpriseMetaInDefaultPartition(),
+		},
+	}, &configOut))
+	require.True(t, configOut)
+
+	create := func(t *testing.T, behavior structs.SessionBehavior) *structs.Session {
+		arg := structs.SessionRequest{
+			Datacenter: "dc1",
+			Op:         structs.SessionCreate,
+			Session: structs.Session{
+				Node:     "foo",
+				Behavior: behavior,
+			},
+		}
+		var out string
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Session.Apply", &arg, &out))
+
+		_, s, err := s1.fsm.State().SessionGet(nil, out, nil)
+		require.NoError(t, err)
+		require.NotNil(t, s)
+		return s
+	}
+
+	// A session without a behavior gets the namespace default
+	require.Equal(t, structs.SessionBehavior(structs.SessionKeysDelete), create(t, "").Behavior)
+
+	// An explicit behavior takes precedence
+	require.Equal(t, structs.SessionKeysRelease, create(t, structs.SessionKeysRelease).Behavior)
+}
+
 func TestSession_DeleteApply(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -376,6 +514,62 @@ func TestSession_List(t *testing.T) {
 	}
 }
 
+func TestSession_List_Filter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	s1.fsm.State().EnsureNode(1, &structs.Node{Node: "foo", Address: "127.0.0.1"})
+	var deleteID string
+	for _, behavior := range []structs.SessionBehavior{structs.SessionKeysRelease, structs.SessionKeysDelete} {
+		arg := structs.SessionRequest{
+			Datacenter: "dc1",
+			Op:         structs.SessionCreate,
+			Session: structs.Session{
+				Node:      "foo",
+				Behavior:  behavior,
+				LockDelay: 20 * time.Second,
+			},
+		}
+		var out string
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Session.Apply", &arg, &out))
+		if behavior == structs.SessionKeysDelete {
+			deleteID = out
+		}
+	}
+
+	getR := structs.SessionSpecificRequest{
+		Datacenter: "dc1",
+		ListFilter: structs.SessionListFilter{Behavior: structs.SessionKeysDelete},
+	}
+	var sessions structs.IndexedSessions
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Session.List", &getR, &sessions))
+	require.Len(t, sessions.Sessions, 1)
+	require.Equal(t, deleteID, sessions.Sessions[0].ID)
+	index := sessions.Index
+
+	maxLockDelay := 10 * time.Second
+	getR.ListFilter = structs.SessionListFilter{MaxLockDelay: &maxLockDelay}
+	sessions = structs.IndexedSessions{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Session.List", &getR, &sessions))
+	require.Empty(t, sessions.Sessions)
+	require.Equal(t, index, sessions.Index)
+
+	getR.ListFilter = structs.SessionListFilter{MinLockDelay: 30 * time.Second, MaxLockDelay: &maxLockDelay}
+	err := msgpackrpc.CallWithCodec(codec, "Session.List", &getR, &sessions)
+	require.ErrorContains(t, err, "greater than maximum lock delay")
+}
+
 func TestSession_Get_List_NodeSessions_ACLFilter(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -786,6 +980,223 @@ session "foo" {
 	}
 }
 
+func TestSession_RenewAll(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServerWithConfig(t, func(c *Config) {
+		c.PrimaryDatacenter = "dc1"
+		c.ACLsEnabled = true
+		c.ACLInitialManagementToken = "root"
+		c.ACLResolverSettings.ACLDefaultPolicy = "deny"
+	})
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForTestAgent(t, s1.RPC, "dc1", testrpc.WithToken("root"))
+
+	rules := `
+session "foo" {
+	policy = "write"
+}
+`
+	token := createToken(t, codec, rules)
+
+	s1.fsm.State().EnsureNode(1, &structs.Node{Node: "foo", Address: "127.0.0.1"})
+	s1.fsm.State().EnsureNode(2, &structs.Node{Node: "bar", Address: "127.0.0.2"})
+
+	createSession := func(node string) string {
+		arg := structs.SessionRequest{
+			Datacenter: "dc1",
+			Op:         structs.SessionCreate,
+			Session: structs.Session{
+				Node: node,
+				TTL:  "30s",
+			},
+			WriteRequest: structs.WriteRequest{Token: "root"},
+		}
+		var id string
+		require.NoError(t, msgpackrpc.CallWithCodec(codec, "Session.Apply", &arg, &id))
+		return id
+	}
+	fooID := createSession("foo")
+	barID := createSession("bar")
+	missingID := generateUUID()
+
+	_, before, err := s1.fsm.State().SessionGet(nil, fooID, nil)
+	require.NoError(t, err)
+
+	start := time.Now()
+	req := structs.SessionRenewAllRequest{
+		Datacenter:   "dc1",
+		SessionIDs:   []string{fooID, barID, missingID},
+		WriteRequest: structs.WriteRequest{Token: token},
+	}
+	var out structs.SessionRenewAllResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Session.RenewAll", &req, &out))
+	require.Len(t, out.Results, 3)
+
+	foo := out.Results[0]
+	require.Equal(t, fooID, foo.SessionID)
+	require.True(t, foo.Renewed)
+	require.Empty(t, foo.Error)
+	require.WithinDuration(t, start.Add(30*time.Second), foo.Deadline, 5*time.Second)
+
+	bar := out.Results[1]
+	require.Equal(t, barID, bar.SessionID)
+	require.False(t, bar.Renewed)
+	require.Contains(t, bar.Error, acl.ErrPermissionDenied.Error())
+
+	missing := out.Results[2]
+	require.Equal(t, missingID, missing.SessionID)
+	require.False(t, missing.Renewed)
+	require.Equal(t, "session not found", missing.Error)
+
+	// Only the session that was renewed was touched.
+	_, after, err := s1.fsm.State().SessionGet(nil, fooID, nil)
+	require.NoError(t, err)
+	require.Greater(t, after.ModifyIndex, before.ModifyIndex)
+	require.Equal(t, after.ModifyIndex, out.Index)
+
+	_, barSession, err := s1.fsm.State().SessionGet(nil, barID, nil)
+	require.NoError(t, err)
+	require.Equal(t, barSession.CreateIndex, barSession.ModifyIndex)
+}
+
+func TestSession_RenewAll_MinimumVersion(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	_, s1 := testServerWithConfig(t, func(c *Config) {
+		c.Build = "1.21.0"
+	})
+	codec := rpcClient(t, s1)
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	req := structs.SessionRenewAllRequest{
+		Datacenter: "dc1",
+		SessionIDs: []string{generateUUID()},
+	}
+	var out structs.SessionRenewAllResponse
+	err := msgpackrpc.CallWithCodec(codec, "Session.RenewAll", &req, &out)
+	require.ErrorContains(t, err, "can't renew sessions in a batch until all servers >= 1.22.0")
+}
+
+func TestSession_Transfer(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	dir1, s1 := testServer(t)
+	defer os.RemoveAll(dir1)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	defer codec.Close()
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	s1.fsm.State().EnsureNode(1, &structs.Node{Node: "old", Address: "127.0.0.1"})
+	s1.fsm.State().EnsureNode(2, &structs.Node{Node: "new", Address: "127.0.0.2"})
+
+	arg := structs.SessionRequest{
+		Datacenter: "dc1",
+		Op:         structs.SessionCreate,
+		Session: structs.Session{
+			Node: "old",
+			Name: "leader-election",
+		},
+	}
+	var id string
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Session.Apply", &arg, &id))
+
+	lock := structs.KVSRequest{
+		Datacenter: "dc1",
+		Op:         api.KVLock,
+		DirEnt: structs.DirEntry{
+			Key:     "service/leader",
+			Value:   []byte("old"),
+			Session: id,
+		},
+	}
+	var locked bool
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.Apply", &lock, &locked))
+	require.True(t, locked)
+
+	// The target node must exist.
+	req := structs.SessionTransferRequest{
+		Datacenter: "dc1",
+		SessionID:  id,
+		Node:       "missing",
+	}
+	var out structs.IndexedSessions
+	err := msgpackrpc.CallWithCodec(codec, "Session.Transfer", &req, &out)
+	require.ErrorContains(t, err, "Missing node registration")
+
+	req.Node = "new"
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Session.Transfer", &req, &out))
+	require.NotZero(t, out.Index)
+	require.Len(t, out.Sessions, 1)
+	require.Equal(t, id, out.Sessions[0].ID)
+	require.Equal(t, "new", out.Sessions[0].Node)
+
+	// Deregistering the old node must not release the lock.
+	dereg := structs.DeregisterRequest{
+		Datacenter: "dc1",
+		Node:       "old",
+	}
+	var ack struct{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Catalog.Deregister", &dereg, &ack))
+
+	getR := structs.KeyRequest{
+		Datacenter: "dc1",
+		Key:        "service/leader",
+	}
+	var dirent structs.IndexedDirEntries
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "KVS.Get", &getR, &dirent))
+	require.Len(t, dirent.Entries, 1)
+	require.Equal(t, id, dirent.Entries[0].Session)
+
+	var sessions structs.IndexedSessions
+	nodeR := structs.NodeSpecificRequest{
+		Datacenter: "dc1",
+		Node:       "new",
+	}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Session.NodeSessions", &nodeR, &sessions))
+	require.Len(t, sessions.Sessions, 1)
+	require.Equal(t, id, sessions.Sessions[0].ID)
+}
+
+func TestSession_Transfer_MinimumVersion(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	_, s1 := testServerWithConfig(t, func(c *Config) {
+		c.Build = "1.21.0"
+	})
+	codec := rpcClient(t, s1)
+
+	testrpc.WaitForLeader(t, s1.RPC, "dc1")
+
+	req := structs.SessionTransferRequest{
+		Datacenter: "dc1",
+		SessionID:  generateUUID(),
+		Node:       "foo",
+	}
+	var out structs.IndexedSessions
+	err := msgpackrpc.CallWithCodec(codec, "Session.Transfer", &req, &out)
+	require.ErrorContains(t, err, "can't transfer sessions until all servers >= 1.22.0")
+}
 func TestSession_Renew_Compat(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
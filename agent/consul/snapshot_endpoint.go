@@ -12,16 +12,22 @@ package consul
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"net"
+	"os"
 	"time"
 
 	"github.com/hashicorp/consul-net-rpc/go-msgpack/codec"
+	"github.com/hashicorp/go-hclog"
 
+	"github.com/dhiaayachi/consul/agent/consul/fsm"
+	"github.com/dhiaayachi/consul/agent/consul/state"
 	"github.com/dhiaayachi/consul/agent/pool"
 	"github.com/dhiaayachi/consul/agent/structs"
+	raftstorage "github.com/dhiaayachi/consul/internal/storage/raft"
 	"github.com/dhiaayachi/consul/snapshot"
 )
 
@@ -142,11 +148,73 @@ func (s *Server) dispatchSnapshotRequest(args *structs.SnapshotRequest, in io.Re
 		// stream back.
 		return io.NopCloser(bytes.NewReader([]byte(""))), nil
 
+	case structs.SnapshotVerify:
+		index, counts, err := s.verifySnapshot(in)
+		if err != nil {
+			return nil, err
+		}
+		reply.Index = index
+		reply.TableCounts = counts
+
+		// Nothing to stream back, the results are all in the reply.
+		return io.NopCloser(bytes.NewReader([]byte(""))), nil
+
 	default:
 		return nil, fmt.Errorf("unrecognized snapshot op %q", args.Op)
 	}
 }
 
+// verifySnapshot runs the snapshot through the same restore path as a real
+// restore, but into a throwaway FSM, so nothing about the running cluster is
+// changed. It returns the snapshot's index and the number of entries in each
+// table of the restored state store.
+func (s *Server) verifySnapshot(in io.Reader) (uint64, map[string]int, error) {
+	snap, metadata, err := snapshot.Read(s.logger, in)
+	defer func() {
+		if snap == nil {
+			return
+		}
+		if err := snap.Close(); err != nil {
+			s.logger.Error("Failed to close temp snapshot", "error", err)
+		}
+		if err := os.Remove(snap.Name()); err != nil {
+			s.logger.Error("Failed to clean up temp snapshot", "error", err)
+		}
+	}()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// It's safe to pass nil as the handle argument here because we won't call
+	// the backend's data access methods (only Restore).
+	backend, err := raftstorage.NewBackend(nil, hclog.NewNullLogger())
+	if err != nil {
+		return 0, nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go backend.Run(ctx)
+
+	tmpFsm := fsm.NewFromDeps(fsm.Deps{
+		Logger: s.logger.Named("snapshot-verify"),
+		NewStateStore: func() *state.Store {
+			return state.NewStateStore(nil)
+		},
+		StorageBackend: backend,
+	})
+	// The FSM closes the reader it's given, but the deferred cleanup above
+	// owns the temp file.
+	if err := tmpFsm.Restore(io.NopCloser(snap)); err != nil {
+		return 0, nil, fmt.Errorf("failed to restore snapshot: %v", err)
+	}
+
+	counts, err := tmpFsm.State().TableCounts()
+	if err != nil {
+		return 0, nil, err
+	}
+	return metadata.Index, counts, nil
+}
+
 // handleSnapshotRequest reads the request from the conn and dispatches it. This
 // will be called from a goroutine after an incoming stream is determined to be
 // a snapshot request.
@@ -787,6 +787,28 @@ func (s *Store) ACLTokenListExpired(local bool, asOf time.Time, max int) (struct
 	return tokens, iter.WatchCh(), nil
 }
 
+// ACLTokenCountExpired returns the number of tokens that are expired as of
+// the provided time and have not been deleted yet.
+func (s *Store) ACLTokenCountExpired(local bool, asOf time.Time) (int, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	iter, err := tx.Get(tableACLTokens, s.expiresIndexName(local))
+	if err != nil {
+		return 0, fmt.Errorf("failed acl token listing: %v", err)
+	}
+
+	var count int
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		token := raw.(*structs.ACLToken)
+		if token.ExpirationTime != nil && !token.ExpirationTime.Before(asOf) {
+			break
+		}
+		count++
+	}
+	return count, nil
+}
+
 func (s *Store) expiresIndexName(local bool) string {
 	if local {
 		return indexExpiresLocal
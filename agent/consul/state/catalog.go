@@ -4,6 +4,7 @@
 package state
 
 import (
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"net"
@@ -42,11 +43,9 @@ const (
 )
 
 var (
-	// startingVirtualIP is the start of the virtual IP range we assign to services.
-	// The effective CIDR range is startingVirtualIP to (startingVirtualIP + virtualIPMaxOffset).
-	startingVirtualIP = net.IP{240, 0, 0, 0}
-
-	virtualIPMaxOffset = net.IP{15, 255, 255, 254}
+	// DefaultVirtualIPCIDR is the range we assign service virtual IPs from when
+	// the leader hasn't stored a configured range in the system metadata.
+	DefaultVirtualIPCIDR = &net.IPNet{IP: net.IP{240, 0, 0, 0}, Mask: net.CIDRMask(4, 32)}
 
 	ErrNodeNotFound = errors.New("node not found")
 )
@@ -249,6 +248,11 @@ func (s *Store) ensureRegistrationTxn(tx WriteTxn, idx uint64, preserveIndexes b
 				return fmt.Errorf("failed inserting service: %s", err)
 
 			}
+			if req.PeerName == "" {
+				if err := s.updateDependencyChecksTxn(tx, idx, req.Service.Service, &req.Service.EnterpriseMeta); err != nil {
+					return err
+				}
+			}
 		}
 	}
 
@@ -819,6 +823,11 @@ func (s *Store) EnsureService(idx uint64, node string, svc *structs.NodeService)
 	if err := ensureServiceTxn(tx, idx, node, false, svc); err != nil {
 		return err
 	}
+	if svc.PeerName == "" {
+		if err := s.updateDependencyChecksTxn(tx, idx, svc.Service, &svc.EnterpriseMeta); err != nil {
+			return err
+		}
+	}
 
 	return tx.Commit()
 }
@@ -1006,6 +1015,12 @@ func assignServiceVirtualIP(tx WriteTxn, idx uint64, psn structs.PeeredServiceNa
 		return "", fmt.Errorf("failed service virtual IP lookup: %s", err)
 	}
 
+	// Virtual IPs are stored as offsets into the configured range.
+	startingVirtualIP, virtualIPMaxOffset, err := virtualIPRangeTxn(tx)
+	if err != nil {
+		return "", err
+	}
+
 	// Service already has a virtual IP assigned, nothing to do.
 	if serviceVIP != nil {
 		sVIP := serviceVIP.(ServiceVirtualIP).IP
@@ -1212,6 +1227,48 @@ func updateVirtualIPMaxIndexes(txn WriteTxn, idx uint64, partition, peerName str
 	return nil
 }
 
+// virtualIPRangeTxn returns the first IP of the range service virtual IPs are
+// assigned from, along with the largest offset that can be allocated in it.
+func virtualIPRangeTxn(tx ReadTxn) (net.IP, net.IP, error) {
+	cidr := DefaultVirtualIPCIDR
+	_, entry, err := systemMetadataGetTxn(tx, nil, structs.SystemMetadataVirtualIPCIDR)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed system metadata lookup: %s", err)
+	}
+	if entry != nil && entry.Value != "" {
+		_, cidr, err = net.ParseCIDR(entry.Value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid virtual IP CIDR %q: %s", entry.Value, err)
+		}
+	}
+
+	start := cidr.IP.To4()
+	if start == nil || len(cidr.Mask) != net.IPv4len {
+		return nil, nil, fmt.Errorf("virtual IP CIDR %q is not ipv4", cidr)
+	}
+
+	// Allocation stops short of maxOffset, which leaves the last two
+	// addresses of the range unused as has always been the case for the
+	// default 240.0.0.0/4 range.
+	maxOffset := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(maxOffset, ^binary.BigEndian.Uint32(cidr.Mask)-1)
+	return start, maxOffset, nil
+}
+
+// virtualIPWithOffsetTxn returns the address of a virtual IP stored as an
+// offset into the configured range.
+func virtualIPWithOffsetTxn(tx ReadTxn, offset net.IP) (string, error) {
+	start, _, err := virtualIPRangeTxn(tx)
+	if err != nil {
+		return "", err
+	}
+	result, err := addIPOffset(start, offset)
+	if err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
 func addIPOffset(a, b net.IP) (net.IP, error) {
 	a4 := a.To4()
 	b4 := b.To4()
@@ -1550,6 +1607,67 @@ func serviceNodesTxn(tx ReadTxn, ws memdb.WatchSet, index string, q Query) (uint
 	return idx, results, nil
 }
 
+// ServiceInstancesByAddress returns the service instances that are reachable at
+// the given address, either because they were registered with that address or
+// because they have no address of their own and their node has that address.
+func (s *Store) ServiceInstancesByAddress(ws memdb.WatchSet, address string, entMeta *acl.EnterpriseMeta) (uint64, structs.ServiceNodes, error) {
+	tx := s.db.ReadTxn()
+	defer tx.Abort()
+
+	// TODO: accept non-pointer value
+	if entMeta == nil {
+		entMeta = structs.DefaultEnterpriseMetaInDefaultPartition()
+	}
+	q := Query{
+		Value:          address,
+		EnterpriseMeta: *entMeta,
+	}
+
+	services, err := tx.Get(tableServices, indexAddress, q)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed service lookup: %s", err)
+	}
+	ws.Add(services.WatchCh())
+
+	var results structs.ServiceNodes
+	for service := services.Next(); service != nil; service = services.Next() {
+		results = append(results, service.(*structs.ServiceNode))
+	}
+
+	nodes, err := tx.Get(tableNodes, indexAddress, q)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed node lookup: %s", err)
+	}
+	ws.Add(nodes.WatchCh())
+
+	for node := nodes.Next(); node != nil; node = nodes.Next() {
+		n := node.(*structs.Node)
+		nodeServices, err := tx.Get(tableServices, indexNode, Query{
+			Value:          n.Node,
+			EnterpriseMeta: *entMeta,
+		})
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed service lookup: %s", err)
+		}
+		ws.Add(nodeServices.WatchCh())
+
+		for service := nodeServices.Next(); service != nil; service = nodeServices.Next() {
+			if sn := service.(*structs.ServiceNode); sn.ServiceAddress == "" {
+				results = append(results, sn)
+			}
+		}
+	}
+
+	// Fill in the node details.
+	results, err = parseServiceNodes(tx, ws, results, entMeta, structs.DefaultPeerKeyword)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed parsing service nodes: %s", err)
+	}
+
+	idx := catalogMaxIndex(tx, entMeta, structs.DefaultPeerKeyword, false)
+	return idx, results, nil
+}
+
 // ServiceTagNodes returns the nodes associated with a given service, filtering
 // out services that don't contain the given tags.
 func (s *Store) ServiceTagNodes(ws memdb.WatchSet, service string, tags []string, entMeta *acl.EnterpriseMeta, peerName string) (uint64, structs.ServiceNodes, error) {
@@ -2119,6 +2237,9 @@ func (s *Store) deleteServiceTxn(tx WriteTxn, idx uint64, nodeName, serviceID st
 		if err := cleanupGatewayWildcards(tx, idx, sn, false); err != nil {
 			return fmt.Errorf("failed to clean up gateway-service associations for %q: %v", psn.String(), err)
 		}
+		if err := s.updateDependencyChecksTxn(tx, idx, svc.ServiceName, &svc.EnterpriseMeta); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -2140,7 +2261,45 @@ func freeServiceVirtualIP(
 		return nil
 	}
 
-	// Don't deregister the virtual IP if at least one instance of this service still exists.
+	inUse, err := serviceVirtualIPInUse(tx, psn, excludeGateway)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return nil
+	}
+
+	serviceVIP, err := tx.First(tableServiceVirtualIPs, indexID, psn)
+	if err != nil {
+		return fmt.Errorf("failed service virtual IP lookup: %s", err)
+	}
+	// Service has no virtual IP assigned, nothing to do.
+	if serviceVIP == nil {
+		return nil
+	}
+
+	// Delete the service virtual IP and add it to the freed IPs list.
+	if err := tx.Delete(tableServiceVirtualIPs, serviceVIP); err != nil {
+		return fmt.Errorf("failed updating freed virtual IP table: %v", err)
+	}
+
+	newEntry := FreeVirtualIP{IP: serviceVIP.(ServiceVirtualIP).IP}
+	if err := tx.Insert(tableFreeVirtualIPs, newEntry); err != nil {
+		return fmt.Errorf("failed updating freed virtual IP table: %v", err)
+	}
+
+	if err := updateVirtualIPMaxIndexes(tx, idx, psn.ServiceName.PartitionOrDefault(), psn.Peer); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// serviceVirtualIPInUse returns true if the virtual IP assigned to the given
+// service must be kept, either because an instance of the service still exists
+// or because a config entry or terminating gateway still references it.
+func serviceVirtualIPInUse(tx ReadTxn, psn structs.PeeredServiceName, excludeGateway *structs.ServiceName) (bool, error) {
+	// The virtual IP is in use while at least one instance of this service still exists.
 	q := Query{
 		Value:          psn.ServiceName.Name,
 		EnterpriseMeta: psn.ServiceName.EnterpriseMeta,
@@ -2148,13 +2307,13 @@ func freeServiceVirtualIP(
 	}
 	if remainingService, err := tx.First(tableServices, indexService, q); err == nil {
 		if remainingService != nil {
-			return nil
+			return true, nil
 		}
 	} else {
-		return fmt.Errorf("failed service lookup for %q: %s", psn.ServiceName.Name, err)
+		return false, fmt.Errorf("failed service lookup for %q: %s", psn.ServiceName.Name, err)
 	}
 
-	// Don't deregister the virtual IP if at least one resolver/router/splitter config entry still
+	// The virtual IP is in use while at least one resolver/router/splitter config entry still
 	// references this service.
 	configEntryVIPKinds := []string{
 		structs.ServiceResolver,
@@ -2166,58 +2325,35 @@ func freeServiceVirtualIP(
 	for _, kind := range configEntryVIPKinds {
 		_, entry, err := configEntryTxn(tx, nil, kind, psn.ServiceName.Name, &psn.ServiceName.EnterpriseMeta)
 		if err != nil {
-			return fmt.Errorf("failed config entry lookup for %s/%s: %s", kind, psn.ServiceName.Name, err)
+			return false, fmt.Errorf("failed config entry lookup for %s/%s: %s", kind, psn.ServiceName.Name, err)
 		}
 		if entry != nil {
-			return nil
+			return true, nil
 		}
 	}
 
-	// Don't deregister the virtual IP if at least one terminating gateway still references this service.
+	// The virtual IP is in use while at least one terminating gateway still references this service.
 	termGatewaySupported, err := terminatingGatewayVirtualIPsSupported(tx, nil)
 	if err != nil {
-		return err
+		return false, err
 	}
 	if termGatewaySupported {
 		svcGateways, err := tx.Get(tableGatewayServices, indexService, psn.ServiceName)
 		if err != nil {
-			return fmt.Errorf("failed gateway lookup for %q: %s", psn.ServiceName.Name, err)
+			return false, fmt.Errorf("failed gateway lookup for %q: %s", psn.ServiceName.Name, err)
 		}
 
 		for service := svcGateways.Next(); service != nil; service = svcGateways.Next() {
 			if svc, ok := service.(*structs.GatewayService); ok && svc != nil {
 				ignoreGateway := excludeGateway == nil || !svc.Gateway.Matches(*excludeGateway)
 				if ignoreGateway && svc.GatewayKind == structs.ServiceKindTerminatingGateway {
-					return nil
+					return true, nil
 				}
 			}
 		}
 	}
 
-	serviceVIP, err := tx.First(tableServiceVirtualIPs, indexID, psn)
-	if err != nil {
-		return fmt.Errorf("failed service virtual IP lookup: %s", err)
-	}
-	// Service has no virtual IP assigned, nothing to do.
-	if serviceVIP == nil {
-		return nil
-	}
-
-	// Delete the service virtual IP and add it to the freed IPs list.
-	if err := tx.Delete(tableServiceVirtualIPs, serviceVIP); err != nil {
-		return fmt.Errorf("failed updating freed virtual IP table: %v", err)
-	}
-
-	newEntry := FreeVirtualIP{IP: serviceVIP.(ServiceVirtualIP).IP}
-	if err := tx.Insert(tableFreeVirtualIPs, newEntry); err != nil {
-		return fmt.Errorf("failed updating freed virtual IP table: %v", err)
-	}
-
-	if err := updateVirtualIPMaxIndexes(tx, idx, psn.ServiceName.PartitionOrDefault(), psn.Peer); err != nil {
-		return err
-	}
-
-	return nil
+	return false, nil
 }
 
 // EnsureCheck is used to store a check registration in the db.
@@ -2316,6 +2452,15 @@ func (s *Store) ensureCheckTxn(tx WriteTxn, idx uint64, preserveIndexes bool, hc
 		hc.Status = api.HealthCritical
 	}
 
+	// The status of dependency checks is derived from the health of the
+	// service they depend on rather than reported by an agent.
+	if hc.Type == dependencyCheckType && hc.PeerName == "" {
+		hc.Status, hc.Output, err = dependencyCheckStatusTxn(tx, hc.Definition.DependencyService, &hc.EnterpriseMeta)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Get the node
 	node, err := tx.First(tableNodes, indexID, Query{
 		Value:          hc.Node,
@@ -2395,7 +2540,117 @@ func (s *Store) ensureCheckTxn(tx WriteTxn, idx uint64, preserveIndexes bool, hc
 		hc.ModifyIndex = idx
 	}
 
-	return catalogInsertCheck(tx, hc, idx)
+	if err := catalogInsertCheck(tx, hc, idx); err != nil {
+		return err
+	}
+	return s.updateCheckDependentsTxn(tx, idx, hc)
+}
+
+// dependencyCheckType is the type of checks that are critical while the
+// service named by their DependencyService has no passing instances. Like
+// session checks, their status is maintained by the state store.
+const dependencyCheckType = "dependency"
+
+// dependencyCheckStatusTxn returns the status and output of a dependency check
+// on the named service. An instance is passing when all of its service and
+// node checks are passing.
+func dependencyCheckStatusTxn(tx ReadTxn, service string, entMeta *acl.EnterpriseMeta) (string, string, error) {
+	services, err := tx.Get(tableServices, indexService, Query{
+		Value:          service,
+		EnterpriseMeta: *entMeta,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed service lookup: %s", err)
+	}
+
+	var passing int
+	for raw := services.Next(); raw != nil; raw = services.Next() {
+		svc := raw.(*structs.ServiceNode)
+		checks, err := tx.Get(tableChecks, indexNode, Query{
+			Value:          svc.Node,
+			EnterpriseMeta: *svc.EnterpriseMeta.WithWildcardNamespace(),
+		})
+		if err != nil {
+			return "", "", fmt.Errorf("failed check lookup: %s", err)
+		}
+
+		healthy := true
+		for check := checks.Next(); check != nil; check = checks.Next() {
+			hc := check.(*structs.HealthCheck)
+			if hc.ServiceID != "" && hc.ServiceID != svc.ServiceID {
+				continue
+			}
+			if hc.Status != api.HealthPassing {
+				healthy = false
+				break
+			}
+		}
+		if healthy {
+			passing++
+		}
+	}
+
+	if passing == 0 {
+		return api.HealthCritical, fmt.Sprintf("Service '%s' has no passing instances", service), nil
+	}
+	return api.HealthPassing, fmt.Sprintf("Service '%s' has %d passing instance(s)", service, passing), nil
+}
+
+// updateDependencyChecksTxn re-evaluates the dependency checks on the named
+// service after the health of its instances may have changed.
+func (s *Store) updateDependencyChecksTxn(tx WriteTxn, idx uint64, service string, entMeta *acl.EnterpriseMeta) error {
+	iter, err := tx.Get(tableChecks, indexDependency, Query{
+		Value:          service,
+		EnterpriseMeta: *entMeta,
+	})
+	if err != nil {
+		return fmt.Errorf("failed dependency check lookup: %s", err)
+	}
+
+	var checks []*structs.HealthCheck
+	for check := iter.Next(); check != nil; check = iter.Next() {
+		checks = append(checks, check.(*structs.HealthCheck).Clone())
+	}
+
+	// Update the checks in a separate loop so we don't trash the iterator.
+	// Checks whose status did not change are left untouched.
+	for _, hc := range checks {
+		if err := s.ensureCheckTxn(tx, idx, false, hc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateCheckDependentsTxn re-evaluates the dependency checks that a change
+// to the given check may affect: those on the check's service or, for node
+// checks, on every service registered on the node.
+func (s *Store) updateCheckDependentsTxn(tx WriteTxn, idx uint64, hc *structs.HealthCheck) error {
+	if hc.PeerName != "" {
+		return nil
+	}
+	if hc.ServiceName != "" {
+		return s.updateDependencyChecksTxn(tx, idx, hc.ServiceName, &hc.EnterpriseMeta)
+	}
+
+	services, err := tx.Get(tableServices, indexNode, Query{
+		Value:          hc.Node,
+		EnterpriseMeta: *hc.EnterpriseMeta.WithWildcardNamespace(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed service lookup: %s", err)
+	}
+	var names []structs.ServiceName
+	for raw := services.Next(); raw != nil; raw = services.Next() {
+		names = append(names, raw.(*structs.ServiceNode).CompoundServiceName().ServiceName)
+	}
+
+	for _, sn := range names {
+		if err := s.updateDependencyChecksTxn(tx, idx, sn.Name, &sn.EnterpriseMeta); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // NodeCheck is used to retrieve a specific check associated with the given
@@ -2557,6 +2812,37 @@ func (s *Store) ChecksInStateByNodeMeta(ws memdb.WatchSet, state string, filters
 	return parseChecksByNodeMeta(tx, ws, idx, iter, filters, entMeta, peerName)
 }
 
+// ChecksByType is used to query the state store for all checks of the given
+// type, such as "http" or "ttl".
+func (s *Store) ChecksByType(ws memdb.WatchSet, checkType string, entMeta *acl.EnterpriseMeta, peerName string) (uint64, structs.HealthChecks, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	// Get the table index.
+	idx := catalogChecksMaxIndex(tx, entMeta, peerName)
+
+	if entMeta == nil {
+		entMeta = structs.DefaultEnterpriseMetaInDefaultPartition()
+	}
+
+	q := Query{
+		Value:          checkType,
+		EnterpriseMeta: *entMeta,
+		PeerName:       peerName,
+	}
+	iter, err := tx.Get(tableChecks, indexCheckType, q)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed check lookup: %s", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	var results structs.HealthChecks
+	for check := iter.Next(); check != nil; check = iter.Next() {
+		results = append(results, check.(*structs.HealthCheck))
+	}
+	return idx, results, nil
+}
+
 func checksInStateTxn(tx ReadTxn, ws memdb.WatchSet, state string, entMeta *acl.EnterpriseMeta, peerName string) (uint64, memdb.ResultIterator, error) {
 	// Get the table index.
 	idx := catalogChecksMaxIndex(tx, entMeta, peerName)
@@ -2792,7 +3078,7 @@ func (s *Store) deleteCheckTxn(tx WriteTxn, idx uint64, node string, checkID typ
 		}
 	}
 
-	return nil
+	return s.updateCheckDependentsTxn(tx, idx, existing)
 }
 
 // CombinedCheckServiceNodes is used to query all nodes and checks for both typical and Connect endpoints of a service
@@ -3011,6 +3297,50 @@ func checkServiceNodesTxn(tx ReadTxn, ws memdb.WatchSet, serviceName string, con
 	return parseCheckServiceNodes(tx, fallbackWS, idx, results, entMeta, peerName, err)
 }
 
+// NodesByServiceHealth returns the names of the nodes running an instance of
+// the given service, grouped by the health of that instance. The health of an
+// instance is the worst status among its node and service checks, so an
+// instance with any critical check is reported as critical. A node running
+// several instances of the service is listed once under each status they
+// report.
+func (s *Store) NodesByServiceHealth(ws memdb.WatchSet, serviceName string, entMeta *acl.EnterpriseMeta) (uint64, map[string][]string, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	idx, csns, err := checkServiceNodesTxn(tx, ws, serviceName, false, entMeta, structs.DefaultPeerKeyword)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	seen := make(map[string]map[string]struct{})
+	results := make(map[string][]string)
+	for _, csn := range csns {
+		status := api.HealthPassing
+		for _, check := range csn.Checks {
+			switch {
+			case check.Status == api.HealthCritical:
+				status = api.HealthCritical
+			case check.Status == api.HealthWarning && status == api.HealthPassing:
+				status = api.HealthWarning
+			}
+		}
+
+		if _, ok := seen[status]; !ok {
+			seen[status] = make(map[string]struct{})
+		}
+		if _, ok := seen[status][csn.Node.Node]; ok {
+			continue
+		}
+		seen[status][csn.Node.Node] = struct{}{}
+		results[status] = append(results[status], csn.Node.Node)
+	}
+
+	for _, nodes := range results {
+		sort.Strings(nodes)
+	}
+	return idx, results, nil
+}
+
 // CheckServiceTagNodes is used to query all nodes and checks for a given
 // service, filtering out services that don't contain the given tag.
 func (s *Store) CheckServiceTagNodes(ws memdb.WatchSet, serviceName string, tags []string, entMeta *acl.EnterpriseMeta, peerName string) (uint64, structs.CheckServiceNodes, error) {
@@ -3096,7 +3426,44 @@ func (s *Store) VirtualIPForService(psn structs.PeeredServiceName) (string, erro
 		return "", nil
 	}
 
-	return vip.(ServiceVirtualIP).IPWithOffset()
+	return virtualIPWithOffsetTxn(tx, vip.(ServiceVirtualIP).IP)
+}
+
+// ServiceByVirtualIP returns the service that owns the given virtual IP,
+// either because it was assigned from the virtual IP range or because it was
+// set as one of the service's manual virtual IPs. It returns nil if no service
+// owns the address.
+func (s *Store) ServiceByVirtualIP(ws memdb.WatchSet, vip string) (uint64, *structs.PeeredServiceName, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	ip := net.ParseIP(vip).To4()
+	if ip == nil {
+		return 0, nil, fmt.Errorf("invalid virtual IP %q: must be an IPv4 address", vip)
+	}
+
+	idx, vips, err := servicesVirtualIPsTxn(tx, ws)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed service virtual IP lookup: %s", err)
+	}
+
+	for _, entry := range vips {
+		assigned, err := virtualIPWithOffsetTxn(tx, entry.IP)
+		if err != nil {
+			return 0, nil, err
+		}
+		if assigned == ip.String() {
+			psn := entry.Service
+			return idx, &psn, nil
+		}
+		for _, manual := range entry.ManualIPs {
+			if net.ParseIP(manual).Equal(ip) {
+				psn := entry.Service
+				return idx, &psn, nil
+			}
+		}
+	}
+	return idx, nil, nil
 }
 
 func (s *Store) ServiceVirtualIPs() (uint64, []ServiceVirtualIP, error) {
@@ -3106,6 +3473,72 @@ func (s *Store) ServiceVirtualIPs() (uint64, []ServiceVirtualIP, error) {
 	return servicesVirtualIPsTxn(tx, nil)
 }
 
+// VirtualIPsAllocated returns whether any service virtual IP has been allocated
+// from the current range, including ones that were freed again since.
+func (s *Store) VirtualIPsAllocated() (bool, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	counter, err := tx.First(tableFreeVirtualIPs, indexCounterOnly, true)
+	if err != nil {
+		return false, fmt.Errorf("failed virtual IP index lookup: %s", err)
+	}
+	if counter != nil {
+		return true, nil
+	}
+
+	vip, err := tx.First(tableServiceVirtualIPs, indexID)
+	if err != nil {
+		return false, fmt.Errorf("failed service virtual IP lookup: %s", err)
+	}
+	return vip != nil, nil
+}
+
+// OrphanedServiceVirtualIPs returns the services that still hold a virtual IP
+// even though they have no remaining instances and are not referenced by any
+// config entry or terminating gateway.
+func (s *Store) OrphanedServiceVirtualIPs() ([]structs.PeeredServiceName, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	supported, err := virtualIPsSupported(tx, nil)
+	if err != nil || !supported {
+		return nil, err
+	}
+
+	_, vips, err := servicesVirtualIPsTxn(tx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed service virtual IP lookup: %s", err)
+	}
+
+	var orphaned []structs.PeeredServiceName
+	for _, vip := range vips {
+		inUse, err := serviceVirtualIPInUse(tx, vip.Service, nil)
+		if err != nil {
+			return nil, err
+		}
+		if !inUse {
+			orphaned = append(orphaned, vip.Service)
+		}
+	}
+	return orphaned, nil
+}
+
+// ReclaimServiceVirtualIPs frees the virtual IPs of the given services. Services
+// that have been registered or referenced again since they were found to be
+// orphaned keep their virtual IP.
+func (s *Store) ReclaimServiceVirtualIPs(idx uint64, services []structs.PeeredServiceName) error {
+	tx := s.db.WriteTxn(idx)
+	defer tx.Abort()
+
+	for _, psn := range services {
+		if err := freeServiceVirtualIP(tx, idx, psn, nil); err != nil {
+			return fmt.Errorf("failed to clean up virtual IP for %q: %v", psn.String(), err)
+		}
+	}
+	return tx.Commit()
+}
+
 func servicesVirtualIPsTxn(tx ReadTxn, ws memdb.WatchSet) (uint64, []ServiceVirtualIP, error) {
 	iter, err := tx.Get(tableServiceVirtualIPs, indexID)
 	if err != nil {
@@ -177,6 +177,66 @@ func testIndexerTableChecks() map[string]indexerTestCase {
 				},
 			},
 		},
+		indexDependency: {
+			read: indexValue{
+				source:   Query{Value: "BaCkEnD"},
+				expected: []byte("~\x00backend\x00"),
+			},
+			write: indexValue{
+				source: &structs.HealthCheck{
+					Node:       "NoDe",
+					CheckID:    "CheckID",
+					Type:       "dependency",
+					Definition: structs.HealthCheckDefinition{DependencyService: "BackEnd"},
+				},
+				expected: []byte("~\x00backend\x00"),
+			},
+			extra: []indexerTestCase{
+				{
+					write: indexValue{
+						source:               obj,
+						expectedIndexMissing: true,
+					},
+				},
+			},
+		},
+		indexCheckType: {
+			read: indexValue{
+				source:   Query{Value: "HTTP"},
+				expected: []byte("~\x00http\x00"),
+			},
+			write: indexValue{
+				source: &structs.HealthCheck{
+					Node:    "NoDe",
+					CheckID: "CheckID",
+					Type:    "http",
+				},
+				expected: []byte("~\x00http\x00"),
+			},
+			extra: []indexerTestCase{
+				{
+					read: indexValue{
+						source:   Query{Value: "HTTP", PeerName: "Peer1"},
+						expected: []byte("peer1\x00http\x00"),
+					},
+					write: indexValue{
+						source: &structs.HealthCheck{
+							Node:     "NoDe",
+							CheckID:  "CheckID",
+							Type:     "http",
+							PeerName: "Peer1",
+						},
+						expected: []byte("peer1\x00http\x00"),
+					},
+				},
+				{
+					write: indexValue{
+						source:               obj,
+						expectedIndexMissing: true,
+					},
+				},
+			},
+		},
 	}
 }
 
@@ -431,6 +491,34 @@ func testIndexerTableNodes() map[string]indexerTestCase {
 				},
 			},
 		},
+		indexAddress: {
+			read: indexValue{
+				source:   Query{Value: "10.0.0.1"},
+				expected: []byte("~\x0010.0.0.1\x00"),
+			},
+			write: indexValue{
+				source:   &structs.Node{Node: "NoDeId", Address: "10.0.0.1"},
+				expected: []byte("~\x0010.0.0.1\x00"),
+			},
+			extra: []indexerTestCase{
+				{
+					read: indexValue{
+						source:   Query{Value: "10.0.0.1", PeerName: "Peer1"},
+						expected: []byte("peer1\x0010.0.0.1\x00"),
+					},
+					write: indexValue{
+						source:   &structs.Node{Node: "NoDeId", Address: "10.0.0.1", PeerName: "Peer1"},
+						expected: []byte("peer1\x0010.0.0.1\x00"),
+					},
+				},
+				{
+					write: indexValue{
+						source:               &structs.Node{Node: "NoDeId"},
+						expectedIndexMissing: true,
+					},
+				},
+			},
+		},
 
 		// TODO(partitions): fix schema tests for tables that reference nodes too
 	}
@@ -671,6 +759,41 @@ func testIndexerTableServices() map[string]indexerTestCase {
 				},
 			},
 		},
+		indexAddress: {
+			read: indexValue{
+				source:   Query{Value: "10.0.0.1"},
+				expected: []byte("~\x0010.0.0.1\x00"),
+			},
+			write: indexValue{
+				source: &structs.ServiceNode{
+					ServiceName:    "ServiceName",
+					ServiceAddress: "10.0.0.1",
+				},
+				expected: []byte("~\x0010.0.0.1\x00"),
+			},
+			extra: []indexerTestCase{
+				{
+					read: indexValue{
+						source:   Query{Value: "10.0.0.1", PeerName: "Peer1"},
+						expected: []byte("peer1\x0010.0.0.1\x00"),
+					},
+					write: indexValue{
+						source: &structs.ServiceNode{
+							ServiceName:    "ServiceName",
+							ServiceAddress: "10.0.0.1",
+							PeerName:       "Peer1",
+						},
+						expected: []byte("peer1\x0010.0.0.1\x00"),
+					},
+				},
+				{
+					write: indexValue{
+						source:               obj,
+						expectedIndexMissing: true,
+					},
+				},
+			},
+		},
 	}
 }
 
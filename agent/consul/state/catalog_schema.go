@@ -40,6 +40,9 @@ const (
 	indexMeta        = "meta"
 	indexCounterOnly = "counter"
 	indexManualVIPs  = "manual-vips"
+	indexAddress     = "address"
+	indexDependency  = "dependency"
+	indexCheckType   = "check_type"
 )
 
 // nodesTableSchema returns a new table schema used for storing struct.Node.
@@ -76,6 +79,15 @@ func nodesTableSchema() *memdb.TableSchema {
 					writeIndexMulti: multiIndexWithPeerName(indexMetaFromNode),
 				},
 			},
+			indexAddress: {
+				Name:         indexAddress,
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: indexerSingle[Query, *structs.Node]{
+					readIndex:  indexWithPeerName(indexFromQuery),
+					writeIndex: indexWithPeerName(indexAddressFromNode),
+				},
+			},
 		},
 	}
 }
@@ -103,6 +115,16 @@ func indexIDFromNode(n *structs.Node) ([]byte, error) {
 	return v, nil
 }
 
+func indexAddressFromNode(n *structs.Node) ([]byte, error) {
+	if n.Address == "" {
+		return nil, errMissingValueForIndex
+	}
+
+	var b indexBuilder
+	b.String(strings.ToLower(n.Address))
+	return b.Bytes(), nil
+}
+
 func indexMetaFromNode(n *structs.Node) ([][]byte, error) {
 	// NOTE: this is case-sensitive!
 
@@ -176,10 +198,32 @@ func servicesTableSchema() *memdb.TableSchema {
 					writeIndex: indexWithPeerName(indexKindFromServiceNode),
 				},
 			},
+			indexAddress: {
+				Name:         indexAddress,
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: indexerSingle[Query, *structs.ServiceNode]{
+					readIndex:  indexWithPeerName(indexFromQuery),
+					writeIndex: indexWithPeerName(indexAddressFromServiceNode),
+				},
+			},
 		},
 	}
 }
 
+// indexAddressFromServiceNode indexes the address the service instance was
+// registered with. Instances without a service address are reachable at their
+// node's address, which is indexed in the nodes table instead.
+func indexAddressFromServiceNode(n *structs.ServiceNode) ([]byte, error) {
+	if n.ServiceAddress == "" {
+		return nil, errMissingValueForIndex
+	}
+
+	var b indexBuilder
+	b.String(strings.ToLower(n.ServiceAddress))
+	return b.Bytes(), nil
+}
+
 func indexFromNodeServiceQuery(q NodeServiceQuery) ([]byte, error) {
 	var b indexBuilder
 	b.String(strings.ToLower(q.Node))
@@ -365,6 +409,24 @@ func checksTableSchema() *memdb.TableSchema {
 					writeIndex: indexWithPeerName(indexNodeServiceFromHealthCheck),
 				},
 			},
+			indexDependency: {
+				Name:         indexDependency,
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: indexerSingle[Query, *structs.HealthCheck]{
+					readIndex:  indexWithPeerName(indexFromQuery),
+					writeIndex: indexWithPeerName(indexDependencyFromHealthCheck),
+				},
+			},
+			indexCheckType: {
+				Name:         indexCheckType,
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: indexerSingle[Query, *structs.HealthCheck]{
+					readIndex:  indexWithPeerName(indexFromQuery),
+					writeIndex: indexWithPeerName(indexTypeFromHealthCheck),
+				},
+			},
 		},
 	}
 }
@@ -412,6 +474,30 @@ func indexStatusFromHealthCheck(hc *structs.HealthCheck) ([]byte, error) {
 	return b.Bytes(), nil
 }
 
+// indexTypeFromHealthCheck indexes checks by their type, such as "http" or
+// "ttl". Checks registered without a type are not indexed.
+func indexTypeFromHealthCheck(hc *structs.HealthCheck) ([]byte, error) {
+	if hc.Type == "" {
+		return nil, errMissingValueForIndex
+	}
+
+	var b indexBuilder
+	b.String(strings.ToLower(hc.Type))
+	return b.Bytes(), nil
+}
+
+// indexDependencyFromHealthCheck indexes dependency checks by the name of the
+// service they depend on.
+func indexDependencyFromHealthCheck(hc *structs.HealthCheck) ([]byte, error) {
+	if hc.Type != dependencyCheckType || hc.Definition.DependencyService == "" {
+		return nil, errMissingValueForIndex
+	}
+
+	var b indexBuilder
+	b.String(strings.ToLower(hc.Definition.DependencyService))
+	return b.Bytes(), nil
+}
+
 func indexServiceNameFromHealthCheck(hc *structs.HealthCheck) ([]byte, error) {
 	if hc.ServiceName == "" {
 		return nil, errMissingValueForIndex
@@ -616,14 +702,6 @@ type ServiceVirtualIP struct {
 	structs.RaftIndex
 }
 
-func (s ServiceVirtualIP) IPWithOffset() (string, error) {
-	result, err := addIPOffset(startingVirtualIP, s.IP)
-	if err != nil {
-		return "", err
-	}
-	return result.String(), nil
-}
-
 // FreeVirtualIP is used to store a virtual IP freed up by a service deregistration.
 // It is also used to store free virtual IPs when a snapshot is created.
 type FreeVirtualIP struct {
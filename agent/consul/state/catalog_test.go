@@ -1959,6 +1959,124 @@ func TestStateStore_EnsureService_VirtualIPAssign(t *testing.T) {
 	assert.Equal(t, ns5.Port, taggedAddress.Port)
 }
 
+func TestStateStore_EnsureService_VirtualIPAssign_CustomCIDR(t *testing.T) {
+	s := testStateStore(t)
+	setVirtualIPFlags(t, s)
+	require.NoError(t, s.SystemMetadataSet(0, &structs.SystemMetadataEntry{
+		Key:   structs.SystemMetadataVirtualIPCIDR,
+		Value: "100.64.0.0/30",
+	}))
+
+	testRegisterNode(t, s, 0, "node1")
+	register := func(idx uint64, name string) error {
+		return s.EnsureService(idx, "node1", &structs.NodeService{
+			ID:      name,
+			Service: name,
+			Port:    1111,
+			Connect: structs.ServiceConnect{Native: true},
+		})
+	}
+
+	// The first service gets the first address of the configured range.
+	require.NoError(t, register(10, "foo"))
+	vip, err := s.VirtualIPForService(structs.PeeredServiceName{ServiceName: structs.ServiceName{Name: "foo"}})
+	require.NoError(t, err)
+	require.Equal(t, "100.64.0.1", vip)
+
+	_, out, err := s.NodeServices(nil, "node1", nil, "")
+	require.NoError(t, err)
+	require.Equal(t, vip, out.Services["foo"].TaggedAddresses[structs.TaggedAddressVirtualIP].Address)
+
+	// A /30 only has room for a single virtual IP since the network address
+	// and the last two addresses of the range are never allocated.
+	err = register(11, "bar")
+	require.ErrorContains(t, err, "cannot allocate any more unique service virtual IPs")
+}
+
+func TestStateStore_VirtualIPsAllocated(t *testing.T) {
+	s := testStateStore(t)
+	setVirtualIPFlags(t, s)
+
+	allocated, err := s.VirtualIPsAllocated()
+	require.NoError(t, err)
+	require.False(t, allocated)
+
+	testRegisterNode(t, s, 0, "node1")
+	require.NoError(t, s.EnsureService(10, "node1", &structs.NodeService{
+		ID:      "foo",
+		Service: "foo",
+		Connect: structs.ServiceConnect{Native: true},
+	}))
+
+	allocated, err = s.VirtualIPsAllocated()
+	require.NoError(t, err)
+	require.True(t, allocated)
+
+	// Freed virtual IPs still count since they are reused from the same range.
+	require.NoError(t, s.DeleteService(11, "node1", "foo", nil, ""))
+	allocated, err = s.VirtualIPsAllocated()
+	require.NoError(t, err)
+	require.True(t, allocated)
+}
+
+func TestStateStore_ServiceByVirtualIP(t *testing.T) {
+	s := testStateStore(t)
+	setVirtualIPFlags(t, s)
+
+	entMeta := structs.DefaultEnterpriseMetaInDefaultPartition()
+	newPSN := func(name string) *structs.PeeredServiceName {
+		return &structs.PeeredServiceName{ServiceName: structs.NewServiceName(name, entMeta)}
+	}
+
+	checkOwner := func(t *testing.T, vip string, expect *structs.PeeredServiceName) {
+		t.Helper()
+		_, psn, err := s.ServiceByVirtualIP(nil, vip)
+		require.NoError(t, err)
+		require.Equal(t, expect, psn)
+	}
+
+	// A connect-native service gets a virtual IP on registration.
+	testRegisterNode(t, s, 0, "node1")
+	require.NoError(t, s.EnsureService(10, "node1", &structs.NodeService{
+		ID:             "foo",
+		Service:        "foo",
+		Port:           1111,
+		Connect:        structs.ServiceConnect{Native: true},
+		EnterpriseMeta: *entMeta,
+	}))
+	checkOwner(t, "240.0.0.1", newPSN("foo"))
+
+	// A service linked to a terminating gateway gets one when the gateway's
+	// config entry is written.
+	require.NoError(t, s.EnsureService(11, "node1", &structs.NodeService{
+		Kind:           structs.ServiceKindTerminatingGateway,
+		ID:             "gateway",
+		Service:        "gateway",
+		Port:           443,
+		EnterpriseMeta: *entMeta,
+	}))
+	require.NoError(t, s.EnsureConfigEntry(12, &structs.TerminatingGatewayConfigEntry{
+		Kind:     structs.TerminatingGateway,
+		Name:     "gateway",
+		Services: []structs.LinkedService{{Name: "external"}},
+	}))
+	checkOwner(t, "240.0.0.2", newPSN("external"))
+
+	// Manual virtual IPs resolve to their service too.
+	found, _, err := s.AssignManualServiceVIPs(13, *newPSN("foo"), []string{"127.0.0.10"})
+	require.NoError(t, err)
+	require.True(t, found)
+	checkOwner(t, "127.0.0.10", newPSN("foo"))
+	checkOwner(t, "240.0.0.1", newPSN("foo"))
+
+	// Unassigned addresses are not found.
+	checkOwner(t, "240.0.0.3", nil)
+	checkOwner(t, "127.0.0.11", nil)
+
+	_, _, err = s.ServiceByVirtualIP(nil, "not-an-ip")
+	require.ErrorContains(t, err, `invalid virtual IP "not-an-ip"`)
+}
+
 func TestStateStore_AssignManualVirtualIPs(t *testing.T) {
 	s := testStateStore(t)
 	setVirtualIPFlags(t, s)
@@ -2592,6 +2710,55 @@ func patchWatchLimit(t *testing.T, limit int) {
 	})
 }
 
+func TestStateStore_ServiceInstancesByAddress(t *testing.T) {
+	s := testStateStore(t)
+
+	ws := memdb.NewWatchSet()
+	idx, instances, err := s.ServiceInstancesByAddress(ws, "10.0.0.1", nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), idx)
+	require.Empty(t, instances)
+
+	require.NoError(t, s.EnsureNode(10, &structs.Node{Node: "foo", Address: "10.0.0.1"}))
+	require.NoError(t, s.EnsureNode(11, &structs.Node{Node: "bar", Address: "10.0.0.2"}))
+
+	// The api service inherits the address of its node, while the db service
+	// registers the same address explicitly on a different node.
+	require.NoError(t, s.EnsureService(12, "foo", &structs.NodeService{ID: "api", Service: "api", Port: 5000}))
+	require.NoError(t, s.EnsureService(13, "bar", &structs.NodeService{ID: "db", Service: "db", Address: "10.0.0.1", Port: 8000}))
+
+	// These are not reachable at the address.
+	require.NoError(t, s.EnsureService(14, "foo", &structs.NodeService{ID: "web", Service: "web", Address: "10.0.0.3", Port: 8080}))
+	require.NoError(t, s.EnsureService(15, "bar", &structs.NodeService{ID: "cache", Service: "cache", Port: 6379}))
+	require.True(t, watchFired(ws))
+
+	ws = memdb.NewWatchSet()
+	idx, instances, err = s.ServiceInstancesByAddress(ws, "10.0.0.1", nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(15), idx)
+	require.Len(t, instances, 2)
+
+	byID := make(map[string]*structs.ServiceNode)
+	for _, instance := range instances {
+		byID[instance.ServiceID] = instance
+	}
+	require.Contains(t, byID, "api")
+	require.Equal(t, "foo", byID["api"].Node)
+	require.Equal(t, "10.0.0.1", byID["api"].Address)
+	require.Contains(t, byID, "db")
+	require.Equal(t, "bar", byID["db"].Node)
+	require.Equal(t, "10.0.0.1", byID["db"].ServiceAddress)
+
+	// Moving the node away from the address drops the instances inheriting it.
+	require.NoError(t, s.EnsureNode(16, &structs.Node{Node: "foo", Address: "10.0.0.4"}))
+	require.True(t, watchFired(ws))
+
+	_, instances, err = s.ServiceInstancesByAddress(nil, "10.0.0.1", nil)
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	require.Equal(t, "db", instances[0].ServiceID)
+}
+
 func TestStateStore_ServiceNodes(t *testing.T) {
 	s := testStateStore(t)
 
@@ -3293,6 +3460,66 @@ func TestStateStore_EnsureCheck_defaultStatus(t *testing.T) {
 	}
 }
 
+func TestStateStore_EnsureCheck_dependency(t *testing.T) {
+	s := testStateStore(t)
+
+	testRegisterNode(t, s, 1, "node1")
+	testRegisterService(t, s, 2, "node1", "backend")
+	testRegisterCheck(t, s, 3, "node1", "backend", "backend-check", api.HealthPassing)
+	testRegisterNode(t, s, 4, "node2")
+
+	// Register a dependency check on the backend service
+	dep := &structs.HealthCheck{
+		Node:    "node2",
+		CheckID: "backend-dep",
+		Type:    dependencyCheckType,
+		Definition: structs.HealthCheckDefinition{
+			DependencyService: "backend",
+		},
+	}
+	require.NoError(t, s.EnsureCheck(5, dep))
+
+	requireDependency := func(t *testing.T, status, output string) {
+		t.Helper()
+		_, checks, err := s.NodeChecks(nil, "node2", nil, "")
+		require.NoError(t, err)
+		require.Len(t, checks, 1)
+		require.Equal(t, status, checks[0].Status)
+		require.Equal(t, output, checks[0].Output)
+	}
+	requireDependency(t, api.HealthPassing, "Service 'backend' has 1 passing instance(s)")
+
+	// A failing node check makes the backend instance unhealthy
+	testRegisterCheck(t, s, 6, "node1", "", "serfHealth", api.HealthCritical)
+	requireDependency(t, api.HealthCritical, "Service 'backend' has no passing instances")
+
+	// Recovery of the node check makes the dependency pass again
+	testRegisterCheck(t, s, 7, "node1", "", "serfHealth", api.HealthPassing)
+	requireDependency(t, api.HealthPassing, "Service 'backend' has 1 passing instance(s)")
+
+	// A failing service check makes the backend instance unhealthy
+	testRegisterCheck(t, s, 8, "node1", "backend", "backend-check", api.HealthCritical)
+	requireDependency(t, api.HealthCritical, "Service 'backend' has no passing instances")
+
+	// Registering another healthy instance makes the dependency pass
+	testRegisterNode(t, s, 9, "node3")
+	testRegisterService(t, s, 10, "node3", "backend")
+	requireDependency(t, api.HealthPassing, "Service 'backend' has 1 passing instance(s)")
+
+	// Deregistering the healthy instance makes the dependency fail
+	require.NoError(t, s.DeleteService(11, "node3", "backend", nil, ""))
+	requireDependency(t, api.HealthCritical, "Service 'backend' has no passing instances")
+
+	// Deleting the failing check leaves a healthy instance
+	require.NoError(t, s.DeleteCheck(12, "node1", "backend-check", nil, ""))
+	requireDependency(t, api.HealthPassing, "Service 'backend' has 1 passing instance(s)")
+
+	// The reported status of a dependency check is ignored
+	dep.Status = api.HealthCritical
+	require.NoError(t, s.EnsureCheck(13, dep))
+	requireDependency(t, api.HealthPassing, "Service 'backend' has 1 passing instance(s)")
+}
+
 func TestStateStore_NodeChecks(t *testing.T) {
 	s := testStateStore(t)
 
@@ -3593,6 +3820,54 @@ func TestStateStore_ChecksInState(t *testing.T) {
 	}
 }
 
+func TestStateStore_ChecksByType(t *testing.T) {
+	s := testStateStore(t)
+
+	// Querying with no results returns nil.
+	ws := memdb.NewWatchSet()
+	idx, res, err := s.ChecksByType(ws, "http", nil, "")
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), idx)
+	require.Nil(t, res)
+
+	withType := func(checkType string) func(chk *structs.HealthCheck) {
+		return func(chk *structs.HealthCheck) {
+			chk.Node = "node1"
+			chk.Status = api.HealthPassing
+			chk.Type = checkType
+		}
+	}
+
+	testRegisterNode(t, s, 1, "node1")
+	testRegisterCheckCustom(t, s, 2, "check1", withType("http"))
+	testRegisterCheckCustom(t, s, 3, "check2", withType("tcp"))
+	testRegisterCheckCustom(t, s, 4, "check3", withType("http"))
+	testRegisterCheck(t, s, 5, "node1", "", "check4", api.HealthPassing)
+	require.True(t, watchFired(ws))
+
+	// Only the checks of the requested type are returned.
+	ws = memdb.NewWatchSet()
+	idx, checks, err := s.ChecksByType(ws, "http", nil, "")
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), idx)
+	require.Len(t, checks, 2)
+	require.Equal(t, types.CheckID("check1"), checks[0].CheckID)
+	require.Equal(t, types.CheckID("check3"), checks[1].CheckID)
+
+	// Registering a check of another type does not fire the watch.
+	testRegisterCheckCustom(t, s, 6, "check5", withType("ttl"))
+	require.False(t, watchFired(ws))
+
+	// Registering a new matching check fires the watch.
+	testRegisterCheckCustom(t, s, 7, "check6", withType("http"))
+	require.True(t, watchFired(ws))
+
+	_, checks, err = s.ChecksByType(nil, "tcp", nil, "")
+	require.NoError(t, err)
+	require.Len(t, checks, 1)
+	require.Equal(t, types.CheckID("check2"), checks[0].CheckID)
+}
+
 func TestStateStore_ChecksInStateByNodeMeta(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -4603,6 +4878,56 @@ func BenchmarkCheckServiceNodes(b *testing.B) {
 	}
 }
 
+func TestStateStore_NodesByServiceHealth(t *testing.T) {
+	s := testStateStore(t)
+
+	ws := memdb.NewWatchSet()
+	idx, groups, err := s.NodesByServiceHealth(ws, "web", nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), idx)
+	require.Empty(t, groups)
+
+	testRegisterNode(t, s, 1, "node1")
+	testRegisterNode(t, s, 2, "node2")
+	testRegisterNode(t, s, 3, "node3")
+	testRegisterService(t, s, 4, "node1", "web")
+	testRegisterService(t, s, 5, "node2", "web")
+	testRegisterService(t, s, 6, "node3", "web")
+	require.True(t, watchFired(ws))
+
+	// node1 is healthy, node2 has a warning service check and node3 has a
+	// failing node-level check which makes its instance critical.
+	testRegisterCheck(t, s, 7, "node1", "web", "web-check", api.HealthPassing)
+	testRegisterCheck(t, s, 8, "node2", "web", "web-check", api.HealthWarning)
+	testRegisterCheck(t, s, 9, "node3", "web", "web-check", api.HealthPassing)
+	testRegisterCheck(t, s, 10, "node3", "", "serfHealth", api.HealthCritical)
+
+	// An unrelated service does not affect the grouping.
+	testRegisterService(t, s, 11, "node1", "db")
+	testRegisterCheck(t, s, 12, "node1", "db", "db-check", api.HealthCritical)
+
+	ws = memdb.NewWatchSet()
+	idx, groups, err = s.NodesByServiceHealth(ws, "web", nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), idx)
+	require.Equal(t, map[string][]string{
+		api.HealthPassing:  {"node1"},
+		api.HealthWarning:  {"node2"},
+		api.HealthCritical: {"node3"},
+	}, groups)
+
+	// Recovering the warning check moves node2 to passing.
+	testRegisterCheck(t, s, 13, "node2", "web", "web-check", api.HealthPassing)
+	require.True(t, watchFired(ws))
+
+	_, groups, err = s.NodesByServiceHealth(nil, "web", nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{
+		api.HealthPassing:  {"node1", "node2"},
+		api.HealthCritical: {"node3"},
+	}, groups)
+}
+
 func TestStateStore_CheckServiceTagNodes(t *testing.T) {
 	s := testStateStore(t)
 
@@ -9332,3 +9657,58 @@ func assertDeepEqual(t *testing.T, x, y interface{}, opts ...cmp.Option) {
 		t.Fatalf("assertion failed: values are not equal\n--- expected\n+++ actual\n%v", diff)
 	}
 }
+
+func TestStateStore_ReclaimServiceVirtualIPs(t *testing.T) {
+	s := testStateStore(t)
+	setVirtualIPFlags(t, s)
+
+	testRegisterNode(t, s, 0, "node1")
+	require.NoError(t, s.EnsureService(10, "node1", &structs.NodeService{
+		ID:      "foo",
+		Service: "foo",
+		Port:    1111,
+		Connect: structs.ServiceConnect{Native: true},
+	}))
+
+	// Simulate a virtual IP that was leaked by a service which is no longer
+	// registered or referenced anywhere.
+	leaked := structs.PeeredServiceName{ServiceName: structs.NewServiceName("leaked", nil)}
+	tx := s.db.WriteTxn(11)
+	_, err := assignServiceVirtualIP(tx, 11, leaked)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	vip, err := s.VirtualIPForService(leaked)
+	require.NoError(t, err)
+	require.Equal(t, "240.0.0.2", vip)
+
+	orphaned, err := s.OrphanedServiceVirtualIPs()
+	require.NoError(t, err)
+	require.Equal(t, []structs.PeeredServiceName{leaked}, orphaned)
+
+	require.NoError(t, s.ReclaimServiceVirtualIPs(12, orphaned))
+
+	vip, err = s.VirtualIPForService(leaked)
+	require.NoError(t, err)
+	require.Empty(t, vip)
+
+	// The registered service keeps its virtual IP.
+	vip, err = s.VirtualIPForService(structs.PeeredServiceName{ServiceName: structs.NewServiceName("foo", nil)})
+	require.NoError(t, err)
+	require.Equal(t, "240.0.0.1", vip)
+
+	orphaned, err = s.OrphanedServiceVirtualIPs()
+	require.NoError(t, err)
+	require.Empty(t, orphaned)
+
+	// The reclaimed address is handed out again.
+	require.NoError(t, s.EnsureService(13, "node1", &structs.NodeService{
+		ID:      "bar",
+		Service: "bar",
+		Port:    2222,
+		Connect: structs.ServiceConnect{Native: true},
+	}))
+	vip, err = s.VirtualIPForService(structs.PeeredServiceName{ServiceName: structs.NewServiceName("bar", nil)})
+	require.NoError(t, err)
+	require.Equal(t, "240.0.0.2", vip)
+}
@@ -154,6 +154,161 @@ func (s *Store) ConfigEntriesByKind(ws memdb.WatchSet, kind string, entMeta *acl
 	return configEntriesByKindTxn(tx, ws, kind, entMeta)
 }
 
+// ConfigEntryCountByKind returns the number of config entries of each kind
+// within entMeta. Kinds without any entries are omitted.
+func (s *Store) ConfigEntryCountByKind(ws memdb.WatchSet, entMeta *acl.EnterpriseMeta) (uint64, map[string]int, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	idx := maxIndexWatchTxn(tx, ws, tableConfigEntries)
+
+	iter, err := getAllConfigEntriesWithTxn(tx, entMeta)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed config entry lookup: %s", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	counts := make(map[string]int)
+	for v := iter.Next(); v != nil; v = iter.Next() {
+		counts[v.(structs.ConfigEntry).GetKind()]++
+	}
+	return idx, counts, nil
+}
+
+// InvalidConfigEntryReferences returns the discovery chain config entries that
+// reference services which are neither registered in the catalog nor defined
+// by a service-defaults or service-resolver config entry. References to
+// services in other datacenters, on cluster peers or in sameness groups
+// cannot be verified locally and are not checked.
+func (s *Store) InvalidConfigEntryReferences(ws memdb.WatchSet, entMeta *acl.EnterpriseMeta) (uint64, []structs.ConfigEntry, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	// Watch both tables since registering or deregistering a service can
+	// also change the result.
+	idx := maxIndexWatchTxn(tx, ws, tableConfigEntries, tableServices)
+
+	_, entries, err := configEntriesByKindTxn(tx, nil, "", entMeta)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var results []structs.ConfigEntry
+	for _, entry := range entries {
+		dcEntry, ok := entry.(discoveryChainConfigEntry)
+		if !ok {
+			continue
+		}
+
+		for _, sid := range localServiceReferences(dcEntry) {
+			exists, err := serviceReferenceExistsTxn(tx, sid)
+			if err != nil {
+				return 0, nil, err
+			}
+			if !exists {
+				results = append(results, entry)
+				break
+			}
+		}
+	}
+	return idx, results, nil
+}
+
+// localServiceReferences returns the services related to entry that are
+// expected to be found in the local catalog. Service resolver redirects and
+// failover targets that name another datacenter, a cluster peer or a sameness
+// group are left out.
+func localServiceReferences(entry discoveryChainConfigEntry) []structs.ServiceID {
+	resolver, ok := entry.(*structs.ServiceResolverConfigEntry)
+	if !ok {
+		return entry.ListRelatedServices()
+	}
+
+	local := *resolver
+	if r := resolver.Redirect; r != nil && (r.Datacenter != "" || r.Peer != "" || r.SamenessGroup != "") {
+		local.Redirect = nil
+	}
+
+	local.Failover = make(map[string]structs.ServiceResolverFailover, len(resolver.Failover))
+	for subset, failover := range resolver.Failover {
+		if len(failover.Targets) == 0 {
+			if len(failover.Datacenters) > 0 || failover.SamenessGroup != "" {
+				continue
+			}
+			local.Failover[subset] = failover
+			continue
+		}
+
+		var targets []structs.ServiceResolverFailoverTarget
+		for _, target := range failover.Targets {
+			if target.Datacenter == "" && target.Peer == "" {
+				targets = append(targets, target)
+			}
+		}
+		if len(targets) > 0 {
+			failover.Targets = targets
+			local.Failover[subset] = failover
+		}
+	}
+	return local.ListRelatedServices()
+}
+
+// serviceReferenceExistsTxn returns true if the service is registered in the
+// catalog or defined by a service-defaults or service-resolver config entry.
+func serviceReferenceExistsTxn(tx ReadTxn, sid structs.ServiceID) (bool, error) {
+	svc, err := tx.First(tableServices, indexService, Query{
+		Value:          sid.ID,
+		EnterpriseMeta: sid.EnterpriseMeta,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed service lookup: %s", err)
+	}
+	if svc != nil {
+		return true, nil
+	}
+
+	for _, kind := range []string{structs.ServiceDefaults, structs.ServiceResolver} {
+		_, entry, err := configEntryTxn(tx, nil, kind, sid.ID, &sid.EnterpriseMeta)
+		if err != nil {
+			return false, err
+		}
+		if entry != nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ConfigEntryCount returns the number of config entries with the given kind,
+// or of all kinds if kind is empty, without loading them into a slice. Pass
+// an EnterpriseMeta with a wildcard namespace to count across a partition.
+// Partition scoping is provided by the enterprise indexes; in CE every entry
+// belongs to the default partition.
+func (s *Store) ConfigEntryCount(kind string, entMeta *acl.EnterpriseMeta) (int, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+	return configEntryCountTxn(tx, kind, entMeta)
+}
+
+func configEntryCountTxn(tx ReadTxn, kind string, entMeta *acl.EnterpriseMeta) (int, error) {
+	var iter memdb.ResultIterator
+	var err error
+	if kind != "" {
+		iter, err = getConfigEntryKindsWithTxn(tx, kind, entMeta)
+	} else {
+		iter, err = getAllConfigEntriesWithTxn(tx, entMeta)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed config entry lookup: %s", err)
+	}
+
+	var count int
+	for v := iter.Next(); v != nil; v = iter.Next() {
+		count++
+	}
+	return count, nil
+}
+
 func listDiscoveryChainNamesTxn(
 	tx ReadTxn,
 	ws memdb.WatchSet,
@@ -389,14 +544,32 @@ func (s *Store) DeleteConfigEntryCAS(idx, cidx uint64, conf structs.ConfigEntry)
 }
 
 func (s *Store) DeleteConfigEntry(idx uint64, kind, name string, entMeta *acl.EnterpriseMeta) error {
+	_, err := s.DeleteConfigEntryReturning(idx, kind, name, entMeta)
+	return err
+}
+
+// DeleteConfigEntryReturning deletes a config entry and returns the entry as
+// it was right before it was deleted, or nil if it didn't exist.
+func (s *Store) DeleteConfigEntryReturning(idx uint64, kind, name string, entMeta *acl.EnterpriseMeta) (structs.ConfigEntry, error) {
 	tx := s.db.WriteTxn(idx)
 	defer tx.Abort()
 
+	existing, err := tx.First(tableConfigEntries, indexID, configentry.NewKindName(kind, name, entMeta))
+	if err != nil {
+		return nil, fmt.Errorf("failed config entry lookup: %s", err)
+	}
+
 	if err := deleteConfigEntryTxn(tx, idx, kind, name, entMeta); err != nil {
-		return err
+		return nil, err
 	}
 
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, nil
+	}
+	return existing.(structs.ConfigEntry), nil
 }
 
 // TODO: accept structs.ConfigEntry instead of individual fields
@@ -643,6 +816,8 @@ func validateProposedConfigEntryInGraph(
 			}
 		}
 	case structs.MeshConfig:
+	case structs.SessionDefaults:
+	case structs.ConfigEntryDefaults:
 	case structs.ExportedServices:
 	case structs.APIGateway: // TODO Consider checkGatewayClash
 	case structs.BoundAPIGateway:
@@ -912,7 +1087,7 @@ func discoveryChainOriginalTargetsTxn(
 		EvaluateInPartition:  source.PartitionOrDefault(),
 		EvaluateInDatacenter: dc,
 	}
-	idx, chain, _, err := serviceDiscoveryChainTxn(tx, ws, source.Name, entMeta, req)
+	idx, chain, _, err := serviceDiscoveryChainTxn(tx, ws, source.Name, entMeta, req, nil)
 	if err != nil {
 		return 0, nil, fmt.Errorf("failed to fetch discovery chain for %q: %v", source.String(), err)
 	}
@@ -959,7 +1134,7 @@ func (s *Store) discoveryChainSourcesTxn(tx ReadTxn, ws memdb.WatchSet, dc strin
 			EvaluateInPartition:  sn.PartitionOrDefault(),
 			EvaluateInDatacenter: dc,
 		}
-		idx, chain, _, err := serviceDiscoveryChainTxn(tx, ws, sn.Name, &sn.EnterpriseMeta, req)
+		idx, chain, _, err := serviceDiscoveryChainTxn(tx, ws, sn.Name, &sn.EnterpriseMeta, req, nil)
 		if err != nil {
 			return 0, nil, fmt.Errorf("failed to fetch discovery chain for %q: %v", sn.String(), err)
 		}
@@ -1465,7 +1640,44 @@ func (s *Store) ServiceDiscoveryChain(
 	tx := s.db.ReadTxn()
 	defer tx.Abort()
 
-	return serviceDiscoveryChainTxn(tx, ws, serviceName, entMeta, req)
+	return serviceDiscoveryChainTxn(tx, ws, serviceName, entMeta, req, nil)
+}
+
+// ShadowServiceDiscoveryChain compiles the discovery chain for the given
+// service as if the proposed config entry had already been written. The
+// proposed entry is checked against the rest of the config entry graph in the
+// same way a real write would be, but nothing is persisted.
+func (s *Store) ShadowServiceDiscoveryChain(
+	serviceName string,
+	entMeta *acl.EnterpriseMeta,
+	req discoverychain.CompileRequest,
+	proposed structs.ConfigEntry,
+) (uint64, *structs.CompiledDiscoveryChain, error) {
+	tx := s.db.ReadTxn()
+	defer tx.Abort()
+
+	q := newConfigEntryQuery(proposed)
+	existing, err := tx.First(tableConfigEntries, indexID, q)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed configuration lookup: %s", err)
+	}
+	var existingConf structs.ConfigEntry
+	if existing != nil {
+		existingConf = existing.(structs.ConfigEntry)
+	}
+
+	if err := validateProposedConfigEntryInGraph(tx, q, proposed, existingConf); err != nil {
+		return 0, nil, err
+	}
+
+	overrides := map[configentry.KindName]structs.ConfigEntry{
+		q: proposed,
+	}
+	index, chain, _, err := serviceDiscoveryChainTxn(tx, nil, serviceName, entMeta, req, overrides)
+	if err != nil {
+		return 0, nil, err
+	}
+	return index, chain, nil
 }
 
 func serviceDiscoveryChainTxn(
@@ -1474,9 +1686,10 @@ func serviceDiscoveryChainTxn(
 	serviceName string,
 	entMeta *acl.EnterpriseMeta,
 	req discoverychain.CompileRequest,
+	overrides map[configentry.KindName]structs.ConfigEntry,
 ) (uint64, *structs.CompiledDiscoveryChain, *configentry.DiscoveryChainSet, error) {
 
-	index, entries, err := readDiscoveryChainConfigEntriesTxn(tx, ws, serviceName, nil, entMeta)
+	index, entries, err := readDiscoveryChainConfigEntriesTxn(tx, ws, serviceName, overrides, entMeta)
 	if err != nil {
 		return 0, nil, nil, err
 	}
@@ -1505,7 +1718,7 @@ func serviceDiscoveryChainTxn(
 	}
 
 	if serviceVIPEntry != nil {
-		assignedIP, err := serviceVIPEntry.IPWithOffset()
+		assignedIP, err := virtualIPWithOffsetTxn(tx, serviceVIPEntry.IP)
 		if err != nil {
 			return 0, nil, nil, err
 		}
@@ -163,3 +163,46 @@ func TestStore_peersForService(t *testing.T) {
 		})
 	}
 }
+
+func TestStore_ConfigEntryCount(t *testing.T) {
+	s := testConfigStateStore(t)
+
+	// CE only has the default partition, so entries written with any other
+	// partition still count towards it. Partition scoping of the count is
+	// covered by the enterprise indexes.
+	otherPartition := acl.NewEnterpriseMetaWithPartition("other", "")
+
+	require.NoError(t, s.EnsureConfigEntry(1, &structs.ServiceConfigEntry{
+		Kind: structs.ServiceDefaults,
+		Name: "web",
+	}))
+	require.NoError(t, s.EnsureConfigEntry(2, &structs.ServiceConfigEntry{
+		Kind:           structs.ServiceDefaults,
+		Name:           "api",
+		EnterpriseMeta: otherPartition,
+	}))
+	require.NoError(t, s.EnsureConfigEntry(3, &structs.ProxyConfigEntry{
+		Kind: structs.ProxyDefaults,
+		Name: structs.ProxyConfigGlobal,
+	}))
+
+	entMeta := structs.DefaultEnterpriseMetaInDefaultPartition().WithWildcardNamespace()
+
+	count, err := s.ConfigEntryCount("", entMeta)
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+
+	count, err = s.ConfigEntryCount(structs.ServiceDefaults, entMeta)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	count, err = s.ConfigEntryCount(structs.ServiceResolver, entMeta)
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+
+	require.NoError(t, s.DeleteConfigEntry(4, structs.ServiceDefaults, "web", nil))
+
+	count, err = s.ConfigEntryCount("", entMeta)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+}
@@ -67,13 +67,20 @@ func TestStore_ConfigEntry(t *testing.T) {
 	require.Equal(t, updated, config)
 
 	// Delete
-	require.NoError(t, s.DeleteConfigEntry(2, structs.ProxyDefaults, "global", nil))
+	deleted, err := s.DeleteConfigEntryReturning(2, structs.ProxyDefaults, "global", nil)
+	require.NoError(t, err)
+	require.Equal(t, updated, deleted)
 
 	idx, config, err = s.ConfigEntry(nil, structs.ProxyDefaults, "global", nil)
 	require.NoError(t, err)
 	require.Equal(t, uint64(2), idx)
 	require.Nil(t, config)
 
+	// Deleting a missing entry returns nothing.
+	deleted, err = s.DeleteConfigEntryReturning(3, structs.ProxyDefaults, "global", nil)
+	require.NoError(t, err)
+	require.Nil(t, deleted)
+
 	// Set up a watch.
 	serviceConf := &structs.ServiceConfigEntry{
 		Kind: structs.ServiceDefaults,
@@ -333,6 +340,155 @@ func TestStore_ConfigEntries(t *testing.T) {
 
 }
 
+func TestStore_ConfigEntryCountByKind(t *testing.T) {
+	s := testConfigStateStore(t)
+
+	// Nothing to count yet.
+	idx, counts, err := s.ConfigEntryCountByKind(nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), idx)
+	require.Empty(t, counts)
+
+	require.NoError(t, s.EnsureConfigEntry(1, &structs.ProxyConfigEntry{
+		Kind: structs.ProxyDefaults,
+		Name: structs.ProxyConfigGlobal,
+	}))
+	require.NoError(t, s.EnsureConfigEntry(2, &structs.ServiceConfigEntry{
+		Kind: structs.ServiceDefaults,
+		Name: "web",
+	}))
+	require.NoError(t, s.EnsureConfigEntry(3, &structs.ServiceConfigEntry{
+		Kind: structs.ServiceDefaults,
+		Name: "api",
+	}))
+	require.NoError(t, s.EnsureConfigEntry(4, &structs.ServiceResolverConfigEntry{
+		Kind: structs.ServiceResolver,
+		Name: "web",
+	}))
+
+	ws := memdb.NewWatchSet()
+	idx, counts, err = s.ConfigEntryCountByKind(ws, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), idx)
+	require.Equal(t, map[string]int{
+		structs.ProxyDefaults:   1,
+		structs.ServiceDefaults: 2,
+		structs.ServiceResolver: 1,
+	}, counts)
+	require.False(t, watchFired(ws))
+
+	// Adding an entry of a new kind fires the watch and is counted.
+	require.NoError(t, s.EnsureConfigEntry(5, &structs.MeshConfigEntry{}))
+	require.True(t, watchFired(ws))
+
+	idx, counts, err = s.ConfigEntryCountByKind(nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), idx)
+	require.Equal(t, 1, counts[structs.MeshConfig])
+	require.Len(t, counts, 4)
+}
+
+func TestStore_InvalidConfigEntryReferences(t *testing.T) {
+	s := testConfigStateStore(t)
+
+	testRegisterNode(t, s, 1, "node1")
+	testRegisterService(t, s, 2, "node1", "web")
+	testRegisterService(t, s, 3, "node1", "api")
+
+	// A resolver whose failover target does not exist.
+	resolver := &structs.ServiceResolverConfigEntry{
+		Kind: structs.ServiceResolver,
+		Name: "web",
+		Failover: map[string]structs.ServiceResolverFailover{
+			"*": {Service: "web-backup"},
+		},
+	}
+	require.NoError(t, s.EnsureConfigEntry(4, resolver))
+
+	// A resolver whose failover target exists.
+	require.NoError(t, s.EnsureConfigEntry(5, &structs.ServiceResolverConfigEntry{
+		Kind: structs.ServiceResolver,
+		Name: "api",
+		Failover: map[string]structs.ServiceResolverFailover{
+			"*": {Service: "web"},
+		},
+	}))
+
+	ws := memdb.NewWatchSet()
+	idx, entries, err := s.InvalidConfigEntryReferences(ws, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), idx)
+	require.Equal(t, []structs.ConfigEntry{resolver}, entries)
+
+	// Registering the failover target resolves the reference.
+	testRegisterService(t, s, 6, "node1", "web-backup")
+	require.True(t, watchFired(ws))
+
+	idx, entries, err = s.InvalidConfigEntryReferences(nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), idx)
+	require.Empty(t, entries)
+
+	// A service defined only by a config entry also resolves the reference.
+	require.NoError(t, s.DeleteService(7, "node1", "web-backup", nil, ""))
+	require.NoError(t, s.EnsureConfigEntry(8, &structs.ServiceConfigEntry{
+		Kind: structs.ServiceDefaults,
+		Name: "web-backup",
+	}))
+
+	_, entries, err = s.InvalidConfigEntryReferences(nil, nil)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	// Targets in other datacenters or on peers cannot be verified locally
+	// and are not reported.
+	require.NoError(t, s.EnsureConfigEntry(9, &structs.ServiceResolverConfigEntry{
+		Kind: structs.ServiceResolver,
+		Name: "remote",
+		Redirect: &structs.ServiceResolverRedirect{
+			Service:    "remote-only",
+			Datacenter: "dc2",
+		},
+	}))
+	require.NoError(t, s.EnsureConfigEntry(10, &structs.ServiceResolverConfigEntry{
+		Kind: structs.ServiceResolver,
+		Name: "failover",
+		Failover: map[string]structs.ServiceResolverFailover{
+			"*": {
+				Targets: []structs.ServiceResolverFailoverTarget{
+					{Service: "dc2-only", Datacenter: "dc2"},
+					{Service: "peer-only", Peer: "cluster-01"},
+				},
+			},
+			"v1": {Service: "legacy-dc2-only", Datacenters: []string{"dc2"}},
+		},
+	}))
+
+	_, entries, err = s.InvalidConfigEntryReferences(nil, nil)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	// A local target next to remote ones is still checked.
+	mixed := &structs.ServiceResolverConfigEntry{
+		Kind: structs.ServiceResolver,
+		Name: "failover",
+		Failover: map[string]structs.ServiceResolverFailover{
+			"*": {
+				Targets: []structs.ServiceResolverFailoverTarget{
+					{Service: "dc2-only", Datacenter: "dc2"},
+					{Service: "missing"},
+				},
+			},
+		},
+	}
+	require.NoError(t, s.EnsureConfigEntry(11, mixed))
+
+	_, entries, err = s.InvalidConfigEntryReferences(nil, nil)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "failover", entries[0].GetName())
+}
+
 func TestStore_ServiceDefaults_Kind_Destination(t *testing.T) {
 	s := testConfigStateStore(t)
 
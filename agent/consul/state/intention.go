@@ -752,15 +752,8 @@ type IntentionDecisionOpts struct {
 // allowPermissions determines whether the presence of L7 permissions leads to a DENY decision.
 // This should be false when evaluating a connection between a source and destination, but not the request that will be sent.
 func (s *Store) IntentionDecision(opts IntentionDecisionOpts) (structs.IntentionDecisionSummary, error) {
-
 	// Figure out which source matches this request.
-	var ixnMatch *structs.Intention
-	for _, ixn := range opts.Intentions {
-		if _, ok := connect.AuthorizeIntentionTarget(opts.Target, opts.Namespace, opts.Partition, opts.Peer, ixn, opts.MatchType); ok {
-			ixnMatch = ixn
-			break
-		}
-	}
+	ixnMatch := MatchingIntention(opts)
 
 	resp := structs.IntentionDecisionSummary{
 		DefaultAllow: opts.DefaultAllow,
@@ -790,6 +783,17 @@ func (s *Store) IntentionDecision(opts IntentionDecisionOpts) (structs.Intention
 	return resp, nil
 }
 
+// MatchingIntention returns the highest precedence intention in opts that
+// applies to the target, or nil if the decision falls back to the default.
+func MatchingIntention(opts IntentionDecisionOpts) *structs.Intention {
+	for _, ixn := range opts.Intentions {
+		if _, ok := connect.AuthorizeIntentionTarget(opts.Target, opts.Namespace, opts.Partition, opts.Peer, ixn, opts.MatchType); ok {
+			return ixn
+		}
+	}
+	return nil
+}
+
 // IntentionMatch returns the list of intentions that match the namespace and
 // name for either a source or destination. This applies the resolution rules
 // so wildcards will match any value.
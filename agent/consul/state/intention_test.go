@@ -15,6 +15,7 @@ import (
 	"github.com/dhiaayachi/consul/acl"
 	"github.com/dhiaayachi/consul/agent/structs"
 	"github.com/dhiaayachi/consul/sdk/testutil"
+	"github.com/dhiaayachi/consul/sdk/testutil/retry"
 )
 
 var (
@@ -2089,6 +2090,79 @@ func TestStore_IntentionDecision(t *testing.T) {
 	}
 }
 
+func TestStore_IntentionDecision_Scheduled(t *testing.T) {
+	now := time.Now()
+	opensAt := now.Add(time.Second)
+	opensLater := now.Add(time.Hour)
+	closedAt := now.Add(-time.Hour)
+
+	entries := []structs.ConfigEntry{
+		&structs.ServiceIntentionsConfigEntry{
+			Kind: structs.ServiceIntentions,
+			Name: "db",
+			Sources: []*structs.SourceIntention{
+				{
+					Name:      "web",
+					Action:    structs.IntentionActionAllow,
+					NotBefore: &opensAt,
+					NotAfter:  &opensLater,
+				},
+			},
+		},
+		&structs.ServiceIntentionsConfigEntry{
+			Kind: structs.ServiceIntentions,
+			Name: "cache",
+			Sources: []*structs.SourceIntention{
+				{
+					Name:     "web",
+					Action:   structs.IntentionActionAllow,
+					NotAfter: &closedAt,
+				},
+			},
+		},
+	}
+
+	s := testConfigStateStore(t)
+	for _, entry := range entries {
+		require.NoError(t, s.EnsureConfigEntry(1, entry))
+	}
+
+	decide := func(t require.TestingT, dst string) structs.IntentionDecisionSummary {
+		entry := structs.IntentionMatchEntry{
+			Namespace: structs.IntentionDefaultNamespace,
+			Partition: acl.DefaultPartitionName,
+			Name:      "web",
+		}
+		_, intentions, err := s.IntentionMatchOne(nil, entry, structs.IntentionMatchSource, structs.IntentionTargetService)
+		require.NoError(t, err)
+
+		decision, err := s.IntentionDecision(IntentionDecisionOpts{
+			Target:     dst,
+			Namespace:  structs.IntentionDefaultNamespace,
+			Partition:  acl.DefaultPartitionName,
+			Intentions: intentions,
+			MatchType:  structs.IntentionMatchDestination,
+		})
+		require.NoError(t, err)
+		return decision
+	}
+
+	// The allow rule is not yet in effect, so the default deny applies.
+	require.Equal(t, structs.IntentionDecisionSummary{}, decide(t, "db"))
+
+	// The expired allow rule is ignored.
+	require.Equal(t, structs.IntentionDecisionSummary{}, decide(t, "cache"))
+
+	// Once the window opens the allow rule takes effect.
+	retry.Run(t, func(r *retry.R) {
+		require.Equal(r, structs.IntentionDecisionSummary{
+			Allowed:  true,
+			HasExact: true,
+		}, decide(r, "db"))
+	})
+	require.True(t, time.Now().After(opensAt))
+}
+
 func disableLegacyIntentions(s *Store) error {
 	return s.SystemMetadataSet(1, &structs.SystemMetadataEntry{
 		Key:   structs.SystemMetadataIntentionFormatKey,
@@ -5,6 +5,7 @@ package state
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/go-memdb"
@@ -18,6 +19,7 @@ const (
 	tableTombstones = "tombstones"
 
 	indexSession = "session"
+	indexExpires = "expires"
 )
 
 // kvsTableSchema returns a new table schema used for storing structs.DirEntry
@@ -39,10 +41,32 @@ func kvsTableSchema() *memdb.TableSchema {
 					Field: "Session",
 				},
 			},
+			indexExpires: {
+				Name:         indexExpires,
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: indexerSingle[*TimeQuery, *structs.DirEntry]{
+					readIndex:  indexFromTimeQuery,
+					writeIndex: indexExpiresFromDirEntry,
+				},
+			},
 		},
 	}
 }
 
+func indexExpiresFromDirEntry(e *structs.DirEntry) ([]byte, error) {
+	if !e.HasExpirationTime() {
+		return nil, errMissingValueForIndex
+	}
+	if e.ExpirationTime.Unix() < 0 {
+		return nil, fmt.Errorf("kvs expiration time cannot be before the unix epoch: %s", e.ExpirationTime)
+	}
+
+	var b indexBuilder
+	b.Time(*e.ExpirationTime)
+	return b.Bytes(), nil
+}
+
 // indexFromIDValue creates an index key from any struct that implements singleValueID
 func indexFromIDValue(e singleValueID) ([]byte, error) {
 	v := e.IDValue()
@@ -206,6 +230,29 @@ func kvsGetTxn(tx ReadTxn,
 	return idx, nil, nil
 }
 
+// KVSListExpired lists the entries in all partitions and namespaces that
+// expired before the provided time, soonest first. The returned set will be no
+// larger than the max value provided.
+func (s *Store) KVSListExpired(asOf time.Time, max int) (structs.DirEntries, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	iter, err := tx.Get(tableKVs, indexExpires)
+	if err != nil {
+		return nil, fmt.Errorf("failed kvs lookup: %s", err)
+	}
+
+	var entries structs.DirEntries
+	for raw := iter.Next(); raw != nil && len(entries) < max; raw = iter.Next() {
+		entry := raw.(*structs.DirEntry)
+		if !entry.IsExpired(asOf) {
+			break
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
 // KVSList is used to list out all keys under a given prefix. If the
 // prefix is left empty, all keys in the KVS will be returned. The returned
 // is the max index of the returned kvs entries or applicable tombstones, or
@@ -259,6 +306,38 @@ func (s *Store) kvsListTxn(tx ReadTxn,
 	return idx, entries, nil
 }
 
+// KVSListBySession is used to list the keys under a given prefix that are
+// locked by the given session. The session index is used for the lookup so
+// the rest of the store is not scanned. The returned index is the full table
+// index for kvs and tombstones.
+func (s *Store) KVSListBySession(ws memdb.WatchSet,
+	sessionID, prefix string, entMeta *acl.EnterpriseMeta) (uint64, structs.DirEntries, error) {
+
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	if entMeta == nil {
+		entMeta = structs.DefaultEnterpriseMetaInDefaultPartition()
+	}
+
+	idx := kvsMaxIndex(tx, *entMeta)
+
+	iter, err := tx.Get(tableKVs, indexSession, sessionID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed kvs lookup: %s", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	var entries structs.DirEntries
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		e := raw.(*structs.DirEntry)
+		if strings.HasPrefix(e.Key, prefix) && kvsEntryMatchesEntMeta(e, entMeta) {
+			entries = append(entries, e)
+		}
+	}
+	return idx, entries, nil
+}
+
 // KVSDelete is used to perform a shallow delete on a single key in the
 // the state store.
 func (s *Store) KVSDelete(idx uint64, key string, entMeta *acl.EnterpriseMeta) error {
@@ -388,16 +467,18 @@ func kvsSetCASTxn(tx WriteTxn, idx uint64, entry *structs.DirEntry) (bool, error
 
 // KVSDeleteTree is used to do a recursive delete on a key prefix
 // in the state store. If any keys are modified, the last index is
-// set, otherwise this is a no-op.
-func (s *Store) KVSDeleteTree(idx uint64, prefix string, entMeta *acl.EnterpriseMeta) error {
+// set, otherwise this is a no-op. Returns the number of keys deleted,
+// which is zero if the prefix only matched keys that were already deleted.
+func (s *Store) KVSDeleteTree(idx uint64, prefix string, entMeta *acl.EnterpriseMeta) (int, error) {
 	tx := s.db.WriteTxn(idx)
 	defer tx.Abort()
 
-	if err := s.kvsDeleteTreeTxn(tx, idx, prefix, entMeta); err != nil {
-		return err
+	deleted, err := s.kvsDeleteTreeTxn(tx, idx, prefix, entMeta)
+	if err != nil {
+		return 0, err
 	}
 
-	return tx.Commit()
+	return deleted, tx.Commit()
 }
 
 // KVSLockDelay returns the expiration time for any lock delay associated with
@@ -523,6 +604,73 @@ func kvsUnlockTxn(tx WriteTxn, idx uint64, entry *structs.DirEntry) (bool, error
 	return true, nil
 }
 
+// KVSLockTransfer is similar to KVSLock but hands a lock that is held by the
+// holder session over to entry.Session without unlocking the key in between.
+// The transfer is only performed if holder currently holds the lock.
+func (s *Store) KVSLockTransfer(idx uint64, holder string, entry *structs.DirEntry) (bool, error) {
+	tx := s.db.WriteTxn(idx)
+	defer tx.Abort()
+
+	transferred, err := kvsLockTransferTxn(tx, idx, holder, entry)
+	if !transferred || err != nil {
+		return false, err
+	}
+
+	err = tx.Commit()
+	return err == nil, err
+}
+
+// kvsLockTransferTxn is the inner method that does a lock transfer inside an
+// existing transaction.
+func kvsLockTransferTxn(tx WriteTxn, idx uint64, holder string, entry *structs.DirEntry) (bool, error) {
+	// Verify that both sessions are present.
+	if holder == "" {
+		return false, fmt.Errorf("missing lock holder session")
+	}
+	if entry.Session == "" {
+		return false, fmt.Errorf("missing session")
+	}
+
+	// Verify that the session receiving the lock exists.
+	sess, err := tx.First(tableSessions, indexID, Query{Value: entry.Session, EnterpriseMeta: entry.EnterpriseMeta})
+	if err != nil {
+		return false, fmt.Errorf("failed session lookup: %s", err)
+	}
+	if sess == nil {
+		return false, fmt.Errorf("invalid session %#v", entry.Session)
+	}
+
+	existing, err := tx.First(tableKVs, indexID, entry)
+	if err != nil {
+		return false, fmt.Errorf("failed kvs lookup: %s", err)
+	}
+
+	// Bail if there's no existing key.
+	if existing == nil {
+		return false, nil
+	}
+
+	// Make sure the given holder is the lock holder.
+	e := existing.(*structs.DirEntry)
+	if e.Session != holder {
+		return false, nil
+	}
+
+	// Handing the lock to a new session counts as a new acquisition.
+	entry.CreateIndex = e.CreateIndex
+	entry.LockIndex = e.LockIndex
+	if entry.Session != holder {
+		entry.LockIndex++
+	}
+	entry.ModifyIndex = idx
+
+	// If we made it this far, we should perform the set.
+	if err := kvsSetTxn(tx, idx, entry, true); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // kvsCheckSessionTxn checks to see if the given session matches the current
 // entry for a key.
 func kvsCheckSessionTxn(tx WriteTxn,
@@ -80,27 +80,42 @@ func kvsListEntriesTxn(tx ReadTxn, ws memdb.WatchSet, prefix string, entMeta acl
 	return lindex, ents, nil
 }
 
+// kvsEntryMatchesEntMeta reports whether the entry belongs to the given
+// enterprise metadata. There is only a single namespace and partition here.
+func kvsEntryMatchesEntMeta(_ *structs.DirEntry, _ *acl.EnterpriseMeta) bool {
+	return true
+}
+
 // kvsDeleteTreeTxn is the inner method that does a recursive delete inside an
-// existing transaction.
-func (s *Store) kvsDeleteTreeTxn(tx WriteTxn, idx uint64, prefix string, entMeta *acl.EnterpriseMeta) error {
-	// For prefix deletes, only insert one tombstone and delete the entire subtree
-	deleted, err := tx.DeletePrefix(tableKVs, indexID+"_prefix", prefix)
+// existing transaction. It returns the number of keys deleted.
+func (s *Store) kvsDeleteTreeTxn(tx WriteTxn, idx uint64, prefix string, entMeta *acl.EnterpriseMeta) (int, error) {
+	entries, err := tx.Get(tableKVs, indexID+"_prefix", prefix)
 	if err != nil {
-		return fmt.Errorf("failed recursive deleting kvs entry: %s", err)
+		return 0, fmt.Errorf("failed kvs lookup: %s", err)
+	}
+	count := 0
+	for entry := entries.Next(); entry != nil; entry = entries.Next() {
+		count++
+	}
+	if count == 0 {
+		return 0, nil
 	}
 
-	if deleted {
-		if prefix != "" { // don't insert a tombstone if the entire tree is deleted, all watchers on keys will see the max_index of the tree
-			if err := s.kvsGraveyard.InsertTxn(tx, prefix, idx, entMeta); err != nil {
-				return fmt.Errorf("failed adding to graveyard: %s", err)
-			}
-		}
+	// For prefix deletes, only insert one tombstone and delete the entire subtree
+	if _, err := tx.DeletePrefix(tableKVs, indexID+"_prefix", prefix); err != nil {
+		return 0, fmt.Errorf("failed recursive deleting kvs entry: %s", err)
+	}
 
-		if err := tx.Insert(tableIndex, &IndexEntry{"kvs", idx}); err != nil {
-			return fmt.Errorf("failed updating index: %s", err)
+	if prefix != "" { // don't insert a tombstone if the entire tree is deleted, all watchers on keys will see the max_index of the tree
+		if err := s.kvsGraveyard.InsertTxn(tx, prefix, idx, entMeta); err != nil {
+			return 0, fmt.Errorf("failed adding to graveyard: %s", err)
 		}
 	}
-	return nil
+
+	if err := tx.Insert(tableIndex, &IndexEntry{"kvs", idx}); err != nil {
+		return 0, fmt.Errorf("failed updating index: %s", err)
+	}
+	return count, nil
 }
 
 func kvsMaxIndex(tx ReadTxn, entMeta acl.EnterpriseMeta) uint64 {
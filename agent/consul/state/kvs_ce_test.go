@@ -6,11 +6,14 @@
 package state
 
 import (
+	"time"
+
 	"github.com/dhiaayachi/consul/acl"
 	"github.com/dhiaayachi/consul/agent/structs"
 )
 
 func testIndexerTableKVs() map[string]indexerTestCase {
+	expires := time.Unix(1000, 0)
 	return map[string]indexerTestCase{
 		indexID: {
 			read: indexValue{
@@ -36,6 +39,16 @@ func testIndexerTableKVs() map[string]indexerTestCase {
 				},
 			},
 		},
+		indexExpires: {
+			read: indexValue{
+				source:   &TimeQuery{Value: expires},
+				expected: []byte{0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x3, 0xe8},
+			},
+			write: indexValue{
+				source:   &structs.DirEntry{Key: "TheKey", ExpirationTime: &expires},
+				expected: []byte{0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x3, 0xe8},
+			},
+		},
 	}
 }
 
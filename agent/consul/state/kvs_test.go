@@ -118,7 +118,7 @@ func TestStateStore_GC(t *testing.T) {
 	}
 
 	// Check for the same behavior with a tree delete.
-	if err := s.KVSDeleteTree(7, "foo/moo", nil); err != nil {
+	if _, err := s.KVSDeleteTree(7, "foo/moo", nil); err != nil {
 		t.Fatalf("err: %s", err)
 	}
 	select {
@@ -488,6 +488,93 @@ func TestStateStore_KVSList(t *testing.T) {
 	}
 }
 
+func TestStateStore_KVSListBySession(t *testing.T) {
+	s := testStateStore(t)
+
+	testRegisterNode(t, s, 1, "node1")
+	session1, session2 := testUUID(), testUUID()
+	require.NoError(t, s.SessionCreate(2, &structs.Session{ID: session1, Node: "node1"}))
+	require.NoError(t, s.SessionCreate(3, &structs.Session{ID: session2, Node: "node1"}))
+
+	require.NoError(t, s.KVSSet(4, &structs.DirEntry{Key: "free", Value: []byte("a")}))
+	for i, lock := range []struct{ key, session string }{
+		{"foo/a", session1},
+		{"foo/b", session2},
+		{"bar", session1},
+	} {
+		ok, err := s.KVSLock(uint64(5+i), &structs.DirEntry{Key: lock.key, Session: lock.session})
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	keys := func(entries structs.DirEntries) []string {
+		var out []string
+		for _, e := range entries {
+			out = append(out, e.Key)
+		}
+		return out
+	}
+
+	idx, entries, err := s.KVSListBySession(nil, session1, "", nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), idx)
+	require.ElementsMatch(t, []string{"foo/a", "bar"}, keys(entries))
+
+	_, entries, err = s.KVSListBySession(nil, session1, "foo/", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"foo/a"}, keys(entries))
+
+	// Releasing the lock removes the key from the session's list.
+	ok, err := s.KVSUnlock(8, &structs.DirEntry{Key: "foo/b", Session: session2})
+	require.NoError(t, err)
+	require.True(t, ok)
+	idx, entries, err = s.KVSListBySession(nil, session2, "", nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(8), idx)
+	require.Empty(t, entries)
+}
+
+func TestStateStore_KVSListExpired(t *testing.T) {
+	s := testStateStore(t)
+
+	now := time.Now()
+	expiresAt := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+
+	require.NoError(t, s.KVSSet(1, &structs.DirEntry{Key: "forever", Value: []byte("a")}))
+	require.NoError(t, s.KVSSet(2, &structs.DirEntry{Key: "later", ExpirationTTL: time.Hour, ExpirationTime: expiresAt(time.Hour)}))
+	require.NoError(t, s.KVSSet(3, &structs.DirEntry{Key: "second", ExpirationTTL: time.Minute, ExpirationTime: expiresAt(-time.Minute)}))
+	require.NoError(t, s.KVSSet(4, &structs.DirEntry{Key: "first", ExpirationTTL: time.Minute, ExpirationTime: expiresAt(-2 * time.Minute)}))
+
+	keys := func(entries structs.DirEntries) []string {
+		var out []string
+		for _, e := range entries {
+			out = append(out, e.Key)
+		}
+		return out
+	}
+
+	entries, err := s.KVSListExpired(now, 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{"first", "second"}, keys(entries))
+
+	entries, err = s.KVSListExpired(now, 1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"first"}, keys(entries))
+
+	entries, err = s.KVSListExpired(now.Add(2*time.Hour), 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{"first", "second", "later"}, keys(entries))
+
+	// Rewriting a key without a TTL removes it from the expiration index.
+	require.NoError(t, s.KVSSet(5, &structs.DirEntry{Key: "first", Value: []byte("b")}))
+	entries, err = s.KVSListExpired(now, 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{"second"}, keys(entries))
+}
+
 func TestStateStore_KVSDelete(t *testing.T) {
 	s := testStateStore(t)
 
@@ -853,17 +940,25 @@ func TestStateStore_KVSDeleteTree(t *testing.T) {
 
 	// Calling tree deletion which affects nothing does not
 	// modify the table index.
-	if err := s.KVSDeleteTree(9, "bar", nil); err != nil {
+	deleted, err := s.KVSDeleteTree(9, "bar", nil)
+	if err != nil {
 		t.Fatalf("err: %s", err)
 	}
+	if deleted != 0 {
+		t.Fatalf("expected 0 keys deleted, got: %d", deleted)
+	}
 	if idx := s.maxIndex(partitionedIndexEntryName(tableKVs, "default")); idx != 4 {
 		t.Fatalf("bad index: %d", idx)
 	}
 
 	// Call tree deletion with a nested prefix.
-	if err := s.KVSDeleteTree(5, "foo/bar", nil); err != nil {
+	deleted, err = s.KVSDeleteTree(5, "foo/bar", nil)
+	if err != nil {
 		t.Fatalf("err: %s", err)
 	}
+	if deleted != 3 {
+		t.Fatalf("expected 3 keys deleted, got: %d", deleted)
+	}
 
 	// Check that all the matching keys were deleted
 	tx := s.db.Txn(false)
@@ -901,6 +996,15 @@ func TestStateStore_KVSDeleteTree(t *testing.T) {
 		t.Fatalf("bad index: %d", idx)
 	}
 
+	// Deleting the prefix again is a no-op since only the tombstone is left.
+	deleted, err = s.KVSDeleteTree(6, "foo/bar", nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if deleted != 0 {
+		t.Fatalf("expected 0 keys deleted, got: %d", deleted)
+	}
+
 	// Now reap the tombstones and watch the index revert to the remaining
 	// foo/zorp key's index.
 	if err := s.ReapTombstones(6, 5); err != nil {
@@ -937,7 +1041,7 @@ func TestStateStore_Watches_PrefixDelete(t *testing.T) {
 	}
 
 	// Delete a key and make sure the index comes from the tombstone.
-	if err := s.KVSDeleteTree(7, "foo/bar/zip", nil); err != nil {
+	if _, err := s.KVSDeleteTree(7, "foo/bar/zip", nil); err != nil {
 		t.Fatalf("unexpected err: %s", err)
 	}
 	// Make sure watch fires
@@ -1002,7 +1106,7 @@ func TestStateStore_Watches_PrefixDelete(t *testing.T) {
 	}
 
 	// Delete all the keys, special case where tombstones are not inserted
-	if err := s.KVSDeleteTree(10, "", nil); err != nil {
+	if _, err := s.KVSDeleteTree(10, "", nil); err != nil {
 		t.Fatalf("unexpected err: %s", err)
 	}
 	wantIndex = 10
@@ -1270,6 +1374,87 @@ func TestStateStore_KVSUnlock(t *testing.T) {
 	}
 }
 
+func TestStateStore_KVSLockTransfer(t *testing.T) {
+	s := testStateStore(t)
+
+	// Transfer with no sessions should fail.
+	ok, err := s.KVSLockTransfer(0, "", &structs.DirEntry{Key: "foo", Value: []byte("bar")})
+	if ok || err == nil || !strings.Contains(err.Error(), "missing lock holder session") {
+		t.Fatalf("didn't detect missing lock holder: %v %s", ok, err)
+	}
+
+	// Make real sessions.
+	testRegisterNode(t, s, 1, "node1")
+	session1, session2, session3 := testUUID(), testUUID(), testUUID()
+	for i, id := range []string{session1, session2, session3} {
+		if err := s.SessionCreate(uint64(2+i), &structs.Session{ID: id, Node: "node1"}); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	// Transfer to a session that doesn't exist should fail.
+	ok, err = s.KVSLockTransfer(5, session1, &structs.DirEntry{Key: "foo", Session: testUUID()})
+	if ok || err == nil || !strings.Contains(err.Error(), "invalid session") {
+		t.Fatalf("didn't detect invalid session: %v %s", ok, err)
+	}
+
+	// Transfer of a missing key should not return an error, but will report
+	// it didn't transfer anything.
+	ok, err = s.KVSLockTransfer(6, session1, &structs.DirEntry{Key: "foo", Session: session2})
+	if ok || err != nil {
+		t.Fatalf("didn't handle transferring a missing key: %v %s", ok, err)
+	}
+
+	// Lock the key with the first session.
+	ok, err = s.KVSLock(7, &structs.DirEntry{Key: "foo", Value: []byte("bar"), Session: session1})
+	if !ok || err != nil {
+		t.Fatalf("didn't get the lock: %v %s", ok, err)
+	}
+
+	// Transfer from a session that doesn't hold the lock should not change
+	// anything.
+	ok, err = s.KVSLockTransfer(8, session3, &structs.DirEntry{Key: "foo", Value: []byte("zoo"), Session: session2})
+	if ok || err != nil {
+		t.Fatalf("didn't handle transferring with the wrong holder: %v %s", ok, err)
+	}
+	idx, result, err := s.KVSGet(nil, "foo", nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), idx)
+	require.Equal(t, session1, result.Session)
+	require.Equal(t, uint64(1), result.LockIndex)
+	require.Equal(t, "bar", string(result.Value))
+
+	// Transfer from the holder hands the lock over.
+	ok, err = s.KVSLockTransfer(9, session1, &structs.DirEntry{Key: "foo", Value: []byte("zoo"), Session: session2})
+	if !ok || err != nil {
+		t.Fatalf("didn't transfer the lock: %v %s", ok, err)
+	}
+	idx, result, err = s.KVSGet(nil, "foo", nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(9), idx)
+	require.Equal(t, session2, result.Session)
+	require.Equal(t, uint64(2), result.LockIndex)
+	require.Equal(t, uint64(7), result.CreateIndex)
+	require.Equal(t, uint64(9), result.ModifyIndex)
+	require.Equal(t, "zoo", string(result.Value))
+
+	// The previous holder can no longer transfer or unlock it.
+	ok, err = s.KVSLockTransfer(10, session1, &structs.DirEntry{Key: "foo", Session: session3})
+	if ok || err != nil {
+		t.Fatalf("didn't handle transferring with the previous holder: %v %s", ok, err)
+	}
+	ok, err = s.KVSUnlock(11, &structs.DirEntry{Key: "foo", Session: session1})
+	if ok || err != nil {
+		t.Fatalf("didn't handle unlocking with the previous holder: %v %s", ok, err)
+	}
+
+	// Destroying the new holder releases the lock.
+	require.NoError(t, s.SessionDestroy(12, session2, nil))
+	_, result, err = s.KVSGet(nil, "foo", nil)
+	require.NoError(t, err)
+	require.Empty(t, result.Session)
+}
+
 func TestStateStore_KVS_Snapshot_Restore(t *testing.T) {
 	s := testStateStore(t)
 
@@ -209,6 +209,13 @@ func (s *Restore) Session(sess *structs.Session) error {
 
 // SessionCreate is used to register a new session in the state store.
 func (s *Store) SessionCreate(idx uint64, sess *structs.Session) error {
+	return s.SessionCreateWithCheckExemptions(idx, sess, nil)
+}
+
+// SessionCreateWithCheckExemptions is like SessionCreate, but also allows
+// health checks of the given types, in addition to "session" checks, to be
+// critical when the session is created.
+func (s *Store) SessionCreateWithCheckExemptions(idx uint64, sess *structs.Session, exemptCheckTypes []string) error {
 	tx := s.db.WriteTxn(idx)
 	defer tx.Abort()
 
@@ -220,7 +227,7 @@ func (s *Store) SessionCreate(idx uint64, sess *structs.Session) error {
 	// future.
 
 	// Call the session creation
-	if err := s.sessionCreateTxn(tx, idx, sess); err != nil {
+	if err := s.sessionCreateTxn(tx, idx, sess, exemptCheckTypes); err != nil {
 		return err
 	}
 
@@ -230,7 +237,7 @@ func (s *Store) SessionCreate(idx uint64, sess *structs.Session) error {
 // sessionCreateTxn is the inner method used for creating session entries in
 // an open transaction. Any health checks registered with the session will be
 // checked for failing status. Returns any error encountered.
-func (s *Store) sessionCreateTxn(tx WriteTxn, idx uint64, sess *structs.Session) error {
+func (s *Store) sessionCreateTxn(tx WriteTxn, idx uint64, sess *structs.Session, exemptCheckTypes []string) error {
 	// Check that we have a session ID
 	if sess.ID == "" {
 		return ErrMissingSessionID
@@ -247,6 +254,11 @@ func (s *Store) sessionCreateTxn(tx WriteTxn, idx uint64, sess *structs.Session)
 		return fmt.Errorf("Invalid session behavior: %s", sess.Behavior)
 	}
 
+	// Verify the session TTL is valid
+	if err := validateSessionTTL(sess.TTL); err != nil {
+		return err
+	}
+
 	// Assign the indexes. ModifyIndex likely will not be used but
 	// we set it here anyways for sanity.
 	sess.CreateIndex = idx
@@ -262,7 +274,7 @@ func (s *Store) sessionCreateTxn(tx WriteTxn, idx uint64, sess *structs.Session)
 	}
 
 	// Verify that all session checks exist
-	if err := validateSessionChecksTxn(tx, sess); err != nil {
+	if err := validateSessionChecksTxn(tx, sess, exemptCheckTypes); err != nil {
 		return err
 	}
 
@@ -274,6 +286,39 @@ func (s *Store) sessionCreateTxn(tx WriteTxn, idx uint64, sess *structs.Session)
 	return s.updateSessionCheck(tx, idx, sess, api.HealthPassing)
 }
 
+// validateSessionTTL returns an error if the given session TTL can't be parsed
+// or is negative. An empty TTL is valid. Limits on the TTL depend on the server
+// configuration and are enforced by the Session endpoint instead.
+func validateSessionTTL(ttl string) error {
+	if ttl == "" {
+		return nil
+	}
+
+	d, err := time.ParseDuration(ttl)
+	if err != nil {
+		return fmt.Errorf("Invalid session TTL %q: %v", ttl, err)
+	}
+	if d < 0 {
+		return fmt.Errorf("Invalid session TTL %q: must not be negative", ttl)
+	}
+	return nil
+}
+
+// sessionCheckExempt returns whether a critical check of the given type is
+// allowed when creating a session. Session checks are always exempt since they
+// are expected to be critical until their session exists.
+func sessionCheckExempt(checkType string, exemptCheckTypes []string) bool {
+	if checkType == "session" {
+		return true
+	}
+	for _, t := range exemptCheckTypes {
+		if t == checkType {
+			return true
+		}
+	}
+	return false
+}
+
 // SessionGet is used to retrieve an active session from the state store.
 func (s *Store) SessionGet(ws memdb.WatchSet,
 	sessionID string, entMeta *acl.EnterpriseMeta) (uint64, *structs.Session, error) {
@@ -283,6 +328,14 @@ func (s *Store) SessionGet(ws memdb.WatchSet,
 
 	idx := maxIndexTxnSessions(tx, entMeta)
 
+	session, err := sessionGetTxn(tx, ws, sessionID, entMeta)
+	if err != nil {
+		return 0, nil, err
+	}
+	return idx, session, nil
+}
+
+func sessionGetTxn(tx ReadTxn, ws memdb.WatchSet, sessionID string, entMeta *acl.EnterpriseMeta) (*structs.Session, error) {
 	// Look up the session by its ID
 	if entMeta == nil {
 		entMeta = structs.DefaultEnterpriseMetaInDefaultPartition()
@@ -290,14 +343,113 @@ func (s *Store) SessionGet(ws memdb.WatchSet,
 	watchCh, session, err := tx.FirstWatch(tableSessions, indexID, Query{Value: sessionID, EnterpriseMeta: *entMeta})
 
 	if err != nil {
-		return 0, nil, fmt.Errorf("failed session lookup: %s", err)
+		return nil, fmt.Errorf("failed session lookup: %s", err)
 	}
 	ws.Add(watchCh)
 
 	if session != nil {
-		return idx, session.(*structs.Session), nil
+		return session.(*structs.Session), nil
+	}
+	return nil, nil
+}
+
+// SessionRenewAll records the renewal of the given sessions at idx. Sessions
+// that no longer exist, or whose node has been deleted, are skipped rather
+// than failing the whole batch, and the sessions index only advances if at
+// least one session was renewed. Returns the IDs of the renewed sessions.
+func (s *Store) SessionRenewAll(idx uint64, sessionIDs []string, entMeta *acl.EnterpriseMeta) ([]string, error) {
+	tx := s.db.WriteTxn(idx)
+	defer tx.Abort()
+
+	var renewed []string
+	for _, id := range sessionIDs {
+		session, err := sessionGetTxn(tx, nil, id, entMeta)
+		if err != nil {
+			return nil, err
+		}
+		if session == nil {
+			continue
+		}
+
+		node, err := tx.First(tableNodes, indexID, Query{Value: session.Node, EnterpriseMeta: *structs.DefaultEnterpriseMetaInPartition(session.PartitionOrDefault())})
+		if err != nil {
+			return nil, fmt.Errorf("failed node lookup: %s", err)
+		}
+		if node == nil {
+			continue
+		}
+
+		updated := *session
+		updated.ModifyIndex = idx
+		if err := insertSessionTxn(tx, &updated, idx, true, false); err != nil {
+			return nil, fmt.Errorf("failed inserting session: %s", err)
+		}
+		renewed = append(renewed, id)
+	}
+
+	if len(renewed) == 0 {
+		return nil, nil
 	}
-	return idx, nil, nil
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return renewed, nil
+}
+
+// SessionTransfer moves an existing session to another node. Any locks held
+// by the session are kept. The new node, and the checks the session will be
+// tied to on it, are validated the same way as when creating a session.
+func (s *Store) SessionTransfer(idx uint64, req *structs.SessionTransferRequest) error {
+	tx := s.db.WriteTxn(idx)
+	defer tx.Abort()
+
+	session, err := sessionGetTxn(tx, nil, req.SessionID, &req.EnterpriseMeta)
+	if err != nil {
+		return err
+	}
+	if session == nil {
+		return fmt.Errorf("Session %q not found", req.SessionID)
+	}
+
+	updated := *session
+	updated.Node = req.Node
+	if len(req.NodeChecks) > 0 || len(req.ServiceChecks) > 0 {
+		updated.Checks = nil
+		updated.NodeChecks = req.NodeChecks
+		updated.ServiceChecks = req.ServiceChecks
+	}
+	updated.ModifyIndex = idx
+
+	// Check that the node exists
+	node, err := tx.First(tableNodes, indexID, Query{Value: updated.Node, EnterpriseMeta: *structs.DefaultEnterpriseMetaInPartition(updated.PartitionOrDefault())})
+	if err != nil {
+		return fmt.Errorf("failed node lookup: %s", err)
+	}
+	if node == nil {
+		return ErrMissingNode
+	}
+
+	// Verify that all session checks exist on the new node
+	if err := validateSessionChecksTxn(tx, &updated, req.ExemptCheckTypes); err != nil {
+		return err
+	}
+
+	// Replace the check mappings of the old node with those of the new one.
+	if err := deleteSessionChecksTxn(tx, updated.ID, &updated.EnterpriseMeta); err != nil {
+		return err
+	}
+	if err := insertSessionTxn(tx, &updated, idx, true, false); err != nil {
+		return fmt.Errorf("failed inserting session: %s", err)
+	}
+
+	if err := s.updateSessionCheck(tx, idx, session, api.HealthCritical); err != nil {
+		return err
+	}
+	if err := s.updateSessionCheck(tx, idx, &updated, api.HealthPassing); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 // NodeSessions returns a set of active sessions associated
@@ -408,26 +560,9 @@ func (s *Store) deleteSessionTxn(tx WriteTxn, idx uint64, sessionID string, entM
 		return fmt.Errorf("unknown session behavior %#v", session.Behavior)
 	}
 
-	if entMeta == nil {
-		entMeta = structs.DefaultEnterpriseMetaInDefaultPartition()
-	}
 	// Delete any check mappings.
-	mappings, err := tx.Get(tableSessionChecks, indexSession, Query{Value: sessionID, EnterpriseMeta: *entMeta})
-	if err != nil {
-		return fmt.Errorf("failed session checks lookup: %s", err)
-	}
-	{
-		var objs []interface{}
-		for mapping := mappings.Next(); mapping != nil; mapping = mappings.Next() {
-			objs = append(objs, mapping)
-		}
-
-		// Do the delete in a separate loop so we don't trash the iterator.
-		for _, obj := range objs {
-			if err := tx.Delete(tableSessionChecks, obj); err != nil {
-				return fmt.Errorf("failed deleting session check: %s", err)
-			}
-		}
+	if err := deleteSessionChecksTxn(tx, sessionID, entMeta); err != nil {
+		return err
 	}
 
 	// Delete any prepared queries.
@@ -453,6 +588,29 @@ func (s *Store) deleteSessionTxn(tx WriteTxn, idx uint64, sessionID string, entM
 	return s.updateSessionCheck(tx, idx, session, api.HealthCritical)
 }
 
+// deleteSessionChecksTxn removes the check mappings of the given session.
+func deleteSessionChecksTxn(tx WriteTxn, sessionID string, entMeta *acl.EnterpriseMeta) error {
+	if entMeta == nil {
+		entMeta = structs.DefaultEnterpriseMetaInDefaultPartition()
+	}
+	mappings, err := tx.Get(tableSessionChecks, indexSession, Query{Value: sessionID, EnterpriseMeta: *entMeta})
+	if err != nil {
+		return fmt.Errorf("failed session checks lookup: %s", err)
+	}
+	var objs []interface{}
+	for mapping := mappings.Next(); mapping != nil; mapping = mappings.Next() {
+		objs = append(objs, mapping)
+	}
+
+	// Do the delete in a separate loop so we don't trash the iterator.
+	for _, obj := range objs {
+		if err := tx.Delete(tableSessionChecks, obj); err != nil {
+			return fmt.Errorf("failed deleting session check: %s", err)
+		}
+	}
+	return nil
+}
+
 // updateSessionCheck The method updates the health-checks associated with the session
 func (s *Store) updateSessionCheck(tx WriteTxn, idx uint64, session *structs.Session, checkState string) error {
 	// Find all checks for the given Node
@@ -139,7 +139,7 @@ func sessionMaxIndex(tx ReadTxn, entMeta *acl.EnterpriseMeta) uint64 {
 	return maxIndexTxn(tx, "sessions")
 }
 
-func validateSessionChecksTxn(tx ReadTxn, session *structs.Session) error {
+func validateSessionChecksTxn(tx ReadTxn, session *structs.Session, exemptCheckTypes []string) error {
 	// Go over the session checks and ensure they exist.
 	for _, checkID := range session.CheckIDs() {
 		check, err := tx.First(tableChecks, indexID, NodeCheckQuery{Node: session.Node, CheckID: string(checkID)})
@@ -152,16 +152,19 @@ func validateSessionChecksTxn(tx ReadTxn, session *structs.Session) error {
 
 		// Verify that the check is not in critical state
 		healthCheck := check.(*structs.HealthCheck)
-		// we are discounting the health check for session checks since they are expected to be in critical state without session and this flow is expected to be used for session checks
-		if healthCheck.Status == api.HealthCritical && healthCheck.Type != "session" {
+		// we are discounting the health check for session checks since they are expected to be in critical state without session and this flow is expected to be used for session checks,
+		// along with any other check types the operator has exempted
+		if healthCheck.Status == api.HealthCritical && !sessionCheckExempt(healthCheck.Type, exemptCheckTypes) {
 			return fmt.Errorf("Check '%s' is in %s state", checkID, healthCheck.Status)
 		}
 	}
 	return nil
 }
 
-// SessionList returns a slice containing all of the active sessions.
-func (s *Store) SessionList(ws memdb.WatchSet, entMeta *acl.EnterpriseMeta) (uint64, structs.Sessions, error) {
+// SessionList returns a slice containing all of the active sessions that
+// match the optional filter. The returned index is always the index of the
+// sessions table, even when the filter excludes every session.
+func (s *Store) SessionList(ws memdb.WatchSet, filter *structs.SessionListFilter, entMeta *acl.EnterpriseMeta) (uint64, structs.Sessions, error) {
 	tx := s.db.Txn(false)
 	defer tx.Abort()
 
@@ -176,6 +179,12 @@ func (s *Store) SessionList(ws memdb.WatchSet, entMeta *acl.EnterpriseMeta) (uin
 		return 0, nil, fmt.Errorf("failed session lookup: %s", err)
 	}
 	ws.Add(sessions.WatchCh())
+	if !filter.IsZero() {
+		sessions = memdb.NewFilterIterator(sessions, func(raw interface{}) bool {
+			return !filter.Matches(raw.(*structs.Session))
+		})
+	}
+
 	// Go over the sessions and create a slice of them.
 	for session := sessions.Next(); session != nil; session = sessions.Next() {
 		result = append(result, session.(*structs.Session))
@@ -189,5 +198,5 @@ func maxIndexTxnSessions(tx *memdb.Txn, _ *acl.EnterpriseMeta) uint64 {
 }
 
 func (s *Store) SessionListAll(ws memdb.WatchSet) (uint64, structs.Sessions, error) {
-	return s.SessionList(ws, nil)
+	return s.SessionList(ws, nil, nil)
 }
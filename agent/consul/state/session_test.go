@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/hashicorp/go-memdb"
 
@@ -208,12 +209,42 @@ func TestStateStore_SessionCreate_SessionGet(t *testing.T) {
 	}
 }
 
+func TestStateStore_SessionCreate_TTL(t *testing.T) {
+	s := testStateStore(t)
+	testRegisterNode(t, s, 1, "node1")
+
+	cases := map[string]struct {
+		ttl    string
+		expect string
+	}{
+		"empty":       {ttl: ""},
+		"valid":       {ttl: "30m"},
+		"long":        {ttl: "48h"},
+		"negative":    {ttl: "-10s", expect: `Invalid session TTL "-10s": must not be negative`},
+		"unparseable": {ttl: "forever", expect: `Invalid session TTL "forever": time: invalid duration "forever"`},
+	}
+
+	idx := uint64(2)
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			sess := &structs.Session{ID: testUUID(), Node: "node1", TTL: tc.ttl}
+			err := s.SessionCreate(idx, sess)
+			idx++
+			if tc.expect != "" {
+				require.EqualError(t, err, tc.expect)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestStateStore_SessionList(t *testing.T) {
 	s := testStateStore(t)
 
 	// Listing when no sessions exist returns nil
 	ws := memdb.NewWatchSet()
-	idx, res, err := s.SessionList(ws, nil)
+	idx, res, err := s.SessionList(ws, nil, nil)
 	if idx != 0 || res != nil || err != nil {
 		t.Fatalf("expected (0, nil, nil), got: (%d, %#v, %#v)", idx, res, err)
 	}
@@ -251,7 +282,7 @@ func TestStateStore_SessionList(t *testing.T) {
 	}
 
 	// List out all of the sessions
-	idx, sessionList, err := s.SessionList(nil, nil)
+	idx, sessionList, err := s.SessionList(nil, nil, nil)
 	if err != nil {
 		t.Fatalf("err: %s", err)
 	}
@@ -268,6 +299,90 @@ func TestStateStore_SessionList(t *testing.T) {
 	}
 }
 
+func TestStateStore_SessionList_Filter(t *testing.T) {
+	s := testStateStore(t)
+
+	testRegisterNode(t, s, 1, "node1")
+
+	sessions := structs.Sessions{
+		&structs.Session{
+			ID:        testUUID(),
+			Node:      "node1",
+			Behavior:  structs.SessionKeysDelete,
+			LockDelay: 5 * time.Second,
+		},
+		&structs.Session{
+			ID:        testUUID(),
+			Node:      "node1",
+			Behavior:  structs.SessionKeysRelease,
+			LockDelay: 15 * time.Second,
+		},
+		&structs.Session{
+			ID:        testUUID(),
+			Node:      "node1",
+			Behavior:  structs.SessionKeysDelete,
+			LockDelay: 30 * time.Second,
+		},
+		&structs.Session{
+			ID:       testUUID(),
+			Node:     "node1",
+			Behavior: structs.SessionKeysRelease,
+		},
+	}
+	for i, session := range sessions {
+		require.NoError(t, s.SessionCreate(uint64(2+i), session))
+	}
+
+	ids := func(list structs.Sessions) []string {
+		var out []string
+		for _, session := range list {
+			out = append(out, session.ID)
+		}
+		return out
+	}
+
+	lockDelay := func(d time.Duration) *time.Duration {
+		return &d
+	}
+
+	cases := map[string]struct {
+		filter *structs.SessionListFilter
+		expect []string
+	}{
+		"nil filter": {
+			expect: ids(sessions),
+		},
+		"behavior": {
+			filter: &structs.SessionListFilter{Behavior: structs.SessionKeysDelete},
+			expect: []string{sessions[0].ID, sessions[2].ID},
+		},
+		"lock delay range": {
+			filter: &structs.SessionListFilter{MinLockDelay: 10 * time.Second, MaxLockDelay: lockDelay(30 * time.Second)},
+			expect: []string{sessions[1].ID, sessions[2].ID},
+		},
+		"behavior and min lock delay": {
+			filter: &structs.SessionListFilter{Behavior: structs.SessionKeysDelete, MinLockDelay: 10 * time.Second},
+			expect: []string{sessions[2].ID},
+		},
+		"zero max lock delay": {
+			filter: &structs.SessionListFilter{MaxLockDelay: lockDelay(0)},
+			expect: []string{sessions[3].ID},
+		},
+		"no match": {
+			filter: &structs.SessionListFilter{Behavior: structs.SessionKeysDelete, MaxLockDelay: lockDelay(time.Second)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			idx, res, err := s.SessionList(nil, tc.filter, nil)
+			require.NoError(t, err)
+			// The index is the table index regardless of what the filter matched.
+			require.Equal(t, uint64(5), idx)
+			require.ElementsMatch(t, tc.expect, ids(res))
+		})
+	}
+}
+
 func TestStateStore_NodeSessions(t *testing.T) {
 	s := testStateStore(t)
 
@@ -395,6 +510,155 @@ func TestStateStore_SessionDestroy(t *testing.T) {
 	tx.Abort()
 }
 
+func TestStateStore_SessionRenewAll(t *testing.T) {
+	s := testStateStore(t)
+
+	// Renewing sessions that don't exist is a no-op.
+	renewed, err := s.SessionRenewAll(1, []string{testUUID()}, nil)
+	require.NoError(t, err)
+	require.Empty(t, renewed)
+	require.Equal(t, uint64(0), s.maxIndex("sessions"))
+
+	testRegisterNode(t, s, 1, "node1")
+	testRegisterNode(t, s, 2, "node2")
+
+	sess1 := &structs.Session{ID: testUUID(), Node: "node1"}
+	require.NoError(t, s.SessionCreate(3, sess1))
+	sess2 := &structs.Session{ID: testUUID(), Node: "node2"}
+	require.NoError(t, s.SessionCreate(4, sess2))
+
+	// Remove node2 without going through DeleteNode, which would also
+	// invalidate its sessions.
+	tx := s.db.WriteTxn(5)
+	_, err = tx.DeleteAll(tableNodes, indexID, Query{Value: "node2"})
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	// Only the session whose node still exists is renewed.
+	missing := testUUID()
+	renewed, err = s.SessionRenewAll(6, []string{sess1.ID, missing, sess2.ID}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{sess1.ID}, renewed)
+	require.Equal(t, uint64(6), s.maxIndex("sessions"))
+
+	idx, out, err := s.SessionGet(nil, sess1.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), idx)
+	require.Equal(t, uint64(3), out.CreateIndex)
+	require.Equal(t, uint64(6), out.ModifyIndex)
+
+	_, out, err = s.SessionGet(nil, sess2.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), out.ModifyIndex)
+
+	// The index does not advance if nothing was renewed.
+	renewed, err = s.SessionRenewAll(7, []string{missing, sess2.ID}, nil)
+	require.NoError(t, err)
+	require.Empty(t, renewed)
+	require.Equal(t, uint64(6), s.maxIndex("sessions"))
+}
+
+func TestStateStore_SessionTransfer(t *testing.T) {
+	s := testStateStore(t)
+
+	testRegisterNode(t, s, 1, "node1")
+	testRegisterCheck(t, s, 2, "node1", "", "check1", api.HealthPassing)
+	testRegisterNode(t, s, 3, "node2")
+	testRegisterCheck(t, s, 4, "node2", "", "check2", api.HealthCritical)
+	testRegisterCheck(t, s, 5, "node2", "", "check3", api.HealthPassing)
+
+	sess := &structs.Session{
+		ID:         testUUID(),
+		Node:       "node1",
+		NodeChecks: []string{"check1"},
+	}
+	require.NoError(t, s.SessionCreate(6, sess))
+
+	ok, err := s.KVSLock(7, &structs.DirEntry{Key: "leader", Value: []byte("node1"), Session: sess.ID})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	transfer := func(idx uint64, node string, checks ...string) error {
+		return s.SessionTransfer(idx, &structs.SessionTransferRequest{
+			SessionID:  sess.ID,
+			Node:       node,
+			NodeChecks: checks,
+		})
+	}
+
+	// The session and target node must exist.
+	err = s.SessionTransfer(8, &structs.SessionTransferRequest{SessionID: testUUID(), Node: "node2"})
+	require.ErrorContains(t, err, "not found")
+	require.ErrorIs(t, transfer(8, "nope", "check3"), ErrMissingNode)
+
+	// The checks of the session must exist and be healthy on the new node.
+	require.ErrorContains(t, transfer(8, "node2"), "Missing check 'check1' registration")
+	require.ErrorContains(t, transfer(8, "node2", "check2"), "Check 'check2' is in critical state")
+
+	require.NoError(t, transfer(8, "node2", "check3"))
+
+	idx, out, err := s.SessionGet(nil, sess.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(8), idx)
+	require.Equal(t, "node2", out.Node)
+	require.Equal(t, []string{"check3"}, out.NodeChecks)
+	require.Equal(t, uint64(6), out.CreateIndex)
+	require.Equal(t, uint64(8), out.ModifyIndex)
+
+	_, sessions, err := s.NodeSessions(nil, "node1", nil)
+	require.NoError(t, err)
+	require.Empty(t, sessions)
+
+	// The lock is still held by the session.
+	_, entry, err := s.KVSGet(nil, "leader", nil)
+	require.NoError(t, err)
+	require.Equal(t, sess.ID, entry.Session)
+
+	// Deregistering the old node no longer affects the session.
+	require.NoError(t, s.DeleteNode(9, "node1", nil, ""))
+	_, out, err = s.SessionGet(nil, sess.ID, nil)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	// The session is now tied to the check on the new node.
+	require.NoError(t, s.DeleteCheck(10, "node2", "check3", nil, ""))
+	_, out, err = s.SessionGet(nil, sess.ID, nil)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	_, entry, err = s.KVSGet(nil, "leader", nil)
+	require.NoError(t, err)
+	require.Empty(t, entry.Session)
+}
+
+func TestStateStore_SessionTransfer_ExemptCheckTypes(t *testing.T) {
+	s := testStateStore(t)
+
+	testRegisterNode(t, s, 1, "node1")
+	testRegisterNode(t, s, 2, "node2")
+	testRegisterCheckCustom(t, s, 3, "info", func(chk *structs.HealthCheck) {
+		chk.Node = "node2"
+		chk.Type = "informational"
+		chk.Status = api.HealthCritical
+	})
+
+	sess := &structs.Session{ID: testUUID(), Node: "node1"}
+	require.NoError(t, s.SessionCreate(4, sess))
+
+	req := &structs.SessionTransferRequest{
+		SessionID:  sess.ID,
+		Node:       "node2",
+		NodeChecks: []string{"info"},
+	}
+	require.ErrorContains(t, s.SessionTransfer(5, req), "Check 'info' is in critical state")
+
+	req.ExemptCheckTypes = []string{"informational"}
+	require.NoError(t, s.SessionTransfer(5, req))
+
+	_, out, err := s.SessionGet(nil, sess.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, "node2", out.Node)
+}
 func TestStateStore_Session_Snapshot_Restore(t *testing.T) {
 	s := testStateStore(t)
 
@@ -481,7 +745,7 @@ func TestStateStore_Session_Snapshot_Restore(t *testing.T) {
 
 		// Read the restored sessions back out and verify that they
 		// match.
-		idx, res, err := s.SessionList(nil, nil)
+		idx, res, err := s.SessionList(nil, nil, nil)
 		if err != nil {
 			t.Fatalf("err: %s", err)
 		}
@@ -1042,6 +1306,49 @@ func TestHealthCheck_SessionRegistrationAllow(t *testing.T) {
 	}
 }
 
+// Allow the session to be created when a critical check has a type that the
+// operator exempted, while other critical checks still block it.
+func TestHealthCheck_SessionRegistrationAllow_ExemptTypes(t *testing.T) {
+	s := testStateStore(t)
+	exempt := []string{"informational"}
+
+	testRegisterNode(t, s, 1, "foo-node")
+	testRegisterCheckCustom(t, s, 2, "info", func(chk *structs.HealthCheck) {
+		chk.Node = "foo-node"
+		chk.Type = "informational"
+		chk.Status = api.HealthCritical
+	})
+	testRegisterCheckCustom(t, s, 3, "ttl", func(chk *structs.HealthCheck) {
+		chk.Node = "foo-node"
+		chk.Type = "ttl"
+		chk.Status = api.HealthCritical
+	})
+
+	sess := &structs.Session{
+		ID:     testUUID(),
+		Node:   "foo-node",
+		Checks: []types.CheckID{"info"},
+	}
+	require.NoError(t, s.SessionCreateWithCheckExemptions(4, sess, exempt))
+
+	sess = &structs.Session{
+		ID:     testUUID(),
+		Node:   "foo-node",
+		Checks: []types.CheckID{"info", "ttl"},
+	}
+	err := s.SessionCreateWithCheckExemptions(5, sess, exempt)
+	require.EqualError(t, err, "Check 'ttl' is in critical state")
+
+	// Without the exemption the informational check blocks the session.
+	sess = &structs.Session{
+		ID:     testUUID(),
+		Node:   "foo-node",
+		Checks: []types.CheckID{"info"},
+	}
+	err = s.SessionCreate(6, sess)
+	require.EqualError(t, err, "Check 'info' is in critical state")
+}
+
 // test the session health check when session status is changed
 func TestHealthCheck_Session(t *testing.T) {
 	s := testStateStore(t)
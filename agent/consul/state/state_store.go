@@ -211,6 +211,27 @@ func (s *Store) WalkAllTables(fn func(table string, item interface{}) bool) erro
 	return nil
 }
 
+// TableCounts returns the number of entries held in each table of the state
+// store, keyed by table name.
+func (s *Store) TableCounts() (map[string]int, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	counts := make(map[string]int, len(s.schema.Tables))
+	for name := range s.schema.Tables {
+		iter, err := tx.Get(name, indexID)
+		if err != nil {
+			return nil, fmt.Errorf("error counting table %q: %w", name, err)
+		}
+		n := 0
+		for item := iter.Next(); item != nil; item = iter.Next() {
+			n++
+		}
+		counts[name] = n
+	}
+	return counts, nil
+}
+
 // LastIndex returns that last index that affects the snapshotted data.
 func (s *Snapshot) LastIndex() uint64 {
 	return s.lastIndex
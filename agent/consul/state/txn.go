@@ -45,7 +45,7 @@ func (s *Store) txnKVS(tx WriteTxn, idx uint64, op *structs.TxnKVOp) (structs.Tx
 		}
 
 	case api.KVDeleteTree:
-		err = s.kvsDeleteTreeTxn(tx, idx, op.DirEnt.Key, &op.DirEnt.EnterpriseMeta)
+		_, err = s.kvsDeleteTreeTxn(tx, idx, op.DirEnt.Key, &op.DirEnt.EnterpriseMeta)
 
 	case api.KVCAS:
 		var ok bool
@@ -264,6 +264,9 @@ func (s *Store) txnService(tx WriteTxn, idx uint64, op *structs.TxnServiceOp) (s
 		if err := ensureServiceTxn(tx, idx, op.Node, false, &op.Service); err != nil {
 			return nil, err
 		}
+		if err := s.updateDependencyChecksTxn(tx, idx, op.Service.Service, &op.Service.EnterpriseMeta); err != nil {
+			return nil, err
+		}
 		entry, err := getNodeServiceTxn(tx, nil, op.Node, op.Service.ID, &op.Service.EnterpriseMeta, op.Service.PeerName)
 		return newTxnResultFromNodeServiceEntry(entry), err
 
@@ -276,6 +279,9 @@ func (s *Store) txnService(tx WriteTxn, idx uint64, op *structs.TxnServiceOp) (s
 		case err != nil:
 			return nil, err
 		}
+		if err := s.updateDependencyChecksTxn(tx, idx, op.Service.Service, &op.Service.EnterpriseMeta); err != nil {
+			return nil, err
+		}
 
 		entry, err := getNodeServiceTxn(tx, nil, op.Node, op.Service.ID, &op.Service.EnterpriseMeta, op.Service.PeerName)
 		return newTxnResultFromNodeServiceEntry(entry), err
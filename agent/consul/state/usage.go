@@ -464,6 +464,63 @@ func (s *Store) ServiceUsage(ws memdb.WatchSet, tenantUsage bool) (uint64, struc
 	return serviceInstances.Index, results, nil
 }
 
+// ServiceUsageFiltered computes service usage from the individual service
+// instances accepted by filter instead of from the usage table. As with
+// ServiceUsage, instances imported from peers are not counted. Only the
+// totals are populated; the per-tenant breakdowns are left empty.
+func (s *Store) ServiceUsageFiltered(ws memdb.WatchSet, filter func(*structs.ServiceNode) (bool, error)) (uint64, structs.ServiceUsage, error) {
+	tx := s.db.ReadTxn()
+	defer tx.Abort()
+
+	iter, err := tx.Get(tableServices, indexID)
+	if err != nil {
+		return 0, structs.ServiceUsage{}, fmt.Errorf("failed services lookup: %s", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	usage := structs.ServiceUsage{
+		ConnectServiceInstances: make(map[string]int, len(allConnectKind)),
+	}
+	for _, kind := range allConnectKind {
+		usage.ConnectServiceInstances[kind] = 0
+	}
+
+	type nodeKey struct{ partition, node string }
+	services := make(map[structs.ServiceName]struct{})
+	nodes := make(map[nodeKey]struct{})
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		svc := raw.(*structs.ServiceNode)
+		if svc.PeerName != "" {
+			continue
+		}
+		match, err := filter(svc)
+		if err != nil {
+			return 0, structs.ServiceUsage{}, err
+		}
+		if !match {
+			continue
+		}
+
+		usage.ServiceInstances++
+		services[svc.CompoundServiceName().ServiceName] = struct{}{}
+		nodes[nodeKey{svc.PartitionOrDefault(), svc.Node}] = struct{}{}
+
+		if svc.ServiceKind != structs.ServiceKindTypical {
+			usage.ConnectServiceInstances[string(svc.ServiceKind)]++
+		}
+		if svc.ServiceConnect.Native {
+			usage.ConnectServiceInstances[connectNativeInstancesTable]++
+		}
+		if svc.ServiceKind == structs.ServiceKindTypical && svc.ServiceName != structs.ConsulServiceName {
+			usage.BillableServiceInstances++
+		}
+	}
+	usage.Services = len(services)
+	usage.Nodes = len(nodes)
+
+	return maxIndexTxn(tx, tableServices), usage, nil
+}
+
 func (s *Store) KVUsage() (uint64, KVUsage, error) {
 	tx := s.db.ReadTxn()
 	defer tx.Abort()
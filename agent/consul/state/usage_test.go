@@ -5,6 +5,7 @@ package state
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -208,6 +209,52 @@ func TestStateStore_Usage_ServiceUsage(t *testing.T) {
 	}
 }
 
+func TestStateStore_Usage_ServiceUsageFiltered(t *testing.T) {
+	s := testStateStore(t)
+
+	testRegisterNode(t, s, 0, "node1")
+	testRegisterNode(t, s, 1, "node2")
+	testRegisterService(t, s, 8, "node1", "service1")
+	testRegisterService(t, s, 9, "node2", "service1")
+	testRegisterService(t, s, 10, "node2", "service2")
+	testRegisterSidecarProxy(t, s, 11, "node1", "service1")
+	testRegisterConnectNativeService(t, s, 12, "node1", "service-native")
+	testRegisterService(t, s, 13, "node1", "other")
+
+	prefix := func(p string) func(*structs.ServiceNode) (bool, error) {
+		return func(svc *structs.ServiceNode) (bool, error) {
+			return strings.HasPrefix(svc.ServiceName, p), nil
+		}
+	}
+
+	ws := memdb.NewWatchSet()
+	idx, usage, err := s.ServiceUsageFiltered(ws, prefix("service"))
+	require.NoError(t, err)
+	require.Equal(t, uint64(13), idx)
+	require.Equal(t, 4, usage.Services)
+	require.Equal(t, 5, usage.ServiceInstances)
+	require.Equal(t, 2, usage.Nodes)
+	require.Equal(t, 1, usage.ConnectServiceInstances[string(structs.ServiceKindConnectProxy)])
+	require.Equal(t, 1, usage.ConnectServiceInstances[connectNativeInstancesTable])
+	require.Equal(t, 0, usage.ConnectServiceInstances[string(structs.ServiceKindMeshGateway)])
+	require.Equal(t, 4, usage.BillableServiceInstances)
+
+	_, usage, err = s.ServiceUsageFiltered(nil, prefix("service2"))
+	require.NoError(t, err)
+	require.Equal(t, 1, usage.Services)
+	require.Equal(t, 1, usage.ServiceInstances)
+	require.Equal(t, 1, usage.Nodes)
+	require.Equal(t, 1, usage.BillableServiceInstances)
+
+	testRegisterService(t, s, 14, "node1", "service3")
+
+	select {
+	case <-ws.WatchCh(context.Background()):
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("timeout waiting on WatchSet")
+	}
+}
+
 func TestStateStore_Usage_ServiceUsage_DeleteNode(t *testing.T) {
 	s := testStateStore(t)
 	testRegisterNode(t, s, 1, "node1")
@@ -29,6 +29,10 @@ type StatsFetcher struct {
 	datacenter   string
 	inflight     map[raft.ServerID]struct{}
 	inflightLock sync.Mutex
+
+	// lastStats holds the most recent stats fetched from each server.
+	lastStats     map[raft.ServerID]structs.RaftStats
+	lastStatsLock sync.RWMutex
 }
 
 // NewStatsFetcher returns a stats fetcher.
@@ -38,6 +42,7 @@ func NewStatsFetcher(logger hclog.Logger, pool *pool.ConnPool, datacenter string
 		pool:       pool,
 		datacenter: datacenter,
 		inflight:   make(map[raft.ServerID]struct{}),
+		lastStats:  make(map[raft.ServerID]structs.RaftStats),
 	}
 }
 
@@ -74,9 +79,25 @@ func (f *StatsFetcher) fetch(server *autopilot.Server, replyCh chan *autopilot.S
 		return
 	}
 
+	f.lastStatsLock.Lock()
+	f.lastStats[server.ID] = reply
+	f.lastStatsLock.Unlock()
+
 	replyCh <- reply.ToAutopilotServerStats()
 }
 
+// LastStats returns the most recent stats fetched from each server.
+func (f *StatsFetcher) LastStats() map[raft.ServerID]structs.RaftStats {
+	f.lastStatsLock.RLock()
+	defer f.lastStatsLock.RUnlock()
+
+	stats := make(map[raft.ServerID]structs.RaftStats, len(f.lastStats))
+	for id, s := range f.lastStats {
+		stats[id] = s
+	}
+	return stats
+}
+
 // Fetch will attempt to query all the servers in parallel.
 func (f *StatsFetcher) Fetch(ctx context.Context, servers map[raft.ServerID]*autopilot.Server) map[raft.ServerID]*autopilot.ServerStats {
 	type workItem struct {
@@ -84,6 +105,15 @@ func (f *StatsFetcher) Fetch(ctx context.Context, servers map[raft.ServerID]*aut
 		replyCh chan *autopilot.ServerStats
 	}
 
+	// Forget the stats of servers that are no longer part of the cluster.
+	f.lastStatsLock.Lock()
+	for id := range f.lastStats {
+		if _, ok := servers[id]; !ok {
+			delete(f.lastStats, id)
+		}
+	}
+	f.lastStatsLock.Unlock()
+
 	// Skip any servers that have inflight requests.
 	var work []*workItem
 	f.inflightLock.Lock()
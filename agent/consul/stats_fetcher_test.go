@@ -111,4 +111,23 @@ func TestStatsFetcher(t *testing.T) {
 			}
 		})
 	}()
+
+	// Drop server 3 from the server set and make sure its last stats are
+	// forgotten.
+	func() {
+		if _, ok := s1.statsFetcher.LastStats()[raft.ServerID(s3.config.NodeID)]; !ok {
+			t.Fatalf("expected stats for server 3")
+		}
+
+		servers := s1.autopilotServers()
+		delete(servers, raft.ServerID(s3.config.NodeID))
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		s1.statsFetcher.Fetch(ctx, servers)
+
+		if _, ok := s1.statsFetcher.LastStats()[raft.ServerID(s3.config.NodeID)]; ok {
+			t.Fatalf("expected stats for server 3 to be dropped")
+		}
+	}()
 }
@@ -80,6 +80,14 @@ func (s *Status) RaftStats(args EmptyReadRequest, reply *structs.RaftStats) erro
 	if err != nil {
 		return fmt.Errorf("error parsing server's last_log_term value: %w", err)
 	}
+	reply.CommitIndex, err = strconv.ParseUint(stats["commit_index"], 10, 64)
+	if err != nil {
+		return fmt.Errorf("error parsing server's commit_index value: %w", err)
+	}
+	reply.AppliedIndex, err = strconv.ParseUint(stats["applied_index"], 10, 64)
+	if err != nil {
+		return fmt.Errorf("error parsing server's applied_index value: %w", err)
+	}
 
 	return nil
 }
@@ -43,6 +43,14 @@ func (t *Txn) preCheck(authorizer resolver.Result, ops structs.TxnOps) structs.T
 	for i, op := range ops {
 		switch {
 		case op.KV != nil:
+			// The holder of a lock transfer can't be given in a transaction.
+			if op.KV.Verb == api.KVLockTransfer {
+				errors = append(errors, &structs.TxnError{
+					OpIndex: i,
+					What:    fmt.Sprintf("KV operation %q is not supported in transactions", op.KV.Verb),
+				})
+				break
+			}
 			ok, err := kvsPreApply(t.logger, t.srv, authorizer, op.KV.Verb, &op.KV.DirEnt)
 			if err != nil {
 				errors = append(errors, &structs.TxnError{
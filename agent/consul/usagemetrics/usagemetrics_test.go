@@ -374,6 +374,22 @@ var baseCases = map[string]testCase{
 					{Name: "kind", Value: "jwt-provider"},
 				},
 			},
+			"consul.usage.test.state.config_entries;datacenter=dc1;kind=session-defaults": {
+				Name:  "consul.usage.test.state.config_entries",
+				Value: 0,
+				Labels: []metrics.Label{
+					{Name: "datacenter", Value: "dc1"},
+					{Name: "kind", Value: "session-defaults"},
+				},
+			},
+			"consul.usage.test.state.config_entries;datacenter=dc1;kind=config-entry-defaults": {
+				Name:  "consul.usage.test.state.config_entries",
+				Value: 0,
+				Labels: []metrics.Label{
+					{Name: "datacenter", Value: "dc1"},
+					{Name: "kind", Value: "config-entry-defaults"},
+				},
+			},
 			"consul.usage.test.state.config_entries;datacenter=dc1;kind=control-plane-request-limit": {
 				Name:  "consul.usage.test.state.config_entries",
 				Value: 0,
@@ -655,6 +671,22 @@ var baseCases = map[string]testCase{
 					{Name: "kind", Value: "jwt-provider"},
 				},
 			},
+			"consul.usage.test.state.config_entries;datacenter=dc1;kind=session-defaults": {
+				Name:  "consul.usage.test.state.config_entries",
+				Value: 0,
+				Labels: []metrics.Label{
+					{Name: "datacenter", Value: "dc1"},
+					{Name: "kind", Value: "session-defaults"},
+				},
+			},
+			"consul.usage.test.state.config_entries;datacenter=dc1;kind=config-entry-defaults": {
+				Name:  "consul.usage.test.state.config_entries",
+				Value: 0,
+				Labels: []metrics.Label{
+					{Name: "datacenter", Value: "dc1"},
+					{Name: "kind", Value: "config-entry-defaults"},
+				},
+			},
 			"consul.usage.test.state.config_entries;datacenter=dc1;kind=control-plane-request-limit": {
 				Name:  "consul.usage.test.state.config_entries",
 				Value: 0,
@@ -0,0 +1,194 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package configentry
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/go-memdb"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/dhiaayachi/consul/acl"
+	external "github.com/dhiaayachi/consul/agent/grpc-external"
+	"github.com/dhiaayachi/consul/agent/structs"
+	"github.com/dhiaayachi/consul/proto/private/pbconfigentry"
+)
+
+// WatchIntentionDecisions streams the intention decisions for a destination
+// service. The full decision set is sent at the start of the stream and a
+// delta is sent every time the intentions that match the destination change
+// the decision for a source.
+func (s *Server) WatchIntentionDecisions(
+	req *pbconfigentry.WatchIntentionDecisionsRequest,
+	serverStream pbconfigentry.ConfigEntryService_WatchIntentionDecisionsServer,
+) error {
+	if req.Destination == "" {
+		return grpcstatus.Error(codes.InvalidArgument, "Destination is required")
+	}
+	if err := s.Backend.EnterpriseCheckPartitions(req.Partition); err != nil {
+		return grpcstatus.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx := serverStream.Context()
+	logger := s.Logger.Named("watch-intention-decisions").With("request_id", external.TraceID())
+	logger.Trace("starting stream")
+	defer logger.Trace("stream closed")
+
+	options, err := external.QueryOptionsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var authzCtx acl.AuthorizerContext
+	entMeta := acl.NewEnterpriseMetaWithPartition(req.Partition, req.Namespace)
+	authz, err := s.Backend.ResolveTokenAndDefaultMeta(options.Token, &entMeta, &authzCtx)
+	if err != nil {
+		return err
+	}
+	if err := authz.ToAllowAuthorizer().IntentionReadAllowed(req.Destination, &authzCtx); err != nil {
+		return grpcstatus.Error(codes.PermissionDenied, err.Error())
+	}
+
+	entry := structs.IntentionMatchEntry{
+		Namespace: entMeta.NamespaceOrDefault(),
+		Partition: entMeta.PartitionOrDefault(),
+		Name:      req.Destination,
+	}
+
+	shutdownCh := s.FSMServer.GetShutdownChannel()
+	var prev map[string]*pbconfigentry.IntentionDecision
+	for {
+		store := s.FSMServer.GetState()
+		ws := memdb.NewWatchSet()
+		ws.Add(store.AbandonCh())
+		ws.Add(shutdownCh)
+
+		idx, ixns, err := store.IntentionMatchOne(ws, entry, structs.IntentionMatchDestination, structs.IntentionTargetService)
+		if err != nil {
+			logger.Error("failed to match intentions", "error", err)
+			return grpcstatus.Error(codes.Internal, err.Error())
+		}
+
+		now := time.Now()
+		decisions := intentionDecisions(ixns, now)
+		rsp := decisionsDelta(prev, decisions)
+		rsp.Index = idx
+		if prev == nil || len(rsp.Upserted) > 0 || len(rsp.Removed) > 0 {
+			if err := serverStream.Send(rsp); err != nil {
+				logger.Error("failed to send response", "error", err)
+				return err
+			}
+		}
+		prev = decisions
+
+		// Intentions with a scheduling window change the decisions without a
+		// write, so wake up at the next window boundary as well.
+		if next, ok := nextIntentionTransition(ixns, now); ok {
+			watchCtx, cancel := context.WithDeadline(ctx, next)
+			ws.WatchCtx(watchCtx)
+			cancel()
+		} else {
+			ws.WatchCtx(ctx)
+		}
+
+		if ctx.Err() != nil {
+			return nil
+		}
+		select {
+		case <-shutdownCh:
+			return grpcstatus.Error(codes.Unavailable, "server is shutting down")
+		default:
+		}
+	}
+}
+
+// intentionDecisions returns the decision for each source matched by ixns,
+// keyed by the source. Intentions are evaluated by precedence so that only
+// the highest precedence intention active at now decides for a source.
+func intentionDecisions(ixns structs.SimplifiedIntentions, now time.Time) map[string]*pbconfigentry.IntentionDecision {
+	sorted := make(structs.Intentions, len(ixns))
+	copy(sorted, ixns)
+	sort.Sort(structs.IntentionPrecedenceSorter(sorted))
+
+	decisions := make(map[string]*pbconfigentry.IntentionDecision, len(sorted))
+	for _, ixn := range sorted {
+		if !ixn.ActiveAt(now) {
+			continue
+		}
+		decision := &pbconfigentry.IntentionDecision{
+			SourceName:          ixn.SourceName,
+			SourceNamespace:     ixn.SourceNS,
+			SourcePartition:     ixn.SourcePartition,
+			SourcePeer:          ixn.SourcePeer,
+			SourceSamenessGroup: ixn.SourceSamenessGroup,
+			Action:              pbconfigentry.IntentionAction_Deny,
+			HasPermissions:      len(ixn.Permissions) > 0,
+			Precedence:          int32(ixn.Precedence),
+		}
+		if ixn.Action == structs.IntentionActionAllow {
+			decision.Action = pbconfigentry.IntentionAction_Allow
+		}
+
+		key := decisionKey(decision)
+		if _, ok := decisions[key]; !ok {
+			decisions[key] = decision
+		}
+	}
+	return decisions
+}
+
+// decisionsDelta returns a response holding the decisions in next that are
+// new or differ from prev, and the sources in prev that next no longer has a
+// decision for. A nil prev produces a snapshot of next.
+func decisionsDelta(prev, next map[string]*pbconfigentry.IntentionDecision) *pbconfigentry.WatchIntentionDecisionsResponse {
+	rsp := &pbconfigentry.WatchIntentionDecisionsResponse{Snapshot: prev == nil}
+	for key, decision := range next {
+		if old, ok := prev[key]; !ok || !proto.Equal(old, decision) {
+			rsp.Upserted = append(rsp.Upserted, decision)
+		}
+	}
+	for key, decision := range prev {
+		if _, ok := next[key]; !ok {
+			rsp.Removed = append(rsp.Removed, &pbconfigentry.IntentionDecision{
+				SourceName:          decision.SourceName,
+				SourceNamespace:     decision.SourceNamespace,
+				SourcePartition:     decision.SourcePartition,
+				SourcePeer:          decision.SourcePeer,
+				SourceSamenessGroup: decision.SourceSamenessGroup,
+			})
+		}
+	}
+	sortDecisions(rsp.Upserted)
+	sortDecisions(rsp.Removed)
+	return rsp
+}
+
+func decisionKey(d *pbconfigentry.IntentionDecision) string {
+	return d.SourcePartition + "/" + d.SourceNamespace + "/" + d.SourceName +
+		"/peer=" + d.SourcePeer + "/sg=" + d.SourceSamenessGroup
+}
+
+func sortDecisions(decisions []*pbconfigentry.IntentionDecision) {
+	sort.Slice(decisions, func(i, j int) bool {
+		return decisionKey(decisions[i]) < decisionKey(decisions[j])
+	})
+}
+
+// nextIntentionTransition returns the earliest NotBefore or NotAfter of ixns
+// that is after now.
+func nextIntentionTransition(ixns structs.SimplifiedIntentions, now time.Time) (time.Time, bool) {
+	var next time.Time
+	for _, ixn := range ixns {
+		for _, t := range []*time.Time{ixn.NotBefore, ixn.NotAfter} {
+			if t != nil && t.After(now) && (next.IsZero() || t.Before(next)) {
+				next = *t
+			}
+		}
+	}
+	return next, !next.IsZero()
+}
@@ -0,0 +1,153 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package configentry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/dhiaayachi/consul/acl"
+	"github.com/dhiaayachi/consul/agent/grpc-external/testutils"
+	"github.com/dhiaayachi/consul/agent/structs"
+	"github.com/dhiaayachi/consul/proto/private/pbconfigentry"
+	"github.com/dhiaayachi/consul/proto/private/prototest"
+)
+
+func TestWatchIntentionDecisions(t *testing.T) {
+	authorizer := acl.MockAuthorizer{}
+	authorizer.On("IntentionRead", "web", mock.Anything).Return(acl.Allow)
+
+	backend := &MockBackend{authorizer: &authorizer}
+	backend.On("EnterpriseCheckPartitions", mock.Anything).Return(nil)
+
+	fakeFSM := testutils.NewFakeBlockingFSM(t)
+	store := fakeFSM.GetState()
+	require.NoError(t, store.SystemMetadataSet(1, &structs.SystemMetadataEntry{
+		Key:   structs.SystemMetadataIntentionFormatKey,
+		Value: structs.SystemMetadataIntentionFormatConfigValue,
+	}))
+
+	setIntentions := func(idx uint64, sources ...*structs.SourceIntention) {
+		t.Helper()
+		entry := &structs.ServiceIntentionsConfigEntry{
+			Kind:    structs.ServiceIntentions,
+			Name:    "web",
+			Sources: sources,
+		}
+		require.NoError(t, entry.Normalize())
+		require.NoError(t, entry.Validate())
+		require.NoError(t, store.EnsureConfigEntry(idx, entry))
+	}
+	setIntentions(2, &structs.SourceIntention{Name: "api", Action: structs.IntentionActionAllow})
+
+	server := NewServer(Config{
+		Backend:    backend,
+		Logger:     hclog.New(nil),
+		ForwardRPC: doForwardRPC,
+		FSMServer:  fakeFSM,
+	})
+
+	conn, err := grpc.Dial(testutils.RunTestServer(t, server).String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	stream, err := pbconfigentry.NewConfigEntryServiceClient(conn).WatchIntentionDecisions(ctx,
+		&pbconfigentry.WatchIntentionDecisionsRequest{Destination: "web"})
+	require.NoError(t, err)
+
+	decision := func(source string, action pbconfigentry.IntentionAction) *pbconfigentry.IntentionDecision {
+		return &pbconfigentry.IntentionDecision{
+			SourceName:      source,
+			SourceNamespace: "default",
+			Action:          action,
+			Precedence:      9,
+		}
+	}
+
+	// The stream starts with the full decision set.
+	rsp, err := stream.Recv()
+	require.NoError(t, err)
+	prototest.AssertDeepEqual(t, &pbconfigentry.WatchIntentionDecisionsResponse{
+		Index:    2,
+		Snapshot: true,
+		Upserted: []*pbconfigentry.IntentionDecision{
+			decision("api", pbconfigentry.IntentionAction_Allow),
+		},
+	}, rsp)
+
+	// Adding an intention only pushes the new decision.
+	setIntentions(3,
+		&structs.SourceIntention{Name: "api", Action: structs.IntentionActionAllow},
+		&structs.SourceIntention{Name: "db", Action: structs.IntentionActionDeny},
+	)
+	rsp, err = stream.Recv()
+	require.NoError(t, err)
+	prototest.AssertDeepEqual(t, &pbconfigentry.WatchIntentionDecisionsResponse{
+		Index: 3,
+		Upserted: []*pbconfigentry.IntentionDecision{
+			decision("db", pbconfigentry.IntentionAction_Deny),
+		},
+	}, rsp)
+
+	// Removing one pushes the source it no longer decides for.
+	setIntentions(4, &structs.SourceIntention{Name: "db", Action: structs.IntentionActionDeny})
+	rsp, err = stream.Recv()
+	require.NoError(t, err)
+	prototest.AssertDeepEqual(t, &pbconfigentry.WatchIntentionDecisionsResponse{
+		Index: 4,
+		Removed: []*pbconfigentry.IntentionDecision{
+			{SourceName: "api", SourceNamespace: "default"},
+		},
+	}, rsp)
+}
+
+func TestWatchIntentionDecisions_Shutdown(t *testing.T) {
+	authorizer := acl.MockAuthorizer{}
+	authorizer.On("IntentionRead", "web", mock.Anything).Return(acl.Allow)
+
+	backend := &MockBackend{authorizer: &authorizer}
+	backend.On("EnterpriseCheckPartitions", mock.Anything).Return(nil)
+
+	fakeFSM := testutils.NewFakeBlockingFSM(t)
+	fakeFSM.ShutdownCh = make(chan struct{})
+
+	server := NewServer(Config{
+		Backend:    backend,
+		Logger:     hclog.New(nil),
+		ForwardRPC: doForwardRPC,
+		FSMServer:  fakeFSM,
+	})
+
+	conn, err := grpc.Dial(testutils.RunTestServer(t, server).String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	stream, err := pbconfigentry.NewConfigEntryServiceClient(conn).WatchIntentionDecisions(ctx,
+		&pbconfigentry.WatchIntentionDecisionsRequest{Destination: "web"})
+	require.NoError(t, err)
+
+	rsp, err := stream.Recv()
+	require.NoError(t, err)
+	require.True(t, rsp.Snapshot)
+
+	// The stream ends once the server shuts down instead of spinning on the
+	// closed channel.
+	close(fakeFSM.ShutdownCh)
+	_, err = stream.Recv()
+	require.Equal(t, codes.Unavailable, status.Code(err))
+}
@@ -73,6 +73,10 @@ func (f *FakeFSM) ReplaceStore(store *state.Store) {
 
 type FakeBlockingFSM struct {
 	store *state.Store
+
+	// ShutdownCh is returned by GetShutdownChannel, closing it simulates the
+	// server shutting down.
+	ShutdownCh chan struct{}
 }
 
 func NewFakeBlockingFSM(t *testing.T) *FakeBlockingFSM {
@@ -102,7 +106,7 @@ func (f *FakeBlockingFSM) IncrementBlockingQueries() uint64 {
 }
 
 func (f *FakeBlockingFSM) GetShutdownChannel() chan struct{} {
-	return nil
+	return f.ShutdownCh
 }
 
 func (f *FakeBlockingFSM) RPCQueryTimeout(queryTimeout time.Duration) time.Duration {
@@ -12,6 +12,7 @@ var rpcRateLimitSpecs = map[string]rate.OperationSpec{
 	"/hashicorp.consul.dataplane.DataplaneService/GetSupportedDataplaneFeatures":            {Type: rate.OperationTypeRead, Category: rate.OperationCategoryDataPlane},
 	"/hashicorp.consul.dns.DNSService/Query":                                                {Type: rate.OperationTypeRead, Category: rate.OperationCategoryDNS},
 	"/hashicorp.consul.internal.configentry.ConfigEntryService/GetResolvedExportedServices": {Type: rate.OperationTypeRead, Category: rate.OperationCategoryConfigEntry},
+	"/hashicorp.consul.internal.configentry.ConfigEntryService/WatchIntentionDecisions":     {Type: rate.OperationTypeRead, Category: rate.OperationCategoryConfigEntry},
 	"/hashicorp.consul.internal.operator.OperatorService/TransferLeader":                    {Type: rate.OperationTypeExempt, Category: rate.OperationCategoryOperator},
 	"/hashicorp.consul.internal.peering.PeeringService/Establish":                           {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryPeering},
 	"/hashicorp.consul.internal.peering.PeeringService/GenerateToken":                       {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryPeering},
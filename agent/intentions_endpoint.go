@@ -4,9 +4,13 @@
 package agent
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/dhiaayachi/consul/acl"
 	cachetype "github.com/dhiaayachi/consul/agent/cache-types"
@@ -41,6 +45,13 @@ func (s *HTTPHandlers) IntentionList(resp http.ResponseWriter, req *http.Request
 		return nil, err
 	}
 
+	if _, ok := req.URL.Query()["unused"]; ok {
+		args.Unused = true
+	}
+	if _, ok := req.URL.Query()["sorted"]; ok {
+		args.Sorted = true
+	}
+
 	var reply structs.IndexedIntentions
 	defer setMeta(resp, &reply.QueryMeta)
 	if err := s.agent.RPC(req.Context(), "Intention.List", &args, &reply); err != nil {
@@ -207,7 +218,7 @@ func (s *HTTPHandlers) IntentionMatch(resp http.ResponseWriter, req *http.Reques
 // GET /v1/connect/intentions/check
 func (s *HTTPHandlers) IntentionCheck(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	// Prepare args
-	args := &structs.IntentionQueryRequest{Check: &structs.IntentionQueryCheck{}}
+	args := &structs.IntentionQueryRequest{}
 	if done := s.parse(resp, req, &args.Datacenter, &args.QueryOptions); done {
 		return nil, nil
 	}
@@ -220,9 +231,9 @@ func (s *HTTPHandlers) IntentionCheck(resp http.ResponseWriter, req *http.Reques
 	q := req.URL.Query()
 
 	// Set the source type if set
-	args.Check.SourceType = structs.IntentionSourceConsul
-	if sourceType, ok := q["source-type"]; ok && len(sourceType) > 0 {
-		args.Check.SourceType = structs.IntentionSourceType(sourceType[0])
+	sourceType := structs.IntentionSourceConsul
+	if st, ok := q["source-type"]; ok && len(st) > 0 {
+		sourceType = structs.IntentionSourceType(st[0])
 	}
 
 	// Extract the source/destination
@@ -235,35 +246,187 @@ func (s *HTTPHandlers) IntentionCheck(resp http.ResponseWriter, req *http.Reques
 		return nil, fmt.Errorf("required query parameter 'destination' not set")
 	}
 
+	var sourcePeer string
+	if peer, ok := q["source-peer"]; ok && len(peer) > 0 {
+		sourcePeer = peer[0]
+	}
+
+	check, err := parseIntentionCheck(source[0], destination[0], sourcePeer, sourceType, &entMeta)
+	if err != nil {
+		return nil, err
+	}
+	args.Check = check
+
+	var reply structs.IntentionQueryCheckResponse
+	if err := s.agent.RPC(req.Context(), "Intention.Check", args, &reply); err != nil {
+		return nil, err
+	}
+
+	return &reply, nil
+}
+
+// intentionCheckBatchRequest is the body of a batch check request.
+type intentionCheckBatchRequest struct {
+	Checks []struct {
+		Source      string
+		Destination string
+		SourcePeer  string
+		SourceType  structs.IntentionSourceType
+	}
+}
+
+// IntentionCheckBatch handles PUT /v1/connect/intentions/check-batch. It
+// tests many source/destination pairs at once.
+func (s *HTTPHandlers) IntentionCheckBatch(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args structs.IntentionQueryCheckBatchRequest
+	if done := s.parse(resp, req, &args.Datacenter, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	var entMeta acl.EnterpriseMeta
+	if err := s.parseEntMetaNoWildcard(req, &entMeta); err != nil {
+		return nil, err
+	}
+
+	var body intentionCheckBatchRequest
+	if err := decodeBody(req.Body, &body); err != nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Request decoding failed: %v", err)}
+	}
+
+	args.Checks = make([]*structs.IntentionQueryCheck, 0, len(body.Checks))
+	for i, c := range body.Checks {
+		if c.Source == "" || c.Destination == "" {
+			return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Check %d: Source and Destination must be set", i)}
+		}
+		sourceType := c.SourceType
+		if sourceType == "" {
+			sourceType = structs.IntentionSourceConsul
+		}
+		check, err := parseIntentionCheck(c.Source, c.Destination, c.SourcePeer, sourceType, &entMeta)
+		if err != nil {
+			return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Check %d: %v", i, err)}
+		}
+		args.Checks = append(args.Checks, check)
+	}
+
+	var reply structs.IntentionQueryCheckBatchResponse
+	if err := s.agent.RPC(req.Context(), "Intention.CheckBatch", &args, &reply); err != nil {
+		return nil, err
+	}
+	if reply.Results == nil {
+		reply.Results = []*structs.IntentionQueryCheckResponse{}
+	}
+	return &reply, nil
+}
+
+// parseIntentionCheck builds the check for a single source/destination pair
+// given in the same format as for the check endpoint.
+func parseIntentionCheck(source, destination, sourcePeer string, sourceType structs.IntentionSourceType, entMeta *acl.EnterpriseMeta) (*structs.IntentionQueryCheck, error) {
+	check := &structs.IntentionQueryCheck{
+		SourceName: source,
+		SourcePeer: sourcePeer,
+		SourceType: sourceType,
+	}
+
 	// We parse them the same way as matches to extract partition/namespace/name
-	args.Check.SourceName = source[0]
-	if args.Check.SourceType == structs.IntentionSourceConsul {
-		parsed, err := parseIntentionStringComponent(source[0], &entMeta, false)
+	if sourceType == structs.IntentionSourceConsul {
+		parsed, err := parseIntentionStringComponent(source, entMeta, false)
 		if err != nil {
-			return nil, fmt.Errorf("source %q is invalid: %s", source[0], err)
+			return nil, fmt.Errorf("source %q is invalid: %s", source, err)
 		}
-		args.Check.SourcePartition = parsed.ap
-		args.Check.SourceNS = parsed.ns
-		args.Check.SourceName = parsed.name
+		check.SourcePartition = parsed.ap
+		check.SourceNS = parsed.ns
+		check.SourceName = parsed.name
 	}
 
 	// The destination is always in the Consul format
-	parsed, err := parseIntentionStringComponent(destination[0], &entMeta, false)
+	parsed, err := parseIntentionStringComponent(destination, entMeta, false)
 	if err != nil {
-		return nil, fmt.Errorf("destination %q is invalid: %s", destination[0], err)
+		return nil, fmt.Errorf("destination %q is invalid: %s", destination, err)
 	}
-	args.Check.DestinationPartition = parsed.ap
-	args.Check.DestinationNS = parsed.ns
-	args.Check.DestinationName = parsed.name
+	check.DestinationPartition = parsed.ap
+	check.DestinationNS = parsed.ns
+	check.DestinationName = parsed.name
 
-	var reply structs.IntentionQueryCheckResponse
-	if err := s.agent.RPC(req.Context(), "Intention.Check", args, &reply); err != nil {
+	return check, nil
+}
+
+// GET /v1/connect/intentions/topology
+func (s *HTTPHandlers) IntentionTopology(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	// Prepare args
+	var args structs.ServiceSpecificRequest
+	if done := s.parse(resp, req, &args.Datacenter, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	var entMeta acl.EnterpriseMeta
+	if err := s.parseEntMetaNoWildcard(req, &entMeta); err != nil {
+		return nil, err
+	}
+
+	service, ok := req.URL.Query()["service"]
+	if !ok || len(service) != 1 {
+		return nil, fmt.Errorf("required query parameter 'service' not set")
+	}
+
+	// We parse it the same way as matches to extract partition/namespace/name
+	parsed, err := parseIntentionStringComponent(service[0], &entMeta, false)
+	if err != nil {
+		return nil, fmt.Errorf("service %q is invalid: %s", service[0], err)
+	}
+	args.ServiceName = parsed.name
+	args.EnterpriseMeta = acl.NewEnterpriseMetaWithPartition(parsed.ap, parsed.ns)
+
+	var reply structs.IndexedIntentionTopology
+	defer setMeta(resp, &reply.QueryMeta)
+	if err := s.agent.RPC(req.Context(), "Intention.Topology", &args, &reply); err != nil {
 		return nil, err
 	}
 
 	return &reply, nil
 }
 
+// IntentionSimulate handles PUT /v1/connect/intentions/simulate. The body is
+// a proposed service-intentions config entry.
+func (s *HTTPHandlers) IntentionSimulate(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var args structs.IntentionSimulateRequest
+	if done := s.parse(resp, req, &args.Datacenter, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	var raw map[string]interface{}
+	if err := decodeBodyDeprecated(req, &raw, nil); err != nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Request decoding failed: %v", err)}
+	}
+	entry, err := structs.DecodeConfigEntry(raw)
+	if err != nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Request decoding failed: %v", err)}
+	}
+	ixnEntry, ok := entry.(*structs.ServiceIntentionsConfigEntry)
+	if !ok {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Request body must be a %s config entry", structs.ServiceIntentions)}
+	}
+
+	var meta acl.EnterpriseMeta
+	if err := s.parseEntMetaForConfigEntryKind(ixnEntry.GetKind(), req, &meta); err != nil {
+		return nil, err
+	}
+	ixnEntry.GetEnterpriseMeta().Merge(&meta)
+	args.Entry = ixnEntry
+
+	var reply structs.IntentionSimulateResponse
+	if err := s.agent.RPC(req.Context(), "Intention.Simulate", &args, &reply); err != nil {
+		return nil, err
+	}
+	if reply.NewlyAllowed == nil {
+		reply.NewlyAllowed = []structs.IntentionSimulatePair{}
+	}
+	if reply.NewlyDenied == nil {
+		reply.NewlyDenied = []structs.IntentionSimulatePair{}
+	}
+	return &reply, nil
+}
+
 // IntentionExact handles the endpoint for /v1/connect/intentions/exact
 func (s *HTTPHandlers) IntentionExact(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	switch req.Method {
@@ -431,6 +594,59 @@ func (s *HTTPHandlers) IntentionDeleteExact(resp http.ResponseWriter, req *http.
 	return true, nil
 }
 
+// PUT /v1/connect/intentions/import
+func (s *HTTPHandlers) IntentionImport(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var entMeta acl.EnterpriseMeta
+	if err := s.parseEntMetaNoWildcard(req, &entMeta); err != nil {
+		return nil, err
+	}
+
+	args := structs.IntentionRequest{
+		Op: structs.IntentionOpImport,
+	}
+	s.parseDC(req, &args.Datacenter)
+	s.parseToken(req, &args.Token)
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Request decode failed: %v", err)}
+	}
+	if err := decodeBody(bytes.NewReader(body), &args.Intention); err != nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Request decode failed: %v", err)}
+	}
+	if args.Intention == nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "Request body is required"}
+	}
+
+	// The intention decoder deliberately drops user provided timestamps, but
+	// an import of a legacy intention needs to keep them.
+	var timestamps struct {
+		CreatedAt, UpdatedAt *time.Time
+	}
+	if err := json.Unmarshal(body, &timestamps); err != nil {
+		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Request decode failed: %v", err)}
+	}
+	if timestamps.CreatedAt != nil {
+		args.Intention.CreatedAt = *timestamps.CreatedAt
+	}
+	if timestamps.UpdatedAt != nil {
+		args.Intention.UpdatedAt = *timestamps.UpdatedAt
+	}
+
+	args.Intention.FillPartitionAndNamespace(&entMeta, false)
+
+	if err := s.validateEnterpriseIntention(args.Intention); err != nil {
+		return nil, err
+	}
+
+	var reply string
+	if err := s.agent.RPC(req.Context(), "Intention.Apply", &args, &reply); err != nil {
+		return nil, err
+	}
+
+	return intentionCreateResponse{reply}, nil
+}
+
 // intentionCreateResponse is the response structure for creating an intention.
 type intentionCreateResponse struct{ ID string }
 
@@ -4,6 +4,7 @@
 package agent
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"net/http"
@@ -269,6 +270,60 @@ func TestIntentionMatch(t *testing.T) {
 	})
 }
 
+func TestIntentionSimulate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	for _, svc := range []string{"web", "db"} {
+		arg := structs.RegisterRequest{
+			Datacenter: "dc1",
+			Node:       "node1",
+			Address:    "127.0.0.1",
+			Service:    &structs.NodeService{Service: svc, Port: 8080},
+		}
+		var out struct{}
+		require.NoError(t, a.RPC(context.Background(), "Catalog.Register", &arg, &out))
+	}
+
+	t.Run("not service-intentions", func(t *testing.T) {
+		body := bytes.NewBufferString(`{"Kind": "service-defaults", "Name": "db"}`)
+		req, err := http.NewRequest("PUT", "/v1/connect/intentions/simulate", body)
+		require.NoError(t, err)
+
+		resp := httptest.NewRecorder()
+		_, err = a.srv.IntentionSimulate(resp, req)
+		testutil.RequireErrorContains(t, err, "must be a service-intentions config entry")
+	})
+
+	// ACLs are disabled, so connections are allowed by default.
+	t.Run("new deny", func(t *testing.T) {
+		body := bytes.NewBufferString(`{
+			"Kind": "service-intentions",
+			"Name": "db",
+			"Sources": [{"Name": "web", "Action": "deny"}]
+		}`)
+		req, err := http.NewRequest("PUT", "/v1/connect/intentions/simulate", body)
+		require.NoError(t, err)
+
+		resp := httptest.NewRecorder()
+		obj, err := a.srv.IntentionSimulate(resp, req)
+		require.NoError(t, err)
+		value := obj.(*structs.IntentionSimulateResponse)
+		require.Equal(t, []structs.IntentionSimulatePair{{
+			Source:      structs.NewServiceName("web", nil),
+			Destination: structs.NewServiceName("db", nil),
+		}}, value.NewlyDenied)
+		require.Empty(t, value.NewlyAllowed)
+	})
+}
+
 func TestIntentionCheck(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -353,6 +408,56 @@ func TestIntentionCheck(t *testing.T) {
 	})
 }
 
+func TestIntentionCheckBatch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	ixn := structs.IntentionRequest{
+		Datacenter: "dc1",
+		Op:         structs.IntentionOpUpsert,
+		Intention:  structs.TestIntention(t),
+	}
+	ixn.Intention.SourceName = "web"
+	ixn.Intention.DestinationName = "db"
+	ixn.Intention.Action = structs.IntentionActionDeny
+	var reply string
+	require.NoError(t, a.RPC(context.Background(), "Intention.Apply", &ixn, &reply))
+
+	t.Run("missing destination", func(t *testing.T) {
+		body := bytes.NewBufferString(`{"Checks": [{"Source": "web"}]}`)
+		req, err := http.NewRequest("PUT", "/v1/connect/intentions/check-batch", body)
+		require.NoError(t, err)
+
+		resp := httptest.NewRecorder()
+		_, err = a.srv.IntentionCheckBatch(resp, req)
+		testutil.RequireErrorContains(t, err, "Check 0: Source and Destination must be set")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		body := bytes.NewBufferString(`{"Checks": [
+			{"Source": "web", "Destination": "db"},
+			{"Source": "api", "Destination": "db"}
+		]}`)
+		req, err := http.NewRequest("PUT", "/v1/connect/intentions/check-batch", body)
+		require.NoError(t, err)
+
+		resp := httptest.NewRecorder()
+		obj, err := a.srv.IntentionCheckBatch(resp, req)
+		require.NoError(t, err)
+		value := obj.(*structs.IntentionQueryCheckBatchResponse)
+		require.Len(t, value.Results, 2)
+		require.False(t, value.Results[0].Allowed)
+		require.True(t, value.Results[1].Allowed)
+	})
+}
+
 func TestIntentionGetExact_PeerIntentions(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -10,6 +10,9 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/hashicorp/go-uuid"
 
 	"github.com/dhiaayachi/consul/agent/structs"
 	"github.com/dhiaayachi/consul/api"
@@ -59,6 +62,29 @@ func (s *HTTPHandlers) KVSGet(resp http.ResponseWriter, req *http.Request, args
 		return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "Missing key name"}
 	}
 
+	// Listing can be limited to the entries with the given flags
+	if _, ok := params["flags"]; ok {
+		if method != "KVS.List" {
+			return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "The flags filter requires recurse"}
+		}
+		flags, err := strconv.ParseUint(params.Get("flags"), 10, 64)
+		if err != nil {
+			return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Invalid flags: %v", err)}
+		}
+		args.Flags = &flags
+	}
+
+	// Listing can also be limited to the entries locked by a session
+	if _, ok := params["session"]; ok {
+		if method != "KVS.List" {
+			return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "The session filter requires recurse"}
+		}
+		if _, err := uuid.ParseUUID(params.Get("session")); err != nil {
+			return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Invalid session: %v", err)}
+		}
+		args.Session = params.Get("session")
+	}
+
 	// Do not allow wildcard NS on GET reqs
 	if method == "KVS.Get" {
 		if err := s.parseEntMetaNoWildcard(req, &args.EnterpriseMeta); err != nil {
@@ -77,6 +103,22 @@ func (s *HTTPHandlers) KVSGet(resp http.ResponseWriter, req *http.Request, args
 	}
 	setMeta(resp, &out.QueryMeta)
 
+	// Servers older than the flags filter ignore it and return every entry.
+	if args.Flags != nil {
+		for _, e := range out.Entries {
+			if e.Flags != *args.Flags {
+				return nil, fmt.Errorf("The flags filter was not applied, all servers must be upgraded to support it")
+			}
+		}
+	}
+	if args.Session != "" {
+		for _, e := range out.Entries {
+			if e.Session != args.Session {
+				return nil, fmt.Errorf("The session filter was not applied, all servers must be upgraded to support it")
+			}
+		}
+	}
+
 	// Check if we get a not found
 	if len(out.Entries) == 0 {
 		resp.WriteHeader(http.StatusNotFound)
@@ -184,6 +226,15 @@ func (s *HTTPHandlers) KVSPut(resp http.ResponseWriter, req *http.Request, args
 		applyReq.DirEnt.Flags = flagVal
 	}
 
+	// Check for an expiration TTL
+	if _, ok := params["ttl"]; ok {
+		ttl, err := time.ParseDuration(params.Get("ttl"))
+		if err != nil {
+			return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Invalid ttl: %v", err)}
+		}
+		applyReq.DirEnt.ExpirationTTL = ttl
+	}
+
 	// Check for cas value
 	if _, ok := params["cas"]; ok {
 		casVal, err := strconv.ParseUint(params.Get("cas"), 10, 64)
@@ -206,6 +257,15 @@ func (s *HTTPHandlers) KVSPut(resp http.ResponseWriter, req *http.Request, args
 		applyReq.Op = api.KVUnlock
 	}
 
+	// Check for a lock transfer to the acquiring session
+	if _, ok := params["transfer-from"]; ok {
+		if applyReq.Op != api.KVLock {
+			return nil, HTTPError{StatusCode: http.StatusBadRequest, Reason: "transfer-from requires acquire"}
+		}
+		applyReq.LockHolder = params.Get("transfer-from")
+		applyReq.Op = api.KVLockTransfer
+	}
+
 	// Check the content-length
 	if req.ContentLength > int64(s.agent.config.KVMaxValueSize) {
 		return nil, HTTPError{
@@ -271,7 +331,20 @@ func (s *HTTPHandlers) KVSDelete(resp http.ResponseWriter, req *http.Request, ar
 		applyReq.Op = api.KVDeleteCAS
 	}
 
-	// Make the RPC
+	// Make the RPC. For recursive deletes the number of deleted keys is
+	// returned in a header so the response body stays the same. Servers that
+	// don't know about KVS.ApplyWithResult yet get the plain KVS.Apply, and
+	// the header is left out.
+	if applyReq.Op == api.KVDeleteTree {
+		var out structs.KVSApplyResponse
+		if err := s.agent.RPC(req.Context(), "KVS.ApplyWithResult", &applyReq, &out); err == nil {
+			resp.Header().Set("X-Consul-KV-Deleted", strconv.Itoa(out.Deleted))
+			return true, nil
+		} else if errMsg := err.Error(); !strings.Contains(errMsg, "rpc: can't find method") {
+			return nil, err
+		}
+	}
+
 	var out bool
 	if err := s.agent.RPC(req.Context(), "KVS.Apply", &applyReq, &out); err != nil {
 		return nil, err
@@ -5,11 +5,17 @@ package agent
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/dhiaayachi/consul/testrpc"
 
@@ -136,12 +142,31 @@ func TestKVSEndpoint_Recurse(t *testing.T) {
 		}
 	}
 
+	{
+		// Delete a subtree, which reports the number of deleted keys.
+		req, _ := http.NewRequest("DELETE", "/v1/kv/foo?recurse", nil)
+		resp := httptest.NewRecorder()
+		obj, err := a.srv.KVSEndpoint(resp, req)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if res := obj.(bool); !res {
+			t.Fatalf("should work")
+		}
+		if deleted := resp.Header().Get("X-Consul-KV-Deleted"); deleted != "2" {
+			t.Fatalf("bad: %q", deleted)
+		}
+	}
+
 	{
 		req, _ := http.NewRequest("DELETE", "/v1/kv/?recurse", nil)
 		resp := httptest.NewRecorder()
 		if _, err := a.srv.KVSEndpoint(resp, req); err != nil {
 			t.Fatalf("err: %v", err)
 		}
+		if deleted := resp.Header().Get("X-Consul-KV-Deleted"); deleted != "3" {
+			t.Fatalf("bad: %q", deleted)
+		}
 	}
 
 	{
@@ -159,6 +184,37 @@ func TestKVSEndpoint_Recurse(t *testing.T) {
 	}
 }
 
+func TestKVSEndpoint_DELETE_Recurse_OldServers(t *testing.T) {
+	t.Parallel()
+
+	// Servers that predate KVS.ApplyWithResult still get the delete, just
+	// without the count of deleted keys.
+	mockDelegate := delegateMock{}
+	mockDelegate.On("RPC", "KVS.ApplyWithResult", mock.Anything, mock.Anything).
+		Return(errors.New("rpc: can't find method KVS.ApplyWithResult"))
+	mockDelegate.On("RPC", "KVS.Apply", mock.Anything, mock.Anything).Return(nil)
+	a := &Agent{delegate: &mockDelegate}
+	h := HTTPHandlers{agent: a}
+
+	req, _ := http.NewRequest("DELETE", "/v1/kv/foo?recurse", nil)
+	resp := httptest.NewRecorder()
+	obj, err := h.KVSDelete(resp, req, &structs.KeyRequest{Key: "foo"})
+	require.NoError(t, err)
+	require.Equal(t, true, obj)
+	require.Empty(t, resp.Header().Get("X-Consul-KV-Deleted"))
+	mockDelegate.AssertExpectations(t)
+
+	// Other errors are returned as-is.
+	mockDelegate = delegateMock{}
+	mockDelegate.On("RPC", "KVS.ApplyWithResult", mock.Anything, mock.Anything).
+		Return(errors.New("Permission denied"))
+	a.delegate = &mockDelegate
+
+	_, err = h.KVSDelete(httptest.NewRecorder(), req, &structs.KeyRequest{Key: "foo"})
+	require.EqualError(t, err, "Permission denied")
+	mockDelegate.AssertNotCalled(t, "RPC", "KVS.Apply", mock.Anything, mock.Anything)
+}
+
 func TestKVSEndpoint_DELETE_CAS(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -309,6 +365,41 @@ func TestKVSEndpoint_CAS(t *testing.T) {
 	}
 }
 
+func TestKVSEndpoint_PUT_TTL(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+
+	req, _ := http.NewRequest("PUT", "/v1/kv/test?ttl=1h", bytes.NewBuffer([]byte("test")))
+	resp := httptest.NewRecorder()
+	obj, err := a.srv.KVSEndpoint(resp, req)
+	require.NoError(t, err)
+	require.True(t, obj.(bool))
+
+	req, _ = http.NewRequest("GET", "/v1/kv/test", nil)
+	resp = httptest.NewRecorder()
+	obj, err = a.srv.KVSEndpoint(resp, req)
+	require.NoError(t, err)
+	d := obj.(structs.DirEntries)[0]
+	require.Equal(t, time.Hour, d.ExpirationTTL)
+	require.True(t, d.HasExpirationTime())
+	require.WithinDuration(t, time.Now().Add(time.Hour), *d.ExpirationTime, time.Minute)
+
+	req, _ = http.NewRequest("PUT", "/v1/kv/test?ttl=soon", bytes.NewBuffer([]byte("test")))
+	resp = httptest.NewRecorder()
+	_, err = a.srv.KVSEndpoint(resp, req)
+	require.ErrorContains(t, err, "Invalid ttl")
+
+	req, _ = http.NewRequest("PUT", "/v1/kv/test?ttl=-1s", bytes.NewBuffer([]byte("test")))
+	resp = httptest.NewRecorder()
+	_, err = a.srv.KVSEndpoint(resp, req)
+	require.ErrorContains(t, err, "must not be negative")
+}
+
 func TestKVSEndpoint_ListKeys(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -425,6 +516,57 @@ func TestKVSEndpoint_AcquireRelease(t *testing.T) {
 	}
 }
 
+func TestKVSEndpoint_LockTransfer(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	// Acquire the lock with the outgoing session
+	outgoing := makeTestSession(t, a.srv)
+	incoming := makeTestSession(t, a.srv)
+	req, _ := http.NewRequest("PUT", "/v1/kv/test?acquire="+outgoing, bytes.NewReader(nil))
+	resp := httptest.NewRecorder()
+	obj, err := a.srv.KVSEndpoint(resp, req)
+	require.NoError(t, err)
+	require.True(t, obj.(bool))
+
+	// A transfer requires the acquiring session
+	req, _ = http.NewRequest("PUT", "/v1/kv/test?transfer-from="+outgoing, bytes.NewReader(nil))
+	resp = httptest.NewRecorder()
+	_, err = a.srv.KVSEndpoint(resp, req)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "transfer-from requires acquire")
+
+	// A session that doesn't hold the lock can't transfer it
+	req, _ = http.NewRequest("PUT", "/v1/kv/test?acquire="+incoming+"&transfer-from="+incoming, bytes.NewReader(nil))
+	resp = httptest.NewRecorder()
+	obj, err = a.srv.KVSEndpoint(resp, req)
+	require.NoError(t, err)
+	require.False(t, obj.(bool))
+
+	// Transfer the lock
+	req, _ = http.NewRequest("PUT", "/v1/kv/test?acquire="+incoming+"&transfer-from="+outgoing, bytes.NewReader(nil))
+	resp = httptest.NewRecorder()
+	obj, err = a.srv.KVSEndpoint(resp, req)
+	require.NoError(t, err)
+	require.True(t, obj.(bool))
+
+	// Verify the incoming session has the lock
+	req, _ = http.NewRequest("GET", "/v1/kv/test", nil)
+	resp = httptest.NewRecorder()
+	obj, err = a.srv.KVSEndpoint(resp, req)
+	require.NoError(t, err)
+	d := obj.(structs.DirEntries)[0]
+	require.Equal(t, incoming, d.Session)
+	require.Equal(t, uint64(2), d.LockIndex)
+}
+
 func TestKVSEndpoint_GET_Raw(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -575,3 +717,129 @@ func TestKVSEndpoint_DELETE_ConflictingFlags(t *testing.T) {
 		t.Fatalf("expected conflicting args error")
 	}
 }
+
+func TestKVSEndpoint_GET_FlagsFilter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	for key, flags := range map[string]string{"test/a": "1", "test/b": "2", "test/c": "1"} {
+		req, _ := http.NewRequest("PUT", "/v1/kv/"+key+"?flags="+flags, bytes.NewBufferString("v"))
+		if _, err := a.srv.KVSEndpoint(httptest.NewRecorder(), req); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+	}
+
+	req, _ := http.NewRequest("GET", "/v1/kv/test?recurse&flags=1", nil)
+	obj, err := a.srv.KVSEndpoint(httptest.NewRecorder(), req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	var keys []string
+	for _, d := range obj.(structs.DirEntries) {
+		keys = append(keys, d.Key)
+	}
+	if !reflect.DeepEqual(keys, []string{"test/a", "test/c"}) {
+		t.Fatalf("bad: %v", keys)
+	}
+
+	req, _ = http.NewRequest("GET", "/v1/kv/test/a?flags=1", nil)
+	if _, err := a.srv.KVSEndpoint(httptest.NewRecorder(), req); err == nil || !strings.Contains(err.Error(), "requires recurse") {
+		t.Fatalf("expected recurse error, got %v", err)
+	}
+
+	req, _ = http.NewRequest("GET", "/v1/kv/test?recurse&flags=nope", nil)
+	if _, err := a.srv.KVSEndpoint(httptest.NewRecorder(), req); err == nil || !strings.Contains(err.Error(), "Invalid flags") {
+		t.Fatalf("expected invalid flags error, got %v", err)
+	}
+}
+
+// mockKVSIgnoreFlags lists KV entries like a server that predates the flags
+// filter.
+type mockKVSIgnoreFlags struct{}
+
+func (m *mockKVSIgnoreFlags) List(args *structs.KeyRequest, reply *structs.IndexedDirEntries) error {
+	reply.Entries = structs.DirEntries{
+		{Key: "test/a", Flags: 1},
+		{Key: "test/b", Flags: 2},
+	}
+	reply.Index = 1
+	return nil
+}
+
+func TestKVSEndpoint_GET_FlagsFilter_OldServers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	if err := a.registerEndpoint("KVS", &mockKVSIgnoreFlags{}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	req, _ := http.NewRequest("GET", "/v1/kv/test?recurse&flags=1", nil)
+	if _, err := a.srv.KVSEndpoint(httptest.NewRecorder(), req); err == nil || !strings.Contains(err.Error(), "flags filter was not applied") {
+		t.Fatalf("expected flags filter error, got %v", err)
+	}
+
+	// Without the filter the entries are returned as is.
+	req, _ = http.NewRequest("GET", "/v1/kv/test?recurse", nil)
+	obj, err := a.srv.KVSEndpoint(httptest.NewRecorder(), req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if n := len(obj.(structs.DirEntries)); n != 2 {
+		t.Fatalf("bad: %d entries", n)
+	}
+}
+
+func TestKVSEndpoint_GET_SessionFilter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	id := makeTestSession(t, a.srv)
+	for _, path := range []string{"test/a?acquire=" + id, "test/b", "test/c?acquire=" + id} {
+		req, _ := http.NewRequest("PUT", "/v1/kv/"+path, bytes.NewBufferString("v"))
+		if _, err := a.srv.KVSEndpoint(httptest.NewRecorder(), req); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+	}
+
+	req, _ := http.NewRequest("GET", "/v1/kv/test?recurse&session="+id, nil)
+	obj, err := a.srv.KVSEndpoint(httptest.NewRecorder(), req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	var keys []string
+	for _, d := range obj.(structs.DirEntries) {
+		keys = append(keys, d.Key)
+	}
+	if !reflect.DeepEqual(keys, []string{"test/a", "test/c"}) {
+		t.Fatalf("bad: %v", keys)
+	}
+
+	req, _ = http.NewRequest("GET", "/v1/kv/test/a?session="+id, nil)
+	if _, err := a.srv.KVSEndpoint(httptest.NewRecorder(), req); err == nil || !strings.Contains(err.Error(), "requires recurse") {
+		t.Fatalf("expected recurse error, got %v", err)
+	}
+
+	req, _ = http.NewRequest("GET", "/v1/kv/test?recurse&session=nope", nil)
+	if _, err := a.srv.KVSEndpoint(httptest.NewRecorder(), req); err == nil || !strings.Contains(err.Error(), "Invalid session") {
+		t.Fatalf("expected invalid session error, got %v", err)
+	}
+}
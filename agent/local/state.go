@@ -60,11 +60,14 @@ type Config struct {
 	CheckUpdateInterval time.Duration
 	Datacenter          string
 	DiscardCheckOutput  bool
-	NodeID              types.NodeID
-	NodeName            string
-	NodeLocality        *structs.Locality
-	Partition           string // this defaults if empty
-	TaggedAddresses     map[string]string
+	// CheckOutputTruncateSize is the size above which check output is
+	// truncated before it is synced. Zero disables the truncation.
+	CheckOutputTruncateSize int
+	NodeID                  types.NodeID
+	NodeName                string
+	NodeLocality            *structs.Locality
+	Partition               string // this defaults if empty
+	TaggedAddresses         map[string]string
 }
 
 // ServiceState describes the state of a service record.
@@ -115,6 +118,10 @@ type CheckState struct {
 	// record on the server.
 	Token string
 
+	// FullOutput is the untruncated output of the health check if it was
+	// longer than the truncation size, and empty otherwise.
+	FullOutput string
+
 	// CriticalTime is the last time the health check status went
 	// from non-critical to critical. When the health check is not
 	// in critical state the value is the zero value.
@@ -210,6 +217,10 @@ type State struct {
 	// is stored in the raft log.
 	discardCheckOutput atomic.Value // bool
 
+	// checkOutputTruncateSize stores the size above which the output of
+	// health checks is truncated before it is stored in the raft log.
+	checkOutputTruncateSize atomic.Value // int
+
 	// tokens contains the ACL tokens
 	tokens *token.Store
 
@@ -235,6 +246,7 @@ func NewState(c Config, logger hclog.Logger, tokens *token.Store) *State {
 		agentEnterpriseMeta: *structs.NodeEnterpriseMetaInPartition(c.Partition),
 	}
 	l.SetDiscardCheckOutput(c.DiscardCheckOutput)
+	l.SetCheckOutputTruncateSize(c.CheckOutputTruncateSize)
 	return l
 }
 
@@ -244,6 +256,23 @@ func (l *State) SetDiscardCheckOutput(b bool) {
 	l.discardCheckOutput.Store(b)
 }
 
+// SetCheckOutputTruncateSize configures the size above which the check
+// output is truncated. Zero disables the truncation. This can be changed
+// at runtime.
+func (l *State) SetCheckOutputTruncateSize(n int) {
+	l.checkOutputTruncateSize.Store(n)
+}
+
+// truncateCheckOutput returns the output to store for a check, and the full
+// output if it had to be truncated.
+func (l *State) truncateCheckOutput(output string) (string, string) {
+	max := l.checkOutputTruncateSize.Load().(int)
+	if max <= 0 || len(output) <= max {
+		return output, ""
+	}
+	return fmt.Sprintf("%s ... (stored %d of %d bytes)", output[:max], max, len(output)), output
+}
+
 // ServiceToken returns the ACL token associated with the service. If the service is
 // not found, or does not have a token, the empty string is returned.
 func (l *State) ServiceToken(id structs.ServiceID) string {
@@ -565,6 +594,8 @@ func (l *State) addCheckLocked(check *structs.HealthCheck, token string, isLocal
 	if l.discardCheckOutput.Load().(bool) {
 		check.Output = ""
 	}
+	var fullOutput string
+	check.Output, fullOutput = l.truncateCheckOutput(check.Output)
 
 	// hard-set the node name and partition
 	check.Node = l.config.NodeName
@@ -582,6 +613,7 @@ func (l *State) addCheckLocked(check *structs.HealthCheck, token string, isLocal
 	l.setCheckStateLocked(&CheckState{
 		Check:            check,
 		Token:            token,
+		FullOutput:       fullOutput,
 		IsLocallyDefined: isLocal,
 	})
 	return nil
@@ -680,6 +712,10 @@ func (l *State) UpdateCheck(id structs.CheckID, status, output string) {
 		output = ""
 	}
 
+	// Keep the full output locally when it is truncated. Like the critical
+	// time below, this never causes a server update.
+	output, c.FullOutput = l.truncateCheckOutput(output)
+
 	// Update the critical time tracking (this doesn't cause a server updates
 	// so we can always keep this up to date).
 	if status == api.HealthCritical {
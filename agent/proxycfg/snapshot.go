@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
 
@@ -1143,6 +1144,49 @@ func (s *ConfigSnapshot) MeshConfigTLSOutgoing() *structs.MeshDirectionalTLSConf
 	return mesh.TLS.Outgoing
 }
 
+// AccessLogs returns the access log configuration for the proxy's listeners.
+// The proxy's own configuration, which includes proxy-defaults, takes
+// precedence over the mesh-wide defaults from the mesh config entry.
+func (s *ConfigSnapshot) AccessLogs() *structs.AccessLogsConfig {
+	if !s.Proxy.AccessLogs.IsZero() {
+		return &s.Proxy.AccessLogs
+	}
+	if mesh := s.MeshConfig(); mesh != nil && mesh.AccessLogs != nil {
+		return mesh.AccessLogs
+	}
+	return &s.Proxy.AccessLogs
+}
+
+// MeshPassiveHealthCheck returns the mesh-wide outlier detection defaults for
+// the proxy's upstream clusters, or nil if the mesh config entry has none.
+func (s *ConfigSnapshot) MeshPassiveHealthCheck() *structs.PassiveHealthCheck {
+	if mesh := s.MeshConfig(); mesh != nil {
+		return mesh.PassiveHealthCheck
+	}
+	return nil
+}
+
+// nextIntentionWindowChange returns when the intentions in effect for the
+// proxy next change because a NotBefore or NotAfter bound passes.
+func (s *ConfigSnapshot) nextIntentionWindowChange(now time.Time) (time.Time, bool) {
+	switch s.Kind {
+	case structs.ServiceKindConnectProxy:
+		return s.ConnectProxy.Intentions.NextActiveChange(now)
+	case structs.ServiceKindTerminatingGateway:
+		var (
+			next  time.Time
+			found bool
+		)
+		for _, intentions := range s.TerminatingGateway.Intentions {
+			if t, ok := intentions.NextActiveChange(now); ok && (!found || t.Before(next)) {
+				next, found = t, true
+			}
+		}
+		return next, found
+	}
+	return time.Time{}, false
+}
+
 func (s *ConfigSnapshot) ToConfigSnapshotUpstreams() (*ConfigSnapshotUpstreams, error) {
 	switch s.Kind {
 	case structs.ServiceKindConnectProxy:
@@ -7,11 +7,14 @@ import (
 	"context"
 	"regexp"
 	"testing"
+	"time"
 
+	"github.com/dhiaayachi/consul/agent/structs"
 	"github.com/dhiaayachi/consul/proto/private/pbpeering"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	fuzz "github.com/google/gofuzz"
+	"github.com/stretchr/testify/require"
 )
 
 func TestConfigSnapshot_Clone(t *testing.T) {
@@ -54,3 +57,31 @@ func TestConfigSnapshot_Clone(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func TestConfigSnapshot_nextIntentionWindowChange(t *testing.T) {
+	now := time.Now()
+	soon, later := now.Add(time.Minute), now.Add(time.Hour)
+
+	pending := structs.TestIntention(t)
+	pending.NotBefore = &later
+	expiring := structs.TestIntention(t)
+	expiring.NotAfter = &soon
+
+	snap := &ConfigSnapshot{Kind: structs.ServiceKindConnectProxy}
+	_, ok := snap.nextIntentionWindowChange(now)
+	require.False(t, ok)
+
+	snap.ConnectProxy.Intentions = structs.SimplifiedIntentions{pending}
+	next, ok := snap.nextIntentionWindowChange(now)
+	require.True(t, ok)
+	require.True(t, next.Equal(later))
+
+	snap = &ConfigSnapshot{Kind: structs.ServiceKindTerminatingGateway}
+	snap.TerminatingGateway.Intentions = map[structs.ServiceName]structs.SimplifiedIntentions{
+		structs.NewServiceName("db", nil):  {pending},
+		structs.NewServiceName("api", nil): {expiring},
+	}
+	next, ok = snap.nextIntentionWindowChange(now)
+	require.True(t, ok)
+	require.True(t, next.Equal(soon))
+}
@@ -375,10 +375,42 @@ func (s *state) unsafeRun(ctx context.Context, snap *ConfigSnapshot) {
 		})
 	}
 
+	// Intentions can start or stop being in effect without any update from
+	// the data sources, so a snapshot is also sent when the next NotBefore or
+	// NotAfter bound of the snapshot's intentions passes.
+	windowCh := make(chan struct{})
+	var windowTimer *time.Timer
+	scheduleIntentionWindowChange := func() {
+		if windowTimer != nil {
+			windowTimer.Stop()
+			windowTimer = nil
+		}
+		next, ok := snap.nextIntentionWindowChange(time.Now())
+		if !ok {
+			return
+		}
+		windowTimer = time.AfterFunc(time.Until(next), func() {
+			select {
+			case windowCh <- struct{}{}:
+			case <-ctx.Done():
+			}
+		})
+	}
+	defer func() {
+		if windowTimer != nil {
+			windowTimer.Stop()
+		}
+	}()
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
+		case <-windowCh:
+			s.logger.Trace("An intention window boundary passed; resending snapshot")
+			windowTimer = nil
+			scheduleIntentionWindowChange()
+
 		case u := <-s.ch:
 			s.logger.Trace("Data source returned; handling snapshot update", "correlationID", u.CorrelationID)
 
@@ -394,6 +426,7 @@ func (s *state) unsafeRun(ctx context.Context, snap *ConfigSnapshot) {
 				)
 				continue
 			}
+			scheduleIntentionWindowChange()
 
 		case <-sendCh:
 			// Allow the next change to trigger a send
@@ -9,37 +9,38 @@ import "github.com/dhiaayachi/consul/agent/consul/rate"
 // for rate limiting purposes. Please be sure to update this list
 // if a net/rpc endpoint is removed.
 var rpcRateLimitSpecs = map[string]rate.OperationSpec{
-	"ACL.AuthMethodDelete":  {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
-	"ACL.AuthMethodList":    {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.AuthMethodRead":    {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.AuthMethodSet":     {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
-	"ACL.Authorize":         {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.BindingRuleDelete": {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
-	"ACL.BindingRuleList":   {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.BindingRuleRead":   {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.BindingRuleSet":    {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
-	"ACL.BootstrapTokens":   {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.Login":             {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
-	"ACL.Logout":            {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
-	"ACL.PolicyBatchRead":   {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.PolicyDelete":      {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
-	"ACL.PolicyList":        {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.PolicyRead":        {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.PolicyResolve":     {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.PolicySet":         {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
-	"ACL.ReplicationStatus": {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.RoleBatchRead":     {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.RoleDelete":        {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
-	"ACL.RoleList":          {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.RoleRead":          {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.RoleResolve":       {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.RoleSet":           {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
-	"ACL.TokenBatchRead":    {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.TokenClone":        {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.TokenDelete":       {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
-	"ACL.TokenList":         {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.TokenRead":         {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
-	"ACL.TokenSet":          {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.AuthMethodDelete":   {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.AuthMethodList":     {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.AuthMethodRead":     {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.AuthMethodSet":      {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.Authorize":          {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.BindingRuleDelete":  {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.BindingRuleList":    {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.BindingRuleRead":    {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.BindingRuleSet":     {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.BootstrapTokens":    {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.Login":              {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.Logout":             {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.PolicyBatchRead":    {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.PolicyDelete":       {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.PolicyList":         {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.PolicyRead":         {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.PolicyResolve":      {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.PolicySet":          {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.PurgeExpiredTokens": {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.ReplicationStatus":  {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.RoleBatchRead":      {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.RoleDelete":         {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.RoleList":           {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.RoleRead":           {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.RoleResolve":        {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.RoleSet":            {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.TokenBatchRead":     {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.TokenClone":         {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.TokenDelete":        {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
+	"ACL.TokenList":          {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.TokenRead":          {Type: rate.OperationTypeRead, Category: rate.OperationCategoryACL},
+	"ACL.TokenSet":           {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryACL},
 
 	"AutoConfig.InitialConfiguration": {Type: rate.OperationTypeRead, Category: rate.OperationCategoryAutoConfig},
 
@@ -53,16 +54,21 @@ var rpcRateLimitSpecs = map[string]rate.OperationSpec{
 	"Catalog.NodeServiceList":     {Type: rate.OperationTypeRead, Category: rate.OperationCategoryCatalog},
 	"Catalog.NodeServices":        {Type: rate.OperationTypeRead, Category: rate.OperationCategoryCatalog},
 	"Catalog.Register":            {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryCatalog},
+	"Catalog.ServiceByVirtualIP":  {Type: rate.OperationTypeRead, Category: rate.OperationCategoryCatalog},
 	"Catalog.ServiceList":         {Type: rate.OperationTypeRead, Category: rate.OperationCategoryCatalog},
 	"Catalog.ServiceNodes":        {Type: rate.OperationTypeRead, Category: rate.OperationCategoryCatalog},
 	"Catalog.VirtualIPForService": {Type: rate.OperationTypeRead, Category: rate.OperationCategoryCatalog},
 
 	"ConfigEntry.Apply":                {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryConfigEntry},
 	"ConfigEntry.Delete":               {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryConfigEntry},
+	"ConfigEntry.DryRunIntentions":     {Type: rate.OperationTypeRead, Category: rate.OperationCategoryConfigEntry},
 	"ConfigEntry.Get":                  {Type: rate.OperationTypeRead, Category: rate.OperationCategoryConfigEntry},
 	"ConfigEntry.List":                 {Type: rate.OperationTypeRead, Category: rate.OperationCategoryConfigEntry},
 	"ConfigEntry.ListAll":              {Type: rate.OperationTypeRead, Category: rate.OperationCategoryConfigEntry},
+	"ConfigEntry.Patch":                {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryConfigEntry},
+	"ConfigEntry.ReplicationStatus":    {Type: rate.OperationTypeRead, Category: rate.OperationCategoryConfigEntry},
 	"ConfigEntry.ResolveServiceConfig": {Type: rate.OperationTypeRead, Category: rate.OperationCategoryConfigEntry},
+	"ConfigEntry.ShadowApply":          {Type: rate.OperationTypeRead, Category: rate.OperationCategoryConfigEntry},
 
 	"ConnectCA.ConfigurationGet": {Type: rate.OperationTypeRead, Category: rate.OperationCategoryConnectCA},
 	"ConnectCA.ConfigurationSet": {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryConnectCA},
@@ -83,16 +89,21 @@ var rpcRateLimitSpecs = map[string]rate.OperationSpec{
 	"FederationState.List":             {Type: rate.OperationTypeRead, Category: rate.OperationCategoryFederationState},
 	"FederationState.ListMeshGateways": {Type: rate.OperationTypeRead, Category: rate.OperationCategoryFederationState},
 
-	"Health.ChecksInState": {Type: rate.OperationTypeRead, Category: rate.OperationCategoryHealth},
-	"Health.NodeChecks":    {Type: rate.OperationTypeRead, Category: rate.OperationCategoryHealth},
-	"Health.ServiceChecks": {Type: rate.OperationTypeRead, Category: rate.OperationCategoryHealth},
-	"Health.ServiceNodes":  {Type: rate.OperationTypeRead, Category: rate.OperationCategoryHealth},
-
-	"Intention.Apply": {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryIntention},
-	"Intention.Check": {Type: rate.OperationTypeRead, Category: rate.OperationCategoryIntention},
-	"Intention.Get":   {Type: rate.OperationTypeRead, Category: rate.OperationCategoryIntention},
-	"Intention.List":  {Type: rate.OperationTypeRead, Category: rate.OperationCategoryIntention},
-	"Intention.Match": {Type: rate.OperationTypeRead, Category: rate.OperationCategoryIntention},
+	"Health.ChecksInState":          {Type: rate.OperationTypeRead, Category: rate.OperationCategoryHealth},
+	"Health.NodeChecks":             {Type: rate.OperationTypeRead, Category: rate.OperationCategoryHealth},
+	"Health.ServiceChecks":          {Type: rate.OperationTypeRead, Category: rate.OperationCategoryHealth},
+	"Health.ServiceFailoverSummary": {Type: rate.OperationTypeRead, Category: rate.OperationCategoryHealth},
+	"Health.ServiceNodes":           {Type: rate.OperationTypeRead, Category: rate.OperationCategoryHealth},
+
+	"Intention.Apply":      {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryIntention},
+	"Intention.Check":      {Type: rate.OperationTypeRead, Category: rate.OperationCategoryIntention},
+	"Intention.CheckBatch": {Type: rate.OperationTypeRead, Category: rate.OperationCategoryIntention},
+	"Intention.Get":        {Type: rate.OperationTypeRead, Category: rate.OperationCategoryIntention},
+	"Intention.List":       {Type: rate.OperationTypeRead, Category: rate.OperationCategoryIntention},
+	"Intention.Match":      {Type: rate.OperationTypeRead, Category: rate.OperationCategoryIntention},
+	"Intention.Simulate":   {Type: rate.OperationTypeRead, Category: rate.OperationCategoryIntention},
+	"Intention.Precedence": {Type: rate.OperationTypeRead, Category: rate.OperationCategoryIntention},
+	"Intention.Topology":   {Type: rate.OperationTypeRead, Category: rate.OperationCategoryIntention},
 
 	"Internal.CatalogOverview":               {Type: rate.OperationTypeRead, Category: rate.OperationCategoryInternal},
 	"Internal.EventFire":                     {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryInternal},
@@ -110,10 +121,11 @@ var rpcRateLimitSpecs = map[string]rate.OperationSpec{
 	"Internal.ServiceGateways":               {Type: rate.OperationTypeRead, Category: rate.OperationCategoryInternal},
 	"Internal.ServiceTopology":               {Type: rate.OperationTypeRead, Category: rate.OperationCategoryInternal},
 
-	"KVS.Apply":    {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryKV},
-	"KVS.Get":      {Type: rate.OperationTypeRead, Category: rate.OperationCategoryKV},
-	"KVS.List":     {Type: rate.OperationTypeRead, Category: rate.OperationCategoryKV},
-	"KVS.ListKeys": {Type: rate.OperationTypeRead, Category: rate.OperationCategoryKV},
+	"KVS.Apply":           {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryKV},
+	"KVS.ApplyWithResult": {Type: rate.OperationTypeWrite, Category: rate.OperationCategoryKV},
+	"KVS.Get":             {Type: rate.OperationTypeRead, Category: rate.OperationCategoryKV},
+	"KVS.List":            {Type: rate.OperationTypeRead, Category: rate.OperationCategoryKV},
+	"KVS.ListKeys":        {Type: rate.OperationTypeRead, Category: rate.OperationCategoryKV},
 
 	"Operator.AutopilotGetConfiguration": {Type: rate.OperationTypeExempt, Category: rate.OperationCategoryOperator},
 	"Operator.AutopilotSetConfiguration": {Type: rate.OperationTypeExempt, Category: rate.OperationCategoryOperator},
@@ -136,6 +148,8 @@ var rpcRateLimitSpecs = map[string]rate.OperationSpec{
 	"Session.List":         {Type: rate.OperationTypeRead, Category: rate.OperationCategorySession},
 	"Session.NodeSessions": {Type: rate.OperationTypeRead, Category: rate.OperationCategorySession},
 	"Session.Renew":        {Type: rate.OperationTypeWrite, Category: rate.OperationCategorySession},
+	"Session.RenewAll":     {Type: rate.OperationTypeWrite, Category: rate.OperationCategorySession},
+	"Session.Transfer":     {Type: rate.OperationTypeWrite, Category: rate.OperationCategorySession},
 
 	"Status.Leader":    {Type: rate.OperationTypeExempt, Category: rate.OperationCategoryStatus},
 	"Status.Peers":     {Type: rate.OperationTypeExempt, Category: rate.OperationCategoryStatus},
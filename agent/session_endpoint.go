@@ -21,8 +21,9 @@ type sessionCreateResponse struct {
 
 // SessionCreate is used to create a new session
 func (s *HTTPHandlers) SessionCreate(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
-	// Default the session to our node + serf check + release session
-	// invalidate behavior.
+	// Default the session to our node + serf check. The invalidate behavior
+	// is defaulted by the servers, which apply the namespace's
+	// session-defaults config entry.
 	args := structs.SessionRequest{
 		Op: structs.SessionCreate,
 		Session: structs.Session{
@@ -30,7 +31,6 @@ func (s *HTTPHandlers) SessionCreate(resp http.ResponseWriter, req *http.Request
 			NodeChecks: []string{string(structs.SerfCheckID)},
 			Checks:     []types.CheckID{structs.SerfCheckID},
 			LockDelay:  15 * time.Second,
-			Behavior:   structs.SessionKeysRelease,
 			TTL:        "",
 		},
 	}
@@ -155,6 +155,9 @@ func (s *HTTPHandlers) SessionList(resp http.ResponseWriter, req *http.Request)
 	if err := s.parseEntMeta(req, &args.EnterpriseMeta); err != nil {
 		return nil, err
 	}
+	if err := parseSessionListFilter(req, &args.ListFilter); err != nil {
+		return nil, err
+	}
 
 	var out structs.IndexedSessions
 	defer setMeta(resp, &out.QueryMeta)
@@ -169,6 +172,31 @@ func (s *HTTPHandlers) SessionList(resp http.ResponseWriter, req *http.Request)
 	return out.Sessions, nil
 }
 
+// parseSessionListFilter reads the behavior, min-lock-delay and
+// max-lock-delay query parameters into filter.
+func parseSessionListFilter(req *http.Request, filter *structs.SessionListFilter) error {
+	query := req.URL.Query()
+	filter.Behavior = structs.SessionBehavior(query.Get("behavior"))
+	if raw := query.Get("min-lock-delay"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Invalid min-lock-delay: %v", err)}
+		}
+		filter.MinLockDelay = d
+	}
+	if raw := query.Get("max-lock-delay"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return HTTPError{StatusCode: http.StatusBadRequest, Reason: fmt.Sprintf("Invalid max-lock-delay: %v", err)}
+		}
+		filter.MaxLockDelay = &d
+	}
+	if err := filter.Validate(); err != nil {
+		return HTTPError{StatusCode: http.StatusBadRequest, Reason: err.Error()}
+	}
+	return nil
+}
+
 // SessionsForNode returns all the nodes belonging to a node
 func (s *HTTPHandlers) SessionsForNode(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	args := structs.NodeSpecificRequest{}
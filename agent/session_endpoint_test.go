@@ -816,3 +816,50 @@ func TestSessionDeleteDestroy(t *testing.T) {
 		t.Fatalf("bad: %v found, should be nothing", res)
 	}
 }
+
+func TestParseSessionListFilter(t *testing.T) {
+	t.Parallel()
+
+	zero := time.Duration(0)
+	thirty := 30 * time.Second
+	cases := map[string]struct {
+		query  string
+		expect structs.SessionListFilter
+		err    string
+	}{
+		"no filter": {},
+		"behavior and min lock delay": {
+			query:  "behavior=delete&min-lock-delay=10s",
+			expect: structs.SessionListFilter{Behavior: structs.SessionKeysDelete, MinLockDelay: 10 * time.Second},
+		},
+		"max lock delay": {
+			query:  "max-lock-delay=30s",
+			expect: structs.SessionListFilter{MaxLockDelay: &thirty},
+		},
+		"zero max lock delay": {
+			query:  "max-lock-delay=0s",
+			expect: structs.SessionListFilter{MaxLockDelay: &zero},
+		},
+		"invalid duration": {
+			query: "max-lock-delay=soon",
+			err:   "Invalid max-lock-delay",
+		},
+		"inverted range": {
+			query: "min-lock-delay=10s&max-lock-delay=0s",
+			err:   "greater than maximum lock delay",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/v1/session/list?"+tc.query, nil)
+			var filter structs.SessionListFilter
+			err := parseSessionListFilter(req, &filter)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expect, filter)
+		})
+	}
+}
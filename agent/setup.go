@@ -366,6 +366,7 @@ func getPrometheusDefs(cfg *config.RuntimeConfig, isServer bool) ([]prometheus.G
 	// TODO(ffmmm): conditionally add only leader specific metrics to gauges, counters, summaries, etc
 	if isServer {
 		gauges = append(gauges,
+			consul.ACLTokenReapingGauges,
 			consul.AutopilotGauges,
 			consul.LeaderCertExpirationGauges,
 			consul.LeaderPeeringMetrics,
@@ -435,6 +436,7 @@ func getPrometheusDefs(cfg *config.RuntimeConfig, isServer bool) ([]prometheus.G
 		consul.ACLCounters,
 		consul.CatalogCounters,
 		consul.ClientCounters,
+		consul.LeaderCounters,
 		consul.RPCCounters,
 		grpcWare.StatsCounters,
 		local.StateCounters,
@@ -40,6 +40,21 @@ func (s *HTTPHandlers) Snapshot(resp http.ResponseWriter, req *http.Request) (in
 		return nil, nil
 
 	case "PUT":
+		if _, ok := req.URL.Query()["verify-only"]; ok {
+			args.Op = structs.SnapshotVerify
+
+			var result structs.SnapshotVerifyResult
+			replyFn := func(reply *structs.SnapshotResponse) error {
+				result.Index = reply.Index
+				result.TableCounts = reply.TableCounts
+				return nil
+			}
+			if err := s.agent.delegate.SnapshotRPC(&args, req.Body, nil, replyFn); err != nil {
+				return nil, err
+			}
+			return result, nil
+		}
+
 		args.Op = structs.SnapshotRestore
 		if err := s.agent.delegate.SnapshotRPC(&args, req.Body, resp, nil); err != nil {
 			return nil, err
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"hash"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -321,6 +322,10 @@ type ACLToken struct {
 	// AuthMethod is the name of the auth method used to create this token.
 	AuthMethod string `json:",omitempty"`
 
+	// WriteRateLimit caps how many write RPCs per second may be issued with
+	// this token. The zero value means the token is not rate limited.
+	WriteRateLimit float64 `json:",omitempty"`
+
 	// ACLAuthMethodEnterpriseMeta is the EnterpriseMeta for the AuthMethod that this token was created from
 	ACLAuthMethodEnterpriseMeta
 
@@ -534,6 +539,10 @@ func (t *ACLToken) SetHash(force bool) []byte {
 			templatedPolicy.AddToHash(hash)
 		}
 
+		if t.WriteRateLimit != 0 {
+			hash.Write([]byte(strconv.FormatFloat(t.WriteRateLimit, 'g', -1, 64)))
+		}
+
 		t.EnterpriseMeta.AddToHash(hash, false)
 
 		// Finalize the hash
@@ -1432,6 +1441,23 @@ type ACLTokenBatchDeleteRequest struct {
 	TokenIDs []string // Tokens to delete
 }
 
+// ACLTokenPurgeExpiredRequest is used to delete all expired tokens right
+// away instead of waiting for them to be reaped.
+type ACLTokenPurgeExpiredRequest struct {
+	Datacenter string
+	WriteRequest
+}
+
+func (r *ACLTokenPurgeExpiredRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+// ACLTokenPurgeExpiredResponse reports how many expired tokens were deleted.
+type ACLTokenPurgeExpiredResponse struct {
+	Local  int
+	Global int
+}
+
 type ACLInitialTokenBootstrapRequest struct {
 	BootstrapSecret string
 	Datacenter      string
@@ -1583,6 +1609,12 @@ type ACLPolicyBatchDeleteRequest struct {
 type ACLRoleSetRequest struct {
 	Role       ACLRole // The role to upsert
 	Datacenter string  // The datacenter to perform the request within
+
+	// Template is the name of a role template configured on the servers to
+	// create the role from, with TemplateVariables substituted.
+	Template          string
+	TemplateVariables map[string]string
+
 	WriteRequest
 }
 
@@ -0,0 +1,114 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+// ACLRoleTemplate is a role definition configured on the servers that new
+// roles can be created from. Its fields may reference the variables given
+// when a role is created from it, e.g. {{.name}}.
+type ACLRoleTemplate struct {
+	Name              string
+	Description       string
+	Policies          []string // policy names
+	ServiceIdentities []*ACLServiceIdentity
+	NodeIdentities    []*ACLNodeIdentity
+}
+
+// Validate checks that the template is named and that all of its fields are
+// valid templates.
+func (t *ACLRoleTemplate) Validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("role template name is required")
+	}
+	_, err := t.expand(func(s string) (string, error) {
+		_, err := parseRoleTemplateField(s)
+		return s, err
+	})
+	if err != nil {
+		return fmt.Errorf("role template %q is not valid: %w", t.Name, err)
+	}
+	return nil
+}
+
+// Instantiate adds the policies and identities of the template to role with
+// the given variables substituted. The description of the template is used
+// if role does not have one.
+func (t *ACLRoleTemplate) Instantiate(role *ACLRole, variables map[string]string) error {
+	expanded, err := t.expand(func(s string) (string, error) {
+		tpl, err := parseRoleTemplateField(s)
+		if err != nil {
+			return "", err
+		}
+		var buf bytes.Buffer
+		if err := tpl.Execute(&buf, variables); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to instantiate role template %q: %w", t.Name, err)
+	}
+
+	if role.Description == "" {
+		role.Description = expanded.Description
+	}
+	for _, name := range expanded.Policies {
+		role.Policies = append(role.Policies, ACLRolePolicyLink{Name: name})
+	}
+	role.ServiceIdentities = append(role.ServiceIdentities, expanded.ServiceIdentities...)
+	role.NodeIdentities = append(role.NodeIdentities, expanded.NodeIdentities...)
+	return nil
+}
+
+// expand returns a copy of the template with fn applied to every field that
+// may reference variables.
+func (t *ACLRoleTemplate) expand(fn func(string) (string, error)) (*ACLRoleTemplate, error) {
+	var err error
+	out := &ACLRoleTemplate{Name: t.Name}
+
+	if out.Description, err = fn(t.Description); err != nil {
+		return nil, err
+	}
+	for _, policy := range t.Policies {
+		name, err := fn(policy)
+		if err != nil {
+			return nil, err
+		}
+		out.Policies = append(out.Policies, name)
+	}
+	for _, svcid := range t.ServiceIdentities {
+		expanded := &ACLServiceIdentity{}
+		if expanded.ServiceName, err = fn(svcid.ServiceName); err != nil {
+			return nil, err
+		}
+		for _, dc := range svcid.Datacenters {
+			dc, err := fn(dc)
+			if err != nil {
+				return nil, err
+			}
+			expanded.Datacenters = append(expanded.Datacenters, dc)
+		}
+		out.ServiceIdentities = append(out.ServiceIdentities, expanded)
+	}
+	for _, nodeid := range t.NodeIdentities {
+		expanded := &ACLNodeIdentity{}
+		if expanded.NodeName, err = fn(nodeid.NodeName); err != nil {
+			return nil, err
+		}
+		if expanded.Datacenter, err = fn(nodeid.Datacenter); err != nil {
+			return nil, err
+		}
+		out.NodeIdentities = append(out.NodeIdentities, expanded)
+	}
+	return out, nil
+}
+
+func parseRoleTemplateField(s string) (*template.Template, error) {
+	return template.New("").Option("missingkey=error").Parse(s)
+}
@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLRoleTemplate_Validate(t *testing.T) {
+	cases := map[string]struct {
+		tmpl   ACLRoleTemplate
+		errMsg string
+	}{
+		"valid": {
+			tmpl: ACLRoleTemplate{
+				Name:              "svc",
+				Description:       "Role for {{.name}}",
+				ServiceIdentities: []*ACLServiceIdentity{{ServiceName: "{{.name}}"}},
+			},
+		},
+		"missing name": {
+			tmpl:   ACLRoleTemplate{Policies: []string{"base"}},
+			errMsg: "role template name is required",
+		},
+		"invalid node identity": {
+			tmpl: ACLRoleTemplate{
+				Name:           "node",
+				NodeIdentities: []*ACLNodeIdentity{{NodeName: "{{.node", Datacenter: "dc1"}},
+			},
+			errMsg: `role template "node" is not valid`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.tmpl.Validate()
+			if tc.errMsg == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.errMsg)
+			}
+		})
+	}
+}
+
+func TestACLRoleTemplate_Instantiate(t *testing.T) {
+	tmpl := &ACLRoleTemplate{
+		Name:              "svc",
+		Description:       "Role for {{.name}}",
+		Policies:          []string{"{{.name}}-policy"},
+		ServiceIdentities: []*ACLServiceIdentity{{ServiceName: "{{.name}}", Datacenters: []string{"{{.dc}}"}}},
+		NodeIdentities:    []*ACLNodeIdentity{{NodeName: "{{.name}}-node", Datacenter: "{{.dc}}"}},
+	}
+
+	t.Run("variables are substituted", func(t *testing.T) {
+		role := &ACLRole{
+			Name:     "web-role",
+			Policies: []ACLRolePolicyLink{{Name: "extra"}},
+		}
+		require.NoError(t, tmpl.Instantiate(role, map[string]string{"name": "web", "dc": "dc2"}))
+
+		require.Equal(t, &ACLRole{
+			Name:              "web-role",
+			Description:       "Role for web",
+			Policies:          []ACLRolePolicyLink{{Name: "extra"}, {Name: "web-policy"}},
+			ServiceIdentities: []*ACLServiceIdentity{{ServiceName: "web", Datacenters: []string{"dc2"}}},
+			NodeIdentities:    []*ACLNodeIdentity{{NodeName: "web-node", Datacenter: "dc2"}},
+		}, role)
+
+		// The template itself is left untouched.
+		require.Equal(t, "{{.name}}", tmpl.ServiceIdentities[0].ServiceName)
+	})
+
+	t.Run("description is kept", func(t *testing.T) {
+		role := &ACLRole{Name: "web-role", Description: "custom"}
+		require.NoError(t, tmpl.Instantiate(role, map[string]string{"name": "web", "dc": "dc2"}))
+		require.Equal(t, "custom", role.Description)
+	})
+
+	t.Run("missing variable", func(t *testing.T) {
+		role := &ACLRole{Name: "web-role"}
+		err := tmpl.Instantiate(role, map[string]string{"name": "web"})
+		require.ErrorContains(t, err, `failed to instantiate role template "svc"`)
+		require.ErrorContains(t, err, `"dc"`)
+	})
+}
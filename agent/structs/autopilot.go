@@ -128,6 +128,12 @@ type RaftStats struct {
 
 	// LastIndex is the last log index this server has a record of in its Raft log.
 	LastIndex uint64
+
+	// CommitIndex is the highest log index this server knows to be committed.
+	CommitIndex uint64
+
+	// AppliedIndex is the highest log index this server has applied to its FSM.
+	AppliedIndex uint64
 }
 
 func (s *RaftStats) ToAutopilotServerStats() *autopilot.ServerStats {
@@ -71,3 +71,9 @@ type AssignServiceManualVIPsResponse struct {
 	Found          bool
 	UnassignedFrom []PeeredServiceName
 }
+
+// ReclaimVirtualIPsRequest is used by the leader to free the virtual IPs of
+// services that no longer have any registrations or references.
+type ReclaimVirtualIPsRequest struct {
+	Services []PeeredServiceName
+}
@@ -4,9 +4,12 @@
 package structs
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -46,9 +49,14 @@ const (
 	// TODO: decide if we want to highlight 'ip' keyword in the name of RateLimitIPConfig
 	RateLimitIPConfig string = "control-plane-request-limit"
 	JWTProvider       string = "jwt-provider"
+	SessionDefaults   string = "session-defaults"
+	// ConfigEntryDefaults entries hold the defaults for another kind, which
+	// is used as their name.
+	ConfigEntryDefaults string = "config-entry-defaults"
 
-	ProxyConfigGlobal string = "global"
-	MeshConfigMesh    string = "mesh"
+	ProxyConfigGlobal   string = "global"
+	MeshConfigMesh      string = "mesh"
+	SessionDefaultsName string = "session-defaults"
 
 	LLMAgent                string = "llm-agent"
 	LLMAgentExternalServers string = "llm-agent-external-servers"
@@ -78,6 +86,8 @@ var AllConfigEntryKinds = []string{
 	InlineCertificate,
 	RateLimitIPConfig,
 	JWTProvider,
+	SessionDefaults,
+	ConfigEntryDefaults,
 }
 
 // ConfigEntry is the interface for centralized configuration stored in Raft.
@@ -736,6 +746,159 @@ func DecodeConfigEntry(raw map[string]interface{}) (ConfigEntry, error) {
 	return entry, nil
 }
 
+// MergePatchConfigEntry applies a JSON merge patch (RFC 7386) to the JSON
+// form of entry and decodes the result like DecodeConfigEntry. The entry
+// itself is not modified. The patch cannot change the kind, name or tenancy
+// of the entry.
+func MergePatchConfigEntry(entry ConfigEntry, patch []byte) (ConfigEntry, error) {
+	var rawPatch interface{}
+	if err := json.Unmarshal(patch, &rawPatch); err != nil {
+		return nil, fmt.Errorf("invalid patch: %w", err)
+	}
+	patchObj, ok := rawPatch.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid patch: must be a JSON object")
+	}
+	patchObj = canonicalPatchKeys(reflect.TypeOf(entry), patchObj)
+
+	encoded, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(encoded, &doc); err != nil {
+		return nil, err
+	}
+
+	errRename := fmt.Errorf("invalid patch: the Kind and Name of a config entry cannot be changed")
+	if kind, ok := patchObj["Kind"]; ok && kind != entry.GetKind() {
+		return nil, errRename
+	}
+
+	patched, err := DecodeConfigEntry(mergePatch(doc, patchObj))
+	if err != nil {
+		return nil, err
+	}
+	if patched.GetName() != entry.GetName() {
+		return nil, errRename
+	}
+	*patched.GetEnterpriseMeta() = *entry.GetEnterpriseMeta()
+	return patched, nil
+}
+
+// canonicalPatchKeys rewrites the keys of patch that refer to a field of t
+// in a different case or by one of its aliases, such as "external_sni", to
+// the key the field is encoded with in JSON. Without this a snake_case key
+// would not replace the existing value during the merge and the decoder would
+// then ignore it in favor of the canonical key. If a patch contains both the
+// canonical key and an alias the canonical key wins, like in
+// decode.HookTranslateKeys.
+func canonicalPatchKeys(t reflect.Type, patch map[string]interface{}) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Map:
+		// Map keys are user data, only the values may need translating.
+		for k, v := range patch {
+			if obj, ok := v.(map[string]interface{}); ok {
+				patch[k] = canonicalPatchKeys(t.Elem(), obj)
+			}
+		}
+		return patch
+	case reflect.Struct:
+	default:
+		return patch
+	}
+
+	fields := patchFieldsForType(t)
+	result := make(map[string]interface{}, len(patch))
+	for k, v := range patch {
+		field, ok := fields[strings.ToLower(k)]
+		if !ok {
+			result[k] = v
+			continue
+		}
+		if _, ok := patch[field.key]; ok && k != field.key {
+			continue
+		}
+		if obj, ok := v.(map[string]interface{}); ok {
+			v = canonicalPatchKeys(field.typ, obj)
+		}
+		result[field.key] = v
+	}
+	return result
+}
+
+type patchField struct {
+	key  string
+	name string
+	typ  reflect.Type
+}
+
+// patchFieldsForType returns the fields of the struct t keyed by the lower
+// case form of every name they may be given in a patch.
+func patchFieldsForType(t reflect.Type) map[string]patchField {
+	fields := make(map[string]patchField)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for k, v := range patchFieldsForType(embedded) {
+					fields[k] = v
+				}
+				continue
+			}
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		field := patchField{key: name, name: f.Name, typ: f.Type}
+		fields[strings.ToLower(name)] = field
+		fields[strings.ToLower(f.Name)] = field
+		if aliases, ok := f.Tag.Lookup("alias"); ok {
+			for _, alias := range strings.Split(aliases, ",") {
+				fields[strings.ToLower(alias)] = field
+			}
+		}
+	}
+	return fields
+}
+
+// mergePatch merges patch into doc as described in RFC 7386: null values
+// remove keys, objects are merged recursively and any other value replaces
+// the existing one.
+func mergePatch(doc, patch map[string]interface{}) map[string]interface{} {
+	if doc == nil {
+		doc = make(map[string]interface{}, len(patch))
+	}
+	for k, v := range patch {
+		switch v := v.(type) {
+		case nil:
+			delete(doc, k)
+		case map[string]interface{}:
+			existing, _ := doc[k].(map[string]interface{})
+			doc[k] = mergePatch(existing, v)
+		default:
+			doc[k] = v
+		}
+	}
+	return doc
+}
+
 type ConfigEntryOp string
 
 const (
@@ -752,6 +915,11 @@ type ConfigEntryRequest struct {
 	Datacenter string
 	Entry      ConfigEntry
 
+	// SetFields lists the top-level fields of Entry that were given
+	// explicitly, so that config-entry-defaults don't replace them even when
+	// they hold their zero value.
+	SetFields []string
+
 	WriteRequest
 }
 
@@ -811,6 +979,28 @@ func (c *ConfigEntryRequest) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+// ConfigEntryPatchRequest is used to update part of an existing config entry
+// with a JSON merge patch.
+type ConfigEntryPatchRequest struct {
+	Datacenter string
+	Kind       string
+	Name       string
+
+	// Patch is a JSON merge patch (RFC 7386) to apply to the current entry.
+	Patch []byte
+
+	// CASIndex, if non-zero, only applies the patch if the ModifyIndex of
+	// the current entry matches it.
+	CASIndex uint64
+
+	acl.EnterpriseMeta `hcl:",squash" mapstructure:",squash"`
+	WriteRequest
+}
+
+func (c *ConfigEntryPatchRequest) RequestDatacenter() string {
+	return c.Datacenter
+}
+
 func MakeConfigEntry(kind, name string) (ConfigEntry, error) {
 	if configEntry := makeEnterpriseConfigEntry(kind, name); configEntry != nil {
 		return configEntry, nil
@@ -852,6 +1042,10 @@ func MakeConfigEntry(kind, name string) (ConfigEntry, error) {
 		return &TCPRouteConfigEntry{Name: name}, nil
 	case JWTProvider:
 		return &JWTProviderConfigEntry{Name: name}, nil
+	case SessionDefaults:
+		return &SessionDefaultsConfigEntry{}, nil
+	case ConfigEntryDefaults:
+		return &ConfigEntryDefaultsConfigEntry{Name: name}, nil
 	case LLMAgent:
 		return &LLMAgentConfigEntry{Name: name}, nil
 	case LLMAgentExternalServers:
@@ -1380,8 +1574,33 @@ func (r *ServiceConfigResponse) UnmarshalBinary(data []byte) error {
 }
 
 // ConfigEntryResponse returns a single ConfigEntry
+// ConfigEntryReplicationStatus is the status of the replication of config
+// entries from the primary datacenter, as seen by the leader of the
+// datacenter that was queried.
+type ConfigEntryReplicationStatus struct {
+	// Enabled is true in secondary datacenters, which replicate their config
+	// entries from the primary datacenter.
+	Enabled bool
+
+	// Running is true if the leader is currently replicating.
+	Running bool
+
+	// SourceDatacenter is the datacenter the config entries are replicated
+	// from.
+	SourceDatacenter string
+
+	// ReplicatedIndex is the index in the primary datacenter up to which the
+	// config entries were replicated.
+	ReplicatedIndex uint64
+}
+
 type ConfigEntryResponse struct {
 	Entry ConfigEntry
+
+	// PrimaryDatacenter is the primary datacenter of the servers that answered
+	// the query, which is where all config entries are written.
+	PrimaryDatacenter string
+
 	QueryMeta
 }
 
@@ -1408,6 +1627,10 @@ func (c *ConfigEntryResponse) MarshalBinary() (data []byte, err error) {
 		return nil, err
 	}
 
+	if err := enc.Encode(c.PrimaryDatacenter); err != nil {
+		return nil, err
+	}
+
 	return bs, nil
 }
 
@@ -1437,6 +1660,11 @@ func (c *ConfigEntryResponse) UnmarshalBinary(data []byte) error {
 		return err
 	}
 
+	// Older servers don't send the primary datacenter.
+	if err := dec.Decode(&c.PrimaryDatacenter); err != nil && err != io.EOF {
+		return err
+	}
+
 	return nil
 }
 
@@ -1472,6 +1700,12 @@ func validateConfigEntryMeta(meta map[string]string) error {
 
 type ConfigEntryDeleteResponse struct {
 	Deleted bool
+
+	// Entry holds the entry that was deleted, when there was one. For
+	// check-and-set deletions it is exactly the version that was deleted.
+	// It is wrapped in a ConfigEntryResponse so that it is encoded with its
+	// kind.
+	Entry *ConfigEntryResponse `json:",omitempty"`
 }
 
 func isValidConnectionBalance(s string) bool {
@@ -0,0 +1,239 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/dhiaayachi/consul/acl"
+)
+
+// ConfigEntryDefaultsConfigEntry holds a template of field values that newly
+// created config entries of another kind inherit. Name is the kind the
+// template applies to. Only top-level fields that the new entry leaves unset
+// are taken from the template; fields set explicitly always win.
+type ConfigEntryDefaultsConfigEntry struct {
+	Name string
+
+	// Defaults holds the template, in the same format as the body of a
+	// config entry of the kind named by Name.
+	Defaults map[string]interface{} `json:",omitempty"`
+
+	Meta               map[string]string `json:",omitempty"`
+	Hash               uint64            `json:",omitempty" hash:"ignore"`
+	acl.EnterpriseMeta `hcl:",squash" mapstructure:",squash"`
+	RaftIndex          `hash:"ignore"`
+}
+
+func (e *ConfigEntryDefaultsConfigEntry) SetHash(h uint64) {
+	e.Hash = h
+}
+
+func (e *ConfigEntryDefaultsConfigEntry) GetHash() uint64 {
+	return e.Hash
+}
+
+func (e *ConfigEntryDefaultsConfigEntry) GetKind() string {
+	return ConfigEntryDefaults
+}
+
+func (e *ConfigEntryDefaultsConfigEntry) GetName() string {
+	if e == nil {
+		return ""
+	}
+
+	return e.Name
+}
+
+func (e *ConfigEntryDefaultsConfigEntry) GetMeta() map[string]string {
+	if e == nil {
+		return nil
+	}
+	return e.Meta
+}
+
+func (e *ConfigEntryDefaultsConfigEntry) Normalize() error {
+	if e == nil {
+		return fmt.Errorf("config entry is nil")
+	}
+
+	e.EnterpriseMeta.Normalize()
+
+	h, err := HashConfigEntry(e)
+	if err != nil {
+		return err
+	}
+	e.Hash = h
+
+	return nil
+}
+
+func (e *ConfigEntryDefaultsConfigEntry) Validate() error {
+	if e == nil {
+		return fmt.Errorf("config entry is nil")
+	}
+
+	if err := validateConfigEntryMeta(e.Meta); err != nil {
+		return err
+	}
+
+	switch e.Name {
+	case "":
+		return fmt.Errorf("Name is required")
+	case ConfigEntryDefaults, BoundAPIGateway:
+		return fmt.Errorf("Name %q does not support defaults", e.Name)
+	}
+	if !isConfigEntryKind(e.Name) {
+		return fmt.Errorf("Name must be a config entry kind, got %q", e.Name)
+	}
+
+	if _, err := e.template(); err != nil {
+		return fmt.Errorf("Defaults: %w", err)
+	}
+
+	return nil
+}
+
+// ApplyTo sets every top-level field of entry that is unset to its value in
+// the template. A field is set if it is named in setFields or has a non-zero
+// value, so an explicit zero value is kept as long as it is listed. The entry
+// must be of the kind the defaults apply to.
+func (e *ConfigEntryDefaultsConfigEntry) ApplyTo(entry ConfigEntry, setFields []string) error {
+	if entry.GetKind() != e.Name {
+		return fmt.Errorf("defaults for %q cannot be applied to a %q config entry", e.Name, entry.GetKind())
+	}
+
+	tmpl, err := e.template()
+	if err != nil {
+		return fmt.Errorf("invalid defaults for %q: %w", e.Name, err)
+	}
+
+	set := make(map[string]struct{}, len(setFields))
+	for _, name := range setFields {
+		set[name] = struct{}{}
+	}
+
+	dst := reflect.ValueOf(entry).Elem()
+	src := reflect.ValueOf(tmpl).Elem()
+	if dst.Type() != src.Type() {
+		return fmt.Errorf("defaults for %q decoded to %T, not %T", e.Name, tmpl, entry)
+	}
+	for i := 0; i < dst.NumField(); i++ {
+		field := dst.Type().Field(i)
+		// Embedded fields carry the tenancy and Raft indexes of the entry.
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		switch field.Name {
+		case "Kind", "Name", "Hash":
+			continue
+		}
+		if _, ok := set[field.Name]; ok {
+			continue
+		}
+		if dst.Field(i).IsZero() {
+			dst.Field(i).Set(src.Field(i))
+		}
+	}
+	return nil
+}
+
+// ConfigEntryFields returns the names of the top-level fields of entry that
+// are present in raw, the decoded body entry was created from. Keys are
+// matched the same way the decoder matches them, so aliases and snake_case
+// keys are recognized.
+func ConfigEntryFields(entry ConfigEntry, raw map[string]interface{}) []string {
+	t := reflect.TypeOf(entry)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := patchFieldsForType(t)
+	var names []string
+	seen := make(map[string]struct{}, len(raw))
+	for k := range raw {
+		field, ok := fields[strings.ToLower(k)]
+		if !ok {
+			continue
+		}
+		if _, ok := seen[field.name]; ok {
+			continue
+		}
+		seen[field.name] = struct{}{}
+		names = append(names, field.name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// template decodes the defaults into a config entry of the kind they apply
+// to.
+func (e *ConfigEntryDefaultsConfigEntry) template() (ConfigEntry, error) {
+	raw := make(map[string]interface{}, len(e.Defaults)+1)
+	for k, v := range e.Defaults {
+		switch strings.ToLower(k) {
+		case "kind", "name", "namespace", "partition":
+			return nil, fmt.Errorf("%s cannot be set by defaults", k)
+		}
+		raw[k] = v
+	}
+	raw["Kind"] = e.Name
+	return DecodeConfigEntry(raw)
+}
+
+func isConfigEntryKind(kind string) bool {
+	for _, k := range AllConfigEntryKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *ConfigEntryDefaultsConfigEntry) CanRead(authz acl.Authorizer) error {
+	return nil
+}
+
+func (e *ConfigEntryDefaultsConfigEntry) CanWrite(authz acl.Authorizer) error {
+	var authzContext acl.AuthorizerContext
+	e.FillAuthzContext(&authzContext)
+	return authz.ToAllowAuthorizer().OperatorWriteAllowed(&authzContext)
+}
+
+func (e *ConfigEntryDefaultsConfigEntry) GetRaftIndex() *RaftIndex {
+	if e == nil {
+		return &RaftIndex{}
+	}
+
+	return &e.RaftIndex
+}
+
+func (e *ConfigEntryDefaultsConfigEntry) GetEnterpriseMeta() *acl.EnterpriseMeta {
+	if e == nil {
+		return nil
+	}
+
+	return &e.EnterpriseMeta
+}
+
+// MarshalJSON adds the Kind field so that the JSON can be decoded back into the
+// correct type.
+func (e *ConfigEntryDefaultsConfigEntry) MarshalJSON() ([]byte, error) {
+	type Alias ConfigEntryDefaultsConfigEntry
+	source := &struct {
+		Kind string
+		*Alias
+	}{
+		Kind:  ConfigEntryDefaults,
+		Alias: (*Alias)(e),
+	}
+	return json.Marshal(source)
+}
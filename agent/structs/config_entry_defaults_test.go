@@ -0,0 +1,42 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigEntryDefaults_ApplyTo(t *testing.T) {
+	defaults := &ConfigEntryDefaultsConfigEntry{
+		Name: ServiceDefaults,
+		Defaults: map[string]interface{}{
+			"Protocol":              "http",
+			"MaxInboundConnections": 100,
+		},
+	}
+
+	raw := map[string]interface{}{
+		"Kind":                    ServiceDefaults,
+		"Name":                    "web",
+		"max_inbound_connections": 0,
+	}
+	entry, err := DecodeConfigEntry(raw)
+	require.NoError(t, err)
+
+	setFields := ConfigEntryFields(entry, raw)
+	require.Equal(t, []string{"Kind", "MaxInboundConnections", "Name"}, setFields)
+
+	require.NoError(t, defaults.ApplyTo(entry, setFields))
+	svc := entry.(*ServiceConfigEntry)
+	require.Equal(t, "http", svc.Protocol)
+	require.Zero(t, svc.MaxInboundConnections)
+
+	// Without the set fields a zero value can't be told apart from an unset
+	// one.
+	svc = &ServiceConfigEntry{Kind: ServiceDefaults, Name: "web"}
+	require.NoError(t, defaults.ApplyTo(svc, nil))
+	require.Equal(t, 100, svc.MaxInboundConnections)
+}
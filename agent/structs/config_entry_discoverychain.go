@@ -1172,6 +1172,10 @@ func (e *ServiceResolverConfigEntry) Validate() error {
 				return fmt.Errorf("Bad Failover[%q]: %w", subset, err)
 			}
 
+			if err := f.validatePolicy(); err != nil {
+				return fmt.Errorf("Bad Failover[%q]: %w", subset, err)
+			}
+
 			if f.ServiceSubset != "" {
 				if f.Service == "" || f.Service == e.Name {
 					if !isSubset(f.ServiceSubset) {
@@ -1257,6 +1261,19 @@ func (e *ServiceResolverConfigEntry) Validate() error {
 			return fmt.Errorf("Bad LoadBalancer configuration. "+
 				"LeastRequestConfig specified for incompatible load balancing policy %q", lb.Policy)
 		}
+		if lb.SlowStartConfig != nil {
+			// An empty policy means Envoy's default, which is round robin.
+			if lb.Policy != "" && lb.Policy != LBPolicyRoundRobin && lb.Policy != LBPolicyLeastRequest {
+				return fmt.Errorf("Bad LoadBalancer configuration. "+
+					"SlowStartConfig specified for incompatible load balancing policy %q", lb.Policy)
+			}
+			if lb.SlowStartConfig.Window <= 0 {
+				return fmt.Errorf("Bad LoadBalancer SlowStartConfig: Window must be > 0")
+			}
+			if lb.SlowStartConfig.MinWeightPercent < 0 || lb.SlowStartConfig.MinWeightPercent > 100 {
+				return fmt.Errorf("Bad LoadBalancer SlowStartConfig: MinWeightPercent must be between 0 and 100")
+			}
+		}
 		if !lb.IsHashBased() && len(lb.HashPolicies) > 0 {
 			return fmt.Errorf("Bad LoadBalancer configuration: "+
 				"HashPolicies specified for non-hash-based Policy: %q", lb.Policy)
@@ -1517,6 +1534,30 @@ func (f *ServiceResolverFailover) isEmpty() bool {
 		f.SamenessGroup == ""
 }
 
+// validatePolicy rejects failover policies whose precedence would be
+// ambiguous given the destinations of the failover.
+func (f *ServiceResolverFailover) validatePolicy() error {
+	if f.Policy == nil {
+		return nil
+	}
+
+	if f.SamenessGroup != "" && len(f.Policy.Regions) > 0 {
+		return fmt.Errorf("SamenessGroup cannot be set with Policy.Regions")
+	}
+
+	if f.Policy.Mode == "order-by-locality" {
+		if len(f.Datacenters) > 0 {
+			return fmt.Errorf("Policy.Mode %q cannot be used with Datacenters", f.Policy.Mode)
+		}
+		for i, target := range f.Targets {
+			if target.Datacenter != "" {
+				return fmt.Errorf("Policy.Mode %q cannot be used with cross-datacenter Targets[%d]", f.Policy.Mode, i)
+			}
+		}
+	}
+	return nil
+}
+
 type ServiceResolverFailoverPolicy struct {
 	// Mode specifies the type of failover that will be performed. Valid values are
 	// "sequential", "" (equivalent to "sequential") and "order-by-locality".
@@ -1589,6 +1630,11 @@ type LoadBalancer struct {
 	// LeastRequestConfig contains configuration for the "least_request" policy type
 	LeastRequestConfig *LeastRequestConfig `json:",omitempty" alias:"least_request_config"`
 
+	// SlowStartConfig contains configuration for ramping up the traffic sent to
+	// new endpoints. Only supported by the "round_robin" and "least_request" policy
+	// types; an empty policy is treated as "round_robin".
+	SlowStartConfig *SlowStartConfig `json:",omitempty" alias:"slow_start_config"`
+
 	// HashPolicies is a list of hash policies to use for hashing load balancing algorithms.
 	// Hash policies are evaluated individually and combined such that identical lists
 	// result in the same hash.
@@ -1612,6 +1658,17 @@ type LeastRequestConfig struct {
 	ChoiceCount uint32 `json:",omitempty" alias:"choice_count"`
 }
 
+// SlowStartConfig contains configuration for ramping up the traffic sent to new
+// endpoints, so that instances which just became healthy are not overwhelmed.
+type SlowStartConfig struct {
+	// Window is how long a new endpoint takes to ramp up to its full weight.
+	Window time.Duration `json:",omitempty"`
+
+	// MinWeightPercent is the percentage of its full weight that a new endpoint
+	// starts with. Defaults to 10.
+	MinWeightPercent float64 `json:",omitempty" alias:"min_weight_percent"`
+}
+
 // HashPolicy defines which attributes will be hashed by hash-based LB algorithms
 type HashPolicy struct {
 	// Field is the attribute type to hash on.
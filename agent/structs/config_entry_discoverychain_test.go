@@ -1638,6 +1638,67 @@ func TestServiceResolverConfigEntry(t *testing.T) {
 	}
 }
 
+func TestServiceResolverFailover_validatePolicy(t *testing.T) {
+	cases := map[string]struct {
+		failover ServiceResolverFailover
+		err      string
+	}{
+		"no policy": {
+			failover: ServiceResolverFailover{SamenessGroup: "group"},
+		},
+		"regions without sameness group": {
+			failover: ServiceResolverFailover{
+				Targets: []ServiceResolverFailoverTarget{{Peer: "cluster-01"}},
+				Policy:  &ServiceResolverFailoverPolicy{Regions: []string{"us-east-1"}},
+			},
+		},
+		"regions with sameness group": {
+			failover: ServiceResolverFailover{
+				SamenessGroup: "group",
+				Policy:        &ServiceResolverFailoverPolicy{Regions: []string{"us-east-1"}},
+			},
+			err: "SamenessGroup cannot be set with Policy.Regions",
+		},
+		"order-by-locality with peers": {
+			failover: ServiceResolverFailover{
+				Targets: []ServiceResolverFailoverTarget{{Peer: "cluster-01"}, {Peer: "cluster-02"}},
+				Policy:  &ServiceResolverFailoverPolicy{Mode: "order-by-locality"},
+			},
+		},
+		"order-by-locality with datacenters": {
+			failover: ServiceResolverFailover{
+				Datacenters: []string{"dc2"},
+				Policy:      &ServiceResolverFailoverPolicy{Mode: "order-by-locality"},
+			},
+			err: `Policy.Mode "order-by-locality" cannot be used with Datacenters`,
+		},
+		"order-by-locality with cross-datacenter target": {
+			failover: ServiceResolverFailover{
+				Targets: []ServiceResolverFailoverTarget{{Peer: "cluster-01"}, {Datacenter: "dc2"}},
+				Policy:  &ServiceResolverFailoverPolicy{Mode: "order-by-locality"},
+			},
+			err: `Policy.Mode "order-by-locality" cannot be used with cross-datacenter Targets[1]`,
+		},
+		"sequential with datacenters": {
+			failover: ServiceResolverFailover{
+				Datacenters: []string{"dc2"},
+				Policy:      &ServiceResolverFailoverPolicy{Mode: "sequential"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.failover.validatePolicy()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
 func TestServiceResolverConfigEntry_LoadBalancer(t *testing.T) {
 
 	type testcase struct {
@@ -1717,6 +1778,63 @@ func TestServiceResolverConfigEntry_LoadBalancer(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "bad policy for slow start config",
+			entry: &ServiceResolverConfigEntry{
+				Kind: ServiceResolver,
+				Name: "test",
+				LoadBalancer: &LoadBalancer{
+					Policy:          LBPolicyRingHash,
+					SlowStartConfig: &SlowStartConfig{Window: 30 * time.Second},
+				},
+			},
+			validateErr: `SlowStartConfig specified for incompatible load balancing policy "ring_hash"`,
+		},
+		{
+			name: "slow start config without window",
+			entry: &ServiceResolverConfigEntry{
+				Kind: ServiceResolver,
+				Name: "test",
+				LoadBalancer: &LoadBalancer{
+					Policy:          LBPolicyRoundRobin,
+					SlowStartConfig: &SlowStartConfig{MinWeightPercent: 20},
+				},
+			},
+			validateErr: `Window must be > 0`,
+		},
+		{
+			name: "slow start config with bad min weight percent",
+			entry: &ServiceResolverConfigEntry{
+				Kind: ServiceResolver,
+				Name: "test",
+				LoadBalancer: &LoadBalancer{
+					Policy:          LBPolicyLeastRequest,
+					SlowStartConfig: &SlowStartConfig{Window: 30 * time.Second, MinWeightPercent: 101},
+				},
+			},
+			validateErr: `MinWeightPercent must be between 0 and 100`,
+		},
+		{
+			name: "good policy for slow start config",
+			entry: &ServiceResolverConfigEntry{
+				Kind: ServiceResolver,
+				Name: "test",
+				LoadBalancer: &LoadBalancer{
+					Policy:          LBPolicyRoundRobin,
+					SlowStartConfig: &SlowStartConfig{Window: 30 * time.Second, MinWeightPercent: 20},
+				},
+			},
+		},
+		{
+			name: "empty policy for slow start config",
+			entry: &ServiceResolverConfigEntry{
+				Kind: ServiceResolver,
+				Name: "test",
+				LoadBalancer: &LoadBalancer{
+					SlowStartConfig: &SlowStartConfig{Window: 30 * time.Second},
+				},
+			},
+		},
 		{
 			name: "good policy for least request config",
 			entry: &ServiceResolverConfigEntry{
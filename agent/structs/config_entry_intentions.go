@@ -5,6 +5,7 @@ package structs
 
 import (
 	"fmt"
+	"reflect"
 	"sort"
 	"strings"
 	"time"
@@ -151,6 +152,8 @@ func (e *ServiceIntentionsConfigEntry) ToIntention(src *SourceIntention) *Intent
 		JWT:                  e.JWT,
 		Action:               src.Action,
 		Permissions:          src.Permissions,
+		NotBefore:            src.NotBefore,
+		NotAfter:             src.NotAfter,
 		Meta:                 meta,
 		Precedence:           src.Precedence,
 		DestinationPartition: e.PartitionOrEmpty(),
@@ -287,6 +290,12 @@ type SourceIntention struct {
 
 	// SamenessGroup is the name of the sameness group, if applicable.
 	SamenessGroup string `json:",omitempty" alias:"sameness_group"`
+
+	// NotBefore and NotAfter optionally bound the time window in which this
+	// source intention is in effect. Outside of the window the source is
+	// treated as if it were not present.
+	NotBefore *time.Time `json:",omitempty" alias:"not_before"`
+	NotAfter  *time.Time `json:",omitempty" alias:"not_after"`
 }
 
 type IntentionJWTRequirement struct {
@@ -467,6 +476,58 @@ func (x *SourceIntention) Clone() *SourceIntention {
 	return &x2
 }
 
+// IntentionSourcesDiff describes how the sources of a service-intentions
+// config entry change when a new version of the entry replaces it. Added and
+// Changed hold the proposed sources, Removed holds the existing ones.
+type IntentionSourcesDiff struct {
+	Added   []*SourceIntention
+	Removed []*SourceIntention
+	Changed []*SourceIntention
+}
+
+// DiffSources compares the sources of e with those of prev, which is nil when
+// the entry does not exist yet. Sources are matched by name, peer and
+// sameness group.
+func (e *ServiceIntentionsConfigEntry) DiffSources(prev *ServiceIntentionsConfigEntry) *IntentionSourcesDiff {
+	type sourceKey struct {
+		ServiceName   ServiceName
+		Peer          string
+		SamenessGroup string
+	}
+	keyOf := func(src *SourceIntention) sourceKey {
+		return sourceKey{ServiceName: src.SourceServiceName(), Peer: src.Peer, SamenessGroup: src.SamenessGroup}
+	}
+
+	prevSources := make(map[sourceKey]*SourceIntention)
+	if prev != nil {
+		for _, src := range prev.Sources {
+			prevSources[keyOf(src)] = src
+		}
+	}
+
+	diff := &IntentionSourcesDiff{}
+	seen := make(map[sourceKey]struct{})
+	for _, src := range e.Sources {
+		key := keyOf(src)
+		seen[key] = struct{}{}
+		prevSrc, ok := prevSources[key]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, src)
+		case !reflect.DeepEqual(src, prevSrc):
+			diff.Changed = append(diff.Changed, src)
+		}
+	}
+	if prev != nil {
+		for _, src := range prev.Sources {
+			if _, ok := seen[keyOf(src)]; !ok {
+				diff.Removed = append(diff.Removed, src)
+			}
+		}
+	}
+	return diff
+}
+
 func (e *ServiceIntentionsConfigEntry) UpdateOver(rawPrev ConfigEntry) error {
 	if rawPrev == nil {
 		return nil
@@ -733,6 +794,10 @@ func (e *ServiceIntentionsConfigEntry) validate(legacyWrite bool) error {
 			return fmt.Errorf("Sources[%d].SamenessGroup: cannot set SamenessGroup and Peer at the same time", i)
 		}
 
+		if src.NotBefore != nil && src.NotAfter != nil && !src.NotAfter.After(*src.NotBefore) {
+			return fmt.Errorf("Sources[%d].NotAfter must be after NotBefore", i)
+		}
+
 		// Length of opaque values
 		if len(src.Description) > metaValueMaxLength {
 			return fmt.Errorf(
@@ -748,6 +813,10 @@ func (e *ServiceIntentionsConfigEntry) validate(legacyWrite bool) error {
 				return fmt.Errorf("Sources[%d].SamenessGroup cannot be set by legacy intentions", i)
 			}
 
+			if src.NotBefore != nil || src.NotAfter != nil {
+				return fmt.Errorf("Sources[%d].NotBefore and NotAfter cannot be set by legacy intentions", i)
+			}
+
 			if len(src.LegacyMeta) > metaMaxKeyPairs {
 				return fmt.Errorf(
 					"Sources[%d].Meta exceeds maximum element count %d", i, metaMaxKeyPairs)
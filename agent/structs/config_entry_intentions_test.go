@@ -131,6 +131,21 @@ func TestServiceIntentionsConfigEntry(t *testing.T) {
 			},
 			validateErr: `Sources[0].Description exceeds maximum length 512`,
 		},
+		"not after before not before": {
+			entry: &ServiceIntentionsConfigEntry{
+				Kind: ServiceIntentions,
+				Name: "test",
+				Sources: []*SourceIntention{
+					{
+						Name:      "foo",
+						Action:    IntentionActionAllow,
+						NotBefore: &testTimeB,
+						NotAfter:  &testTimeA,
+					},
+				},
+			},
+			validateErr: `Sources[0].NotAfter must be after NotBefore`,
+		},
 		"config entry meta not allowed on legacy writes": {
 			legacy: true,
 			entry: &ServiceIntentionsConfigEntry{
@@ -35,6 +35,16 @@ type MeshConfigEntry struct {
 
 	Peering *PeeringMeshConfig `json:",omitempty"`
 
+	// AccessLogs configures access logging for the listeners of all proxies and
+	// gateways in the mesh that don't configure access logs themselves, for
+	// example through proxy-defaults.
+	AccessLogs *AccessLogsConfig `json:",omitempty" alias:"access_logs"`
+
+	// PassiveHealthCheck configures outlier detection for the upstream clusters
+	// of all proxies in the mesh. Values set for an upstream, for example through
+	// the UpstreamConfig of service-defaults, take precedence.
+	PassiveHealthCheck *PassiveHealthCheck `json:",omitempty" alias:"passive_health_check"`
+
 	Meta               map[string]string `json:",omitempty"`
 	Hash               uint64            `json:",omitempty" hash:"ignore"`
 	acl.EnterpriseMeta `hcl:",squash" mapstructure:",squash"`
@@ -255,6 +265,18 @@ func (e *MeshConfigEntry) Validate() error {
 		return fmt.Errorf("error in HTTP incoming request normalization configuration: %v", err)
 	}
 
+	if e.AccessLogs != nil {
+		if err := e.AccessLogs.Validate(); err != nil {
+			return fmt.Errorf("error in access logs configuration: %v", err)
+		}
+	}
+
+	if e.PassiveHealthCheck != nil {
+		if err := e.PassiveHealthCheck.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return e.validateEnterpriseMeta()
 }
 
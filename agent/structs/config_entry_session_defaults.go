@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/dhiaayachi/consul/acl"
+)
+
+// SessionDefaultsConfigEntry holds the defaults applied to sessions created
+// in the namespace the entry belongs to.
+type SessionDefaultsConfigEntry struct {
+	// Behavior is the invalidation behavior given to sessions created without
+	// one. When empty, sessions default to SessionKeysRelease.
+	Behavior SessionBehavior `json:",omitempty"`
+
+	Meta               map[string]string `json:",omitempty"`
+	Hash               uint64            `json:",omitempty" hash:"ignore"`
+	acl.EnterpriseMeta `hcl:",squash" mapstructure:",squash"`
+	RaftIndex          `hash:"ignore"`
+}
+
+func (e *SessionDefaultsConfigEntry) SetHash(h uint64) {
+	e.Hash = h
+}
+
+func (e *SessionDefaultsConfigEntry) GetHash() uint64 {
+	return e.Hash
+}
+
+func (e *SessionDefaultsConfigEntry) GetKind() string {
+	return SessionDefaults
+}
+
+func (e *SessionDefaultsConfigEntry) GetName() string {
+	if e == nil {
+		return ""
+	}
+
+	return SessionDefaultsName
+}
+
+func (e *SessionDefaultsConfigEntry) GetMeta() map[string]string {
+	if e == nil {
+		return nil
+	}
+	return e.Meta
+}
+
+func (e *SessionDefaultsConfigEntry) Normalize() error {
+	if e == nil {
+		return fmt.Errorf("config entry is nil")
+	}
+
+	e.EnterpriseMeta.Normalize()
+
+	h, err := HashConfigEntry(e)
+	if err != nil {
+		return err
+	}
+	e.Hash = h
+
+	return nil
+}
+
+func (e *SessionDefaultsConfigEntry) Validate() error {
+	if e == nil {
+		return fmt.Errorf("config entry is nil")
+	}
+
+	if err := validateConfigEntryMeta(e.Meta); err != nil {
+		return err
+	}
+
+	switch e.Behavior {
+	case "", SessionKeysRelease, SessionKeysDelete:
+	default:
+		return fmt.Errorf("Invalid Behavior setting '%s'", e.Behavior)
+	}
+
+	return nil
+}
+
+func (e *SessionDefaultsConfigEntry) CanRead(authz acl.Authorizer) error {
+	return nil
+}
+
+func (e *SessionDefaultsConfigEntry) CanWrite(authz acl.Authorizer) error {
+	var authzContext acl.AuthorizerContext
+	e.FillAuthzContext(&authzContext)
+	return authz.ToAllowAuthorizer().OperatorWriteAllowed(&authzContext)
+}
+
+func (e *SessionDefaultsConfigEntry) GetRaftIndex() *RaftIndex {
+	if e == nil {
+		return &RaftIndex{}
+	}
+
+	return &e.RaftIndex
+}
+
+func (e *SessionDefaultsConfigEntry) GetEnterpriseMeta() *acl.EnterpriseMeta {
+	if e == nil {
+		return nil
+	}
+
+	return &e.EnterpriseMeta
+}
+
+// MarshalJSON adds the Kind field so that the JSON can be decoded back into the
+// correct type.
+func (e *SessionDefaultsConfigEntry) MarshalJSON() ([]byte, error) {
+	type Alias SessionDefaultsConfigEntry
+	source := &struct {
+		Kind string
+		*Alias
+	}{
+		Kind:  SessionDefaults,
+		Alias: (*Alias)(e),
+	}
+	return json.Marshal(source)
+}
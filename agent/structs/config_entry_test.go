@@ -1588,6 +1588,42 @@ func TestDecodeConfigEntry(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "service-resolver: envoy slow start",
+			snake: `
+				kind = "service-resolver"
+				name = "main"
+				load_balancer = {
+					policy = "round_robin"
+					slow_start_config = {
+						window = "30s"
+						min_weight_percent = 20
+					}
+				}
+			`,
+			camel: `
+				Kind = "service-resolver"
+				Name = "main"
+				LoadBalancer = {
+					Policy = "round_robin"
+					SlowStartConfig = {
+						Window = "30s"
+						MinWeightPercent = 20
+					}
+				}
+			`,
+			expect: &ServiceResolverConfigEntry{
+				Kind: "service-resolver",
+				Name: "main",
+				LoadBalancer: &LoadBalancer{
+					Policy: LBPolicyRoundRobin,
+					SlowStartConfig: &SlowStartConfig{
+						Window:           30 * time.Second,
+						MinWeightPercent: 20,
+					},
+				},
+			},
+		},
 		{
 			// TODO(rb): test SDS stuff here in both places (global/service)
 			name: "ingress-gateway: kitchen sink",
@@ -2205,6 +2241,13 @@ func TestDecodeConfigEntry(t *testing.T) {
 				peering {
 					peer_through_mesh_gateways = true
 				}
+				passive_health_check {
+					interval = "2s"
+					max_failures = 3
+					enforcing_consecutive_5xx = 4
+					max_ejection_percent = 5
+					base_ejection_time = "6s"
+				}
 			`,
 			camel: `
 				Kind = "mesh"
@@ -2249,6 +2292,13 @@ func TestDecodeConfigEntry(t *testing.T) {
 				Peering {
 					PeerThroughMeshGateways = true
 				}
+				PassiveHealthCheck {
+					Interval = "2s"
+					MaxFailures = 3
+					EnforcingConsecutive5xx = 4
+					MaxEjectionPercent = 5
+					BaseEjectionTime = "6s"
+				}
 			`,
 			expect: &MeshConfigEntry{
 				Meta: map[string]string{
@@ -2292,6 +2342,13 @@ func TestDecodeConfigEntry(t *testing.T) {
 				Peering: &PeeringMeshConfig{
 					PeerThroughMeshGateways: true,
 				},
+				PassiveHealthCheck: &PassiveHealthCheck{
+					Interval:                2 * time.Second,
+					MaxFailures:             3,
+					EnforcingConsecutive5xx: uintPointer(4),
+					MaxEjectionPercent:      uintPointer(5),
+					BaseEjectionTime:        durationPointer(6 * time.Second),
+				},
 			},
 		},
 		{
@@ -2534,6 +2591,74 @@ func TestDecodeConfigEntry(t *testing.T) {
 	}
 }
 
+func TestMergePatchConfigEntry(t *testing.T) {
+	entry := &ServiceConfigEntry{
+		Kind:        ServiceDefaults,
+		Name:        "web",
+		Protocol:    "tcp",
+		ExternalSNI: "web.example.com",
+		Meta:        map[string]string{"a": "1", "b": "2"},
+		RaftIndex:   RaftIndex{CreateIndex: 5, ModifyIndex: 7},
+	}
+
+	patched, err := MergePatchConfigEntry(entry, []byte(`{
+		"Protocol": "http",
+		"ExternalSNI": null,
+		"Meta": {"b": null, "c": "3"}
+	}`))
+	require.NoError(t, err)
+	require.Equal(t, &ServiceConfigEntry{
+		Kind:     ServiceDefaults,
+		Name:     "web",
+		Protocol: "http",
+		Meta:     map[string]string{"a": "1", "c": "3"},
+	}, patched)
+
+	// The original entry is left alone.
+	require.Equal(t, "tcp", entry.Protocol)
+	require.Len(t, entry.Meta, 2)
+
+	_, err = MergePatchConfigEntry(entry, []byte(`{"Kind": "proxy-defaults"}`))
+	require.ErrorContains(t, err, "cannot be changed")
+
+	_, err = MergePatchConfigEntry(entry, []byte(`"http"`))
+	require.ErrorContains(t, err, "must be a JSON object")
+
+	_, err = MergePatchConfigEntry(entry, []byte(`{"Bogus": true}`))
+	require.ErrorContains(t, err, `invalid config key "Bogus"`)
+
+	// snake_case and lower case keys replace the values of the fields they
+	// refer to, and nested objects are merged.
+	entry.TransparentProxy = TransparentProxyConfig{OutboundListenerPort: 15001, DialedDirectly: true}
+	entry.MaxInboundConnections = 5
+	patched, err = MergePatchConfigEntry(entry, []byte(`{
+		"protocol": "http",
+		"external_sni": null,
+		"max_inbound_connections": 10,
+		"transparent_proxy": {"dialed_directly": false}
+	}`))
+	require.NoError(t, err)
+	require.Equal(t, &ServiceConfigEntry{
+		Kind:                  ServiceDefaults,
+		Name:                  "web",
+		Protocol:              "http",
+		TransparentProxy:      TransparentProxyConfig{OutboundListenerPort: 15001},
+		MaxInboundConnections: 10,
+		Meta:                  map[string]string{"a": "1", "b": "2"},
+	}, patched)
+
+	// The canonical key wins over an alias, like when decoding.
+	patched, err = MergePatchConfigEntry(entry, []byte(`{"ExternalSNI": "a.example.com", "external_sni": "b.example.com"}`))
+	require.NoError(t, err)
+	require.Equal(t, "a.example.com", patched.(*ServiceConfigEntry).ExternalSNI)
+
+	_, err = MergePatchConfigEntry(entry, []byte(`{"kind": "proxy-defaults"}`))
+	require.ErrorContains(t, err, "cannot be changed")
+
+	_, err = MergePatchConfigEntry(entry, []byte(`{"name": "api"}`))
+	require.ErrorContains(t, err, "cannot be changed")
+}
+
 func TestServiceConfigRequest(t *testing.T) {
 
 	tests := []struct {
@@ -2695,6 +2820,13 @@ func TestConfigEntryResponseMarshalling(t *testing.T) {
 				// Connect:  ConnectConfiguration{SideCarProxy: true},
 			},
 		},
+		"primary datacenter": {
+			Entry: &ServiceConfigEntry{
+				Kind: ServiceDefaults,
+				Name: "foo",
+			},
+			PrimaryDatacenter: "dc1",
+		},
 	}
 
 	for name, tcase := range cases {
@@ -2712,6 +2844,21 @@ func TestConfigEntryResponseMarshalling(t *testing.T) {
 			require.Equal(t, tcase, resp)
 		})
 	}
+
+	t.Run("without primary datacenter", func(t *testing.T) {
+		// Older servers end the response after the query meta.
+		bs := make([]byte, 128)
+		enc := codec.NewEncoderBytes(&bs, MsgpackHandle)
+		require.NoError(t, enc.Encode(ServiceDefaults))
+		require.NoError(t, enc.Encode(&ServiceConfigEntry{Kind: ServiceDefaults, Name: "foo"}))
+		require.NoError(t, enc.Encode(QueryMeta{Index: 5}))
+
+		var resp ConfigEntryResponse
+		require.NoError(t, resp.UnmarshalBinary(bs))
+		require.Equal(t, "foo", resp.Entry.GetName())
+		require.Equal(t, uint64(5), resp.Index)
+		require.Empty(t, resp.PrimaryDatacenter)
+	})
 }
 
 func TestPassiveHealthCheck_Validate(t *testing.T) {
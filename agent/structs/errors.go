@@ -25,6 +25,8 @@ const (
 	errStateReadOnly                         = "CA Provider State is read-only"
 	errSamenessGroupNotFound                 = "Sameness Group not found"
 	errSamenessGroupMustBeDefaultForFailover = "Sameness Group must have DefaultForFailover set to true in order to use this endpoint"
+	errConfigEntryQuotaExceeded              = "Config entry quota exceeded"
+	errConfigEntryNotFound                   = "Config entry not found"
 )
 
 var (
@@ -43,6 +45,8 @@ var (
 	ErrStateReadOnly                         = errors.New(errStateReadOnly)
 	ErrSamenessGroupNotFound                 = errors.New(errSamenessGroupNotFound)
 	ErrSamenessGroupMustBeDefaultForFailover = errors.New(errSamenessGroupMustBeDefaultForFailover)
+	ErrConfigEntryQuotaExceeded              = errors.New(errConfigEntryQuotaExceeded)
+	ErrConfigEntryNotFound                   = errors.New(errConfigEntryNotFound)
 )
 
 func IsErrNoDCPath(err error) bool {
@@ -68,3 +72,11 @@ func IsErrSamenessGroupNotFound(err error) bool {
 func IsErrSamenessGroupMustBeDefaultForFailover(err error) bool {
 	return err != nil && strings.Contains(err.Error(), errSamenessGroupMustBeDefaultForFailover)
 }
+
+func IsErrConfigEntryQuotaExceeded(err error) bool {
+	return err != nil && strings.Contains(err.Error(), errConfigEntryQuotaExceeded)
+}
+
+func IsErrConfigEntryNotFound(err error) bool {
+	return err != nil && strings.Contains(err.Error(), errConfigEntryNotFound)
+}
@@ -89,6 +89,12 @@ type Intention struct {
 	// JWT specifies JWT authn that applies to incoming requests.
 	JWT *IntentionJWTRequirement `bexpr:"-" json:",omitempty"`
 
+	// NotBefore and NotAfter optionally bound the time window in which the
+	// intention is in effect. Outside of the window the intention is treated
+	// as if it did not exist.
+	NotBefore *time.Time `bexpr:"-" json:",omitempty"`
+	NotAfter  *time.Time `bexpr:"-" json:",omitempty"`
+
 	// DefaultAddr is not used.
 	// Deprecated: DefaultAddr is not used and may be removed in a future version.
 	DefaultAddr string `bexpr:"-" codec:",omitempty" json:",omitempty"`
@@ -229,6 +235,18 @@ func (x *Intention) SetHash() {
 //
 // Deprecated: this is only used for legacy intention CRUD
 func (x *Intention) Validate() error {
+	return x.validate(false)
+}
+
+// ValidateAllowL7 is like Validate but permits Permissions to be set, for
+// legacy creates that opted in with IntentionRequest.AllowL7.
+//
+// Deprecated: this is only used for legacy intention CRUD
+func (x *Intention) ValidateAllowL7() error {
+	return x.validate(true)
+}
+
+func (x *Intention) validate(allowL7 bool) error {
 	var result error
 
 	// Empty values
@@ -301,16 +319,23 @@ func (x *Intention) Validate() error {
 		}
 	}
 
-	switch x.Action {
-	case IntentionActionAllow, IntentionActionDeny:
-	default:
-		result = multierror.Append(result, fmt.Errorf(
-			"Action must be set to 'allow' or 'deny'"))
-	}
+	if allowL7 && len(x.Permissions) > 0 {
+		if x.Action != "" {
+			result = multierror.Append(result, fmt.Errorf(
+				"Action must be omitted if Permissions are specified"))
+		}
+	} else {
+		switch x.Action {
+		case IntentionActionAllow, IntentionActionDeny:
+		default:
+			result = multierror.Append(result, fmt.Errorf(
+				"Action must be set to 'allow' or 'deny'"))
+		}
 
-	if len(x.Permissions) > 0 {
-		result = multierror.Append(result, fmt.Errorf(
-			"Permissions must not be set when using the legacy APIs"))
+		if len(x.Permissions) > 0 {
+			result = multierror.Append(result, fmt.Errorf(
+				"Permissions must not be set when using the legacy APIs"))
+		}
 	}
 
 	switch x.SourceType {
@@ -500,6 +525,8 @@ func (x *Intention) ToSourceIntention(legacy bool) *SourceIntention {
 		LegacyMeta:       x.Meta,
 		LegacyCreateTime: &ct,
 		LegacyUpdateTime: &ut,
+		NotBefore:        x.NotBefore,
+		NotAfter:         x.NotAfter,
 	}
 	if !legacy {
 		src.Permissions = x.Permissions
@@ -507,6 +534,18 @@ func (x *Intention) ToSourceIntention(legacy bool) *SourceIntention {
 	return src
 }
 
+// ActiveAt returns whether the intention is in effect at the given time
+// according to its NotBefore and NotAfter bounds.
+func (x *Intention) ActiveAt(t time.Time) bool {
+	if x.NotBefore != nil && t.Before(*x.NotBefore) {
+		return false
+	}
+	if x.NotAfter != nil && !t.Before(*x.NotAfter) {
+		return false
+	}
+	return true
+}
+
 // IntentionAction is the action that the intention represents. This
 // can be "allow" or "deny".
 type IntentionAction string
@@ -559,6 +598,76 @@ type IndexedIntentionMatches struct {
 	QueryMeta
 }
 
+// IndexedIntentionPrecedence represents the rules evaluated for a destination,
+// ordered from highest to lowest precedence. The last entry is always the
+// default policy that applies when no intention matches.
+type IndexedIntentionPrecedence struct {
+	Rules []*IntentionPrecedenceRule
+	QueryMeta
+}
+
+// IntentionPrecedenceRule is a single step of the evaluation order for a
+// destination. It is either a matching intention or, for the final step, the
+// default intention policy.
+type IntentionPrecedenceRule struct {
+	// Intention is the intention evaluated at this step. It is nil for the
+	// default policy.
+	Intention *Intention `json:",omitempty"`
+
+	// Precedence is the precedence of the intention, or zero for the default
+	// policy.
+	Precedence int
+
+	// Action is the action taken when this rule matches. It is empty for
+	// intentions with L7 permissions since those have no single action.
+	Action IntentionAction `json:",omitempty"`
+
+	// Default is true for the synthetic entry representing the default
+	// intention policy.
+	Default bool `json:",omitempty"`
+}
+
+// IndexedIntentionTopology is the graph of services a source service can reach
+// once both intentions and discovery chain routing are applied. Upstreams are
+// ordered by partition, namespace and name.
+type IndexedIntentionTopology struct {
+	Upstreams []*IntentionTopologyUpstream
+	QueryMeta
+}
+
+// IntentionTopologyUpstream is a service that intentions allow the source to
+// connect to, along with the targets its discovery chain resolves to.
+type IntentionTopologyUpstream struct {
+	Name      string
+	Namespace string `json:",omitempty"`
+	Partition string `json:",omitempty"`
+
+	// Protocol is the protocol of the upstream's discovery chain.
+	Protocol string
+
+	// Targets are the targets that requests to the upstream may be routed,
+	// split or failed over to, ordered by ID.
+	Targets []*IntentionTopologyTarget
+}
+
+// IntentionTopologyTarget is a single target of an upstream's discovery
+// chain.
+type IntentionTopologyTarget struct {
+	ID            string
+	Service       string
+	ServiceSubset string `json:",omitempty"`
+	Namespace     string `json:",omitempty"`
+	Partition     string `json:",omitempty"`
+	Datacenter    string `json:",omitempty"`
+	Peer          string `json:",omitempty"`
+
+	// Allowed is whether intentions allow the source to connect to the
+	// service of the target. Routing to a different service through the
+	// upstream only succeeds if this is also true. As when inferring the
+	// upstreams, intentions with L7 permissions count as allowing it.
+	Allowed bool
+}
+
 // IntentionOp is the operation for a request related to intentions.
 type IntentionOp string
 
@@ -568,6 +677,7 @@ const (
 	IntentionOpDelete    IntentionOp = "delete"
 	IntentionOpDeleteAll IntentionOp = "delete-all" // NOTE: this is only accepted when it comes from the leader, RPCs will reject this
 	IntentionOpUpsert    IntentionOp = "upsert"     // config-entry only
+	IntentionOpImport    IntentionOp = "import"     // RPC only, stored as a create or upsert
 )
 
 // IntentionRequest is used to create, update, and delete intentions.
@@ -583,6 +693,12 @@ type IntentionRequest struct {
 	// This is mutually exclusive with the Mutation field.
 	Intention *Intention
 
+	// AllowL7 permits a create operation to set Permissions on the
+	// intention. Such an intention is written as a source of the
+	// destination's service-intentions config entry rather than as a legacy
+	// intention, so it is not assigned an ID.
+	AllowL7 bool
+
 	// Mutation is a change to make to an Intention.
 	//
 	// This is mutually exclusive with the Intention field.
@@ -640,6 +756,10 @@ type IntentionQueryRequest struct {
 	// unique name instead of its ID.
 	Exact *IntentionQueryExact
 
+	// Precedence is non-nil if we're listing the rules evaluated for a
+	// destination, in the order they are evaluated.
+	Precedence *IntentionMatchEntry
+
 	// Options for queries
 	QueryOptions
 }
@@ -663,12 +783,14 @@ func (q *IntentionQueryRequest) CacheInfo() cache.RequestInfo {
 		Match       *IntentionQueryMatch
 		Check       *IntentionQueryCheck
 		Exact       *IntentionQueryExact
+		Precedence  *IntentionMatchEntry
 		Filter      string
 	}{
 		IntentionID: q.IntentionID,
 		Check:       q.Check,
 		Match:       q.Match,
 		Exact:       q.Exact,
+		Precedence:  q.Precedence,
 		Filter:      q.QueryOptions.Filter,
 	}, nil)
 	if err == nil {
@@ -708,6 +830,10 @@ type IntentionQueryCheck struct {
 	SourcePartition      string `json:",omitempty"`
 	DestinationPartition string `json:",omitempty"`
 
+	// SourcePeer is the cluster peer the source is imported from. It is
+	// empty for sources in the local cluster.
+	SourcePeer string `json:",omitempty"`
+
 	// SourceType is the type of the value for the source.
 	SourceType IntentionSourceType
 }
@@ -722,6 +848,72 @@ func (q *IntentionQueryCheck) GetACLPrefix() (string, bool) {
 // IntentionQueryCheckResponse is the response for a test request.
 type IntentionQueryCheckResponse struct {
 	Allowed bool
+
+	// Error is set instead of Allowed when a check within a batch could
+	// not be evaluated, for example because the token is not allowed to
+	// read the destination service. It is never set for single checks.
+	Error string `json:",omitempty"`
+}
+
+// IntentionQueryCheckBatchRequest is used to test many source/destination
+// pairs in a single request.
+type IntentionQueryCheckBatchRequest struct {
+	// Datacenter is the target this request is intended for.
+	Datacenter string
+
+	// Checks are the source/destination pairs to test.
+	Checks []*IntentionQueryCheck
+
+	// Options for queries
+	QueryOptions
+}
+
+// RequestDatacenter returns the datacenter for a given request.
+func (q *IntentionQueryCheckBatchRequest) RequestDatacenter() string {
+	return q.Datacenter
+}
+
+// IntentionQueryCheckBatchResponse is the response for a batch test request.
+// Results is parallel to the Checks of the request.
+type IntentionQueryCheckBatchResponse struct {
+	Results []*IntentionQueryCheckResponse
+}
+
+// IntentionSimulateRequest is used to find the pairs of services whose
+// intention decision would change if a proposed service-intentions config
+// entry was applied.
+type IntentionSimulateRequest struct {
+	// Datacenter is the target this request is intended for.
+	Datacenter string
+
+	// Entry is the proposed config entry. It replaces the existing entry
+	// for the same destination, if any.
+	Entry *ServiceIntentionsConfigEntry
+
+	// Options for queries
+	QueryOptions
+}
+
+// RequestDatacenter returns the datacenter for a given request.
+func (q *IntentionSimulateRequest) RequestDatacenter() string {
+	return q.Datacenter
+}
+
+// IntentionSimulateResponse is the response for a simulate request.
+type IntentionSimulateResponse struct {
+	// NewlyAllowed are the pairs that are denied today and would be allowed
+	// by the proposed entry.
+	NewlyAllowed []IntentionSimulatePair
+
+	// NewlyDenied are the pairs that are allowed today and would be denied
+	// by the proposed entry.
+	NewlyDenied []IntentionSimulatePair
+}
+
+// IntentionSimulatePair is a source/destination pair of catalog services.
+type IntentionSimulatePair struct {
+	Source      ServiceName
+	Destination ServiceName
 }
 
 // IntentionDecisionSummary contains a summary of a set of intentions between two services
@@ -775,6 +967,8 @@ func (q *IntentionQueryExact) Validate() error {
 type IntentionListRequest struct {
 	Datacenter         string
 	Legacy             bool `json:"-"`
+	Unused             bool // only return intentions without recorded matches
+	Sorted             bool // return intentions in IntentionPrecedenceSorter order
 	acl.EnterpriseMeta `hcl:",squash" mapstructure:",squash"`
 	QueryOptions
 }
@@ -786,6 +980,46 @@ func (r *IntentionListRequest) RequestDatacenter() string {
 // SimplifiedIntentions contains expanded sameness groups.
 type SimplifiedIntentions Intentions
 
+// Active returns the intentions that are in effect at the given time. The
+// receiver is returned as-is if none of them are outside of their window.
+func (s SimplifiedIntentions) Active(t time.Time) SimplifiedIntentions {
+	for i, ixn := range s {
+		if ixn.ActiveAt(t) {
+			continue
+		}
+		out := make(SimplifiedIntentions, i, len(s))
+		copy(out, s[:i])
+		for _, ixn := range s[i+1:] {
+			if ixn.ActiveAt(t) {
+				out = append(out, ixn)
+			}
+		}
+		return out
+	}
+	return s
+}
+
+// NextActiveChange returns the earliest NotBefore or NotAfter bound after the
+// given time, which is when the result of Active next changes. It returns
+// false if none of the intentions have a bound after t.
+func (s SimplifiedIntentions) NextActiveChange(t time.Time) (time.Time, bool) {
+	var (
+		next  time.Time
+		found bool
+	)
+	for _, ixn := range s {
+		for _, bound := range []*time.Time{ixn.NotBefore, ixn.NotAfter} {
+			if bound == nil || !bound.After(t) {
+				continue
+			}
+			if !found || bound.Before(next) {
+				next, found = *bound, true
+			}
+		}
+	}
+	return next, found
+}
+
 // IntentionPrecedenceSorter takes a list of intentions and sorts them
 // based on the match precedence rules for intentions. The intentions
 // closer to the head of the list have higher precedence. i.e. index 0 has
@@ -7,6 +7,7 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -244,6 +245,64 @@ func TestIntentionValidate(t *testing.T) {
 	}
 }
 
+func TestIntentionValidateAllowL7(t *testing.T) {
+	perms := []*IntentionPermission{{
+		Action: IntentionActionAllow,
+		HTTP:   &IntentionHTTPPermission{PathPrefix: "/api"},
+	}}
+
+	cases := []struct {
+		Name   string
+		Modify func(*Intention)
+		Err    string
+	}{
+		{
+			"action without permissions",
+			func(x *Intention) {},
+			"",
+		},
+		{
+			"permissions without action",
+			func(x *Intention) {
+				x.Action = ""
+				x.Permissions = perms
+			},
+			"",
+		},
+		{
+			"permissions and action",
+			func(x *Intention) { x.Permissions = perms },
+			"action must be omitted",
+		},
+		{
+			"neither permissions nor action",
+			func(x *Intention) { x.Action = "" },
+			"action must be set",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			ixn := TestIntention(t)
+			tc.Modify(ixn)
+
+			err := ixn.ValidateAllowL7()
+			assert.Equal(t, err != nil, tc.Err != "", err)
+			if err == nil {
+				return
+			}
+
+			assert.Contains(t, strings.ToLower(err.Error()), strings.ToLower(tc.Err))
+		})
+	}
+
+	// Without the opt-in the permissions are still rejected.
+	ixn := TestIntention(t)
+	ixn.Action = ""
+	ixn.Permissions = perms
+	require.ErrorContains(t, ixn.Validate(), "Permissions must not be set")
+}
+
 func TestIntentionPrecedenceSorter(t *testing.T) {
 	type fields struct {
 		SrcSamenessGroup string
@@ -388,6 +447,36 @@ func TestIntentionPrecedenceSorter(t *testing.T) {
 	}
 }
 
+func TestSimplifiedIntentions_Active(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	always := TestIntention(t)
+	expired := TestIntention(t)
+	expired.NotAfter = &past
+	pending := TestIntention(t)
+	pending.NotBefore = &future
+	current := TestIntention(t)
+	current.NotBefore = &past
+	current.NotAfter = &future
+
+	ixns := SimplifiedIntentions{always, expired, pending, current}
+	require.Equal(t, SimplifiedIntentions{always, current}, ixns.Active(now))
+	require.Equal(t, SimplifiedIntentions{always, expired}, ixns.Active(past.Add(-time.Minute)))
+
+	next, ok := ixns.NextActiveChange(now)
+	require.True(t, ok)
+	require.True(t, next.Equal(future))
+
+	_, ok = ixns.NextActiveChange(future)
+	require.False(t, ok)
+
+	unbounded := SimplifiedIntentions{always}
+	require.Equal(t, unbounded, unbounded.Active(now))
+	_, ok = unbounded.NextActiveChange(now)
+	require.False(t, ok)
+}
+
 func TestIntention_SetHash(t *testing.T) {
 	i := Intention{
 		ID:              "the-id",
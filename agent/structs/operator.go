@@ -37,6 +37,12 @@ type RaftServer struct {
 
 	// LastIndex is the last log index this server has a record of in its Raft log.
 	LastIndex uint64
+
+	// CommitIndex is the highest log index this server knows to be committed.
+	CommitIndex uint64
+
+	// AppliedIndex is the highest log index this server has applied to its FSM.
+	AppliedIndex uint64
 }
 
 // RaftConfigurationResponse is returned when querying for the current Raft
@@ -8,6 +8,7 @@ type SnapshotOp int
 const (
 	SnapshotSave SnapshotOp = iota
 	SnapshotRestore
+	SnapshotVerify
 )
 
 // SnapshotReplyFn gets a peek at the reply before the snapshot streams, which
@@ -41,7 +42,24 @@ type SnapshotResponse struct {
 	// Error is the overall error status of the RPC request.
 	Error string
 
+	// TableCounts has the number of entries in each state store table after
+	// the snapshot was restored into a throwaway FSM. It is only filled in
+	// for a SnapshotVerify.
+	TableCounts map[string]int
+
 	// QueryMeta has freshness information about the server that handled the
-	// request. It is only filled in for a SnapshotSave.
+	// request. It is only filled in for a SnapshotSave, except for the Index
+	// which is also set to the snapshot's index for a SnapshotVerify.
 	QueryMeta
 }
+
+// SnapshotVerifyResult is returned by the HTTP API when a snapshot restore is
+// only verified rather than applied.
+type SnapshotVerifyResult struct {
+	// Index is the Raft index the snapshot was taken at.
+	Index uint64
+
+	// TableCounts has the number of entries in each state store table after
+	// the snapshot was restored.
+	TableCounts map[string]int
+}
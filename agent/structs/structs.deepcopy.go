@@ -717,6 +717,14 @@ func (o *Intention) DeepCopy() *Intention {
 			}
 		}
 	}
+	if o.NotBefore != nil {
+		cp.NotBefore = new(time.Time)
+		*cp.NotBefore = *o.NotBefore
+	}
+	if o.NotAfter != nil {
+		cp.NotAfter = new(time.Time)
+		*cp.NotAfter = *o.NotAfter
+	}
 	if o.Meta != nil {
 		cp.Meta = make(map[string]string, len(o.Meta))
 		for k2, v2 := range o.Meta {
@@ -787,6 +795,10 @@ func (o *LoadBalancer) DeepCopy() *LoadBalancer {
 		cp.LeastRequestConfig = new(LeastRequestConfig)
 		*cp.LeastRequestConfig = *o.LeastRequestConfig
 	}
+	if o.SlowStartConfig != nil {
+		cp.SlowStartConfig = new(SlowStartConfig)
+		*cp.SlowStartConfig = *o.SlowStartConfig
+	}
 	if o.HashPolicies != nil {
 		cp.HashPolicies = make([]HashPolicy, len(o.HashPolicies))
 		copy(cp.HashPolicies, o.HashPolicies)
@@ -822,6 +834,26 @@ func (o *MeshConfigEntry) DeepCopy() *MeshConfigEntry {
 		cp.Peering = new(PeeringMeshConfig)
 		*cp.Peering = *o.Peering
 	}
+	if o.AccessLogs != nil {
+		cp.AccessLogs = new(AccessLogsConfig)
+		*cp.AccessLogs = *o.AccessLogs
+	}
+	if o.PassiveHealthCheck != nil {
+		cp.PassiveHealthCheck = new(PassiveHealthCheck)
+		*cp.PassiveHealthCheck = *o.PassiveHealthCheck
+		if o.PassiveHealthCheck.EnforcingConsecutive5xx != nil {
+			cp.PassiveHealthCheck.EnforcingConsecutive5xx = new(uint32)
+			*cp.PassiveHealthCheck.EnforcingConsecutive5xx = *o.PassiveHealthCheck.EnforcingConsecutive5xx
+		}
+		if o.PassiveHealthCheck.MaxEjectionPercent != nil {
+			cp.PassiveHealthCheck.MaxEjectionPercent = new(uint32)
+			*cp.PassiveHealthCheck.MaxEjectionPercent = *o.PassiveHealthCheck.MaxEjectionPercent
+		}
+		if o.PassiveHealthCheck.BaseEjectionTime != nil {
+			cp.PassiveHealthCheck.BaseEjectionTime = new(time.Duration)
+			*cp.PassiveHealthCheck.BaseEjectionTime = *o.PassiveHealthCheck.BaseEjectionTime
+		}
+	}
 	if o.Meta != nil {
 		cp.Meta = make(map[string]string, len(o.Meta))
 		for k2, v2 := range o.Meta {
@@ -91,6 +91,9 @@ const (
 	RaftLogVerifierCheckpoint                   = 41 // Only used for log verifier, no-op on FSM.
 	ResourceOperationType                       = 42
 	UpdateVirtualIPRequestType                  = 43
+	ReclaimVirtualIPsRequestType                = 44
+	SessionRenewAllRequestType                  = 45
+	SessionTransferRequestType                  = 46
 )
 
 const (
@@ -160,6 +163,9 @@ var requestTypeStrings = map[MessageType]string{
 	RaftLogVerifierCheckpoint:       "RaftLogVerifierCheckpoint",
 	ResourceOperationType:           "Resource",
 	UpdateVirtualIPRequestType:      "UpdateManualVirtualIPRequestType",
+	ReclaimVirtualIPsRequestType:    "ReclaimVirtualIPs",
+	SessionRenewAllRequestType:      "SessionRenewAll",
+	SessionTransferRequestType:      "SessionTransfer",
 }
 
 const (
@@ -746,6 +752,24 @@ func (r *PartitionSpecificRequest) CacheInfo() cache.RequestInfo {
 	}
 }
 
+// VirtualIPRequest is used to look up the service that owns a virtual IP.
+type VirtualIPRequest struct {
+	Datacenter string
+	VirtualIP  string
+	QueryOptions
+}
+
+func (r *VirtualIPRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+// ServiceByVirtualIPResponse holds the service that owns a virtual IP.
+// Service is nil when no service owns the requested address.
+type ServiceByVirtualIPResponse struct {
+	Service *PeeredServiceName
+	QueryMeta
+}
+
 // ServiceSpecificRequest is used to query about a specific service
 type ServiceSpecificRequest struct {
 	Datacenter string
@@ -1913,6 +1937,7 @@ type HealthCheckDefinition struct {
 	AliasNode                      string              `json:",omitempty"`
 	AliasService                   string              `json:",omitempty"`
 	SessionName                    string              `json:",omitempty"`
+	DependencyService              string              `json:",omitempty"`
 	TTL                            time.Duration       `json:",omitempty"`
 }
 
@@ -2458,6 +2483,54 @@ type IndexedCheckServiceNodes struct {
 	QueryMeta
 }
 
+// ServiceFailoverSummaryRequest is used to request the health of a service's
+// instances along with the health of each target it would fail over to.
+type ServiceFailoverSummaryRequest struct {
+	Datacenter  string
+	ServiceName string
+
+	acl.EnterpriseMeta `hcl:",squash" mapstructure:",squash"`
+	QueryOptions
+}
+
+func (r *ServiceFailoverSummaryRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+// DiscoveryTargetHealth summarizes the health of the instances behind a
+// single discovery chain target.
+type DiscoveryTargetHealth struct {
+	// TargetID is the ID of the target in the compiled discovery chain.
+	TargetID string
+
+	Service       string
+	ServiceSubset string `json:",omitempty"`
+	Namespace     string `json:",omitempty"`
+	Partition     string `json:",omitempty"`
+	Datacenter    string `json:",omitempty"`
+	Peer          string `json:",omitempty"`
+
+	// Healthy is the number of instances that would receive traffic, using the
+	// same rules a proxy applies to the target's endpoints.
+	Healthy int
+
+	// Total is the number of instances registered for the target.
+	Total int
+
+	// Error is set when the instances of a failover target could not be
+	// fetched, such as when its datacenter is unreachable.
+	Error string `json:",omitempty"`
+}
+
+// ServiceFailoverSummaryResponse is the response to a
+// Health.ServiceFailoverSummary request. Failover is ordered the same way
+// the targets are tried.
+type ServiceFailoverSummaryResponse struct {
+	Primary  *DiscoveryTargetHealth
+	Failover []*DiscoveryTargetHealth
+	QueryMeta
+}
+
 type IndexedNodesWithGateways struct {
 	ImportedNodes CheckServiceNodes
 	Nodes         CheckServiceNodes
@@ -2667,6 +2740,15 @@ type DirEntry struct {
 	Value     []byte
 	Session   string `json:",omitempty"`
 
+	// ExpirationTTL is how long the entry lives after it was last written.
+	// The zero value means the entry never expires.
+	ExpirationTTL time.Duration `json:",omitempty"`
+
+	// ExpirationTime is the point after which the leader deletes the entry.
+	// It is set from ExpirationTTL on the leader before the write is
+	// committed, so that every server agrees on it.
+	ExpirationTime *time.Time `json:",omitempty" bexpr:"-"`
+
 	acl.EnterpriseMeta `bexpr:"-"`
 	RaftIndex
 }
@@ -2683,6 +2765,8 @@ func (d *DirEntry) Clone() *DirEntry {
 			CreateIndex: d.CreateIndex,
 			ModifyIndex: d.ModifyIndex,
 		},
+		ExpirationTTL:  d.ExpirationTTL,
+		ExpirationTime: d.ExpirationTime,
 		EnterpriseMeta: d.EnterpriseMeta,
 	}
 }
@@ -2692,7 +2776,20 @@ func (d *DirEntry) Equal(o *DirEntry) bool {
 		d.Key == o.Key &&
 		d.Flags == o.Flags &&
 		bytes.Equal(d.Value, o.Value) &&
-		d.Session == o.Session
+		d.Session == o.Session &&
+		d.ExpirationTTL == o.ExpirationTTL &&
+		d.HasExpirationTime() == o.HasExpirationTime() &&
+		(!d.HasExpirationTime() || d.ExpirationTime.Equal(*o.ExpirationTime))
+}
+
+// HasExpirationTime returns true if the entry is set to expire.
+func (d *DirEntry) HasExpirationTime() bool {
+	return d.ExpirationTime != nil && !d.ExpirationTime.IsZero()
+}
+
+// IsExpired returns true if the entry is set to expire before asOf.
+func (d *DirEntry) IsExpired(asOf time.Time) bool {
+	return d.HasExpirationTime() && d.ExpirationTime.Before(asOf)
 }
 
 // IDValue implements the state.singleValueID interface for indexing.
@@ -2707,6 +2804,11 @@ type KVSRequest struct {
 	Datacenter string
 	Op         api.KVOp // Which operation are we performing
 	DirEnt     DirEntry // Which directory entry
+
+	// LockHolder is the session expected to hold the lock on DirEnt.Key for
+	// an api.KVLockTransfer. The lock is handed to DirEnt.Session.
+	LockHolder string `json:",omitempty"`
+
 	WriteRequest
 }
 
@@ -2714,10 +2816,29 @@ func (r *KVSRequest) RequestDatacenter() string {
 	return r.Datacenter
 }
 
+// KVSApplyResponse is the result of applying a KVSRequest with
+// KVS.ApplyWithResult.
+type KVSApplyResponse struct {
+	// Success is the same result KVS.Apply returns.
+	Success bool
+
+	// Deleted is the number of keys removed by a delete-tree operation. It
+	// is zero for all other operations.
+	Deleted int
+}
+
 // KeyRequest is used to request a key, or key prefix
 type KeyRequest struct {
 	Datacenter string
 	Key        string
+
+	// Flags, if set, limits a KVS.List to the entries whose flags equal it.
+	Flags *uint64
+
+	// Session, if set, limits a KVS.List to the entries locked by the
+	// session with this ID.
+	Session string
+
 	acl.EnterpriseMeta
 	QueryOptions
 }
@@ -2834,6 +2955,13 @@ type SessionRequest struct {
 	Datacenter string
 	Op         SessionOp // Which operation are we performing
 	Session    Session   // Which session
+
+	// ExemptCheckTypes lists the health check types, in addition to
+	// "session", that may be critical when the session is created. It is
+	// set by the server from its configuration before the request is
+	// committed, so every server applies the request the same way.
+	ExemptCheckTypes []string `json:",omitempty"`
+
 	WriteRequest
 }
 
@@ -2847,6 +2975,11 @@ type SessionSpecificRequest struct {
 	SessionID  string
 	// DEPRECATED in 1.7.0
 	Session string
+
+	// ListFilter restricts the sessions returned by Session.List. It is
+	// ignored by the other session endpoints.
+	ListFilter SessionListFilter
+
 	acl.EnterpriseMeta
 	QueryOptions
 }
@@ -2855,6 +2988,120 @@ func (r *SessionSpecificRequest) RequestDatacenter() string {
 	return r.Datacenter
 }
 
+// SessionListFilter restricts a session list to the sessions matching all
+// of its non-zero fields.
+type SessionListFilter struct {
+	// Behavior only matches sessions with the given behavior.
+	Behavior SessionBehavior
+
+	// MinLockDelay and MaxLockDelay are inclusive bounds on the lock delay
+	// of the matched sessions. MaxLockDelay is a pointer so that an explicit
+	// zero, which only matches sessions without a lock delay, can be told
+	// apart from no upper bound.
+	MinLockDelay time.Duration
+	MaxLockDelay *time.Duration
+}
+
+// IsZero reports whether the filter matches every session.
+func (f *SessionListFilter) IsZero() bool {
+	return f == nil || (f.Behavior == "" && f.MinLockDelay == 0 && f.MaxLockDelay == nil)
+}
+
+// Validate checks the filter for unknown behaviors and inverted ranges.
+func (f *SessionListFilter) Validate() error {
+	if f == nil {
+		return nil
+	}
+	switch f.Behavior {
+	case "", SessionKeysRelease, SessionKeysDelete:
+	default:
+		return fmt.Errorf("Invalid session behavior %q", f.Behavior)
+	}
+	if f.MinLockDelay < 0 || (f.MaxLockDelay != nil && *f.MaxLockDelay < 0) {
+		return fmt.Errorf("Lock delay bounds must not be negative")
+	}
+	if f.MaxLockDelay != nil && f.MinLockDelay > *f.MaxLockDelay {
+		return fmt.Errorf("Minimum lock delay %v is greater than maximum lock delay %v", f.MinLockDelay, *f.MaxLockDelay)
+	}
+	return nil
+}
+
+// Matches reports whether the session passes the filter.
+func (f *SessionListFilter) Matches(s *Session) bool {
+	if f.IsZero() {
+		return true
+	}
+	if f.Behavior != "" && s.Behavior != f.Behavior {
+		return false
+	}
+	if s.LockDelay < f.MinLockDelay {
+		return false
+	}
+	if f.MaxLockDelay != nil && s.LockDelay > *f.MaxLockDelay {
+		return false
+	}
+	return true
+}
+
+// SessionRenewAllRequest is used to renew many sessions at once.
+type SessionRenewAllRequest struct {
+	Datacenter string
+	SessionIDs []string
+	acl.EnterpriseMeta
+	WriteRequest
+}
+
+func (r *SessionRenewAllRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
+// SessionRenewResult is the outcome of renewing a single session as part of
+// a SessionRenewAllRequest.
+type SessionRenewResult struct {
+	SessionID string
+	Renewed   bool
+
+	// Error is why the session could not be renewed, e.g. because it no
+	// longer exists.
+	Error string `json:",omitempty"`
+
+	// Deadline is the time until which the session is guaranteed not to be
+	// invalidated. It is zero for sessions without a TTL.
+	Deadline time.Time `json:",omitempty"`
+}
+
+type SessionRenewAllResponse struct {
+	Results []SessionRenewResult
+	Index   uint64
+}
+
+// SessionTransferRequest is used to move a session, along with any locks it
+// holds, to another node.
+type SessionTransferRequest struct {
+	Datacenter string
+	SessionID  string
+
+	// Node is the node the session is transferred to.
+	Node string
+
+	// NodeChecks and ServiceChecks replace the checks of the session, which
+	// must be registered on the new node. If both are empty the session
+	// keeps its current checks.
+	NodeChecks    []string
+	ServiceChecks []ServiceCheck
+
+	// ExemptCheckTypes is set by the server the same way as
+	// SessionRequest.ExemptCheckTypes.
+	ExemptCheckTypes []string `json:",omitempty"`
+
+	acl.EnterpriseMeta
+	WriteRequest
+}
+
+func (r *SessionTransferRequest) RequestDatacenter() string {
+	return r.Datacenter
+}
+
 type IndexedSessions struct {
 	Sessions Sessions
 	QueryMeta
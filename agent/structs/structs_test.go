@@ -1878,6 +1878,7 @@ func (a aclAuthorizerCheckServiceNode) NodeRead(_ string, ctx *acl.AuthorizerCon
 }
 
 func TestStructs_DirEntry_Clone(t *testing.T) {
+	expires := time.Unix(100, 0)
 	e := &DirEntry{
 		LockIndex: 5,
 		Key:       "hello",
@@ -1888,6 +1889,8 @@ func TestStructs_DirEntry_Clone(t *testing.T) {
 			CreateIndex: 1,
 			ModifyIndex: 2,
 		},
+		ExpirationTTL:  time.Minute,
+		ExpirationTime: &expires,
 	}
 
 	clone := e.Clone()
@@ -33,6 +33,7 @@ const (
 	SystemMetadataIntentionFormatLegacyValue   = "legacy"
 	SystemMetadataVirtualIPsEnabled            = "virtual-ips"
 	SystemMetadataTermGatewayVirtualIPsEnabled = "virtual-ips-term-gateway"
+	SystemMetadataVirtualIPCIDR                = "virtual-ips-cidr"
 )
 
 type SystemMetadataEntry struct {
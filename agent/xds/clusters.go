@@ -1187,7 +1187,7 @@ func (s *ResourceGenerator) makeUpstreamClusterForPeerService(
 
 	clusterName := generatePeeredClusterName(uid, tbs)
 
-	outlierDetection := config.ToOutlierDetection(upstreamConfig.PassiveHealthCheck, nil, true)
+	outlierDetection := config.ToOutlierDetection(cfgSnap.MeshPassiveHealthCheck(), upstreamConfig.PassiveHealthCheck, true)
 	// We can't rely on health checks for services on cluster peers because they
 	// don't take into account service resolvers, splitters and routers. Setting
 	// MaxEjectionPercent too 100% gives outlier detection the power to eject the
@@ -1324,7 +1324,7 @@ func (s *ResourceGenerator) makeUpstreamClusterForPreparedQuery(upstream structs
 			CircuitBreakers: &envoy_cluster_v3.CircuitBreakers{
 				Thresholds: makeThresholdsIfNeeded(cfg.Limits),
 			},
-			OutlierDetection: config.ToOutlierDetection(cfg.PassiveHealthCheck, nil, true),
+			OutlierDetection: config.ToOutlierDetection(cfgSnap.MeshPassiveHealthCheck(), cfg.PassiveHealthCheck, true),
 		}
 		if cfg.Protocol == "http2" || cfg.Protocol == "grpc" {
 			if err := s.setHttp2ProtocolOptions(c); err != nil {
@@ -1546,7 +1546,7 @@ func (s *ResourceGenerator) makeUpstreamClustersForDiscoveryChain(
 				CircuitBreakers: &envoy_cluster_v3.CircuitBreakers{
 					Thresholds: makeThresholdsIfNeeded(upstreamConfig.Limits),
 				},
-				OutlierDetection: config.ToOutlierDetection(upstreamConfig.PassiveHealthCheck, nil, true),
+				OutlierDetection: config.ToOutlierDetection(cfgSnap.MeshPassiveHealthCheck(), upstreamConfig.PassiveHealthCheck, true),
 			}
 
 			var lb *structs.LoadBalancer
@@ -2028,20 +2028,41 @@ func injectLBToCluster(ec *structs.LoadBalancer, c *envoy_cluster_v3.Cluster) er
 
 	switch ec.Policy {
 	case "":
-		return nil
+		// Envoy defaults to round robin, so only slow start needs to be set.
+		if ec.SlowStartConfig == nil {
+			return nil
+		}
+		c.LbPolicy = envoy_cluster_v3.Cluster_ROUND_ROBIN
+		c.LbConfig = &envoy_cluster_v3.Cluster_RoundRobinLbConfig_{
+			RoundRobinLbConfig: &envoy_cluster_v3.Cluster_RoundRobinLbConfig{
+				SlowStartConfig: makeSlowStartConfig(ec.SlowStartConfig),
+			},
+		}
 	case structs.LBPolicyLeastRequest:
 		c.LbPolicy = envoy_cluster_v3.Cluster_LEAST_REQUEST
 
-		if ec.LeastRequestConfig != nil {
+		if ec.LeastRequestConfig != nil || ec.SlowStartConfig != nil {
+			cfg := &envoy_cluster_v3.Cluster_LeastRequestLbConfig{
+				SlowStartConfig: makeSlowStartConfig(ec.SlowStartConfig),
+			}
+			if ec.LeastRequestConfig != nil {
+				cfg.ChoiceCount = &wrapperspb.UInt32Value{Value: ec.LeastRequestConfig.ChoiceCount}
+			}
 			c.LbConfig = &envoy_cluster_v3.Cluster_LeastRequestLbConfig_{
-				LeastRequestLbConfig: &envoy_cluster_v3.Cluster_LeastRequestLbConfig{
-					ChoiceCount: &wrapperspb.UInt32Value{Value: ec.LeastRequestConfig.ChoiceCount},
-				},
+				LeastRequestLbConfig: cfg,
 			}
 		}
 	case structs.LBPolicyRoundRobin:
 		c.LbPolicy = envoy_cluster_v3.Cluster_ROUND_ROBIN
 
+		if ec.SlowStartConfig != nil {
+			c.LbConfig = &envoy_cluster_v3.Cluster_RoundRobinLbConfig_{
+				RoundRobinLbConfig: &envoy_cluster_v3.Cluster_RoundRobinLbConfig{
+					SlowStartConfig: makeSlowStartConfig(ec.SlowStartConfig),
+				},
+			}
+		}
+
 	case structs.LBPolicyRandom:
 		c.LbPolicy = envoy_cluster_v3.Cluster_RANDOM
 
@@ -2065,6 +2086,22 @@ func injectLBToCluster(ec *structs.LoadBalancer, c *envoy_cluster_v3.Cluster) er
 	return nil
 }
 
+// makeSlowStartConfig returns the Envoy configuration that ramps up the weight
+// of new endpoints, or nil if slow start is not configured.
+func makeSlowStartConfig(cfg *structs.SlowStartConfig) *envoy_cluster_v3.Cluster_SlowStartConfig {
+	if cfg == nil {
+		return nil
+	}
+
+	ss := &envoy_cluster_v3.Cluster_SlowStartConfig{
+		SlowStartWindow: durationpb.New(cfg.Window),
+	}
+	if cfg.MinWeightPercent > 0 {
+		ss.MinWeightPercent = &envoy_type_v3.Percent{Value: cfg.MinWeightPercent}
+	}
+	return ss
+}
+
 func (s *ResourceGenerator) setHttp2ProtocolOptions(c *envoy_cluster_v3.Cluster) error {
 	cfg := &envoy_upstreams_v3.HttpProtocolOptions{
 		UpstreamProtocolOptions: &envoy_upstreams_v3.HttpProtocolOptions_ExplicitHttpConfig_{
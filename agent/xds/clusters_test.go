@@ -13,8 +13,10 @@ import (
 	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	envoy_core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	envoy_tls_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	testinf "github.com/mitchellh/go-testing-interface"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	"github.com/dhiaayachi/consul/agent/proxycfg"
@@ -191,6 +193,68 @@ func TestEnvoyLBConfig_InjectToCluster(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "round robin with slow start",
+			lb: &structs.LoadBalancer{
+				Policy: structs.LBPolicyRoundRobin,
+				SlowStartConfig: &structs.SlowStartConfig{
+					Window: 30 * time.Second,
+				},
+			},
+			expected: &envoy_cluster_v3.Cluster{
+				LbPolicy: envoy_cluster_v3.Cluster_ROUND_ROBIN,
+				LbConfig: &envoy_cluster_v3.Cluster_RoundRobinLbConfig_{
+					RoundRobinLbConfig: &envoy_cluster_v3.Cluster_RoundRobinLbConfig{
+						SlowStartConfig: &envoy_cluster_v3.Cluster_SlowStartConfig{
+							SlowStartWindow: durationpb.New(30 * time.Second),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "empty policy with slow start",
+			lb: &structs.LoadBalancer{
+				SlowStartConfig: &structs.SlowStartConfig{
+					Window: 30 * time.Second,
+				},
+			},
+			expected: &envoy_cluster_v3.Cluster{
+				LbPolicy: envoy_cluster_v3.Cluster_ROUND_ROBIN,
+				LbConfig: &envoy_cluster_v3.Cluster_RoundRobinLbConfig_{
+					RoundRobinLbConfig: &envoy_cluster_v3.Cluster_RoundRobinLbConfig{
+						SlowStartConfig: &envoy_cluster_v3.Cluster_SlowStartConfig{
+							SlowStartWindow: durationpb.New(30 * time.Second),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "least_request with slow start",
+			lb: &structs.LoadBalancer{
+				Policy: structs.LBPolicyLeastRequest,
+				LeastRequestConfig: &structs.LeastRequestConfig{
+					ChoiceCount: 3,
+				},
+				SlowStartConfig: &structs.SlowStartConfig{
+					Window:           time.Minute,
+					MinWeightPercent: 25,
+				},
+			},
+			expected: &envoy_cluster_v3.Cluster{
+				LbPolicy: envoy_cluster_v3.Cluster_LEAST_REQUEST,
+				LbConfig: &envoy_cluster_v3.Cluster_LeastRequestLbConfig_{
+					LeastRequestLbConfig: &envoy_cluster_v3.Cluster_LeastRequestLbConfig{
+						ChoiceCount: &wrapperspb.UInt32Value{Value: 3},
+						SlowStartConfig: &envoy_cluster_v3.Cluster_SlowStartConfig{
+							SlowStartWindow:  durationpb.New(time.Minute),
+							MinWeightPercent: &envoy_type_v3.Percent{Value: 25},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range tests {
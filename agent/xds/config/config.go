@@ -37,6 +37,11 @@ type XDSCommonConfig struct {
 	// XDSFetchTimeoutMs specifies the amount of milliseconds to wait for dynamically configured Envoy data (EDS, RDS).
 	// Uses the Envoy default value if not specified or negative. A value of zero disables the timeout.
 	XDSFetchTimeoutMs *int `mapstructure:"xds_fetch_timeout_ms"`
+
+	// ListenerDrainTimeoutMs specifies the amount of milliseconds that HTTP connections on a listener
+	// are given to drain gracefully when the listener is updated or removed. Uses the Envoy default
+	// value if not specified or negative. TCP listeners always use Envoy's global drain time.
+	ListenerDrainTimeoutMs *int `mapstructure:"listener_drain_timeout_ms"`
 }
 
 // ParseXDSCommonConfig returns the XDSCommonConfig parsed from an opaque map. If an
@@ -58,6 +63,16 @@ func (c *XDSCommonConfig) GetXDSFetchTimeout() *durationpb.Duration {
 	return nil
 }
 
+func (c *XDSCommonConfig) GetListenerDrainTimeout() *durationpb.Duration {
+	if c == nil || c.ListenerDrainTimeoutMs == nil {
+		return nil
+	}
+	if *c.ListenerDrainTimeoutMs >= 0 {
+		return durationpb.New(time.Duration(*c.ListenerDrainTimeoutMs) * time.Millisecond)
+	}
+	return nil
+}
+
 // ProxyConfig describes the keys we understand from Connect.Proxy.Config. Note
 // that this only includes config keys that affects runtime config delivered by
 // xDS. For Envoy config keys that affect bootstrap generation see
@@ -197,6 +212,7 @@ func ParseGatewayConfig(m map[string]interface{}) (GatewayConfig, error) {
 // If all values are zero a default empty OutlierDetection will be returned to
 // enable outlier detection with default values.
 //   - If override is not nil, it will overwrite the values from p, e.g., ingress gateway defaults
+//     or the mesh-wide defaults for a connect-proxy upstream
 //   - allowZero is added to handle the legacy case where connect-proxy and mesh gateway can set 0
 //     for EnforcingConsecutive5xx. Due to the definition of proto of PassiveHealthCheck, ingress
 //     gateway's EnforcingConsecutive5xx must be > 0.
@@ -242,11 +258,9 @@ func ToOutlierDetection(p *structs.PassiveHealthCheck, override *structs.Passive
 
 	if override.EnforcingConsecutive5xx != nil {
 		// NOTE: EnforcingConsecutive5xx must be great than 0 for ingress-gateway
-		if *override.EnforcingConsecutive5xx != 0 {
+		if *override.EnforcingConsecutive5xx != 0 || allowZero {
 			od.EnforcingConsecutive_5Xx = &wrapperspb.UInt32Value{Value: *override.EnforcingConsecutive5xx}
 		}
-		// Because only ingress gateways have overrides and they cannot have a value of 0, there is no allowZero
-		// override case to handle
 	}
 
 	if override.MaxEjectionPercent != nil {
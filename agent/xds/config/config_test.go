@@ -370,3 +370,55 @@ func Test_ProxyConfig_GetXDSFetchTimeout(t *testing.T) {
 		})
 	}
 }
+
+func Test_ProxyConfig_GetListenerDrainTimeout(t *testing.T) {
+	tests := map[string]struct {
+		conf      map[string]interface{}
+		expect    *durationpb.Duration
+		expectErr bool
+	}{
+		"nil_conf": {
+			expect: nil,
+			conf:   nil,
+		},
+		"key_not_found": {
+			expect: nil,
+			conf:   map[string]interface{}{},
+		},
+		"bad_value_for_key": {
+			expectErr: true,
+			conf: map[string]interface{}{
+				"listener_drain_timeout_ms": "bad",
+			},
+		},
+		"negative_value_for_key": {
+			expect: nil,
+			conf: map[string]interface{}{
+				"listener_drain_timeout_ms": -1,
+			},
+		},
+		"zero_value_for_key": {
+			expect: durationpb.New(0),
+			conf: map[string]interface{}{
+				"listener_drain_timeout_ms": 0,
+			},
+		},
+		"valid_value_for_key": {
+			expect: durationpb.New(99 * time.Millisecond),
+			conf: map[string]interface{}{
+				"listener_drain_timeout_ms": 99,
+			},
+		},
+	}
+	for n, tc := range tests {
+		t.Run(n, func(t *testing.T) {
+			conf, err := ParseXDSCommonConfig(tc.conf)
+			if tc.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.True(t, proto.Equal(tc.expect, conf.GetListenerDrainTimeout()))
+		})
+	}
+}
@@ -9,7 +9,9 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -41,6 +43,7 @@ import (
 
 var errOverwhelmed = status.Error(codes.ResourceExhausted, "this server has too many xDS streams open, please try another")
 var errConfigSyncError = status.Errorf(codes.Internal, "config-source sync loop terminated due to error")
+var errInitialConfigTimeout = status.Error(codes.FailedPrecondition, "timed out waiting for the proxy's initial configuration, check that the proxy service is registered")
 
 // xdsProtocolLegacyChildResend enables the legacy behavior for the `ensureChildResend` function.
 // This environment variable exists as an escape hatch so that users can disable the behavior, if needed.
@@ -56,6 +59,27 @@ const (
 	deltaRecvUnknownType
 )
 
+// Kinds of DeltaDiscoveryRequest, used as a metric label.
+const (
+	deltaRequestACK         = "ack"
+	deltaRequestNACK        = "nack"
+	deltaRequestSpontaneous = "spontaneous"
+)
+
+// deltaRequestKind returns whether req (N)ACKs an earlier response, or is a
+// spontaneous request such as the first request of a type or a change of
+// subscriptions. Only (N)ACKs carry a response nonce.
+func deltaRequestKind(req *envoy_discovery_v3.DeltaDiscoveryRequest) string {
+	switch {
+	case req.ResponseNonce == "":
+		return deltaRequestSpontaneous
+	case req.ErrorDetail == nil:
+		return deltaRequestACK
+	default:
+		return deltaRequestNACK
+	}
+}
+
 // ADSDeltaStream is a shorter way of referring to this thing...
 type ADSDeltaStream = envoy_discovery_v3.AggregatedDiscoveryService_DeltaAggregatedResourcesServer
 
@@ -131,11 +155,25 @@ func (s *Server) processDelta(stream ADSDeltaStream, reqCh <-chan *envoy_discove
 		drainCh          limiter.SessionTerminatedChan
 		cfgSrcTerminated proxycfg.SrcTerminatedChan
 		watchCancel      func()
-		nonce            uint64 // xDS requires a unique nonce to correlate response/request pairs
-		ready            bool   // set to true after the first snapshot arrives
+		nonce            uint64          // xDS requires a unique nonce to correlate response/request pairs
+		ready            bool            // set to true after the first snapshot arrives
+		withheld         map[string]bool // xDS types the token may not receive
+
+		// Snapshots that arrive within MinUpdateInterval of the last applied
+		// one are held in pendingSnapshot until updateTimer fires.
+		pendingSnapshot *proxycfg.ConfigSnapshot
+		updateTimer     <-chan time.Time
+		lastUpdate      time.Time
+
+		// initialConfigTimer fires if no snapshot arrives within
+		// InitialConfigTimeout of starting to watch the proxy.
+		initialConfigTimer <-chan time.Time
 
 		streamStartTime = time.Now()
 		streamStartOnce sync.Once
+
+		proxyID          structs.ServiceID // set once the proxy identifies itself
+		partitionCounted bool              // set once the stream counts towards its partition's limit
 	)
 
 	var (
@@ -167,7 +205,14 @@ func (s *Server) processDelta(stream ADSDeltaStream, reqCh <-chan *envoy_discove
 			return snapshot.AllowEmptyClusters()
 		}),
 		xdscommon.EndpointType: newDeltaType(logger, stream, xdscommon.EndpointType, nil),
-		xdscommon.SecretType:   newDeltaType(logger, stream, xdscommon.SecretType, nil), // TODO allowEmptyFn
+		xdscommon.SecretType: newDeltaType(logger, stream, xdscommon.SecretType, func() bool {
+			// Withheld secrets are sent as an empty response so that the proxy
+			// does not wait for them.
+			return withheld[xdscommon.SecretType]
+		}),
+	}
+	for _, handler := range handlers {
+		handler.caseInsensitive = s.CaseInsensitiveResourceNames
 	}
 
 	// Endpoints are stored within a Cluster (and Routes
@@ -188,6 +233,38 @@ func (s *Server) processDelta(stream ADSDeltaStream, reqCh <-chan *envoy_discove
 		childrenNames: make(map[string][]string),
 	}
 
+	streamID := s.streams.register()
+	defer s.streams.deregister(streamID)
+
+	// publishStatus makes the current state of the handlers visible to
+	// StreamStatuses. The registry is only updated when the state differs
+	// from what was last published, which is rare compared to how often the
+	// loop runs.
+	var published StreamStatus
+	publishStatus := func() {
+		var id string
+		if proxyID.ID != "" {
+			id = proxyID.String()
+		}
+		changed := published.Types == nil || published.ProxyID != id
+		for typeURL, handler := range handlers {
+			if changed {
+				break
+			}
+			changed = published.Types[typeURL] != handler.status()
+		}
+		if !changed {
+			return
+		}
+
+		types := make(map[string]DeltaTypeStatus, len(handlers))
+		for typeURL, handler := range handlers {
+			types[typeURL] = handler.status()
+		}
+		published = StreamStatus{ProxyID: id, Types: types}
+		s.streams.update(streamID, published)
+	}
+
 	var authTimer <-chan time.Time
 	extendAuthTimer := func() {
 		authTimer = time.After(s.AuthCheckFrequency)
@@ -197,7 +274,63 @@ func (s *Server) processDelta(stream ADSDeltaStream, reqCh <-chan *envoy_discove
 		return s.authorize(stream.Context(), snapshot)
 	}
 
+	// applySnapshot generates and indexes the xDS resources for a new config
+	// snapshot, replacing the resources that the handlers sync to Envoy.
+	applySnapshot := func(cs *proxycfg.ConfigSnapshot) error {
+		if caRotated(snapshot, cs) {
+			// Clusters and listeners carry the TLS contexts with the roots and
+			// leaf certificate, and secrets may reference certificates that are
+			// reissued for the new CA, so resend them even if they did not
+			// change. Endpoints and routes follow their parents.
+			logger.Debug("resending TLS resources after CA rotation")
+			for _, typeURL := range []string{xdscommon.ClusterType, xdscommon.ListenerType, xdscommon.SecretType} {
+				handlers[typeURL].forceResend()
+			}
+		}
+		snapshot = cs
+		pendingSnapshot = nil
+		updateTimer = nil
+		lastUpdate = time.Now()
+
+		newRes, err := getEnvoyConfiguration(snapshot, logger, s.CfgFetcher)
+		if err != nil {
+			return status.Errorf(codes.Unavailable, "failed to generate all xDS resources from the snapshot: %v", err)
+		}
+
+		// index and hash the xDS structures
+		newResourceMap := xdscommon.IndexResources(logger, newRes)
+
+		if s.ResourceMapMutateFn != nil {
+			s.ResourceMapMutateFn(newResourceMap)
+		}
+
+		if newResourceMap, err = s.applyEnvoyExtensions(newResourceMap, snapshot, node); err != nil {
+			// err is already the result of calling status.Errorf
+			return err
+		}
+
+		if err := populateChildIndexMap(newResourceMap); err != nil {
+			return status.Errorf(codes.Unavailable, "failed to index xDS resource versions: %v", err)
+		}
+
+		newVersions, err := computeResourceVersions(newResourceMap)
+		if err != nil {
+			return status.Errorf(codes.Unavailable, "failed to compute xDS resource versions: %v", err)
+		}
+
+		if s.logsResourceDiff(snapshot.ProxyID.ServiceID) {
+			logResourceDiff(logger, currentVersions, newVersions)
+		}
+
+		resourceMap = newResourceMap
+		currentVersions = newVersions
+		ready = true
+		return nil
+	}
+
 	for {
+		publishStatus()
+
 		select {
 		case <-drainCh:
 			logger.Debug("draining stream to rebalance load")
@@ -235,7 +368,19 @@ func (s *Server) processDelta(stream ADSDeltaStream, reqCh <-chan *envoy_discove
 				}
 			}
 
-			if handler, ok := handlers[req.TypeUrl]; ok {
+			// The type URL is chosen by the client, so types that are not
+			// handled share a label to keep the metric's cardinality bounded.
+			handler, ok := handlers[req.TypeUrl]
+			typeLabel := req.TypeUrl
+			if !ok {
+				typeLabel = "unknown"
+			}
+			metrics.IncrCounterWithLabels([]string{"xds", "server", "deltaRequest"}, 1, []metrics.Label{
+				{Name: "typeUrl", Value: typeLabel},
+				{Name: "kind", Value: deltaRequestKind(req)},
+			})
+
+			if ok {
 				switch handler.Recv(req, proxyFeatures) {
 				case deltaRecvNewSubscription:
 					logger.Trace("subscribing to type", "typeUrl", req.TypeUrl)
@@ -260,37 +405,31 @@ func (s *Server) processDelta(stream ADSDeltaStream, reqCh <-chan *envoy_discove
 				// would've already exited this loop.
 				return status.Error(codes.Aborted, "xDS stream terminated due to an irrecoverable error, please try again")
 			}
-			snapshot = cs
-
-			newRes, err := getEnvoyConfiguration(snapshot, logger, s.CfgFetcher)
-			if err != nil {
-				return status.Errorf(codes.Unavailable, "failed to generate all xDS resources from the snapshot: %v", err)
-			}
-
-			// index and hash the xDS structures
-			newResourceMap := xdscommon.IndexResources(logger, newRes)
-
-			if s.ResourceMapMutateFn != nil {
-				s.ResourceMapMutateFn(newResourceMap)
+			if wait := s.MinUpdateInterval - time.Since(lastUpdate); s.MinUpdateInterval > 0 && wait > 0 {
+				// Snapshots arriving faster than the minimum interval are
+				// coalesced, only the latest is applied once it elapses.
+				logger.Trace("Coalescing config snapshot until the minimum update interval elapses", "wait", wait)
+				pendingSnapshot = cs
+				if updateTimer == nil {
+					updateTimer = time.After(wait)
+				}
+				continue
 			}
-
-			if newResourceMap, err = s.applyEnvoyExtensions(newResourceMap, snapshot, node); err != nil {
-				// err is already the result of calling status.Errorf
+			if err := applySnapshot(cs); err != nil {
 				return err
 			}
-
-			if err := populateChildIndexMap(newResourceMap); err != nil {
-				return status.Errorf(codes.Unavailable, "failed to index xDS resource versions: %v", err)
+		case <-updateTimer:
+			if err := applySnapshot(pendingSnapshot); err != nil {
+				return err
 			}
-
-			newVersions, err := computeResourceVersions(newResourceMap)
-			if err != nil {
-				return status.Errorf(codes.Unavailable, "failed to compute xDS resource versions: %v", err)
+		case <-initialConfigTimer:
+			initialConfigTimer = nil
+			logger.Warn("xDS stream has not received the proxy's initial configuration, check that the proxy service is registered",
+				"timeout", s.InitialConfigTimeout)
+			metrics.IncrCounter([]string{"xds", "server", "streamInitialConfigTimeout"}, 1)
+			if s.TerminateOnInitialConfigTimeout {
+				return errInitialConfigTimeout
 			}
-
-			resourceMap = newResourceMap
-			currentVersions = newVersions
-			ready = true
 		case <-cfgSrcTerminated:
 			// Ensure that we cancel and cleanup resources if the sync loop terminates for any reason.
 			// This is necessary to handle the scenario where an unexpected error occurs that the loop
@@ -314,7 +453,7 @@ func (s *Server) processDelta(stream ADSDeltaStream, reqCh <-chan *envoy_discove
 			}
 
 			// Start authentication process, we need the proxyID
-			proxyID := structs.NewServiceID(node.Id, parseEnterpriseMeta(node))
+			proxyID = structs.NewServiceID(node.Id, parseEnterpriseMeta(node))
 
 			// Start watching config for that proxy
 			var err error
@@ -341,6 +480,10 @@ func (s *Server) processDelta(stream ADSDeltaStream, reqCh <-chan *envoy_discove
 
 			logger.Trace("watching proxy, pending initial proxycfg snapshot for xDS")
 
+			if s.InitialConfigTimeout > 0 {
+				initialConfigTimer = time.After(s.InitialConfigTimeout)
+			}
+
 			// Now wait for the config so we can check ACL
 			state = stateDeltaPendingInitialConfig
 		case stateDeltaPendingInitialConfig:
@@ -351,6 +494,7 @@ func (s *Server) processDelta(stream ADSDeltaStream, reqCh <-chan *envoy_discove
 
 			// Got config, try to authenticate next.
 			state = stateDeltaRunning
+			initialConfigTimer = nil
 
 			// Upgrade the logger
 			loggerName := snapshot.LoggerName()
@@ -367,10 +511,38 @@ func (s *Server) processDelta(stream ADSDeltaStream, reqCh <-chan *envoy_discove
 			if err := checkStreamACLs(snapshot); err != nil {
 				return err
 			}
+
+			// Account for the stream in the proxy's partition so that a single
+			// partition can't use up the capacity of the server. The partition
+			// is taken from the proxy registration once the token is known to
+			// be allowed to use it, since the node metadata is not trusted.
+			if !partitionCounted {
+				partition := snapshot.ProxyID.PartitionOrDefault()
+				endPartitionStream, ok := s.partitionStreams.begin(partition, s.MaxStreamsPerPartition)
+				if !ok {
+					logger.Debug("rejecting stream because its partition reached its stream limit",
+						"partition", partition,
+						"limit", s.MaxStreamsPerPartition,
+					)
+					metrics.IncrCounterWithLabels([]string{"xds", "server", "partitionStreamLimitReached"}, 1,
+						[]metrics.Label{{Name: "partition", Value: partition}})
+					return errOverwhelmed
+				}
+				defer endPartitionStream()
+				partitionCounted = true
+			}
 			// For the first time through the state machine, this is when the
 			// timer is first started.
 			extendAuthTimer()
 
+			// Some types require more than the stream as a whole. Their
+			// resources are withheld rather than failing the stream.
+			var err error
+			withheld, err = s.withheldTypes(stream.Context(), snapshot)
+			if err != nil {
+				return err
+			}
+
 			if !ready {
 				logger.Trace("Skipping delta computation because we haven't gotten a snapshot yet")
 				continue
@@ -401,7 +573,12 @@ func (s *Server) processDelta(stream ADSDeltaStream, reqCh <-chan *envoy_discove
 						break
 					}
 				}
-				err, _ := handlers[op.TypeUrl].SendIfNew(currentVersions[op.TypeUrl], resourceMap, &nonce, op.Upsert, op.Remove)
+				versions := currentVersions[op.TypeUrl]
+				if withheld[op.TypeUrl] {
+					logger.Trace("Withholding resources the token is not allowed to receive", "typeUrl", op.TypeUrl)
+					versions = nil
+				}
+				err, _ := handlers[op.TypeUrl].SendIfNew(versions, resourceMap, &nonce, op.Upsert, op.Remove)
 				if err != nil {
 					return status.Errorf(codes.Unavailable,
 						"failed to send %sreply for type %q: %v",
@@ -413,6 +590,15 @@ func (s *Server) processDelta(stream ADSDeltaStream, reqCh <-chan *envoy_discove
 	}
 }
 
+// caRotated returns true if the active CA root differs between two
+// consecutive snapshots of a proxy.
+func caRotated(prev, next *proxycfg.ConfigSnapshot) bool {
+	if prev == nil || prev.Roots == nil || next.Roots == nil {
+		return false
+	}
+	return prev.Roots.ActiveRootID != next.Roots.ActiveRootID
+}
+
 func (s *Server) applyEnvoyExtensions(resources *xdscommon.IndexedResources, snapshot *proxycfg.ConfigSnapshot, node *envoy_config_core_v3.Node) (*xdscommon.IndexedResources, error) {
 	var err error
 	envoyVersion := xdscommon.DetermineEnvoyVersionFromNode(node)
@@ -436,6 +622,67 @@ func (s *Server) applyEnvoyExtensions(resources *xdscommon.IndexedResources, sna
 	return resources, nil
 }
 
+// logsResourceDiff returns true if resource changes should be logged for the
+// given proxy.
+func (s *Server) logsResourceDiff(proxyID structs.ServiceID) bool {
+	for _, id := range s.ResourceDiffLogServiceIDs {
+		if id == proxyID.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// logResourceDiff logs the names of the resources of each type that were
+// added, removed or changed between two sets of resource versions.
+func logResourceDiff(logger hclog.Logger, oldVersions, newVersions map[string]map[string]string) {
+	var typeURLs []string
+	for typeURL := range newVersions {
+		typeURLs = append(typeURLs, typeURL)
+	}
+	for typeURL := range oldVersions {
+		if _, ok := newVersions[typeURL]; !ok {
+			typeURLs = append(typeURLs, typeURL)
+		}
+	}
+	sort.Strings(typeURLs)
+
+	for _, typeURL := range typeURLs {
+		var added, removed, changed []string
+		for name, version := range newVersions[typeURL] {
+			oldVersion, ok := oldVersions[typeURL][name]
+			switch {
+			case !ok:
+				added = append(added, name)
+			case oldVersion != version:
+				changed = append(changed, name)
+			}
+		}
+		for name := range oldVersions[typeURL] {
+			if _, ok := newVersions[typeURL][name]; !ok {
+				removed = append(removed, name)
+			}
+		}
+		if len(added) == 0 && len(removed) == 0 && len(changed) == 0 {
+			continue
+		}
+
+		sort.Strings(added)
+		sort.Strings(removed)
+		sort.Strings(changed)
+		logger.Info("xDS resources changed",
+			"typeUrl", typeURL,
+			"added", added,
+			"removed", removed,
+			"changed", changed,
+		)
+	}
+}
+
+// constructExtension builds an Envoy extension from its config. It is a
+// variable so tests can substitute extensions that are not registered.
+var constructExtension = envoyextensions.ConstructExtension
+
 func validateAndApplyEnvoyExtension(logger hclog.Logger, cfgSnap *proxycfg.ConfigSnapshot, resources *xdscommon.IndexedResources, runtimeConfig extensioncommon.RuntimeConfig, envoyVersion, consulVersion *goversion.Version) (*xdscommon.IndexedResources, error) {
 	logFn := logger.Warn
 	if runtimeConfig.EnvoyExtension.Required {
@@ -499,7 +746,7 @@ func validateAndApplyEnvoyExtension(logger hclog.Logger, cfgSnap *proxycfg.Confi
 	}
 
 	now := time.Now()
-	extender, err := envoyextensions.ConstructExtension(ext)
+	extender, err := constructExtension(ext)
 	metrics.MeasureSinceWithLabels([]string{"envoy_extension", "validate_arguments"}, now, getMetricLabels(err))
 	if err != nil {
 		errorParams = append(errorParams, "error", err)
@@ -529,6 +776,19 @@ func validateAndApplyEnvoyExtension(logger hclog.Logger, cfgSnap *proxycfg.Confi
 	now = time.Now()
 	resources, err = applyEnvoyExtension(extender, resources, &runtimeConfig)
 	metrics.MeasureSinceWithLabels([]string{"envoy_extension", "extend"}, now, getMetricLabels(err))
+	var panicErr *extensionPanicError
+	if errors.As(err, &panicErr) {
+		metrics.IncrCounterWithLabels([]string{"envoy_extension", "panic"}, 1, []metrics.Label{
+			{Name: "extension", Value: ext.Name},
+			{Name: "service", Value: cfgSnap.Service},
+		})
+		logger.Error("envoy extension panicked",
+			"extension", ext.Name,
+			"proxy_service_id", cfgSnap.ProxyID.String(),
+			"required", ext.Required,
+			"panic", panicErr.recovered,
+		)
+	}
 	if err != nil {
 		errorParams = append(errorParams, "error", err)
 		logFn("failed to apply envoy extension", errorParams...)
@@ -556,8 +816,7 @@ func applyEnvoyExtension(extender extensioncommon.EnvoyExtender, resources *xdsc
 	defer func() {
 		if err := recover(); err != nil {
 			r = resources
-			e = fmt.Errorf("attempt to apply Envoy extension %q caused an unexpected panic: %v",
-				runtimeConfig.EnvoyExtension.Name, err)
+			e = &extensionPanicError{name: runtimeConfig.EnvoyExtension.Name, recovered: err}
 		}
 	}()
 
@@ -575,6 +834,17 @@ func applyEnvoyExtension(extender extensioncommon.EnvoyExtender, resources *xdsc
 	return newResources, nil
 }
 
+// extensionPanicError is returned by applyEnvoyExtension when the extension
+// panicked instead of returning an error.
+type extensionPanicError struct {
+	name      string
+	recovered interface{}
+}
+
+func (e *extensionPanicError) Error() string {
+	return fmt.Sprintf("attempt to apply Envoy extension %q caused an unexpected panic: %v", e.name, e.recovered)
+}
+
 // https://www.envoyproxy.io/docs/envoy/latest/api-docs/xds_protocol#eventual-consistency-considerations
 var xDSUpdateOrder = []xDSUpdateOperation{
 	// 1. SDS updates (if any) can be pushed here with no harm.
@@ -655,8 +925,12 @@ type xDSDeltaType struct {
 	// sentToEnvoyOnce is true after we've sent one response to envoy.
 	sentToEnvoyOnce bool
 
-	// subscriptions is the set of currently subscribed envoy resources.
-	// If wildcard == true, this will be empty.
+	// caseInsensitive indicates that subscribed resource names match
+	// resources regardless of case.
+	caseInsensitive bool
+
+	// subscriptions is the set of currently subscribed envoy resources, keyed
+	// by subscriptionKey. If wildcard == true, this will be empty.
 	subscriptions map[string]struct{}
 
 	// resourceVersions is the current view of CONFIRMED/ACKed updates to
@@ -671,16 +945,47 @@ type xDSDeltaType struct {
 	//
 	// nonce -> name -> {version}
 	pendingUpdates map[string]map[string]PendingUpdate
+
+	// lastNackError is the error detail of the most recent NACK received
+	// for this type. It is kept for debugging and never cleared.
+	lastNackError string
 }
 
 func (t *xDSDeltaType) subscribed(name string) bool {
 	if t.wildcard {
 		return true
 	}
-	_, subscribed := t.subscriptions[name]
+	_, subscribed := t.subscriptions[t.subscriptionKey(name)]
 	return subscribed
 }
 
+// subscriptionKey returns the key that subscriptions to the named resource
+// are tracked under.
+func (t *xDSDeltaType) subscriptionKey(name string) string {
+	if t.caseInsensitive {
+		return strings.ToLower(name)
+	}
+	return name
+}
+
+// namesMatching returns the keys of m that a subscription to name applies
+// to. Unless caseInsensitive is set, that is at most name itself.
+func namesMatching[V any](m map[string]V, name string, caseInsensitive bool) []string {
+	if !caseInsensitive {
+		if _, ok := m[name]; ok {
+			return []string{name}
+		}
+		return nil
+	}
+	var names []string
+	for k := range m {
+		if strings.EqualFold(k, name) {
+			names = append(names, k)
+		}
+	}
+	return names
+}
+
 type PendingUpdate struct {
 	Remove  bool
 	Version string
@@ -740,20 +1045,20 @@ func (t *xDSDeltaType) Recv(req *envoy_discovery_v3.DeltaDiscoveryRequest, sf xd
 		DeltaDiscoveryRequest can be either or both of:
 	*/
 
-	if req.ResponseNonce != "" {
-		/*
-			[2] (N)ACKing an earlier resource update from the server (using
-			response_nonce, with presence of error_detail making it a NACK).
-		*/
-		if req.ErrorDetail == nil {
-			t.logger.Trace("got ok response from envoy proxy", "nonce", req.ResponseNonce)
-			t.ack(req.ResponseNonce)
-		} else {
-			t.logger.Error("got error response from envoy proxy", "nonce", req.ResponseNonce,
-				"error", status.ErrorProto(req.ErrorDetail))
-			t.nack(req.ResponseNonce)
-			return deltaRecvResponseNack
-		}
+	/*
+		[2] (N)ACKing an earlier resource update from the server (using
+		response_nonce, with presence of error_detail making it a NACK).
+	*/
+	switch deltaRequestKind(req) {
+	case deltaRequestACK:
+		t.logger.Trace("got ok response from envoy proxy", "nonce", req.ResponseNonce)
+		t.ack(req.ResponseNonce)
+	case deltaRequestNACK:
+		nackErr := status.ErrorProto(req.ErrorDetail)
+		t.logger.Error("got error response from envoy proxy", "nonce", req.ResponseNonce,
+			"error", nackErr)
+		t.nack(req.ResponseNonce, nackErr)
+		return deltaRecvResponseNack
 	}
 
 	if registeredThisTime && len(req.InitialResourceVersions) > 0 {
@@ -770,7 +1075,7 @@ func (t *xDSDeltaType) Recv(req *envoy_discovery_v3.DeltaDiscoveryRequest, sf xd
 		t.resourceVersions = req.InitialResourceVersions
 		if !t.wildcard {
 			for k := range req.InitialResourceVersions {
-				t.subscriptions[k] = struct{}{}
+				t.subscriptions[t.subscriptionKey(k)] = struct{}{}
 			}
 		}
 	}
@@ -798,20 +1103,23 @@ func (t *xDSDeltaType) Recv(req *envoy_discovery_v3.DeltaDiscoveryRequest, sf xd
 			//
 			// We handle that here by ALWAYS wiping the version so the diff
 			// decides to send the value.
-			_, alreadySubscribed := t.subscriptions[name]
-			t.subscriptions[name] = struct{}{}
+			key := t.subscriptionKey(name)
+			_, alreadySubscribed := t.subscriptions[key]
+			t.subscriptions[key] = struct{}{}
 
 			// Reset the tracked version so we force a reply.
-			if _, alreadyTracked := t.resourceVersions[name]; alreadyTracked {
-				t.resourceVersions[name] = ""
+			for _, trackedName := range namesMatching(t.resourceVersions, name, t.caseInsensitive) {
+				t.resourceVersions[trackedName] = ""
 			}
 
 			// Certain xDS types are children of other types, meaning that if Envoy subscribes to a parent.
 			// We MUST assume that if Envoy ever had data for the children of this parent, then the child's
 			// data is gone.
 			if t.deltaChild != nil && t.deltaChild.childType.registered {
-				for _, childName := range t.deltaChild.childrenNames[name] {
-					t.ensureChildResend(name, childName)
+				for _, parentName := range namesMatching(t.deltaChild.childrenNames, name, t.caseInsensitive) {
+					for _, childName := range t.deltaChild.childrenNames[parentName] {
+						t.ensureChildResend(parentName, childName)
+					}
 				}
 			}
 
@@ -823,10 +1131,11 @@ func (t *xDSDeltaType) Recv(req *envoy_discovery_v3.DeltaDiscoveryRequest, sf xd
 		}
 
 		for _, name := range req.ResourceNamesUnsubscribe {
-			if _, ok := t.subscriptions[name]; !ok {
+			key := t.subscriptionKey(name)
+			if _, ok := t.subscriptions[key]; !ok {
 				continue
 			}
-			delete(t.subscriptions, name)
+			delete(t.subscriptions, key)
 			t.logger.Trace("unsubscribing resource for stream", "resource", name)
 			// NOTE: we'll let the normal differential comparison handle cleaning up resourceVersions
 		}
@@ -856,8 +1165,43 @@ func (t *xDSDeltaType) ack(nonce string) {
 	delete(t.pendingUpdates, nonce)
 }
 
-func (t *xDSDeltaType) nack(nonce string) {
+func (t *xDSDeltaType) nack(nonce string, err error) {
 	delete(t.pendingUpdates, nonce)
+	if err != nil {
+		t.lastNackError = err.Error()
+	}
+}
+
+// forceResend marks every resource of this type as out of date in Envoy, so
+// the next send includes all of them even if their versions did not change.
+func (t *xDSDeltaType) forceResend() {
+	for name := range t.resourceVersions {
+		t.resourceVersions[name] = ""
+	}
+	// Updates that are still waiting for an ACK would otherwise restore the
+	// versions once they are ACKed.
+	for _, pending := range t.pendingUpdates {
+		for name, update := range pending {
+			if !update.Remove {
+				pending[name] = PendingUpdate{}
+			}
+		}
+	}
+}
+
+// status returns a summary of the type's state for debugging.
+func (t *xDSDeltaType) status() DeltaTypeStatus {
+	pending := 0
+	for _, updates := range t.pendingUpdates {
+		pending += len(updates)
+	}
+	return DeltaTypeStatus{
+		Registered:     t.registered,
+		Wildcard:       t.wildcard,
+		Subscriptions:  len(t.subscriptions),
+		PendingUpdates: pending,
+		LastNackError:  t.lastNackError,
+	}
 }
 
 func (t *xDSDeltaType) SendIfNew(
@@ -991,15 +1335,16 @@ func (t *xDSDeltaType) createDeltaResponse(
 		}
 
 		// Now find new things not in envoy yet
-		for name := range t.subscriptions {
+		for name, currVers := range currentVersions {
+			if !t.subscribed(name) {
+				continue
+			}
 			if _, known := t.resourceVersions[name]; known {
 				continue
 			}
-			if currVers, ok := currentVersions[name]; ok {
-				updates[name] = PendingUpdate{Version: currVers}
-				if upsert {
-					hasRelevantUpdates = true
-				}
+			updates[name] = PendingUpdate{Version: currVers}
+			if upsert {
+				hasRelevantUpdates = true
 			}
 		}
 	}
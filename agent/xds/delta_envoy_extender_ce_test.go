@@ -386,6 +386,24 @@ end`,
 				return proxycfg.TestConfigSnapshotDiscoveryChain(t, "default", false, propertyOverrideServiceDefaultsRemoveOutlierDetection, nil)
 			},
 		},
+		{
+			name: "propertyoverride-remove-outlier-detection-mesh-defaults",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshotDiscoveryChain(t, "default", false, propertyOverrideServiceDefaultsRemoveOutlierDetection, []proxycfg.UpdateEvent{
+					{
+						CorrelationID: "mesh",
+						Result: &structs.ConfigEntryResponse{
+							Entry: &structs.MeshConfigEntry{
+								PassiveHealthCheck: &structs.PassiveHealthCheck{
+									Interval:    10 * time.Second,
+									MaxFailures: 5,
+								},
+							},
+						},
+					},
+				})
+			},
+		},
 		{
 			name: "propertyoverride-cluster-load-assignment-outbound-add",
 			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
@@ -4,6 +4,7 @@
 package xds
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"strconv"
@@ -27,6 +28,7 @@ import (
 	"github.com/dhiaayachi/consul/version"
 	envoy_cluster_v3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	envoy_tls_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	envoy_discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	"github.com/hashicorp/go-hclog"
 	goversion "github.com/hashicorp/go-version"
@@ -256,6 +258,267 @@ func TestServer_DeltaAggregatedResources_v3_BasicProtocol_TCP(t *testing.T) {
 	}
 }
 
+func TestServer_DeltaAggregatedResources_v3_CaseInsensitiveResourceNames(t *testing.T) {
+	aclResolve := func(id string) (acl.Authorizer, error) {
+		// Allow all
+		return acl.RootAuthorizer("manage"), nil
+	}
+
+	for _, caseInsensitive := range []bool{false, true} {
+		t.Run(fmt.Sprintf("caseInsensitive=%t", caseInsensitive), func(t *testing.T) {
+			scenario := newTestServerDeltaScenario(t, aclResolve, "web-sidecar-proxy", "", 0, func(s *Server) {
+				s.CaseInsensitiveResourceNames = caseInsensitive
+			})
+			mgr, errCh, envoy := scenario.mgr, scenario.errCh, scenario.envoy
+
+			sid := structs.NewServiceID("web-sidecar-proxy", nil)
+			mgr.RegisterProxy(t, sid)
+
+			snap := newTestSnapshot(t, nil, "", nil)
+
+			envoy.SendDeltaReq(t, xdscommon.ClusterType, nil)
+			mgr.DeliverConfig(t, sid, snap)
+
+			assertDeltaResponseSent(t, envoy.deltaStream.sendCh, &envoy_discovery_v3.DeltaDiscoveryResponse{
+				TypeUrl: xdscommon.ClusterType,
+				Nonce:   hexString(1),
+				Resources: makeTestResources(t,
+					makeTestCluster(t, snap, "tcp:local_app"),
+					makeTestCluster(t, snap, "tcp:db"),
+					makeTestCluster(t, snap, "tcp:geo-cache"),
+				),
+			})
+
+			// Subscribe to the db endpoints using a name that only differs
+			// from the resource's name by case.
+			envoy.SendDeltaReq(t, xdscommon.EndpointType, &envoy_discovery_v3.DeltaDiscoveryRequest{
+				ResourceNamesSubscribe: []string{
+					strings.ToUpper("db.default.dc1.internal.11111111-2222-3333-4444-555555555555.consul"),
+				},
+			})
+
+			if caseInsensitive {
+				assertDeltaResponseSent(t, envoy.deltaStream.sendCh, &envoy_discovery_v3.DeltaDiscoveryResponse{
+					TypeUrl: xdscommon.EndpointType,
+					Nonce:   hexString(2),
+					Resources: makeTestResources(t,
+						makeTestEndpoints(t, snap, "tcp:db"),
+					),
+				})
+			} else {
+				// Without a matching subscription there is nothing to send.
+				assertDeltaResponseSent(t, envoy.deltaStream.sendCh, &envoy_discovery_v3.DeltaDiscoveryResponse{
+					TypeUrl: xdscommon.EndpointType,
+					Nonce:   hexString(2),
+				})
+			}
+
+			assertDeltaChanBlocked(t, envoy.deltaStream.sendCh)
+
+			envoy.Close()
+			select {
+			case err := <-errCh:
+				require.NoError(t, err)
+			case <-time.After(50 * time.Millisecond):
+				t.Fatalf("timed out waiting for handler to finish")
+			}
+		})
+	}
+}
+
+func TestServer_DeltaAggregatedResources_v3_ResourceDiffLog(t *testing.T) {
+	aclResolve := func(id string) (acl.Authorizer, error) {
+		// Allow all
+		return acl.RootAuthorizer("manage"), nil
+	}
+
+	for _, targeted := range []bool{false, true} {
+		t.Run(fmt.Sprintf("targeted=%t", targeted), func(t *testing.T) {
+			var logs syncBuffer
+			scenario := newTestServerDeltaScenario(t, aclResolve, "web-sidecar-proxy", "", 0, func(s *Server) {
+				s.Logger = hclog.New(&hclog.LoggerOptions{Output: &logs, Level: hclog.Info})
+				if targeted {
+					s.ResourceDiffLogServiceIDs = []string{"web-sidecar-proxy"}
+				}
+			})
+			mgr, errCh, envoy := scenario.mgr, scenario.errCh, scenario.envoy
+
+			sid := structs.NewServiceID("web-sidecar-proxy", nil)
+			mgr.RegisterProxy(t, sid)
+
+			snap := newTestSnapshot(t, nil, "", nil)
+
+			envoy.SendDeltaReq(t, xdscommon.ClusterType, nil)
+			mgr.DeliverConfig(t, sid, snap)
+
+			assertDeltaResponseSent(t, envoy.deltaStream.sendCh, &envoy_discovery_v3.DeltaDiscoveryResponse{
+				TypeUrl: xdscommon.ClusterType,
+				Nonce:   hexString(1),
+				Resources: makeTestResources(t,
+					makeTestCluster(t, snap, "tcp:local_app"),
+					makeTestCluster(t, snap, "tcp:db"),
+					makeTestCluster(t, snap, "tcp:geo-cache"),
+				),
+			})
+
+			if !targeted {
+				require.NotContains(t, logs.String(), "xDS resources changed")
+			} else {
+				// The first snapshot adds every resource.
+				require.Contains(t, logs.String(), "xDS resources changed")
+				require.Contains(t, logs.String(), "typeUrl="+xdscommon.ClusterType)
+				require.Contains(t, logs.String(), "db.default.dc1.internal.11111111-2222-3333-4444-555555555555.consul")
+				logs.Reset()
+
+				// Only the db endpoints change when one of them is removed.
+				snap = newTestSnapshot(t, snap, "", nil)
+				snap.ConnectProxy.ConfigSnapshotUpstreams.WatchedUpstreamEndpoints[UID("db")]["db.default.default.dc1"] =
+					snap.ConnectProxy.ConfigSnapshotUpstreams.WatchedUpstreamEndpoints[UID("db")]["db.default.default.dc1"][0:1]
+				mgr.DeliverConfig(t, sid, snap)
+
+				retry.Run(t, func(r *retry.R) {
+					output := logs.String()
+					require.Contains(r, output, "typeUrl="+xdscommon.EndpointType)
+					require.Contains(r, output, `changed=["db.default.dc1.internal.11111111-2222-3333-4444-555555555555.consul"]`)
+					require.NotContains(r, output, "typeUrl="+xdscommon.ClusterType)
+				})
+			}
+
+			envoy.Close()
+			select {
+			case err := <-errCh:
+				require.NoError(t, err)
+			case <-time.After(50 * time.Millisecond):
+				t.Fatalf("timed out waiting for handler to finish")
+			}
+		})
+	}
+}
+
+func TestServer_DeltaAggregatedResources_v3_MinUpdateInterval(t *testing.T) {
+	aclResolve := func(id string) (acl.Authorizer, error) {
+		// Allow all
+		return acl.RootAuthorizer("manage"), nil
+	}
+
+	const interval = 500 * time.Millisecond
+	var applied int32
+	scenario := newTestServerDeltaScenario(t, aclResolve, "web-sidecar-proxy", "", 0, func(s *Server) {
+		s.MinUpdateInterval = interval
+		s.ResourceMapMutateFn = func(*xdscommon.IndexedResources) {
+			atomic.AddInt32(&applied, 1)
+		}
+	})
+	mgr, errCh, envoy := scenario.mgr, scenario.errCh, scenario.envoy
+
+	sid := structs.NewServiceID("web-sidecar-proxy", nil)
+	mgr.RegisterProxy(t, sid)
+
+	snap := newTestSnapshot(t, nil, "", nil)
+
+	testutil.RunStep(t, "initial sync", func(t *testing.T) {
+		envoy.SendDeltaReq(t, xdscommon.ClusterType, nil)
+		mgr.DeliverConfig(t, sid, snap)
+
+		assertDeltaResponseSent(t, envoy.deltaStream.sendCh, &envoy_discovery_v3.DeltaDiscoveryResponse{
+			TypeUrl: xdscommon.ClusterType,
+			Nonce:   hexString(1),
+			Resources: makeTestResources(t,
+				makeTestCluster(t, snap, "tcp:local_app"),
+				makeTestCluster(t, snap, "tcp:db"),
+				makeTestCluster(t, snap, "tcp:geo-cache"),
+			),
+		})
+
+		envoy.SendDeltaReq(t, xdscommon.EndpointType, &envoy_discovery_v3.DeltaDiscoveryRequest{
+			ResourceNamesSubscribe: []string{
+				"db.default.dc1.internal.11111111-2222-3333-4444-555555555555.consul",
+			},
+		})
+
+		assertDeltaResponseSent(t, envoy.deltaStream.sendCh, &envoy_discovery_v3.DeltaDiscoveryResponse{
+			TypeUrl: xdscommon.EndpointType,
+			Nonce:   hexString(2),
+			Resources: makeTestResources(t,
+				makeTestEndpoints(t, snap, "tcp:db"),
+			),
+		})
+
+		envoy.SendDeltaReqACK(t, xdscommon.ClusterType, 1)
+		envoy.SendDeltaReqACK(t, xdscommon.EndpointType, 2)
+
+		assertDeltaChanBlocked(t, envoy.deltaStream.sendCh)
+		require.Equal(t, int32(1), atomic.LoadInt32(&applied))
+	})
+
+	testutil.RunStep(t, "flapping snapshots are coalesced", func(t *testing.T) {
+		// Flap one of the db endpoints, ending with it removed.
+		for i := 0; i < 5; i++ {
+			snap = newTestSnapshot(t, snap, "", nil)
+			if i%2 == 0 {
+				snap.ConnectProxy.ConfigSnapshotUpstreams.WatchedUpstreamEndpoints[UID("db")]["db.default.default.dc1"] =
+					snap.ConnectProxy.ConfigSnapshotUpstreams.WatchedUpstreamEndpoints[UID("db")]["db.default.default.dc1"][0:1]
+			}
+			mgr.DeliverConfig(t, sid, snap)
+
+			// Nothing is sent until the interval elapses.
+			assertDeltaChanBlocked(t, envoy.deltaStream.sendCh)
+		}
+
+		// Only the latest snapshot is applied once the interval elapses.
+		select {
+		case got := <-envoy.deltaStream.sendCh:
+			assertDeltaResponse(t, got, &envoy_discovery_v3.DeltaDiscoveryResponse{
+				TypeUrl: xdscommon.EndpointType,
+				Nonce:   hexString(3),
+				Resources: makeTestResources(t,
+					makeTestEndpoints(t, snap, "tcp:db[0]"),
+				),
+			})
+		case <-time.After(2 * interval):
+			t.Fatalf("no response received after %s", 2*interval)
+		}
+
+		envoy.SendDeltaReqACK(t, xdscommon.EndpointType, 3)
+
+		assertDeltaChanBlocked(t, envoy.deltaStream.sendCh)
+		require.Equal(t, int32(2), atomic.LoadInt32(&applied))
+	})
+
+	envoy.Close()
+	select {
+	case err := <-errCh:
+		require.NoError(t, err)
+	case <-time.After(50 * time.Millisecond):
+		t.Fatalf("timed out waiting for handler to finish")
+	}
+}
+
+// syncBuffer is a bytes.Buffer that is safe to write to and read from
+// concurrently.
+type syncBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.String()
+}
+
+func (b *syncBuffer) Reset() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.buf.Reset()
+}
+
 func TestServer_DeltaAggregatedResources_v3_NackLoop(t *testing.T) {
 	aclResolve := func(id string) (acl.Authorizer, error) {
 		// Allow all
@@ -349,11 +612,31 @@ func TestServer_DeltaAggregatedResources_v3_NackLoop(t *testing.T) {
 		assertDeltaChanBlocked(t, envoy.deltaStream.sendCh)
 
 		// Envoy NACKs the listener update due to the bad public listener
-		envoy.SendDeltaReqNACK(t, xdscommon.ListenerType, 3, &rpcstatus.Status{})
+		envoy.SendDeltaReqNACK(t, xdscommon.ListenerType, 3, &rpcstatus.Status{
+			Code:    int32(codes.InvalidArgument),
+			Message: "cannot bind to port 1",
+		})
 
 		// Consul should not respond until a new snapshot is delivered
 		// because the current snapshot is known to be bad.
 		assertDeltaChanBlocked(t, envoy.deltaStream.sendCh)
+
+		// The NACK is reported in the stream status.
+		retry.Run(t, func(r *retry.R) {
+			statuses := scenario.server.StreamStatuses()
+			require.Len(r, statuses, 1)
+			require.Equal(r, sid.String(), statuses[0].ProxyID)
+			require.Equal(r, DeltaTypeStatus{
+				Registered:    true,
+				Wildcard:      true,
+				LastNackError: "rpc error: code = InvalidArgument desc = cannot bind to port 1",
+			}, statuses[0].Types[xdscommon.ListenerType])
+			require.Equal(r, DeltaTypeStatus{
+				Registered:    true,
+				Subscriptions: 2,
+			}, statuses[0].Types[xdscommon.EndpointType])
+			require.False(r, statuses[0].Types[xdscommon.SecretType].Registered)
+		})
 	})
 
 	testutil.RunStep(t, "simulate envoy NACKing a listener update", func(t *testing.T) {
@@ -386,6 +669,169 @@ func TestServer_DeltaAggregatedResources_v3_NackLoop(t *testing.T) {
 	case <-time.After(50 * time.Millisecond):
 		t.Fatalf("timed out waiting for handler to finish")
 	}
+
+	// Closed streams are no longer reported.
+	require.Empty(t, scenario.server.StreamStatuses())
+
+	testutil.RunStep(t, "check request counters", func(t *testing.T) {
+		data := scenario.sink.Data()
+		require.Len(t, data, 1)
+
+		requireDeltaRequestCount := func(typeURL, kind string, expected int) {
+			t.Helper()
+			key := "consul.xds.test.xds.server.deltaRequest;typeUrl=" + typeURL + ";kind=" + kind
+			val, ok := data[0].Counters[key]
+			require.True(t, ok, "missing counter %s", key)
+			require.Equal(t, expected, val.Count, key)
+		}
+		requireDeltaRequestCount(xdscommon.ClusterType, deltaRequestSpontaneous, 1)
+		requireDeltaRequestCount(xdscommon.ClusterType, deltaRequestACK, 1)
+		requireDeltaRequestCount(xdscommon.EndpointType, deltaRequestSpontaneous, 1)
+		requireDeltaRequestCount(xdscommon.EndpointType, deltaRequestACK, 2)
+		requireDeltaRequestCount(xdscommon.ListenerType, deltaRequestSpontaneous, 1)
+		requireDeltaRequestCount(xdscommon.ListenerType, deltaRequestNACK, 1)
+	})
+}
+
+func TestServer_DeltaAggregatedResources_v3_UnknownTypeRequestCounter(t *testing.T) {
+	aclResolve := func(id string) (acl.Authorizer, error) { return acl.ManageAll(), nil }
+
+	scenario := newTestServerDeltaScenario(t, aclResolve, "web-sidecar-proxy", "", 0)
+	envoy, errCh := scenario.envoy, scenario.errCh
+
+	envoy.SendDeltaReq(t, "type.googleapis.com/made.up.Type", nil)
+	envoy.SendDeltaReq(t, xdscommon.ClusterType, nil)
+
+	// Requests are handled in order, so once the cluster request is counted
+	// the unknown one has been too.
+	retry.Run(t, func(r *retry.R) {
+		data := scenario.sink.Data()
+		require.Len(r, data, 1)
+
+		val, ok := data[0].Counters["consul.xds.test.xds.server.deltaRequest;typeUrl="+xdscommon.ClusterType+";kind=spontaneous"]
+		require.True(r, ok)
+		require.Equal(r, 1, val.Count)
+
+		val, ok = data[0].Counters["consul.xds.test.xds.server.deltaRequest;typeUrl=unknown;kind=spontaneous"]
+		require.True(r, ok)
+		require.Equal(r, 1, val.Count)
+
+		for key := range data[0].Counters {
+			require.NotContains(r, key, "made.up.Type")
+		}
+	})
+
+	envoy.Close()
+	select {
+	case err := <-errCh:
+		require.NoError(t, err)
+	case <-time.After(50 * time.Millisecond):
+		t.Fatalf("timed out waiting for handler to finish")
+	}
+}
+
+func TestServer_DeltaAggregatedResources_v3_MaxStreamsPerPartition(t *testing.T) {
+	aclResolve := func(id string) (acl.Authorizer, error) { return acl.ManageAll(), nil }
+
+	scenario := newTestServerDeltaScenario(t, aclResolve, "web-sidecar-proxy", "", 0, func(s *Server) {
+		s.MaxStreamsPerPartition = 1
+	})
+	server, mgr, errCh, envoy := scenario.server, scenario.mgr, scenario.errCh, scenario.envoy
+
+	webID := structs.NewServiceID("web-sidecar-proxy", nil)
+	dbID := structs.NewServiceID("db-sidecar-proxy", nil)
+	mgr.RegisterProxy(t, webID)
+	mgr.RegisterProxy(t, dbID)
+
+	// Streams only count towards the limit of the partition once the proxy
+	// registration was received and authorized.
+	envoy.SendDeltaReq(t, xdscommon.ClusterType, nil)
+	end, ok := server.partitionStreams.begin("default", server.MaxStreamsPerPartition)
+	require.True(t, ok)
+	end()
+
+	mgr.DeliverConfig(t, webID, newTestSnapshot(t, nil, "", nil))
+	retry.Run(t, func(r *retry.R) {
+		end, ok := server.partitionStreams.begin("default", server.MaxStreamsPerPartition)
+		if ok {
+			end()
+		}
+		require.False(r, ok)
+	})
+
+	// A second proxy in the same partition is over the limit.
+	envoy2 := NewTestEnvoy(t, "db-sidecar-proxy", "")
+	t.Cleanup(func() { envoy2.Close() })
+
+	errCh2 := make(chan error, 1)
+	go func() {
+		errCh2 <- server.DeltaAggregatedResources(envoy2.deltaStream)
+	}()
+	envoy2.SendDeltaReq(t, xdscommon.ClusterType, nil)
+	mgr.DeliverConfig(t, dbID, newTestSnapshot(t, nil, "", nil))
+
+	select {
+	case err := <-errCh2:
+		require.Equal(t, errOverwhelmed, err)
+	case <-time.After(200 * time.Millisecond):
+		t.Fatalf("timed out waiting for handler to finish")
+	}
+
+	retry.Run(t, func(r *retry.R) {
+		data := scenario.sink.Data()
+		require.Len(r, data, 1)
+
+		val, ok := data[0].Counters["consul.xds.test.xds.server.partitionStreamLimitReached;partition=default"]
+		require.True(r, ok)
+		require.Equal(r, 1, val.Count)
+	})
+
+	// Closing the first stream frees its slot.
+	envoy.Close()
+	select {
+	case err := <-errCh:
+		require.NoError(t, err)
+	case <-time.After(50 * time.Millisecond):
+		t.Fatalf("timed out waiting for handler to finish")
+	}
+
+	end, ok = server.partitionStreams.begin("default", server.MaxStreamsPerPartition)
+	require.True(t, ok)
+	end()
+}
+
+func TestPartitionStreamCounters(t *testing.T) {
+	var c partitionStreamCounters
+
+	endA, ok := c.begin("a", 2)
+	require.True(t, ok)
+	_, ok = c.begin("a", 2)
+	require.True(t, ok)
+
+	// Partition a is full.
+	_, ok = c.begin("a", 2)
+	require.False(t, ok)
+
+	// Other partitions are not affected.
+	endB, ok := c.begin("b", 2)
+	require.True(t, ok)
+
+	// Ending a stream frees its slot, even if it is ended more than once.
+	endA()
+	endA()
+	_, ok = c.begin("a", 2)
+	require.True(t, ok)
+	_, ok = c.begin("a", 2)
+	require.False(t, ok)
+
+	endB()
+	require.NotContains(t, c.counts, "b")
+
+	// Zero means no limit.
+	for i := 0; i < 10; i++ {
+		_, ok = c.begin("c", 0)
+		require.True(t, ok)
+	}
 }
 
 func TestServer_DeltaAggregatedResources_v3_BasicProtocol_HTTP2(t *testing.T) {
@@ -902,12 +1348,12 @@ func TestServer_DeltaAggregatedResources_v3_BasicProtocol_TCP_clusterChangeBefor
 		envoy.SendDeltaReqACK(t, xdscommon.ClusterType, 1)
 
 		// The updated cluster snapshot with new certificates is sent immediately
-		// after the first is ACKed.
+		// after the first is ACKed. The CA rotation resends every cluster.
 		assertDeltaResponseSent(t, envoy.deltaStream.sendCh, &envoy_discovery_v3.DeltaDiscoveryResponse{
 			TypeUrl: xdscommon.ClusterType,
 			Nonce:   hexString(3),
 			Resources: makeTestResources(t,
-				// SAME makeTestCluster(t, snap, "tcp:local_app"),
+				makeTestCluster(t, newSnap, "tcp:local_app"),
 				makeTestCluster(t, newSnap, "tcp:db"),
 				makeTestCluster(t, newSnap, "tcp:geo-cache"),
 			),
@@ -1327,6 +1773,216 @@ func TestServer_DeltaAggregatedResources_v3_ACLEnforcement(t *testing.T) {
 	}
 }
 
+func TestServer_DeltaAggregatedResources_v3_SecretsRequireMeshRead(t *testing.T) {
+	secret := &envoy_tls_v3.Secret{Name: "gateway-cert"}
+
+	tests := []struct {
+		name        string
+		acl         string
+		wantSecrets bool
+	}{
+		{
+			name:        "service write",
+			acl:         `service "web" { policy = "write" }`,
+			wantSecrets: false,
+		},
+		{
+			name:        "service write and mesh read",
+			acl:         `service "web" { policy = "write" } mesh = "read"`,
+			wantSecrets: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := acl.NewPolicyFromSource(tt.acl, nil, nil)
+			require.NoError(t, err)
+			aclResolve := func(id string) (acl.Authorizer, error) {
+				return acl.NewPolicyAuthorizerWithDefaults(acl.RootAuthorizer("deny"), []*acl.Policy{policy}, nil)
+			}
+
+			scenario := newTestServerDeltaScenario(t, aclResolve, "web-sidecar-proxy", "token", 0, func(s *Server) {
+				s.SecretsRequireMeshRead = true
+				s.ResourceMapMutateFn = func(resourceMap *xdscommon.IndexedResources) {
+					resourceMap.Index[xdscommon.SecretType][secret.Name] = secret
+				}
+			})
+			mgr, errCh, envoy := scenario.mgr, scenario.errCh, scenario.envoy
+
+			sid := structs.NewServiceID("web-sidecar-proxy", nil)
+			mgr.RegisterProxy(t, sid)
+
+			snap := newTestSnapshot(t, nil, "", nil)
+
+			envoy.SendDeltaReq(t, xdscommon.ClusterType, nil)
+			mgr.DeliverConfig(t, sid, snap)
+
+			// Clusters flow regardless of the secrets permission.
+			assertDeltaResponseSent(t, envoy.deltaStream.sendCh, &envoy_discovery_v3.DeltaDiscoveryResponse{
+				TypeUrl: xdscommon.ClusterType,
+				Nonce:   hexString(1),
+				Resources: makeTestResources(t,
+					makeTestCluster(t, snap, "tcp:local_app"),
+					makeTestCluster(t, snap, "tcp:db"),
+					makeTestCluster(t, snap, "tcp:geo-cache"),
+				),
+			})
+
+			envoy.SendDeltaReq(t, xdscommon.SecretType, &envoy_discovery_v3.DeltaDiscoveryRequest{
+				ResourceNamesSubscribe: []string{secret.Name},
+			})
+
+			want := &envoy_discovery_v3.DeltaDiscoveryResponse{
+				TypeUrl: xdscommon.SecretType,
+				Nonce:   hexString(2),
+			}
+			if tt.wantSecrets {
+				want.Resources = makeTestResources(t, secret)
+			}
+			assertDeltaResponseSent(t, envoy.deltaStream.sendCh, want)
+			assertDeltaChanBlocked(t, envoy.deltaStream.sendCh)
+
+			envoy.Close()
+			select {
+			case err := <-errCh:
+				require.NoError(t, err)
+			case <-time.After(50 * time.Millisecond):
+				t.Fatalf("timed out waiting for handler to finish")
+			}
+		})
+	}
+}
+
+func TestServer_DeltaAggregatedResources_v3_CARotationResendsTLSResources(t *testing.T) {
+	aclResolve := func(id string) (acl.Authorizer, error) {
+		// Allow all
+		return acl.RootAuthorizer("manage"), nil
+	}
+	scenario := newTestServerDeltaScenario(t, aclResolve, "web-sidecar-proxy", "", 0)
+	mgr, errCh, envoy := scenario.mgr, scenario.errCh, scenario.envoy
+
+	sid := structs.NewServiceID("web-sidecar-proxy", nil)
+
+	// Register the proxy to create state needed to Watch() on
+	mgr.RegisterProxy(t, sid)
+
+	var snap *proxycfg.ConfigSnapshot
+	testutil.RunStep(t, "get into initial state", func(t *testing.T) {
+		snap = newTestSnapshot(t, nil, "", nil)
+
+		envoy.SendDeltaReq(t, xdscommon.ClusterType, &envoy_discovery_v3.DeltaDiscoveryRequest{})
+		assertDeltaChanBlocked(t, envoy.deltaStream.sendCh)
+
+		mgr.DeliverConfig(t, sid, snap)
+
+		assertDeltaResponseSent(t, envoy.deltaStream.sendCh, &envoy_discovery_v3.DeltaDiscoveryResponse{
+			TypeUrl: xdscommon.ClusterType,
+			Nonce:   hexString(1),
+			Resources: makeTestResources(t,
+				makeTestCluster(t, snap, "tcp:local_app"),
+				makeTestCluster(t, snap, "tcp:db"),
+				makeTestCluster(t, snap, "tcp:geo-cache"),
+			),
+		})
+
+		envoy.SendDeltaReq(t, xdscommon.EndpointType, &envoy_discovery_v3.DeltaDiscoveryRequest{
+			ResourceNamesSubscribe: []string{
+				"db.default.dc1.internal.11111111-2222-3333-4444-555555555555.consul",
+				"geo-cache.default.dc1.query.11111111-2222-3333-4444-555555555555.consul",
+			},
+		})
+
+		assertDeltaResponseSent(t, envoy.deltaStream.sendCh, &envoy_discovery_v3.DeltaDiscoveryResponse{
+			TypeUrl: xdscommon.EndpointType,
+			Nonce:   hexString(2),
+			Resources: makeTestResources(t,
+				makeTestEndpoints(t, snap, "tcp:db"),
+				makeTestEndpoints(t, snap, "tcp:geo-cache"),
+			),
+		})
+
+		envoy.SendDeltaReqACK(t, xdscommon.ClusterType, 1)
+		assertDeltaChanBlocked(t, envoy.deltaStream.sendCh)
+
+		envoy.SendDeltaReq(t, xdscommon.ListenerType, nil)
+		envoy.SendDeltaReqACK(t, xdscommon.EndpointType, 2)
+
+		assertDeltaResponseSent(t, envoy.deltaStream.sendCh, &envoy_discovery_v3.DeltaDiscoveryResponse{
+			TypeUrl: xdscommon.ListenerType,
+			Nonce:   hexString(3),
+			Resources: makeTestResources(t,
+				makeTestListener(t, snap, "tcp:public_listener"),
+				makeTestListener(t, snap, "tcp:db"),
+				makeTestListener(t, snap, "tcp:geo-cache"),
+			),
+		})
+
+		envoy.SendDeltaReqACK(t, xdscommon.ListenerType, 3)
+		assertDeltaChanBlocked(t, envoy.deltaStream.sendCh)
+	})
+
+	testutil.RunStep(t, "unchanged snapshot is not resent", func(t *testing.T) {
+		snap = newTestSnapshot(t, snap, "", nil)
+		mgr.DeliverConfig(t, sid, snap)
+
+		assertDeltaChanBlocked(t, envoy.deltaStream.sendCh)
+	})
+
+	testutil.RunStep(t, "clusters and listeners are resent after CA rotation", func(t *testing.T) {
+		// Only the active root changes, so none of the generated resources do.
+		snap = newTestSnapshot(t, snap, "", nil)
+		snap.Roots = snap.Roots.DeepCopy()
+		snap.Roots.ActiveRootID = "rotated-root"
+		mgr.DeliverConfig(t, sid, snap)
+
+		assertDeltaResponseSent(t, envoy.deltaStream.sendCh, &envoy_discovery_v3.DeltaDiscoveryResponse{
+			TypeUrl: xdscommon.ClusterType,
+			Nonce:   hexString(4),
+			Resources: makeTestResources(t,
+				makeTestCluster(t, snap, "tcp:local_app"),
+				makeTestCluster(t, snap, "tcp:db"),
+				makeTestCluster(t, snap, "tcp:geo-cache"),
+			),
+		})
+
+		// The endpoints of the resent clusters follow.
+		assertDeltaResponseSent(t, envoy.deltaStream.sendCh, &envoy_discovery_v3.DeltaDiscoveryResponse{
+			TypeUrl: xdscommon.EndpointType,
+			Nonce:   hexString(5),
+			Resources: makeTestResources(t,
+				makeTestEndpoints(t, snap, "tcp:db"),
+				makeTestEndpoints(t, snap, "tcp:geo-cache"),
+			),
+		})
+
+		// Listeners are sent once the clusters are ACKed.
+		envoy.SendDeltaReqACK(t, xdscommon.ClusterType, 4)
+		envoy.SendDeltaReqACK(t, xdscommon.EndpointType, 5)
+
+		assertDeltaResponseSent(t, envoy.deltaStream.sendCh, &envoy_discovery_v3.DeltaDiscoveryResponse{
+			TypeUrl: xdscommon.ListenerType,
+			Nonce:   hexString(6),
+			Resources: makeTestResources(t,
+				makeTestListener(t, snap, "tcp:public_listener"),
+				makeTestListener(t, snap, "tcp:db"),
+				makeTestListener(t, snap, "tcp:geo-cache"),
+			),
+		})
+
+		envoy.SendDeltaReqACK(t, xdscommon.ListenerType, 6)
+
+		assertDeltaChanBlocked(t, envoy.deltaStream.sendCh)
+	})
+
+	envoy.Close()
+	select {
+	case err := <-errCh:
+		require.NoError(t, err)
+	case <-time.After(50 * time.Millisecond):
+		t.Fatalf("timed out waiting for handler to finish")
+	}
+}
+
 func TestServer_DeltaAggregatedResources_v3_ACLTokenDeleted_StreamTerminatedDuringDiscoveryRequest(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -1636,6 +2292,56 @@ func TestServer_DeltaAggregatedResources_v3_CfgSrcTerminated(t *testing.T) {
 	}
 }
 
+func TestServer_DeltaAggregatedResources_v3_InitialConfigTimeout(t *testing.T) {
+	aclResolve := func(id string) (acl.Authorizer, error) { return acl.ManageAll(), nil }
+
+	for _, terminate := range []bool{false, true} {
+		t.Run(fmt.Sprintf("terminate=%t", terminate), func(t *testing.T) {
+			scenario := newTestServerDeltaScenario(t, aclResolve, "web-sidecar-proxy", "", 0, func(s *Server) {
+				s.InitialConfigTimeout = 20 * time.Millisecond
+				s.TerminateOnInitialConfigTimeout = terminate
+			})
+			errCh, envoy := scenario.errCh, scenario.envoy
+
+			// The proxy is never registered so no snapshot is delivered.
+			envoy.SendDeltaReq(t, xdscommon.ClusterType, nil)
+
+			if terminate {
+				select {
+				case err := <-errCh:
+					require.Error(t, err)
+					require.Equal(t, codes.FailedPrecondition.String(), status.Code(err).String())
+					require.Equal(t, errInitialConfigTimeout, err)
+				case <-time.After(200 * time.Millisecond):
+					t.Fatalf("timed out waiting for handler to finish")
+				}
+			}
+
+			retry.Run(t, func(r *retry.R) {
+				data := scenario.sink.Data()
+				require.Len(r, data, 1)
+
+				val, ok := data[0].Counters["consul.xds.test.xds.server.streamInitialConfigTimeout"]
+				require.True(r, ok)
+				require.Equal(r, 1, val.Count)
+			})
+
+			if !terminate {
+				// The stream is kept open and is only reported once.
+				assertDeltaChanBlocked(t, envoy.deltaStream.sendCh)
+
+				envoy.Close()
+				select {
+				case err := <-errCh:
+					require.NoError(t, err)
+				case <-time.After(50 * time.Millisecond):
+					t.Fatalf("timed out waiting for handler to finish")
+				}
+			}
+		})
+	}
+}
+
 type capacityReachedLimiter struct{}
 
 func (capacityReachedLimiter) BeginSession() (limiter.Session, error) {
@@ -1689,7 +2395,8 @@ func TestServer_DeltaAggregatedResources_v3_StreamDrained(t *testing.T) {
 		require.Len(t, data, 1)
 
 		item := data[0]
-		require.Len(t, item.Counters, 1)
+		// The drain counter and the counter for the initial cluster request.
+		require.Len(t, item.Counters, 2)
 
 		val, ok := item.Counters["consul.xds.test.xds.server.streamDrained"]
 		require.True(t, ok)
@@ -1897,6 +2604,67 @@ func Test_validateAndApplyEnvoyExtension_Validations(t *testing.T) {
 	}
 }
 
+func Test_validateAndApplyEnvoyExtension_PanicMetric(t *testing.T) {
+	sink := metrics.NewInmemSink(1*time.Minute, 1*time.Minute)
+	cfg := metrics.DefaultConfig("consul.xds.test")
+	cfg.EnableHostname = false
+	cfg.EnableRuntimeMetrics = false
+	metrics.NewGlobal(cfg, sink)
+	t.Cleanup(func() {
+		metrics.NewGlobal(cfg, &metrics.BlackholeSink{})
+	})
+
+	origConstruct := constructExtension
+	constructExtension = func(api.EnvoyExtension) (extensioncommon.EnvoyExtender, error) {
+		return &extensioncommon.BasicEnvoyExtender{
+			Extension: &maybePanicExtension{panicOnPatch: true},
+		}, nil
+	}
+	t.Cleanup(func() { constructExtension = origConstruct })
+
+	envoyVersion, _ := goversion.NewVersion("1.25.0")
+	consulVersion, _ := goversion.NewVersion("1.16.0")
+
+	snap := proxycfg.ConfigSnapshot{
+		Service: "api",
+		ProxyID: proxycfg.ProxyID{
+			ServiceID: structs.NewServiceID("api-sidecar-proxy", nil),
+		},
+	}
+	runtimeConfig := extensioncommon.RuntimeConfig{
+		Kind:        api.ServiceKindConnectProxy,
+		ServiceName: api.CompoundServiceName{Name: "api"},
+		Upstreams:   map[api.CompoundServiceName]*extensioncommon.UpstreamData{},
+		EnvoyExtension: api.EnvoyExtension{
+			Name:     "maybePanicExtension",
+			Required: false,
+		},
+	}
+	indexedResources := xdscommon.IndexResources(testutil.Logger(t), map[string][]proto.Message{
+		xdscommon.ListenerType: {
+			&envoy_listener_v3.Listener{Name: xdscommon.OutboundListenerName},
+		},
+	})
+
+	var logs bytes.Buffer
+	logger := hclog.New(&hclog.LoggerOptions{Output: &logs, Level: hclog.Error})
+
+	// The extension is not required so its failure is not returned, but it
+	// is still counted and logged.
+	resources, err := validateAndApplyEnvoyExtension(logger, &snap, indexedResources, runtimeConfig, envoyVersion, consulVersion)
+	require.NoError(t, err)
+	require.Equal(t, indexedResources, resources)
+
+	data := sink.Data()
+	require.Len(t, data, 1)
+	counter, ok := data[0].Counters["consul.xds.test.envoy_extension.panic;extension=maybePanicExtension;service=api"]
+	require.True(t, ok, "panic counter not found in %v", data[0].Counters)
+	require.Equal(t, 1, counter.Count)
+
+	require.Contains(t, logs.String(), "envoy extension panicked")
+	require.Contains(t, logs.String(), "proxy_service_id=api-sidecar-proxy")
+}
+
 func Test_applyEnvoyExtension_CanApply(t *testing.T) {
 	type testCase struct {
 		name     string
@@ -61,17 +61,37 @@ func (s *ResourceGenerator) listenersFromSnapshot(cfgSnap *proxycfg.ConfigSnapsh
 		return nil, errors.New("nil config given")
 	}
 
+	var (
+		resources []proto.Message
+		err       error
+	)
 	switch cfgSnap.Kind {
 	case structs.ServiceKindConnectProxy:
-		return s.listenersFromSnapshotConnectProxy(cfgSnap)
+		resources, err = s.listenersFromSnapshotConnectProxy(cfgSnap)
 	case structs.ServiceKindTerminatingGateway,
 		structs.ServiceKindMeshGateway,
 		structs.ServiceKindIngressGateway,
 		structs.ServiceKindAPIGateway:
-		return s.listenersFromSnapshotGateway(cfgSnap)
+		resources, err = s.listenersFromSnapshotGateway(cfgSnap)
 	default:
 		return nil, fmt.Errorf("Invalid service kind: %v", cfgSnap.Kind)
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	if drainTimeout := cfgSnap.GetXDSCommonConfig(s.Logger).GetListenerDrainTimeout(); drainTimeout != nil {
+		for _, resource := range resources {
+			listener, ok := resource.(*envoy_listener_v3.Listener)
+			if !ok {
+				continue
+			}
+			if err := setListenerDrainTimeout(listener, drainTimeout); err != nil {
+				return nil, fmt.Errorf("failed to set drain timeout on listener %q: %w", listener.Name, err)
+			}
+		}
+	}
+	return resources, nil
 }
 
 // listenersFromSnapshotConnectProxy returns the "listeners" for a connect proxy service
@@ -103,7 +123,7 @@ func (s *ResourceGenerator) listenersFromSnapshotConnectProxy(cfgSnap *proxycfg.
 
 		opts := makeListenerOpts{
 			name:       xdscommon.OutboundListenerName,
-			accessLogs: cfgSnap.Proxy.AccessLogs,
+			accessLogs: *cfgSnap.AccessLogs(),
 			addr:       "127.0.0.1",
 			port:       port,
 			direction:  envoy_core_v3.TrafficDirection_OUTBOUND,
@@ -176,7 +196,7 @@ func (s *ResourceGenerator) listenersFromSnapshotConnectProxy(cfgSnap *proxycfg.
 		// Generate the upstream listeners for when they are explicitly set with a local bind port or socket path
 		if upstreamCfg != nil && upstreamCfg.HasLocalPortOrSocket() {
 			filterChain, err := s.makeUpstreamFilterChain(filterChainOpts{
-				accessLogs:      &cfgSnap.Proxy.AccessLogs,
+				accessLogs:      cfgSnap.AccessLogs(),
 				routeName:       uid.EnvoyID(),
 				clusterName:     clusterName,
 				filterName:      filterName,
@@ -191,7 +211,7 @@ func (s *ResourceGenerator) listenersFromSnapshotConnectProxy(cfgSnap *proxycfg.
 
 			opts := makeListenerOpts{
 				name:       uid.EnvoyID(),
-				accessLogs: cfgSnap.Proxy.AccessLogs,
+				accessLogs: *cfgSnap.AccessLogs(),
 				direction:  envoy_core_v3.TrafficDirection_OUTBOUND,
 				logger:     s.Logger,
 				upstream:   upstreamCfg,
@@ -212,7 +232,7 @@ func (s *ResourceGenerator) listenersFromSnapshotConnectProxy(cfgSnap *proxycfg.
 		// as we do for explicit upstreams above.
 
 		filterChain, err := s.makeUpstreamFilterChain(filterChainOpts{
-			accessLogs:      &cfgSnap.Proxy.AccessLogs,
+			accessLogs:      cfgSnap.AccessLogs(),
 			routeName:       uid.EnvoyID(),
 			clusterName:     clusterName,
 			filterName:      filterName,
@@ -296,7 +316,7 @@ func (s *ResourceGenerator) listenersFromSnapshotConnectProxy(cfgSnap *proxycfg.
 			const name = "~http" // name used for the shared route name
 			routeName := clusterNameForDestination(cfgSnap, name, fmt.Sprintf("%d", svcConfig.Destination.Port), svcConfig.NamespaceOrDefault(), svcConfig.PartitionOrDefault())
 			filterChain, err := s.makeUpstreamFilterChain(filterChainOpts{
-				accessLogs:      &cfgSnap.Proxy.AccessLogs,
+				accessLogs:      cfgSnap.AccessLogs(),
 				routeName:       routeName,
 				filterName:      routeName,
 				protocol:        svcConfig.Protocol,
@@ -315,7 +335,7 @@ func (s *ResourceGenerator) listenersFromSnapshotConnectProxy(cfgSnap *proxycfg.
 				clusterName := clusterNameForDestination(cfgSnap, uid.Name, address, uid.NamespaceOrDefault(), uid.PartitionOrDefault())
 
 				filterChain, err := s.makeUpstreamFilterChain(filterChainOpts{
-					accessLogs:  &cfgSnap.Proxy.AccessLogs,
+					accessLogs:  cfgSnap.AccessLogs(),
 					routeName:   uid.EnvoyID(),
 					clusterName: clusterName,
 					filterName:  clusterName,
@@ -394,7 +414,7 @@ func (s *ResourceGenerator) listenersFromSnapshotConnectProxy(cfgSnap *proxycfg.
 		// Generate the upstream listeners for when they are explicitly set with a local bind port or socket path
 		if upstreamCfg != nil && upstreamCfg.HasLocalPortOrSocket() {
 			filterChain, err := s.makeUpstreamFilterChain(filterChainOpts{
-				accessLogs:  &cfgSnap.Proxy.AccessLogs,
+				accessLogs:  cfgSnap.AccessLogs(),
 				clusterName: clusterName,
 				filterName: fmt.Sprintf("%s.%s.%s",
 					upstreamCfg.DestinationName,
@@ -411,7 +431,7 @@ func (s *ResourceGenerator) listenersFromSnapshotConnectProxy(cfgSnap *proxycfg.
 
 			opts := makeListenerOpts{
 				name:       uid.EnvoyID(),
-				accessLogs: cfgSnap.Proxy.AccessLogs,
+				accessLogs: *cfgSnap.AccessLogs(),
 				direction:  envoy_core_v3.TrafficDirection_OUTBOUND,
 				logger:     s.Logger,
 				upstream:   upstreamCfg,
@@ -433,7 +453,7 @@ func (s *ResourceGenerator) listenersFromSnapshotConnectProxy(cfgSnap *proxycfg.
 		// as we do for explicit upstreams above.
 
 		filterChain, err := s.makeUpstreamFilterChain(filterChainOpts{
-			accessLogs:  &cfgSnap.Proxy.AccessLogs,
+			accessLogs:  cfgSnap.AccessLogs(),
 			routeName:   uid.EnvoyID(),
 			clusterName: clusterName,
 			filterName: fmt.Sprintf("%s.%s.%s",
@@ -500,7 +520,7 @@ func (s *ResourceGenerator) listenersFromSnapshotConnectProxy(cfgSnap *proxycfg.
 				filterName := fmt.Sprintf("%s.%s.%s.%s", uid.Name, uid.NamespaceOrDefault(), uid.PartitionOrDefault(), cfgSnap.Datacenter)
 
 				filterChain, err := s.makeUpstreamFilterChain(filterChainOpts{
-					accessLogs:  &cfgSnap.Proxy.AccessLogs,
+					accessLogs:  cfgSnap.AccessLogs(),
 					clusterName: "passthrough~" + sni,
 					filterName:  filterName,
 					protocol:    "tcp",
@@ -552,7 +572,7 @@ func (s *ResourceGenerator) listenersFromSnapshotConnectProxy(cfgSnap *proxycfg.
 			!meshConf.TransparentProxy.MeshDestinationsOnly {
 
 			filterChain, err := s.makeUpstreamFilterChain(filterChainOpts{
-				accessLogs:  &cfgSnap.Proxy.AccessLogs,
+				accessLogs:  cfgSnap.AccessLogs(),
 				clusterName: naming.OriginalDestinationClusterName,
 				filterName:  naming.OriginalDestinationClusterName,
 				protocol:    "tcp",
@@ -597,7 +617,7 @@ func (s *ResourceGenerator) listenersFromSnapshotConnectProxy(cfgSnap *proxycfg.
 
 		opts := makeListenerOpts{
 			name:       uid.EnvoyID(),
-			accessLogs: cfgSnap.Proxy.AccessLogs,
+			accessLogs: *cfgSnap.AccessLogs(),
 			direction:  envoy_core_v3.TrafficDirection_OUTBOUND,
 			logger:     s.Logger,
 			upstream:   u,
@@ -607,7 +627,7 @@ func (s *ResourceGenerator) listenersFromSnapshotConnectProxy(cfgSnap *proxycfg.
 
 		filterChain, err := s.makeUpstreamFilterChain(filterChainOpts{
 			// TODO (SNI partition) add partition for upstream SNI
-			accessLogs:  &cfgSnap.Proxy.AccessLogs,
+			accessLogs:  cfgSnap.AccessLogs(),
 			clusterName: connect.UpstreamSNI(u, "", cfgSnap.Datacenter, cfgSnap.Roots.TrustDomain),
 			filterName:  uid.EnvoyID(),
 			routeName:   uid.EnvoyID(),
@@ -1071,6 +1091,48 @@ func extractRdsResourceNames(listener *envoy_listener_v3.Listener) ([]string, er
 	return found, nil
 }
 
+// setListenerDrainTimeout sets the drain timeout on every http connection
+// manager filter of the listener so that in-flight requests are given time to
+// complete when the listener is updated or removed.
+func setListenerDrainTimeout(listener *envoy_listener_v3.Listener, drainTimeout *durationpb.Duration) error {
+	chains := listener.FilterChains
+	if listener.DefaultFilterChain != nil {
+		chains = append(chains[:len(chains):len(chains)], listener.DefaultFilterChain)
+	}
+
+	for chainIdx, chain := range chains {
+		for filterIdx, filter := range chain.Filters {
+			if filter.Name != httpConnectionManagerNewName {
+				continue
+			}
+
+			tc, ok := filter.ConfigType.(*envoy_listener_v3.Filter_TypedConfig)
+			if !ok {
+				return fmt.Errorf(
+					"filter chain %d has a %q filter %d with an unsupported config type: %T",
+					chainIdx,
+					filter.Name,
+					filterIdx,
+					filter.ConfigType,
+				)
+			}
+
+			var hcm envoy_http_v3.HttpConnectionManager
+			if err := tc.TypedConfig.UnmarshalTo(&hcm); err != nil {
+				return err
+			}
+			hcm.DrainTimeout = drainTimeout
+
+			newFilter, err := makeFilter(filter.Name, &hcm)
+			if err != nil {
+				return err
+			}
+			chain.Filters[filterIdx] = newFilter
+		}
+	}
+	return nil
+}
+
 // Locate the existing http connect manager L4 filter and inject our RBAC filter at the top.
 func injectHTTPFilterOnFilterChains(
 	listener *envoy_listener_v3.Listener,
@@ -1330,7 +1392,7 @@ func (s *ResourceGenerator) makeInboundListener(cfgSnap *proxycfg.ConfigSnapshot
 
 	opts := makeListenerOpts{
 		name:       name,
-		accessLogs: cfgSnap.Proxy.AccessLogs,
+		accessLogs: *cfgSnap.AccessLogs(),
 		addr:       addr,
 		port:       port,
 		direction:  envoy_core_v3.TrafficDirection_INBOUND,
@@ -1354,7 +1416,7 @@ func (s *ResourceGenerator) makeInboundListener(cfgSnap *proxycfg.ConfigSnapshot
 		requestTimeoutMs: cfg.LocalRequestTimeoutMs,
 		idleTimeoutMs:    cfg.LocalIdleTimeoutMs,
 		tracing:          tracing,
-		accessLogs:       &cfgSnap.Proxy.AccessLogs,
+		accessLogs:       cfgSnap.AccessLogs(),
 		logger:           s.Logger,
 	}
 	if useHTTPFilter {
@@ -1547,7 +1609,7 @@ func (s *ResourceGenerator) makeExposedCheckListener(cfgSnap *proxycfg.ConfigSna
 
 	listenerOpts := makeListenerOpts{
 		name:       listenerName,
-		accessLogs: cfgSnap.Proxy.AccessLogs,
+		accessLogs: *cfgSnap.AccessLogs(),
 		addr:       addr,
 		port:       path.ListenerPort,
 		direction:  envoy_core_v3.TrafficDirection_INBOUND,
@@ -1566,7 +1628,7 @@ func (s *ResourceGenerator) makeExposedCheckListener(cfgSnap *proxycfg.ConfigSna
 		statPrefix:       "",
 		routePath:        path.Path,
 		httpAuthzFilters: nil,
-		accessLogs:       &cfgSnap.Proxy.AccessLogs,
+		accessLogs:       cfgSnap.AccessLogs(),
 		logger:           s.Logger,
 		// in the exposed check listener we don't set the tracing configuration
 	}
@@ -1624,7 +1686,7 @@ func (s *ResourceGenerator) makeTerminatingGatewayListener(
 
 	listenerOpts := makeListenerOpts{
 		name:       name,
-		accessLogs: cfgSnap.Proxy.AccessLogs,
+		accessLogs: *cfgSnap.AccessLogs(),
 		addr:       addr,
 		port:       port,
 		direction:  envoy_core_v3.TrafficDirection_INBOUND,
@@ -1744,7 +1806,7 @@ func (s *ResourceGenerator) makeTerminatingGatewayListener(
 	// This fallback catch-all filter ensures a listener will be present for health checks to pass
 	// Envoy will reset these connections since known endpoints are caught by filter chain matches above
 	filterOpts := listenerFilterOpts{
-		accessLogs: &cfgSnap.Proxy.AccessLogs,
+		accessLogs: cfgSnap.AccessLogs(),
 		cluster:    "",
 		filterName: name,
 		logger:     s.Logger,
@@ -1860,7 +1922,7 @@ func (s *ResourceGenerator) makeFilterChainTerminatingGateway(cfgSnap *proxycfg.
 		statPrefix: "upstream.",
 		routePath:  "",
 		tracing:    tracing,
-		accessLogs: &cfgSnap.Proxy.AccessLogs,
+		accessLogs: cfgSnap.AccessLogs(),
 		logger:     s.Logger,
 	}
 
@@ -1918,7 +1980,7 @@ func (s *ResourceGenerator) makeMeshGatewayListener(name, addr string, port int,
 	// The cluster name here doesn't matter as the sni_cluster
 	// filter will fill it in for us.
 	filterOpts := listenerFilterOpts{
-		accessLogs: &cfgSnap.Proxy.AccessLogs,
+		accessLogs: cfgSnap.AccessLogs(),
 		cluster:    "",
 		filterName: name,
 		logger:     s.Logger,
@@ -1938,7 +2000,7 @@ func (s *ResourceGenerator) makeMeshGatewayListener(name, addr string, port int,
 
 	opts := makeListenerOpts{
 		name:       name,
-		accessLogs: cfgSnap.Proxy.AccessLogs,
+		accessLogs: *cfgSnap.AccessLogs(),
 		addr:       addr,
 		port:       port,
 		direction:  envoy_core_v3.TrafficDirection_UNSPECIFIED,
@@ -1972,7 +2034,7 @@ func (s *ResourceGenerator) makeMeshGatewayListener(name, addr string, port int,
 		filterName := fmt.Sprintf("%s.%s", name, key.String())
 
 		filterOpts := listenerFilterOpts{
-			accessLogs: &cfgSnap.Proxy.AccessLogs,
+			accessLogs: cfgSnap.AccessLogs(),
 			cluster:    clusterName,
 			filterName: filterName,
 			logger:     s.Logger,
@@ -2008,7 +2070,7 @@ func (s *ResourceGenerator) makeMeshGatewayListener(name, addr string, port int,
 			clusterName := cfgSnap.ServerSNIFn(key.Datacenter, "")
 			filterName := fmt.Sprintf("%s.%s", name, key.String())
 			filterOpts := listenerFilterOpts{
-				accessLogs: &cfgSnap.Proxy.AccessLogs,
+				accessLogs: cfgSnap.AccessLogs(),
 				cluster:    clusterName,
 				filterName: filterName,
 				logger:     s.Logger,
@@ -2036,7 +2098,7 @@ func (s *ResourceGenerator) makeMeshGatewayListener(name, addr string, port int,
 
 			filterName := fmt.Sprintf("%s.%s", name, cfgSnap.Datacenter)
 			filterOpts := listenerFilterOpts{
-				accessLogs: &cfgSnap.Proxy.AccessLogs,
+				accessLogs: cfgSnap.AccessLogs(),
 				cluster:    clusterName,
 				filterName: filterName,
 				logger:     s.Logger,
@@ -2074,7 +2136,7 @@ func (s *ResourceGenerator) makeMeshGatewayListener(name, addr string, port int,
 			filterName := fmt.Sprintf("%s.%s", name, cfgSnap.Datacenter)
 
 			filterOpts := listenerFilterOpts{
-				accessLogs: &cfgSnap.Proxy.AccessLogs,
+				accessLogs: cfgSnap.AccessLogs(),
 				cluster:    clusterName,
 				filterName: filterName,
 				logger:     s.Logger,
@@ -2102,7 +2164,7 @@ func (s *ResourceGenerator) makeMeshGatewayListener(name, addr string, port int,
 	for name := range cfgSnap.MeshGateway.PeerServers {
 
 		filterOpts := listenerFilterOpts{
-			accessLogs: &cfgSnap.Proxy.AccessLogs,
+			accessLogs: cfgSnap.AccessLogs(),
 			cluster:    name,
 			filterName: name,
 			logger:     s.Logger,
@@ -2165,7 +2227,7 @@ func (s *ResourceGenerator) makeMeshGatewayPeerFilterChain(
 	filterName := fmt.Sprintf("%s.%s.%s.%s", chain.ServiceName, chain.Namespace, chain.Partition, chain.Datacenter)
 
 	filterChain, err := s.makeUpstreamFilterChain(filterChainOpts{
-		accessLogs:           &cfgSnap.Proxy.AccessLogs,
+		accessLogs:           cfgSnap.AccessLogs(),
 		routeName:            uid.EnvoyID(),
 		clusterName:          clusterName,
 		filterName:           filterName,
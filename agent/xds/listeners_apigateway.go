@@ -87,7 +87,7 @@ func (s *ResourceGenerator) makeAPIGatewayListeners(address string, cfgSnap *pro
 
 			opts := makeListenerOpts{
 				name:       uid.EnvoyID(),
-				accessLogs: cfgSnap.Proxy.AccessLogs,
+				accessLogs: *cfgSnap.AccessLogs(),
 				addr:       address,
 				port:       u.LocalBindPort,
 				direction:  envoy_core_v3.TrafficDirection_OUTBOUND,
@@ -96,7 +96,7 @@ func (s *ResourceGenerator) makeAPIGatewayListeners(address string, cfgSnap *pro
 			l := makeListener(opts)
 
 			filterChain, err := s.makeUpstreamFilterChain(filterChainOpts{
-				accessLogs:      &cfgSnap.Proxy.AccessLogs,
+				accessLogs:      cfgSnap.AccessLogs(),
 				routeName:       uid.EnvoyID(),
 				useRDS:          useRDS,
 				fetchTimeoutRDS: cfgSnap.GetXDSCommonConfig(s.Logger).GetXDSFetchTimeout(),
@@ -126,7 +126,7 @@ func (s *ResourceGenerator) makeAPIGatewayListeners(address string, cfgSnap *pro
 						routeName:       listenerKey.RouteName(),
 						cluster:         clusterName,
 						statPrefix:      "ingress_upstream_",
-						accessLogs:      &cfgSnap.Proxy.AccessLogs,
+						accessLogs:      cfgSnap.AccessLogs(),
 						logger:          s.Logger,
 					},
 					certs,
@@ -148,7 +148,7 @@ func (s *ResourceGenerator) makeAPIGatewayListeners(address string, cfgSnap *pro
 			// If multiple upstreams share this port, make a special listener for the protocol.
 			listenerOpts := makeListenerOpts{
 				name:       listenerKey.Protocol,
-				accessLogs: cfgSnap.Proxy.AccessLogs,
+				accessLogs: *cfgSnap.AccessLogs(),
 				addr:       address,
 				port:       listenerKey.Port,
 				direction:  envoy_core_v3.TrafficDirection_OUTBOUND,
@@ -220,7 +220,7 @@ func (s *ResourceGenerator) makeAPIGatewayListeners(address string, cfgSnap *pro
 				statPrefix:       "ingress_upstream_",
 				routePath:        "",
 				httpAuthzFilters: authFilters,
-				accessLogs:       &cfgSnap.Proxy.AccessLogs,
+				accessLogs:       cfgSnap.AccessLogs(),
 				logger:           s.Logger,
 			}
 
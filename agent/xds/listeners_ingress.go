@@ -69,7 +69,7 @@ func (s *ResourceGenerator) makeIngressGatewayListeners(address string, cfgSnap
 
 			opts := makeListenerOpts{
 				name:       uid.EnvoyID(),
-				accessLogs: cfgSnap.Proxy.AccessLogs,
+				accessLogs: *cfgSnap.AccessLogs(),
 				addr:       address,
 				port:       u.LocalBindPort,
 				direction:  envoy_core_v3.TrafficDirection_OUTBOUND,
@@ -78,7 +78,7 @@ func (s *ResourceGenerator) makeIngressGatewayListeners(address string, cfgSnap
 			l := makeListener(opts)
 
 			filterChain, err := s.makeUpstreamFilterChain(filterChainOpts{
-				accessLogs:      &cfgSnap.Proxy.AccessLogs,
+				accessLogs:      cfgSnap.AccessLogs(),
 				routeName:       uid.EnvoyID(),
 				useRDS:          useRDS,
 				fetchTimeoutRDS: cfgSnap.GetXDSCommonConfig(s.Logger).GetXDSFetchTimeout(),
@@ -99,7 +99,7 @@ func (s *ResourceGenerator) makeIngressGatewayListeners(address string, cfgSnap
 			// If multiple upstreams share this port, make a special listener for the protocol.
 			listenerOpts := makeListenerOpts{
 				name:       listenerKey.Protocol,
-				accessLogs: cfgSnap.Proxy.AccessLogs,
+				accessLogs: *cfgSnap.AccessLogs(),
 				addr:       address,
 				port:       listenerKey.Port,
 				direction:  envoy_core_v3.TrafficDirection_OUTBOUND,
@@ -118,7 +118,7 @@ func (s *ResourceGenerator) makeIngressGatewayListeners(address string, cfgSnap
 				statPrefix:       "ingress_upstream_",
 				routePath:        "",
 				httpAuthzFilters: nil,
-				accessLogs:       &cfgSnap.Proxy.AccessLogs,
+				accessLogs:       cfgSnap.AccessLogs(),
 				logger:           s.Logger,
 			}
 
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	envoy_listener_v3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
 	envoy_rbac_v3 "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
@@ -71,6 +72,11 @@ func intentionListToIntermediateRBACForm(
 	trustBundlesByPeer map[string]*pbpeering.PeeringTrustBundle,
 	providerMap map[string]*structs.JWTProviderConfigEntry,
 ) ([]*rbacIntention, error) {
+	// Intentions outside of their NotBefore/NotAfter window are treated as if
+	// they didn't exist. The proxy state sends a new snapshot when the next
+	// window starts or ends, which rebuilds these rules.
+	intentions = intentions.Active(time.Now())
+
 	sort.Sort(structs.IntentionPrecedenceSorter(intentions))
 
 	// Omit any lower-precedence intentions that share the same source.
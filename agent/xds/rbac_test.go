@@ -9,6 +9,7 @@ import (
 	"regexp"
 	"sort"
 	"testing"
+	"time"
 
 	envoy_rbac_v3 "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
 	envoy_matcher_v3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
@@ -966,6 +967,43 @@ func TestMakeRBACNetworkAndHTTPFilters(t *testing.T) {
 	}
 }
 
+func TestIntentionListToIntermediateRBACForm_TimeWindow(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	testIntention := func(src string, action structs.IntentionAction) *structs.Intention {
+		ixn := structs.TestIntention(t)
+		ixn.SourceName = src
+		ixn.DestinationName = "api"
+		ixn.Action = action
+		//nolint:staticcheck
+		ixn.UpdatePrecedence()
+		return ixn
+	}
+
+	// An expired deny for web must not shadow the wildcard allow, and an
+	// allow for db that hasn't started yet must not be rendered at all.
+	expired := testIntention("web", structs.IntentionActionDeny)
+	expired.NotAfter = &past
+	pending := testIntention("db", structs.IntentionActionAllow)
+	pending.NotBefore = &future
+	wildcard := testIntention("*", structs.IntentionActionAllow)
+
+	ixns := structs.SimplifiedIntentions{expired, pending, wildcard}
+	localInfo := rbacLocalInfo{trustDomain: "test.consul", datacenter: "dc1"}
+
+	rixns, err := intentionListToIntermediateRBACForm(ixns, localInfo, false, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, rixns, 1)
+	require.Equal(t, "*", rixns[0].Source.Name)
+	require.Equal(t, intentionActionAllow, rixns[0].Action)
+	require.Empty(t, rixns[0].NotSources)
+
+	rules, err := makeRBACRules(ixns, false, localInfo, false, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, rules.Policies, 1)
+}
+
 func TestRemoveSameSourceIntentions(t *testing.T) {
 	testIntention := func(t *testing.T, src, dst string) *structs.Intention {
 		t.Helper()
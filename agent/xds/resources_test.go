@@ -313,6 +313,7 @@ func TestAllResourcesFromSnapshot(t *testing.T) {
 	tests = append(tests, getTLSGoldenTestCases()...)
 	tests = append(tests, getPeeredGoldenTestCases()...)
 	tests = append(tests, getXDSFetchTimeoutTestCases()...)
+	tests = append(tests, getListenerDrainTimeoutTestCases()...)
 
 	latestEnvoyVersion := xdscommon.EnvoyVersions[0]
 	for _, envoyVersion := range xdscommon.EnvoyVersions {
@@ -1855,6 +1856,53 @@ func getCustomConfigurationGoldenTestCases(enterprise bool) []goldenTestCase {
 				}, nil)
 			},
 		},
+		{
+			name: "mesh-passive-healthcheck",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshot(t, nil, []proxycfg.UpdateEvent{
+					{
+						CorrelationID: "mesh",
+						Result: &structs.ConfigEntryResponse{
+							Entry: &structs.MeshConfigEntry{
+								PassiveHealthCheck: &structs.PassiveHealthCheck{
+									Interval:                10 * time.Second,
+									MaxFailures:             5,
+									EnforcingConsecutive5xx: uint32ptr(80),
+									MaxEjectionPercent:      uint32ptr(50),
+									BaseEjectionTime:        durationPtr(20 * time.Second),
+								},
+							},
+						},
+					},
+				})
+			},
+		},
+		{
+			name: "mesh-passive-healthcheck-upstream-override",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshot(t, func(ns *structs.NodeService) {
+					ns.Proxy.Upstreams[0].Config["passive_health_check"] = map[string]interface{}{
+						"max_failures":         float64(2),
+						"max_ejection_percent": float64(100),
+					}
+				}, []proxycfg.UpdateEvent{
+					{
+						CorrelationID: "mesh",
+						Result: &structs.ConfigEntryResponse{
+							Entry: &structs.MeshConfigEntry{
+								PassiveHealthCheck: &structs.PassiveHealthCheck{
+									Interval:                10 * time.Second,
+									MaxFailures:             5,
+									EnforcingConsecutive5xx: uint32ptr(80),
+									MaxEjectionPercent:      uint32ptr(50),
+									BaseEjectionTime:        durationPtr(20 * time.Second),
+								},
+							},
+						},
+					},
+				})
+			},
+		},
 		{
 			name: "custom-max-inbound-connections",
 			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
@@ -2848,6 +2896,68 @@ func getAccessLogsGoldenTestCases() []goldenTestCase {
 					nil)
 			},
 		},
+		{
+			name: "access-logs-mesh-defaults",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshot(t, nil, []proxycfg.UpdateEvent{
+					{
+						CorrelationID: "mesh",
+						Result: &structs.ConfigEntryResponse{
+							Entry: &structs.MeshConfigEntry{
+								AccessLogs: &structs.AccessLogsConfig{
+									Enabled:    true,
+									Type:       structs.FileLogSinkType,
+									Path:       "/tmp/accesslog.txt",
+									JSONFormat: "{ \"custom_start_time\": \"%START_TIME%\" }",
+								},
+							},
+						},
+					},
+				})
+			},
+		},
+		{
+			name: "access-logs-proxy-defaults-override-mesh",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshot(t, func(ns *structs.NodeService) {
+					// This should be passed into the snapshot through proxy-defaults
+					ns.Proxy.AccessLogs = structs.AccessLogsConfig{
+						Enabled: true,
+					}
+				}, []proxycfg.UpdateEvent{
+					{
+						CorrelationID: "mesh",
+						Result: &structs.ConfigEntryResponse{
+							Entry: &structs.MeshConfigEntry{
+								AccessLogs: &structs.AccessLogsConfig{
+									Enabled: true,
+									Type:    structs.StdErrLogSinkType,
+								},
+							},
+						},
+					},
+				})
+			},
+		},
+		{
+			name: "access-logs-mesh-defaults-ingress-gateway",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshotIngressGateway(t, true, "tcp", "default", nil, nil, []proxycfg.UpdateEvent{
+					{
+						CorrelationID: "mesh",
+						Result: &structs.ConfigEntryResponse{
+							Entry: &structs.MeshConfigEntry{
+								AccessLogs: &structs.AccessLogsConfig{
+									Enabled:    true,
+									Type:       structs.StdOutLogSinkType,
+									TextFormat: "CUSTOM FORMAT %START_TIME%",
+								},
+							},
+						},
+					},
+				})
+			},
+		},
 	}
 }
 
@@ -3118,3 +3228,26 @@ func getXDSFetchTimeoutTestCases() []goldenTestCase {
 		},
 	}
 }
+
+func getListenerDrainTimeoutTestCases() []goldenTestCase {
+	return []goldenTestCase{
+		{
+			name: "listener-drain-timeout-ms-sidecar",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshotDiscoveryChain(t, "chain-and-router", false, func(ns *structs.NodeService) {
+					ns.Proxy.Config["listener_drain_timeout_ms"] = 7777
+				}, nil)
+			},
+		},
+		{
+			name: "listener-drain-timeout-ms-ingress-with-router",
+			create: func(t testinf.T) *proxycfg.ConfigSnapshot {
+				return proxycfg.TestConfigSnapshotIngressGateway(t, true, "http", "chain-and-router", func(ns *structs.NodeService) {
+					ns.Proxy.Config = map[string]interface{}{
+						"listener_drain_timeout_ms": 7777,
+					}
+				}, nil, nil)
+			},
+		},
+	}
+}
@@ -43,6 +43,22 @@ var (
 			Name: []string{"xds", "server", "streamDrained"},
 			Help: "Counts the number of xDS streams that are drained when rebalancing the load between servers.",
 		},
+		{
+			Name: []string{"envoy_extension", "panic"},
+			Help: "Counts the number of times an Envoy extension panicked while being applied to a proxy's xDS resources.",
+		},
+		{
+			Name: []string{"xds", "server", "streamInitialConfigTimeout"},
+			Help: "Counts the number of xDS streams that waited longer than the initial config timeout for the proxy's first configuration snapshot.",
+		},
+		{
+			Name: []string{"xds", "server", "deltaRequest"},
+			Help: "Counts the number of delta xDS requests received from proxies, labeled by type URL and by whether the request was an ACK, a NACK, or a spontaneous request such as a subscription change.",
+		},
+		{
+			Name: []string{"xds", "server", "partitionStreamLimitReached"},
+			Help: "Counts the number of xDS streams rejected because their admin partition reached its stream limit.",
+		},
 	}
 	StatsSummaries = []prometheus.SummaryDefinition{
 		{
@@ -73,6 +89,10 @@ const (
 	// DefaultAuthCheckFrequency is the default value for
 	// Server.AuthCheckFrequency to use when the zero value is provided.
 	DefaultAuthCheckFrequency = 5 * time.Minute
+
+	// DefaultInitialConfigTimeout is the default value for
+	// Server.InitialConfigTimeout.
+	DefaultInitialConfigTimeout = 1 * time.Minute
 )
 
 // ACLResolverFunc is a shim to resolve ACLs. Since ACL enforcement is so far
@@ -105,10 +125,52 @@ type Server struct {
 	// there has been no recent DiscoveryRequest).
 	AuthCheckFrequency time.Duration
 
+	// CaseInsensitiveResourceNames makes resource names that proxies
+	// subscribe to match resources regardless of case. It is off by default
+	// so subscriptions only match resources with exactly the same name.
+	CaseInsensitiveResourceNames bool
+
+	// ResourceDiffLogServiceIDs lists the service IDs of proxies whose xDS
+	// resource changes are logged whenever their config snapshot updates.
+	// Only resource names are logged, not their contents.
+	ResourceDiffLogServiceIDs []string
+
+	// SecretsRequireMeshRead withholds SDS secrets from proxies whose token
+	// lacks mesh:read, on top of the service:write that every xDS stream
+	// requires. Other resource types are still sent.
+	SecretsRequireMeshRead bool
+
+	// MinUpdateInterval is the minimum time between two update sweeps on a
+	// single stream. Config snapshots that arrive faster than this are
+	// coalesced so only the latest is sent once the interval elapses. Zero
+	// sends every snapshot as soon as it arrives.
+	MinUpdateInterval time.Duration
+
+	// InitialConfigTimeout is how long a stream may wait for the proxy's first
+	// config snapshot before it is reported, which usually means the proxy's
+	// service is not registered. Zero disables the check.
+	InitialConfigTimeout time.Duration
+
+	// TerminateOnInitialConfigTimeout closes streams that exceed
+	// InitialConfigTimeout with an error instead of only reporting them.
+	TerminateOnInitialConfigTimeout bool
+
+	// MaxStreamsPerPartition limits the number of concurrent delta streams
+	// from the proxies of any one admin partition, so that one partition
+	// can't use up the capacity of the server. Streams over the limit are
+	// rejected with errOverwhelmed. Zero means no limit.
+	MaxStreamsPerPartition int
+
 	// ResourceMapMutateFn exclusively exists for testing purposes.
 	ResourceMapMutateFn func(resourceMap *xdscommon.IndexedResources)
 
 	activeStreams *activeStreamCounters
+
+	// streams holds the status of the active delta streams for debugging.
+	streams streamRegistry
+
+	// partitionStreams counts the active delta streams of each partition.
+	partitionStreams partitionStreamCounters
 }
 
 // activeStreamCounters tracks various stream-related metrics.
@@ -151,13 +213,14 @@ func NewServer(
 	cfgFetcher configfetcher.ConfigFetcher,
 ) *Server {
 	return &Server{
-		NodeName:           nodeName,
-		Logger:             logger,
-		ProxyWatcher:       proxyWatcher,
-		ResolveToken:       resolveTokenSecret,
-		CfgFetcher:         cfgFetcher,
-		AuthCheckFrequency: DefaultAuthCheckFrequency,
-		activeStreams:      &activeStreamCounters{},
+		NodeName:             nodeName,
+		Logger:               logger,
+		ProxyWatcher:         proxyWatcher,
+		ResolveToken:         resolveTokenSecret,
+		CfgFetcher:           cfgFetcher,
+		AuthCheckFrequency:   DefaultAuthCheckFrequency,
+		InitialConfigTimeout: DefaultInitialConfigTimeout,
+		activeStreams:        &activeStreamCounters{},
 	}
 }
 
@@ -214,3 +277,26 @@ func (s *Server) authorize(ctx context.Context, snapshot *proxycfg.ConfigSnapsho
 
 	return snapshot.Authorize(authz)
 }
+
+// withheldTypes returns the xDS types whose resources must not be sent to the
+// proxy even though the token in ctx is authorized for the stream itself.
+func (s *Server) withheldTypes(ctx context.Context, snapshot *proxycfg.ConfigSnapshot) (map[string]bool, error) {
+	if !s.SecretsRequireMeshRead {
+		return nil, nil
+	}
+
+	authz, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var authzContext acl.AuthorizerContext
+	snapshot.ProxyID.EnterpriseMeta.FillAuthzContext(&authzContext)
+	if err := authz.ToAllowAuthorizer().MeshReadAllowed(&authzContext); err != nil {
+		if acl.IsErrPermissionDenied(err) {
+			return map[string]bool{xdscommon.SecretType: true}, nil
+		}
+		return nil, err
+	}
+	return nil, nil
+}
@@ -0,0 +1,140 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package xds
+
+import (
+	"sort"
+	"sync"
+)
+
+// StreamStatus describes the state of an active delta xDS stream. It is
+// intended for debugging proxies that are stuck or repeatedly NACKing.
+type StreamStatus struct {
+	// ProxyID is the ID of the proxy service on the other end of the
+	// stream. It is empty until the proxy has identified itself.
+	ProxyID string
+
+	// Types holds the status of each xDS type, keyed by type URL.
+	Types map[string]DeltaTypeStatus
+}
+
+// DeltaTypeStatus describes the state of a single xDS type on a stream.
+type DeltaTypeStatus struct {
+	// Registered is true once the proxy has requested this type.
+	Registered bool
+
+	// Wildcard is true if the proxy subscribed to all resources of this
+	// type rather than to specific names.
+	Wildcard bool
+
+	// Subscriptions is the number of resources the proxy subscribed to by
+	// name. It is always zero for wildcard subscriptions.
+	Subscriptions int
+
+	// PendingUpdates is the number of resource updates sent to the proxy
+	// that it has not yet ACKed.
+	PendingUpdates int
+
+	// LastNackError is the error the proxy sent with its most recent NACK
+	// for this type.
+	LastNackError string `json:",omitempty"`
+}
+
+// streamRegistry tracks the status of the active delta streams. Each stream
+// publishes a copy of its state so the registry can be read without
+// synchronizing with the stream's own goroutine.
+type streamRegistry struct {
+	lock    sync.Mutex
+	nextID  uint64
+	streams map[uint64]StreamStatus
+}
+
+// register adds a new stream and returns its ID.
+func (r *streamRegistry) register() uint64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.streams == nil {
+		r.streams = make(map[uint64]StreamStatus)
+	}
+	r.nextID++
+	r.streams[r.nextID] = StreamStatus{}
+	return r.nextID
+}
+
+// update replaces the status of the stream with the given ID.
+func (r *streamRegistry) update(id uint64, status StreamStatus) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.streams[id]; ok {
+		r.streams[id] = status
+	}
+}
+
+// deregister removes the stream with the given ID.
+func (r *streamRegistry) deregister(id uint64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	delete(r.streams, id)
+}
+
+// StreamStatuses returns the status of every active delta xDS stream,
+// sorted by proxy ID.
+func (s *Server) StreamStatuses() []StreamStatus {
+	s.streams.lock.Lock()
+	defer s.streams.lock.Unlock()
+
+	ids := make([]uint64, 0, len(s.streams.streams))
+	for id := range s.streams.streams {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	statuses := make([]StreamStatus, 0, len(ids))
+	for _, id := range ids {
+		statuses = append(statuses, s.streams.streams[id])
+	}
+	sort.SliceStable(statuses, func(i, j int) bool {
+		return statuses[i].ProxyID < statuses[j].ProxyID
+	})
+	return statuses
+}
+
+// partitionStreamCounters counts the active delta streams of each admin
+// partition so that they can be limited separately.
+type partitionStreamCounters struct {
+	lock   sync.Mutex
+	counts map[string]int
+}
+
+// begin accounts for a new stream in partition unless the partition already
+// has max streams, in which case it returns false. Zero means no limit. The
+// returned function must be called when the stream ends.
+func (c *partitionStreamCounters) begin(partition string, max int) (func(), bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if max > 0 && c.counts[partition] >= max {
+		return nil, false
+	}
+	if c.counts == nil {
+		c.counts = make(map[string]int)
+	}
+	c.counts[partition]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.lock.Lock()
+			defer c.lock.Unlock()
+
+			c.counts[partition]--
+			if c.counts[partition] == 0 {
+				delete(c.counts, partition)
+			}
+		})
+	}, true
+}
@@ -185,6 +185,7 @@ func newTestServerDeltaScenario(
 	proxyID string,
 	token string,
 	authCheckFrequency time.Duration,
+	serverOpts ...func(*Server),
 ) *testServerScenario {
 	mgr := newTestManager(t)
 	envoy := NewTestEnvoy(t, proxyID, token)
@@ -211,6 +212,9 @@ func newTestServerDeltaScenario(
 	if authCheckFrequency > 0 {
 		s.AuthCheckFrequency = authCheckFrequency
 	}
+	for _, opt := range serverOpts {
+		opt(s)
+	}
 
 	errCh := make(chan error, 1)
 	go func() {
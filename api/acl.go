@@ -51,6 +51,7 @@ type ACLToken struct {
 	TemplatedPolicies []*ACLTemplatedPolicy `json:",omitempty"`
 	Local             bool
 	AuthMethod        string        `json:",omitempty"`
+	WriteRateLimit    float64       `json:",omitempty"`
 	ExpirationTTL     time.Duration `json:",omitempty"`
 	ExpirationTime    *time.Time    `json:",omitempty"`
 	CreateTime        time.Time     `json:",omitempty"`
@@ -1175,6 +1176,44 @@ func (a *ACL) RoleCreate(role *ACLRole, q *WriteOptions) (*ACLRole, *WriteMeta,
 	return &out, wm, nil
 }
 
+// RoleCreateFromTemplate will create a new role from the named role template
+// configured on the servers, substituting the given variables. The policies
+// and identities of the template are added to those of the role parameter,
+// which must have a Name set and must not have an ID.
+func (a *ACL) RoleCreateFromTemplate(template string, role *ACLRole, variables map[string]string, q *WriteOptions) (*ACLRole, *WriteMeta, error) {
+	if role.ID != "" {
+		return nil, nil, fmt.Errorf("Cannot specify an ID in Role Creation")
+	}
+	if template == "" {
+		return nil, nil, fmt.Errorf("Must specify a role template name")
+	}
+
+	r := a.c.newRequest("PUT", "/v1/acl/role/template/"+url.PathEscape(template))
+	r.setWriteOptions(q)
+	r.obj = struct {
+		Role      *ACLRole
+		Variables map[string]string `json:",omitempty"`
+	}{
+		Role:      role,
+		Variables: variables,
+	}
+	rtt, resp, err := a.c.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, nil, err
+	}
+	wm := &WriteMeta{RequestTime: rtt}
+	var out ACLRole
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+
+	return &out, wm, nil
+}
+
 // RoleUpdate updates a role. The ID field of the role parameter must be set to an
 // existing role ID
 func (a *ACL) RoleUpdate(role *ACLRole, q *WriteOptions) (*ACLRole, *WriteMeta, error) {
@@ -995,6 +995,40 @@ func (a *Agent) UpdateTTLOpts(checkID, output, status string, q *QueryOptions) e
 	return nil
 }
 
+// AgentCheckOutput is the full output of the last run of a local check.
+type AgentCheckOutput struct {
+	CheckID string
+
+	// Output is the full output of the last run of the check.
+	Output string
+
+	// Truncated is true if the output stored in the catalog is a truncated
+	// copy of Output.
+	Truncated bool
+}
+
+// CheckOutput returns the full output of the last run of a check registered
+// with the local agent, even if the copy stored in the catalog was truncated
+// because of the agent's check_output_truncate_size.
+func (a *Agent) CheckOutput(checkID string, q *QueryOptions) (*AgentCheckOutput, error) {
+	r := a.c.newRequest("GET", "/v1/agent/check/output/"+checkID)
+	r.setQueryOptions(q)
+	_, resp, err := a.c.doRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, err
+	}
+
+	var out AgentCheckOutput
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
 // CheckRegister is used to register a new check with
 // the local agent
 func (a *Agent) CheckRegister(check *AgentCheckRegistration) error {
@@ -326,6 +326,10 @@ type QueryMeta struct {
 	// filtered out by enforcing ACLs. It may be false because nothing was
 	// removed, or because the endpoint does not yet support this flag.
 	ResultsFilteredByACLs bool
+
+	// PrimaryDatacenter is the primary datacenter of the servers that answered
+	// the query. It is only set when reading a single config entry.
+	PrimaryDatacenter string
 }
 
 // WriteMeta is used to return meta data about a write
@@ -1232,6 +1236,9 @@ func parseQueryMeta(resp *http.Response, q *QueryMeta) error {
 		q.ResultsFilteredByACLs = false
 	}
 
+	// Parse X-Consul-Primary-Datacenter
+	q.PrimaryDatacenter = header.Get("X-Consul-Primary-Datacenter")
+
 	// Parse Cache info
 	if cacheStr := header.Get("X-Cache"); cacheStr != "" {
 		q.CacheHit = strings.EqualFold(cacheStr, "HIT")
@@ -37,6 +37,9 @@ const (
 	InlineCertificate       string = "inline-certificate"
 	HTTPRoute               string = "http-route"
 	JWTProvider             string = "jwt-provider"
+	SessionDefaults         string = "session-defaults"
+	SessionDefaultsName     string = "session-defaults"
+	ConfigEntryDefaults     string = "config-entry-defaults"
 	LLMAgent                string = "llm-agent"
 	LLMAgentExternalServers string = "llm-agent-external-servers"
 )
@@ -442,6 +445,10 @@ func makeConfigEntry(kind, name string) (ConfigEntry, error) {
 		return &ServiceIntentionsConfigEntry{Kind: kind, Name: name}, nil
 	case MeshConfig:
 		return &MeshConfigEntry{}, nil
+	case SessionDefaults:
+		return &SessionDefaultsConfigEntry{}, nil
+	case ConfigEntryDefaults:
+		return &ConfigEntryDefaultsConfigEntry{Name: name}, nil
 	case ExportedServices:
 		return &ExportedServicesConfigEntry{Name: name}, nil
 	case SamenessGroup:
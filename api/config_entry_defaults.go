@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"encoding/json"
+)
+
+// ConfigEntryDefaultsConfigEntry holds a template of field values that newly
+// created config entries of another kind inherit unless they set the field
+// themselves.
+type ConfigEntryDefaultsConfigEntry struct {
+	// Name is the config entry kind the defaults apply to, for example
+	// "service-defaults".
+	Name string
+
+	// Partition is the partition the ConfigEntryDefaultsConfigEntry applies to.
+	// Partitioning is a Consul Enterprise feature.
+	Partition string `json:",omitempty"`
+
+	// Namespace is the namespace the ConfigEntryDefaultsConfigEntry applies to.
+	// Namespacing is a Consul Enterprise feature.
+	Namespace string `json:",omitempty"`
+
+	// Defaults holds the template, in the same format as the body of a config
+	// entry of the kind named by Name.
+	Defaults map[string]interface{} `json:",omitempty"`
+
+	Meta map[string]string `json:",omitempty"`
+
+	// CreateIndex is the Raft index this entry was created at. This is a
+	// read-only field.
+	CreateIndex uint64
+
+	// ModifyIndex is used for the Check-And-Set operations and can also be fed
+	// back into the WaitIndex of the QueryOptions in order to perform blocking
+	// queries.
+	ModifyIndex uint64
+}
+
+func (e *ConfigEntryDefaultsConfigEntry) GetKind() string            { return ConfigEntryDefaults }
+func (e *ConfigEntryDefaultsConfigEntry) GetName() string            { return e.Name }
+func (e *ConfigEntryDefaultsConfigEntry) GetPartition() string       { return e.Partition }
+func (e *ConfigEntryDefaultsConfigEntry) GetNamespace() string       { return e.Namespace }
+func (e *ConfigEntryDefaultsConfigEntry) GetMeta() map[string]string { return e.Meta }
+func (e *ConfigEntryDefaultsConfigEntry) GetCreateIndex() uint64     { return e.CreateIndex }
+func (e *ConfigEntryDefaultsConfigEntry) GetModifyIndex() uint64     { return e.ModifyIndex }
+
+// MarshalJSON adds the Kind field so that the JSON can be decoded back into the
+// correct type.
+func (e *ConfigEntryDefaultsConfigEntry) MarshalJSON() ([]byte, error) {
+	type Alias ConfigEntryDefaultsConfigEntry
+	source := &struct {
+		Kind string
+		*Alias
+	}{
+		Kind:  ConfigEntryDefaults,
+		Alias: (*Alias)(e),
+	}
+	return json.Marshal(source)
+}
@@ -305,6 +305,11 @@ type LoadBalancer struct {
 	// LeastRequestConfig contains configuration for the "least_request" policy type
 	LeastRequestConfig *LeastRequestConfig `json:",omitempty" alias:"least_request_config"`
 
+	// SlowStartConfig contains configuration for ramping up the traffic sent to
+	// new endpoints. Only supported by the "round_robin" and "least_request" policy
+	// types; an empty policy is treated as "round_robin".
+	SlowStartConfig *SlowStartConfig `json:",omitempty" alias:"slow_start_config"`
+
 	// HashPolicies is a list of hash policies to use for hashing load balancing algorithms.
 	// Hash policies are evaluated individually and combined such that identical lists
 	// result in the same hash.
@@ -328,6 +333,17 @@ type LeastRequestConfig struct {
 	ChoiceCount uint32 `json:",omitempty" alias:"choice_count"`
 }
 
+// SlowStartConfig contains configuration for ramping up the traffic sent to new
+// endpoints, so that instances which just became healthy are not overwhelmed.
+type SlowStartConfig struct {
+	// Window is how long a new endpoint takes to ramp up to its full weight.
+	Window time.Duration `json:",omitempty"`
+
+	// MinWeightPercent is the percentage of its full weight that a new endpoint
+	// starts with. Defaults to 10.
+	MinWeightPercent float64 `json:",omitempty" alias:"min_weight_percent"`
+}
+
 // HashPolicy defines which attributes will be hashed by hash-based LB algorithms
 type HashPolicy struct {
 	// Field is the attribute type to hash on.
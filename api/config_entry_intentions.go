@@ -32,6 +32,11 @@ type SourceIntention struct {
 	Type          IntentionSourceType
 	Description   string `json:",omitempty"`
 
+	// NotBefore and NotAfter optionally bound the time window in which this
+	// source intention is in effect.
+	NotBefore *time.Time `json:",omitempty" alias:"not_before"`
+	NotAfter  *time.Time `json:",omitempty" alias:"not_after"`
+
 	LegacyID         string            `json:",omitempty" alias:"legacy_id"`
 	LegacyMeta       map[string]string `json:",omitempty" alias:"legacy_meta"`
 	LegacyCreateTime *time.Time        `json:",omitempty" alias:"legacy_create_time"`
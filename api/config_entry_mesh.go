@@ -40,6 +40,14 @@ type MeshConfigEntry struct {
 
 	Peering *PeeringMeshConfig `json:",omitempty"`
 
+	// AccessLogs configures access logging for the listeners of all proxies and
+	// gateways in the mesh that don't configure access logs themselves.
+	AccessLogs *AccessLogsConfig `json:",omitempty" alias:"access_logs"`
+
+	// PassiveHealthCheck configures outlier detection for the upstream clusters
+	// of all proxies in the mesh. Values set for an upstream take precedence.
+	PassiveHealthCheck *PassiveHealthCheck `json:",omitempty" alias:"passive_health_check"`
+
 	Meta map[string]string `json:",omitempty"`
 
 	// CreateIndex is the Raft index this entry was created at. This is a
@@ -0,0 +1,57 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"encoding/json"
+)
+
+// SessionDefaultsConfigEntry manages the defaults applied to sessions created
+// in a namespace.
+type SessionDefaultsConfigEntry struct {
+	// Partition is the partition the SessionDefaultsConfigEntry applies to.
+	// Partitioning is a Consul Enterprise feature.
+	Partition string `json:",omitempty"`
+
+	// Namespace is the namespace the SessionDefaultsConfigEntry applies to.
+	// Namespacing is a Consul Enterprise feature.
+	Namespace string `json:",omitempty"`
+
+	// Behavior is the invalidation behavior, either "release" or "delete",
+	// given to sessions created without one.
+	Behavior string `json:",omitempty"`
+
+	Meta map[string]string `json:",omitempty"`
+
+	// CreateIndex is the Raft index this entry was created at. This is a
+	// read-only field.
+	CreateIndex uint64
+
+	// ModifyIndex is used for the Check-And-Set operations and can also be fed
+	// back into the WaitIndex of the QueryOptions in order to perform blocking
+	// queries.
+	ModifyIndex uint64
+}
+
+func (e *SessionDefaultsConfigEntry) GetKind() string            { return SessionDefaults }
+func (e *SessionDefaultsConfigEntry) GetName() string            { return SessionDefaultsName }
+func (e *SessionDefaultsConfigEntry) GetPartition() string       { return e.Partition }
+func (e *SessionDefaultsConfigEntry) GetNamespace() string       { return e.Namespace }
+func (e *SessionDefaultsConfigEntry) GetMeta() map[string]string { return e.Meta }
+func (e *SessionDefaultsConfigEntry) GetCreateIndex() uint64     { return e.CreateIndex }
+func (e *SessionDefaultsConfigEntry) GetModifyIndex() uint64     { return e.ModifyIndex }
+
+// MarshalJSON adds the Kind field so that the JSON can be decoded back into the
+// correct type.
+func (e *SessionDefaultsConfigEntry) MarshalJSON() ([]byte, error) {
+	type Alias SessionDefaultsConfigEntry
+	source := &struct {
+		Kind string
+		*Alias
+	}{
+		Kind:  SessionDefaults,
+		Alias: (*Alias)(e),
+	}
+	return json.Marshal(source)
+}
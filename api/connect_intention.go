@@ -60,6 +60,12 @@ type Intention struct {
 	// service-intentions config entry directly.
 	Permissions []*IntentionPermission `json:",omitempty"`
 
+	// NotBefore and NotAfter optionally bound the time window in which the
+	// intention is in effect. Outside of the window the intention is treated
+	// as if it did not exist.
+	NotBefore *time.Time `json:",omitempty"`
+	NotAfter  *time.Time `json:",omitempty"`
+
 	// DefaultAddr is not used.
 	// Deprecated: DefaultAddr is not used and may be removed in a future version.
 	DefaultAddr string `json:",omitempty"`
@@ -167,6 +173,43 @@ const (
 	IntentionMatchDestination IntentionMatchType = "destination"
 )
 
+// IntentionTopology is the graph of services a source service can reach once
+// both intentions and discovery chain routing are applied.
+type IntentionTopology struct {
+	Upstreams []*IntentionTopologyUpstream
+}
+
+// IntentionTopologyUpstream is a service that intentions allow the source to
+// connect to, along with the targets its discovery chain resolves to.
+type IntentionTopologyUpstream struct {
+	Name      string
+	Namespace string `json:",omitempty"`
+	Partition string `json:",omitempty"`
+
+	// Protocol is the protocol of the upstream's discovery chain.
+	Protocol string
+
+	// Targets are the targets that requests to the upstream may be routed,
+	// split or failed over to.
+	Targets []*IntentionTopologyTarget
+}
+
+// IntentionTopologyTarget is a single target of an upstream's discovery
+// chain.
+type IntentionTopologyTarget struct {
+	ID            string
+	Service       string
+	ServiceSubset string `json:",omitempty"`
+	Namespace     string `json:",omitempty"`
+	Partition     string `json:",omitempty"`
+	Datacenter    string `json:",omitempty"`
+	Peer          string `json:",omitempty"`
+
+	// Allowed is whether intentions allow the source to connect to the
+	// service of the target.
+	Allowed bool
+}
+
 // IntentionCheck are the arguments for the intention check API. For
 // more documentation see the IntentionCheck function.
 type IntentionCheck struct {
@@ -175,14 +218,39 @@ type IntentionCheck struct {
 	// may be other values as defined by the SourceType.
 	Source, Destination string
 
+	// SourcePeer is the cluster peer the source is imported from, if any.
+	SourcePeer string `json:",omitempty"`
+
 	// SourceType is the type of the value for the source.
 	SourceType IntentionSourceType
 }
 
+// IntentionCheckResult is the result of a single check in a batch. Error is
+// set instead of Allowed when the check could not be evaluated, for example
+// because the token is not allowed to read the destination service.
+type IntentionCheckResult struct {
+	Allowed bool
+	Error   string `json:",omitempty"`
+}
+
 // Intentions returns the list of intentions.
 func (h *Connect) Intentions(q *QueryOptions) ([]*Intention, *QueryMeta, error) {
+	return h.intentionList(false, q)
+}
+
+// IntentionsUnused returns the intentions that have not decided any intention
+// check served by the responding server since it started. Decisions made
+// locally by client agents or enforced by proxies are not counted.
+func (h *Connect) IntentionsUnused(q *QueryOptions) ([]*Intention, *QueryMeta, error) {
+	return h.intentionList(true, q)
+}
+
+func (h *Connect) intentionList(unused bool, q *QueryOptions) ([]*Intention, *QueryMeta, error) {
 	r := h.c.newRequest("GET", "/v1/connect/intentions")
 	r.setQueryOptions(q)
+	if unused {
+		r.params.Set("unused", "true")
+	}
 	rtt, resp, err := h.c.doRequest(r)
 	if err != nil {
 		return nil, nil, err
@@ -352,6 +420,9 @@ func (h *Connect) IntentionCheck(args *IntentionCheck, q *QueryOptions) (bool, *
 	r.setQueryOptions(q)
 	r.params.Set("source", args.Source)
 	r.params.Set("destination", args.Destination)
+	if args.SourcePeer != "" {
+		r.params.Set("source-peer", args.SourcePeer)
+	}
 	if args.SourceType != "" {
 		r.params.Set("source-type", string(args.SourceType))
 	}
@@ -375,6 +446,59 @@ func (h *Connect) IntentionCheck(args *IntentionCheck, q *QueryOptions) (bool, *
 	return out.Allowed, qm, nil
 }
 
+// IntentionCheckBatch is like IntentionCheck but tests many source and
+// destination pairs in a single request. The results are in the same order as
+// checks.
+func (h *Connect) IntentionCheckBatch(checks []*IntentionCheck, q *QueryOptions) ([]*IntentionCheckResult, *QueryMeta, error) {
+	r := h.c.newRequest("PUT", "/v1/connect/intentions/check-batch")
+	r.setQueryOptions(q)
+	r.obj = struct{ Checks []*IntentionCheck }{Checks: checks}
+	rtt, resp, err := h.c.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, nil, err
+	}
+
+	qm := &QueryMeta{}
+	parseQueryMeta(resp, qm)
+	qm.RequestTime = rtt
+
+	var out struct{ Results []*IntentionCheckResult }
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+	return out.Results, qm, nil
+}
+
+// IntentionTopology returns the services that the given service can reach
+// once both intentions and discovery chain routing and failover are applied.
+func (h *Connect) IntentionTopology(service string, q *QueryOptions) (*IntentionTopology, *QueryMeta, error) {
+	r := h.c.newRequest("GET", "/v1/connect/intentions/topology")
+	r.setQueryOptions(q)
+	r.params.Set("service", service)
+	rtt, resp, err := h.c.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, nil, err
+	}
+
+	qm := &QueryMeta{}
+	parseQueryMeta(resp, qm)
+	qm.RequestTime = rtt
+
+	var out IntentionTopology
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+	return &out, qm, nil
+}
+
 // IntentionUpsert will update an existing intention. The Source & Destination parameters
 // in the structure must be non-empty. The ID must be empty.
 func (c *Connect) IntentionUpsert(ixn *Intention, q *WriteOptions) (*WriteMeta, error) {
@@ -410,6 +534,34 @@ func maybePrefixNamespaceAndPartition(part, ns, name string) string {
 	}
 }
 
+// IntentionImport writes an intention previously read from Consul, such as
+// one produced by "consul intention export". If the intention has an ID it is
+// recreated as a legacy intention keeping its ID and its CreatedAt and
+// UpdatedAt timestamps, and that ID is returned. Otherwise it is upserted
+// like IntentionUpsert and the returned ID is empty.
+func (c *Connect) IntentionImport(ixn *Intention, q *WriteOptions) (string, *WriteMeta, error) {
+	r := c.c.newRequest("PUT", "/v1/connect/intentions/import")
+	r.setWriteOptions(q)
+	r.obj = ixn
+	rtt, resp, err := c.c.doRequest(r)
+	if err != nil {
+		return "", nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return "", nil, err
+	}
+
+	wm := &WriteMeta{}
+	wm.RequestTime = rtt
+
+	var out struct{ ID string }
+	if err := decodeBody(resp, &out); err != nil {
+		return "", nil, err
+	}
+	return out.ID, wm, nil
+}
+
 // IntentionCreate will create a new intention. The ID in the given
 // structure must be empty and a generate ID will be returned on
 // success.
@@ -183,6 +183,18 @@ func TestAPI_ConnectIntentionCheck(t *testing.T) {
 		require.NoError(t, err)
 		require.True(t, result)
 	}
+
+	// Check both at once
+	{
+		results, _, err := connect.IntentionCheckBatch([]*IntentionCheck{
+			{Source: "default/qux", Destination: "default/bar"},
+			{Source: "default/foo", Destination: "default/bar"},
+		}, nil)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		require.False(t, results[0].Allowed)
+		require.True(t, results[1].Allowed)
+	}
 }
 
 func testIntention() *Intention {
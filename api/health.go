@@ -77,6 +77,9 @@ type HealthCheckDefinition struct {
 	DeregisterCriticalServiceAfterDuration time.Duration `json:"-"`
 	// when parent Type is `session`, and if this session is destroyed, the check will be marked as critical
 	SessionName string `json:",omitempty"`
+	// when parent Type is `dependency`, the check is critical while this
+	// service has no passing instances
+	DependencyService string `json:",omitempty"`
 
 	// DEPRECATED in Consul 1.4.1. Use the above time.Duration fields instead.
 	Interval                       ReadableDuration
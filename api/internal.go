@@ -65,3 +65,45 @@ func (i *Internal) AssignServiceVirtualIP(
 	}
 	return &out, qm, nil
 }
+
+// ConfigEntryReplicationStatus is the status of the replication of config
+// entries from the primary datacenter.
+type ConfigEntryReplicationStatus struct {
+	// Enabled is true in secondary datacenters.
+	Enabled bool
+
+	// Running is true if the leader is currently replicating.
+	Running bool
+
+	// SourceDatacenter is the datacenter the config entries are replicated
+	// from.
+	SourceDatacenter string
+
+	// ReplicatedIndex is the index in the source datacenter up to which the
+	// config entries were replicated.
+	ReplicatedIndex uint64
+}
+
+// ConfigEntryReplicationStatus returns the status of the replication of
+// config entries in the queried datacenter.
+func (i *Internal) ConfigEntryReplicationStatus(q *QueryOptions) (*ConfigEntryReplicationStatus, *QueryMeta, error) {
+	r := i.c.newRequest("GET", "/v1/internal/config-entry-replication")
+	r.setQueryOptions(q)
+	rtt, resp, err := i.c.doRequest(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, nil, err
+	}
+
+	qm := &QueryMeta{RequestTime: rtt}
+	parseQueryMeta(resp, qm)
+
+	var out ConfigEntryReplicationStatus
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, nil, err
+	}
+	return &out, qm, nil
+}
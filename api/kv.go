@@ -5,11 +5,13 @@ package api
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // KVPair is used to represent a single K/V entry
@@ -44,6 +46,14 @@ type KVPair struct {
 	// session ID.
 	Session string
 
+	// ExpirationTTL is how long the key lives after it was last written, after
+	// which the leader deletes it. The zero value means the key never expires.
+	ExpirationTTL time.Duration `json:",omitempty"`
+
+	// ExpirationTime is the point after which the key is deleted. This is a
+	// read-only field that is set from ExpirationTTL when the key is written.
+	ExpirationTime *time.Time `json:",omitempty"`
+
 	// Namespace is the namespace the KVPair is associated with
 	// Namespacing is a Consul Enterprise feature.
 	Namespace string `json:",omitempty"`
@@ -90,7 +100,53 @@ func (k *KV) Get(key string, q *QueryOptions) (*KVPair, *QueryMeta, error) {
 
 // List is used to lookup all keys under a prefix
 func (k *KV) List(prefix string, q *QueryOptions) (KVPairs, *QueryMeta, error) {
-	resp, qm, err := k.getInternal(prefix, map[string]string{"recurse": ""}, q)
+	return k.list(prefix, map[string]string{"recurse": ""}, q)
+}
+
+// ListWithFlags is like List but only returns the key/value pairs whose
+// flags equal the given value. The filtering is done by the servers. Agents
+// older than the filter ignore it, so an error is returned if any of the
+// returned pairs has different flags.
+func (k *KV) ListWithFlags(prefix string, flags uint64, q *QueryOptions) (KVPairs, *QueryMeta, error) {
+	params := map[string]string{
+		"recurse": "",
+		"flags":   strconv.FormatUint(flags, 10),
+	}
+	entries, qm, err := k.list(prefix, params, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, entry := range entries {
+		if entry.Flags != flags {
+			return nil, nil, fmt.Errorf("Unexpected response: the flags filter was not applied, the agent may not support it")
+		}
+	}
+	return entries, qm, nil
+}
+
+// ListWithSession is like List but only returns the key/value pairs locked
+// by the given session. The servers look the keys up by session, so the rest
+// of the store is not read. Agents older than the filter ignore it, so an
+// error is returned if any of the returned pairs is not locked by the session.
+func (k *KV) ListWithSession(prefix, session string, q *QueryOptions) (KVPairs, *QueryMeta, error) {
+	params := map[string]string{
+		"recurse": "",
+		"session": session,
+	}
+	entries, qm, err := k.list(prefix, params, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, entry := range entries {
+		if entry.Session != session {
+			return nil, nil, fmt.Errorf("Unexpected response: the session filter was not applied, the agent may not support it")
+		}
+	}
+	return entries, qm, nil
+}
+
+func (k *KV) list(prefix string, params map[string]string, q *QueryOptions) (KVPairs, *QueryMeta, error) {
+	resp, qm, err := k.getInternal(prefix, params, q)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -129,6 +185,43 @@ func (k *KV) Keys(prefix, separator string, q *QueryOptions) ([]string, *QueryMe
 	return entries, qm, nil
 }
 
+// KeysFunc is like Keys but calls fn with each key as it is decoded from the
+// response instead of collecting them, so the keys under very large prefixes
+// don't have to be held in memory at once. Decoding stops at the first error
+// returned by fn, which is then returned.
+func (k *KV) KeysFunc(prefix, separator string, q *QueryOptions, fn func(key string) error) (*QueryMeta, error) {
+	params := map[string]string{"keys": ""}
+	if separator != "" {
+		params["separator"] = separator
+	}
+	resp, qm, err := k.getInternal(prefix, params, q)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return qm, nil
+	}
+	defer closeResponseBody(resp)
+
+	dec := json.NewDecoder(resp.Body)
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	for dec.More() {
+		var key string
+		if err := dec.Decode(&key); err != nil {
+			return nil, err
+		}
+		if err := fn(key); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return qm, nil
+}
+
 func (k *KV) getInternal(key string, params map[string]string, q *QueryOptions) (*http.Response, *QueryMeta, error) {
 	r := k.c.newRequest("GET", "/v1/kv/"+strings.TrimPrefix(key, "/"))
 	r.setQueryOptions(q)
@@ -164,6 +257,9 @@ func (k *KV) Put(p *KVPair, q *WriteOptions) (*WriteMeta, error) {
 	if p.Flags != 0 {
 		params["flags"] = strconv.FormatUint(p.Flags, 10)
 	}
+	if p.ExpirationTTL != 0 {
+		params["ttl"] = p.ExpirationTTL.String()
+	}
 	_, wm, err := k.put(p.Key, params, p.Value, q)
 	return wm, err
 }
@@ -176,6 +272,9 @@ func (k *KV) CAS(p *KVPair, q *WriteOptions) (bool, *WriteMeta, error) {
 	if p.Flags != 0 {
 		params["flags"] = strconv.FormatUint(p.Flags, 10)
 	}
+	if p.ExpirationTTL != 0 {
+		params["ttl"] = p.ExpirationTTL.String()
+	}
 	params["cas"] = strconv.FormatUint(p.ModifyIndex, 10)
 	return k.put(p.Key, params, p.Value, q)
 }
@@ -188,6 +287,9 @@ func (k *KV) Acquire(p *KVPair, q *WriteOptions) (bool, *WriteMeta, error) {
 	if p.Flags != 0 {
 		params["flags"] = strconv.FormatUint(p.Flags, 10)
 	}
+	if p.ExpirationTTL != 0 {
+		params["ttl"] = p.ExpirationTTL.String()
+	}
 	params["acquire"] = p.Session
 	return k.put(p.Key, params, p.Value, q)
 }
@@ -200,10 +302,30 @@ func (k *KV) Release(p *KVPair, q *WriteOptions) (bool, *WriteMeta, error) {
 	if p.Flags != 0 {
 		params["flags"] = strconv.FormatUint(p.Flags, 10)
 	}
+	if p.ExpirationTTL != 0 {
+		params["ttl"] = p.ExpirationTTL.String()
+	}
 	params["release"] = p.Session
 	return k.put(p.Key, params, p.Value, q)
 }
 
+// TransferLock is used to hand a lock held by the holder session over to
+// p.Session without the key being unlocked in between. The Key, Flags, Value
+// and Session are respected. Returns true on success or false if holder
+// doesn't hold the lock.
+func (k *KV) TransferLock(p *KVPair, holder string, q *WriteOptions) (bool, *WriteMeta, error) {
+	params := make(map[string]string, 3)
+	if p.Flags != 0 {
+		params["flags"] = strconv.FormatUint(p.Flags, 10)
+	}
+	if p.ExpirationTTL != 0 {
+		params["ttl"] = p.ExpirationTTL.String()
+	}
+	params["acquire"] = p.Session
+	params["transfer-from"] = holder
+	return k.put(p.Key, params, p.Value, q)
+}
+
 func (k *KV) put(key string, params map[string]string, body []byte, q *WriteOptions) (bool, *WriteMeta, error) {
 	if len(key) > 0 && key[0] == '/' {
 		return false, nil, fmt.Errorf("Invalid key. Key must not begin with a '/': %s", key)
@@ -257,6 +379,36 @@ func (k *KV) DeleteTree(prefix string, w *WriteOptions) (*WriteMeta, error) {
 	return qm, err
 }
 
+// DeleteTreeWithCount is used to delete all keys under a prefix. It returns
+// the number of keys deleted, which is -1 if the agent is too old to report
+// it.
+func (k *KV) DeleteTreeWithCount(prefix string, w *WriteOptions) (int, *WriteMeta, error) {
+	r := k.c.newRequest("DELETE", "/v1/kv/"+strings.TrimPrefix(prefix, "/"))
+	r.setWriteOptions(w)
+	r.params.Set("recurse", "")
+	rtt, resp, err := k.c.doRequest(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return 0, nil, err
+	}
+
+	wm := &WriteMeta{}
+	wm.RequestTime = rtt
+
+	header := resp.Header.Get("X-Consul-KV-Deleted")
+	if header == "" {
+		return -1, wm, nil
+	}
+	deleted, err := strconv.Atoi(header)
+	if err != nil {
+		return 0, nil, fmt.Errorf("Failed to parse X-Consul-KV-Deleted: %v", err)
+	}
+	return deleted, wm, nil
+}
+
 func (k *KV) deleteInternal(key string, params map[string]string, q *WriteOptions) (bool, *WriteMeta, error) {
 	r := k.c.newRequest("DELETE", "/v1/kv/"+strings.TrimPrefix(key, "/"))
 	r.setWriteOptions(q)
@@ -5,7 +5,9 @@ package api
 
 import (
 	"bytes"
+	"errors"
 	"path"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -392,6 +394,56 @@ func TestAPI_ClientKeys_DeleteRecurse(t *testing.T) {
 	}
 }
 
+func TestAPI_ClientKeysFunc(t *testing.T) {
+	t.Parallel()
+	c, s := makeClient(t)
+	defer s.Stop()
+
+	kv := c.KV()
+
+	keys := []string{"keysfunc/a", "keysfunc/b/c", "keysfunc/d"}
+	for _, key := range keys {
+		if _, err := kv.Put(&KVPair{Key: key}, nil); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+	}
+
+	var out []string
+	meta, err := kv.KeysFunc("keysfunc/", "/", nil, func(key string) error {
+		out = append(out, key)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if expect := []string{"keysfunc/a", "keysfunc/b/", "keysfunc/d"}; !reflect.DeepEqual(out, expect) {
+		t.Fatalf("got %v, expected %v", out, expect)
+	}
+	if meta.LastIndex == 0 {
+		t.Fatalf("unexpected value: %#v", meta)
+	}
+
+	// Errors from the callback stop the iteration.
+	stop := errors.New("stop")
+	var calls int
+	_, err = kv.KeysFunc("keysfunc/", "", nil, func(string) error {
+		calls++
+		return stop
+	})
+	if err != stop || calls != 1 {
+		t.Fatalf("got err %v after %d calls", err, calls)
+	}
+
+	// A prefix without keys is not an error.
+	calls = 0
+	if _, err := kv.KeysFunc("missing/", "", nil, func(string) error {
+		calls++
+		return nil
+	}); err != nil || calls != 0 {
+		t.Fatalf("got err %v after %d calls", err, calls)
+	}
+}
+
 func TestAPI_ClientAcquireRelease(t *testing.T) {
 	t.Parallel()
 	c, s := makeClient(t)
@@ -581,3 +633,34 @@ func TestAPI_KVClientTxn(t *testing.T) {
 		t.Fatalf("unexpected value: %#v", meta)
 	}
 }
+
+func TestAPI_KVListWithFlags_OldAgent(t *testing.T) {
+	mapi, client := setupMockAPI(t)
+
+	// An agent that predates the flags filter ignores it.
+	mapi.withReply("GET", "/v1/kv/test", nil, 200, []*KVPair{
+		{Key: "test/a", Flags: 1},
+		{Key: "test/b", Flags: 2},
+	}).Twice()
+
+	_, _, err := client.KV().ListWithFlags("test", 1, nil)
+	require.ErrorContains(t, err, "the flags filter was not applied")
+
+	pairs, _, err := client.KV().List("test", nil)
+	require.NoError(t, err)
+	require.Len(t, pairs, 2)
+}
+
+
+func TestAPI_KVListWithSession_OldAgent(t *testing.T) {
+	mapi, client := setupMockAPI(t)
+
+	// An agent that predates the session filter ignores it.
+	mapi.withReply("GET", "/v1/kv/test", nil, 200, []*KVPair{
+		{Key: "test/a", Session: "session1"},
+		{Key: "test/b"},
+	}).Once()
+
+	_, _, err := client.KV().ListWithSession("test", "session1", nil)
+	require.ErrorContains(t, err, "the session filter was not applied")
+}
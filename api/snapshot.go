@@ -55,3 +55,38 @@ func (s *Snapshot) Restore(q *WriteOptions, in io.Reader) error {
 	}
 	return nil
 }
+
+// SnapshotVerifyResult is the result of verifying a snapshot restore.
+type SnapshotVerifyResult struct {
+	// Index is the Raft index the snapshot was taken at.
+	Index uint64
+
+	// TableCounts has the number of entries in each state store table after
+	// the snapshot was restored.
+	TableCounts map[string]int
+}
+
+// Verify streams in an existing snapshot and has the servers run it through
+// the full restore path into a throwaway FSM, without changing any state. An
+// error is returned if the snapshot would fail to restore.
+func (s *Snapshot) Verify(q *WriteOptions, in io.Reader) (*SnapshotVerifyResult, error) {
+	r := s.c.newRequest("PUT", "/v1/snapshot")
+	r.body = in
+	r.header.Set("Content-Type", "application/octet-stream")
+	r.params.Set("verify-only", "")
+	r.setWriteOptions(q)
+	_, resp, err := s.c.doRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(resp)
+	if err := requireOK(resp); err != nil {
+		return nil, err
+	}
+
+	var out SnapshotVerifyResult
+	if err := decodeBody(resp, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
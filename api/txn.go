@@ -69,6 +69,7 @@ const (
 	KVCAS            KVOp = "cas"
 	KVLock           KVOp = "lock"
 	KVUnlock         KVOp = "unlock"
+	KVLockTransfer   KVOp = "lock-transfer"
 	KVGet            KVOp = "get"
 	KVGetOrEmpty     KVOp = "get-or-empty"
 	KVGetTree        KVOp = "get-tree"
@@ -6,6 +6,9 @@ package watch
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	consulapi "github.com/dhiaayachi/consul/api"
 )
@@ -173,6 +176,12 @@ func serviceWatch(params map[string]interface{}) (WatcherFunc, error) {
 		return nil, err
 	}
 
+	var serviceMeta map[string]string
+	if err := assignValueStringMap(params, "service_meta", &serviceMeta); err != nil {
+		return nil, err
+	}
+	filter = serviceMetaFilter(filter, serviceMeta)
+
 	fn := func(p *Plan) (BlockingParamVal, interface{}, error) {
 		health := p.client.Health()
 		opts := makeQueryOptionsWithContext(p, stale)
@@ -189,6 +198,31 @@ func serviceWatch(params map[string]interface{}) (WatcherFunc, error) {
 	return fn, nil
 }
 
+// serviceMetaFilter extends the given filter expression so that only service
+// instances carrying all of the given meta key/value pairs are returned. The
+// filtering happens in the blocking query, so changes to instances outside of
+// the subset never reach the handler.
+func serviceMetaFilter(filter string, meta map[string]string) string {
+	if len(meta) == 0 {
+		return filter
+	}
+
+	keys := make([]string, 0, len(meta))
+	for k := range meta {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	clauses := make([]string, 0, len(keys)+1)
+	if filter != "" {
+		clauses = append(clauses, "("+filter+")")
+	}
+	for _, k := range keys {
+		clauses = append(clauses, fmt.Sprintf("Service.Meta[%s] == %s", strconv.Quote(k), strconv.Quote(meta[k])))
+	}
+	return strings.Join(clauses, " and ")
+}
+
 // checksWatch is used to watch a specific checks in a given state
 func checksWatch(params map[string]interface{}) (WatcherFunc, error) {
 	stale := false
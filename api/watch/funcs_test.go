@@ -856,6 +856,87 @@ func TestServiceWatch_Filter(t *testing.T) {
 	}
 }
 
+func TestServiceWatch_ServiceMeta(t *testing.T) {
+	t.Parallel()
+	c, s := makeClient(t)
+	defer s.Stop()
+
+	s.WaitForSerfCheck(t)
+
+	var (
+		wakeups  [][]*api.ServiceEntry
+		notifyCh = make(chan struct{})
+	)
+
+	plan := mustParse(t, `{"type":"service", "service":"foo", "service_meta":{"track":"canary"}}`)
+	plan.Handler = func(idx uint64, raw interface{}) {
+		if raw == nil {
+			return // ignore
+		}
+		v, ok := raw.([]*api.ServiceEntry)
+		if !ok {
+			return // ignore
+		}
+
+		wakeups = append(wakeups, v)
+		notifyCh <- struct{}{}
+	}
+
+	agent := c.Agent()
+	canary := &api.AgentServiceRegistration{
+		ID:   "foo-canary",
+		Name: "foo",
+		Port: 8080,
+		Meta: map[string]string{"track": "canary"},
+	}
+	stable := &api.AgentServiceRegistration{
+		ID:   "foo-stable",
+		Name: "foo",
+		Port: 8080,
+		Meta: map[string]string{"track": "stable"},
+	}
+	require.NoError(t, agent.ServiceRegister(canary))
+	require.NoError(t, agent.ServiceRegister(stable))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := plan.Run(s.HTTPAddr); err != nil {
+			t.Errorf("err: %v", err)
+		}
+	}()
+	defer plan.Stop()
+
+	// Wait for the initial wakeup.
+	<-notifyCh
+
+	// Updating an instance outside of the subset must not fire the handler.
+	stable.Port = 9090
+	require.NoError(t, agent.ServiceRegister(stable))
+	select {
+	case <-notifyCh:
+		t.Fatal("handler fired for an instance outside of the watched subset")
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	// Updating an instance in the subset does.
+	canary.Port = 9090
+	require.NoError(t, agent.ServiceRegister(canary))
+	<-notifyCh
+
+	plan.Stop()
+	wg.Wait()
+
+	require.Len(t, wakeups, 2)
+	for _, v := range wakeups {
+		require.Len(t, v, 1)
+		require.Equal(t, "foo-canary", v[0].Service.ID)
+	}
+	require.Equal(t, 8080, wakeups[0][0].Service.Port)
+	require.Equal(t, 9090, wakeups[1][0].Service.Port)
+}
+
 func TestChecksWatch_State(t *testing.T) {
 	t.Parallel()
 	c, s := makeClient(t)
@@ -271,6 +271,32 @@ func assignValueStringSlice(params map[string]interface{}, name string, out *[]s
 	return nil
 }
 
+// assignValueStringMap is used to extract a value ensuring it is a map of strings
+func assignValueStringMap(params map[string]interface{}, name string, out *map[string]string) error {
+	if raw, ok := params[name]; ok {
+		tmp := make(map[string]string)
+		switch v := raw.(type) {
+		case map[string]string:
+			for k, val := range v {
+				tmp[k] = val
+			}
+		case map[string]interface{}:
+			for k, val := range v {
+				s, ok := val.(string)
+				if !ok {
+					return fmt.Errorf("Key %s of %s expected to be string", k, name)
+				}
+				tmp[k] = s
+			}
+		default:
+			return fmt.Errorf("Expecting %s to be a map of strings", name)
+		}
+		*out = tmp
+		delete(params, name)
+	}
+	return nil
+}
+
 // Parse the 'http_handler_config' parameters
 func parseHttpHandlerConfig(configParams interface{}) (*HttpHandlerConfig, error) {
 	var config HttpHandlerConfig
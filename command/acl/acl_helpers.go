@@ -112,6 +112,19 @@ func GetPolicyIDByName(client *api.Client, name string) (string, error) {
 	return policy.ID, nil
 }
 
+// GetPolicyIDFromNameOrPartial resolves a policy given either its name or a
+// unique prefix of its ID. Names take precedence.
+func GetPolicyIDFromNameOrPartial(client *api.Client, nameOrID string) (string, error) {
+	policy, err := GetPolicyByName(client, nameOrID)
+	if err != nil {
+		return "", err
+	}
+	if policy != nil {
+		return policy.ID, nil
+	}
+	return GetPolicyIDFromPartial(client, nameOrID)
+}
+
 func GetRoleIDFromPartial(client *api.Client, partialID string) (string, error) {
 	// the full UUID string was given
 	if len(partialID) == 36 {
@@ -159,6 +172,15 @@ func GetRoleIDByName(client *api.Client, name string) (string, error) {
 	return "", fmt.Errorf("No such role with name %s", name)
 }
 
+// GetRoleIDFromNameOrPartial resolves a role given either its name or a
+// unique prefix of its ID. Names take precedence.
+func GetRoleIDFromNameOrPartial(client *api.Client, nameOrID string) (string, error) {
+	if roleID, err := GetRoleIDByName(client, nameOrID); err == nil {
+		return roleID, nil
+	}
+	return GetRoleIDFromPartial(client, nameOrID)
+}
+
 func GetBindingRuleIDFromPartial(client *api.Client, partialID string) (string, error) {
 	// the full UUID string was given
 	if len(partialID) == 36 {
@@ -269,6 +291,24 @@ func ExtractTemplatedPolicies(templatedPolicy string, templatedPolicyFile string
 	return out, nil
 }
 
+// ExtractRoleTemplateVariables parses the -var arguments given to create a
+// role from a role template.
+func ExtractRoleTemplateVariables(variables []string) (map[string]string, error) {
+	if len(variables) == 0 {
+		return nil, nil
+	}
+
+	out := make(map[string]string)
+	for _, variable := range variables {
+		parts := strings.SplitN(variable, ":", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("malformed -var argument: %q, expecting VariableName:Value", variable)
+		}
+		out[parts[0]] = parts[1]
+	}
+	return out, nil
+}
+
 func ExtractBindVars(bindVars map[string]string) (*api.ACLTemplatedPolicyVariables, error) {
 	if len(bindVars) == 0 {
 		return nil, nil
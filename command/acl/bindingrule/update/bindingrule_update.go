@@ -10,6 +10,8 @@ import (
 
 	"github.com/mitchellh/cli"
 
+	"github.com/dhiaayachi/consul/agent/consul/auth"
+	"github.com/dhiaayachi/consul/agent/structs"
 	"github.com/dhiaayachi/consul/api"
 	"github.com/dhiaayachi/consul/command/acl"
 	"github.com/dhiaayachi/consul/command/acl/bindingrule"
@@ -39,6 +41,9 @@ type cmd struct {
 	noMerge  bool
 	showMeta bool
 	format   string
+
+	dryRun       bool
+	sampleFields map[string]string
 }
 
 func (c *cmd) init() {
@@ -111,6 +116,23 @@ func (c *cmd) init() {
 			" Format is VariableName=Value",
 	)
 
+	c.flags.BoolVar(
+		&c.dryRun,
+		"dry-run",
+		false,
+		"Do not update the binding rule. Instead evaluate the resulting rule "+
+			"against the identity given with -sample-field and print what a token "+
+			"created by logging in with that identity would be granted.",
+	)
+
+	c.flags.Var(
+		(*flags.FlagMapValue)(&c.sampleFields),
+		"sample-field",
+		"A field of the sample identity used by -dry-run, in the same form used by "+
+			"selectors and bind name interpolation. May be specified multiple times. "+
+			"Format is group.name=value, for example serviceaccount.name=web",
+	)
+
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
@@ -128,6 +150,11 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	if len(c.sampleFields) > 0 && !c.dryRun {
+		c.UI.Error("-sample-field can only be used with -dry-run")
+		return 1
+	}
+
 	client, err := c.http.APIClient()
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
@@ -208,6 +235,10 @@ func (c *cmd) Run(args []string) int {
 		}
 	}
 
+	if c.dryRun {
+		return c.preview(rule)
+	}
+
 	rule, _, err = client.ACL().BindingRuleUpdate(rule, nil)
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Error updating binding rule %q: %v", ruleID, err))
@@ -232,6 +263,66 @@ func (c *cmd) Run(args []string) int {
 	return 0
 }
 
+// preview prints what the given binding rule grants to a token created by
+// logging in with the sample identity, using the same evaluation as the
+// servers.
+func (c *cmd) preview(rule *api.ACLBindingRule) int {
+	// Selectors address fields as group.name, while bind names are
+	// interpolated with the flat field names.
+	selectable := make(map[string]map[string]string)
+	for field, value := range c.sampleFields {
+		group, name, ok := strings.Cut(field, ".")
+		if !ok || group == "" || name == "" || strings.Contains(name, ".") {
+			c.UI.Error(fmt.Sprintf("Invalid -sample-field %q: the name must have the form group.name", field))
+			return 1
+		}
+		if selectable[group] == nil {
+			selectable[group] = make(map[string]string)
+		}
+		selectable[group][name] = value
+	}
+
+	structsRule := &structs.ACLBindingRule{
+		Selector: rule.Selector,
+		BindType: string(rule.BindType),
+		BindName: rule.BindName,
+	}
+	if rule.BindVars != nil {
+		structsRule.BindVars = &structs.ACLTemplatedPolicyVariables{Name: rule.BindVars.Name}
+	}
+
+	preview, err := auth.PreviewBindingRule(structsRule, selectable, c.sampleFields)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error evaluating binding rule %q: %v", rule.ID, err))
+		return 1
+	}
+
+	if !preview.Matched {
+		c.UI.Info("The selector does not match the sample identity. The binding rule grants nothing.")
+		return 0
+	}
+
+	switch rule.BindType {
+	case api.BindingRuleBindTypeService:
+		c.UI.Info(fmt.Sprintf("The token would get the service identity %q.", preview.BindName))
+	case api.BindingRuleBindTypeNode:
+		c.UI.Info(fmt.Sprintf("The token would get the node identity %q.", preview.BindName))
+	case api.BindingRuleBindTypePolicy:
+		c.UI.Info(fmt.Sprintf("The token would be linked to the policy %q, if it exists.", preview.BindName))
+	case api.BindingRuleBindTypeRole:
+		c.UI.Info(fmt.Sprintf("The token would be linked to the role %q, if it exists.", preview.BindName))
+	case api.BindingRuleBindTypeTemplatedPolicy:
+		out := fmt.Sprintf("The token would get the templated policy %q", preview.TemplatedPolicy.TemplateName)
+		if vars := preview.TemplatedPolicy.TemplateVariables; vars != nil && vars.Name != "" {
+			out += fmt.Sprintf(" with the name %q", vars.Name)
+		}
+		c.UI.Info(out + ".")
+	default:
+		c.UI.Info(fmt.Sprintf("The binding rule has the unknown bind type %q and grants nothing.", rule.BindType))
+	}
+	return 0
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -267,5 +358,15 @@ Usage: consul acl binding-rule update -id ID [options]
           -bind-type=role \
           -bind-name='k8s-${serviceaccount.name}' \
           -selector='serviceaccount.namespace==default and serviceaccount.name==web'
+
+  Check what the updated binding rule would grant to a sample identity,
+  without updating it:
+
+    $ consul acl binding-rule update \
+          -id=43cb72df-9c6f-4315-ac8a-01a9d98155ef \
+          -bind-name='k8s-${serviceaccount.name}' \
+          -dry-run \
+          -sample-field=serviceaccount.namespace=default \
+          -sample-field=serviceaccount.name=web
 `
 )
@@ -578,6 +578,94 @@ func TestBindingRuleUpdateCommand(t *testing.T) {
 		err = json.Unmarshal([]byte(output), &jsonOutput)
 		assert.NoError(t, err)
 	})
+
+	t.Run("dry run", func(t *testing.T) {
+		id := createRule(t, false)
+
+		run := func(t *testing.T, extra ...string) string {
+			ui := cli.NewMockUi()
+			cmd := New(ui)
+
+			args := append([]string{
+				"-http-addr=" + a.HTTPAddr(),
+				"-token=root",
+				"-id=" + id,
+				"-dry-run",
+			}, extra...)
+			code := cmd.Run(args)
+			require.Equal(t, 0, code, "err: %s", ui.ErrorWriter.String())
+			require.Empty(t, ui.ErrorWriter.String())
+			return ui.OutputWriter.String()
+		}
+
+		out := run(t,
+			"-bind-name=k8s-${serviceaccount.name}",
+			"-sample-field=serviceaccount.namespace=default",
+			"-sample-field=serviceaccount.name=web",
+		)
+		require.Contains(t, out, `The token would get the service identity "k8s-web".`)
+
+		out = run(t,
+			"-bind-type=role",
+			"-bind-name=${serviceaccount.name}-role",
+			"-sample-field=serviceaccount.namespace=default",
+			"-sample-field=serviceaccount.name=web",
+		)
+		require.Contains(t, out, `The token would be linked to the role "web-role", if it exists.`)
+
+		out = run(t,
+			"-bind-type=templated-policy",
+			"-bind-name="+api.ACLTemplatedPolicyServiceName,
+			"-bind-vars=name=svc-${serviceaccount.name}",
+			"-sample-field=serviceaccount.namespace=default",
+			"-sample-field=serviceaccount.name=web",
+		)
+		require.Contains(t, out, `The token would get the templated policy "builtin/service" with the name "svc-web".`)
+
+		out = run(t,
+			"-sample-field=serviceaccount.namespace=prod",
+			"-sample-field=serviceaccount.name=web",
+		)
+		require.Contains(t, out, "The selector does not match the sample identity.")
+
+		// The rule itself is left untouched.
+		rule, _, err := client.ACL().BindingRuleRead(id, &api.QueryOptions{Token: "root"})
+		require.NoError(t, err)
+		require.Equal(t, "test-${serviceaccount.name}", rule.BindName)
+		require.Equal(t, api.BindingRuleBindTypeService, rule.BindType)
+	})
+
+	t.Run("dry run errors", func(t *testing.T) {
+		id := createRule(t, false)
+
+		cases := map[string]struct {
+			args   []string
+			expect string
+		}{
+			"sample field without dry run": {
+				args:   []string{"-sample-field=serviceaccount.name=web"},
+				expect: "-sample-field can only be used with -dry-run",
+			},
+			"bad sample field": {
+				args:   []string{"-dry-run", "-sample-field=name=web"},
+				expect: `Invalid -sample-field "name"`,
+			},
+			"invalid bind name": {
+				args:   []string{"-dry-run", "-bind-name=${serviceaccount.name}!", "-sample-field=serviceaccount.namespace=default", "-sample-field=serviceaccount.name=web"},
+				expect: `invalid bind name: "web!"`,
+			},
+		}
+		for name, tc := range cases {
+			t.Run(name, func(t *testing.T) {
+				ui := cli.NewMockUi()
+				cmd := New(ui)
+
+				args := append([]string{"-http-addr=" + a.HTTPAddr(), "-token=root", "-id=" + id}, tc.args...)
+				require.Equal(t, 1, cmd.Run(args))
+				require.Contains(t, ui.ErrorWriter.String(), tc.expect)
+			})
+		}
+	})
 }
 
 func TestBindingRuleUpdateCommand_noMerge(t *testing.T) {
@@ -6,9 +6,11 @@ package policydelete
 import (
 	"flag"
 	"fmt"
+	"strings"
 
 	"github.com/mitchellh/cli"
 
+	"github.com/dhiaayachi/consul/api"
 	"github.com/dhiaayachi/consul/command/acl"
 	"github.com/dhiaayachi/consul/command/flags"
 )
@@ -27,6 +29,8 @@ type cmd struct {
 
 	policyID   string
 	policyName string
+	checkUsage bool
+	force      bool
 }
 
 func (c *cmd) init() {
@@ -35,6 +39,10 @@ func (c *cmd) init() {
 		"It may be specified as a unique ID prefix but will error if the prefix "+
 		"matches multiple policy IDs")
 	c.flags.StringVar(&c.policyName, "name", "", "The name of the policy to delete.")
+	c.flags.BoolVar(&c.checkUsage, "check-usage", true, "Refuse to delete the policy "+
+		"while any token or role still links to it.")
+	c.flags.BoolVar(&c.force, "force", false, "Delete the policy even if tokens or "+
+		"roles still link to it. This overrides -check-usage.")
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
@@ -69,6 +77,19 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	if c.checkUsage && !c.force {
+		usage, err := policyUsage(client, policyID)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error checking usage of policy %q: %v", policyID, err))
+			return 1
+		}
+		if len(usage) > 0 {
+			c.UI.Error(fmt.Sprintf("Policy %q is still linked by:\n  %s\nUse -force to delete it anyway",
+				policyID, strings.Join(usage, "\n  ")))
+			return 1
+		}
+	}
+
 	if _, err := client.ACL().PolicyDelete(policyID, nil); err != nil {
 		c.UI.Error(fmt.Sprintf("Error deleting policy %q: %v", policyID, err))
 		return 1
@@ -78,6 +99,35 @@ func (c *cmd) Run(args []string) int {
 	return 0
 }
 
+// policyUsage returns a description of every token and role that links to the
+// given policy.
+func policyUsage(client *api.Client, policyID string) ([]string, error) {
+	var usage []string
+
+	tokens, _, err := client.ACL().TokenListFiltered(api.ACLTokenFilterOptions{Policy: policyID}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tokens: %w", err)
+	}
+	for _, token := range tokens {
+		usage = append(usage, fmt.Sprintf("token %s (%s)", token.AccessorID, token.Description))
+	}
+
+	roles, _, err := client.ACL().RoleList(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+	for _, role := range roles {
+		for _, link := range role.Policies {
+			if link.ID == policyID {
+				usage = append(usage, fmt.Sprintf("role %s (%s)", role.ID, role.Name))
+				break
+			}
+		}
+	}
+
+	return usage, nil
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -101,5 +151,9 @@ Usage: consul acl policy delete [options] -id POLICY
 
         $ consul acl policy delete -id b6b856da-5193-4e78-845a-7d61ca8371ba
 
+    Delete a policy that is still linked by tokens or roles:
+
+        $ consul acl policy delete -id b6b85 -force
+
 `
 )
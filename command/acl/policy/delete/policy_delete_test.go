@@ -10,6 +10,7 @@ import (
 
 	"github.com/mitchellh/cli"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/dhiaayachi/consul/agent"
 	"github.com/dhiaayachi/consul/api"
@@ -75,3 +76,80 @@ func TestPolicyDeleteCommand(t *testing.T) {
 	)
 	assert.EqualError(t, err, "Unexpected response code: 404 (Requested policy does not exist: ACL not found)")
 }
+
+func TestPolicyDeleteCommand_CheckUsage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, `
+	primary_datacenter = "dc1"
+	acl {
+		enabled = true
+		tokens {
+			initial_management = "root"
+		}
+	}`)
+
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	client := a.Client()
+
+	policy, _, err := client.ACL().PolicyCreate(
+		&api.ACLPolicy{Name: "test-policy"},
+		&api.WriteOptions{Token: "root"},
+	)
+	require.NoError(t, err)
+
+	token, _, err := client.ACL().TokenCreate(
+		&api.ACLToken{
+			Description: "linked token",
+			Policies:    []*api.ACLTokenPolicyLink{{ID: policy.ID}},
+		},
+		&api.WriteOptions{Token: "root"},
+	)
+	require.NoError(t, err)
+
+	role, _, err := client.ACL().RoleCreate(
+		&api.ACLRole{
+			Name:     "linked-role",
+			Policies: []*api.ACLRolePolicyLink{{ID: policy.ID}},
+		},
+		&api.WriteOptions{Token: "root"},
+	)
+	require.NoError(t, err)
+
+	t.Run("refused without force", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+			"-id=" + policy.ID,
+		})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "token "+token.AccessorID)
+		require.Contains(t, ui.ErrorWriter.String(), "role "+role.ID)
+
+		_, _, err := client.ACL().PolicyRead(policy.ID, &api.QueryOptions{Token: "root"})
+		require.NoError(t, err)
+	})
+
+	t.Run("deleted with force", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+			"-id=" + policy.ID,
+			"-force",
+		})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+		require.Contains(t, ui.OutputWriter.String(), "deleted successfully")
+	})
+}
@@ -37,6 +37,7 @@ type cmd struct {
 	templatedPolicy          string
 	templatedPolicyFile      string
 	templatedPolicyVariables []string
+	fromTemplate             string
 
 	showMeta bool
 	format   string
@@ -58,12 +59,14 @@ func (c *cmd) init() {
 	c.flags.Var((*flags.AppendSliceValue)(&c.nodeIdents), "node-identity", "Name of a "+
 		"node identity to use for this role. May be specified multiple times. Format is "+
 		"NODENAME:DATACENTER")
-	c.flags.Var((*flags.AppendSliceValue)(&c.templatedPolicyVariables), "var", "Templated policy variables."+
-		" Must be used in combination with -templated-policy flag to specify required variables."+
+	c.flags.Var((*flags.AppendSliceValue)(&c.templatedPolicyVariables), "var", "Templated policy or role template variables."+
+		" Must be used in combination with -templated-policy or -from-template flag to specify required variables."+
 		" May be specified multiple times with different variables."+
 		" Format is VariableName:Value")
 	c.flags.StringVar(&c.templatedPolicy, "templated-policy", "", "The templated policy name. Use -var flag to specify variables when required.")
 	c.flags.StringVar(&c.templatedPolicyFile, "templated-policy-file", "", "Path to a file containing templated policy names and variables.")
+	c.flags.StringVar(&c.fromTemplate, "from-template", "", "The name of a role template configured on the servers "+
+		"to create the role from. Use -var flag to specify the template variables.")
 	c.flags.StringVar(
 		&c.format,
 		"format",
@@ -89,8 +92,14 @@ func (c *cmd) Run(args []string) int {
 	}
 
 	if len(c.policyNames) == 0 && len(c.policyIDs) == 0 && len(c.serviceIdents) == 0 && len(c.nodeIdents) == 0 &&
-		len(c.templatedPolicy) == 0 && len(c.templatedPolicyFile) == 0 {
-		c.UI.Error("Cannot create a role without specifying -policy-name, -policy-id, -service-identity, -node-identity, -templated-policy-file or -templated-policy at least once")
+		len(c.templatedPolicy) == 0 && len(c.templatedPolicyFile) == 0 && len(c.fromTemplate) == 0 {
+		c.UI.Error("Cannot create a role without specifying -policy-name, -policy-id, -service-identity, -node-identity, -templated-policy-file, -templated-policy or -from-template at least once")
+		return 1
+	}
+
+	if len(c.fromTemplate) != 0 && (len(c.templatedPolicy) != 0 || len(c.templatedPolicyFile) != 0) {
+		c.UI.Error("Cannot combine the use of from-template flag with templated-policy or templated-policy-file flags. " +
+			"Add the templated policies to the role after creating it with -from-template")
 		return 1
 	}
 
@@ -148,7 +157,18 @@ func (c *cmd) Run(args []string) int {
 	}
 	newRole.TemplatedPolicies = parsedTemplatedPolicies
 
-	r, _, err := client.ACL().RoleCreate(newRole, nil)
+	var r *api.ACLRole
+	if c.fromTemplate != "" {
+		var variables map[string]string
+		variables, err = acl.ExtractRoleTemplateVariables(c.templatedPolicyVariables)
+		if err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+		r, _, err = client.ACL().RoleCreateFromTemplate(c.fromTemplate, newRole, variables, nil)
+	} else {
+		r, _, err = client.ACL().RoleCreate(newRole, nil)
+	}
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Failed to create new role: %v", err))
 		return 1
@@ -194,4 +214,10 @@ Usage: consul acl role create -name NAME [options]
                                  -service-identity "db:east,west" \
                                  -templated-policy "builtin/service" \
                                  -var "name:api"
+
+    Create a new role from a role template configured on the servers:
+
+        $ consul acl role create -name "web-role" \
+                                 -from-template "service-role" \
+                                 -var "name:web"
 `
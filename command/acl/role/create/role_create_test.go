@@ -184,3 +184,114 @@ func TestRoleCreateCommand_JSON(t *testing.T) {
 		assert.NoError(t, err)
 	}
 }
+
+func TestRoleCreateCommand_FromTemplate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, `
+	primary_datacenter = "dc1"
+	acl {
+		enabled = true
+		tokens {
+			initial_management = "root"
+		}
+		role_templates = [
+			{
+				name = "service-role"
+				description = "Role for {{.name}}"
+				policies = ["{{.name}}-policy"]
+				service_identities = [
+					{
+						service_name = "{{.name}}"
+						datacenters = ["{{.dc}}"]
+					}
+				]
+			}
+		]
+	}`)
+
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	client := a.Client()
+
+	policy, _, err := client.ACL().PolicyCreate(
+		&api.ACLPolicy{Name: "web-policy"},
+		&api.WriteOptions{Token: "root"},
+	)
+	require.NoError(t, err)
+
+	t.Run("from-template", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+			"-format=json",
+			"-name=web-role",
+			"-from-template=service-role",
+			"-var=name:web",
+			"-var=dc:dc1",
+			"-node-identity=web-node:dc1",
+		})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+		var role api.ACLRole
+		require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &role))
+		require.Equal(t, "web-role", role.Name)
+		require.Equal(t, "Role for web", role.Description)
+		require.Equal(t, []*api.ACLRolePolicyLink{{ID: policy.ID, Name: "web-policy"}}, role.Policies)
+		require.Equal(t, []*api.ACLServiceIdentity{{ServiceName: "web", Datacenters: []string{"dc1"}}}, role.ServiceIdentities)
+		require.Equal(t, []*api.ACLNodeIdentity{{NodeName: "web-node", Datacenter: "dc1"}}, role.NodeIdentities)
+	})
+
+	t.Run("missing variable", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+			"-name=api-role",
+			"-from-template=service-role",
+			"-var=name:api",
+		})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), `failed to instantiate role template "service-role"`)
+	})
+
+	t.Run("unknown template", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+			"-name=db-role",
+			"-from-template=unknown",
+		})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), `role template "unknown" does not exist`)
+	})
+
+	t.Run("prevent from-template and templated-policy simultaneous use", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-token=root",
+			"-name=db-role",
+			"-from-template=service-role",
+			"-templated-policy=builtin/service",
+			"-var=name:db",
+		})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "Cannot combine the use of from-template flag")
+	})
+}
@@ -6,6 +6,7 @@ package tokencreate
 import (
 	"flag"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -42,9 +43,12 @@ type cmd struct {
 	templatedPolicyFile      string
 	templatedPolicyVariables []string
 	expirationTTL            time.Duration
+	writeRateLimit           float64
 	local                    bool
 	showMeta                 bool
 	format                   string
+	fromLogin                string
+	bearerTokenFile          string
 }
 
 func (c *cmd) init() {
@@ -73,6 +77,9 @@ func (c *cmd) init() {
 		"NODENAME:DATACENTER")
 	c.flags.DurationVar(&c.expirationTTL, "expires-ttl", 0, "Duration of time this "+
 		"token should be valid for")
+	c.flags.Float64Var(&c.writeRateLimit, "write-rate-limit", 0, "Maximum number of "+
+		"write requests per second that may be made with this token. Defaults to 0, "+
+		"which does not limit the token")
 	c.flags.StringVar(
 		&c.format,
 		"format",
@@ -85,6 +92,11 @@ func (c *cmd) init() {
 		" Format is VariableName:Value")
 	c.flags.StringVar(&c.templatedPolicy, "templated-policy", "", "The templated policy name.  Use -var flag to specify variables when required.")
 	c.flags.StringVar(&c.templatedPolicyFile, "templated-policy-file", "", "Path to a file containing templated policies and variables.")
+	c.flags.StringVar(&c.fromLogin, "from-login", "", "Name of an auth method to log in to "+
+		"instead of creating the token directly. The token is minted by the auth method's "+
+		"binding rules and only its Secret ID is printed. Requires -bearer-token-file")
+	c.flags.StringVar(&c.bearerTokenFile, "bearer-token-file", "", "Path to a file containing "+
+		"a secret bearer token to use with the -from-login auth method")
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
@@ -97,6 +109,14 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	if c.fromLogin != "" {
+		return c.login()
+	}
+	if c.bearerTokenFile != "" {
+		c.UI.Error("Cannot use -bearer-token-file without -from-login")
+		return 1
+	}
+
 	if len(c.policyNames) == 0 && len(c.policyIDs) == 0 &&
 		len(c.roleNames) == 0 && len(c.roleIDs) == 0 &&
 		len(c.serviceIdents) == 0 && len(c.nodeIdents) == 0 &&
@@ -124,6 +144,9 @@ func (c *cmd) Run(args []string) int {
 		AccessorID:  c.accessor,
 		SecretID:    c.secret,
 	}
+	if c.writeRateLimit > 0 {
+		newToken.WriteRateLimit = c.writeRateLimit
+	}
 	if c.expirationTTL > 0 {
 		newToken.ExpirationTTL = c.expirationTTL
 	}
@@ -202,6 +225,72 @@ func (c *cmd) Run(args []string) int {
 	return 0
 }
 
+// login mints a token by logging in to the -from-login auth method and prints
+// only its Secret ID. The auth method's binding rules decide the token's
+// properties, so the flags that set them are rejected.
+func (c *cmd) login() int {
+	var invalid []string
+	c.flags.Visit(func(f *flag.Flag) {
+		if isTokenFlag(f.Name) {
+			invalid = append(invalid, "-"+f.Name)
+		}
+	})
+	if len(invalid) > 0 {
+		c.UI.Error(fmt.Sprintf("Cannot use %s with -from-login", strings.Join(invalid, ", ")))
+		return 1
+	}
+
+	if c.bearerTokenFile == "" {
+		c.UI.Error("Missing required '-bearer-token-file' flag")
+		return 1
+	}
+	data, err := os.ReadFile(c.bearerTokenFile)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	bearerToken := strings.TrimSpace(string(data))
+	if bearerToken == "" {
+		c.UI.Error(fmt.Sprintf("No bearer token found in %s", c.bearerTokenFile))
+		return 1
+	}
+
+	// Ensure that we don't try to use a token when performing a login
+	// operation.
+	c.http.SetToken("")
+	c.http.SetTokenFile("")
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	t, _, err := client.ACL().Login(&api.ACLLoginParams{
+		AuthMethod:  c.fromLogin,
+		BearerToken: bearerToken,
+	}, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error logging in: %s", err))
+		return 1
+	}
+
+	c.UI.Output(t.SecretID)
+	return 0
+}
+
+// isTokenFlag reports whether the named flag sets a property of the created
+// token, as opposed to an HTTP or output flag.
+func isTokenFlag(name string) bool {
+	switch name {
+	case "accessor", "secret", "local", "description", "policy-id", "policy-name",
+		"role-id", "role-name", "service-identity", "node-identity", "expires-ttl",
+		"write-rate-limit", "var", "templated-policy", "templated-policy-file":
+		return true
+	}
+	return false
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -230,5 +319,10 @@ Usage: consul acl token create [options]
                                     -service-identity "db:east,west" \
                                     -templated-policy "builtin/service" \
                                     -var "name:web"
+
+  Mint a token by logging in to an auth method and print only its Secret ID:
+
+          $ export CONSUL_HTTP_TOKEN=$(consul acl token create -from-login=kubernetes \
+                                    -bearer-token-file=/var/run/secrets/kubernetes.io/serviceaccount/token)
 `
 )
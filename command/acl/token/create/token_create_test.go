@@ -5,6 +5,8 @@ package tokencreate
 
 import (
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -13,7 +15,9 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/dhiaayachi/consul/agent"
+	"github.com/dhiaayachi/consul/agent/consul/authmethod/testauth"
 	"github.com/dhiaayachi/consul/api"
+	"github.com/dhiaayachi/consul/sdk/testutil"
 	"github.com/dhiaayachi/consul/testrpc"
 )
 
@@ -237,3 +241,94 @@ func TestTokenCreateCommand_JSON(t *testing.T) {
 		require.NoError(t, err, "token unmarshalling error")
 	}
 }
+
+func TestTokenCreateCommand_FromLogin(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, `
+	primary_datacenter = "dc1"
+	acl {
+		enabled = true
+		tokens {
+			initial_management = "root"
+		}
+	}`)
+
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	client := a.Client()
+
+	bearerTokenFile := filepath.Join(testutil.TempDir(t, "acl"), "bearer.token")
+	require.NoError(t, os.WriteFile(bearerTokenFile, []byte("demo-token"), 0600))
+
+	testSessionID := testauth.StartSession()
+	defer testauth.ResetSession(testSessionID)
+
+	testauth.InstallSessionToken(
+		testSessionID,
+		"demo-token",
+		"default", "demo", "76091af4-4b56-11e9-ac4b-708b11801cbe",
+	)
+
+	_, _, err := client.ACL().AuthMethodCreate(
+		&api.ACLAuthMethod{
+			Name: "test",
+			Type: "testing",
+			Config: map[string]interface{}{
+				"SessionID": testSessionID,
+			},
+		},
+		&api.WriteOptions{Token: "root"},
+	)
+	require.NoError(t, err)
+
+	_, _, err = client.ACL().BindingRuleCreate(&api.ACLBindingRule{
+		AuthMethod: "test",
+		BindType:   api.BindingRuleBindTypeService,
+		BindName:   "${serviceaccount.name}",
+		Selector:   "serviceaccount.namespace==default",
+	},
+		&api.WriteOptions{Token: "root"},
+	)
+	require.NoError(t, err)
+
+	t.Run("mints token", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-from-login=test",
+			"-bearer-token-file=" + bearerTokenFile,
+		})
+		require.Equal(t, 0, code, "err: %s", ui.ErrorWriter.String())
+		require.Empty(t, ui.ErrorWriter.String())
+
+		secretID := strings.TrimSpace(ui.OutputWriter.String())
+		require.Len(t, secretID, 36, "must be a valid uid: %s", secretID)
+
+		token, _, err := client.ACL().TokenReadSelf(&api.QueryOptions{Token: secretID})
+		require.NoError(t, err)
+		require.Equal(t, "test", token.AuthMethod)
+		require.Equal(t, []*api.ACLServiceIdentity{{ServiceName: "demo"}}, token.ServiceIdentities)
+	})
+
+	t.Run("rejects token properties", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-from-login=test",
+			"-bearer-token-file=" + bearerTokenFile,
+			"-policy-name=test-policy",
+		})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "Cannot use -policy-name with -from-login")
+	})
+}
@@ -72,6 +72,9 @@ func (f *prettyFormatter) FormatToken(token *api.ACLToken) (string, error) {
 	if token.AuthMethod != "" {
 		buffer.WriteString(fmt.Sprintf("Auth Method:      %s (Namespace: %s)\n", token.AuthMethod, token.AuthMethodNamespace))
 	}
+	if token.WriteRateLimit > 0 {
+		buffer.WriteString(fmt.Sprintf("Write Rate Limit: %g/s\n", token.WriteRateLimit))
+	}
 	buffer.WriteString(fmt.Sprintf("Create Time:      %v\n", token.CreateTime))
 	if token.ExpirationTime != nil && !token.ExpirationTime.IsZero() {
 		buffer.WriteString(fmt.Sprintf("Expiration Time:  %v\n", *token.ExpirationTime))
@@ -143,6 +146,9 @@ func (f *prettyFormatter) FormatTokenExpanded(token *api.ACLTokenExpanded) (stri
 	if token.AuthMethod != "" {
 		buffer.WriteString(fmt.Sprintf("Auth Method:      %s (Namespace: %s)\n", token.AuthMethod, token.AuthMethodNamespace))
 	}
+	if token.WriteRateLimit > 0 {
+		buffer.WriteString(fmt.Sprintf("Write Rate Limit: %g/s\n", token.WriteRateLimit))
+	}
 	buffer.WriteString(fmt.Sprintf("Create Time:      %v\n", token.CreateTime))
 	if token.ExpirationTime != nil && !token.ExpirationTime.IsZero() {
 		buffer.WriteString(fmt.Sprintf("Expiration Time:  %v\n", *token.ExpirationTime))
@@ -33,10 +33,14 @@ type cmd struct {
 	appendPolicyIDs            []string
 	policyNames                []string
 	appendPolicyNames          []string
+	appendPolicies             []string
+	removePolicies             []string
 	roleIDs                    []string
 	appendRoleIDs              []string
 	roleNames                  []string
 	appendRoleNames            []string
+	appendRoles                []string
+	removeRoles                []string
 	serviceIdents              []string
 	nodeIdents                 []string
 	appendNodeIdents           []string
@@ -47,6 +51,7 @@ type cmd struct {
 	replaceTemplatedPolicyFile string
 	templatedPolicyVariables   []string
 	description                string
+	writeRateLimit             float64
 	showMeta                   bool
 	format                     string
 
@@ -66,6 +71,9 @@ func (c *cmd) init() {
 		"It may be specified as a unique ID prefix but will error if the prefix "+
 		"matches multiple token Accessor IDs")
 	c.flags.StringVar(&c.description, "description", "", "A description of the token")
+	c.flags.Float64Var(&c.writeRateLimit, "write-rate-limit", 0, "Maximum number of "+
+		"write requests per second that may be made with this token. Set to 0 to "+
+		"remove the limit. The existing limit is kept if this is not specified")
 	c.flags.Var((*flags.AppendSliceValue)(&c.policyIDs), "policy-id", "ID of a "+
 		"policy to use for this token. Overwrites existing policies. May be specified multiple times")
 	c.flags.Var((*flags.AppendSliceValue)(&c.appendPolicyIDs), "append-policy-id", "ID of a "+
@@ -74,6 +82,10 @@ func (c *cmd) init() {
 		"policy to use for this token. Overwrites existing policies. May be specified multiple times")
 	c.flags.Var((*flags.AppendSliceValue)(&c.appendPolicyNames), "append-policy-name", "Name of a "+
 		"policy to add to this token. The token retains existing policies. May be specified multiple times")
+	c.flags.Var((*flags.AppendSliceValue)(&c.appendPolicies), "append-policy", "ID or name of a "+
+		"policy to add to this token. The token retains existing policies. May be specified multiple times")
+	c.flags.Var((*flags.AppendSliceValue)(&c.removePolicies), "remove-policy", "ID or name of a "+
+		"policy to remove from this token. The token retains its other policies. May be specified multiple times")
 	c.flags.Var((*flags.AppendSliceValue)(&c.roleIDs), "role-id", "ID of a "+
 		"role to use for this token. Overwrites existing roles. May be specified multiple times")
 	c.flags.Var((*flags.AppendSliceValue)(&c.roleNames), "role-name", "Name of a "+
@@ -82,6 +94,10 @@ func (c *cmd) init() {
 		"role to add to this token. The token retains existing roles. May be specified multiple times")
 	c.flags.Var((*flags.AppendSliceValue)(&c.appendRoleNames), "append-role-name", "Name of a "+
 		"role to add to this token. The token retains existing roles. May be specified multiple times")
+	c.flags.Var((*flags.AppendSliceValue)(&c.appendRoles), "append-role", "ID or name of a "+
+		"role to add to this token. The token retains existing roles. May be specified multiple times")
+	c.flags.Var((*flags.AppendSliceValue)(&c.removeRoles), "remove-role", "ID or name of a "+
+		"role to remove from this token. The token retains its other roles. May be specified multiple times")
 	c.flags.Var((*flags.AppendSliceValue)(&c.serviceIdents), "service-identity", "Name of a "+
 		"service identity to use for this token. May be specified multiple times. Format is "+
 		"the SERVICENAME or SERVICENAME:DATACENTER1,DATACENTER2,...")
@@ -176,6 +192,10 @@ func (c *cmd) Run(args []string) int {
 		t.Description = c.description
 	}
 
+	if isFlagSet(c.flags, "write-rate-limit") {
+		t.WriteRateLimit = c.writeRateLimit
+	}
+
 	hasAppendServiceFields := len(c.appendServiceIdents) > 0
 	hasServiceFields := len(c.serviceIdents) > 0
 	if hasAppendServiceFields && hasServiceFields {
@@ -231,6 +251,22 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	hasEditPolicyFields := len(c.appendPolicies) > 0 || len(c.removePolicies) > 0
+	if hasEditPolicyFields && !c.mergePolicies && (len(c.policyIDs) > 0 || len(c.policyNames) > 0) {
+		c.UI.Error("Cannot combine the use of policy-id/policy-name flags with -append-policy or -remove-policy. " +
+			"To set or overwrite existing policies, use -policy-id or -policy-name. " +
+			"To incrementally change existing policies, use -append-policy or -remove-policy.")
+		return 1
+	}
+
+	hasEditRoleFields := len(c.appendRoles) > 0 || len(c.removeRoles) > 0
+	if hasEditRoleFields && !c.mergeRoles && (len(c.roleIDs) > 0 || len(c.roleNames) > 0) {
+		c.UI.Error("Cannot combine the use of role-id/role-name flags with -append-role or -remove-role. " +
+			"To set or overwrite existing roles, use -role-id or -role-name. " +
+			"To incrementally change existing roles, use -append-role or -remove-role.")
+		return 1
+	}
+
 	if c.mergePolicies {
 		c.UI.Warn("merge-policies is deprecated and will be removed in a future Consul version. " +
 			"Use `append-policy-name` or `append-policy-id` instead.")
@@ -307,6 +343,45 @@ func (c *cmd) Run(args []string) int {
 		}
 	}
 
+	if hasEditPolicyFields {
+		for _, policy := range c.appendPolicies {
+			policyID, err := acl.GetPolicyIDFromNameOrPartial(client, policy)
+			if err != nil {
+				c.UI.Error(fmt.Sprintf("Error resolving policy %s: %v", policy, err))
+				return 1
+			}
+			found := false
+			for _, link := range t.Policies {
+				if link.ID == policyID {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				t.Policies = append(t.Policies, &api.ACLTokenPolicyLink{ID: policyID})
+			}
+		}
+
+		remove := make(map[string]struct{}, len(c.removePolicies))
+		for _, policy := range c.removePolicies {
+			policyID, err := acl.GetPolicyIDFromNameOrPartial(client, policy)
+			if err != nil {
+				c.UI.Error(fmt.Sprintf("Error resolving policy %s: %v", policy, err))
+				return 1
+			}
+			remove[policyID] = struct{}{}
+		}
+
+		policies := t.Policies[:0]
+		for _, link := range t.Policies {
+			if _, ok := remove[link.ID]; !ok {
+				policies = append(policies, link)
+			}
+		}
+		t.Policies = policies
+	}
+
 	if c.mergeRoles {
 		c.UI.Warn("merge-roles is deprecated and will be removed in a future Consul version. " +
 			"Use `append-role-name` or `append-role-id` instead.")
@@ -382,6 +457,45 @@ func (c *cmd) Run(args []string) int {
 		}
 	}
 
+	if hasEditRoleFields {
+		for _, role := range c.appendRoles {
+			roleID, err := acl.GetRoleIDFromNameOrPartial(client, role)
+			if err != nil {
+				c.UI.Error(fmt.Sprintf("Error resolving role %s: %v", role, err))
+				return 1
+			}
+			found := false
+			for _, link := range t.Roles {
+				if link.ID == roleID {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				t.Roles = append(t.Roles, &api.ACLTokenRoleLink{ID: roleID})
+			}
+		}
+
+		remove := make(map[string]struct{}, len(c.removeRoles))
+		for _, role := range c.removeRoles {
+			roleID, err := acl.GetRoleIDFromNameOrPartial(client, role)
+			if err != nil {
+				c.UI.Error(fmt.Sprintf("Error resolving role %s: %v", role, err))
+				return 1
+			}
+			remove[roleID] = struct{}{}
+		}
+
+		roles := t.Roles[:0]
+		for _, link := range t.Roles {
+			if _, ok := remove[link.ID]; !ok {
+				roles = append(roles, link)
+			}
+		}
+		t.Roles = roles
+	}
+
 	if c.mergeServiceIdents || hasAppendServiceFields {
 		for _, svcid := range parsedServiceIdents {
 			found := -1
@@ -449,6 +563,16 @@ func (c *cmd) Run(args []string) int {
 	return 0
 }
 
+func isFlagSet(flags *flag.FlagSet, name string) bool {
+	found := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			found = true
+		}
+	})
+	return found
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -469,6 +593,12 @@ Usage: consul acl token update [options]
 
         $ consul acl token update -accessor-id abcd -description "replication" -merge-policies
 
+    Add one policy to the token and remove another, keeping the rest:
+
+        $ consul acl token update -accessor-id abcd \
+                                  -append-policy "token-replication" \
+                                  -remove-policy "legacy-replication"
+
     Update all editable fields of the token:
 
         $ consul acl token update -accessor-id abcd \
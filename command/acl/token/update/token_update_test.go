@@ -310,6 +310,100 @@ func TestTokenUpdateCommandWithAppend(t *testing.T) {
 		require.Len(t, responseToken.Policies, 2)
 	})
 
+	// update with append-policy
+	t.Run("append-policy", func(t *testing.T) {
+		token := create_token(t, client,
+			&api.ACLToken{Description: "test", Policies: []*api.ACLTokenPolicyLink{{Name: policy.Name}}},
+			&api.WriteOptions{Token: "root"},
+		)
+
+		responseToken := run(t, []string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-accessor-id=" + token.AccessorID,
+			"-token=root",
+			"-append-policy=" + secondPolicy.Name,
+		})
+
+		require.ElementsMatch(t, []*api.ACLTokenPolicyLink{
+			{ID: policy.ID, Name: policy.Name},
+			{ID: secondPolicy.ID, Name: secondPolicy.Name},
+		}, responseToken.Policies)
+		require.Equal(t, "test", responseToken.Description)
+
+		// Appending a policy the token already has is a no-op.
+		responseToken = run(t, []string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-accessor-id=" + token.AccessorID,
+			"-token=root",
+			"-append-policy=" + policy.ID,
+		})
+		require.Len(t, responseToken.Policies, 2)
+	})
+
+	// update with remove-policy
+	t.Run("remove-policy", func(t *testing.T) {
+		token := create_token(t, client,
+			&api.ACLToken{Description: "test", Policies: []*api.ACLTokenPolicyLink{
+				{Name: policy.Name},
+				{Name: secondPolicy.Name},
+			}},
+			&api.WriteOptions{Token: "root"},
+		)
+
+		responseToken := run(t, []string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-accessor-id=" + token.AccessorID,
+			"-token=root",
+			"-remove-policy=" + secondPolicy.ID[:8],
+		})
+
+		require.Equal(t, []*api.ACLTokenPolicyLink{{ID: policy.ID, Name: policy.Name}}, responseToken.Policies)
+	})
+
+	// update with append-role and remove-role
+	t.Run("append-remove-role", func(t *testing.T) {
+		role, _, err := client.ACL().RoleCreate(
+			&api.ACLRole{Name: "test-role", Policies: []*api.ACLRolePolicyLink{{ID: policy.ID}}},
+			&api.WriteOptions{Token: "root"},
+		)
+		require.NoError(t, err)
+		secondRole, _, err := client.ACL().RoleCreate(
+			&api.ACLRole{Name: "secondary-role", Policies: []*api.ACLRolePolicyLink{{ID: policy.ID}}},
+			&api.WriteOptions{Token: "root"},
+		)
+		require.NoError(t, err)
+
+		token := create_token(t, client,
+			&api.ACLToken{Description: "test", Roles: []*api.ACLTokenRoleLink{{Name: role.Name}}},
+			&api.WriteOptions{Token: "root"},
+		)
+
+		responseToken := run(t, []string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-accessor-id=" + token.AccessorID,
+			"-token=root",
+			"-append-role=" + secondRole.Name,
+			"-remove-role=" + role.ID,
+		})
+
+		require.Equal(t, []*api.ACLTokenRoleLink{{ID: secondRole.ID, Name: secondRole.Name}}, responseToken.Roles)
+	})
+
+	t.Run("append-policy with policy-name", func(t *testing.T) {
+		token := create_token(t, client, &api.ACLToken{Description: "test"}, &api.WriteOptions{Token: "root"})
+
+		ui := cli.NewMockUi()
+		code := New(ui).Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-accessor-id=" + token.AccessorID,
+			"-token=root",
+			"-append-policy=" + secondPolicy.Name,
+			"-policy-name=" + policy.Name,
+		})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "Cannot combine the use of policy-id/policy-name flags with -append-policy")
+	})
+
 	// update with append-node-identity
 	t.Run("append-node-identity", func(t *testing.T) {
 		token := create_token(t, client,
@@ -30,11 +30,12 @@ type cmd struct {
 	help  string
 
 	// flags
-	detailed bool
-	near     string
-	nodeMeta map[string]string
-	service  string
-	filter   string
+	detailed   bool
+	near       string
+	nodeMeta   map[string]string
+	service    string
+	serviceTag string
+	filter     string
 
 	testStdin io.Reader
 }
@@ -53,6 +54,9 @@ func (c *cmd) init() {
 		"specified multiple times to filter on multiple sources of metadata.")
 	c.flags.StringVar(&c.service, "service", "", "Service `id or name` to filter nodes. "+
 		"Only nodes which are providing the given service will be returned.")
+	c.flags.StringVar(&c.serviceTag, "service-tag", "", "Service `tag` to filter nodes. "+
+		"Only nodes providing an instance of the -service with the given tag will "+
+		"be returned.")
 
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
@@ -71,6 +75,11 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	if c.serviceTag != "" && c.service == "" {
+		c.UI.Error("The -service-tag flag requires -service")
+		return 1
+	}
+
 	// Create and test the HTTP client
 	client, err := c.http.APIClient()
 	if err != nil {
@@ -89,7 +98,7 @@ func (c *cmd) Run(args []string) int {
 
 	var nodes []*api.Node
 	if c.service != "" {
-		services, _, err := client.Catalog().Service(c.service, "", &api.QueryOptions{
+		services, _, err := client.Catalog().Service(c.service, c.serviceTag, &api.QueryOptions{
 			Near:     c.near,
 			NodeMeta: c.nodeMeta,
 			Filter:   c.filter,
@@ -99,9 +108,16 @@ func (c *cmd) Run(args []string) int {
 			return 1
 		}
 
-		nodes = make([]*api.Node, len(services))
-		for i, s := range services {
-			nodes[i] = &api.Node{
+		// A node is listed once even when it runs several instances of the
+		// service.
+		seen := make(map[string]struct{}, len(services))
+		nodes = make([]*api.Node, 0, len(services))
+		for _, s := range services {
+			if _, ok := seen[s.Node]; ok {
+				continue
+			}
+			seen[s.Node] = struct{}{}
+			nodes = append(nodes, &api.Node{
 				ID:              s.ID,
 				Node:            s.Node,
 				Address:         s.Address,
@@ -110,7 +126,7 @@ func (c *cmd) Run(args []string) int {
 				Meta:            s.NodeMeta,
 				CreateIndex:     s.CreateIndex,
 				ModifyIndex:     s.ModifyIndex,
-			}
+			})
 		}
 	} else {
 		nodes, _, err = client.Catalog().Nodes(&api.QueryOptions{
@@ -191,6 +207,10 @@ Usage: consul catalog nodes [options]
 
       $ consul catalog nodes -service=web
 
+  To list nodes which are running a particular service with a given tag:
+
+      $ consul catalog nodes -service=web -service-tag=v2
+
   To filter by node metadata:
 
       $ consul catalog nodes -node-meta="foo=bar"
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/dhiaayachi/consul/agent"
+	"github.com/dhiaayachi/consul/api"
 	"github.com/dhiaayachi/consul/testrpc"
 	"github.com/mitchellh/cli"
 )
@@ -171,6 +172,106 @@ func TestCatalogListNodesCommand(t *testing.T) {
 	})
 }
 
+func TestCatalogListNodesCommand_serviceFilter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	catalog := a.Client().Catalog()
+	register := func(node, id string, tags ...string) {
+		_, err := catalog.Register(&api.CatalogRegistration{
+			Node:    node,
+			Address: "10.0.0.1",
+			Service: &api.AgentService{
+				ID:      id,
+				Service: "web",
+				Tags:    tags,
+			},
+		}, nil)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+	}
+	register("web-node-1", "web-1", "v1")
+	register("web-node-1", "web-2", "v2")
+	register("web-node-2", "web-1", "v2")
+	if _, err := catalog.Register(&api.CatalogRegistration{
+		Node:    "db-node",
+		Address: "10.0.0.2",
+	}, nil); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	t.Run("service", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+		args := []string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-service", "web",
+		}
+		code := c.Run(args)
+		if code != 0 {
+			t.Fatalf("bad exit code %d: %s", code, ui.ErrorWriter.String())
+		}
+		output := ui.OutputWriter.String()
+		for _, s := range []string{"web-node-1", "web-node-2"} {
+			if !strings.Contains(output, s) {
+				t.Errorf("expected %q to contain %q", output, s)
+			}
+		}
+		for _, s := range []string{"db-node", a.Config.NodeName} {
+			if strings.Contains(output, s) {
+				t.Errorf("expected %q to NOT contain %q", output, s)
+			}
+		}
+		if n := strings.Count(output, "web-node-1"); n != 1 {
+			t.Errorf("expected web-node-1 to be listed once, got %d", n)
+		}
+	})
+
+	t.Run("service-tag", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+		args := []string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-service", "web",
+			"-service-tag", "v1",
+		}
+		code := c.Run(args)
+		if code != 0 {
+			t.Fatalf("bad exit code %d: %s", code, ui.ErrorWriter.String())
+		}
+		output := ui.OutputWriter.String()
+		if !strings.Contains(output, "web-node-1") {
+			t.Errorf("expected %q to contain %q", output, "web-node-1")
+		}
+		if strings.Contains(output, "web-node-2") {
+			t.Errorf("expected %q to NOT contain %q", output, "web-node-2")
+		}
+	})
+
+	t.Run("service-tag without service", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+		args := []string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-service-tag", "v1",
+		}
+		if code := c.Run(args); code != 1 {
+			t.Fatalf("expected exit code 1, got %d", code)
+		}
+		if got, want := ui.ErrorWriter.String(), "requires -service"; !strings.Contains(got, want) {
+			t.Fatalf("expected %q to contain %q", got, want)
+		}
+	})
+}
+
 func TestCatalogListNodesCommand_verticalBar(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -13,6 +13,7 @@ import (
 
 	"github.com/dhiaayachi/consul/api"
 	"github.com/dhiaayachi/consul/command/flags"
+	"github.com/hashicorp/go-bexpr"
 	"github.com/mitchellh/cli"
 )
 
@@ -29,9 +30,11 @@ type cmd struct {
 	help  string
 
 	// flags
-	node     string
-	nodeMeta map[string]string
-	tags     bool
+	node         string
+	nodeMeta     map[string]string
+	tags         bool
+	changedSince uint64
+	filter       string
 }
 
 func (c *cmd) init() {
@@ -45,6 +48,15 @@ func (c *cmd) init() {
 		"of metadata.")
 	c.flags.BoolVar(&c.tags, "tags", false, "Display each service's tags as a "+
 		"comma-separated list beside each service entry.")
+	c.flags.Uint64Var(&c.changedSince, "changed-since", 0, "Wait for the catalog "+
+		"to change after the given Raft `index` and only list the services that "+
+		"were added, removed or had their tags changed since then, followed by "+
+		"the new index to pass on the next call.")
+	c.flags.StringVar(&c.filter, "filter", "", "Filter `expression` to use "+
+		"with the request. It is evaluated by the servers against each service "+
+		"instance, so only services with a matching instance are listed. With "+
+		"-node, the expression uses the node's service selectors, such as Tags "+
+		"instead of ServiceTags.")
 
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
@@ -63,6 +75,15 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	// Catch syntax errors before making any request. Unknown selectors are
+	// still reported by the servers.
+	if c.filter != "" {
+		if _, err := bexpr.CreateEvaluator(c.filter, nil); err != nil {
+			c.UI.Error(fmt.Sprintf("Error parsing filter expression: %s", err))
+			return 1
+		}
+	}
+
 	// Create and test the HTTP client
 	client, err := c.http.APIClient()
 	if err != nil {
@@ -70,33 +91,32 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
-	var services map[string][]string
-	if c.node != "" {
-		catalogNode, _, err := client.Catalog().Node(c.node, &api.QueryOptions{
-			NodeMeta: c.nodeMeta,
-		})
-		if err != nil {
-			c.UI.Error(fmt.Sprintf("Error listing services for node: %s", err))
-			return 1
-		}
-		if catalogNode != nil {
-			services = make(map[string][]string, len(catalogNode.Services))
-			for _, s := range catalogNode.Services {
-				services[s.Service] = append(services[s.Service], s.Tags...)
-			}
-		}
+	var (
+		services map[string][]string
+		removed  []string
+		meta     *api.QueryMeta
+	)
+	if c.changedSince > 0 {
+		services, removed, meta, err = c.changedServices(client)
 	} else {
-		services, _, err = client.Catalog().Services(&api.QueryOptions{
-			NodeMeta: c.nodeMeta,
-		})
-		if err != nil {
+		services, meta, err = c.listServices(client, 0)
+	}
+	if err != nil {
+		if c.node != "" {
+			c.UI.Error(fmt.Sprintf("Error listing services for node: %s", err))
+		} else {
 			c.UI.Error(fmt.Sprintf("Error listing services: %s", err))
-			return 1
 		}
+		return 1
 	}
 
 	// Handle the edge case where there are no services that match the query.
-	if len(services) == 0 {
+	if len(services) == 0 && len(removed) == 0 {
+		if c.changedSince > 0 {
+			c.UI.Error(fmt.Sprintf("No services changed since index %d", c.changedSince))
+			c.UI.Output(fmt.Sprintf("Index: %d", meta.LastIndex))
+			return 0
+		}
 		c.UI.Error("No services match the given query - try expanding your search.")
 		return 0
 	}
@@ -115,6 +135,9 @@ func (c *cmd) Run(args []string) int {
 			sort.Strings(services[s])
 			fmt.Fprintf(tw, "%s\t%s\n", s, strings.Join(services[s], ","))
 		}
+		for _, s := range removed {
+			fmt.Fprintf(tw, "%s\t(removed)\n", s)
+		}
 		if err := tw.Flush(); err != nil {
 			c.UI.Error(fmt.Sprintf("Error flushing tabwriter: %s", err))
 			return 1
@@ -124,11 +147,141 @@ func (c *cmd) Run(args []string) int {
 		for _, s := range order {
 			c.UI.Output(s)
 		}
+		for _, s := range removed {
+			c.UI.Output(s + " (removed)")
+		}
+	}
+
+	if c.changedSince > 0 {
+		c.UI.Output(fmt.Sprintf("Index: %d", meta.LastIndex))
 	}
 
 	return 0
 }
 
+// listServices returns the services in the catalog, or on the node given by
+// -node, along with their tags. A non-zero waitIndex makes it a blocking
+// query that returns once the catalog has moved past that index.
+func (c *cmd) listServices(client *api.Client, waitIndex uint64) (map[string][]string, *api.QueryMeta, error) {
+	opts := &api.QueryOptions{
+		NodeMeta:  c.nodeMeta,
+		WaitIndex: waitIndex,
+		Filter:    c.filter,
+	}
+	if c.node == "" {
+		return client.Catalog().Services(opts)
+	}
+
+	catalogNode, meta, err := client.Catalog().Node(c.node, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	services := make(map[string][]string)
+	if catalogNode != nil {
+		for _, s := range catalogNode.Services {
+			services[s.Service] = append(services[s.Service], s.Tags...)
+		}
+	}
+	return services, meta, nil
+}
+
+// changedServices lists the services that changed after the -changed-since
+// index. If the catalog is still at that index it waits for the catalog to
+// move and diffs the services against the ones listed at that index, which
+// also finds the services that were removed. Otherwise the services are
+// compared by the highest ModifyIndex of their instances, which finds the
+// services that were added or had an instance registered or updated, but not
+// the ones that were removed since the catalog no longer holds them.
+func (c *cmd) changedServices(client *api.Client) (map[string][]string, []string, *api.QueryMeta, error) {
+	prev, prevMeta, err := c.listServices(client, 0)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if prevMeta.LastIndex > c.changedSince {
+		changed, err := c.modifiedServices(client, prev)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		c.UI.Warn(fmt.Sprintf("The catalog changed after index %d before it could be read, services removed since then are not listed", c.changedSince))
+		return changed, nil, prevMeta, nil
+	}
+
+	services, meta, err := c.listServices(client, c.changedSince)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	changed := make(map[string][]string)
+	for name, tags := range services {
+		if prevTags, ok := prev[name]; !ok || !sameTags(prevTags, tags) {
+			changed[name] = tags
+		}
+	}
+	var removed []string
+	for name := range prev {
+		if _, ok := services[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+	return changed, removed, meta, nil
+}
+
+// modifiedServices returns the given services that have an instance with a
+// ModifyIndex past the -changed-since index.
+func (c *cmd) modifiedServices(client *api.Client, services map[string][]string) (map[string][]string, error) {
+	opts := &api.QueryOptions{
+		NodeMeta: c.nodeMeta,
+		Filter:   c.filter,
+	}
+	changed := make(map[string][]string)
+	if c.node != "" {
+		catalogNode, _, err := client.Catalog().Node(c.node, opts)
+		if err != nil {
+			return nil, err
+		}
+		if catalogNode != nil {
+			for _, s := range catalogNode.Services {
+				if tags, ok := services[s.Service]; ok && s.ModifyIndex > c.changedSince {
+					changed[s.Service] = tags
+				}
+			}
+		}
+		return changed, nil
+	}
+
+	for name, tags := range services {
+		instances, _, err := client.Catalog().Service(name, "", opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range instances {
+			if s.ModifyIndex > c.changedSince {
+				changed[name] = tags
+				break
+			}
+		}
+	}
+	return changed, nil
+}
+
+// sameTags returns true if a and b hold the same tags in any order.
+func sameTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -161,6 +314,21 @@ Usage: consul catalog services [options]
 
       $ consul catalog services -node-meta="foo=bar"
 
+  To list the services with an instance tagged "canary":
+
+      $ consul catalog services -filter='"canary" in ServiceTags'
+
+  The services of a single node use different selectors, so the same filter
+  with -node is written as:
+
+      $ consul catalog services -node=web -filter='"canary" in Tags'
+
+  To wait for changes after a previous call and list only the services that
+  were added, removed or had their tags changed, followed by the index for the
+  next call:
+
+      $ consul catalog services -changed-since=1234
+
   For a full list of options and examples, please see the Consul documentation.
 `
 )
@@ -4,13 +4,19 @@
 package services
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
 
 	"github.com/dhiaayachi/consul/testrpc"
 
 	"github.com/dhiaayachi/consul/agent"
 	"github.com/dhiaayachi/consul/api"
+	"github.com/dhiaayachi/consul/sdk/testutil/retry"
 	"github.com/mitchellh/cli"
 )
 
@@ -122,6 +128,75 @@ func TestCatalogListServicesCommand(t *testing.T) {
 		}
 	})
 
+	t.Run("filter", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+		args := []string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-filter", `"foo" in ServiceTags`,
+		}
+		code := c.Run(args)
+		if code != 0 {
+			t.Fatalf("bad exit code %d: %s", code, ui.ErrorWriter.String())
+		}
+		output := ui.OutputWriter.String()
+		if expected := "testing\n"; output != expected {
+			t.Errorf("expected %q to be %q", output, expected)
+		}
+	})
+
+	t.Run("filter_node", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+		args := []string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-node", a.Config.NodeName,
+			"-filter", `"foo" in Tags`,
+		}
+		code := c.Run(args)
+		if code != 0 {
+			t.Fatalf("bad exit code %d: %s", code, ui.ErrorWriter.String())
+		}
+		output := ui.OutputWriter.String()
+		if expected := "testing\n"; output != expected {
+			t.Errorf("expected %q to be %q", output, expected)
+		}
+	})
+
+	t.Run("filter_invalid", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+		args := []string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-filter", `ServiceTags contains`,
+		}
+		code := c.Run(args)
+		if code == 0 {
+			t.Fatal("expected non-zero exit")
+		}
+		output := ui.ErrorWriter.String()
+		if expected := "Error parsing filter expression"; !strings.Contains(output, expected) {
+			t.Errorf("expected %q to contain %q", output, expected)
+		}
+	})
+
+	t.Run("filter_unknown_selector", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+		args := []string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-filter", `NotASelector == "foo"`,
+		}
+		code := c.Run(args)
+		if code == 0 {
+			t.Fatal("expected non-zero exit")
+		}
+		output := ui.ErrorWriter.String()
+		if expected := "Error listing services"; !strings.Contains(output, expected) {
+			t.Errorf("expected %q to contain %q", output, expected)
+		}
+	})
+
 	t.Run("node-meta", func(t *testing.T) {
 		ui := cli.NewMockUi()
 		c := New(ui)
@@ -139,3 +214,119 @@ func TestCatalogListServicesCommand(t *testing.T) {
 		}
 	})
 }
+
+func TestCatalogListServicesCommand_ChangedSince(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+	client := a.Client()
+
+	register := func(t require.TestingT, name string, tags ...string) {
+		_, err := client.Catalog().Register(&api.CatalogRegistration{
+			Node:    "foo",
+			Address: "127.0.0.1",
+			Service: &api.AgentService{
+				Service: name,
+				Tags:    tags,
+			},
+		}, nil)
+		require.NoError(t, err)
+	}
+	index := func(t require.TestingT) uint64 {
+		_, meta, err := client.Catalog().Services(nil)
+		require.NoError(t, err)
+		return meta.LastIndex
+	}
+	run := func(t require.TestingT, index uint64, args ...string) (int, *cli.MockUi) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+		args = append([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-changed-since=" + strconv.FormatUint(index, 10),
+			"-tags",
+		}, args...)
+		return c.Run(args), ui
+	}
+	// outputLines splits the output and checks that it ends with an index
+	// past the given one.
+	outputLines := func(t require.TestingT, ui *cli.MockUi, index uint64) []string {
+		lines := strings.Split(strings.TrimSpace(ui.OutputWriter.String()), "\n")
+		var newIndex uint64
+		_, err := fmt.Sscanf(lines[len(lines)-1], "Index: %d", &newIndex)
+		require.NoError(t, err)
+		require.Greater(t, newIndex, index)
+		return lines[:len(lines)-1]
+	}
+
+	register(t, "unchanged", "v1")
+
+	for name, args := range map[string][]string{
+		"all nodes": {},
+		"node":      {"-node=foo"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			updated := "updated-" + strings.ReplaceAll(name, " ", "-")
+			added := "added-" + strings.ReplaceAll(name, " ", "-")
+			register(t, updated, "v1")
+
+			// The catalog has moved past the index by the time the command
+			// runs, so the changes are found from the ModifyIndex of each
+			// service instance.
+			idx := index(t)
+			register(t, updated, "v2")
+			register(t, added, "v1")
+
+			code, ui := run(t, idx, args...)
+			require.Equal(t, 0, code, ui.ErrorWriter.String())
+			require.Contains(t, ui.ErrorWriter.String(), "services removed since then are not listed")
+
+			lines := outputLines(t, ui, idx)
+			require.Len(t, lines, 2)
+			require.Regexp(t, `^`+added+`\s+v1$`, lines[0])
+			require.Regexp(t, `^`+updated+`\s+v2$`, lines[1])
+		})
+	}
+
+	t.Run("removed", func(t *testing.T) {
+		// The removal is only found if the command is already waiting on the
+		// index when the service is deregistered, so retry until it is.
+		retry.Run(t, func(r *retry.R) {
+			register(r, "gone", "v1")
+			idx := index(r)
+
+			doneCh := make(chan struct{})
+			var (
+				code int
+				ui   *cli.MockUi
+			)
+			go func() {
+				defer close(doneCh)
+				code, ui = run(r, idx)
+			}()
+
+			time.Sleep(50 * time.Millisecond)
+			_, err := client.Catalog().Deregister(&api.CatalogDeregistration{
+				Node:      "foo",
+				ServiceID: "gone",
+			}, nil)
+			require.NoError(r, err)
+
+			select {
+			case <-doneCh:
+			case <-time.After(10 * time.Second):
+				r.Fatal("command did not return after the catalog changed")
+			}
+			require.Equal(r, 0, code, ui.ErrorWriter.String())
+			require.Empty(r, ui.ErrorWriter.String())
+
+			lines := outputLines(r, ui, idx)
+			require.Len(r, lines, 1)
+			require.Regexp(r, `^gone\s+\(removed\)$`, lines[0])
+		})
+	})
+}
@@ -7,11 +7,27 @@ import (
 	"flag"
 	"fmt"
 
+	"github.com/dhiaayachi/consul/agent/structs"
 	"github.com/dhiaayachi/consul/api"
 	"github.com/dhiaayachi/consul/command/flags"
 	"github.com/mitchellh/cli"
+	"github.com/ryanuber/columnize"
 )
 
+// allKinds is the set of config entry kinds listed by -kind=all. It is built
+// from structs.AllConfigEntryKinds, leaving out kinds this edition cannot
+// decode (such as enterprise-only kinds). The LLM agent kinds are not part of
+// the shared list, so they are added explicitly.
+var allKinds = func() []string {
+	var kinds []string
+	for _, kind := range structs.AllConfigEntryKinds {
+		if _, err := structs.MakeConfigEntry(kind, ""); err == nil {
+			kinds = append(kinds, kind)
+		}
+	}
+	return append(kinds, structs.LLMAgent, structs.LLMAgentExternalServers)
+}()
+
 func New(ui cli.Ui) *cmd {
 	c := &cmd{UI: ui}
 	c.init()
@@ -30,7 +46,8 @@ type cmd struct {
 
 func (c *cmd) init() {
 	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
-	c.flags.StringVar(&c.kind, "kind", "", "The kind of configurations to list.")
+	c.flags.StringVar(&c.kind, "kind", "", "The kind of configurations to list. "+
+		"Use \"all\" to list the config entries of every kind.")
 	c.flags.StringVar(&c.filter, "filter", "", "Filter to use with the request.")
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
@@ -55,6 +72,10 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	if c.kind == "all" {
+		return c.listAll(client)
+	}
+
 	entries, _, err := client.ConfigEntries().List(c.kind, &api.QueryOptions{
 		Filter: c.filter,
 	})
@@ -69,6 +90,33 @@ func (c *cmd) Run(args []string) int {
 	return 0
 }
 
+func (c *cmd) listAll(client *api.Client) int {
+	if c.filter != "" {
+		c.UI.Error("The -filter parameter is not supported with -kind=all")
+		return 1
+	}
+
+	result := []string{"Kind\x1fName"}
+	for _, kind := range allKinds {
+		entries, _, err := client.ConfigEntries().List(kind, nil)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error listing config entries for kind %q: %v", kind, err))
+			return 1
+		}
+		for _, entry := range entries {
+			result = append(result, fmt.Sprintf("%s\x1f%s", kind, entry.GetName()))
+		}
+	}
+
+	if len(result) == 1 {
+		c.UI.Info("No config entries found")
+		return 0
+	}
+
+	c.UI.Output(columnize.Format(result, &columnize.Config{Delim: string([]byte{0x1f})}))
+	return 0
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -89,5 +137,10 @@ Usage: consul config list [options] -kind <config kind>
 
     $ consul config list -kind service-defaults
 
+  Use -kind=all to list the config entries of every kind along with
+  their kind:
+
+    $ consul config list -kind all
+
 `
 )
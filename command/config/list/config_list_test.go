@@ -6,8 +6,10 @@ package list
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/dhiaayachi/consul/agent"
+	"github.com/dhiaayachi/consul/agent/structs"
 	"github.com/dhiaayachi/consul/api"
 	"github.com/mitchellh/cli"
 	"github.com/stretchr/testify/require"
@@ -49,6 +51,19 @@ func TestConfigList(t *testing.T) {
 	}, nil)
 	require.NoError(t, err)
 
+	_, _, err = client.ConfigEntries().Set(&api.ProxyConfigEntry{
+		Kind: api.ProxyDefaults,
+		Name: api.ProxyConfigGlobal,
+	}, nil)
+	require.NoError(t, err)
+
+	_, _, err = client.ConfigEntries().Set(&api.ServiceResolverConfigEntry{
+		Kind:           api.ServiceResolver,
+		Name:           "web",
+		ConnectTimeout: 5 * time.Second,
+	}, nil)
+	require.NoError(t, err)
+
 	cases := map[string]struct {
 		args     []string
 		expected []string
@@ -97,6 +112,43 @@ func TestConfigList(t *testing.T) {
 
 		})
 	}
+
+	t.Run("list all kinds", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-kind=all",
+		})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+		var rows [][]string
+		for _, line := range strings.Split(strings.TrimSpace(ui.OutputWriter.String()), "\n") {
+			rows = append(rows, strings.Fields(line))
+		}
+		require.Equal(t, []string{"Kind", "Name"}, rows[0])
+		require.ElementsMatch(t, [][]string{
+			{api.ServiceDefaults, "api"},
+			{api.ServiceDefaults, "foo"},
+			{api.ServiceDefaults, "web"},
+			{api.ProxyDefaults, api.ProxyConfigGlobal},
+			{api.ServiceResolver, "web"},
+		}, rows[1:])
+	})
+
+	t.Run("filter all kinds", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+
+		code := cmd.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-kind=all",
+			"-filter=" + `Protocol == "http"`,
+		})
+		require.NotEqual(t, 0, code)
+		require.Contains(t, ui.ErrorWriter.String(), "not supported with -kind=all")
+	})
 }
 
 func TestConfigList_InvalidArgs(t *testing.T) {
@@ -116,3 +168,14 @@ func TestConfigList_InvalidArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigList_AllKinds(t *testing.T) {
+	t.Parallel()
+
+	require.Contains(t, allKinds, structs.BoundAPIGateway)
+	require.Contains(t, allKinds, structs.LLMAgent)
+	for _, kind := range allKinds {
+		_, err := structs.MakeConfigEntry(kind, "")
+		require.NoError(t, err, kind)
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"net/http"
 
+	"github.com/dhiaayachi/consul/api"
 	"github.com/dhiaayachi/consul/command/flags"
 	"github.com/mitchellh/cli"
 )
@@ -24,8 +26,9 @@ type cmd struct {
 	http  *flags.HTTPFlags
 	help  string
 
-	kind string
-	name string
+	kind       string
+	name       string
+	showSource bool
 }
 
 func (c *cmd) init() {
@@ -34,6 +37,9 @@ func (c *cmd) init() {
 	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
 	c.flags.StringVar(&c.kind, "kind", "", "The kind of configuration to read.")
 	c.flags.StringVar(&c.name, "name", "", "The name of configuration to read.")
+	c.flags.BoolVar(&c.showSource, "show-source", false,
+		"Before the entry, output whether it was created in the datacenter it "+
+			"was read from or replicated from the primary datacenter.")
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
@@ -62,12 +68,21 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
-	entry, _, err := client.ConfigEntries().Get(c.kind, c.name, nil)
+	entry, meta, err := client.ConfigEntries().Get(c.kind, c.name, nil)
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Error reading config entry %s/%s: %v", c.kind, c.name, err))
 		return 1
 	}
 
+	if c.showSource {
+		source, err := c.entrySource(client, meta)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error determining the source of config entry %s/%s: %v", c.kind, c.name, err))
+			return 1
+		}
+		c.UI.Info("Source: " + source)
+	}
+
 	b, err := json.MarshalIndent(entry, "", "    ")
 	if err != nil {
 		c.UI.Error("Failed to encode output data")
@@ -78,6 +93,39 @@ func (c *cmd) Run(args []string) int {
 	return 0
 }
 
+// entrySource describes where the entry that was read originates. Secondary
+// datacenters replicate their config entries from the primary datacenter, so
+// the entry is compared against the one in the primary datacenter and the
+// index the replication has reached.
+func (c *cmd) entrySource(client *api.Client, meta *api.QueryMeta) (string, error) {
+	status, _, err := client.Internal().ConfigEntryReplicationStatus(&api.QueryOptions{
+		Datacenter: c.http.Datacenter(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to read the config entry replication status: %w", err)
+	}
+	if !status.Enabled {
+		return fmt.Sprintf("local (created in primary datacenter %q)", meta.PrimaryDatacenter), nil
+	}
+
+	primary := status.SourceDatacenter
+	entry, _, err := client.ConfigEntries().Get(c.kind, c.name, &api.QueryOptions{
+		Datacenter: primary,
+	})
+	if err != nil {
+		if statusErr, ok := err.(api.StatusError); ok && statusErr.Code == http.StatusNotFound {
+			return fmt.Sprintf("local (not in primary datacenter %q, it will be removed by replication)", primary), nil
+		}
+		return "", fmt.Errorf("failed to read the entry from primary datacenter %q: %w", primary, err)
+	}
+
+	if entry.GetModifyIndex() > status.ReplicatedIndex {
+		return fmt.Sprintf("replicated from primary datacenter %q, the change at index %d is not replicated yet (replicated up to index %d)",
+			primary, entry.GetModifyIndex(), status.ReplicatedIndex), nil
+	}
+	return fmt.Sprintf("replicated from primary datacenter %q (replicated up to index %d)", primary, status.ReplicatedIndex), nil
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -97,5 +145,9 @@ Usage: consul config read [options] -kind <config kind> -name <config name>
   Example:
 
     $ consul config read -kind proxy-defaults -name global
+
+  To also show whether the entry was replicated from the primary datacenter:
+
+    $ consul config read -show-source -kind proxy-defaults -name global
 `
 )
@@ -8,6 +8,8 @@ import (
 
 	"github.com/dhiaayachi/consul/agent"
 	"github.com/dhiaayachi/consul/api"
+	"github.com/dhiaayachi/consul/sdk/testutil/retry"
+	"github.com/dhiaayachi/consul/testrpc"
 	"github.com/mitchellh/cli"
 	"github.com/stretchr/testify/require"
 )
@@ -75,3 +77,76 @@ func TestConfigRead_InvalidArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigRead_ShowSource(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a1 := agent.NewTestAgent(t, `
+		primary_datacenter = "dc1"
+	`)
+	defer a1.Shutdown()
+	testrpc.WaitForLeader(t, a1.RPC, "dc1")
+
+	a2 := agent.NewTestAgent(t, `
+		datacenter = "dc2"
+		primary_datacenter = "dc1"
+	`)
+	defer a2.Shutdown()
+	testrpc.WaitForLeader(t, a2.RPC, "dc2")
+
+	_, err := a2.JoinWAN([]string{a1.Config.SerfBindAddrWAN.String()})
+	require.NoError(t, err)
+	retry.Run(t, func(r *retry.R) {
+		require.Len(r, a1.WANMembers(), 2)
+	})
+
+	_, _, err = a1.Client().ConfigEntries().Set(&api.ServiceConfigEntry{
+		Kind:     api.ServiceDefaults,
+		Name:     "web",
+		Protocol: "http",
+	}, nil)
+	require.NoError(t, err)
+
+	retry.Run(t, func(r *retry.R) {
+		_, _, err := a2.Client().ConfigEntries().Get(api.ServiceDefaults, "web", nil)
+		require.NoError(r, err)
+	})
+
+	read := func(t require.TestingT, args ...string) string {
+		ui := cli.NewMockUi()
+		c := New(ui)
+
+		args = append(args, "-show-source", "-kind="+api.ServiceDefaults, "-name=web")
+		code := c.Run(args)
+		require.Empty(t, ui.ErrorWriter.String())
+		require.Equal(t, 0, code)
+		return ui.OutputWriter.String()
+	}
+
+	t.Run("primary", func(t *testing.T) {
+		out := read(t, "-http-addr="+a1.HTTPAddr())
+		require.Contains(t, out, `Source: local (created in primary datacenter "dc1")`)
+		require.Contains(t, out, `"Protocol": "http"`)
+	})
+
+	// The replication status is updated once the replication round that
+	// wrote the entry completes.
+	t.Run("secondary", func(t *testing.T) {
+		retry.Run(t, func(r *retry.R) {
+			out := read(r, "-http-addr="+a2.HTTPAddr())
+			require.Contains(r, out, `Source: replicated from primary datacenter "dc1" (replicated up to index`)
+			require.Contains(r, out, `"Protocol": "http"`)
+		})
+	})
+
+	t.Run("secondary through primary agent", func(t *testing.T) {
+		retry.Run(t, func(r *retry.R) {
+			out := read(r, "-http-addr="+a1.HTTPAddr(), "-datacenter=dc2")
+			require.Contains(r, out, `Source: replicated from primary datacenter "dc1" (replicated up to index`)
+		})
+	})
+}
@@ -4,12 +4,17 @@
 package write
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/mitchellh/cli"
 
+	"github.com/dhiaayachi/consul/api"
 	"github.com/dhiaayachi/consul/command/config"
 	"github.com/dhiaayachi/consul/command/flags"
 	"github.com/dhiaayachi/consul/command/helpers"
@@ -27,11 +32,16 @@ type cmd struct {
 	http  *flags.HTTPFlags
 	help  string
 
-	cas         bool
-	modifyIndex uint64
-	testStdin   io.Reader
+	cas             bool
+	modifyIndex     uint64
+	waitSync        string
+	waitSyncTimeout time.Duration
+	testStdin       io.Reader
 }
 
+// waitSyncInterval is how often -wait-sync polls the secondary datacenters.
+const waitSyncInterval = 500 * time.Millisecond
+
 func (c *cmd) init() {
 	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
 	c.http = &flags.HTTPFlags{}
@@ -42,6 +52,12 @@ func (c *cmd) init() {
 	c.flags.Uint64Var(&c.modifyIndex, "modify-index", 0,
 		"Unsigned integer representing the ModifyIndex of the config entry. "+
 			"This is used in combination with the -cas flag.")
+	c.flags.StringVar(&c.waitSync, "wait-sync", "",
+		"Comma-separated list of secondary datacenters. After the write, wait "+
+			"until the config entry has been replicated to each of them.")
+	c.flags.DurationVar(&c.waitSyncTimeout, "wait-sync-timeout", time.Minute,
+		"Maximum time to wait for replication when -wait-sync is set. "+
+			"The default value is 1m.")
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
 	flags.Merge(c.flags, c.http.MultiTenancyFlags())
@@ -65,11 +81,19 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
-	entry, err := helpers.ParseConfigEntry(data)
+	parsed, err := helpers.ParseConfigEntries(data)
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Failed to decode config entry input: %v", err))
 		return 1
 	}
+	if len(parsed) == 0 {
+		c.UI.Error("No config entries found in the input")
+		return 1
+	}
+	if c.cas && len(parsed) > 1 {
+		c.UI.Error("The -cas flag can only be used when writing a single config entry")
+		return 1
+	}
 
 	client, err := c.http.APIClient()
 	if err != nil {
@@ -77,22 +101,40 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
-	entries := client.ConfigEntries()
+	// Entries are written in order. On the first failure the remaining
+	// entries are skipped, and the entries already written are kept.
+	for i, entry := range parsed {
+		if err := c.writeEntry(client, entry); err != nil {
+			c.UI.Error(err.Error())
+			if len(parsed) > 1 {
+				c.UI.Error(fmt.Sprintf("Stopped at entry %d of %d; %d config entries were written", i+1, len(parsed), i))
+			}
+			return 1
+		}
+	}
+
+	return 0
+}
 
-	written := false
+// writeEntry writes a single config entry and, with -wait-sync, waits for it
+// to replicate.
+func (c *cmd) writeEntry(client *api.Client, entry api.ConfigEntry) error {
+	var (
+		written bool
+		err     error
+	)
+	entries := client.ConfigEntries()
 	if c.cas {
 		written, _, err = entries.CAS(entry, c.modifyIndex, nil)
 	} else {
 		written, _, err = entries.Set(entry, nil)
 	}
 	if err != nil {
-		c.UI.Error(fmt.Sprintf("Error writing config entry %s/%s: %v", entry.GetKind(), entry.GetName(), err))
-		return 1
+		return fmt.Errorf("Error writing config entry %s/%s: %v", entry.GetKind(), entry.GetName(), err)
 	}
 
 	if !written {
-		c.UI.Error(fmt.Sprintf("Config entry not updated: %s/%s", entry.GetKind(), entry.GetName()))
-		return 1
+		return fmt.Errorf("Config entry not updated: %s/%s", entry.GetKind(), entry.GetName())
 	}
 
 	c.UI.Info(fmt.Sprintf("Config entry written: %s/%s", entry.GetKind(), entry.GetName()))
@@ -101,7 +143,88 @@ func (c *cmd) Run(args []string) int {
 		c.UI.Warn("WARNING: " + msg)
 	}
 
-	return 0
+	if c.waitSync != "" {
+		if err := c.waitForSync(client, entry); err != nil {
+			return fmt.Errorf("Error waiting for config entry %s/%s to replicate: %v", entry.GetKind(), entry.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// waitForSync polls each datacenter named in -wait-sync until it serves the
+// same version of the entry as the primary datacenter. Secondaries store
+// replicated entries at their own raft indexes, so the primary's ModifyIndex
+// is used to pick the version to wait for and the entries are compared
+// without their indexes.
+func (c *cmd) waitForSync(client *api.Client, entry api.ConfigEntry) error {
+	self, err := client.Agent().Self()
+	if err != nil {
+		return fmt.Errorf("failed to look up the primary datacenter: %w", err)
+	}
+	primary, _ := self["Config"]["PrimaryDatacenter"].(string)
+	if primary == "" {
+		primary, _ = self["Config"]["Datacenter"].(string)
+	}
+
+	entries := client.ConfigEntries()
+	written, _, err := entries.Get(entry.GetKind(), entry.GetName(), &api.QueryOptions{
+		Datacenter:        primary,
+		RequireConsistent: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read the entry from the primary datacenter %q: %w", primary, err)
+	}
+	want, err := configEntryWithoutIndexes(written)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(c.waitSyncTimeout)
+	for _, dc := range strings.Split(c.waitSync, ",") {
+		dc = strings.TrimSpace(dc)
+		if dc == "" {
+			continue
+		}
+
+		for {
+			got, _, err := entries.Get(entry.GetKind(), entry.GetName(), &api.QueryOptions{Datacenter: dc})
+			if err == nil {
+				var have map[string]interface{}
+				have, err = configEntryWithoutIndexes(got)
+				if err == nil && reflect.DeepEqual(want, have) {
+					break
+				}
+			}
+
+			if time.Now().After(deadline) {
+				if err != nil {
+					return fmt.Errorf("timed out waiting for datacenter %q: %w", dc, err)
+				}
+				return fmt.Errorf("timed out waiting for datacenter %q: the config entry contents do not match the primary datacenter %q", dc, primary)
+			}
+			time.Sleep(waitSyncInterval)
+		}
+
+		c.UI.Info(fmt.Sprintf("Config entry replicated to %s", dc))
+	}
+	return nil
+}
+
+// configEntryWithoutIndexes returns the entry as a generic map without its
+// CreateIndex and ModifyIndex so copies from different datacenters can be
+// compared.
+func configEntryWithoutIndexes(entry api.ConfigEntry) (map[string]interface{}, error) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	delete(out, "CreateIndex")
+	delete(out, "ModifyIndex")
+	return out, nil
 }
 
 func (c *cmd) Synopsis() string {
@@ -122,6 +245,11 @@ Usage: consul config write [options] <configuration>
   should be read from stdin. The data should be either in HCL or
   JSON form.
 
+  Several config entries can be written at once from a JSON array, a
+  stream of JSON objects, or a document whose only top-level key is an
+  Entries list. They are written in order, stopping at the first entry
+  that fails. The -cas flag cannot be used with several entries.
+
   Example (from file):
 
     $ consul config write web.service.hcl
@@ -129,5 +257,9 @@ Usage: consul config write [options] <configuration>
   Example (from stdin):
 
     $ consul config write -
+
+  Example (wait for replication to secondary datacenters):
+
+    $ consul config write -wait-sync=dc2,dc3 web.service.hcl
 `
 )
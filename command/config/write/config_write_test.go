@@ -16,6 +16,8 @@ import (
 	"github.com/dhiaayachi/consul/api"
 	"github.com/dhiaayachi/consul/command/config"
 	"github.com/dhiaayachi/consul/sdk/testutil"
+	"github.com/dhiaayachi/consul/sdk/testutil/retry"
+	"github.com/dhiaayachi/consul/testrpc"
 )
 
 func TestConfigWrite_noTabs(t *testing.T) {
@@ -193,6 +195,91 @@ kind = "proxy-defaults"
 	})
 }
 
+func TestConfigWrite_MultipleEntries(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	run := func(t *testing.T, input string, extra ...string) (*cli.MockUi, int) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+		c.testStdin = bytes.NewBufferString(input)
+
+		args := append([]string{"-http-addr=" + a.HTTPAddr()}, extra...)
+		return ui, c.Run(append(args, "-"))
+	}
+
+	t.Run("HCL list", func(t *testing.T) {
+		ui, code := run(t, `
+Entries = [
+  {
+    Kind     = "service-defaults"
+    Name     = "web"
+    Protocol = "http"
+  },
+  {
+    Kind     = "service-defaults"
+    Name     = "api"
+    Protocol = "grpc"
+  },
+]
+`)
+		require.Empty(t, ui.ErrorWriter.String())
+		require.Equal(t, 0, code)
+		require.Equal(t, "Config entry written: service-defaults/web\n"+
+			"Config entry written: service-defaults/api\n", ui.OutputWriter.String())
+
+		entry, _, err := client.ConfigEntries().Get(api.ServiceDefaults, "api", nil)
+		require.NoError(t, err)
+		require.Equal(t, "grpc", entry.(*api.ServiceConfigEntry).Protocol)
+	})
+
+	t.Run("JSON stream", func(t *testing.T) {
+		ui, code := run(t, `
+{"Kind": "service-defaults", "Name": "db", "Protocol": "tcp"}
+{"Kind": "service-defaults", "Name": "cache", "Protocol": "tcp"}
+`)
+		require.Empty(t, ui.ErrorWriter.String())
+		require.Equal(t, 0, code)
+		require.Contains(t, ui.OutputWriter.String(), "Config entry written: service-defaults/cache")
+	})
+
+	t.Run("stops at the first failure", func(t *testing.T) {
+		ui, code := run(t, `[
+  {"Kind": "service-defaults", "Name": "first"},
+  {"Kind": "service-defaults", "Name": "second", "Protocol": "bogus"},
+  {"Kind": "service-defaults", "Name": "third"}
+]`)
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.OutputWriter.String(), "Config entry written: service-defaults/first")
+		require.Contains(t, ui.ErrorWriter.String(), "Error writing config entry service-defaults/second")
+		require.Contains(t, ui.ErrorWriter.String(), "Stopped at entry 2 of 3; 1 config entries were written")
+
+		entry, _, err := client.ConfigEntries().Get(api.ServiceDefaults, "first", nil)
+		require.NoError(t, err)
+		require.NotNil(t, entry)
+
+		_, _, err = client.ConfigEntries().Get(api.ServiceDefaults, "third", nil)
+		require.Error(t, err)
+	})
+
+	t.Run("cas is rejected", func(t *testing.T) {
+		ui, code := run(t, `[
+  {"Kind": "service-defaults", "Name": "web"},
+  {"Kind": "service-defaults", "Name": "api"}
+]`, "-cas", "-modify-index=1")
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "The -cas flag can only be used when writing a single config entry")
+		require.Empty(t, ui.OutputWriter.String())
+	})
+}
+
 func TestConfigWrite_Warning(t *testing.T) {
 	t.Parallel()
 
@@ -288,3 +375,71 @@ func requireContainsLower(t *testing.T, haystack, needle string) {
 	t.Helper()
 	require.Contains(t, strings.ToLower(haystack), strings.ToLower(needle))
 }
+
+func TestConfigWrite_WaitSync(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a1 := agent.NewTestAgent(t, `
+		primary_datacenter = "dc1"
+	`)
+	defer a1.Shutdown()
+	testrpc.WaitForLeader(t, a1.RPC, "dc1")
+
+	a2 := agent.NewTestAgent(t, `
+		datacenter = "dc2"
+		primary_datacenter = "dc1"
+	`)
+	defer a2.Shutdown()
+	testrpc.WaitForLeader(t, a2.RPC, "dc2")
+
+	_, err := a2.JoinWAN([]string{a1.Config.SerfBindAddrWAN.String()})
+	require.NoError(t, err)
+	retry.Run(t, func(r *retry.R) {
+		require.Len(r, a1.WANMembers(), 2)
+	})
+
+	f := testutil.TempFile(t, "config-write-svc-web.hcl")
+	_, err = f.WriteString(`
+		Kind = "service-defaults"
+		Name = "web"
+		Protocol = "http"
+	`)
+	require.NoError(t, err)
+
+	t.Run("replicated", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+
+		code := c.Run([]string{
+			"-http-addr=" + a1.HTTPAddr(),
+			"-wait-sync=dc2",
+			f.Name(),
+		})
+		require.Empty(t, ui.ErrorWriter.String())
+		require.Equal(t, 0, code)
+		require.Contains(t, ui.OutputWriter.String(), "Config entry replicated to dc2")
+
+		// The secondary must already have the entry once the command returns.
+		entry, _, err := a2.Client().ConfigEntries().Get(api.ServiceDefaults, "web", nil)
+		require.NoError(t, err)
+		require.Equal(t, "http", entry.(*api.ServiceConfigEntry).Protocol)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+
+		code := c.Run([]string{
+			"-http-addr=" + a1.HTTPAddr(),
+			"-wait-sync=dc3",
+			"-wait-sync-timeout=1s",
+			f.Name(),
+		})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), `timed out waiting for datacenter "dc3"`)
+	})
+}
@@ -4,9 +4,12 @@
 package get
 
 import (
+	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"flag"
 	"fmt"
+	"time"
 
 	"github.com/dhiaayachi/consul/api"
 	"github.com/dhiaayachi/consul/command/flags"
@@ -24,10 +27,21 @@ type cmd struct {
 	flags *flag.FlagSet
 	http  *flags.HTTPFlags
 	help  string
+
+	format string
 }
 
+const (
+	PrettyFormat string = "pretty"
+	JSONFormat   string = "json"
+)
+
 func (c *cmd) init() {
 	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.format, "format", PrettyFormat,
+		fmt.Sprintf("Output format {%s|%s}. The JSON output also includes the "+
+			"active root's validity period and whether a root rotation is in progress.",
+			PrettyFormat, JSONFormat))
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
@@ -43,6 +57,11 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	if c.format != PrettyFormat && c.format != JSONFormat {
+		c.UI.Error(fmt.Sprintf("Invalid format %q, must be one of %s|%s", c.format, PrettyFormat, JSONFormat))
+		return 1
+	}
+
 	// Set up a client.
 	client, err := c.http.APIClient()
 	if err != nil {
@@ -59,6 +78,27 @@ func (c *cmd) Run(args []string) int {
 		c.UI.Error(fmt.Sprintf("Error querying CA configuration: %s", err))
 		return 1
 	}
+
+	if c.format == JSONFormat {
+		roots, _, err := client.Connect().CARoots(opts)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error querying CA roots: %s", err))
+			return 1
+		}
+		status, err := newCAStatus(config, roots)
+		if err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+		out, err := json.MarshalIndent(status, "", "    ")
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error encoding CA configuration: %s", err))
+			return 1
+		}
+		c.UI.Output(string(out))
+		return 0
+	}
+
 	output, err := json.MarshalIndent(config, "", "\t")
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Error formatting CA configuration: %s", err))
@@ -68,6 +108,51 @@ func (c *cmd) Run(args []string) int {
 	return 0
 }
 
+// caStatus is the output of -format=json.
+type caStatus struct {
+	Config *api.CAConfig
+
+	// ActiveRoot is the root that currently signs leaf certificates.
+	ActiveRoot *caRootStatus `json:",omitempty"`
+
+	// RotationInProgress is true while roots that were rotated out are still
+	// trusted, which lasts until the leaf certificates they signed expire.
+	RotationInProgress bool
+}
+
+type caRootStatus struct {
+	ID        string
+	Name      string
+	NotBefore time.Time
+	NotAfter  time.Time
+}
+
+func newCAStatus(config *api.CAConfig, roots *api.CARootList) (*caStatus, error) {
+	status := &caStatus{Config: config}
+	for _, root := range roots.Roots {
+		if !root.Active {
+			status.RotationInProgress = true
+			continue
+		}
+
+		block, _ := pem.Decode([]byte(root.RootCertPEM))
+		if block == nil {
+			return nil, fmt.Errorf("Error decoding CA root %s: no PEM data found", root.ID)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing CA root %s: %s", root.ID, err)
+		}
+		status.ActiveRoot = &caRootStatus{
+			ID:        root.ID,
+			Name:      root.Name,
+			NotBefore: cert.NotBefore,
+			NotAfter:  cert.NotAfter,
+		}
+	}
+	return status, nil
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -4,8 +4,12 @@
 package get
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
 
 	"github.com/dhiaayachi/consul/testrpc"
 
@@ -43,3 +47,35 @@ func TestConnectCAGetConfigCommand(t *testing.T) {
 		t.Fatalf("bad: %s", output)
 	}
 }
+
+func TestConnectCAGetConfigCommand_JSONFormat(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+	args := []string{"-http-addr=" + a.HTTPAddr(), "-format=json"}
+
+	code := c.Run(args)
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+	var out map[string]interface{}
+	require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &out))
+
+	config, ok := out["Config"].(map[string]interface{})
+	require.True(t, ok, "missing Config: %s", ui.OutputWriter.String())
+	require.Equal(t, "consul", config["Provider"])
+
+	root, ok := out["ActiveRoot"].(map[string]interface{})
+	require.True(t, ok, "missing ActiveRoot: %s", ui.OutputWriter.String())
+	notAfter, err := time.Parse(time.RFC3339, root["NotAfter"].(string))
+	require.NoError(t, err)
+	require.True(t, notAfter.After(time.Now()))
+	require.Equal(t, false, out["RotationInProgress"])
+}
@@ -125,7 +125,9 @@ func (c *cmd) init() {
 		fmt.Sprintf("One or more types of information to capture. This can be used "+
 			"to capture a subset of information, and defaults to capturing "+
 			"everything available. Possible information for capture: %s. "+
-			"This can be repeated multiple times.", strings.Join(defaultTargets, ", ")))
+			"Additional information that is only captured when requested: %s. "+
+			"This can be repeated multiple times.", strings.Join(defaultTargets, ", "),
+			strings.Join(optionalTargets, ", ")))
 	c.flags.DurationVar(&c.interval, "interval", debugInterval,
 		fmt.Sprintf("The interval in which to capture dynamic information such as "+
 			"telemetry, and profiling. Defaults to %s.", debugInterval))
@@ -358,9 +360,164 @@ func (c *cmd) captureStatic() error {
 			errs = multierror.Append(errs, err)
 		}
 	}
+
+	if c.captureTarget(targetFailover) {
+		failover, err := c.captureFailover()
+		if err != nil {
+			errs = multierror.Append(errs, err)
+		}
+		if err := writeJSONFile(filepath.Join(c.output, targetFailover+".json"), failover); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+
+	if c.captureTarget(targetSessions) {
+		sessions, err := c.captureSessions()
+		if err != nil {
+			errs = multierror.Append(errs, err)
+		}
+		if err := writeJSONFile(filepath.Join(c.output, targetSessions+".json"), sessions); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
 	return errs
 }
 
+// failoverCapture describes the failover configured by a single
+// service-resolver config entry.
+type failoverCapture struct {
+	Resolver *api.ServiceResolverConfigEntry
+
+	// Targets holds the discovery targets that the resolver's failover
+	// resolves to, keyed by target ID, as compiled by the discovery chain.
+	Targets map[string]*api.DiscoveryTarget `json:",omitempty"`
+}
+
+// captureFailover collects every service-resolver config entry in the
+// agent's partition that configures failover, along with the destinations
+// that failover resolves to.
+func (c *cmd) captureFailover() ([]failoverCapture, error) {
+	entries, _, err := c.client.ConfigEntries().List(api.ServiceResolver, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service resolvers: %w", err)
+	}
+
+	var errs error
+	result := make([]failoverCapture, 0, len(entries))
+	for _, entry := range entries {
+		resolver, ok := entry.(*api.ServiceResolverConfigEntry)
+		if !ok || len(resolver.Failover) == 0 {
+			continue
+		}
+
+		fc := failoverCapture{Resolver: resolver}
+
+		q := &api.QueryOptions{Namespace: resolver.Namespace, Partition: resolver.Partition}
+		resp, _, err := c.client.DiscoveryChain().Get(resolver.Name, nil, q)
+		if err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("failed to compile discovery chain for %q: %w", resolver.Name, err))
+		} else if resp.Chain != nil {
+			fc.Targets = failoverTargets(resp.Chain)
+		}
+
+		result = append(result, fc)
+	}
+	return result, errs
+}
+
+// failoverTargets returns the targets referenced by failover on any resolver
+// node of the compiled chain.
+func failoverTargets(chain *api.CompiledDiscoveryChain) map[string]*api.DiscoveryTarget {
+	targets := make(map[string]*api.DiscoveryTarget)
+	for _, node := range chain.Nodes {
+		if node.Resolver == nil || node.Resolver.Failover == nil {
+			continue
+		}
+		for _, id := range node.Resolver.Failover.Targets {
+			if target, ok := chain.Targets[id]; ok {
+				targets[id] = target
+			}
+		}
+	}
+	return targets
+}
+
+// sessionCapture describes a single session along with the health checks it
+// is bound to and the KV keys it currently holds locks on.
+type sessionCapture struct {
+	Session *api.SessionEntry
+
+	// Checks holds the current state of the health checks that invalidate
+	// the session when they become critical.
+	Checks api.HealthChecks `json:",omitempty"`
+
+	// LockedKeys holds the KV keys currently locked by the session.
+	LockedKeys []string `json:",omitempty"`
+}
+
+// captureSessions collects every session in the datacenter, the health of
+// the checks each one is bound to, and the KV keys each one has locked.
+func (c *cmd) captureSessions() ([]sessionCapture, error) {
+	sessions, _, err := c.client.Session().List(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	var errs error
+	nodeChecks := make(map[string]api.HealthChecks)
+	result := make([]sessionCapture, 0, len(sessions))
+	for _, session := range sessions {
+		checks, ok := nodeChecks[session.Node]
+		if !ok {
+			checks, _, err = c.client.Health().Node(session.Node, nil)
+			if err != nil {
+				errs = multierror.Append(errs, fmt.Errorf("failed to get checks for node %q: %w", session.Node, err))
+			}
+			nodeChecks[session.Node] = checks
+		}
+
+		// Only the keys are kept, values never make it into the bundle.
+		pairs, _, err := c.client.KV().ListWithSession("", session.ID, nil)
+		if err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("failed to list keys locked by session %q: %w", session.ID, err))
+		}
+		var locked []string
+		for _, pair := range pairs {
+			locked = append(locked, pair.Key)
+		}
+
+		result = append(result, sessionCapture{
+			Session:    session,
+			Checks:     sessionChecks(session, checks),
+			LockedKeys: locked,
+		})
+	}
+	return result, errs
+}
+
+// sessionChecks returns the subset of a node's checks that the session is
+// bound to.
+func sessionChecks(session *api.SessionEntry, checks api.HealthChecks) api.HealthChecks {
+	ids := make(map[string]struct{})
+	for _, id := range session.Checks {
+		ids[id] = struct{}{}
+	}
+	for _, id := range session.NodeChecks {
+		ids[id] = struct{}{}
+	}
+	for _, sc := range session.ServiceChecks {
+		ids[sc.ID] = struct{}{}
+	}
+
+	var result api.HealthChecks
+	for _, check := range checks {
+		if _, ok := ids[check.CheckID]; ok {
+			result = append(result, check)
+		}
+	}
+	return result
+}
+
 func writeJSONFile(filename string, content interface{}) error {
 	marshaled, err := json.MarshalIndent(content, "", "\t")
 	if err != nil {
@@ -572,6 +729,11 @@ func allowedTarget(target string) bool {
 			return true
 		}
 	}
+	for _, t := range optionalTargets {
+		if t == target {
+			return true
+		}
+	}
 	for _, t := range deprecatedTargets {
 		if t == target {
 			return true
@@ -717,6 +879,8 @@ const (
 	targetHost     = "host"
 	targetAgent    = "agent"
 	targetMembers  = "members"
+	targetFailover = "failover"
+	targetSessions = "sessions"
 	// targetCluster is the now deprecated name for targetMembers
 	targetCluster = "cluster"
 )
@@ -731,6 +895,13 @@ var defaultTargets = []string{
 	targetMembers,
 }
 
+// optionalTargets specifies the list of targets that are only captured when
+// explicitly requested
+var optionalTargets = []string{
+	targetFailover,
+	targetSessions,
+}
+
 var deprecatedTargets = []string{targetCluster}
 
 func (c *cmd) Synopsis() string {
@@ -772,6 +943,11 @@ Usage: consul debug [options]
 
       $ consul debug -capture metrics -capture agent
 
+  Some information, such as the failover configuration of all service
+  resolvers, is only captured when requested.
+
+      $ consul debug -capture failover
+
   By default, the archive containing the debugging information is
   saved to the current directory as a .tar.gz file. The
   output path can be specified, as well as an option to disable
@@ -8,6 +8,7 @@ import (
 	"bufio"
 	"bytes"
 	"compress/gzip"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -26,6 +27,7 @@ import (
 	"gotest.tools/v3/fs"
 
 	"github.com/dhiaayachi/consul/agent"
+	"github.com/dhiaayachi/consul/api"
 	"github.com/dhiaayachi/consul/sdk/testutil"
 	"github.com/dhiaayachi/consul/testrpc"
 )
@@ -451,6 +453,124 @@ func TestDebugCommand_CaptureTargets(t *testing.T) {
 	}
 }
 
+func TestDebugCommand_CaptureSessions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	testDir := testutil.TempDir(t, "debug")
+
+	a := agent.NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	client := a.Client()
+	id, _, err := client.Session().Create(&api.SessionEntry{Name: "locker"}, nil)
+	require.NoError(t, err)
+
+	value := []byte("leader-secret")
+	acquired, _, err := client.KV().Acquire(&api.KVPair{Key: "service/web/leader", Value: value, Session: id}, nil)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	// Keys without a lock holder are not attributed to any session.
+	_, err = client.KV().Put(&api.KVPair{Key: "service/web/config"}, nil)
+	require.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	cmd := New(ui)
+	cmd.validateTiming = false
+
+	outputPath := fmt.Sprintf("%s/debug", testDir)
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-output=" + outputPath,
+		"-archive=false",
+		"-capture=sessions",
+	}
+
+	code := cmd.Run(args)
+	require.Equal(t, 0, code)
+	require.Equal(t, "", ui.ErrorWriter.String())
+
+	raw, err := os.ReadFile(filepath.Join(outputPath, "sessions.json"))
+	require.NoError(t, err)
+	require.NotContains(t, string(raw), base64.StdEncoding.EncodeToString(value))
+
+	var captured []sessionCapture
+	require.NoError(t, json.Unmarshal(raw, &captured))
+	require.Len(t, captured, 1)
+	require.Equal(t, id, captured[0].Session.ID)
+	require.Equal(t, "locker", captured[0].Session.Name)
+	require.Equal(t, a.Config.NodeName, captured[0].Session.Node)
+	require.Equal(t, []string{"service/web/leader"}, captured[0].LockedKeys)
+
+	require.Len(t, captured[0].Checks, 1)
+	require.Equal(t, "serfHealth", captured[0].Checks[0].CheckID)
+	require.Equal(t, api.HealthPassing, captured[0].Checks[0].Status)
+}
+
+func TestDebugCommand_CaptureFailover(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	testDir := testutil.TempDir(t, "debug")
+
+	a := agent.NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	client := a.Client()
+	_, _, err := client.ConfigEntries().Set(&api.ServiceResolverConfigEntry{
+		Kind: api.ServiceResolver,
+		Name: "web",
+		Failover: map[string]api.ServiceResolverFailover{
+			"*": {Datacenters: []string{"dc2"}},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	// Resolvers without failover are not captured.
+	_, _, err = client.ConfigEntries().Set(&api.ServiceResolverConfigEntry{
+		Kind:           api.ServiceResolver,
+		Name:           "api",
+		ConnectTimeout: 5 * time.Second,
+	}, nil)
+	require.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	cmd := New(ui)
+	cmd.validateTiming = false
+
+	outputPath := fmt.Sprintf("%s/debug", testDir)
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-output=" + outputPath,
+		"-archive=false",
+		"-capture=failover",
+	}
+
+	code := cmd.Run(args)
+	require.Equal(t, 0, code)
+	require.Equal(t, "", ui.ErrorWriter.String())
+
+	raw, err := os.ReadFile(filepath.Join(outputPath, "failover.json"))
+	require.NoError(t, err)
+
+	var captured []failoverCapture
+	require.NoError(t, json.Unmarshal(raw, &captured))
+	require.Len(t, captured, 1)
+	require.Equal(t, "web", captured[0].Resolver.Name)
+	require.Equal(t, []string{"dc2"}, captured[0].Resolver.Failover["*"].Datacenters)
+
+	require.Len(t, captured[0].Targets, 1)
+	for _, target := range captured[0].Targets {
+		require.Equal(t, "web", target.Service)
+		require.Equal(t, "dc2", target.Datacenter)
+	}
+}
+
 func validateLogLine(content []byte) bool {
 	fields := strings.SplitN(string(content), " ", 2)
 	if len(fields) != 2 {
@@ -5,6 +5,7 @@ package helpers
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -80,6 +81,96 @@ func ParseConfigEntry(data string) (api.ConfigEntry, error) {
 	return newDecodeConfigEntry(raw)
 }
 
+// ParseConfigEntries parses one or more config entries. Besides a single
+// entry, it accepts a JSON array of entries, a stream of JSON objects, or an
+// HCL or JSON document whose only top-level key is an Entries list. Entries are
+// returned in the order they appear in the input.
+func ParseConfigEntries(data string) ([]api.ConfigEntry, error) {
+	trimmed := strings.TrimSpace(data)
+	if strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "{") {
+		docs, err := splitJSONDocuments(trimmed)
+		if err == nil && (strings.HasPrefix(trimmed, "[") || len(docs) > 1) {
+			entries := make([]api.ConfigEntry, 0, len(docs))
+			for i, doc := range docs {
+				entry, err := ParseConfigEntry(string(doc))
+				if err != nil {
+					return nil, fmt.Errorf("entry %d: %w", i+1, err)
+				}
+				entries = append(entries, entry)
+			}
+			return entries, nil
+		}
+	}
+
+	var raw map[string]interface{}
+	if err := hclDecode(&raw, data); err != nil {
+		return nil, fmt.Errorf("Failed to decode config entry input: %v", err)
+	}
+
+	var list interface{}
+	for k, v := range raw {
+		if strings.ToLower(k) == "entries" && len(raw) == 1 {
+			list = v
+		}
+	}
+	if list == nil {
+		entry, err := newDecodeConfigEntry(raw)
+		if err != nil {
+			return nil, err
+		}
+		return []api.ConfigEntry{entry}, nil
+	}
+
+	var items []map[string]interface{}
+	switch v := list.(type) {
+	case []map[string]interface{}:
+		items = v
+	case []interface{}:
+		for _, item := range v {
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("Entries must be a list of config entries")
+			}
+			items = append(items, m)
+		}
+	default:
+		return nil, fmt.Errorf("Entries must be a list of config entries")
+	}
+	entries := make([]api.ConfigEntry, 0, len(items))
+	for i, item := range items {
+		entry, err := newDecodeConfigEntry(item)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %w", i+1, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// splitJSONDocuments splits a JSON array, or a stream of JSON values, into its
+// elements.
+func splitJSONDocuments(data string) ([]json.RawMessage, error) {
+	if strings.HasPrefix(data, "[") {
+		var docs []json.RawMessage
+		if err := json.Unmarshal([]byte(data), &docs); err != nil {
+			return nil, err
+		}
+		return docs, nil
+	}
+
+	var docs []json.RawMessage
+	dec := json.NewDecoder(strings.NewReader(data))
+	for {
+		var doc json.RawMessage
+		if err := dec.Decode(&doc); err == io.EOF {
+			return docs, nil
+		} else if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+}
+
 // There is a 'structs' variation of this in
 // agent/structs/config_entry.go:DecodeConfigEntry
 func newDecodeConfigEntry(raw map[string]interface{}) (api.ConfigEntry, error) {
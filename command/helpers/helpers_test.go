@@ -3392,6 +3392,90 @@ func TestParseConfigEntry(t *testing.T) {
 	}
 }
 
+func TestParseConfigEntries(t *testing.T) {
+	web := &api.ServiceConfigEntry{Kind: api.ServiceDefaults, Name: "web", Protocol: "http"}
+	global := &api.ProxyConfigEntry{Kind: api.ProxyDefaults, Name: api.ProxyConfigGlobal}
+
+	cases := map[string]struct {
+		input     string
+		expect    []api.ConfigEntry
+		expectErr string
+	}{
+		"single entry": {
+			input: `
+Kind     = "service-defaults"
+Name     = "web"
+Protocol = "http"
+`,
+			expect: []api.ConfigEntry{web},
+		},
+		"hcl list": {
+			input: `
+Entries = [
+  {
+    Kind     = "service-defaults"
+    Name     = "web"
+    Protocol = "http"
+  },
+  {
+    Kind = "proxy-defaults"
+    Name = "global"
+  },
+]
+`,
+			expect: []api.ConfigEntry{web, global},
+		},
+		"hcl blocks": {
+			input: `
+entries {
+  kind     = "service-defaults"
+  name     = "web"
+  protocol = "http"
+}
+entries {
+  kind = "proxy-defaults"
+  name = "global"
+}
+`,
+			expect: []api.ConfigEntry{web, global},
+		},
+		"json array": {
+			input: `[
+  {"Kind": "service-defaults", "Name": "web", "Protocol": "http"},
+  {"Kind": "proxy-defaults", "Name": "global"}
+]`,
+			expect: []api.ConfigEntry{web, global},
+		},
+		"json stream": {
+			input: `
+{"Kind": "service-defaults", "Name": "web", "Protocol": "http"}
+{"kind": "proxy-defaults", "name": "global"}
+`,
+			expect: []api.ConfigEntry{web, global},
+		},
+		"invalid entry in list": {
+			input:     `[{"Kind": "service-defaults", "Name": "web"}, {"Name": "api"}]`,
+			expectErr: "entry 2: ",
+		},
+		"entries is not a list": {
+			input:     `Entries = "web"`,
+			expectErr: "Entries must be a list of config entries",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			entries, err := ParseConfigEntries(tc.input)
+			if tc.expectErr != "" {
+				require.ErrorContains(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expect, entries)
+		})
+	}
+}
+
 func requireContainsLower(t *testing.T, haystack, needle string) {
 	t.Helper()
 	require.Contains(t, strings.ToLower(haystack), strings.ToLower(needle))
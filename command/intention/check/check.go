@@ -4,13 +4,17 @@
 package check
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
+	"sort"
+	"strings"
 
 	"github.com/dhiaayachi/consul/api"
 	"github.com/dhiaayachi/consul/command/flags"
 	"github.com/mitchellh/cli"
+	"github.com/ryanuber/columnize"
 )
 
 func New(ui cli.Ui) *cmd {
@@ -25,12 +29,28 @@ type cmd struct {
 	http  *flags.HTTPFlags
 	help  string
 
+	sourcePeer string
+	matrix     bool
+	format     string
+	limit      int
+
 	// testStdin is the input for testing.
 	testStdin io.Reader
 }
 
 func (c *cmd) init() {
 	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.sourcePeer, "source-peer", "",
+		"The cluster peer that SRC is imported from. By default SRC is a "+
+			"service in the local cluster.")
+	c.flags.BoolVar(&c.matrix, "matrix", false,
+		"Check every pair of services registered in the catalog and print "+
+			"the results as a matrix. No arguments are accepted in this mode.")
+	c.flags.StringVar(&c.format, "format", formatTable,
+		fmt.Sprintf("Output format of -matrix {%s|%s}.", formatTable, formatJSON))
+	c.flags.IntVar(&c.limit, "limit", defaultMatrixLimit,
+		"The maximum number of services -matrix will check. The command "+
+			"fails if more services are registered.")
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
@@ -44,6 +64,9 @@ func (c *cmd) Run(args []string) int {
 	}
 
 	args = c.flags.Args()
+	if c.matrix {
+		return c.runMatrix(args)
+	}
 	if len(args) != 2 {
 		c.UI.Error(fmt.Sprintf("Error: command requires exactly two arguments: src and dst"))
 		return 2
@@ -60,6 +83,7 @@ func (c *cmd) Run(args []string) int {
 	allowed, _, err := client.Connect().IntentionCheck(&api.IntentionCheck{
 		Source:      args[0],
 		Destination: args[1],
+		SourcePeer:  c.sourcePeer,
 		SourceType:  api.IntentionSourceConsul,
 	}, nil)
 	if err != nil {
@@ -76,6 +100,151 @@ func (c *cmd) Run(args []string) int {
 	return 1
 }
 
+// runMatrix checks every ordered pair of catalog services with a single
+// batch request.
+func (c *cmd) runMatrix(args []string) int {
+	if len(args) != 0 {
+		c.UI.Error("Error: -matrix does not accept arguments")
+		return 2
+	}
+	if c.sourcePeer != "" {
+		c.UI.Error("Error: -source-peer cannot be used with -matrix")
+		return 2
+	}
+	if c.format != formatTable && c.format != formatJSON {
+		c.UI.Error(fmt.Sprintf("Invalid format, valid formats are {%s|%s}", formatTable, formatJSON))
+		return 2
+	}
+	if c.limit < 1 {
+		c.UI.Error("Error: -limit must be at least 1")
+		return 2
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 2
+	}
+
+	// Only typical services make connections; proxies and gateways are
+	// left out.
+	catalog, _, err := client.Catalog().Services(&api.QueryOptions{Filter: `ServiceKind == ""`})
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error listing services: %s", err))
+		return 2
+	}
+	services := make([]string, 0, len(catalog))
+	for name := range catalog {
+		if name == "consul" {
+			continue
+		}
+		services = append(services, name)
+	}
+	sort.Strings(services)
+
+	if len(services) == 0 {
+		c.UI.Error("There are no services registered.")
+		return 2
+	}
+	if len(services) > c.limit {
+		c.UI.Error(fmt.Sprintf("Error: %d services are registered, more than the limit of %d; use -limit to raise it",
+			len(services), c.limit))
+		return 2
+	}
+
+	checks := make([]*api.IntentionCheck, 0, len(services)*(len(services)-1))
+	for _, src := range services {
+		for _, dst := range services {
+			if src == dst {
+				continue
+			}
+			checks = append(checks, &api.IntentionCheck{
+				Source:      src,
+				Destination: dst,
+				SourceType:  api.IntentionSourceConsul,
+			})
+		}
+	}
+
+	results, _, err := client.Connect().IntentionCheckBatch(checks, nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error checking the connections: %s", err))
+		return 2
+	}
+	if len(results) != len(checks) {
+		c.UI.Error(fmt.Sprintf("Error checking the connections: expected %d results, got %d", len(checks), len(results)))
+		return 2
+	}
+
+	matrix := matrixOutput{Services: services, Checks: make([]matrixCheck, 0, len(checks))}
+	for i, check := range checks {
+		matrix.Checks = append(matrix.Checks, matrixCheck{
+			Source:      check.Source,
+			Destination: check.Destination,
+			Allowed:     results[i].Allowed,
+			Error:       results[i].Error,
+		})
+	}
+
+	if c.format == formatJSON {
+		out, err := json.MarshalIndent(matrix, "", "    ")
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error encoding the matrix: %s", err))
+			return 2
+		}
+		c.UI.Output(string(out))
+		return 0
+	}
+
+	c.UI.Output(formatMatrix(matrix))
+	return 0
+}
+
+// matrixOutput is the result of -matrix. Checks holds one entry for each
+// ordered pair of distinct services.
+type matrixOutput struct {
+	Services []string
+	Checks   []matrixCheck
+}
+
+type matrixCheck struct {
+	Source      string
+	Destination string
+	Allowed     bool
+	Error       string `json:",omitempty"`
+}
+
+// formatMatrix renders the matrix with a row per source and a column per
+// destination.
+func formatMatrix(matrix matrixOutput) string {
+	cells := make(map[[2]string]string, len(matrix.Checks))
+	for _, check := range matrix.Checks {
+		cell := "deny"
+		switch {
+		case check.Error != "":
+			cell = "error"
+		case check.Allowed:
+			cell = "allow"
+		}
+		cells[[2]string{check.Source, check.Destination}] = cell
+	}
+
+	lines := make([]string, 0, len(matrix.Services)+1)
+	lines = append(lines, "Source \\ Destination\x1f"+strings.Join(matrix.Services, "\x1f"))
+	for _, src := range matrix.Services {
+		row := []string{src}
+		for _, dst := range matrix.Services {
+			cell, ok := cells[[2]string{src, dst}]
+			if !ok {
+				cell = "-"
+			}
+			row = append(row, cell)
+		}
+		lines = append(lines, strings.Join(row, "\x1f"))
+	}
+	return columnize.Format(lines, &columnize.Config{Delim: string([]byte{0x1f})})
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -84,15 +253,36 @@ func (c *cmd) Help() string {
 	return c.help
 }
 
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+
+	// defaultMatrixLimit keeps the default matrix at a size that is still
+	// readable in a terminal.
+	defaultMatrixLimit = 30
+)
+
 const (
 	synopsis = "Check whether a connection between two services is allowed."
 	help     = `
 Usage: consul intention check [options] SRC DST
+       consul intention check -matrix [options]
 
   Check whether a connection between SRC and DST would be allowed by
   Connect given the current Consul configuration.
 
       $ consul intention check web db
 
+  Check a connection from a service imported from a cluster peer:
+
+      $ consul intention check -source-peer=cluster-02 web db
+
+  Check every pair of services registered in the catalog:
+
+      $ consul intention check -matrix
+
+  Errors, such as a destination the token cannot read, are shown as "error"
+  in the matrix. Use -format=json to see the error messages.
+
 `
 )
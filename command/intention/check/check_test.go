@@ -4,6 +4,7 @@
 package check
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 
@@ -45,6 +46,21 @@ func TestIntentionCheck_Validation(t *testing.T) {
 			[]string{"a", "b", "c"},
 			"requires exactly two",
 		},
+
+		"matrix with args": {
+			[]string{"-matrix", "a", "b"},
+			"-matrix does not accept arguments",
+		},
+
+		"matrix with source peer": {
+			[]string{"-matrix", "-source-peer=cluster-02"},
+			"-source-peer cannot be used with -matrix",
+		},
+
+		"matrix with invalid format": {
+			[]string{"-matrix", "-format=yaml"},
+			"Invalid format",
+		},
 	}
 
 	for name, tc := range cases {
@@ -116,3 +132,71 @@ func TestIntentionCheck(t *testing.T) {
 		require.Contains(t, ui.OutputWriter.String(), "Denied")
 	}
 }
+
+func TestIntentionCheck_Matrix(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	for _, name := range []string{"api", "db", "web"} {
+		_, err := client.Catalog().Register(&api.CatalogRegistration{
+			Node:    "node1",
+			Address: "127.0.0.1",
+			Service: &api.AgentService{Service: name, Port: 8080},
+		}, nil)
+		require.NoError(t, err)
+	}
+
+	//nolint:staticcheck
+	_, _, err := client.Connect().IntentionCreate(&api.Intention{
+		SourceName:      "web",
+		DestinationName: "db",
+		Action:          api.IntentionActionDeny,
+	}, nil)
+	require.NoError(t, err)
+
+	t.Run("json", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+
+		args := []string{"-http-addr=" + a.HTTPAddr(), "-matrix", "-format=json"}
+		require.Equal(t, 0, c.Run(args), ui.ErrorWriter.String())
+
+		var out matrixOutput
+		require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &out))
+		require.Equal(t, []string{"api", "db", "web"}, out.Services)
+		require.Len(t, out.Checks, 6)
+		require.Contains(t, out.Checks, matrixCheck{Source: "web", Destination: "db", Allowed: false})
+		require.Contains(t, out.Checks, matrixCheck{Source: "api", Destination: "db", Allowed: true})
+	})
+
+	t.Run("table", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+
+		args := []string{"-http-addr=" + a.HTTPAddr(), "-matrix"}
+		require.Equal(t, 0, c.Run(args), ui.ErrorWriter.String())
+
+		lines := strings.Split(strings.TrimSpace(ui.OutputWriter.String()), "\n")
+		require.Len(t, lines, 4)
+		require.Equal(t, []string{"Source", "\\", "Destination", "api", "db", "web"}, strings.Fields(lines[0]))
+		require.Equal(t, []string{"web", "allow", "deny", "-"}, strings.Fields(lines[3]))
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+
+		args := []string{"-http-addr=" + a.HTTPAddr(), "-matrix", "-limit=2"}
+		require.Equal(t, 2, c.Run(args))
+		require.Contains(t, ui.ErrorWriter.String(), "3 services are registered, more than the limit of 2")
+	})
+}
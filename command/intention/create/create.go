@@ -35,6 +35,9 @@ type cmd struct {
 	flagReplace bool
 	flagMeta    map[string]string
 
+	flagExcludeSource string
+	flagAllowOthers   bool
+
 	// testStdin is the input for testing.
 	testStdin io.Reader
 }
@@ -48,10 +51,15 @@ func (c *cmd) init() {
 	c.flags.BoolVar(&c.flagFile, "file", false,
 		"Read intention data from one or more files.")
 	c.flags.BoolVar(&c.flagReplace, "replace", false,
-		"Replace matching intentions.")
+		"Replace matching intentions, or create them if they do not exist.")
 	c.flags.Var((*flags.FlagMapValue)(&c.flagMeta), "meta",
 		"Metadata to set on the intention, formatted as key=value. This flag "+
 			"may be specified multiple times to set multiple meta fields.")
+	c.flags.StringVar(&c.flagExcludeSource, "exclude-source", "",
+		"Deny the given source while allowing every other source to the "+
+			"destination. Must be used together with -allow-others.")
+	c.flags.BoolVar(&c.flagAllowOthers, "allow-others", false,
+		"Allow all sources other than the one given by -exclude-source.")
 
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
@@ -65,6 +73,10 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	if c.flagExcludeSource != "" || c.flagAllowOthers {
+		return c.runExcludeSource()
+	}
+
 	// Default to allow
 	if !c.flagAllow && !c.flagDeny {
 		c.flagAllow = true
@@ -93,40 +105,27 @@ func (c *cmd) Run(args []string) int {
 
 	// Go through and create each intention
 	for _, ixn := range ixns {
-		// If replace is set to true, then perform an update operation.
+		// If replace is set to true, upsert the intention. This updates the
+		// source intention on the destination's config entry if it exists and
+		// creates it otherwise.
 		if c.flagReplace {
-			oldIxn, _, err := client.Connect().IntentionGetExact(
-				intention.FormatSource(ixn),
-				intention.FormatDestination(ixn),
-				nil,
-			)
+			updated, err := replaceIntention(client, ixn)
 			if err != nil {
 				c.UI.Error(fmt.Sprintf(
-					"Error looking up intention for replacement with source %q "+
+					"Error replacing intention with source %q "+
 						"and destination %q: %s",
 					intention.FormatSource(ixn),
 					intention.FormatDestination(ixn),
 					err))
 				return 1
 			}
-			if oldIxn != nil {
-				// We set the ID of our intention so we overwrite it
-				ixn.ID = oldIxn.ID
-
-				//nolint:staticcheck
-				if _, err := client.Connect().IntentionUpdate(ixn, nil); err != nil {
-					c.UI.Error(fmt.Sprintf(
-						"Error replacing intention with source %q "+
-							"and destination %q: %s",
-						intention.FormatSource(ixn),
-						intention.FormatDestination(ixn),
-						err))
-					return 1
-				}
-
-				// Continue since we don't want to try to insert a new intention
-				continue
+
+			if updated {
+				c.UI.Output(fmt.Sprintf("Updated: %s", ixn))
+			} else {
+				c.UI.Output(fmt.Sprintf("Created: %s", ixn))
 			}
+			continue
 		}
 
 		//nolint:staticcheck
@@ -142,6 +141,121 @@ func (c *cmd) Run(args []string) int {
 	return 0
 }
 
+// runExcludeSource creates an intention that allows every source except the
+// one given by -exclude-source. A wildcard allow and an exact-match deny are
+// upserted on the destination. An exact source always takes precedence over
+// the wildcard, so the excluded source is denied while all others are allowed.
+func (c *cmd) runExcludeSource() int {
+	if c.flagExcludeSource == "" || !c.flagAllowOthers {
+		c.UI.Error("The -exclude-source and -allow-others flags must be specified together.")
+		return 1
+	}
+	if c.flagAllow || c.flagDeny || c.flagFile {
+		c.UI.Error("The -exclude-source flag cannot be combined with -allow, -deny or -file.")
+		return 1
+	}
+
+	args := c.flags.Args()
+	if len(args) != 1 {
+		c.UI.Error("Must specify exactly one argument: destination")
+		return 1
+	}
+
+	srcName, srcNS, srcPart, err := intention.ParseIntentionTarget(c.flagExcludeSource)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error: Invalid intention source: %v", err))
+		return 1
+	}
+	if srcName == "*" {
+		c.UI.Error("Error: The -exclude-source flag must name a specific service.")
+		return 1
+	}
+
+	dstName, dstNS, dstPart, err := intention.ParseIntentionTarget(args[0])
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error: Invalid intention destination: %v", err))
+		return 1
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	newIntention := func(name string, action api.IntentionAction) *api.Intention {
+		return &api.Intention{
+			SourcePartition:      srcPart,
+			SourceNS:             srcNS,
+			SourceName:           name,
+			DestinationPartition: dstPart,
+			DestinationNS:        dstNS,
+			DestinationName:      dstName,
+			SourceType:           api.IntentionSourceConsul,
+			Action:               action,
+		}
+	}
+	// The deny is written first so that the excluded source is never allowed
+	// by the wildcard on its own.
+	ixns := []*api.Intention{
+		newIntention(srcName, api.IntentionActionDeny),
+		newIntention("*", api.IntentionActionAllow),
+	}
+
+	if !c.flagReplace {
+		for _, ixn := range ixns {
+			existing, _, err := client.Connect().IntentionGetExact(
+				intention.FormatSource(ixn), intention.FormatDestination(ixn), nil)
+			if err != nil {
+				c.UI.Error(fmt.Sprintf("Error fetching existing intentions for %q: %s", args[0], err))
+				return 1
+			}
+			if existing != nil {
+				c.UI.Error(fmt.Sprintf(
+					"Error: An intention with source %q and destination %q already exists. "+
+						"Use -replace to replace it.", intention.FormatSource(ixn), args[0]))
+				return 1
+			}
+		}
+	}
+
+	for _, ixn := range ixns {
+		if _, err := client.Connect().IntentionUpsert(ixn, nil); err != nil {
+			c.UI.Error(fmt.Sprintf("Error writing intention %q: %s", ixn, err))
+			return 1
+		}
+		c.UI.Output(fmt.Sprintf("Created: %s", ixn))
+	}
+	return 0
+}
+
+// replaceIntention replaces the intention from the same source to the same
+// destination as ixn, or creates it if there is none. The replacement is
+// applied by the servers so the state kept for the destination's other sources
+// is preserved: legacy intentions are updated by ID and all others are
+// upserted. It returns true if an existing intention was replaced.
+func replaceIntention(client *api.Client, ixn *api.Intention) (bool, error) {
+	existing, _, err := client.Connect().IntentionGetExact(
+		intention.FormatSource(ixn), intention.FormatDestination(ixn), nil)
+	if err != nil {
+		return false, err
+	}
+
+	if existing != nil && existing.ID != "" {
+		ixn.ID = existing.ID
+		//nolint:staticcheck
+		if _, err := client.Connect().IntentionUpdate(ixn, nil); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	if _, err := client.Connect().IntentionUpsert(ixn, nil); err != nil {
+		return false, err
+	}
+	return existing != nil, nil
+}
+
 // ixnsFromArgs returns the set of intentions to create based on the arguments
 // given and the flags set. This will call ixnsFromFiles if the -file flag
 // was set.
@@ -258,6 +372,12 @@ Usage: consul intention create [options] -file FILE...
   Metadata and any other fields of the previous intention will not be
   preserved.
 
+  To allow every source except one, specify the source to exclude along with
+  the "-allow-others" flag and only the destination. Both rules are written
+  in a single operation:
+
+      $ consul intention create -exclude-source web -allow-others db
+
   Additional flags and more advanced use cases are detailed below.
 `
 )
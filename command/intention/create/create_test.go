@@ -36,6 +36,22 @@ func TestIntentionCreate_Validation(t *testing.T) {
 			[]string{"-allow", "-deny", "foo", "bar"},
 			"one of -allow",
 		},
+		"-exclude-source without -allow-others": {
+			[]string{"-exclude-source", "foo", "bar"},
+			"must be specified together",
+		},
+		"-exclude-source with -deny": {
+			[]string{"-exclude-source", "foo", "-allow-others", "-deny", "bar"},
+			"cannot be combined",
+		},
+		"-exclude-source with two args": {
+			[]string{"-exclude-source", "foo", "-allow-others", "foo", "bar"},
+			"exactly one argument",
+		},
+		"-exclude-source wildcard": {
+			[]string{"-exclude-source", "*", "-allow-others", "bar"},
+			"specific service",
+		},
 	}
 
 	for name, tc := range cases {
@@ -324,3 +340,200 @@ func TestIntentionCreate_replace(t *testing.T) {
 		require.Equal(t, api.IntentionActionDeny, ixns[0].Action)
 	}
 }
+
+func TestIntentionCreate_replaceConfigEntry(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	// Intentions written as a config entry have no legacy ID.
+	_, _, err := client.ConfigEntries().Set(&api.ServiceIntentionsConfigEntry{
+		Kind: api.ServiceIntentions,
+		Name: "bar",
+		Sources: []*api.SourceIntention{
+			{Name: "foo", Action: api.IntentionActionAllow},
+			{Name: "baz", Action: api.IntentionActionAllow},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-replace",
+		"-deny",
+		"foo", "bar",
+	}
+
+	// Replacing is idempotent.
+	for i := 0; i < 2; i++ {
+		ui := cli.NewMockUi()
+		c := New(ui)
+		require.Equal(t, 0, c.Run(args), ui.ErrorWriter.String())
+		require.Contains(t, ui.OutputWriter.String(), "Updated: foo => bar (deny)")
+
+		entry, _, err := client.ConfigEntries().Get(api.ServiceIntentions, "bar", nil)
+		require.NoError(t, err)
+		sources := entry.(*api.ServiceIntentionsConfigEntry).Sources
+		require.Len(t, sources, 2)
+		for _, src := range sources {
+			switch src.Name {
+			case "foo":
+				require.Equal(t, api.IntentionActionDeny, src.Action)
+			case "baz":
+				require.Equal(t, api.IntentionActionAllow, src.Action)
+			default:
+				t.Fatalf("unexpected source %q", src.Name)
+			}
+		}
+	}
+
+	// A missing intention is created.
+	ui := cli.NewMockUi()
+	c := New(ui)
+	require.Equal(t, 0, c.Run([]string{"-http-addr=" + a.HTTPAddr(), "-replace", "web", "db"}), ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "Created: web => db (allow)")
+
+	ixn, _, err := client.Connect().IntentionGetExact("web", "db", nil)
+	require.NoError(t, err)
+	require.NotNil(t, ixn)
+	require.Equal(t, api.IntentionActionAllow, ixn.Action)
+
+	// Metadata can't be set on an intention stored in a config entry.
+	ui = cli.NewMockUi()
+	c = New(ui)
+	require.Equal(t, 1, c.Run([]string{"-http-addr=" + a.HTTPAddr(), "-replace", "-deny", "-meta", "k=v", "web", "db"}))
+	require.Contains(t, ui.ErrorWriter.String(), "Meta must not be specified")
+}
+
+func TestIntentionCreate_replaceKeepsOtherSources(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	// Legacy intentions have an ID, timestamps and their own metadata, which
+	// are kept on their source in the destination's config entry.
+	legacy := func(source string) *api.Intention {
+		//nolint:staticcheck
+		_, _, err := client.Connect().IntentionCreate(&api.Intention{
+			SourceName:      source,
+			DestinationName: "db",
+			SourceType:      api.IntentionSourceConsul,
+			Action:          api.IntentionActionAllow,
+			Meta:            map[string]string{"owner": source},
+		}, nil)
+		require.NoError(t, err)
+
+		ixn, _, err := client.Connect().IntentionGetExact(source, "db", nil)
+		require.NoError(t, err)
+		require.NotEmpty(t, ixn.ID)
+		return ixn
+	}
+	before := legacy("api")
+	web := legacy("web")
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+	require.Equal(t, 0, c.Run([]string{"-http-addr=" + a.HTTPAddr(), "-replace", "-deny", "-meta", "owner=web", "web", "db"}), ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "Updated: web => db (deny)")
+
+	replaced, _, err := client.Connect().IntentionGetExact("web", "db", nil)
+	require.NoError(t, err)
+	require.Equal(t, web.ID, replaced.ID)
+	require.Equal(t, api.IntentionActionDeny, replaced.Action)
+
+	after, _, err := client.Connect().IntentionGetExact("api", "db", nil)
+	require.NoError(t, err)
+	require.Equal(t, before.ID, after.ID)
+	require.Equal(t, before.Meta, after.Meta)
+	require.Equal(t, before.CreatedAt, after.CreatedAt)
+	require.Equal(t, before.UpdatedAt, after.UpdatedAt)
+}
+
+func TestIntentionCreate_excludeSource(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	// An unrelated source that must be preserved.
+	{
+		ui := cli.NewMockUi()
+		c := New(ui)
+
+		args := []string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-deny",
+			"api", "db",
+		}
+		require.Equal(t, 0, c.Run(args), ui.ErrorWriter.String())
+	}
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+		"-exclude-source", "web",
+		"-allow-others",
+		"db",
+	}
+	require.Equal(t, 0, c.Run(args), ui.ErrorWriter.String())
+
+	check := func(source string) bool {
+		allowed, _, err := client.Connect().IntentionCheck(&api.IntentionCheck{
+			Source:      source,
+			Destination: "db",
+		}, nil)
+		require.NoError(t, err)
+		return allowed
+	}
+	require.False(t, check("web"))
+	require.True(t, check("billing"))
+	require.False(t, check("api"))
+
+	ixns, _, err := client.Connect().Intentions(nil)
+	require.NoError(t, err)
+	require.Len(t, ixns, 3)
+
+	// Running it again without -replace conflicts with the existing rules.
+	{
+		ui := cli.NewMockUi()
+		c := New(ui)
+		require.Equal(t, 1, c.Run(args), ui.ErrorWriter.String())
+		require.Contains(t, ui.ErrorWriter.String(), "already exists")
+	}
+
+	// With -replace it is idempotent.
+	{
+		ui := cli.NewMockUi()
+		c := New(ui)
+		require.Equal(t, 0, c.Run(append([]string{"-replace"}, args...)), ui.ErrorWriter.String())
+
+		ixns, _, err := client.Connect().Intentions(nil)
+		require.NoError(t, err)
+		require.Len(t, ixns, 3)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package exp
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+
+	"github.com/dhiaayachi/consul/command/flags"
+	"github.com/mitchellh/cli"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	flags.Merge(c.flags, c.http.MultiTenancyFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if len(c.flags.Args()) != 0 {
+		c.UI.Error(fmt.Sprintf("Too many arguments (expected 0, got %d)", len(c.flags.Args())))
+		return 1
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	ixns, _, err := client.Connect().Intentions(nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to retrieve the intentions list: %s", err))
+		return 1
+	}
+
+	// The raft indexes are meaningless to another cluster and are assigned
+	// again on import.
+	for _, ixn := range ixns {
+		ixn.CreateIndex, ixn.ModifyIndex = 0, 0
+	}
+
+	b, err := json.MarshalIndent(ixns, "", "    ")
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to encode the intentions: %s", err))
+		return 1
+	}
+	c.UI.Output(string(b))
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const (
+	synopsis = "Export intentions as JSON."
+	help     = `
+Usage: consul intention export [options]
+
+  Write all intentions to stdout as a JSON array that can be read back with
+  "consul intention import".
+
+  Intentions that were created with the legacy ID-based API keep their ID
+  and their CreatedAt and UpdatedAt timestamps in the export, so tooling
+  that refers to them by ID keeps working after a round trip.
+
+      $ consul intention export > intentions.json
+`
+)
@@ -0,0 +1,60 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package exp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dhiaayachi/consul/agent"
+	"github.com/dhiaayachi/consul/api"
+	"github.com/dhiaayachi/consul/sdk/testutil/retry"
+)
+
+func TestIntentionExportCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestIntentionExportCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	var id string
+	retry.Run(t, func(r *retry.R) {
+		var err error
+		//nolint:staticcheck
+		id, _, err = client.Connect().IntentionCreate(&api.Intention{
+			SourceName:      "web",
+			DestinationName: "db",
+			Action:          api.IntentionActionAllow,
+		}, nil)
+		require.NoError(r, err)
+	})
+
+	ui := cli.NewMockUi()
+	cmd := New(ui)
+	require.Equal(t, 0, cmd.Run([]string{"-http-addr=" + a.HTTPAddr()}), ui.ErrorWriter.String())
+
+	var exported []*api.Intention
+	require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &exported))
+	require.Len(t, exported, 1)
+	require.Equal(t, id, exported[0].ID)
+	require.Equal(t, "web", exported[0].SourceName)
+	require.Equal(t, "db", exported[0].DestinationName)
+	require.False(t, exported[0].CreatedAt.IsZero())
+	require.Zero(t, exported[0].CreateIndex)
+}
@@ -0,0 +1,117 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package imp
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/dhiaayachi/consul/api"
+	"github.com/dhiaayachi/consul/command/flags"
+	"github.com/dhiaayachi/consul/command/helpers"
+	"github.com/mitchellh/cli"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	// testStdin is the input for testing.
+	testStdin io.Reader
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	flags.Merge(c.flags, c.http.MultiTenancyFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = c.flags.Args()
+	switch len(args) {
+	case 0:
+		c.UI.Error("Missing FILE argument")
+		return 1
+	case 1:
+	default:
+		c.UI.Error(fmt.Sprintf("Too many arguments (expected 1, got %d)", len(args)))
+		return 1
+	}
+
+	data, err := helpers.LoadDataSourceNoRaw(args[0], c.testStdin)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to load data: %v", err))
+		return 1
+	}
+
+	var ixns []*api.Intention
+	if err := json.Unmarshal([]byte(data), &ixns); err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to decode intentions: %v", err))
+		return 1
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	for _, ixn := range ixns {
+		id, _, err := client.Connect().IntentionImport(ixn, nil)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error importing intention %q => %q: %s",
+				ixn.SourceString(), ixn.DestinationString(), err))
+			return 1
+		}
+		if id != "" {
+			c.UI.Info(fmt.Sprintf("Imported %s => %s (ID: %s)", ixn.SourceString(), ixn.DestinationString(), id))
+		} else {
+			c.UI.Info(fmt.Sprintf("Imported %s => %s", ixn.SourceString(), ixn.DestinationString()))
+		}
+	}
+
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const (
+	synopsis = "Import intentions from JSON."
+	help     = `
+Usage: consul intention import [options] FILE
+
+  Write the intentions in FILE, as produced by "consul intention export".
+  Use "-" as FILE to read from stdin.
+
+  Intentions with an ID are recreated as legacy intentions keeping their ID
+  and their CreatedAt and UpdatedAt timestamps. An intention with that ID
+  must not already exist. Intentions without an ID are upserted.
+
+      $ consul intention import intentions.json
+`
+)
@@ -0,0 +1,129 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package imp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dhiaayachi/consul/agent"
+	"github.com/dhiaayachi/consul/api"
+	"github.com/dhiaayachi/consul/command/intention/exp"
+	"github.com/dhiaayachi/consul/sdk/testutil/retry"
+)
+
+func TestIntentionImportCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestIntentionImportCommand_Validation(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		args   []string
+		output string
+	}{
+		"no file": {
+			[]string{},
+			"Missing FILE argument",
+		},
+		"extra args": {
+			[]string{"foo", "bar"},
+			"Too many arguments",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			c := New(ui)
+
+			require.Equal(t, 1, c.Run(tc.args))
+			require.Contains(t, ui.ErrorWriter.String(), tc.output)
+		})
+	}
+}
+
+func TestIntentionImportCommand_RoundTrip(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	// A legacy intention with an ID and one written as a config entry.
+	var legacyID string
+	retry.Run(t, func(r *retry.R) {
+		var err error
+		//nolint:staticcheck
+		legacyID, _, err = client.Connect().IntentionCreate(&api.Intention{
+			SourceName:      "web",
+			DestinationName: "db",
+			Action:          api.IntentionActionAllow,
+		}, nil)
+		require.NoError(r, err)
+	})
+	_, err := client.Connect().IntentionUpsert(&api.Intention{
+		SourceName:      "web",
+		DestinationName: "api",
+		Action:          api.IntentionActionDeny,
+	}, nil)
+	require.NoError(t, err)
+
+	original, _, err := client.Connect().IntentionGet(legacyID, nil)
+	require.NoError(t, err)
+	require.NotNil(t, original)
+
+	// Export.
+	exportUI := cli.NewMockUi()
+	require.Equal(t, 0, exp.New(exportUI).Run([]string{"-http-addr=" + a.HTTPAddr()}), exportUI.ErrorWriter.String())
+	exported := exportUI.OutputWriter.String()
+
+	// Remove everything so the import has to recreate it.
+	_, err = client.Connect().IntentionDelete(legacyID, nil)
+	require.NoError(t, err)
+	_, err = client.Connect().IntentionDeleteExact("web", "api", nil)
+	require.NoError(t, err)
+
+	// Import.
+	ui := cli.NewMockUi()
+	c := New(ui)
+	c.testStdin = strings.NewReader(exported)
+	require.Equal(t, 0, c.Run([]string{"-http-addr=" + a.HTTPAddr(), "-"}), ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), legacyID)
+
+	imported, _, err := client.Connect().IntentionGet(legacyID, nil)
+	require.NoError(t, err)
+	require.NotNil(t, imported)
+	require.Equal(t, legacyID, imported.ID)
+	require.True(t, original.CreatedAt.Equal(imported.CreatedAt), "%v != %v", original.CreatedAt, imported.CreatedAt)
+	require.True(t, original.UpdatedAt.Equal(imported.UpdatedAt), "%v != %v", original.UpdatedAt, imported.UpdatedAt)
+
+	upserted, _, err := client.Connect().IntentionGetExact("web", "api", nil)
+	require.NoError(t, err)
+	require.NotNil(t, upserted)
+	require.Empty(t, upserted.ID)
+	require.Equal(t, api.IntentionActionDeny, upserted.Action)
+
+	// Importing the same export again collides on the legacy ID.
+	ui = cli.NewMockUi()
+	c = New(ui)
+	c.testStdin = strings.NewReader(exported)
+	require.Equal(t, 1, c.Run([]string{"-http-addr=" + a.HTTPAddr(), "-"}))
+	require.Contains(t, ui.ErrorWriter.String(), "already exists")
+
+	var check []*api.Intention
+	require.NoError(t, json.Unmarshal([]byte(exported), &check))
+	require.Len(t, check, 2)
+}
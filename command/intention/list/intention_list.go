@@ -6,7 +6,9 @@ package list
 import (
 	"flag"
 	"fmt"
+	"strings"
 
+	"github.com/dhiaayachi/consul/api"
 	"github.com/dhiaayachi/consul/command/flags"
 	"github.com/mitchellh/cli"
 	"github.com/ryanuber/columnize"
@@ -23,10 +25,26 @@ type cmd struct {
 	flags *flag.FlagSet
 	http  *flags.HTTPFlags
 	help  string
+
+	// flags
+	flagUnused bool
+	flagFormat string
 }
 
+const (
+	formatTable     = "table"
+	formatTableWide = "table-wide"
+)
+
 func (c *cmd) init() {
 	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.BoolVar(&c.flagUnused, "unused", false,
+		"Only list intentions that have not decided any intention check served "+
+			"by the responding server since it started.")
+	c.flags.StringVar(&c.flagFormat, "format", formatTable,
+		fmt.Sprintf("Output format {%s|%s}. The %s format adds a column that "+
+			"summarizes the L7 permissions of each intention.",
+			formatTable, formatTableWide, formatTableWide))
 
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
@@ -40,29 +58,51 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	if c.flagFormat != formatTable && c.flagFormat != formatTableWide {
+		c.UI.Error(fmt.Sprintf("Invalid format, valid formats are {%s|%s}", formatTable, formatTableWide))
+		return 1
+	}
+
 	client, err := c.http.APIClient()
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
 		return 1
 	}
 
-	ixns, _, err := client.Connect().Intentions(nil)
+	var ixns []*api.Intention
+	if c.flagUnused {
+		ixns, _, err = client.Connect().IntentionsUnused(nil)
+	} else {
+		ixns, _, err = client.Connect().Intentions(nil)
+	}
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Failed to retrieve the intentions list: %s", err))
 		return 1
 	}
 
 	if len(ixns) == 0 {
-		c.UI.Error(fmt.Sprintf("There are no intentions."))
+		if c.flagUnused {
+			c.UI.Error("There are no unused intentions.")
+		} else {
+			c.UI.Error(fmt.Sprintf("There are no intentions."))
+		}
 		return 2
 	}
 
+	wide := c.flagFormat == formatTableWide
+
 	result := make([]string, 0, len(ixns))
 	header := "ID\x1fSource\x1fAction\x1fDestination\x1fPrecedence"
+	if wide {
+		header += "\x1fPermissions"
+	}
 	result = append(result, header)
 	for _, ixn := range ixns {
 		line := fmt.Sprintf("%s\x1f%s\x1f%s\x1f%s\x1f%d",
 			ixn.ID, ixn.SourceName, ixn.Action, ixn.DestinationName, ixn.Precedence)
+		if wide {
+			line += "\x1f" + permissionsSummary(ixn.Permissions)
+		}
 		result = append(result, line)
 	}
 
@@ -72,6 +112,41 @@ func (c *cmd) Run(args []string) int {
 	return 0
 }
 
+// permissionsSummary returns a one line summary of L7 permissions, such as
+// "allow methods=GET path_prefix=/api headers=1; deny path_exact=/admin". Header values are
+// left out to keep the table readable; use "consul intention get" for them.
+func permissionsSummary(perms []*api.IntentionPermission) string {
+	if len(perms) == 0 {
+		return "-"
+	}
+
+	summaries := make([]string, 0, len(perms))
+	for _, perm := range perms {
+		parts := []string{string(perm.Action)}
+		if http := perm.HTTP; http != nil {
+			if len(http.Methods) > 0 {
+				parts = append(parts, "methods="+strings.Join(http.Methods, ","))
+			}
+			switch {
+			case http.PathExact != "":
+				parts = append(parts, "path_exact="+http.PathExact)
+			case http.PathPrefix != "":
+				parts = append(parts, "path_prefix="+http.PathPrefix)
+			case http.PathRegex != "":
+				parts = append(parts, "path_regex="+http.PathRegex)
+			}
+			if len(http.Header) > 0 {
+				parts = append(parts, fmt.Sprintf("headers=%d", len(http.Header)))
+			}
+		}
+		if perm.JWT != nil && len(perm.JWT.Providers) > 0 {
+			parts = append(parts, fmt.Sprintf("jwt_providers=%d", len(perm.JWT.Providers)))
+		}
+		summaries = append(summaries, strings.Join(parts, " "))
+	}
+	return strings.Join(summaries, "; ")
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -86,5 +161,18 @@ const (
 Usage: consul intention list
 
   List all intentions.
+
+  List the intentions that have not decided any intention check since the
+  responding server started:
+
+      $ consul intention list -unused
+
+  Include a summary of the L7 permissions of each intention:
+
+      $ consul intention list -format=table-wide
+
+  Match counts are kept in memory by each server and only cover checks made
+  through the intention check API. Decisions made by client agents or
+  enforced by proxies are not counted.
 `
 )
@@ -53,3 +53,124 @@ func TestIntentionListCommand(t *testing.T) {
 	require.Equal(t, 0, cmd.Run(args), ui.ErrorWriter.String())
 	require.Contains(t, ui.OutputWriter.String(), id)
 }
+
+func TestIntentionListCommand_Unused(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	for _, src := range []string{"web", "api"} {
+		retry.Run(t, func(r *retry.R) {
+			_, err := client.Connect().IntentionUpsert(&api.Intention{
+				SourceName:      src,
+				DestinationName: "db",
+				Action:          api.IntentionActionAllow,
+			}, nil)
+			require.NoError(r, err)
+		})
+	}
+
+	// Only web => db decides a check.
+	allowed, _, err := client.Connect().IntentionCheck(&api.IntentionCheck{
+		Source:      "web",
+		Destination: "db",
+	}, nil)
+	require.NoError(t, err)
+	require.True(t, allowed)
+
+	ui := cli.NewMockUi()
+	cmd := New(ui)
+	args := []string{"-http-addr=" + a.HTTPAddr(), "-unused"}
+
+	require.Equal(t, 0, cmd.Run(args), ui.ErrorWriter.String())
+	output := ui.OutputWriter.String()
+	require.Contains(t, output, "api")
+	require.NotContains(t, output, "web")
+}
+
+func TestIntentionListCommand_TableWide(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	// L7 intentions require an HTTP destination.
+	retry.Run(t, func(r *retry.R) {
+		_, _, err := client.ConfigEntries().Set(&api.ProxyConfigEntry{
+			Kind:   api.ProxyDefaults,
+			Name:   api.ProxyConfigGlobal,
+			Config: map[string]interface{}{"protocol": "http"},
+		}, nil)
+		require.NoError(r, err)
+	})
+	_, _, err := client.ConfigEntries().Set(&api.ServiceIntentionsConfigEntry{
+		Kind: api.ServiceIntentions,
+		Name: "api",
+		Sources: []*api.SourceIntention{
+			{
+				Name: "web",
+				Permissions: []*api.IntentionPermission{
+					{
+						Action: api.IntentionActionAllow,
+						HTTP: &api.IntentionHTTPPermission{
+							PathPrefix: "/v1",
+							Methods:    []string{"GET", "HEAD"},
+							Header: []api.IntentionHTTPHeaderPermission{
+								{Name: "x-debug", Present: true},
+							},
+						},
+					},
+					{
+						Action: api.IntentionActionDeny,
+						HTTP: &api.IntentionHTTPPermission{
+							PathExact: "/admin",
+						},
+					},
+				},
+			},
+			{
+				Name:   "dashboard",
+				Action: api.IntentionActionAllow,
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	t.Run("table-wide", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+		args := []string{"-http-addr=" + a.HTTPAddr(), "-format=table-wide"}
+
+		require.Equal(t, 0, cmd.Run(args), ui.ErrorWriter.String())
+		output := ui.OutputWriter.String()
+		require.Contains(t, output, "Permissions")
+		require.Contains(t, output, "allow methods=GET,HEAD path_prefix=/v1 headers=1; deny path_exact=/admin")
+	})
+
+	t.Run("table", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+		args := []string{"-http-addr=" + a.HTTPAddr()}
+
+		require.Equal(t, 0, cmd.Run(args), ui.ErrorWriter.String())
+		require.NotContains(t, ui.OutputWriter.String(), "Permissions")
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := New(ui)
+		args := []string{"-http-addr=" + a.HTTPAddr(), "-format=json"}
+
+		require.Equal(t, 1, cmd.Run(args))
+		require.Contains(t, ui.ErrorWriter.String(), "Invalid format")
+	})
+}
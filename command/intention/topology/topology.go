@@ -0,0 +1,91 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package topology
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+
+	"github.com/dhiaayachi/consul/command/flags"
+	"github.com/mitchellh/cli"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	flags.Merge(c.flags, c.http.MultiTenancyFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = c.flags.Args()
+	if len(args) != 1 {
+		c.UI.Error("Error: command requires exactly one argument: src")
+		return 1
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
+		return 1
+	}
+
+	topology, _, err := client.Connect().IntentionTopology(args[0], nil)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error retrieving the topology: %s", err))
+		return 1
+	}
+
+	b, err := json.MarshalIndent(topology, "", "    ")
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to encode the topology: %s", err))
+		return 1
+	}
+	c.UI.Output(string(b))
+	return 0
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const (
+	synopsis = "Show the services a service can reach."
+	help     = `
+Usage: consul intention topology [options] SRC
+
+  Write the services that SRC can reach as JSON. Each upstream that
+  intentions allow SRC to connect to is listed with the targets its
+  discovery chain routes, splits or fails over to. Targets are checked
+  against the intentions of SRC as well, since a request routed to a
+  different service is only allowed if SRC may connect to that service.
+
+      $ consul intention topology web
+`
+)
@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package topology
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dhiaayachi/consul/agent"
+	"github.com/dhiaayachi/consul/api"
+	"github.com/dhiaayachi/consul/testrpc"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntentionTopology_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(nil).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestIntentionTopology_Validation(t *testing.T) {
+	t.Parallel()
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+
+	require.Equal(t, 1, c.Run([]string{"web", "db"}))
+	require.Contains(t, ui.ErrorWriter.String(), "requires exactly one argument")
+}
+
+func TestIntentionTopology(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	for _, svc := range []string{"web", "db", "cache"} {
+		_, err := client.Catalog().Register(&api.CatalogRegistration{
+			Node:    "node1",
+			Address: "127.0.0.1",
+			Service: &api.AgentService{Service: svc, Port: 8080},
+		}, nil)
+		require.NoError(t, err)
+	}
+
+	//nolint:staticcheck
+	_, _, err := client.Connect().IntentionCreate(&api.Intention{
+		SourceName:      "web",
+		DestinationName: "cache",
+		Action:          api.IntentionActionDeny,
+	}, nil)
+	require.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	c := New(ui)
+
+	args := []string{
+		"-http-addr=" + a.HTTPAddr(),
+		"web",
+	}
+	require.Equal(t, 0, c.Run(args), ui.ErrorWriter.String())
+
+	var topology api.IntentionTopology
+	require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &topology))
+	require.Len(t, topology.Upstreams, 1)
+	require.Equal(t, "db", topology.Upstreams[0].Name)
+	require.Len(t, topology.Upstreams[0].Targets, 1)
+	require.Equal(t, "db", topology.Upstreams[0].Targets[0].Service)
+	require.True(t, topology.Upstreams[0].Targets[0].Allowed)
+}
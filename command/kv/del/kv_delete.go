@@ -26,8 +26,17 @@ type cmd struct {
 	cas         bool
 	modifyIndex uint64
 	recurse     bool
+	casRecurse  bool
+
+	// testBeforeDelete is called in -cas-recurse mode after the keys under
+	// the prefix are read and before they are deleted. Used in tests.
+	testBeforeDelete func()
 }
 
+// maxCASRecurseKeys is the maximum number of keys that can be deleted with
+// -cas-recurse, since they are all deleted in a single transaction.
+const maxCASRecurseKeys = 128
+
 func (c *cmd) init() {
 	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
 	c.flags.BoolVar(&c.cas, "cas", false,
@@ -38,6 +47,10 @@ func (c *cmd) init() {
 			"used in combination with the -cas flag.")
 	c.flags.BoolVar(&c.recurse, "recurse", false,
 		"Recursively delete all keys with the path. The default value is false.")
+	c.flags.BoolVar(&c.casRecurse, "cas-recurse", false,
+		"Recursively delete all keys with the path in a single transaction, "+
+			"only if none of them has been modified since they were read. "+
+			"The default value is false.")
 
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
@@ -74,7 +87,7 @@ func (c *cmd) Run(args []string) int {
 
 	// If the key is empty and we are not doing a recursive delete, this is an
 	// error.
-	if key == "" && !c.recurse {
+	if key == "" && !c.recurse && !c.casRecurse {
 		c.UI.Error("Error! Missing KEY argument")
 		return 1
 	}
@@ -96,6 +109,10 @@ func (c *cmd) Run(args []string) int {
 		c.UI.Error("Cannot specify both -cas and -recurse!")
 		return 1
 	}
+	if c.casRecurse && c.cas {
+		c.UI.Error("Cannot specify both -cas and -cas-recurse!")
+		return 1
+	}
 
 	// Create and test the HTTP client
 	client, err := c.http.APIClient()
@@ -105,13 +122,24 @@ func (c *cmd) Run(args []string) int {
 	}
 
 	switch {
+	case c.casRecurse:
+		return c.deleteTreeCAS(client, key)
 	case c.recurse:
-		if _, err := client.KV().DeleteTree(key, nil); err != nil {
+		deleted, _, err := client.KV().DeleteTreeWithCount(key, nil)
+		if err != nil {
 			c.UI.Error(fmt.Sprintf("Error! Did not delete prefix %s: %s", key, err))
 			return 1
 		}
 
-		c.UI.Info(fmt.Sprintf("Success! Deleted keys with prefix: %s", key))
+		switch deleted {
+		case -1:
+			// The agent is too old to report the count.
+			c.UI.Info(fmt.Sprintf("Success! Deleted keys with prefix: %s", key))
+		case 1:
+			c.UI.Info(fmt.Sprintf("Success! Deleted 1 key with prefix: %s", key))
+		default:
+			c.UI.Info(fmt.Sprintf("Success! Deleted %d keys with prefix: %s", deleted, key))
+		}
 		return 0
 	case c.cas:
 		pair := &api.KVPair{
@@ -142,6 +170,57 @@ func (c *cmd) Run(args []string) int {
 	}
 }
 
+// deleteTreeCAS deletes all keys with the given prefix in a single
+// transaction. Each key is deleted with a check-and-set on the ModifyIndex
+// it had when read, so the whole delete is aborted if any key changed.
+func (c *cmd) deleteTreeCAS(client *api.Client, prefix string) int {
+	pairs, _, err := client.KV().List(prefix, &api.QueryOptions{RequireConsistent: true})
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error! Did not delete prefix %s: %s", prefix, err))
+		return 1
+	}
+	if len(pairs) > maxCASRecurseKeys {
+		c.UI.Error(fmt.Sprintf("Error! Did not delete prefix %s: %d keys exceed the limit of %d for -cas-recurse",
+			prefix, len(pairs), maxCASRecurseKeys))
+		return 1
+	}
+
+	if c.testBeforeDelete != nil {
+		c.testBeforeDelete()
+	}
+
+	if len(pairs) > 0 {
+		ops := make(api.TxnOps, 0, len(pairs))
+		for _, pair := range pairs {
+			ops = append(ops, &api.TxnOp{
+				KV: &api.KVTxnOp{
+					Verb:      api.KVDeleteCAS,
+					Key:       pair.Key,
+					Index:     pair.ModifyIndex,
+					Namespace: pair.Namespace,
+					Partition: pair.Partition,
+				},
+			})
+		}
+
+		ok, resp, _, err := client.Txn().Txn(ops, nil)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error! Did not delete prefix %s: %s", prefix, err))
+			return 1
+		}
+		if !ok {
+			for _, txnErr := range resp.Errors {
+				c.UI.Error(fmt.Sprintf("Error! Key %s changed: %s", pairs[txnErr.OpIndex].Key, txnErr.What))
+			}
+			c.UI.Error(fmt.Sprintf("Error! Did not delete prefix %s: CAS failed", prefix))
+			return 1
+		}
+	}
+
+	c.UI.Info(fmt.Sprintf("Success! Deleted keys with prefix: %s", prefix))
+	return 0
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -168,5 +247,10 @@ Usage: consul kv delete [options] KEY_OR_PREFIX
 
   This will delete the keys named "foo", "food", and "foo/bar/zip" if they
   existed.
+
+  To delete all keys which start with "foo" only if none of them is modified
+  while the command runs, specify the -cas-recurse option:
+
+      $ consul kv delete -cas-recurse foo
 `
 )
@@ -11,6 +11,7 @@ import (
 	"github.com/dhiaayachi/consul/agent"
 	"github.com/dhiaayachi/consul/api"
 	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
 )
 
 func TestKVDeleteCommand_noTabs(t *testing.T) {
@@ -37,6 +38,10 @@ func TestKVDeleteCommand_Validation(t *testing.T) {
 			[]string{"-cas", "foo"},
 			"Cannot delete a key that does not exist",
 		},
+		"-cas and -cas-recurse": {
+			[]string{"-cas", "-modify-index", "2", "-cas-recurse", "foo"},
+			"Cannot specify both -cas and -cas-recurse",
+		},
 		"-modify-index no -cas": {
 			[]string{"-modify-index", "2", "foo"},
 			"Cannot specify -modify-index without",
@@ -151,6 +156,10 @@ func TestKVDeleteCommand_Recurse(t *testing.T) {
 		t.Fatalf("bad: %d. %#v", code, ui.ErrorWriter.String())
 	}
 
+	if !strings.Contains(ui.OutputWriter.String(), "Deleted 3 keys with prefix: foo") {
+		t.Fatalf("bad: %#v", ui.OutputWriter.String())
+	}
+
 	for _, k := range keys {
 		pair, _, err := client.KV().Get(k, nil)
 		if err != nil {
@@ -160,6 +169,16 @@ func TestKVDeleteCommand_Recurse(t *testing.T) {
 			t.Fatalf("bad: %#v", pair)
 		}
 	}
+
+	// Deleting the prefix again is a no-op.
+	ui = cli.NewMockUi()
+	c = New(ui)
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d. %#v", code, ui.ErrorWriter.String())
+	}
+	if !strings.Contains(ui.OutputWriter.String(), "Deleted 0 keys with prefix: foo") {
+		t.Fatalf("bad: %#v", ui.OutputWriter.String())
+	}
 }
 
 func TestKVDeleteCommand_CAS(t *testing.T) {
@@ -225,3 +244,58 @@ func TestKVDeleteCommand_CAS(t *testing.T) {
 		t.Fatalf("bad: %#v", data)
 	}
 }
+
+func TestKVDeleteCommand_CASRecurse(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	keys := []string{"foo/a", "foo/b", "food"}
+	putKeys := func(t *testing.T) {
+		for _, k := range keys {
+			_, err := client.KV().Put(&api.KVPair{Key: k, Value: []byte("bar")}, nil)
+			require.NoError(t, err)
+		}
+	}
+
+	t.Run("aborted when a key changes", func(t *testing.T) {
+		putKeys(t)
+
+		ui := cli.NewMockUi()
+		c := New(ui)
+		c.testBeforeDelete = func() {
+			_, err := client.KV().Put(&api.KVPair{Key: "foo/b", Value: []byte("changed")}, nil)
+			require.NoError(t, err)
+		}
+
+		code := c.Run([]string{"-http-addr=" + a.HTTPAddr(), "-cas-recurse", "foo"})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "Key foo/b changed")
+		require.Contains(t, ui.ErrorWriter.String(), "Did not delete prefix foo: CAS failed")
+
+		// No key was deleted
+		pairs, _, err := client.KV().List("foo", nil)
+		require.NoError(t, err)
+		require.Len(t, pairs, len(keys))
+	})
+
+	t.Run("deleted when unchanged", func(t *testing.T) {
+		putKeys(t)
+
+		ui := cli.NewMockUi()
+		c := New(ui)
+
+		code := c.Run([]string{"-http-addr=" + a.HTTPAddr(), "-cas-recurse", "foo"})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+		require.Contains(t, ui.OutputWriter.String(), "Deleted keys with prefix: foo")
+
+		pairs, _, err := client.KV().List("foo", nil)
+		require.NoError(t, err)
+		require.Empty(t, pairs)
+	})
+}
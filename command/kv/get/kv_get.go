@@ -6,9 +6,11 @@ package get
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/dhiaayachi/consul/api"
@@ -31,7 +33,16 @@ type cmd struct {
 	detailed     bool
 	keys         bool
 	recurse      bool
+	asTree       bool
 	separator    string
+	showFlags    bool
+	flagsFilter  uint64
+
+	// filterFlags is true if -flags-filter was given.
+	filterFlags bool
+
+	// separatorSet is true if -separator was given.
+	separatorSet bool
 }
 
 func (c *cmd) init() {
@@ -50,9 +61,22 @@ func (c *cmd) init() {
 	c.flags.BoolVar(&c.recurse, "recurse", false,
 		"Recursively look at all keys prefixed with the given path. The default "+
 			"value is false.")
+	c.flags.BoolVar(&c.asTree, "as-tree", false,
+		"Output the result of a recursive lookup as a JSON object nested by the "+
+			"\"/\" separated segments of each key. A key that is also a prefix of "+
+			"other keys has its value stored under the \"\" member of its object. "+
+			"Requires -recurse. The default value is false.")
 	c.flags.StringVar(&c.separator, "separator", "/",
 		"String to use as a separator between keys. The default value is \"/\", "+
-			"but this option is only taken into account when paired with the -keys flag.")
+			"but this option is only taken into account when paired with the -keys flag. "+
+			"When combined with -keys and -recurse, keys are only collapsed at the "+
+			"separator if this option is given explicitly.")
+	c.flags.BoolVar(&c.showFlags, "flags", false,
+		"Include the flags of each key in the output, after the key name and "+
+			"before the value, separated by colons. The default value is false.")
+	c.flags.Uint64Var(&c.flagsFilter, "flags-filter", 0,
+		"Only list the keys whose flags equal this unsigned integer. The keys "+
+			"are filtered by the servers. Requires -recurse.")
 
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
@@ -65,6 +89,14 @@ func (c *cmd) Run(args []string) int {
 	if err := c.flags.Parse(args); err != nil {
 		return 1
 	}
+	c.flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "flags-filter":
+			c.filterFlags = true
+		case "separator":
+			c.separatorSet = true
+		}
+	})
 
 	key := ""
 
@@ -94,6 +126,22 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	if c.asTree {
+		if !c.recurse {
+			c.UI.Error("Error! The -as-tree flag requires -recurse")
+			return 1
+		}
+		if c.keys || c.detailed || c.showFlags {
+			c.UI.Error("Error! The -as-tree flag cannot be used with -keys, -detailed, or -flags")
+			return 1
+		}
+	}
+
+	if c.filterFlags && !c.recurse {
+		c.UI.Error("Error! The -flags-filter flag requires -recurse")
+		return 1
+	}
+
 	// Create and test the HTTP client
 	client, err := c.http.APIClient()
 	if err != nil {
@@ -102,10 +150,16 @@ func (c *cmd) Run(args []string) int {
 	}
 
 	switch {
+	case c.keys && c.recurse && !c.detailed && !c.showFlags && !c.filterFlags:
+		// Only the key names are needed, so they can be streamed instead of
+		// loading every pair under the prefix.
+		separator := ""
+		if c.separatorSet {
+			separator = c.separator
+		}
+		return c.streamKeys(client, key, separator)
 	case c.keys && c.recurse:
-		pairs, _, err := client.KV().List(key, &api.QueryOptions{
-			AllowStale: c.http.Stale(),
-		})
+		pairs, err := c.list(client, key)
 		if err != nil {
 			c.UI.Error(fmt.Sprintf("Error querying Consul agent: %s", err))
 			return 1
@@ -123,32 +177,35 @@ func (c *cmd) Run(args []string) int {
 				if i < len(pairs)-1 {
 					c.UI.Info("")
 				}
+			} else if c.showFlags {
+				c.UI.Info(fmt.Sprintf("%s:%d", pair.Key, pair.Flags))
 			} else {
 				c.UI.Info(fmt.Sprintf("%s", pair.Key))
 			}
 		}
 		return 0
 	case c.keys:
-		keys, _, err := client.KV().Keys(key, c.separator, &api.QueryOptions{
-			AllowStale: c.http.Stale(),
-		})
+		return c.streamKeys(client, key, c.separator)
+	case c.recurse:
+		pairs, err := c.list(client, key)
 		if err != nil {
 			c.UI.Error(fmt.Sprintf("Error querying Consul agent: %s", err))
 			return 1
 		}
 
-		for _, k := range keys {
-			c.UI.Info(k)
-		}
-
-		return 0
-	case c.recurse:
-		pairs, _, err := client.KV().List(key, &api.QueryOptions{
-			AllowStale: c.http.Stale(),
-		})
-		if err != nil {
-			c.UI.Error(fmt.Sprintf("Error querying Consul agent: %s", err))
-			return 1
+		if c.asTree {
+			tree, err := kvTree(pairs, c.base64encode)
+			if err != nil {
+				c.UI.Error(fmt.Sprintf("Error building KV tree: %s", err))
+				return 1
+			}
+			b, err := json.MarshalIndent(tree, "", "    ")
+			if err != nil {
+				c.UI.Error(fmt.Sprintf("Error rendering KV tree: %s", err))
+				return 1
+			}
+			c.UI.Info(string(b))
+			return 0
 		}
 
 		for i, pair := range pairs {
@@ -165,10 +222,14 @@ func (c *cmd) Run(args []string) int {
 					c.UI.Info("")
 				}
 			} else {
+				key := pair.Key
+				if c.showFlags {
+					key = fmt.Sprintf("%s:%d", pair.Key, pair.Flags)
+				}
 				if c.base64encode {
-					c.UI.Info(fmt.Sprintf("%s:%s", pair.Key, base64.StdEncoding.EncodeToString(pair.Value)))
+					c.UI.Info(fmt.Sprintf("%s:%s", key, base64.StdEncoding.EncodeToString(pair.Value)))
 				} else {
-					c.UI.Info(fmt.Sprintf("%s:%s", pair.Key, pair.Value))
+					c.UI.Info(fmt.Sprintf("%s:%s", key, pair.Value))
 				}
 			}
 		}
@@ -199,15 +260,54 @@ func (c *cmd) Run(args []string) int {
 			return 0
 		}
 
+		value := string(pair.Value)
 		if c.base64encode {
-			c.UI.Info(base64.StdEncoding.EncodeToString(pair.Value))
-		} else {
-			c.UI.Info(string(pair.Value))
+			value = base64.StdEncoding.EncodeToString(pair.Value)
 		}
+		if c.showFlags {
+			value = fmt.Sprintf("%d:%s", pair.Flags, value)
+		}
+		c.UI.Info(value)
 		return 0
 	}
 }
 
+// streamKeys prints the keys under prefix as they are received, collapsed at
+// separator if it is not empty. It is an error if there are no such keys.
+func (c *cmd) streamKeys(client *api.Client, prefix, separator string) int {
+	var found bool
+	_, err := client.KV().KeysFunc(prefix, separator, &api.QueryOptions{
+		AllowStale: c.http.Stale(),
+	}, func(k string) error {
+		found = true
+		c.UI.Info(k)
+		return nil
+	})
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error querying Consul agent: %s", err))
+		return 1
+	}
+	if !found {
+		c.UI.Error(fmt.Sprintf("Error! No keys exist under prefix: %s", prefix))
+		return 1
+	}
+	return 0
+}
+
+// list returns the pairs under prefix, limited to the ones matching
+// -flags-filter if it was given.
+func (c *cmd) list(client *api.Client, prefix string) (api.KVPairs, error) {
+	q := &api.QueryOptions{
+		AllowStale: c.http.Stale(),
+	}
+	if c.filterFlags {
+		pairs, _, err := client.KV().ListWithFlags(prefix, c.flagsFilter, q)
+		return pairs, err
+	}
+	pairs, _, err := client.KV().List(prefix, q)
+	return pairs, err
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -216,6 +316,52 @@ func (c *cmd) Help() string {
 	return c.help
 }
 
+// kvTree folds pairs into nested maps keyed by the "/" separated segments of
+// each key. The value of a key that is also the prefix of other keys is kept
+// under the "" member of its map, which is also where a key ending in "/"
+// lands. Keys ending in "/" without a value only create their map.
+func kvTree(pairs api.KVPairs, base64EncodeValue bool) (map[string]interface{}, error) {
+	tree := make(map[string]interface{})
+	for _, pair := range pairs {
+		value := string(pair.Value)
+		if base64EncodeValue {
+			value = base64.StdEncoding.EncodeToString(pair.Value)
+		}
+
+		segments := strings.Split(pair.Key, "/")
+		node := tree
+		for _, segment := range segments[:len(segments)-1] {
+			switch child := node[segment].(type) {
+			case map[string]interface{}:
+				node = child
+			case string:
+				// The key so far also holds a value of its own.
+				next := map[string]interface{}{"": child}
+				node[segment] = next
+				node = next
+			default:
+				next := make(map[string]interface{})
+				node[segment] = next
+				node = next
+			}
+		}
+
+		last := segments[len(segments)-1]
+		if last == "" && len(pair.Value) == 0 {
+			continue
+		}
+		if child, ok := node[last].(map[string]interface{}); ok {
+			node = child
+			last = ""
+		}
+		if _, ok := node[last]; ok {
+			return nil, fmt.Errorf("conflicting values for key %q", pair.Key)
+		}
+		node[last] = value
+	}
+	return tree, nil
+}
+
 func prettyKVPair(w io.Writer, pair *api.KVPair, base64EncodeValue bool, keysOnly bool) error {
 	tw := tabwriter.NewWriter(w, 0, 2, 6, ' ', 0)
 	fmt.Fprintf(tw, "CreateIndex\t%d\n", pair.CreateIndex)
@@ -273,6 +419,22 @@ Usage: consul kv get [options] [KEY_OR_PREFIX]
 
       $ consul kv get -keys foo
 
+  The keys are printed as they are received, so even very large trees can be
+  listed. Combine "-keys" with "-recurse" to list every key under the prefix
+  rather than collapsing them at the "/" separator:
+
+      $ consul kv get -keys -recurse foo
+
+  To output the key-value pairs under a prefix as a nested JSON object, combine
+  the "-recurse" flag with "-as-tree":
+
+      $ consul kv get -recurse -as-tree foo
+
+  To only list the keys under a prefix that were stored with the given flags,
+  and show the flags of each key:
+
+      $ consul kv get -recurse -flags -flags-filter=42 foo
+
   For a full list of options and examples, please see the Consul documentation.
 `
 )
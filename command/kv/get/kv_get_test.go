@@ -5,12 +5,14 @@ package get
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"strings"
 	"testing"
 
 	"github.com/dhiaayachi/consul/agent"
 	"github.com/dhiaayachi/consul/api"
 	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
 )
 
 func TestKVGetCommand_noTabs(t *testing.T) {
@@ -23,7 +25,6 @@ func TestKVGetCommand_noTabs(t *testing.T) {
 func TestKVGetCommand_Validation(t *testing.T) {
 	t.Parallel()
 	ui := cli.NewMockUi()
-	c := New(ui)
 
 	cases := map[string]struct {
 		args   []string
@@ -37,6 +38,18 @@ func TestKVGetCommand_Validation(t *testing.T) {
 			[]string{"foo", "bar", "baz"},
 			"Too many arguments",
 		},
+		"as-tree without recurse": {
+			[]string{"-as-tree", "foo"},
+			"requires -recurse",
+		},
+		"as-tree with keys": {
+			[]string{"-recurse", "-as-tree", "-keys", "foo"},
+			"cannot be used with -keys, -detailed, or -flags",
+		},
+		"flags-filter without recurse": {
+			[]string{"-flags-filter=1", "foo"},
+			"requires -recurse",
+		},
 	}
 
 	for name, tc := range cases {
@@ -48,6 +61,8 @@ func TestKVGetCommand_Validation(t *testing.T) {
 			ui.OutputWriter.Reset()
 		}
 
+		// Flag values persist between runs, so each case needs a new command.
+		c := New(ui)
 		code := c.Run(tc.args)
 		if code == 0 {
 			t.Errorf("%s: expected non-zero exit", name)
@@ -280,6 +295,54 @@ func TestKVGetCommand_Keys(t *testing.T) {
 	}
 }
 
+func TestKVGetCommand_KeysStreaming(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	for _, key := range []string{"foo/a", "foo/b/c", "foo/b/d", "foo/e"} {
+		_, err := client.KV().Put(&api.KVPair{Key: key}, nil)
+		require.NoError(t, err)
+	}
+
+	run := func(t *testing.T, args ...string) (int, string, string) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+
+		args = append([]string{"-http-addr=" + a.HTTPAddr()}, args...)
+		code := c.Run(args)
+		return code, ui.OutputWriter.String(), ui.ErrorWriter.String()
+	}
+
+	t.Run("recurse lists every key", func(t *testing.T) {
+		code, out, errOut := run(t, "-keys", "-recurse", "foo/")
+		require.Equal(t, 0, code, errOut)
+		require.Equal(t, "foo/a\nfoo/b/c\nfoo/b/d\nfoo/e\n", out)
+	})
+
+	t.Run("recurse collapses at an explicit separator", func(t *testing.T) {
+		code, out, errOut := run(t, "-keys", "-recurse", "-separator=/", "foo/")
+		require.Equal(t, 0, code, errOut)
+		require.Equal(t, "foo/a\nfoo/b/\nfoo/e\n", out)
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		code, out, errOut := run(t, "-keys", "-recurse", "bar/")
+		require.Equal(t, 1, code)
+		require.Empty(t, out)
+		require.Contains(t, errOut, "No keys exist under prefix: bar/")
+
+		code, _, errOut = run(t, "-keys", "bar/")
+		require.Equal(t, 1, code)
+		require.Contains(t, errOut, "No keys exist under prefix: bar/")
+	})
+}
+
 func TestKVGetCommand_Recurse(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -369,6 +432,83 @@ func TestKVGetCommand_RecurseBase64(t *testing.T) {
 	}
 }
 
+func TestKVGetCommand_RecurseAsTree(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	keys := map[string]string{
+		"foo":             "root",
+		"foo/":            "",
+		"foo/a":           "a",
+		"foo/b/c":         "c",
+		"foo/b/d/e":       "e",
+		"foo/b/d":         "d",
+		"foo/empty/":      "",
+		"other/unrelated": "x",
+	}
+	for k, v := range keys {
+		pair := &api.KVPair{Key: k, Value: []byte(v)}
+		if _, err := client.KV().Put(pair, nil); err != nil {
+			t.Fatalf("err: %#v", err)
+		}
+	}
+
+	run := func(t *testing.T, args ...string) map[string]interface{} {
+		ui := cli.NewMockUi()
+		c := New(ui)
+
+		args = append([]string{"-http-addr=" + a.HTTPAddr(), "-recurse", "-as-tree"}, args...)
+		code := c.Run(args)
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+		var tree map[string]interface{}
+		require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &tree))
+		return tree
+	}
+
+	t.Run("raw values", func(t *testing.T) {
+		expect := map[string]interface{}{
+			"foo": map[string]interface{}{
+				"":  "root",
+				"a": "a",
+				"b": map[string]interface{}{
+					"c": "c",
+					"d": map[string]interface{}{
+						"":  "d",
+						"e": "e",
+					},
+				},
+				"empty": map[string]interface{}{},
+			},
+		}
+		require.Equal(t, expect, run(t, "foo"))
+	})
+
+	t.Run("base64 values", func(t *testing.T) {
+		encode := func(v string) string {
+			return base64.StdEncoding.EncodeToString([]byte(v))
+		}
+		expect := map[string]interface{}{
+			"foo": map[string]interface{}{
+				"b": map[string]interface{}{
+					"c": encode("c"),
+					"d": map[string]interface{}{
+						"":  encode("d"),
+						"e": encode("e"),
+					},
+				},
+			},
+		}
+		require.Equal(t, expect, run(t, "-base64", "foo/b"))
+	})
+}
+
 func TestKVGetCommand_DetailedBase64(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -520,3 +660,55 @@ func TestKVGetCommand_DetailedKeysRecurse(t *testing.T) {
 		}
 	}
 }
+
+func TestKVGetCommand_Flags(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	pairs := []*api.KVPair{
+		{Key: "foo/a", Flags: 0, Value: []byte("a")},
+		{Key: "foo/b", Flags: 42, Value: []byte("b")},
+		{Key: "foo/c", Flags: 7, Value: []byte("c")},
+		{Key: "foo/d", Flags: 42, Value: []byte("d")},
+	}
+	for _, pair := range pairs {
+		_, err := client.KV().Put(pair, nil)
+		require.NoError(t, err)
+	}
+
+	run := func(t *testing.T, args ...string) string {
+		ui := cli.NewMockUi()
+		c := New(ui)
+
+		args = append([]string{"-http-addr=" + a.HTTPAddr()}, args...)
+		code := c.Run(args)
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+		return ui.OutputWriter.String()
+	}
+
+	t.Run("get shows flags", func(t *testing.T) {
+		require.Equal(t, "42:b\n", run(t, "-flags", "foo/b"))
+	})
+
+	t.Run("recurse shows flags", func(t *testing.T) {
+		require.Equal(t, "foo/a:0:a\nfoo/b:42:b\nfoo/c:7:c\nfoo/d:42:d\n", run(t, "-recurse", "-flags", "foo"))
+	})
+
+	t.Run("recurse filters by flags", func(t *testing.T) {
+		require.Equal(t, "foo/b:b\nfoo/d:d\n", run(t, "-recurse", "-flags-filter=42", "foo"))
+	})
+
+	t.Run("recurse keys filters by flags", func(t *testing.T) {
+		require.Equal(t, "foo/a:0\n", run(t, "-recurse", "-keys", "-flags", "-flags-filter=0", "foo"))
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		require.Empty(t, run(t, "-recurse", "-flags-filter=1", "foo"))
+	})
+}
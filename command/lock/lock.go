@@ -4,6 +4,8 @@
 package lock
 
 import (
+	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
@@ -18,6 +20,7 @@ import (
 	"github.com/dhiaayachi/consul/agent/exec"
 	"github.com/dhiaayachi/consul/api"
 	"github.com/dhiaayachi/consul/command/flags"
+	"github.com/dhiaayachi/consul/lib/file"
 	"github.com/mitchellh/cli"
 )
 
@@ -55,6 +58,7 @@ type cmd struct {
 	verbose   bool
 
 	// flags
+	leaderFile         string
 	limit              int
 	monitorRetry       int
 	name               string
@@ -76,6 +80,11 @@ func (c *cmd) init() {
 		"Exit 2 if the child process exited with an error if this is true, "+
 			"otherwise this doesn't propagate an error from the child. The "+
 			"default value is false.")
+	c.flags.StringVar(&c.leaderFile, "leader-file", "",
+		"Optional path of a file to keep updated with the identity of the "+
+			"current lock holder, as JSON with the holder's session ID, session "+
+			"name and node. The file is emptied while the lock is not held. Only "+
+			"supported when -n=1.")
 	c.flags.IntVar(&c.limit, "n", 1,
 		"Optional limit on the number of concurrent lock holders. The underlying "+
 			"implementation switches from a lock to a semaphore when the value is "+
@@ -140,6 +149,11 @@ func (c *cmd) run(args []string, lu **LockUnlock) int {
 		return 1
 	}
 
+	if c.leaderFile != "" && c.limit != 1 {
+		c.UI.Error("The -leader-file flag is only supported with -n=1")
+		return 1
+	}
+
 	// Calculate a session name if none provided
 	if c.name == "" {
 		c.name = fmt.Sprintf("Consul lock for '%s' at '%s'", strings.Join(extra[1:], " "), prefix)
@@ -177,6 +191,24 @@ func (c *cmd) run(args []string, lu **LockUnlock) int {
 		return 1
 	}
 
+	// Track the lock holder in the leader file for as long as we contend
+	// for, or hold, the lock.
+	if c.leaderFile != "" {
+		ctx, cancel := context.WithCancel(context.Background())
+		doneCh := make(chan struct{})
+		go func() {
+			defer close(doneCh)
+			c.watchLeader(ctx, client, path.Join(prefix, api.DefaultSemaphoreKey))
+		}()
+		defer func() {
+			cancel()
+			<-doneCh
+			if err := c.writeLeaderFile(nil); err != nil {
+				c.UI.Error(fmt.Sprintf("Error clearing leader file: %s", err))
+			}
+		}()
+	}
+
 	// Attempt the acquisition
 	if c.verbose {
 		c.UI.Info("Attempting lock acquisition")
@@ -337,6 +369,104 @@ func (c *cmd) setupSemaphore(client *api.Client, limit int, prefix, name string,
 	return lu, nil
 }
 
+// leaderInfo is the identity of the lock holder written to the leader file.
+type leaderInfo struct {
+	Session string
+	Name    string
+	Node    string
+}
+
+// watchLeader is a long running routine that blocks on the lock key and
+// writes the identity of its holder to the leader file whenever it changes,
+// until the context is cancelled.
+func (c *cmd) watchLeader(ctx context.Context, client *api.Client, key string) {
+	var (
+		waitIndex uint64
+		written   bool
+		holder    string
+	)
+	for {
+		opts := &api.QueryOptions{
+			WaitIndex:         waitIndex,
+			RequireConsistent: true,
+		}
+		pair, meta, err := client.KV().Get(key, opts.WithContext(ctx))
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			if c.verbose {
+				c.UI.Info(fmt.Sprintf("Error watching lock holder: %s", err))
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(defaultMonitorRetryTime):
+			}
+			continue
+		}
+		if meta.LastIndex < waitIndex {
+			waitIndex = 0
+		} else {
+			waitIndex = meta.LastIndex
+		}
+
+		var session string
+		if pair != nil {
+			session = pair.Session
+		}
+		if written && session == holder {
+			continue
+		}
+
+		var info *leaderInfo
+		if session != "" {
+			// The session lookup must not block on the KV index used above.
+			sessionOpts := &api.QueryOptions{RequireConsistent: true}
+			entry, _, err := client.Session().Info(session, sessionOpts.WithContext(ctx))
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				if c.verbose {
+					c.UI.Info(fmt.Sprintf("Error looking up lock holder: %s", err))
+				}
+				// Retry the lookup on the next pass without blocking.
+				waitIndex = 0
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(defaultMonitorRetryTime):
+				}
+				continue
+			}
+			if entry != nil {
+				info = &leaderInfo{Session: entry.ID, Name: entry.Name, Node: entry.Node}
+			}
+		}
+		if err := c.writeLeaderFile(info); err != nil {
+			c.UI.Error(fmt.Sprintf("Error writing leader file: %s", err))
+			continue
+		}
+		written, holder = true, session
+	}
+}
+
+// writeLeaderFile atomically replaces the contents of the leader file with
+// the given holder, or empties it if there is none.
+func (c *cmd) writeLeaderFile(info *leaderInfo) error {
+	var data []byte
+	if info != nil {
+		var err error
+		data, err = json.Marshal(info)
+		if err != nil {
+			return err
+		}
+		data = append(data, '\n')
+	}
+	return file.WriteAtomicWithPerms(c.leaderFile, data, 0755, 0644)
+}
+
 // startChild is a long running routine used to start and
 // wait for the child process to exit.
 func (c *cmd) startChild(args []string, passStdin, shell bool) error {
@@ -484,5 +614,9 @@ Usage: consul lock [options] prefix child...
   exclusion. Setting a higher value switches to a semaphore allowing multiple
   holders to coordinate.
 
+  With -leader-file, the identity of the current lock holder is kept up to
+  date in the given file while waiting for and holding the lock, so other
+  processes can read who holds leadership.
+
   The prefix provided must have write privileges.
 `
@@ -4,6 +4,7 @@
 package lock
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/dhiaayachi/consul/agent"
 	"github.com/dhiaayachi/consul/api"
+	"github.com/dhiaayachi/consul/sdk/testutil/retry"
 	"github.com/dhiaayachi/consul/testrpc"
 	"github.com/mitchellh/cli"
 )
@@ -41,6 +43,7 @@ func TestLockCommand_BadArgs(t *testing.T) {
 	argFail(t, []string{"-try=blah", "test/prefix", "date"}, "parse error")
 	argFail(t, []string{"-try=-10s", "test/prefix", "date"}, "Timeout must be positive")
 	argFail(t, []string{"-monitor-retry=-5", "test/prefix", "date"}, "must be >= 0")
+	argFail(t, []string{"-leader-file=leader", "-n=2", "test/prefix", "date"}, "only supported with -n=1")
 }
 
 func TestLockCommand(t *testing.T) {
@@ -377,3 +380,96 @@ func TestLockCommand_ChildExitCode(t *testing.T) {
 		})
 	}
 }
+
+func TestLockCommand_LeaderFile(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	type contender struct {
+		ui         *cli.MockUi
+		shutdownCh chan struct{}
+		leaderFile string
+		codeCh     chan int
+	}
+	start := func(name string) *contender {
+		c := &contender{
+			ui:         cli.NewMockUi(),
+			shutdownCh: make(chan struct{}),
+			leaderFile: filepath.Join(a.Config.DataDir, name+"-leader"),
+			codeCh:     make(chan int, 1),
+		}
+		cmd := New(c.ui, c.shutdownCh)
+		args := []string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-name=" + name,
+			"-leader-file=" + c.leaderFile,
+			"test/prefix", "sleep", "60",
+		}
+		go func() { c.codeCh <- cmd.Run(args) }()
+		return c
+	}
+	leaderName := func(r *retry.R, path string) string {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			r.Fatalf("err: %v", err)
+		}
+		if len(data) == 0 {
+			return ""
+		}
+		var info leaderInfo
+		if err := json.Unmarshal(data, &info); err != nil {
+			r.Fatalf("err: %v", err)
+		}
+		if info.Node != a.Config.NodeName {
+			r.Fatalf("expected node %q, got %q", a.Config.NodeName, info.Node)
+		}
+		return info.Name
+	}
+
+	first := start("first")
+	retry.Run(t, func(r *retry.R) {
+		if got := leaderName(r, first.leaderFile); got != "first" {
+			r.Fatalf("expected first to hold the lock, got %q", got)
+		}
+	})
+
+	second := start("second")
+	defer close(second.shutdownCh)
+	retry.Run(t, func(r *retry.R) {
+		if got := leaderName(r, second.leaderFile); got != "first" {
+			r.Fatalf("expected second to see first as leader, got %q", got)
+		}
+	})
+
+	// Stopping the first contender hands the lock over to the second.
+	close(first.shutdownCh)
+	select {
+	case code := <-first.codeCh:
+		if code != 0 {
+			t.Fatalf("bad: %d. %#v", code, first.ui.ErrorWriter.String())
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("first contender did not exit")
+	}
+
+	data, err := os.ReadFile(first.leaderFile)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(data) != 0 {
+		t.Fatalf("expected leader file to be emptied on exit, got %q", data)
+	}
+
+	retry.Run(t, func(r *retry.R) {
+		if got := leaderName(r, second.leaderFile); got != "second" {
+			r.Fatalf("expected second to hold the lock, got %q", got)
+		}
+	})
+}
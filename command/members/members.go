@@ -11,6 +11,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/hashicorp/go-bexpr"
 	"github.com/hashicorp/serf/serf"
 	"github.com/mitchellh/cli"
 	"github.com/ryanuber/columnize"
@@ -29,11 +30,13 @@ type cmd struct {
 	http  *flags.HTTPFlags
 
 	// flags
-	detailed     bool
-	wan          bool
-	statusFilter string
-	segment      string
-	filter       string
+	detailed       bool
+	segmentSummary bool
+	wan            bool
+	statusFilter   string
+	segment        string
+	filter         string
+	tagFilter      string
 }
 
 func New(ui cli.Ui) *cmd {
@@ -46,6 +49,9 @@ func (c *cmd) init() {
 	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
 	c.flags.BoolVar(&c.detailed, "detailed", false,
 		"Provides detailed information about nodes.")
+	c.flags.BoolVar(&c.segmentSummary, "segment-summary", false,
+		"Summarizes the members of each partition and segment with counts by "+
+			"status instead of listing them.")
 	c.flags.BoolVar(&c.wan, "wan", false,
 		"If the agent is in server mode, this can be used to return the other "+
 			"peers in the WAN pool.")
@@ -56,6 +62,10 @@ func (c *cmd) init() {
 		"(Enterprise-only) If provided, output is filtered to only nodes in"+
 			"the given segment.")
 	c.flags.StringVar(&c.filter, "filter", "", "Filter to use with the request")
+	c.flags.StringVar(&c.tagFilter, "tag-filter", "",
+		"If provided, output is filtered to only nodes whose tags match the "+
+			"expression. Tag names are used as selectors, such as "+
+			"'role == consul and dc == dc1'.")
 
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
@@ -68,6 +78,11 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	if c.detailed && c.segmentSummary {
+		c.UI.Error("The -detailed and -segment-summary flags cannot be used together")
+		return 1
+	}
+
 	// Compile the regexp
 	statusRe, err := regexp.Compile(c.statusFilter)
 	if err != nil {
@@ -75,6 +90,15 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	var tagFilter *bexpr.Evaluator
+	if c.tagFilter != "" {
+		tagFilter, err = bexpr.CreateEvaluatorForType(c.tagFilter, nil, map[string]string(nil))
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Failed to parse tag filter: %v", err))
+			return 1
+		}
+	}
+
 	client, err := c.http.APIClient()
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Error connecting to Consul agent: %s", err))
@@ -93,6 +117,14 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	if tagFilter != nil {
+		members, err = filterByTags(members, tagFilter)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error filtering members by tags: %s", err))
+			return 1
+		}
+	}
+
 	// Filter the results
 	n := len(members)
 	for i := 0; i < n; i++ {
@@ -130,6 +162,8 @@ func (c *cmd) Run(args []string) int {
 	var result []string
 	if c.detailed {
 		result = c.detailedOutput(members)
+	} else if c.segmentSummary {
+		result = c.segmentSummaryOutput(members)
 	} else {
 		result = c.standardOutput(members)
 	}
@@ -141,6 +175,26 @@ func (c *cmd) Run(args []string) int {
 	return 0
 }
 
+// filterByTags returns the members whose tags, as reported by the agent,
+// match the filter.
+func filterByTags(members []*consulapi.AgentMember, filter *bexpr.Evaluator) ([]*consulapi.AgentMember, error) {
+	out := make([]*consulapi.AgentMember, 0, len(members))
+	for _, member := range members {
+		tags := member.Tags
+		if tags == nil {
+			tags = map[string]string{}
+		}
+		match, err := filter.Evaluate(tags)
+		if err != nil {
+			return nil, fmt.Errorf("member %q: %w", member.Name, err)
+		}
+		if match {
+			out = append(out, member)
+		}
+	}
+	return out, nil
+}
+
 // ByMemberNamePartitionAndSegment sorts members by name with a stable sort.
 //
 // 1. servers go at the top
@@ -280,6 +334,53 @@ func (c *cmd) detailedOutput(members []*consulapi.AgentMember) []string {
 	return result
 }
 
+// segmentSummaryOutput is used to dump the number of members in each
+// partition and segment by status, in the order the segments first appear in
+// members.
+func (c *cmd) segmentSummaryOutput(members []*consulapi.AgentMember) []string {
+	type segmentKey struct {
+		partition string
+		segment   string
+	}
+	type segmentCounts struct {
+		alive, failed, left, total int
+	}
+
+	var keys []segmentKey
+	counts := make(map[segmentKey]*segmentCounts)
+	for _, member := range members {
+		tags := parseTags(member.Tags)
+		key := segmentKey{partition: tags.partition, segment: tags.segment}
+		sc, ok := counts[key]
+		if !ok {
+			sc = &segmentCounts{}
+			counts[key] = sc
+			keys = append(keys, key)
+		}
+
+		switch serf.MemberStatus(member.Status) {
+		case serf.StatusAlive:
+			sc.alive++
+		case serf.StatusFailed:
+			sc.failed++
+		case serf.StatusLeft:
+			sc.left++
+		}
+		sc.total++
+	}
+
+	result := make([]string, 0, len(keys)+1)
+	header := "Partition\x1fSegment\x1fAlive\x1fFailed\x1fLeft\x1fTotal"
+	result = append(result, header)
+	for _, key := range keys {
+		sc := counts[key]
+		line := fmt.Sprintf("%s\x1f%s\x1f%d\x1f%d\x1f%d\x1f%d",
+			key.partition, key.segment, sc.alive, sc.failed, sc.left, sc.total)
+		result = append(result, line)
+	}
+	return result
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -293,4 +394,8 @@ const help = `
 Usage: consul members [options]
 
   Outputs the members of a running Consul agent.
+
+  List only the servers of datacenter dc1:
+
+      $ consul members -tag-filter 'role == consul and dc == dc1'
 `
@@ -11,6 +11,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/hashicorp/go-bexpr"
+	"github.com/hashicorp/serf/serf"
 	"github.com/mitchellh/cli"
 	"github.com/stretchr/testify/require"
 
@@ -148,6 +150,97 @@ func TestMembersCommand_statusFilter_failed(t *testing.T) {
 	}
 }
 
+func TestMembersCommand_tagFilter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+
+	run := func(t *testing.T, filter string) (int, *cli.MockUi) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+		c.flags.SetOutput(ui.ErrorWriter)
+		code := c.Run([]string{"-http-addr=" + a.HTTPAddr(), "-tag-filter=" + filter})
+		return code, ui
+	}
+
+	t.Run("match", func(t *testing.T) {
+		code, ui := run(t, "role == consul and dc == dc1")
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+		require.Contains(t, ui.OutputWriter.String(), a.Config.NodeName)
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		code, ui := run(t, "role == node")
+		require.Equal(t, 2, code, ui.ErrorWriter.String())
+		require.NotContains(t, ui.OutputWriter.String(), a.Config.NodeName)
+	})
+
+	t.Run("invalid expression", func(t *testing.T) {
+		code, ui := run(t, "role ==")
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "Failed to parse tag filter")
+	})
+}
+
+func TestFilterByTags(t *testing.T) {
+	t.Parallel()
+
+	members := []*consulapi.AgentMember{
+		{Name: "server-dc1", Tags: map[string]string{"role": "consul", "dc": "dc1"}},
+		{Name: "server-dc2", Tags: map[string]string{"role": "consul", "dc": "dc2"}},
+		{Name: "client-dc1", Tags: map[string]string{"role": "node", "dc": "dc1"}},
+		{Name: "client-rack", Tags: map[string]string{"role": "node", "dc": "dc1", "rack": "r1"}},
+		{Name: "untagged"},
+	}
+
+	cases := map[string]struct {
+		filter string
+		expect []string
+	}{
+		"servers in dc1": {
+			filter: "role == consul and dc == dc1",
+			expect: []string{"server-dc1"},
+		},
+		"all dc1": {
+			filter: "dc == dc1",
+			expect: []string{"server-dc1", "client-dc1", "client-rack"},
+		},
+		"negation": {
+			filter: "role != consul",
+			expect: []string{"client-dc1", "client-rack", "untagged"},
+		},
+		"custom tag": {
+			filter: "rack == r1 or dc == dc2",
+			expect: []string{"server-dc2", "client-rack"},
+		},
+		"no match": {
+			filter: "role == consul and dc == dc3",
+			expect: []string{},
+		},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			ev, err := bexpr.CreateEvaluatorForType(tc.filter, nil, map[string]string(nil))
+			require.NoError(t, err)
+
+			out, err := filterByTags(members, ev)
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(out))
+			for _, m := range out {
+				names = append(names, m.Name)
+			}
+			require.Equal(t, tc.expect, names)
+		})
+	}
+}
+
 func TestMembersCommand_verticalBar(t *testing.T) {
 	if testing.Short() {
 		t.Skip("too slow for testing.Short")
@@ -264,3 +357,23 @@ func TestSortByMemberNamePartitionAndSegment(t *testing.T) {
 		require.Equal(t, stringify(expect), stringify(data), "iteration #%d", i)
 	}
 }
+
+func TestMembersCommand_segmentSummaryOutput(t *testing.T) {
+	alive, failed, left := int(serf.StatusAlive), int(serf.StatusFailed), int(serf.StatusLeft)
+	members := []*consulapi.AgentMember{
+		{Name: "a", Status: alive, Tags: map[string]string{"segment": "alpha"}},
+		{Name: "b", Status: alive, Tags: map[string]string{"segment": "alpha"}},
+		{Name: "c", Status: failed, Tags: map[string]string{"segment": "alpha"}},
+		{Name: "d", Status: alive, Tags: map[string]string{"segment": "beta"}},
+		{Name: "e", Status: failed, Tags: map[string]string{"segment": "beta"}},
+		{Name: "f", Status: failed, Tags: map[string]string{"segment": "beta"}},
+		{Name: "g", Status: left, Tags: map[string]string{"segment": "beta"}},
+	}
+
+	c := New(cli.NewMockUi())
+	require.Equal(t, []string{
+		"Partition\x1fSegment\x1fAlive\x1fFailed\x1fLeft\x1fTotal",
+		"\x1falpha\x1f2\x1f1\x1f0\x1f3",
+		"\x1fbeta\x1f1\x1f2\x1f1\x1f4",
+	}, c.segmentSummaryOutput(members))
+}
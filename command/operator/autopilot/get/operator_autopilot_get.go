@@ -4,6 +4,7 @@
 package get
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 
@@ -23,10 +24,20 @@ type cmd struct {
 	flags *flag.FlagSet
 	http  *flags.HTTPFlags
 	help  string
+
+	format string
 }
 
+const (
+	PrettyFormat string = "pretty"
+	JSONFormat   string = "json"
+)
+
 func (c *cmd) init() {
 	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.StringVar(&c.format, "format", PrettyFormat,
+		fmt.Sprintf("Output format {%s|%s}. The JSON output can be passed to "+
+			"'consul operator autopilot set-config -from-file'.", PrettyFormat, JSONFormat))
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
@@ -42,6 +53,11 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	if c.format != PrettyFormat && c.format != JSONFormat {
+		c.UI.Error(fmt.Sprintf("Invalid format %q, must be one of %s|%s", c.format, PrettyFormat, JSONFormat))
+		return 1
+	}
+
 	// Set up a client.
 	client, err := c.http.APIClient()
 	if err != nil {
@@ -58,6 +74,17 @@ func (c *cmd) Run(args []string) int {
 		c.UI.Error(fmt.Sprintf("Error querying Autopilot configuration: %s", err))
 		return 1
 	}
+
+	if c.format == JSONFormat {
+		out, err := json.MarshalIndent(config, "", "    ")
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error encoding Autopilot configuration: %s", err))
+			return 1
+		}
+		c.UI.Output(string(out))
+		return 0
+	}
+
 	c.UI.Output(fmt.Sprintf("CleanupDeadServers = %v", config.CleanupDeadServers))
 	c.UI.Output(fmt.Sprintf("LastContactThreshold = %v", config.LastContactThreshold.String()))
 	c.UI.Output(fmt.Sprintf("MaxTrailingLogs = %v", config.MaxTrailingLogs))
@@ -4,12 +4,15 @@
 package set
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/dhiaayachi/consul/api"
 	"github.com/dhiaayachi/consul/command/flags"
+	"github.com/dhiaayachi/consul/command/helpers"
 	"github.com/mitchellh/cli"
 )
 
@@ -34,6 +37,10 @@ type cmd struct {
 	redundancyZoneTag       flags.StringValue
 	disableUpgradeMigration flags.BoolValue
 	upgradeVersionTag       flags.StringValue
+	fromFile                string
+
+	// testStdin is the input for testing.
+	testStdin io.Reader
 }
 
 func (c *cmd) init() {
@@ -65,6 +72,11 @@ func (c *cmd) init() {
 	c.flags.Var(&c.upgradeVersionTag, "upgrade-version-tag",
 		"(Enterprise-only) The node_meta tag to use for version info when performing upgrade "+
 			"migrations. If left blank, the Consul version will be used.")
+	c.flags.StringVar(&c.fromFile, "from-file", "",
+		"Path to a JSON file containing the Autopilot configuration to apply, in the "+
+			"format produced by 'consul operator autopilot get-config -format=json'. "+
+			"Use '-' to read from stdin. Fields missing from the file keep their current "+
+			"value and any other flags given override the values from the file.")
 
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
@@ -96,6 +108,26 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	if c.fromFile != "" {
+		data, err := helpers.LoadDataSourceNoRaw(c.fromFile, c.testStdin)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Failed to load data: %v", err))
+			return 1
+		}
+		// Decoding on top of the current configuration keeps the fields that
+		// are missing from the file. When the file carries the ModifyIndex it
+		// was read at, the check-and-set below fails if the configuration has
+		// been changed since.
+		if err := json.Unmarshal([]byte(data), conf); err != nil {
+			c.UI.Error(fmt.Sprintf("Failed to decode Autopilot configuration: %v", err))
+			return 1
+		}
+		if conf.LastContactThreshold == nil || conf.ServerStabilizationTime == nil {
+			c.UI.Error("Failed to decode Autopilot configuration: durations must not be null")
+			return 1
+		}
+	}
+
 	// Update the config values based on the set flags.
 	c.cleanupDeadServers.Merge(&conf.CleanupDeadServers)
 	c.redundancyZoneTag.Merge(&conf.RedundancyZoneTag)
@@ -142,4 +174,10 @@ const help = `
 Usage: consul operator autopilot set-config [options]
 
   Modifies the current Autopilot configuration.
+
+  The configuration can also be read from a file, which allows editing the
+  output of get-config and applying it back in a single update:
+
+      $ consul operator autopilot get-config -format=json > autopilot.json
+      $ consul operator autopilot set-config -from-file=autopilot.json
 `
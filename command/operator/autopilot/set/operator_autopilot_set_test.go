@@ -5,15 +5,22 @@ package set
 
 import (
 	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
-	"github.com/dhiaayachi/consul/testrpc"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
 
 	"github.com/dhiaayachi/consul/agent"
 	"github.com/dhiaayachi/consul/agent/structs"
-	"github.com/mitchellh/cli"
+	"github.com/dhiaayachi/consul/api"
+	"github.com/dhiaayachi/consul/command/operator/autopilot/get"
+	"github.com/dhiaayachi/consul/sdk/testutil"
+	"github.com/dhiaayachi/consul/testrpc"
 )
 
 func TestOperatorAutopilotSetConfigCommand_noTabs(t *testing.T) {
@@ -77,3 +84,61 @@ func TestOperatorAutopilotSetConfigCommand(t *testing.T) {
 		t.Fatalf("bad: %#v", reply)
 	}
 }
+
+func TestOperatorAutopilotSetConfigCommand_FromFile(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	testrpc.WaitForTestAgent(t, a.RPC, "dc1")
+
+	getConfig := func() api.AutopilotConfiguration {
+		ui := cli.NewMockUi()
+		code := get.New(ui).Run([]string{"-http-addr=" + a.HTTPAddr(), "-format=json"})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+		var conf api.AutopilotConfiguration
+		require.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &conf))
+		return conf
+	}
+
+	// Round-trip the JSON output of get-config through a file.
+	before := getConfig()
+	raw, err := json.Marshal(before)
+	require.NoError(t, err)
+	path := filepath.Join(testutil.TempDir(t, "autopilot"), "autopilot.json")
+	require.NoError(t, os.WriteFile(path, raw, 0600))
+
+	ui := cli.NewMockUi()
+	code := New(ui).Run([]string{"-http-addr=" + a.HTTPAddr(), "-from-file=" + path})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "Configuration updated")
+
+	after := getConfig()
+	require.Greater(t, after.ModifyIndex, before.ModifyIndex)
+	before.ModifyIndex, after.ModifyIndex = 0, 0
+	require.Equal(t, before, after)
+
+	// The file still carries the old ModifyIndex, so applying it again must
+	// not overwrite the newer configuration.
+	ui = cli.NewMockUi()
+	code = New(ui).Run([]string{"-http-addr=" + a.HTTPAddr(), "-from-file=" + path})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.OutputWriter.String(), "could not be atomically updated")
+
+	// Edited documents without a ModifyIndex are applied on top of the current
+	// configuration, and flags take precedence over the file.
+	ui = cli.NewMockUi()
+	c := New(ui)
+	c.testStdin = strings.NewReader(`{"MaxTrailingLogs": 42, "MinQuorum": 2}`)
+	code = c.Run([]string{"-http-addr=" + a.HTTPAddr(), "-from-file=-", "-min-quorum=3"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+	edited := getConfig()
+	require.Equal(t, uint64(42), edited.MaxTrailingLogs)
+	require.Equal(t, uint(3), edited.MinQuorum)
+	require.Equal(t, before.LastContactThreshold, edited.LastContactThreshold)
+}
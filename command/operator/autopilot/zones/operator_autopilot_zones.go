@@ -0,0 +1,153 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package zones
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+
+	"github.com/dhiaayachi/consul/api"
+	"github.com/dhiaayachi/consul/command/flags"
+	"github.com/mitchellh/cli"
+	"github.com/ryanuber/columnize"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		c.UI.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	opts := &api.QueryOptions{
+		AllowStale: c.http.Stale(),
+	}
+	state, err := client.Operator().AutopilotState(opts)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error querying Autopilot state: %s", err))
+		return 1
+	}
+
+	if len(state.RedundancyZones) == 0 {
+		c.UI.Error("No redundancy zones are configured. Redundancy zones require Consul Enterprise " +
+			"and the autopilot redundancy_zone_tag setting.")
+		return 2
+	}
+
+	c.UI.Output(formatZones(state))
+	return 0
+}
+
+// zoneSummary is the per-zone rollup shown by the command.
+type zoneSummary struct {
+	Name             string
+	Servers          int
+	Voters           int
+	FailureTolerance int
+	Healthy          bool
+}
+
+// summarizeZones rolls the autopilot state up into one summary per
+// redundancy zone, sorted by zone name. A zone is healthy when it has at
+// least one voter, all of its voters are healthy, and it can lose its voter:
+// either it has a healthy non-voter to promote or autopilot reports a
+// failure tolerance above zero.
+func summarizeZones(state *api.AutopilotState) []zoneSummary {
+	summaries := make([]zoneSummary, 0, len(state.RedundancyZones))
+	for name, zone := range state.RedundancyZones {
+		healthy := len(zone.Voters) > 0
+		voters := make(map[string]struct{}, len(zone.Voters))
+		for _, id := range zone.Voters {
+			voters[id] = struct{}{}
+			if srv, ok := state.Servers[id]; !ok || !srv.Healthy {
+				healthy = false
+			}
+		}
+
+		redundant := zone.FailureTolerance > 0
+		for _, id := range zone.Servers {
+			if _, ok := voters[id]; ok {
+				continue
+			}
+			if srv, ok := state.Servers[id]; ok && srv.Healthy {
+				redundant = true
+				break
+			}
+		}
+		healthy = healthy && redundant
+
+		summaries = append(summaries, zoneSummary{
+			Name:             name,
+			Servers:          len(zone.Servers),
+			Voters:           len(zone.Voters),
+			FailureTolerance: zone.FailureTolerance,
+			Healthy:          healthy,
+		})
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Name < summaries[j].Name
+	})
+	return summaries
+}
+
+func formatZones(state *api.AutopilotState) string {
+	result := []string{"Zone\x1fServers\x1fVoters\x1fFailure Tolerance\x1fHealthy"}
+	for _, z := range summarizeZones(state) {
+		result = append(result, fmt.Sprintf("%s\x1f%d\x1f%d\x1f%d\x1f%t",
+			z.Name, z.Servers, z.Voters, z.FailureTolerance, z.Healthy))
+	}
+	return columnize.Format(result, &columnize.Config{Delim: string([]byte{0x1f})})
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Display the health of autopilot redundancy zones"
+const help = `
+Usage: consul operator autopilot zones [options]
+
+  Displays one line per autopilot redundancy zone with the number of
+  servers and voters in the zone, the zone's failure tolerance as reported
+  by autopilot, and whether the zone is healthy. A zone is healthy when it
+  has at least one voter, all of its voters are healthy, and it either has a
+  healthy non-voter to take over or a failure tolerance above zero.
+
+  Redundancy zones are only available in Consul Enterprise.
+`
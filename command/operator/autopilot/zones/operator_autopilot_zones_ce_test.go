@@ -0,0 +1,34 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build !consulent
+
+package zones
+
+import (
+	"testing"
+
+	"github.com/dhiaayachi/consul/agent"
+	"github.com/dhiaayachi/consul/testrpc"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZonesCommand_noZones(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, "")
+	defer a.Shutdown()
+	testrpc.WaitForLeader(t, a.RPC, "dc1")
+
+	ui := cli.NewMockUi()
+	cmd := New(ui)
+
+	code := cmd.Run([]string{"-http-addr=" + a.HTTPAddr()})
+	require.Equal(t, 2, code)
+	require.Contains(t, ui.ErrorWriter.String(), "No redundancy zones are configured.")
+	require.Empty(t, ui.OutputWriter.String())
+}
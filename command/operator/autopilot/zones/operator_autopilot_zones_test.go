@@ -0,0 +1,56 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package zones
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dhiaayachi/consul/api"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZonesCommand_noTabs(t *testing.T) {
+	t.Parallel()
+
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestZonesCommand_summarizeZones(t *testing.T) {
+	t.Parallel()
+
+	input, err := os.ReadFile(filepath.Join("testdata", "state.json"))
+	require.NoError(t, err)
+
+	var state api.AutopilotState
+	require.NoError(t, json.Unmarshal(input, &state))
+
+	expected := []zoneSummary{
+		{Name: "zone1", Servers: 2, Voters: 1, FailureTolerance: 1, Healthy: true},
+		{Name: "zone2", Servers: 2, Voters: 1, FailureTolerance: 0, Healthy: false},
+		{Name: "zone3", Servers: 1, Voters: 1, FailureTolerance: 0, Healthy: false},
+	}
+	require.Equal(t, expected, summarizeZones(&state))
+
+	// A failure tolerance above zero makes up for the unhealthy non-voter.
+	zone2 := state.RedundancyZones["zone2"]
+	zone2.FailureTolerance = 1
+	state.RedundancyZones["zone2"] = zone2
+	require.True(t, summarizeZones(&state)[1].Healthy)
+
+	// A zone that lost its voter is not healthy.
+	state.RedundancyZones["zone1"] = api.AutopilotZone{Servers: []string{"a2"}}
+	require.False(t, summarizeZones(&state)[0].Healthy)
+
+	out := formatZones(&state)
+	require.Contains(t, out, "Zone   Servers  Voters  Failure Tolerance  Healthy")
+	require.Contains(t, out, "zone2  2        1       1                  true")
+	require.Contains(t, out, "zone3  1        1       0                  false")
+}
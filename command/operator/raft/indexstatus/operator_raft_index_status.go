@@ -0,0 +1,136 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package indexstatus
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/dhiaayachi/consul/api"
+	"github.com/dhiaayachi/consul/command/flags"
+	"github.com/mitchellh/cli"
+	"github.com/ryanuber/columnize"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+
+	maxLag uint64
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.flags.Uint64Var(&c.maxLag, "max-lag", 100,
+		"Maximum number of log entries a server's applied index may trail the "+
+			"highest commit index in the cluster before it is flagged as lagging.")
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		c.UI.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	// Set up a client.
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	reply, err := client.Operator().RaftGetConfiguration(&api.QueryOptions{
+		AllowStale: c.http.Stale(),
+	})
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to retrieve raft configuration: %v", err))
+		return 1
+	}
+
+	result, lagging := formatIndexStatus(reply.Servers, c.maxLag)
+	c.UI.Output(result)
+	if lagging > 0 {
+		c.UI.Error(fmt.Sprintf("%d of %d servers trail the commit index by more than %d entries",
+			lagging, len(reply.Servers), c.maxLag))
+		return 2
+	}
+	return 0
+}
+
+// formatIndexStatus renders a table of the commit and applied index of each
+// server, flagging those whose applied index trails the highest commit index
+// of the cluster by more than maxLag. It also returns the number of servers
+// that were flagged.
+func formatIndexStatus(servers []*api.RaftServer, maxLag uint64) (string, int) {
+	var clusterCommit uint64
+	for _, s := range servers {
+		if s.CommitIndex > clusterCommit {
+			clusterCommit = s.CommitIndex
+		}
+	}
+
+	lagging := 0
+	result := []string{"Node\x1fID\x1fState\x1fCommit Index\x1fApplied Index\x1fLag\x1fStatus"}
+	for _, s := range servers {
+		state := "follower"
+		if s.Leader {
+			state = "leader"
+		}
+
+		// Servers that haven't reported their stats to the leader yet, or
+		// that run a version that doesn't report them, can't be compared.
+		lag, status := "-", "unknown"
+		if s.CommitIndex != 0 || s.AppliedIndex != 0 {
+			var behind uint64
+			if clusterCommit > s.AppliedIndex {
+				behind = clusterCommit - s.AppliedIndex
+			}
+			lag, status = fmt.Sprintf("%d", behind), "ok"
+			if behind > maxLag {
+				status = "lagging"
+				lagging++
+			}
+		}
+
+		result = append(result, fmt.Sprintf("%s\x1f%s\x1f%s\x1f%d\x1f%d\x1f%s\x1f%s",
+			s.Node, s.ID, state, s.CommitIndex, s.AppliedIndex, lag, status))
+	}
+
+	return columnize.Format(result, &columnize.Config{Delim: string([]byte{0x1f})}), lagging
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Display the commit and applied Raft index of each server"
+const help = `
+Usage: consul operator raft index-status [options]
+
+  Displays the commit and applied Raft index of each server and flags any
+  server whose applied index trails the highest commit index in the cluster
+  by more than -max-lag entries. Use it after an incident to find servers
+  that have only partially applied the log before deciding on a restore.
+
+  The command exits with status 2 if any server is flagged.
+`
@@ -0,0 +1,91 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package indexstatus
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dhiaayachi/consul/agent"
+	"github.com/dhiaayachi/consul/api"
+	"github.com/dhiaayachi/consul/sdk/testutil/retry"
+	"github.com/dhiaayachi/consul/testrpc"
+)
+
+func TestOperatorRaftIndexStatusCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestOperatorRaftIndexStatusCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a1 := agent.NewTestAgent(t, ``)
+	defer a1.Shutdown()
+	testrpc.WaitForLeader(t, a1.RPC, "dc1")
+
+	a2 := agent.NewTestAgent(t, `
+		bootstrap = false
+	`)
+	defer a2.Shutdown()
+	_, err := a2.JoinLAN([]string{fmt.Sprintf("127.0.0.1:%d", a1.Config.SerfPortLAN)}, nil)
+	require.NoError(t, err)
+
+	retry.Run(t, func(r *retry.R) {
+		servers, err := a1.Client().Operator().RaftGetConfiguration(nil)
+		require.NoError(r, err)
+		require.Len(r, servers.Servers, 2)
+
+		var commits []uint64
+		for _, s := range servers.Servers {
+			require.NotZero(r, s.CommitIndex, "server %s has not reported its stats", s.Node)
+			require.LessOrEqual(r, s.AppliedIndex, s.CommitIndex)
+			commits = append(commits, s.CommitIndex)
+		}
+		diff := int64(commits[0]) - int64(commits[1])
+		require.LessOrEqual(r, diff, int64(10))
+		require.GreaterOrEqual(r, diff, int64(-10))
+
+		ui := cli.NewMockUi()
+		c := New(ui)
+		code := c.Run([]string{"-http-addr=" + a1.HTTPAddr(), "-max-lag=10"})
+		require.Equal(r, 0, code, ui.ErrorWriter.String())
+
+		output := ui.OutputWriter.String()
+		require.Contains(r, output, a1.Config.NodeName)
+		require.Contains(r, output, a2.Config.NodeName)
+		require.NotContains(r, output, "lagging")
+		require.NotContains(r, output, "unknown")
+	})
+}
+
+func TestFormatIndexStatus(t *testing.T) {
+	t.Parallel()
+
+	servers := []*api.RaftServer{
+		{Node: "alice", ID: "a", Leader: true, CommitIndex: 500, AppliedIndex: 500},
+		{Node: "bob", ID: "b", CommitIndex: 498, AppliedIndex: 495},
+		{Node: "carol", ID: "c", CommitIndex: 320, AppliedIndex: 300},
+		{Node: "dave", ID: "d"},
+	}
+
+	output, lagging := formatIndexStatus(servers, 100)
+	require.Equal(t, 1, lagging)
+
+	lines := strings.Split(output, "\n")
+	require.Len(t, lines, 5)
+	require.Regexp(t, `^alice\s+a\s+leader\s+500\s+500\s+0\s+ok$`, lines[1])
+	require.Regexp(t, `^bob\s+b\s+follower\s+498\s+495\s+5\s+ok$`, lines[2])
+	require.Regexp(t, `^carol\s+c\s+follower\s+320\s+300\s+200\s+lagging$`, lines[3])
+	require.Regexp(t, `^dave\s+d\s+follower\s+0\s+0\s+-\s+unknown$`, lines[4])
+}
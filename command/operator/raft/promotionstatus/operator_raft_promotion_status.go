@@ -0,0 +1,167 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package promotionstatus
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dhiaayachi/consul/api"
+	"github.com/dhiaayachi/consul/command/flags"
+	"github.com/mitchellh/cli"
+	"github.com/ryanuber/columnize"
+)
+
+func New(ui cli.Ui) *cmd {
+	c := &cmd{UI: ui}
+	c.init()
+	return c
+}
+
+type cmd struct {
+	UI    cli.Ui
+	flags *flag.FlagSet
+	http  *flags.HTTPFlags
+	help  string
+}
+
+func (c *cmd) init() {
+	c.flags = flag.NewFlagSet("", flag.ContinueOnError)
+	c.http = &flags.HTTPFlags{}
+	flags.Merge(c.flags, c.http.ClientFlags())
+	flags.Merge(c.flags, c.http.ServerFlags())
+	c.help = flags.Usage(help, c.flags)
+}
+
+func (c *cmd) Run(args []string) int {
+	if err := c.flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		c.UI.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	// Set up a client.
+	client, err := c.http.APIClient()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	q := &api.QueryOptions{
+		AllowStale: c.http.Stale(),
+	}
+	conf, err := client.Operator().AutopilotGetConfiguration(q)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to retrieve autopilot configuration: %v", err))
+		return 1
+	}
+	state, err := client.Operator().AutopilotState(q)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Failed to retrieve autopilot state: %v", err))
+		return 1
+	}
+
+	c.UI.Output(formatPromotionStatus(conf, state, time.Now()))
+	return 0
+}
+
+// formatPromotionStatus renders a table of the servers that are not voters,
+// along with whether they meet autopilot's promotion criteria.
+func formatPromotionStatus(conf *api.AutopilotConfiguration, state *api.AutopilotState, now time.Time) string {
+	var ids []string
+	for id, srv := range state.Servers {
+		if srv.Status == api.AutopilotServerNonVoter || srv.Status == api.AutopilotServerStaging {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return "All servers are voters"
+	}
+	sort.Strings(ids)
+
+	result := []string{"Node\x1fID\x1fAddress\x1fStatus\x1fHealthy\x1fStable For\x1fPromotable\x1fReason"}
+	for _, id := range ids {
+		srv := state.Servers[id]
+
+		promotable, reason := promotionProgress(conf, state, srv, now)
+		result = append(result, fmt.Sprintf("%s\x1f%s\x1f%s\x1f%s\x1f%v\x1f%s\x1f%v\x1f%s",
+			srv.Name, srv.ID, srv.Address, srv.Status, srv.Healthy, stableFor(srv, now), promotable, reason))
+	}
+
+	return columnize.Format(result, &columnize.Config{Delim: string([]byte{0x1f})})
+}
+
+// stableFor returns how long the server has been stable as of now, rounded
+// down to the second. StableSince is set by the leader, so a small clock skew
+// with this host is rendered as 0s rather than a negative duration.
+func stableFor(srv api.AutopilotServer, now time.Time) string {
+	if srv.StableSince.IsZero() {
+		return "-"
+	}
+	d := now.Sub(srv.StableSince)
+	if d < 0 {
+		d = 0
+	}
+	return d.Truncate(time.Second).String()
+}
+
+// promotionProgress reports whether the server meets autopilot's promotion
+// criteria: it must be healthy and have been stable for at least the
+// configured server stabilization time. Otherwise the reason it does not is
+// returned.
+func promotionProgress(conf *api.AutopilotConfiguration, state *api.AutopilotState, srv api.AutopilotServer, now time.Time) (bool, string) {
+	if srv.ReadReplica {
+		return false, "read replicas are never promoted"
+	}
+
+	if !srv.Healthy {
+		if srv.NodeStatus != "alive" {
+			return false, fmt.Sprintf("node status is %q", srv.NodeStatus)
+		}
+		if srv.LastContact != nil && conf.LastContactThreshold != nil &&
+			srv.LastContact.Duration() > conf.LastContactThreshold.Duration() {
+			return false, fmt.Sprintf("last contact %s exceeds %s", srv.LastContact, conf.LastContactThreshold)
+		}
+		if leader, ok := state.Servers[state.Leader]; ok {
+			if srv.LastTerm != leader.LastTerm {
+				return false, fmt.Sprintf("raft term %d does not match leader term %d", srv.LastTerm, leader.LastTerm)
+			}
+			if leader.LastIndex > srv.LastIndex+conf.MaxTrailingLogs {
+				return false, fmt.Sprintf("trails leader by %d logs, more than %d", leader.LastIndex-srv.LastIndex, conf.MaxTrailingLogs)
+			}
+		}
+		return false, "unhealthy"
+	}
+
+	var stabilization time.Duration
+	if conf.ServerStabilizationTime != nil {
+		stabilization = conf.ServerStabilizationTime.Duration()
+	}
+	if remaining := stabilization - now.Sub(srv.StableSince); remaining > 0 {
+		return false, fmt.Sprintf("stabilizing, %s remaining", remaining.Truncate(time.Second))
+	}
+
+	return true, "meets promotion criteria, awaiting autopilot"
+}
+
+func (c *cmd) Synopsis() string {
+	return synopsis
+}
+
+func (c *cmd) Help() string {
+	return c.help
+}
+
+const synopsis = "Display the promotion status of non-voting servers"
+const help = `
+Usage: consul operator raft promotion-status [options]
+
+  Displays the servers that are not Raft voters, how long each has been
+  stable, and whether it meets the autopilot criteria for promotion to voter.
+  This helps diagnose servers stuck as non-voters after being added.
+`
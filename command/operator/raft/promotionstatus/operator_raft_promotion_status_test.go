@@ -0,0 +1,103 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package promotionstatus
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dhiaayachi/consul/agent"
+	"github.com/dhiaayachi/consul/api"
+	"github.com/dhiaayachi/consul/sdk/testutil/retry"
+	"github.com/dhiaayachi/consul/testrpc"
+)
+
+func TestOperatorRaftPromotionStatusCommand_noTabs(t *testing.T) {
+	t.Parallel()
+	if strings.ContainsRune(New(cli.NewMockUi()).Help(), '\t') {
+		t.Fatal("help has tabs")
+	}
+}
+
+func TestFormatPromotionStatus_StableFor(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	conf := &api.AutopilotConfiguration{
+		ServerStabilizationTime: api.NewReadableDuration(10 * time.Second),
+	}
+	state := &api.AutopilotState{
+		Servers: map[string]api.AutopilotServer{
+			"a": {
+				ID: "a", Name: "stable", Status: api.AutopilotServerNonVoter,
+				Healthy: true, StableSince: now.Add(-90*time.Second - 500*time.Millisecond),
+			},
+			"b": {
+				ID: "b", Name: "skewed", Status: api.AutopilotServerNonVoter,
+				Healthy: true, StableSince: now.Add(2 * time.Second),
+			},
+			"c": {
+				ID: "c", Name: "unknown", Status: api.AutopilotServerNonVoter,
+				Healthy: false, NodeStatus: "left",
+			},
+		},
+	}
+
+	lines := strings.Split(formatPromotionStatus(conf, state, now), "\n")
+	require.Len(t, lines, 4)
+	require.Contains(t, lines[0], "Stable For")
+	require.Regexp(t, `^stable\s.*\s1m30s\s`, lines[1])
+	require.Regexp(t, `^skewed\s.*\s0s\s`, lines[2])
+	require.Regexp(t, `^unknown\s.*\s-\s`, lines[3])
+	require.NotContains(t, lines[1], "2024")
+}
+
+func TestOperatorRaftPromotionStatusCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a1 := agent.NewTestAgent(t, `
+		autopilot {
+			server_stabilization_time = "1h"
+		}
+	`)
+	defer a1.Shutdown()
+	testrpc.WaitForLeader(t, a1.RPC, "dc1")
+
+	// With a single voter, there is nothing to report
+	ui := cli.NewMockUi()
+	c := New(ui)
+	code := c.Run([]string{"-http-addr=" + a1.HTTPAddr()})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "All servers are voters")
+
+	// Add a fresh server, which stays a non-voter until it has been stable
+	// for the server stabilization time
+	a2 := agent.NewTestAgent(t, `
+		bootstrap = false
+	`)
+	defer a2.Shutdown()
+	_, err := a2.JoinLAN([]string{fmt.Sprintf("127.0.0.1:%d", a1.Config.SerfPortLAN)}, nil)
+	require.NoError(t, err)
+
+	retry.Run(t, func(r *retry.R) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+		code := c.Run([]string{"-http-addr=" + a1.HTTPAddr()})
+		require.Equal(r, 0, code, ui.ErrorWriter.String())
+
+		output := ui.OutputWriter.String()
+		require.Contains(r, output, a2.Config.NodeName)
+		require.Contains(r, output, "non-voter")
+		require.Contains(r, output, "stabilizing")
+		require.Contains(r, output, "remaining")
+	})
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/dhiaayachi/consul/api"
 	"github.com/dhiaayachi/consul/command/flags"
+	"github.com/hashicorp/serf/serf"
 	"github.com/mitchellh/cli"
 )
 
@@ -25,8 +26,9 @@ type cmd struct {
 	help  string
 
 	// flags
-	address string
-	id      string
+	address  string
+	id       string
+	deadOnly bool
 }
 
 func (c *cmd) init() {
@@ -35,6 +37,10 @@ func (c *cmd) init() {
 		"The address to remove from the Raft configuration.")
 	c.flags.StringVar(&c.id, "id", "",
 		"The ID to remove from the Raft configuration.")
+	c.flags.BoolVar(&c.deadOnly, "dead-only", false,
+		"Remove every server in the Raft configuration that is failed or has left "+
+			"the LAN gossip pool, instead of a single server given by -address or -id. "+
+			"Refuses to remove anything if the remaining servers do not have quorum.")
 
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
@@ -58,6 +64,14 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	if c.deadOnly {
+		if c.address != "" || c.id != "" {
+			c.UI.Error("Cannot give -dead-only with -address or -id")
+			return 1
+		}
+		return c.removeDeadPeers(client)
+	}
+
 	// Fetch the current configuration.
 	if err := raftRemovePeers(c.address, c.id, client.Operator()); err != nil {
 		c.UI.Error(fmt.Sprintf("Error removing peer: %v", err))
@@ -72,6 +86,75 @@ func (c *cmd) Run(args []string) int {
 	return 0
 }
 
+// removeDeadPeers removes every server from the Raft configuration whose node
+// is failed or left in the LAN gossip pool.
+func (c *cmd) removeDeadPeers(client *api.Client) int {
+	dead, err := deadPeers(client, c.http.Stale())
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error finding dead peers: %v", err))
+		return 1
+	}
+	if len(dead) == 0 {
+		c.UI.Output("No dead peers found in the Raft configuration")
+		return 0
+	}
+
+	for _, s := range dead {
+		if err := client.Operator().RaftRemovePeerByID(s.ID, nil); err != nil {
+			c.UI.Error(fmt.Sprintf("Error removing peer %q with id %q: %v", s.Node, s.ID, err))
+			return 1
+		}
+		c.UI.Output(fmt.Sprintf("Removed peer %q with id %q", s.Node, s.ID))
+	}
+	return 0
+}
+
+// deadPeers returns the servers in the Raft configuration whose node is failed
+// or left in the LAN gossip pool. It returns an error if the servers that are
+// still alive do not make up a quorum of the voters, since the removals could
+// not be committed then.
+func deadPeers(client *api.Client, stale bool) ([]*api.RaftServer, error) {
+	members, err := client.Agent().Members(false)
+	if err != nil {
+		return nil, err
+	}
+	status := make(map[string]serf.MemberStatus, len(members))
+	for _, m := range members {
+		status[m.Name] = serf.MemberStatus(m.Status)
+	}
+
+	reply, err := client.Operator().RaftGetConfiguration(&api.QueryOptions{
+		AllowStale: stale,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		dead       []*api.RaftServer
+		voters     int
+		deadVoters int
+	)
+	for _, s := range reply.Servers {
+		if s.Voter {
+			voters++
+		}
+		switch status[s.Node] {
+		case serf.StatusFailed, serf.StatusLeft:
+			dead = append(dead, s)
+			if s.Voter {
+				deadVoters++
+			}
+		}
+	}
+
+	if quorum := voters/2 + 1; voters-deadVoters < quorum {
+		return nil, fmt.Errorf("only %d of %d voters are alive, which is less than the quorum of %d",
+			voters-deadVoters, voters, quorum)
+	}
+	return dead, nil
+}
+
 func raftRemovePeers(address, id string, operator *api.Operator) error {
 	if len(address) == 0 && len(id) == 0 {
 		return fmt.Errorf("an address or id is required for the peer to remove")
@@ -114,4 +197,11 @@ Usage: consul operator raft remove-peer [options]
   quorum. If the server still shows in the output of the "consul members" command,
   it is preferable to clean up by simply running "consul force-leave" instead of
   this command.
+
+  To remove every server that is failed or has left the LAN gossip pool at
+  once, use the -dead-only flag:
+
+      $ consul operator raft remove-peer -dead-only
+
+  The removals are refused if the remaining servers do not have quorum.
 `
@@ -4,11 +4,18 @@
 package removepeer
 
 import (
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
-	"github.com/dhiaayachi/consul/agent"
+	"github.com/hashicorp/serf/serf"
 	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dhiaayachi/consul/agent"
+	"github.com/dhiaayachi/consul/sdk/testutil/retry"
+	"github.com/dhiaayachi/consul/testrpc"
 )
 
 func TestOperatorRaftRemovePeerCommand_noTabs(t *testing.T) {
@@ -61,3 +68,136 @@ func TestOperatorRaftRemovePeerCommand(t *testing.T) {
 		}
 	})
 }
+
+func TestOperatorRaftRemovePeerCommand_DeadOnly(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	// Keep autopilot from cleaning up the dead server before the command can.
+	a1 := agent.NewTestAgent(t, `
+		autopilot {
+			cleanup_dead_servers = false
+			server_stabilization_time = "100ms"
+		}
+	`)
+	defer a1.Shutdown()
+	testrpc.WaitForLeader(t, a1.RPC, "dc1")
+
+	joinAddr := fmt.Sprintf("127.0.0.1:%d", a1.Config.SerfPortLAN)
+	var others []*agent.TestAgent
+	for i := 0; i < 2; i++ {
+		a := agent.NewTestAgent(t, `
+			bootstrap = false
+			autopilot {
+				cleanup_dead_servers = false
+			}
+		`)
+		defer a.Shutdown()
+		_, err := a.JoinLAN([]string{joinAddr}, nil)
+		require.NoError(t, err)
+		others = append(others, a)
+	}
+	a2, a3 := others[0], others[1]
+
+	client := a1.Client()
+	retry.RunWith(&retry.Timer{Wait: 250 * time.Millisecond, Timeout: 30 * time.Second}, t, func(r *retry.R) {
+		reply, err := client.Operator().RaftGetConfiguration(nil)
+		require.NoError(r, err)
+		require.Len(r, reply.Servers, 3)
+		for _, s := range reply.Servers {
+			require.True(r, s.Voter, "%s is not a voter yet", s.Node)
+		}
+	})
+
+	// With every server alive there is nothing to remove.
+	ui := cli.NewMockUi()
+	code := New(ui).Run([]string{"-http-addr=" + a1.HTTPAddr(), "-dead-only"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "No dead peers found")
+
+	a3.Shutdown()
+	retry.Run(t, func(r *retry.R) {
+		var gone bool
+		for _, m := range a1.LANMembersInAgentPartition() {
+			if m.Name == a3.Config.NodeName && (m.Status == serf.StatusFailed || m.Status == serf.StatusLeft) {
+				gone = true
+			}
+		}
+		require.True(r, gone, "a3 has not been detected as failed or left after shutdown")
+	})
+
+	ui = cli.NewMockUi()
+	code = New(ui).Run([]string{"-http-addr=" + a1.HTTPAddr(), "-dead-only"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), fmt.Sprintf("Removed peer %q", a3.Config.NodeName))
+
+	reply, err := client.Operator().RaftGetConfiguration(nil)
+	require.NoError(t, err)
+	var nodes []string
+	for _, s := range reply.Servers {
+		nodes = append(nodes, s.Node)
+	}
+	require.ElementsMatch(t, []string{a1.Config.NodeName, a2.Config.NodeName}, nodes)
+
+	t.Run("cannot be combined with -id", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		code := New(ui).Run([]string{"-http-addr=" + a1.HTTPAddr(), "-dead-only", "-id=nope"})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "Cannot give -dead-only with -address or -id")
+	})
+}
+
+func TestOperatorRaftRemovePeerCommand_DeadOnlyNoQuorum(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a1 := agent.NewTestAgent(t, `
+		autopilot {
+			cleanup_dead_servers = false
+			server_stabilization_time = "100ms"
+		}
+	`)
+	defer a1.Shutdown()
+	testrpc.WaitForLeader(t, a1.RPC, "dc1")
+
+	a2 := agent.NewTestAgent(t, `
+		bootstrap = false
+		autopilot {
+			cleanup_dead_servers = false
+		}
+	`)
+	defer a2.Shutdown()
+	_, err := a2.JoinLAN([]string{fmt.Sprintf("127.0.0.1:%d", a1.Config.SerfPortLAN)}, nil)
+	require.NoError(t, err)
+
+	client := a1.Client()
+	retry.RunWith(&retry.Timer{Wait: 250 * time.Millisecond, Timeout: 30 * time.Second}, t, func(r *retry.R) {
+		reply, err := client.Operator().RaftGetConfiguration(nil)
+		require.NoError(r, err)
+		require.Len(r, reply.Servers, 2)
+		for _, s := range reply.Servers {
+			require.True(r, s.Voter, "%s is not a voter yet", s.Node)
+		}
+	})
+
+	// Losing one of two voters loses quorum, so nothing may be removed.
+	a2.Shutdown()
+	retry.Run(t, func(r *retry.R) {
+		var gone bool
+		for _, m := range a1.LANMembersInAgentPartition() {
+			if m.Name == a2.Config.NodeName && (m.Status == serf.StatusFailed || m.Status == serf.StatusLeft) {
+				gone = true
+			}
+		}
+		require.True(r, gone, "a2 has not been detected as failed or left after shutdown")
+	})
+
+	ui := cli.NewMockUi()
+	code := New(ui).Run([]string{"-http-addr=" + a1.HTTPAddr(), "-stale", "-dead-only"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "less than the quorum of 2")
+}
@@ -34,6 +34,7 @@ type cmd struct {
 	onlyBillable   bool
 	onlyConnect    bool
 	allDatacenters bool
+	filter         string
 }
 
 func (c *cmd) init() {
@@ -44,6 +45,8 @@ func (c *cmd) init() {
 		"Cannot be used with -billable.")
 	c.flags.BoolVar(&c.allDatacenters, "all-datacenters", false, "Display service counts from "+
 		"all datacenters.")
+	c.flags.StringVar(&c.filter, "filter", "", "Filter to use with the request. "+
+		"Only service instances matching the filter are counted.")
 
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
@@ -75,7 +78,10 @@ func (c *cmd) Run(args []string) int {
 
 	billableTotal := 0
 	var datacenterBillableTotals []string
-	usage, _, err := client.Operator().Usage(&api.QueryOptions{Global: c.allDatacenters})
+	usage, _, err := client.Operator().Usage(&api.QueryOptions{
+		Global: c.allDatacenters,
+		Filter: c.filter,
+	})
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Error fetching usage information: %s", err))
 		return 1
@@ -286,6 +292,10 @@ Usage: consul operator usage instances [options]
 
       $ consul operator usage instances -connect
 
+  To count only the instances of services whose name starts with "web":
+
+      $ consul operator usage instances -filter 'ServiceName matches "^web"'
+
   For a full list of options and examples, please see the Consul documentation.
 `
 )
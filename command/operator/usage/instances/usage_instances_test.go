@@ -50,6 +50,16 @@ func TestUsageInstancesCommand(t *testing.T) {
 			name:   "basic output",
 			output: "Billable Service Instances Total: 2",
 		},
+		{
+			name:      "filter narrows the counted instances",
+			extraArgs: []string{"-filter", `ServiceName == "testing2"`},
+			output:    "Billable Service Instances Total: 1",
+		},
+		{
+			name:      "invalid filter",
+			extraArgs: []string{"-filter", "ServiceName ==="},
+			err:       errors.New("Error fetching usage information"),
+		},
 		{
 			name:      "billable and connect flags together are invalid",
 			extraArgs: []string{"-billable", "-connect"},
@@ -75,9 +75,12 @@ import (
 	ixncheck "github.com/dhiaayachi/consul/command/intention/check"
 	ixncreate "github.com/dhiaayachi/consul/command/intention/create"
 	ixndelete "github.com/dhiaayachi/consul/command/intention/delete"
+	ixnexp "github.com/dhiaayachi/consul/command/intention/exp"
 	ixnget "github.com/dhiaayachi/consul/command/intention/get"
+	ixnimp "github.com/dhiaayachi/consul/command/intention/imp"
 	ixnlist "github.com/dhiaayachi/consul/command/intention/list"
 	ixnmatch "github.com/dhiaayachi/consul/command/intention/match"
+	ixntopology "github.com/dhiaayachi/consul/command/intention/topology"
 	"github.com/dhiaayachi/consul/command/join"
 	"github.com/dhiaayachi/consul/command/keygen"
 	"github.com/dhiaayachi/consul/command/keyring"
@@ -99,8 +102,11 @@ import (
 	operautoget "github.com/dhiaayachi/consul/command/operator/autopilot/get"
 	operautoset "github.com/dhiaayachi/consul/command/operator/autopilot/set"
 	operautostate "github.com/dhiaayachi/consul/command/operator/autopilot/state"
+	operautozones "github.com/dhiaayachi/consul/command/operator/autopilot/zones"
 	operraft "github.com/dhiaayachi/consul/command/operator/raft"
+	operraftindex "github.com/dhiaayachi/consul/command/operator/raft/indexstatus"
 	operraftlist "github.com/dhiaayachi/consul/command/operator/raft/listpeers"
+	operraftpromotion "github.com/dhiaayachi/consul/command/operator/raft/promotionstatus"
 	operraftremove "github.com/dhiaayachi/consul/command/operator/raft/removepeer"
 	"github.com/dhiaayachi/consul/command/operator/raft/transferleader"
 	"github.com/dhiaayachi/consul/command/operator/usage"
@@ -222,9 +228,12 @@ func RegisteredCommands(ui cli.Ui) map[string]mcli.CommandFactory {
 		entry{"intention check", func(ui cli.Ui) (cli.Command, error) { return ixncheck.New(ui), nil }},
 		entry{"intention create", func(ui cli.Ui) (cli.Command, error) { return ixncreate.New(ui), nil }},
 		entry{"intention delete", func(ui cli.Ui) (cli.Command, error) { return ixndelete.New(ui), nil }},
+		entry{"intention export", func(ui cli.Ui) (cli.Command, error) { return ixnexp.New(ui), nil }},
 		entry{"intention get", func(ui cli.Ui) (cli.Command, error) { return ixnget.New(ui), nil }},
+		entry{"intention import", func(ui cli.Ui) (cli.Command, error) { return ixnimp.New(ui), nil }},
 		entry{"intention list", func(ui cli.Ui) (cli.Command, error) { return ixnlist.New(ui), nil }},
 		entry{"intention match", func(ui cli.Ui) (cli.Command, error) { return ixnmatch.New(ui), nil }},
+		entry{"intention topology", func(ui cli.Ui) (cli.Command, error) { return ixntopology.New(ui), nil }},
 		entry{"join", func(ui cli.Ui) (cli.Command, error) { return join.New(ui), nil }},
 		entry{"keygen", func(ui cli.Ui) (cli.Command, error) { return keygen.New(ui), nil }},
 		entry{"keyring", func(ui cli.Ui) (cli.Command, error) { return keyring.New(ui), nil }},
@@ -246,8 +255,11 @@ func RegisteredCommands(ui cli.Ui) map[string]mcli.CommandFactory {
 		entry{"operator autopilot get-config", func(ui cli.Ui) (cli.Command, error) { return operautoget.New(ui), nil }},
 		entry{"operator autopilot set-config", func(ui cli.Ui) (cli.Command, error) { return operautoset.New(ui), nil }},
 		entry{"operator autopilot state", func(ui cli.Ui) (cli.Command, error) { return operautostate.New(ui), nil }},
+		entry{"operator autopilot zones", func(ui cli.Ui) (cli.Command, error) { return operautozones.New(ui), nil }},
 		entry{"operator raft", func(cli.Ui) (cli.Command, error) { return operraft.New(), nil }},
+		entry{"operator raft index-status", func(ui cli.Ui) (cli.Command, error) { return operraftindex.New(ui), nil }},
 		entry{"operator raft list-peers", func(ui cli.Ui) (cli.Command, error) { return operraftlist.New(ui), nil }},
+		entry{"operator raft promotion-status", func(ui cli.Ui) (cli.Command, error) { return operraftpromotion.New(ui), nil }},
 		entry{"operator raft remove-peer", func(ui cli.Ui) (cli.Command, error) { return operraftremove.New(ui), nil }},
 		entry{"operator raft transfer-leader", func(ui cli.Ui) (cli.Command, error) { return transferleader.New(ui), nil }},
 		entry{"operator usage", func(ui cli.Ui) (cli.Command, error) { return usage.New(), nil }},
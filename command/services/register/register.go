@@ -6,7 +6,10 @@ package register
 import (
 	"flag"
 	"fmt"
+	osexec "os/exec"
+	"time"
 
+	"github.com/dhiaayachi/consul/agent/exec"
 	"github.com/dhiaayachi/consul/api"
 	"github.com/dhiaayachi/consul/command/flags"
 	"github.com/dhiaayachi/consul/command/services"
@@ -35,6 +38,8 @@ type cmd struct {
 	flagTags            []string
 	flagMeta            map[string]string
 	flagTaggedAddresses map[string]string
+	flagHealthCommand   string
+	flagHealthTTL       time.Duration
 }
 
 func (c *cmd) init() {
@@ -60,6 +65,14 @@ func (c *cmd) init() {
 		"Tagged address to set on the service, formatted as key=value. This flag "+
 			"may be specified multiple times to set multiple addresses.")
 	c.flags.StringVar(&c.flagKind, "kind", "", "The services 'kind'")
+	c.flags.StringVar(&c.flagHealthCommand, "health-from-command", "",
+		"Command to run once before registering the services. Each service is "+
+			"registered with a TTL check that starts out passing if the command "+
+			"exits with 0, and critical otherwise, with the output of the command "+
+			"as the check output. Requires -health-ttl.")
+	c.flags.DurationVar(&c.flagHealthTTL, "health-ttl", 0,
+		"TTL of the check added by -health-from-command. The check turns critical "+
+			"if it is not updated within this duration.")
 
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
@@ -117,6 +130,31 @@ func (c *cmd) Run(args []string) int {
 		}
 	}
 
+	// A TTL check is the only kind whose status can be set from outside the
+	// agent, and it always expires, so the TTL must be chosen explicitly.
+	var healthStatus, healthOutput string
+	if c.flagHealthCommand != "" {
+		if c.flagHealthTTL <= 0 {
+			c.UI.Error("-health-from-command requires a positive -health-ttl")
+			return 1
+		}
+		var err error
+		healthStatus, healthOutput, err = healthFromCommand(c.flagHealthCommand)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error running health command: %s", err))
+			return 1
+		}
+		for _, svc := range svcs {
+			svc.Checks = append(svc.Checks, &api.AgentServiceCheck{
+				CheckID: healthCheckID(svc),
+				Name:    "Health from command",
+				Notes:   fmt.Sprintf("Initial status set by the exit code of %q", c.flagHealthCommand),
+				TTL:     c.flagHealthTTL.String(),
+				Status:  healthStatus,
+			})
+		}
+	}
+
 	// Create and test the HTTP client
 	client, err := c.http.APIClient()
 	if err != nil {
@@ -132,12 +170,52 @@ func (c *cmd) Run(args []string) int {
 			return 1
 		}
 
+		// The registration cannot carry the check output, so it is set with
+		// a TTL update right after.
+		if c.flagHealthCommand != "" {
+			err := client.Agent().UpdateTTLOpts(healthCheckID(svc), healthOutput, healthStatus,
+				&api.QueryOptions{Namespace: svc.Namespace, Partition: svc.Partition})
+			if err != nil {
+				c.UI.Error(fmt.Sprintf("Error setting the health check output of service %q: %s",
+					svc.Name, err))
+				return 1
+			}
+		}
+
 		c.UI.Output(fmt.Sprintf("Registered service: %s", svc.Name))
 	}
 
 	return 0
 }
 
+// healthCheckID returns the ID of the check added to svc by
+// -health-from-command.
+func healthCheckID(svc *api.AgentServiceRegistration) string {
+	id := svc.ID
+	if id == "" {
+		id = svc.Name
+	}
+	return "service:" + id + ":health-from-command"
+}
+
+// healthFromCommand runs script once and returns the check status matching
+// its exit code, passing if it exits with 0 and critical otherwise, along
+// with its combined output.
+func healthFromCommand(script string) (string, string, error) {
+	cmd, err := exec.Script(script)
+	if err != nil {
+		return "", "", err
+	}
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if _, ok := err.(*osexec.ExitError); ok {
+			return api.HealthCritical, string(output), nil
+		}
+		return "", "", err
+	}
+	return api.HealthPassing, string(output), nil
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -159,6 +237,13 @@ Usage: consul services register [options] [FILE...]
 
       $ consul services register web.json
 
+  To register the services with an initial health status, give a command
+  whose exit code decides it. The services get a TTL check that starts out
+  passing if the command exits with 0, and critical otherwise:
+
+      $ consul services register -name=web -health-ttl=10m \
+          -health-from-command="curl -sf localhost:8080"
+
   Additional flags and more advanced use cases are detailed below.
 `
 )
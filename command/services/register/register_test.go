@@ -228,3 +228,60 @@ func testFile(t *testing.T, suffix string) *os.File {
 
 	return f
 }
+
+func TestCommand_Flags_HealthFromCommand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	cases := map[string]struct {
+		command string
+		status  string
+		output  string
+	}{
+		"passing":  {"echo ok", api.HealthPassing, "ok\n"},
+		"critical": {"echo down; exit 3", api.HealthCritical, "down\n"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			c := New(ui)
+
+			args := []string{
+				"-http-addr=" + a.HTTPAddr(),
+				"-name", "web-" + name,
+				"-health-from-command", tc.command,
+				"-health-ttl", "1m",
+			}
+			require.Equal(t, 0, c.Run(args), ui.ErrorWriter.String())
+
+			checks, err := client.Agent().ChecksWithFilter(`ServiceID == "web-` + name + `"`)
+			require.NoError(t, err)
+			require.Len(t, checks, 1)
+			for _, chk := range checks {
+				require.Equal(t, tc.status, chk.Status)
+				require.Equal(t, tc.output, chk.Output)
+			}
+		})
+	}
+
+	t.Run("no ttl", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+
+		args := []string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-name", "web-no-ttl",
+			"-health-from-command", "exit 0",
+		}
+		require.Equal(t, 1, c.Run(args))
+		require.Contains(t, ui.ErrorWriter.String(), "requires a positive -health-ttl")
+	})
+}
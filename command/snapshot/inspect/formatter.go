@@ -19,6 +19,7 @@ const (
 
 type Formatter interface {
 	Format(*OutputFormat) (string, error)
+	FormatStats(*StatsOutputFormat) (string, error)
 }
 
 func GetSupportedFormats() []string {
@@ -79,6 +80,34 @@ func (_ *prettyFormatter) Format(info *OutputFormat) (string, error) {
 	return b.String(), nil
 }
 
+func (_ *prettyFormatter) FormatStats(info *StatsOutputFormat) (string, error) {
+	var b bytes.Buffer
+	tw := tabwriter.NewWriter(&b, 8, 8, 6, ' ', 0)
+
+	fmt.Fprintln(tw, " Type\tCount\tSize\tBytes\tPercent")
+	fmt.Fprintf(tw, " %s\t%s\t%s\t%s\t%s", "----", "----", "----", "----", "----")
+	for _, s := range info.Stats {
+		fmt.Fprintf(tw, "\n %s\t%d\t%s\t%d\t%s", s.Name, s.Count, ByteSize(uint64(s.Sum)), s.Sum, percent(s.Sum, info.TotalSize))
+	}
+	fmt.Fprintf(tw, "\n %s\t%s\t%s\t%s\t%s", "----", "----", "----", "----", "----")
+	fmt.Fprintf(tw, "\n Total\t%d\t%s\t%d\t%s", info.TotalCount, ByteSize(uint64(info.TotalSize)), info.TotalSize, percent(info.TotalSize, info.TotalSize))
+
+	if err := tw.Flush(); err != nil {
+		return b.String(), err
+	}
+
+	return b.String(), nil
+}
+
+// percent returns part as a percentage of total, with one decimal place.
+func percent(part, total int) string {
+	if total == 0 {
+		return "0%"
+	}
+	result := strconv.FormatFloat(float64(part)*100/float64(total), 'f', 1, 64)
+	return result + "%"
+}
+
 type jsonFormatter struct{}
 
 func newJSONFormatter() Formatter {
@@ -93,6 +122,14 @@ func (_ *jsonFormatter) Format(info *OutputFormat) (string, error) {
 	return string(b), nil
 }
 
+func (_ *jsonFormatter) FormatStats(info *StatsOutputFormat) (string, error) {
+	b, err := json.MarshalIndent(info, "", "   ")
+	if err != nil {
+		return "", fmt.Errorf("Failed to marshal snapshot stats: %v", err)
+	}
+	return string(b), nil
+}
+
 const (
 	BYTE = 1 << (10 * iota)
 	KILOBYTE
@@ -39,6 +39,7 @@ type cmd struct {
 	kvDetails bool
 	kvDepth   int
 	kvFilter  string
+	stats     bool
 }
 
 func (c *cmd) init() {
@@ -49,6 +50,10 @@ func (c *cmd) init() {
 		"Can only be used with -kvdetails. The key prefix depth used to breakdown KV store data. Defaults to 2.")
 	c.flags.StringVar(&c.kvFilter, "kvfilter", "",
 		"Can only be used with -kvdetails. Limits KV key breakdown using this prefix filter.")
+	c.flags.BoolVar(&c.stats, "stats", false,
+		"Only outputs the breakdown of the snapshot by message type, with the exact "+
+			"number of encoded bytes and the share of the snapshot used by each type. "+
+			"Cannot be used with -kvdetails.")
 	c.flags.StringVar(
 		&c.format,
 		"format",
@@ -78,6 +83,14 @@ type SnapshotInfo struct {
 	TotalSizeKV int
 }
 
+// StatsOutputFormat is used for passing the breakdown
+// of the snapshot by message type through the formatter
+type StatsOutputFormat struct {
+	Stats      []typeStats
+	TotalCount int
+	TotalSize  int
+}
+
 // OutputFormat is used for passing information
 // through the formatter
 type OutputFormat struct {
@@ -94,6 +107,11 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	if c.stats && c.kvDetails {
+		c.UI.Error("-stats cannot be used with -kvdetails")
+		return 1
+	}
+
 	var file string
 	args = c.flags.Args()
 
@@ -164,6 +182,26 @@ func (c *cmd) Run(args []string) int {
 		c.UI.Error(fmt.Sprintf("Error outputting enhanced snapshot data: %s", err))
 		return 1
 	}
+
+	if c.stats {
+		in := &StatsOutputFormat{
+			Stats:     generateStats(info),
+			TotalSize: info.TotalSize,
+		}
+		for _, s := range in.Stats {
+			in.TotalCount += s.Count
+		}
+
+		out, err := formatter.FormatStats(in)
+		if err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+
+		c.UI.Output(out)
+		return 0
+	}
+
 	//Generate structs for the formatter with information we read in
 	metaformat := &MetadataInfo{
 		ID:      meta.ID,
@@ -367,6 +405,10 @@ Usage: consul snapshot inspect [options] FILE
   To inspect the file "backup.snap":
 
     $ consul snapshot inspect backup.snap
+
+  To only show how many bytes each type of data uses in "backup.snap":
+
+    $ consul snapshot inspect -stats backup.snap
   
   For a full list of options and examples, please see the Consul documentation.
 `
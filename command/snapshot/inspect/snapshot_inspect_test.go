@@ -99,6 +99,32 @@ func TestSnapshotInspectCommand(t *testing.T) {
 	require.Equal(t, want, ui.OutputWriter.String())
 }
 
+func TestSnapshotInspectStatsCommand(t *testing.T) {
+	for _, format := range GetSupportedFormats() {
+		t.Run(format, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			c := New(ui)
+			args := []string{"-stats", "-format", format, "./testdata/backupWithKV.snap"}
+
+			code := c.Run(args)
+			require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+			want := golden(t, t.Name(), ui.OutputWriter.String())
+			require.Equal(t, want, ui.OutputWriter.String())
+		})
+	}
+
+	t.Run("with kvdetails", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+		args := []string{"-stats", "-kvdetails", "./testdata/backupWithKV.snap"}
+
+		code := c.Run(args)
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "-stats cannot be used with -kvdetails")
+	})
+}
+
 func TestSnapshotInspectKVDetailsCommand(t *testing.T) {
 
 	filepath := "./testdata/backupWithKV.snap"
@@ -6,10 +6,15 @@ package restore
 import (
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"sort"
 
+	"github.com/dhiaayachi/consul/api"
 	"github.com/dhiaayachi/consul/command/flags"
+	"github.com/dhiaayachi/consul/snapshot"
 	"github.com/mitchellh/cli"
+	"github.com/ryanuber/columnize"
 )
 
 func New(ui cli.Ui) *cmd {
@@ -23,6 +28,9 @@ type cmd struct {
 	flags *flag.FlagSet
 	http  *flags.HTTPFlags
 	help  string
+
+	decryptKeyFile string
+	verifyOnly     bool
 }
 
 func (c *cmd) init() {
@@ -30,6 +38,13 @@ func (c *cmd) init() {
 	c.http = &flags.HTTPFlags{}
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
+	c.flags.StringVar(&c.decryptKeyFile, "decrypt", "", "Path to a file containing the "+
+		"base64 encoded AES key that was used to encrypt the snapshot with "+
+		"\"consul snapshot save -encrypt\".")
+	c.flags.BoolVar(&c.verifyOnly, "verify-only", false, "Verify that the snapshot "+
+		"would restore cleanly on the servers without changing any state. The "+
+		"snapshot is restored into a throwaway copy of the server's state store "+
+		"and the number of entries in each table is reported.")
 	c.help = flags.Usage(help, c.flags)
 }
 
@@ -67,8 +82,31 @@ func (c *cmd) Run(args []string) int {
 	}
 	defer f.Close()
 
+	var in io.Reader = f
+	if c.decryptKeyFile != "" {
+		decrypted, err := c.decrypt(f)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error decrypting snapshot file: %s", err))
+			return 1
+		}
+		defer os.Remove(decrypted.Name())
+		defer decrypted.Close()
+		in = decrypted
+	}
+
+	if c.verifyOnly {
+		result, err := client.Snapshot().Verify(nil, in)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error verifying snapshot: %s", err))
+			return 1
+		}
+		c.UI.Output(formatVerifyResult(result))
+		c.UI.Info("Verified snapshot")
+		return 0
+	}
+
 	// Restore the snapshot.
-	err = client.Snapshot().Restore(nil, f)
+	err = client.Snapshot().Restore(nil, in)
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Error restoring snapshot: %s", err))
 		return 1
@@ -78,6 +116,58 @@ func (c *cmd) Run(args []string) int {
 	return 0
 }
 
+// formatVerifyResult renders the snapshot index and the non-empty tables of a
+// verified snapshot.
+func formatVerifyResult(result *api.SnapshotVerifyResult) string {
+	tables := make([]string, 0, len(result.TableCounts))
+	for name, count := range result.TableCounts {
+		if count > 0 {
+			tables = append(tables, name)
+		}
+	}
+	sort.Strings(tables)
+
+	rows := []string{"Table\x1fCount"}
+	for _, name := range tables {
+		rows = append(rows, fmt.Sprintf("%s\x1f%d", name, result.TableCounts[name]))
+	}
+
+	return fmt.Sprintf("Index: %d\n\n%s", result.Index, columnize.Format(rows, &columnize.Config{Delim: string([]byte{0x1f})}))
+}
+
+// decrypt decrypts the snapshot into a temporary file so the whole snapshot
+// is authenticated before any of it is sent to the servers. The caller is
+// responsible for closing and removing the returned file.
+func (c *cmd) decrypt(in io.Reader) (*os.File, error) {
+	data, err := os.ReadFile(c.decryptKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %v", err)
+	}
+	key, err := snapshot.ParseEncryptionKey(data)
+	if err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.CreateTemp("", "snapshot")
+	if err != nil {
+		return nil, err
+	}
+	cleanup := func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}
+
+	if err := snapshot.Decrypt(key, in, tmp); err != nil {
+		cleanup()
+		return nil, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		cleanup()
+		return nil, err
+	}
+	return tmp, nil
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -106,5 +196,13 @@ Usage: consul snapshot restore [options] FILE
 
     $ consul snapshot restore backup.snap
 
+  To restore a snapshot that was saved with -encrypt:
+
+    $ consul snapshot restore -decrypt=snapshot.key backup.snap
+
+  To check that "backup.snap" would restore cleanly without changing any state:
+
+    $ consul snapshot restore -verify-only backup.snap
+
   For a full list of options and examples, please see the Consul documentation.
 `
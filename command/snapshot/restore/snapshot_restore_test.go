@@ -5,6 +5,7 @@ package restore
 
 import (
 	"crypto/rand"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"os"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/dhiaayachi/consul/agent"
 	"github.com/dhiaayachi/consul/api"
+	"github.com/dhiaayachi/consul/command/snapshot/save"
 	"github.com/dhiaayachi/consul/sdk/testutil"
 	"github.com/mitchellh/cli"
 	"github.com/stretchr/testify/require"
@@ -167,3 +169,153 @@ func TestSnapshotRestoreCommand_TruncatedSnapshot(t *testing.T) {
 		})
 	}
 }
+
+func TestSnapshotRestoreCommand_VerifyOnly(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	_, err := client.KV().Put(&api.KVPair{Key: "foo", Value: []byte("before")}, nil)
+	require.NoError(t, err)
+
+	var inputData []byte
+	{
+		rc, _, err := client.Snapshot().Save(nil)
+		require.NoError(t, err)
+		defer rc.Close()
+
+		inputData, err = io.ReadAll(rc)
+		require.NoError(t, err)
+	}
+
+	// Change the state after the snapshot so we can tell that verifying
+	// didn't restore anything.
+	_, err = client.KV().Put(&api.KVPair{Key: "foo", Value: []byte("after")}, nil)
+	require.NoError(t, err)
+
+	dir := testutil.TempDir(t, "snapshot")
+
+	t.Run("valid snapshot", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+
+		file := filepath.Join(dir, "valid.snap")
+		require.NoError(t, os.WriteFile(file, inputData, 0644))
+
+		code := c.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-verify-only",
+			file,
+		})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+		output := ui.OutputWriter.String()
+		require.Contains(t, output, "Verified snapshot")
+		require.Regexp(t, `(?m)^kvs\s+1$`, output)
+
+		pair, _, err := client.KV().Get("foo", nil)
+		require.NoError(t, err)
+		require.NotNil(t, pair)
+		require.Equal(t, "after", string(pair.Value))
+	})
+
+	t.Run("truncated snapshot", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := New(ui)
+
+		file := filepath.Join(dir, "truncated.snap")
+		require.NoError(t, os.WriteFile(file, inputData[:len(inputData)/2], 0644))
+
+		code := c.Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-verify-only",
+			file,
+		})
+		require.Equal(t, 1, code, "expected non-zero exit")
+		require.Contains(t, ui.ErrorWriter.String(), "Error verifying snapshot")
+
+		pair, _, err := client.KV().Get("foo", nil)
+		require.NoError(t, err)
+		require.NotNil(t, pair)
+		require.Equal(t, "after", string(pair.Value))
+	})
+}
+
+func TestSnapshotRestoreCommand_Encrypted(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	t.Parallel()
+	a := agent.NewTestAgent(t, ``)
+	defer a.Shutdown()
+	client := a.Client()
+
+	_, err := client.KV().Put(&api.KVPair{Key: "secret", Value: []byte("hunter2")}, nil)
+	require.NoError(t, err)
+
+	dir := testutil.TempDir(t, "snapshot")
+	writeKey := func(name string) string {
+		key := make([]byte, 32)
+		_, err := rand.Read(key)
+		require.NoError(t, err)
+
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(key)), 0600))
+		return path
+	}
+	keyFile := writeKey("snapshot.key")
+	wrongKeyFile := writeKey("wrong.key")
+	file := filepath.Join(dir, "backup.snap")
+
+	{
+		ui := cli.NewMockUi()
+		code := save.New(ui).Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-encrypt=" + keyFile,
+			file,
+		})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		require.NotContains(t, string(data), "hunter2")
+	}
+
+	t.Run("wrong key", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		code := New(ui).Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-decrypt=" + wrongKeyFile,
+			file,
+		})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "wrong key")
+	})
+
+	t.Run("missing decrypt", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		code := New(ui).Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			file,
+		})
+		require.Equal(t, 1, code)
+		require.Contains(t, ui.ErrorWriter.String(), "Error restoring snapshot")
+	})
+
+	t.Run("correct key", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		code := New(ui).Run([]string{
+			"-http-addr=" + a.HTTPAddr(),
+			"-decrypt=" + keyFile,
+			file,
+		})
+		require.Equal(t, 0, code, ui.ErrorWriter.String())
+		require.Contains(t, ui.OutputWriter.String(), "Restored snapshot")
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"flag"
 	"fmt"
 	"golang.org/x/exp/slices"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -31,6 +32,7 @@ type cmd struct {
 	http               *flags.HTTPFlags
 	help               string
 	appendFileNameFlag flags.StringValue
+	encryptKeyFile     string
 }
 
 func (c *cmd) getAppendFileNameFlag() *flag.FlagSet {
@@ -46,6 +48,9 @@ func (c *cmd) init() {
 	flags.Merge(c.flags, c.http.ClientFlags())
 	flags.Merge(c.flags, c.http.ServerFlags())
 	flags.Merge(c.flags, c.getAppendFileNameFlag())
+	c.flags.StringVar(&c.encryptKeyFile, "encrypt", "", "Path to a file containing a "+
+		"base64 encoded AES key, such as one generated by \"consul keygen\". If set, the "+
+		"snapshot is encrypted with AES-GCM before it is written to FILE.")
 	c.help = flags.Usage(help, c.flags)
 }
 
@@ -68,6 +73,19 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	var encryptKey []byte
+	if c.encryptKeyFile != "" {
+		data, err := os.ReadFile(c.encryptKeyFile)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error reading encryption key file: %s", err))
+			return 1
+		}
+		if encryptKey, err = snapshot.ParseEncryptionKey(data); err != nil {
+			c.UI.Error(fmt.Sprintf("Error parsing encryption key file: %s", err))
+			return 1
+		}
+	}
+
 	// Create and test the HTTP client
 	client, err := c.http.APIClient()
 
@@ -150,6 +168,15 @@ func (c *cmd) Run(args []string) int {
 		return 1
 	}
 
+	if encryptKey != nil {
+		if err := encryptFile(encryptKey, unverifiedFile, file); err != nil {
+			c.UI.Error(fmt.Sprintf("Error encrypting snapshot file: %s", err))
+			return 1
+		}
+		c.UI.Info(fmt.Sprintf("Saved, verified and encrypted snapshot to index %d", qm.LastIndex))
+		return 0
+	}
+
 	if err := safeio.Rename(unverifiedFile, file); err != nil {
 		c.UI.Error(fmt.Sprintf("Error renaming %q to %q: %v", unverifiedFile, file, err))
 		return 1
@@ -159,6 +186,24 @@ func (c *cmd) Run(args []string) int {
 	return 0
 }
 
+// encryptFile writes an encrypted copy of the snapshot in src to dst.
+func encryptFile(key []byte, src, dst string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(snapshot.Encrypt(key, f, pw))
+	}()
+
+	_, err = safeio.WriteToFile(pr, dst, 0600)
+	pr.CloseWithError(err)
+	return err
+}
+
 func (c *cmd) Synopsis() string {
 	return synopsis
 }
@@ -187,5 +232,9 @@ Usage: consul snapshot save [options] FILE
 
     $ consul snapshot save -stale backup.snap
 
+  To encrypt the snapshot with a key stored in "snapshot.key":
+
+    $ consul snapshot save -encrypt=snapshot.key backup.snap
+
   For a full list of options and examples, please see the Consul documentation.
 `
@@ -41,6 +41,7 @@ type cmd struct {
 	prefix      string
 	service     string
 	tag         []string
+	serviceMeta map[string]string
 	passingOnly string
 	state       string
 	name        string
@@ -62,6 +63,10 @@ func (c *cmd) init() {
 			"optional for 'checks' type.")
 	c.flags.Var((*flags.AppendSliceValue)(&c.tag), "tag", "Specifies the service tag(s) to filter on. "+
 		"Optional for 'service' type. May be specified multiple times")
+	c.flags.Var((*flags.FlagMapValue)(&c.serviceMeta), "service-meta",
+		"Specifies a service metadata key/value pair to filter on, in the form `key=value`. "+
+			"Only instances with all of the given metadata are watched. Optional for "+
+			"'service' type. May be specified multiple times")
 	c.flags.StringVar(&c.passingOnly, "passingonly", "",
 		"Specifies if only hosts passing all checks are displayed. "+
 			"Optional for 'service' type, must be one of `[true|false]`. Defaults false.")
@@ -136,6 +141,9 @@ func (c *cmd) Run(args []string) int {
 	if len(c.tag) > 0 {
 		params["tag"] = c.tag
 	}
+	if len(c.serviceMeta) > 0 {
+		params["service_meta"] = c.serviceMeta
+	}
 	if c.http.Stale() {
 		params["stale"] = c.http.Stale()
 	}
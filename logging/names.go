@@ -32,6 +32,7 @@ const (
 	HTTP                  string = "http"
 	HTTPRouteController   string = "http_route_controller"
 	IngressGateway        string = "ingress_gateway"
+	IntentionAudit        string = "intention_audit"
 	Intentions            string = "intentions"
 	Internal              string = "internal"
 	KV                    string = "kvs"
@@ -1657,6 +1657,11 @@ func LoadBalancerToStructs(s *LoadBalancer, t *structs.LoadBalancer) {
 		LeastRequestConfigToStructs(s.LeastRequestConfig, &x)
 		t.LeastRequestConfig = &x
 	}
+	if s.SlowStartConfig != nil {
+		var x structs.SlowStartConfig
+		SlowStartConfigToStructs(s.SlowStartConfig, &x)
+		t.SlowStartConfig = &x
+	}
 	{
 		t.HashPolicies = make([]structs.HashPolicy, len(s.HashPolicies))
 		for i := range s.HashPolicies {
@@ -1681,6 +1686,11 @@ func LoadBalancerFromStructs(t *structs.LoadBalancer, s *LoadBalancer) {
 		LeastRequestConfigFromStructs(t.LeastRequestConfig, &x)
 		s.LeastRequestConfig = &x
 	}
+	if t.SlowStartConfig != nil {
+		var x SlowStartConfig
+		SlowStartConfigFromStructs(t.SlowStartConfig, &x)
+		s.SlowStartConfig = &x
+	}
 	{
 		s.HashPolicies = make([]*HashPolicy, len(t.HashPolicies))
 		for i := range t.HashPolicies {
@@ -1730,6 +1740,12 @@ func MeshConfigToStructs(s *MeshConfig, t *structs.MeshConfigEntry) {
 		PeeringMeshConfigToStructs(s.Peering, &x)
 		t.Peering = &x
 	}
+	t.AccessLogs = accessLogsConfigToStructs(s.AccessLogs)
+	if s.PassiveHealthCheck != nil {
+		var x structs.PassiveHealthCheck
+		PassiveHealthCheckToStructs(s.PassiveHealthCheck, &x)
+		t.PassiveHealthCheck = &x
+	}
 	t.Meta = s.Meta
 	t.Hash = s.Hash
 }
@@ -1759,6 +1775,12 @@ func MeshConfigFromStructs(t *structs.MeshConfigEntry, s *MeshConfig) {
 		PeeringMeshConfigFromStructs(t.Peering, &x)
 		s.Peering = &x
 	}
+	s.AccessLogs = accessLogsConfigFromStructs(t.AccessLogs)
+	if t.PassiveHealthCheck != nil {
+		var x PassiveHealthCheck
+		PassiveHealthCheckFromStructs(t.PassiveHealthCheck, &x)
+		s.PassiveHealthCheck = &x
+	}
 	s.Meta = t.Meta
 	s.Hash = t.Hash
 }
@@ -2446,6 +2468,20 @@ func ServiceResolverSubsetFromStructs(t *structs.ServiceResolverSubset, s *Servi
 	s.Filter = t.Filter
 	s.OnlyPassing = t.OnlyPassing
 }
+func SlowStartConfigToStructs(s *SlowStartConfig, t *structs.SlowStartConfig) {
+	if s == nil {
+		return
+	}
+	t.Window = structs.DurationFromProto(s.Window)
+	t.MinWeightPercent = s.MinWeightPercent
+}
+func SlowStartConfigFromStructs(t *structs.SlowStartConfig, s *SlowStartConfig) {
+	if s == nil {
+		return
+	}
+	s.Window = structs.DurationToProto(t.Window)
+	s.MinWeightPercent = t.MinWeightPercent
+}
 func SourceIntentionToStructs(s *SourceIntention, t *structs.SourceIntention) {
 	if s == nil {
 		return
@@ -2472,6 +2508,8 @@ func SourceIntentionToStructs(s *SourceIntention, t *structs.SourceIntention) {
 	t.EnterpriseMeta = enterpriseMetaToStructs(s.EnterpriseMeta)
 	t.Peer = s.Peer
 	t.SamenessGroup = s.SamenessGroup
+	t.NotBefore = timeToStructs(s.NotBefore)
+	t.NotAfter = timeToStructs(s.NotAfter)
 }
 func SourceIntentionFromStructs(t *structs.SourceIntention, s *SourceIntention) {
 	if s == nil {
@@ -2499,6 +2537,8 @@ func SourceIntentionFromStructs(t *structs.SourceIntention, s *SourceIntention)
 	s.EnterpriseMeta = enterpriseMetaFromStructs(t.EnterpriseMeta)
 	s.Peer = t.Peer
 	s.SamenessGroup = t.SamenessGroup
+	s.NotBefore = timeFromStructs(t.NotBefore)
+	s.NotAfter = timeFromStructs(t.NotAfter)
 }
 func StatusToStructs(s *Status, t *structs.Status) {
 	if s == nil {
@@ -13,6 +13,7 @@ import (
 	"github.com/dhiaayachi/consul/agent/structs"
 	"github.com/dhiaayachi/consul/api"
 	"github.com/dhiaayachi/consul/proto/private/pbcommon"
+	"github.com/dhiaayachi/consul/proto/private/pbservice"
 	"github.com/dhiaayachi/consul/types"
 )
 
@@ -361,6 +362,24 @@ func enterpriseMetaFromStructs(m acl.EnterpriseMeta) *pbcommon.EnterpriseMeta {
 	return pbcommon.NewEnterpriseMetaFromStructs(m)
 }
 
+func accessLogsConfigToStructs(a *pbservice.AccessLogsConfig) *structs.AccessLogsConfig {
+	if a == nil {
+		return nil
+	}
+	var out structs.AccessLogsConfig
+	pbservice.AccessLogsConfigToStructs(a, &out)
+	return &out
+}
+
+func accessLogsConfigFromStructs(a *structs.AccessLogsConfig) *pbservice.AccessLogsConfig {
+	if a == nil {
+		return nil
+	}
+	var out pbservice.AccessLogsConfig
+	pbservice.AccessLogsConfigFromStructs(a, &out)
+	return &out
+}
+
 func timeFromStructs(t *time.Time) *timestamppb.Timestamp {
 	if t == nil {
 		return nil
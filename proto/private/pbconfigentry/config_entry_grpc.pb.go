@@ -23,6 +23,11 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ConfigEntryServiceClient interface {
 	GetResolvedExportedServices(ctx context.Context, in *GetResolvedExportedServicesRequest, opts ...grpc.CallOption) (*GetResolvedExportedServicesResponse, error)
+	// WatchIntentionDecisions streams the intention decisions that apply to a
+	// destination service. The first response carries every decision and later
+	// responses carry the decisions that changed when the service-intentions
+	// config entries were updated.
+	WatchIntentionDecisions(ctx context.Context, in *WatchIntentionDecisionsRequest, opts ...grpc.CallOption) (ConfigEntryService_WatchIntentionDecisionsClient, error)
 }
 
 type configEntryServiceClient struct {
@@ -42,11 +47,48 @@ func (c *configEntryServiceClient) GetResolvedExportedServices(ctx context.Conte
 	return out, nil
 }
 
+func (c *configEntryServiceClient) WatchIntentionDecisions(ctx context.Context, in *WatchIntentionDecisionsRequest, opts ...grpc.CallOption) (ConfigEntryService_WatchIntentionDecisionsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ConfigEntryService_ServiceDesc.Streams[0], "/hashicorp.consul.internal.configentry.ConfigEntryService/WatchIntentionDecisions", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &configEntryServiceWatchIntentionDecisionsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ConfigEntryService_WatchIntentionDecisionsClient interface {
+	Recv() (*WatchIntentionDecisionsResponse, error)
+	grpc.ClientStream
+}
+
+type configEntryServiceWatchIntentionDecisionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *configEntryServiceWatchIntentionDecisionsClient) Recv() (*WatchIntentionDecisionsResponse, error) {
+	m := new(WatchIntentionDecisionsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ConfigEntryServiceServer is the server API for ConfigEntryService service.
 // All implementations should embed UnimplementedConfigEntryServiceServer
 // for forward compatibility
 type ConfigEntryServiceServer interface {
 	GetResolvedExportedServices(context.Context, *GetResolvedExportedServicesRequest) (*GetResolvedExportedServicesResponse, error)
+	// WatchIntentionDecisions streams the intention decisions that apply to a
+	// destination service. The first response carries every decision and later
+	// responses carry the decisions that changed when the service-intentions
+	// config entries were updated.
+	WatchIntentionDecisions(*WatchIntentionDecisionsRequest, ConfigEntryService_WatchIntentionDecisionsServer) error
 }
 
 // UnimplementedConfigEntryServiceServer should be embedded to have forward compatible implementations.
@@ -56,6 +98,9 @@ type UnimplementedConfigEntryServiceServer struct {
 func (UnimplementedConfigEntryServiceServer) GetResolvedExportedServices(context.Context, *GetResolvedExportedServicesRequest) (*GetResolvedExportedServicesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetResolvedExportedServices not implemented")
 }
+func (UnimplementedConfigEntryServiceServer) WatchIntentionDecisions(*WatchIntentionDecisionsRequest, ConfigEntryService_WatchIntentionDecisionsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchIntentionDecisions not implemented")
+}
 
 // UnsafeConfigEntryServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to ConfigEntryServiceServer will
@@ -86,6 +131,27 @@ func _ConfigEntryService_GetResolvedExportedServices_Handler(srv interface{}, ct
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ConfigEntryService_WatchIntentionDecisions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchIntentionDecisionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ConfigEntryServiceServer).WatchIntentionDecisions(m, &configEntryServiceWatchIntentionDecisionsServer{stream})
+}
+
+type ConfigEntryService_WatchIntentionDecisionsServer interface {
+	Send(*WatchIntentionDecisionsResponse) error
+	grpc.ServerStream
+}
+
+type configEntryServiceWatchIntentionDecisionsServer struct {
+	grpc.ServerStream
+}
+
+func (x *configEntryServiceWatchIntentionDecisionsServer) Send(m *WatchIntentionDecisionsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // ConfigEntryService_ServiceDesc is the grpc.ServiceDesc for ConfigEntryService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -98,6 +164,12 @@ var ConfigEntryService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _ConfigEntryService_GetResolvedExportedServices_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchIntentionDecisions",
+			Handler:       _ConfigEntryService_WatchIntentionDecisions_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "private/pbconfigentry/config_entry.proto",
 }
@@ -0,0 +1,42 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package pbconfigentry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dhiaayachi/consul/acl"
+	"github.com/dhiaayachi/consul/agent/structs"
+)
+
+func TestConfigEntry_MeshConfig_RoundTrip(t *testing.T) {
+	entry := &structs.MeshConfigEntry{
+		AccessLogs: &structs.AccessLogsConfig{
+			Enabled:             true,
+			DisableListenerLogs: true,
+			Type:                structs.FileLogSinkType,
+			Path:                "/var/log/envoy.log",
+			JSONFormat:          `{"start_time": "%START_TIME%"}`,
+		},
+		PassiveHealthCheck: &structs.PassiveHealthCheck{
+			Interval:                10 * time.Second,
+			MaxFailures:             5,
+			EnforcingConsecutive5xx: uintPointer(80),
+			MaxEjectionPercent:      uintPointer(50),
+			BaseEjectionTime:        structs.DurationPointer(30 * time.Second),
+		},
+		Meta:           map[string]string{"foo": "bar"},
+		EnterpriseMeta: *acl.DefaultEnterpriseMeta(),
+	}
+
+	result := ConfigEntryToStructs(ConfigEntryFromStructs(entry))
+	require.Equal(t, entry, result)
+}
+
+func uintPointer(v uint32) *uint32 {
+	return &v
+}
@@ -161,6 +161,7 @@ func HealthCheckDefinitionToStructs(s *HealthCheckDefinition, t *structs.HealthC
 	t.AliasNode = s.AliasNode
 	t.AliasService = s.AliasService
 	t.SessionName = s.SessionName
+	t.DependencyService = s.DependencyService
 	t.TTL = structs.DurationFromProto(s.TTL)
 }
 func HealthCheckDefinitionFromStructs(t *structs.HealthCheckDefinition, s *HealthCheckDefinition) {
@@ -192,5 +193,6 @@ func HealthCheckDefinitionFromStructs(t *structs.HealthCheckDefinition, s *Healt
 	s.AliasNode = t.AliasNode
 	s.AliasService = t.AliasService
 	s.SessionName = t.SessionName
+	s.DependencyService = t.DependencyService
 	s.TTL = structs.DurationToProto(t.TTL)
 }
@@ -0,0 +1,179 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package snapshot
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// An encrypted snapshot is framed as follows:
+//
+//	magic | nonce prefix (8 bytes) | chunk...
+//
+// where each chunk is:
+//
+//	final flag (1 byte) | ciphertext length (4 bytes, big endian) | ciphertext
+//
+// Every chunk is sealed with AES-GCM using the nonce prefix followed by the
+// big endian chunk counter as nonce, and the final flag as additional data.
+// This authenticates the order of the chunks as well as the end of the stream,
+// so reordered or truncated files are rejected along with files that were
+// encrypted with a different key.
+const (
+	encryptedMagic       = "consul-snapshot-aes-gcm-v1\n"
+	encryptedNoncePrefix = 8
+	encryptedChunkSize   = 64 * 1024
+)
+
+var (
+	// ErrNotEncrypted is returned by Decrypt when the input does not start
+	// with the encrypted snapshot header.
+	ErrNotEncrypted = errors.New("snapshot is not encrypted")
+
+	// ErrDecrypt is returned by Decrypt when a chunk of the snapshot fails to
+	// authenticate, which is usually caused by using the wrong key.
+	ErrDecrypt = errors.New("failed to decrypt snapshot: wrong key or corrupted file")
+)
+
+// ParseEncryptionKey parses a base64 encoded AES key, such as the ones
+// generated by "consul keygen". Surrounding whitespace is ignored.
+func ParseEncryptionKey(data []byte) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode key: %v", err)
+	}
+	switch len(key) {
+	case 16, 24, 32:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("key must be 16, 24 or 32 bytes long, got %d", len(key))
+	}
+}
+
+func newEncryptionAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func chunkNonce(prefix []byte, counter uint32) []byte {
+	nonce := make([]byte, len(prefix)+4)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[len(prefix):], counter)
+	return nonce
+}
+
+// Encrypt reads a snapshot from in and writes it to out encrypted with the
+// given key.
+func Encrypt(key []byte, in io.Reader, out io.Writer) error {
+	aead, err := newEncryptionAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	prefix := make([]byte, encryptedNoncePrefix)
+	if _, err := io.ReadFull(rand.Reader, prefix); err != nil {
+		return fmt.Errorf("failed to generate nonce: %v", err)
+	}
+	if _, err := io.WriteString(out, encryptedMagic); err != nil {
+		return err
+	}
+	if _, err := out.Write(prefix); err != nil {
+		return err
+	}
+
+	buf := make([]byte, encryptedChunkSize)
+	var counter uint32
+	for {
+		n, err := io.ReadFull(in, buf)
+		final := err == io.EOF || err == io.ErrUnexpectedEOF
+		if err != nil && !final {
+			return err
+		}
+
+		flag := []byte{0}
+		if final {
+			flag[0] = 1
+		}
+		sealed := aead.Seal(nil, chunkNonce(prefix, counter), buf[:n], flag)
+
+		var header [5]byte
+		header[0] = flag[0]
+		binary.BigEndian.PutUint32(header[1:], uint32(len(sealed)))
+		if _, err := out.Write(header[:]); err != nil {
+			return err
+		}
+		if _, err := out.Write(sealed); err != nil {
+			return err
+		}
+
+		if final {
+			return nil
+		}
+		counter++
+		if counter == 0 {
+			return fmt.Errorf("snapshot is too large to encrypt")
+		}
+	}
+}
+
+// Decrypt reads an encrypted snapshot from in and writes the decrypted
+// snapshot to out. Data is written as each chunk is authenticated, so callers
+// should only trust the output once Decrypt returns without error.
+func Decrypt(key []byte, in io.Reader, out io.Writer) error {
+	aead, err := newEncryptionAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	magic := make([]byte, len(encryptedMagic))
+	if _, err := io.ReadFull(in, magic); err != nil || !bytes.Equal(magic, []byte(encryptedMagic)) {
+		return ErrNotEncrypted
+	}
+	prefix := make([]byte, encryptedNoncePrefix)
+	if _, err := io.ReadFull(in, prefix); err != nil {
+		return fmt.Errorf("failed to read snapshot header: %v", err)
+	}
+
+	maxSealed := encryptedChunkSize + aead.Overhead()
+	sealed := make([]byte, maxSealed)
+	var counter uint32
+	for {
+		var header [5]byte
+		if _, err := io.ReadFull(in, header[:]); err != nil {
+			return fmt.Errorf("failed to read snapshot chunk: %v", err)
+		}
+		length := int(binary.BigEndian.Uint32(header[1:]))
+		if header[0] > 1 || length > maxSealed {
+			return ErrDecrypt
+		}
+		if _, err := io.ReadFull(in, sealed[:length]); err != nil {
+			return fmt.Errorf("failed to read snapshot chunk: %v", err)
+		}
+
+		plain, err := aead.Open(nil, chunkNonce(prefix, counter), sealed[:length], header[:1])
+		if err != nil {
+			return ErrDecrypt
+		}
+		if _, err := out.Write(plain); err != nil {
+			return err
+		}
+
+		if header[0] == 1 {
+			return nil
+		}
+		counter++
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package snapshot
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testEncryptionKey(t *testing.T) []byte {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return key
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	key := testEncryptionKey(t)
+
+	for _, size := range []int{0, 1, encryptedChunkSize - 1, encryptedChunkSize, 3*encryptedChunkSize + 17} {
+		data := make([]byte, size)
+		_, err := rand.Read(data)
+		require.NoError(t, err)
+
+		var encrypted bytes.Buffer
+		require.NoError(t, Encrypt(key, bytes.NewReader(data), &encrypted))
+		if size >= 64 {
+			require.NotContains(t, encrypted.String(), string(data[:64]))
+		}
+
+		var decrypted bytes.Buffer
+		require.NoError(t, Decrypt(key, bytes.NewReader(encrypted.Bytes()), &decrypted))
+		require.True(t, bytes.Equal(data, decrypted.Bytes()))
+	}
+}
+
+func TestDecrypt_Errors(t *testing.T) {
+	key := testEncryptionKey(t)
+
+	data := make([]byte, 2*encryptedChunkSize+5)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+
+	var encrypted bytes.Buffer
+	require.NoError(t, Encrypt(key, bytes.NewReader(data), &encrypted))
+
+	t.Run("wrong key", func(t *testing.T) {
+		err := Decrypt(testEncryptionKey(t), bytes.NewReader(encrypted.Bytes()), io.Discard)
+		require.ErrorIs(t, err, ErrDecrypt)
+	})
+
+	t.Run("not encrypted", func(t *testing.T) {
+		err := Decrypt(key, bytes.NewReader(data), io.Discard)
+		require.ErrorIs(t, err, ErrNotEncrypted)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		// Dropping the final chunk leaves a stream that authenticates chunk by
+		// chunk but never reaches the end marker.
+		truncated := encrypted.Bytes()[:len(encrypted.Bytes())-(5+5+16)]
+		err := Decrypt(key, bytes.NewReader(truncated), io.Discard)
+		require.Error(t, err)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		tampered := bytes.Clone(encrypted.Bytes())
+		tampered[len(tampered)-1] ^= 0xff
+		err := Decrypt(key, bytes.NewReader(tampered), io.Discard)
+		require.ErrorIs(t, err, ErrDecrypt)
+	})
+}
+
+func TestParseEncryptionKey(t *testing.T) {
+	key := testEncryptionKey(t)
+
+	got, err := ParseEncryptionKey([]byte(base64.StdEncoding.EncodeToString(key) + "\n"))
+	require.NoError(t, err)
+	require.Equal(t, key, got)
+
+	_, err = ParseEncryptionKey([]byte("not base64!"))
+	require.Error(t, err)
+
+	_, err = ParseEncryptionKey([]byte(base64.StdEncoding.EncodeToString([]byte("short"))))
+	require.ErrorContains(t, err, "must be 16, 24 or 32 bytes")
+}
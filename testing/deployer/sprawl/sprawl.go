@@ -50,6 +50,9 @@ type Sprawl struct {
 	topology  *topology.Topology
 	generator *tfgen.Generator
 
+	// set during Relaunch
+	topologyDiff *topology.TopologyDiff
+
 	clients        map[string]*api.Client      // one per cluster
 	grpcConns      map[string]*grpc.ClientConn // one per cluster (when v2 enabled)
 	grpcConnCancel map[string]func()           // one per cluster (when v2 enabled)
@@ -66,6 +69,13 @@ func (s *Sprawl) Topology() *topology.Topology {
 	return s.topology
 }
 
+// TopologyDiff returns what the most recent relaunch changed relative to the
+// topology before it, or nil if there has not been a relaunch yet. Do not
+// write to any of these fields.
+func (s *Sprawl) TopologyDiff() *topology.TopologyDiff {
+	return s.topologyDiff
+}
+
 func (s *Sprawl) Config() *topology.Config {
 	c2, err := copyConfig(s.config)
 	if err != nil {
@@ -440,12 +450,13 @@ func (s *Sprawl) RelaunchWithPhase(
 
 	s.logger = s.launchLogger.Named(launchPhase.String())
 
-	newTopology, err := topology.Recompile(s.logger.Named("recompile"), cfg, s.topology)
+	newTopology, diff, err := topology.Recompile(s.logger.Named("recompile"), cfg, s.topology)
 	if err != nil {
 		return fmt.Errorf("topology.Compile: %w", err)
 	}
 
 	s.topology = newTopology
+	s.topologyDiff = diff
 
 	s.logger.Debug("compiled replacement topology", "ct", jd(s.topology)) // TODO
 
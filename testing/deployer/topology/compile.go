@@ -8,9 +8,12 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"regexp"
 	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/google/go-cmp/cmp"
 	"golang.org/x/exp/maps"
@@ -24,22 +27,27 @@ import (
 const DockerPrefix = "cslc" // ConSuLCluster
 
 func Compile(logger hclog.Logger, raw *Config) (*Topology, error) {
-	return compile(logger, raw, nil, "")
+	t, _, err := compile(logger, raw, nil, "")
+	return t, err
 }
 
-func Recompile(logger hclog.Logger, raw *Config, prev *Topology) (*Topology, error) {
+// Recompile compiles raw as a replacement for prev, inheriting runtime
+// details like addresses and ports from it. Only some aspects of the topology
+// may change; the returned TopologyDiff summarizes the changes that were
+// allowed.
+func Recompile(logger hclog.Logger, raw *Config, prev *Topology) (*Topology, *TopologyDiff, error) {
 	if prev == nil {
-		return nil, errors.New("missing previous topology")
+		return nil, nil, errors.New("missing previous topology")
 	}
 	return compile(logger, raw, prev, "")
 }
 
-func compile(logger hclog.Logger, raw *Config, prev *Topology, testingID string) (*Topology, error) {
+func compile(logger hclog.Logger, raw *Config, prev *Topology, testingID string) (*Topology, *TopologyDiff, error) {
 	if logger == nil {
-		return nil, errors.New("logger is required")
+		return nil, nil, errors.New("logger is required")
 	}
 	if raw == nil {
-		return nil, errors.New("config is required")
+		return nil, nil, errors.New("config is required")
 	}
 
 	var id string
@@ -49,7 +57,7 @@ func compile(logger hclog.Logger, raw *Config, prev *Topology, testingID string)
 		var err error
 		id, err = newTopologyID()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	} else {
 		id = prev.ID
@@ -57,23 +65,23 @@ func compile(logger hclog.Logger, raw *Config, prev *Topology, testingID string)
 
 	images := DefaultImages().OverrideWith(raw.Images)
 	if images.Consul != "" {
-		return nil, fmt.Errorf("topology.images.consul cannot be set at this level")
+		return nil, nil, fmt.Errorf("topology.images.consul cannot be set at this level")
 	}
 
 	if len(raw.Networks) == 0 {
-		return nil, fmt.Errorf("topology.networks is empty")
+		return nil, nil, fmt.Errorf("topology.networks is empty")
 	}
 
 	networks := make(map[string]*Network)
 	for _, net := range raw.Networks {
 		if net.DockerName != "" {
-			return nil, fmt.Errorf("network %q should not specify DockerName", net.Name)
+			return nil, nil, fmt.Errorf("network %q should not specify DockerName", net.Name)
 		}
 		if !IsValidLabel(net.Name) {
-			return nil, fmt.Errorf("network name is not valid: %s", net.Name)
+			return nil, nil, fmt.Errorf("network name is not valid: %s", net.Name)
 		}
 		if _, exists := networks[net.Name]; exists {
-			return nil, fmt.Errorf("cannot have two networks with the same name %q", net.Name)
+			return nil, nil, fmt.Errorf("cannot have two networks with the same name %q", net.Name)
 		}
 
 		switch net.Type {
@@ -81,7 +89,7 @@ func compile(logger hclog.Logger, raw *Config, prev *Topology, testingID string)
 			net.Type = "lan"
 		case "wan", "lan":
 		default:
-			return nil, fmt.Errorf("network %q has unknown type %q", net.Name, net.Type)
+			return nil, nil, fmt.Errorf("network %q has unknown type %q", net.Name, net.Type)
 		}
 
 		networks[net.Name] = net
@@ -89,7 +97,7 @@ func compile(logger hclog.Logger, raw *Config, prev *Topology, testingID string)
 	}
 
 	if len(raw.Clusters) == 0 {
-		return nil, fmt.Errorf("topology.clusters is empty")
+		return nil, nil, fmt.Errorf("topology.clusters is empty")
 	}
 
 	var (
@@ -120,6 +128,10 @@ func compile(logger hclog.Logger, raw *Config, prev *Topology, testingID string)
 			c.NetworkName = c.Name
 		}
 
+		if c.PortBase < 0 || c.PortBase > 65535 {
+			return nil, fmt.Errorf("cluster %q has invalid port base: %d", c.Name, c.PortBase)
+		}
+
 		c.Images = images.OverrideWith(c.Images).ChooseConsul(c.Enterprise)
 
 		if _, ok := networks[c.NetworkName]; !ok {
@@ -229,6 +241,12 @@ func compile(logger hclog.Logger, raw *Config, prev *Topology, testingID string)
 
 			n.Images = c.Images.OverrideWith(n.Images.ChooseConsul(c.Enterprise)).ChooseNode(n.Kind)
 
+			if n.Resources != nil {
+				if err := compileNodeResources(n.Resources); err != nil {
+					return nil, fmt.Errorf("node resources are not valid: %w", err)
+				}
+			}
+
 			n.Cluster = c.Name
 			n.Datacenter = c.Datacenter
 			n.dockerName = DockerPrefix + "-" + n.Name + "-" + id
@@ -278,6 +296,37 @@ func compile(logger hclog.Logger, raw *Config, prev *Topology, testingID string)
 				return nil, fmt.Errorf("cluster %q node %q uses dataplane, but has more than one service", c.Name, n.Name)
 			}
 
+			// With a port base, envoy ports left unset are assigned in
+			// workload order from it. Every port set explicitly on the node
+			// is skipped so that an assigned port never takes one of them.
+			var nextPort func() (int, error)
+			if c.PortBase > 0 {
+				reserved := make(map[int]struct{})
+				for port := range n.usedPorts {
+					reserved[port] = struct{}{}
+				}
+				for _, wrk := range n.Workloads {
+					for _, port := range wrk.ports() {
+						reserved[port] = struct{}{}
+					}
+				}
+				next := c.PortBase
+				nextPort = func() (int, error) {
+					for {
+						if _, ok := reserved[next]; !ok {
+							break
+						}
+						next++
+					}
+					if next > 65535 {
+						return 0, fmt.Errorf("cluster %q node %q ran out of ports to assign from port base %d", c.Name, n.Name, c.PortBase)
+					}
+					port := next
+					next++
+					return port, nil
+				}
+			}
+
 			var (
 				foundPeerNames = make(map[string]struct{})
 				seenServices   = make(map[ID]struct{})
@@ -312,6 +361,23 @@ func compile(logger hclog.Logger, raw *Config, prev *Topology, testingID string)
 				}
 				seenServices[wrk.ID] = struct{}{}
 
+				if !wrk.DisableServiceMesh && nextPort != nil {
+					if wrk.EnvoyAdminPort <= 0 {
+						port, err := nextPort()
+						if err != nil {
+							return nil, err
+						}
+						wrk.EnvoyAdminPort = port
+					}
+					if n.IsDataplane() && wrk.EnvoyPublicListenerPort <= 0 {
+						port, err := nextPort()
+						if err != nil {
+							return nil, err
+						}
+						wrk.EnvoyPublicListenerPort = port
+					}
+				}
+
 				if !wrk.DisableServiceMesh && n.IsDataplane() {
 					if wrk.EnvoyPublicListenerPort <= 0 {
 						if _, ok := n.usedPorts[20000]; !ok {
@@ -432,13 +498,13 @@ func compile(logger hclog.Logger, raw *Config, prev *Topology, testingID string)
 	for _, c := range raw.Clusters {
 		peerNames, err := compileCluster(c)
 		if err != nil {
-			return nil, fmt.Errorf("error building cluster %q: %w", c.Name, err)
+			return nil, nil, fmt.Errorf("error building cluster %q: %w", c.Name, err)
 		}
 
 		foundPeerNames[c.Name] = peerNames
 
 		if _, exists := clusters[c.Name]; exists {
-			return nil, fmt.Errorf("cannot have two clusters with the same name %q; use unique names and override the Datacenter field if that's what you want", c.Name)
+			return nil, nil, fmt.Errorf("cannot have two clusters with the same name %q; use unique names and override the Datacenter field if that's what you want", c.Name)
 		}
 
 		clusters[c.Name] = c
@@ -456,14 +522,14 @@ func compile(logger hclog.Logger, raw *Config, prev *Topology, testingID string)
 	for _, p := range raw.Peerings {
 		dialingCluster, ok := clusters[p.Dialing.Name]
 		if !ok {
-			return nil, fmt.Errorf("peering references a dialing cluster that does not exist: %s", p.Dialing.Name)
+			return nil, nil, fmt.Errorf("peering references a dialing cluster that does not exist: %s", p.Dialing.Name)
 		}
 		acceptingCluster, ok := clusters[p.Accepting.Name]
 		if !ok {
-			return nil, fmt.Errorf("peering references an accepting cluster that does not exist: %s", p.Accepting.Name)
+			return nil, nil, fmt.Errorf("peering references an accepting cluster that does not exist: %s", p.Accepting.Name)
 		}
 		if p.Dialing.Name == p.Accepting.Name {
-			return nil, fmt.Errorf("self peerings are not allowed: %s", p.Dialing.Name)
+			return nil, nil, fmt.Errorf("self peerings are not allowed: %s", p.Dialing.Name)
 		}
 
 		p.Dialing.Partition = PartitionOrDefault(p.Dialing.Partition)
@@ -471,20 +537,20 @@ func compile(logger hclog.Logger, raw *Config, prev *Topology, testingID string)
 
 		if dialingCluster.Enterprise {
 			if !dialingCluster.hasPartition(p.Dialing.Partition) {
-				return nil, fmt.Errorf("dialing side of peering cannot reference a partition that does not exist: %s", p.Dialing.Partition)
+				return nil, nil, fmt.Errorf("dialing side of peering cannot reference a partition that does not exist: %s", p.Dialing.Partition)
 			}
 		} else {
 			if p.Dialing.Partition != "default" {
-				return nil, fmt.Errorf("dialing side of peering cannot reference a partition when CE")
+				return nil, nil, fmt.Errorf("dialing side of peering cannot reference a partition when CE")
 			}
 		}
 		if acceptingCluster.Enterprise {
 			if !acceptingCluster.hasPartition(p.Accepting.Partition) {
-				return nil, fmt.Errorf("accepting side of peering cannot reference a partition that does not exist: %s", p.Accepting.Partition)
+				return nil, nil, fmt.Errorf("accepting side of peering cannot reference a partition that does not exist: %s", p.Accepting.Partition)
 			}
 		} else {
 			if p.Accepting.Partition != "default" {
-				return nil, fmt.Errorf("accepting side of peering cannot reference a partition when CE")
+				return nil, nil, fmt.Errorf("accepting side of peering cannot reference a partition when CE")
 			}
 		}
 
@@ -521,7 +587,7 @@ func compile(logger hclog.Logger, raw *Config, prev *Topology, testingID string)
 				pretty = append(pretty, name)
 			}
 			sort.Strings(pretty)
-			return nil, fmt.Errorf("cluster[%s] found topology references to peerings that do not exist: %v", cluster, pretty)
+			return nil, nil, fmt.Errorf("cluster[%s] found topology references to peerings that do not exist: %v", cluster, pretty)
 		}
 	}
 
@@ -538,7 +604,7 @@ func compile(logger hclog.Logger, raw *Config, prev *Topology, testingID string)
 					}
 					remotePeer, ok := c.Peerings[us.Peer]
 					if !ok {
-						return nil, fmt.Errorf("not possible")
+						return nil, nil, fmt.Errorf("not possible")
 					}
 					us.Cluster = remotePeer.Link.Name
 					us.Peering = remotePeer.Link
@@ -558,10 +624,13 @@ func compile(logger hclog.Logger, raw *Config, prev *Topology, testingID string)
 		NetworkAreas: raw.NetworkAreas,
 	}
 
+	var diff *TopologyDiff
 	if prev != nil {
+		diff = &TopologyDiff{}
+
 		// networks cannot change
 		if !sameKeys(prev.Networks, t.Networks) {
-			return nil, fmt.Errorf("cannot create or destroy networks")
+			return nil, nil, fmt.Errorf("cannot create or destroy networks")
 		}
 
 		for _, newNetwork := range t.Networks {
@@ -571,14 +640,14 @@ func compile(logger hclog.Logger, raw *Config, prev *Topology, testingID string)
 			newNetwork.inheritFromExisting(oldNetwork)
 
 			if err := isSame(oldNetwork, newNetwork); err != nil {
-				return nil, fmt.Errorf("networks cannot change: %w", err)
+				return nil, nil, fmt.Errorf("networks cannot change: %w", err)
 			}
 
 		}
 
 		// cannot add or remove an entire cluster
 		if !sameKeys(prev.Clusters, t.Clusters) {
-			return nil, fmt.Errorf("cannot create or destroy clusters")
+			return nil, nil, fmt.Errorf("cannot create or destroy clusters")
 		}
 
 		for _, newCluster := range t.Clusters {
@@ -591,7 +660,7 @@ func compile(logger hclog.Logger, raw *Config, prev *Topology, testingID string)
 				newCluster.NetworkName != oldCluster.NetworkName ||
 				newCluster.Datacenter != oldCluster.Datacenter ||
 				newCluster.Enterprise != oldCluster.Enterprise {
-				return nil, fmt.Errorf("cannot edit some cluster fields for %q", newCluster.Name)
+				return nil, nil, fmt.Errorf("cannot edit some cluster fields for %q", newCluster.Name)
 			}
 
 			// WARN on presence of some things.
@@ -603,19 +672,26 @@ func compile(logger hclog.Logger, raw *Config, prev *Topology, testingID string)
 			}
 
 			// Check NODES
-			if err := inheritAndValidateNodes(oldCluster.Nodes, newCluster.Nodes); err != nil {
-				return nil, fmt.Errorf("some immutable aspects of nodes were changed in cluster %q: %w", newCluster.Name, err)
+			if err := inheritAndValidateNodes(newCluster.Name, oldCluster.Nodes, newCluster.Nodes, diff); err != nil {
+				return nil, nil, fmt.Errorf("some immutable aspects of nodes were changed in cluster %q: %w", newCluster.Name, err)
 			}
 		}
+
+		diff.sort()
 	}
 
-	return t, nil
+	return t, diff, nil
 }
 
 const permutedWarning = "use the disabled node kind if you want to ignore a node"
 
+// inheritAndValidateNodes carries over runtime details from the prev nodes to
+// the curr nodes of the named cluster, and records the allowed changes in
+// diff.
 func inheritAndValidateNodes(
+	cluster string,
 	prev, curr []*Node,
+	diff *TopologyDiff,
 ) error {
 	nodeMap := mapifyNodes(curr)
 
@@ -645,6 +721,16 @@ func inheritAndValidateNodes(
 
 		currNode.Node.inheritFromExisting(node)
 
+		if currNode.Node.Images != node.Images {
+			diff.Nodes = append(diff.Nodes, NodeDiff{
+				Cluster:    cluster,
+				ID:         node.ID(),
+				Kind:       node.Kind,
+				PrevImages: node.Images,
+				Images:     currNode.Node.Images,
+			})
+		}
+
 		for i := 0; i < len(currNode.Node.Addresses); i++ {
 			prevAddr := node.Addresses[i]
 			currAddr := currNode.Node.Addresses[i]
@@ -679,6 +765,16 @@ func inheritAndValidateNodes(
 			}
 
 			currWrk.inheritFromExisting(wrk)
+
+			if currWrk.Image != wrk.Image {
+				diff.Workloads = append(diff.Workloads, WorkloadDiff{
+					Cluster:   cluster,
+					Node:      node.ID(),
+					ID:        wrk.ID,
+					PrevImage: wrk.Image,
+					Image:     currWrk.Image,
+				})
+			}
 		}
 	}
 	return nil
@@ -758,3 +854,75 @@ func mapifyWorkloads(workloads []*Service) map[ID]*Service {
 	}
 	return m
 }
+
+// compileNodeResources validates the user supplied resource limits and
+// computes the values handed to the docker provider.
+func compileNodeResources(r *NodeResources) error {
+	r.cpuShares = 0
+	r.memoryMB = 0
+
+	if r.CPU != "" {
+		cpu, err := strconv.ParseFloat(r.CPU, 64)
+		if err != nil {
+			return fmt.Errorf("cpu %q is not a number: %w", r.CPU, err)
+		}
+		if math.IsNaN(cpu) || math.IsInf(cpu, 0) {
+			return fmt.Errorf("cpu %q must be finite", r.CPU)
+		}
+		if cpu < 0 {
+			return fmt.Errorf("cpu %q must not be negative", r.CPU)
+		}
+		r.cpuShares = int(math.Round(cpu * 1024))
+		if cpu > 0 && r.cpuShares < 2 {
+			// docker rejects weights below 2
+			r.cpuShares = 2
+		}
+	}
+
+	if r.Memory != "" {
+		bytes, err := parseMemoryBytes(r.Memory)
+		if err != nil {
+			return err
+		}
+		const mib = 1024 * 1024
+		if bytes > 0 && bytes < mib {
+			return fmt.Errorf("memory %q must be at least 1m", r.Memory)
+		}
+		r.memoryMB = int(bytes / mib)
+	}
+
+	return nil
+}
+
+// parseMemoryBytes parses a docker style memory size such as "512m" or "2g"
+// into a number of bytes.
+func parseMemoryBytes(s string) (int64, error) {
+	num := strings.ToLower(strings.TrimSpace(s))
+	var mult int64 = 1
+	if num != "" {
+		switch num[len(num)-1] {
+		case 'b':
+			num = num[:len(num)-1]
+		case 'k':
+			mult = 1024
+			num = num[:len(num)-1]
+		case 'm':
+			mult = 1024 * 1024
+			num = num[:len(num)-1]
+		case 'g':
+			mult = 1024 * 1024 * 1024
+			num = num[:len(num)-1]
+		}
+	}
+	v, err := strconv.ParseInt(num, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("memory %q is not a valid size: %w", s, err)
+	}
+	if v < 0 {
+		return 0, fmt.Errorf("memory %q must not be negative", s)
+	}
+	if v > math.MaxInt64/mult {
+		return 0, fmt.Errorf("memory %q is too large", s)
+	}
+	return v * mult, nil
+}
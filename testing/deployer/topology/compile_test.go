@@ -28,7 +28,7 @@ func TestCompile_CE(t *testing.T) {
 	const clusterID = "87c82bd03dc89d4d"
 
 	run := func(t *testing.T, tc testcase) {
-		got, err := compile(logger, tc.in, nil, clusterID)
+		got, _, err := compile(logger, tc.in, nil, clusterID)
 		if tc.expectErr == "" {
 			require.NotNil(t, tc.expect, "field must be set")
 			require.NoError(t, err)
@@ -476,6 +476,86 @@ func TestCompile_CE(t *testing.T) {
 			},
 			expectErr: `error building cluster "foo": error compiling node "zim": user cannot specify the node index`,
 		},
+		"node/resources negative cpu": {
+			in: &Config{
+				Networks: []*Network{
+					{Name: "foo"},
+				},
+				Clusters: []*Cluster{{
+					Name: "foo",
+					Nodes: []*Node{{
+						Kind:      NodeKindServer,
+						Name:      "zim",
+						Resources: &NodeResources{CPU: "-1"},
+					}},
+				}},
+			},
+			expectErr: `error building cluster "foo": error compiling node "zim": node resources are not valid: cpu "-1" must not be negative`,
+		},
+		"node/resources unparseable cpu": {
+			in: &Config{
+				Networks: []*Network{
+					{Name: "foo"},
+				},
+				Clusters: []*Cluster{{
+					Name: "foo",
+					Nodes: []*Node{{
+						Kind:      NodeKindServer,
+						Name:      "zim",
+						Resources: &NodeResources{CPU: "lots"},
+					}},
+				}},
+			},
+			expectErr: `error building cluster "foo": error compiling node "zim": node resources are not valid: cpu "lots" is not a number`,
+		},
+		"node/resources negative memory": {
+			in: &Config{
+				Networks: []*Network{
+					{Name: "foo"},
+				},
+				Clusters: []*Cluster{{
+					Name: "foo",
+					Nodes: []*Node{{
+						Kind:      NodeKindServer,
+						Name:      "zim",
+						Resources: &NodeResources{Memory: "-512m"},
+					}},
+				}},
+			},
+			expectErr: `error building cluster "foo": error compiling node "zim": node resources are not valid: memory "-512m" must not be negative`,
+		},
+		"node/resources unparseable memory": {
+			in: &Config{
+				Networks: []*Network{
+					{Name: "foo"},
+				},
+				Clusters: []*Cluster{{
+					Name: "foo",
+					Nodes: []*Node{{
+						Kind:      NodeKindServer,
+						Name:      "zim",
+						Resources: &NodeResources{Memory: "12x"},
+					}},
+				}},
+			},
+			expectErr: `error building cluster "foo": error compiling node "zim": node resources are not valid: memory "12x" is not a valid size`,
+		},
+		"node/resources tiny memory": {
+			in: &Config{
+				Networks: []*Network{
+					{Name: "foo"},
+				},
+				Clusters: []*Cluster{{
+					Name: "foo",
+					Nodes: []*Node{{
+						Kind:      NodeKindServer,
+						Name:      "zim",
+						Resources: &NodeResources{Memory: "512k"},
+					}},
+				}},
+			},
+			expectErr: `error building cluster "foo": error compiling node "zim": node resources are not valid: memory "512k" must be at least 1m`,
+		},
 		"node/missing address network": {
 			in: &Config{
 				Networks: []*Network{
@@ -1352,10 +1432,209 @@ type testingT interface {
 	require.TestingT
 }
 
+func TestCompile_NodeResources(t *testing.T) {
+	logger := hclog.NewNullLogger()
+
+	newConfig := func() *Config {
+		return &Config{
+			Networks: []*Network{
+				{Name: "foo"},
+			},
+			Clusters: []*Cluster{{
+				Name: "foo",
+				Nodes: []*Node{
+					{
+						Kind: NodeKindServer,
+						Name: "srv1",
+						Resources: &NodeResources{
+							CPU:    "0.5",
+							Memory: "2g",
+						},
+					},
+					{
+						Kind: NodeKindClient,
+						Name: "cli1",
+					},
+				},
+			}},
+		}
+	}
+
+	assertResources := func(t *testing.T, topo *Topology) {
+		t.Helper()
+		c := topo.Clusters["foo"]
+		require.NotNil(t, c)
+
+		srv := c.NodeByID(NodeID{Partition: "default", Name: "srv1"})
+		require.NotNil(t, srv.Resources)
+		require.Equal(t, "0.5", srv.Resources.CPU)
+		require.Equal(t, "2g", srv.Resources.Memory)
+		require.Equal(t, 512, srv.Resources.CPUShares())
+		require.Equal(t, 2048, srv.Resources.MemoryMB())
+
+		cli := c.NodeByID(NodeID{Partition: "default", Name: "cli1"})
+		require.Nil(t, cli.Resources)
+		require.Zero(t, cli.Resources.CPUShares())
+		require.Zero(t, cli.Resources.MemoryMB())
+	}
+
+	topo, _, err := compile(logger, newConfig(), nil, "87c82bd03dc89d4d")
+	require.NoError(t, err)
+	assertResources(t, topo)
+
+	topo2, _, err := Recompile(logger, newConfig(), topo)
+	require.NoError(t, err)
+	assertResources(t, topo2)
+
+	bad := newConfig()
+	bad.Clusters[0].Nodes[0].Resources.Memory = "-1g"
+	_, _, err = Recompile(logger, bad, topo2)
+	testutil.RequireErrorContains(t, err, `memory "-1g" must not be negative`)
+}
+
+func TestCompile_PortBase(t *testing.T) {
+	logger := hclog.NewNullLogger()
+
+	newConfig := func(portBase int) *Config {
+		return &Config{
+			Networks: []*Network{
+				{Name: "foo"},
+			},
+			Clusters: []*Cluster{{
+				Name:     "foo",
+				PortBase: portBase,
+				Nodes: []*Node{
+					{
+						Kind: NodeKindServer,
+						Name: "srv1",
+					},
+					{
+						Kind: NodeKindClient,
+						Name: "cli1",
+						Workloads: []*Workload{
+							{ID: NewID("a", "", ""), Image: "busybox", Port: 8080},
+							{ID: NewID("b", "", ""), Image: "busybox", Port: 8081, EnvoyAdminPort: 30001},
+							{ID: NewID("c", "", ""), Image: "busybox", Port: 30002},
+						},
+					},
+					{
+						Kind: NodeKindDataplane,
+						Name: "dp1",
+						Workloads: []*Workload{
+							{ID: NewID("d", "", ""), Image: "busybox", Port: 8080},
+						},
+					},
+				},
+			}},
+		}
+	}
+
+	type ports struct {
+		admin, public int
+	}
+	collect := func(topo *Topology) map[string]ports {
+		out := make(map[string]ports)
+		for _, n := range topo.Clusters["foo"].Nodes {
+			for _, wrk := range n.Workloads {
+				out[wrk.ID.Name] = ports{admin: wrk.EnvoyAdminPort, public: wrk.EnvoyPublicListenerPort}
+			}
+		}
+		return out
+	}
+
+	topo, _, err := compile(logger, newConfig(30000), nil, "87c82bd03dc89d4d")
+	require.NoError(t, err)
+	expect := map[string]ports{
+		"a": {admin: 30000},
+		"b": {admin: 30001},
+		"c": {admin: 30003},
+		"d": {admin: 30000, public: 30001},
+	}
+	require.Equal(t, expect, collect(topo))
+
+	// Compiling the same definition again gives the same ports.
+	topo2, _, err := compile(logger, newConfig(30000), nil, "87c82bd03dc89d4d")
+	require.NoError(t, err)
+	require.Equal(t, expect, collect(topo2))
+
+	_, _, err = compile(logger, newConfig(-1), nil, "87c82bd03dc89d4d")
+	testutil.RequireErrorContains(t, err, `cluster "foo" has invalid port base: -1`)
+
+	_, _, err = compile(logger, newConfig(65535), nil, "87c82bd03dc89d4d")
+	testutil.RequireErrorContains(t, err, `cluster "foo" node "cli1" ran out of ports to assign from port base 65535`)
+}
+
+func TestRecompile_Diff(t *testing.T) {
+	logger := hclog.NewNullLogger()
+
+	newConfig := func(serverImage, workloadImage string) *Config {
+		return &Config{
+			Networks: []*Network{
+				{Name: "foo"},
+			},
+			Clusters: []*Cluster{{
+				Name: "foo",
+				Nodes: []*Node{
+					{
+						Kind:   NodeKindServer,
+						Name:   "srv1",
+						Images: Images{ConsulCE: serverImage},
+					},
+					{
+						Kind: NodeKindClient,
+						Name: "cli1",
+						Workloads: []*Workload{
+							{
+								ID:             NewID("zim", "default", "default"),
+								Image:          workloadImage,
+								Port:           8888,
+								EnvoyAdminPort: 19000,
+							},
+						},
+					},
+				},
+			}},
+		}
+	}
+
+	topo, err := Compile(logger, newConfig("consul:1.0", "busybox:1"))
+	require.NoError(t, err)
+
+	// Nothing changed.
+	topo2, diff, err := Recompile(logger, newConfig("consul:1.0", "busybox:1"), topo)
+	require.NoError(t, err)
+	require.True(t, diff.IsEmpty())
+
+	// Only the server image changed.
+	topo3, diff, err := Recompile(logger, newConfig("consul:2.0", "busybox:1"), topo2)
+	require.NoError(t, err)
+	require.Empty(t, diff.Workloads)
+	require.Len(t, diff.Nodes, 1)
+	nd := diff.Nodes[0]
+	require.Equal(t, "foo", nd.Cluster)
+	require.Equal(t, NewNodeID("srv1", "default"), nd.ID)
+	require.Equal(t, NodeKindServer, nd.Kind)
+	require.Equal(t, "consul:1.0", nd.PrevImages.Consul)
+	require.Equal(t, "consul:2.0", nd.Images.Consul)
+
+	// Only the workload image changed.
+	_, diff, err = Recompile(logger, newConfig("consul:2.0", "busybox:2"), topo3)
+	require.NoError(t, err)
+	require.Empty(t, diff.Nodes)
+	require.Equal(t, []WorkloadDiff{{
+		Cluster:   "foo",
+		Node:      NewNodeID("cli1", "default"),
+		ID:        NewID("zim", "default", "default"),
+		PrevImage: "busybox:1",
+		Image:     "busybox:2",
+	}}, diff.Workloads)
+}
+
 var ignoreUnexportedTypes = []any{
 	Cluster{},
 	Images{},
 	Node{},
+	NodeResources{},
 	Workload{},
 }
 
@@ -284,6 +284,14 @@ type Cluster struct {
 	// Segments is a map of network segment name and the ports
 	Segments map[string]int
 
+	// PortBase, if set, is where envoy ports that are left unset on mesh
+	// workloads are assigned from, instead of requiring an admin port and
+	// giving the first dataplane workload public listener port 20000. Ports
+	// are assigned per node in workload order, admin port first, skipping
+	// ports set explicitly on the node, so the result only depends on the
+	// topology definition.
+	PortBase int `json:",omitempty"`
+
 	// DisableGossipEncryption disables gossip encryption on the cluster
 	// Default is false to enable gossip encryption
 	DisableGossipEncryption bool `json:",omitempty"`
@@ -488,6 +496,41 @@ type NetworkSegment struct {
 	Port int
 }
 
+// NodeResources describes optional resource limits applied to the container
+// backing a Node.
+type NodeResources struct {
+	// CPU is a fractional number of CPUs (e.g. "0.5" or "2"). The docker
+	// provider cannot set a hard quota, so this is translated into a relative
+	// cpu_shares weight of 1024 shares per CPU.
+	CPU string `json:",omitempty"`
+
+	// Memory is a hard memory limit using docker units (e.g. "512m" or "2g").
+	// A bare number is interpreted as bytes.
+	Memory string `json:",omitempty"`
+
+	// computed at topology compile
+	cpuShares int
+	memoryMB  int
+}
+
+// CPUShares returns the relative cpu_shares weight computed from CPU at
+// topology compile, or zero if unset.
+func (r *NodeResources) CPUShares() int {
+	if r == nil {
+		return 0
+	}
+	return r.cpuShares
+}
+
+// MemoryMB returns the memory limit in megabytes computed from Memory at
+// topology compile, or zero if unset.
+func (r *NodeResources) MemoryMB() int {
+	if r == nil {
+		return 0
+	}
+	return r.memoryMB
+}
+
 // TODO: rename pod
 type Node struct {
 	Kind      NodeKind
@@ -504,6 +547,9 @@ type Node struct {
 	Addresses []*Address
 	Workloads []*Workload
 
+	// Resources optionally limits the cpu and memory available to this node.
+	Resources *NodeResources `json:",omitempty"`
+
 	// denormalized at topology compile
 	Cluster    string
 	Datacenter string
@@ -881,3 +927,58 @@ func (c PeerCluster) String() string {
 func (p *Peering) String() string {
 	return "(" + p.Dialing.String() + ")->(" + p.Accepting.String() + ")"
 }
+
+// TopologyDiff summarizes the changes that Recompile allowed relative to the
+// previous topology. Workload commands and environments cannot change on
+// recompile, so only image changes are reported.
+type TopologyDiff struct {
+	// Nodes lists the nodes whose images changed, sorted by cluster and node.
+	Nodes []NodeDiff `json:",omitempty"`
+
+	// Workloads lists the workloads whose image changed, sorted by cluster,
+	// node and workload.
+	Workloads []WorkloadDiff `json:",omitempty"`
+}
+
+// IsEmpty returns true if nothing changed.
+func (d *TopologyDiff) IsEmpty() bool {
+	return d == nil || (len(d.Nodes) == 0 && len(d.Workloads) == 0)
+}
+
+func (d *TopologyDiff) sort() {
+	sort.Slice(d.Nodes, func(i, j int) bool {
+		a, b := d.Nodes[i], d.Nodes[j]
+		if a.Cluster != b.Cluster {
+			return a.Cluster < b.Cluster
+		}
+		return a.ID.String() < b.ID.String()
+	})
+	sort.Slice(d.Workloads, func(i, j int) bool {
+		a, b := d.Workloads[i], d.Workloads[j]
+		if a.Cluster != b.Cluster {
+			return a.Cluster < b.Cluster
+		}
+		if a.Node != b.Node {
+			return a.Node.String() < b.Node.String()
+		}
+		return a.ID.String() < b.ID.String()
+	})
+}
+
+// NodeDiff records the image change of a node.
+type NodeDiff struct {
+	Cluster    string
+	ID         NodeID
+	Kind       NodeKind
+	PrevImages Images
+	Images     Images
+}
+
+// WorkloadDiff records the image change of a workload.
+type WorkloadDiff struct {
+	Cluster   string
+	Node      NodeID
+	ID        ID
+	PrevImage string
+	Image     string
+}